@@ -10,7 +10,7 @@ const (
   -format value
     	deprecated; use -output
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -socketPath string
     	Path to the SPIRE Agent API Unix domain socket (default "/tmp/spire-agent/public/api.sock")
   -spiffeID string
@@ -20,7 +20,7 @@ const (
 `
 	fetchX509Usage = `Usage of fetch x509:
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -silent
     	Suppress stdout
   -socketPath string
@@ -34,7 +34,7 @@ const (
   -audience string
     	expected audience value
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -socketPath string
     	Path to the SPIRE Agent API Unix domain socket (default "/tmp/spire-agent/public/api.sock")
   -svid string
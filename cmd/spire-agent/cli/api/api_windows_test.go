@@ -12,7 +12,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Agent API named pipe (default "\\spire-agent\\public\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -spiffeID string
     	SPIFFE ID subject (optional)
   -timeout value
@@ -22,7 +22,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Agent API named pipe (default "\\spire-agent\\public\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -silent
     	Suppress stdout
   -timeout value
@@ -36,7 +36,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Agent API named pipe (default "\\spire-agent\\public\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -svid string
     	JWT SVID
   -timeout value
@@ -9,24 +9,46 @@ var (
     	Indicates that the command will not perform any action, but will print the agents that would be purged.
   -expiredFor duration
     	Amount of time that has passed since the agent's SVID has expired. It is used to determine which agents to purge. (default 720h0m0s)
+  -notSeenFor duration
+    	Amount of time that has passed since the agent was last seen by the server. When set, agents are purged based on this instead of -expiredFor.
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
 `
 	listUsage = `Usage of agent list:
+  -agentVersion string
+    	Filter agents by the version they last reported
+  -attestationType string
+    	Filter agents by attestation type
+  -banned string
+    	Filter agents by banned state (true or false)
+  -canReattest string
+    	Filter agents by whether they can re-attest (true or false)
+  -columns value
+    	Comma-separated list of the columns to print with the table output format; default: all columns.
+  -expired
+    	Filter agents with an expired SVID
+  -expiresWithin value
+    	Filter agents with an SVID expiring within the given duration (e.g. 24h)
   -matchSelectorsOn string
     	The match mode used when filtering by selectors. Options: exact, any, superset and subset (default "superset")
+  -notSeenFor value
+    	Filter agents not seen by the server for the given duration (e.g. 72h)
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table); default: pretty.
   -selector value
     	A colon-delimited type:value selector. Can be used more than once
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
+  -sortBy string
+    	Sort agents by the given field. Options: spiffe_id, attestation_type, expires_at and last_seen_at; default: attestation order
+  -sortDescending
+    	Sort agents in descending order
 `
 	banUsage = `Usage of agent ban:
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
   -spiffeID string
@@ -34,7 +56,7 @@ var (
 `
 	evictUsage = `Usage of agent evict:
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
   -spiffeID string
@@ -42,13 +64,13 @@ var (
 `
 	countUsage = `Usage of agent count:
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
 `
 	showUsage = `Usage of agent show:
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -socketPath string
     	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
   -spiffeID string
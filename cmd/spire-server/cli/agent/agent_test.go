@@ -14,6 +14,8 @@ import (
 	"github.com/spiffe/spire/cmd/spire-server/cli/agent"
 	"github.com/spiffe/spire/cmd/spire-server/cli/common"
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	agentextv1 "github.com/spiffe/spire/proto/spire/api/server/agent/v1"
+	"github.com/spiffe/spire/test/golden"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -259,7 +261,7 @@ func TestList(t *testing.T) {
 		expectedStdoutPretty string
 		expectedStdoutJSON   string
 		expectedStderr       string
-		expectReq            *agentv1.ListAgentsRequest
+		expectReq            *agentextv1.SearchAgentsRequest
 		existentAgents       []*types.Agent
 		expectedFormat       string
 		serverErr            error
@@ -270,8 +272,8 @@ func TestList(t *testing.T) {
 			existentAgents:       testAgents,
 			expectedStdoutPretty: "Found 1 attested agent:\n\nSPIFFE ID         : spiffe://example.org/spire/agent/agent1",
 			expectedStdoutJSON:   `{"agents":[{"id":{"trust_domain":"example.org","path":"/spire/agent/agent1"},"attestation_type":"","x509svid_serial_number":"","x509svid_expires_at":"0","selectors":[],"banned":false,"can_reattest":true}],"next_page_token":""}`,
-			expectReq: &agentv1.ListAgentsRequest{
-				Filter:   &agentv1.ListAgentsRequest_Filter{},
+			expectReq: &agentextv1.SearchAgentsRequest{
+				Filter:   &agentextv1.SearchAgentsRequest_Filter{},
 				PageSize: 1000,
 			},
 		},
@@ -279,8 +281,8 @@ func TestList(t *testing.T) {
 			name:               "no agents",
 			expectedReturnCode: 0,
 			expectedStdoutJSON: `{"agents":[],"next_page_token":""}`,
-			expectReq: &agentv1.ListAgentsRequest{
-				Filter:   &agentv1.ListAgentsRequest_Filter{},
+			expectReq: &agentextv1.SearchAgentsRequest{
+				Filter:   &agentextv1.SearchAgentsRequest_Filter{},
 				PageSize: 1000,
 			},
 		},
@@ -289,16 +291,16 @@ func TestList(t *testing.T) {
 			expectedReturnCode: 1,
 			serverErr:          status.Error(codes.Internal, "internal server error"),
 			expectedStderr:     "Error: rpc error: code = Internal desc = internal server error\n",
-			expectReq: &agentv1.ListAgentsRequest{
-				Filter:   &agentv1.ListAgentsRequest_Filter{},
+			expectReq: &agentextv1.SearchAgentsRequest{
+				Filter:   &agentextv1.SearchAgentsRequest_Filter{},
 				PageSize: 1000,
 			},
 		},
 		{
 			name: "by selector: default matcher",
 			args: []string{"-selector", "foo:bar", "-selector", "bar:baz"},
-			expectReq: &agentv1.ListAgentsRequest{
-				Filter: &agentv1.ListAgentsRequest_Filter{
+			expectReq: &agentextv1.SearchAgentsRequest{
+				Filter: &agentextv1.SearchAgentsRequest_Filter{
 					BySelectorMatch: &types.SelectorMatch{
 						Selectors: []*types.Selector{
 							{Type: "foo", Value: "bar"},
@@ -316,8 +318,8 @@ func TestList(t *testing.T) {
 		{
 			name: "by selector: any matcher",
 			args: []string{"-selector", "foo:bar", "-selector", "bar:baz", "-matchSelectorsOn", "any"},
-			expectReq: &agentv1.ListAgentsRequest{
-				Filter: &agentv1.ListAgentsRequest_Filter{
+			expectReq: &agentextv1.SearchAgentsRequest{
+				Filter: &agentextv1.SearchAgentsRequest_Filter{
 					BySelectorMatch: &types.SelectorMatch{
 						Selectors: []*types.Selector{
 							{Type: "foo", Value: "bar"},
@@ -335,8 +337,8 @@ func TestList(t *testing.T) {
 		{
 			name: "by selector: exact matcher",
 			args: []string{"-selector", "foo:bar", "-selector", "bar:baz", "-matchSelectorsOn", "exact"},
-			expectReq: &agentv1.ListAgentsRequest{
-				Filter: &agentv1.ListAgentsRequest_Filter{
+			expectReq: &agentextv1.SearchAgentsRequest{
+				Filter: &agentextv1.SearchAgentsRequest_Filter{
 					BySelectorMatch: &types.SelectorMatch{
 						Selectors: []*types.Selector{
 							{Type: "foo", Value: "bar"},
@@ -354,8 +356,8 @@ func TestList(t *testing.T) {
 		{
 			name: "by selector: superset matcher",
 			args: []string{"-selector", "foo:bar", "-selector", "bar:baz", "-matchSelectorsOn", "superset"},
-			expectReq: &agentv1.ListAgentsRequest{
-				Filter: &agentv1.ListAgentsRequest_Filter{
+			expectReq: &agentextv1.SearchAgentsRequest{
+				Filter: &agentextv1.SearchAgentsRequest_Filter{
 					BySelectorMatch: &types.SelectorMatch{
 						Selectors: []*types.Selector{
 							{Type: "foo", Value: "bar"},
@@ -373,8 +375,8 @@ func TestList(t *testing.T) {
 		{
 			name: "by selector: subset matcher",
 			args: []string{"-selector", "foo:bar", "-selector", "bar:baz", "-matchSelectorsOn", "subset"},
-			expectReq: &agentv1.ListAgentsRequest{
-				Filter: &agentv1.ListAgentsRequest_Filter{
+			expectReq: &agentextv1.SearchAgentsRequest{
+				Filter: &agentextv1.SearchAgentsRequest_Filter{
 					BySelectorMatch: &types.SelectorMatch{
 						Selectors: []*types.Selector{
 							{Type: "foo", Value: "bar"},
@@ -389,6 +391,55 @@ func TestList(t *testing.T) {
 			expectedStdoutPretty: "Found 1 attested agent:\n\nSPIFFE ID         : spiffe://example.org/spire/agent/agent1",
 			expectedStdoutJSON:   `{"agents":[{"id":{"trust_domain":"example.org","path":"/spire/agent/agent1"},"attestation_type":"","x509svid_serial_number":"","x509svid_expires_at":"0","selectors":[],"banned":false,"can_reattest":true}],"next_page_token":""}`,
 		},
+		{
+			name: "by attestation type, agent version, banned and can reattest, sorted",
+			args: []string{
+				"-attestationType", "join_token",
+				"-agentVersion", "1.8.0",
+				"-banned", "false",
+				"-canReattest", "true",
+				"-sortBy", "last_seen_at",
+				"-sortDescending",
+			},
+			expectReq: &agentextv1.SearchAgentsRequest{
+				Filter: &agentextv1.SearchAgentsRequest_Filter{
+					ByAttestationType: "join_token",
+					ByAgentVersion:    "1.8.0",
+					ByBanned:          wrapperspb.Bool(false),
+					ByCanReattest:     wrapperspb.Bool(true),
+				},
+				SortBy:         agentextv1.SearchAgentsRequest_SORT_BY_LAST_SEEN_AT,
+				SortDescending: true,
+				PageSize:       1000,
+			},
+			existentAgents:       testAgents,
+			expectedStdoutPretty: "Found 1 attested agent:\n\nSPIFFE ID         : spiffe://example.org/spire/agent/agent1",
+			expectedStdoutJSON:   `{"agents":[{"id":{"trust_domain":"example.org","path":"/spire/agent/agent1"},"attestation_type":"","x509svid_serial_number":"","x509svid_expires_at":"0","selectors":[],"banned":false,"can_reattest":true}],"next_page_token":""}`,
+		},
+		{
+			name:               "invalid banned value",
+			args:               []string{"-banned", "maybe"},
+			expectedReturnCode: 1,
+			expectedStderr:     "Error: invalid value for -banned: \"maybe\"\n",
+		},
+		{
+			name:               "invalid can reattest value",
+			args:               []string{"-canReattest", "maybe"},
+			expectedReturnCode: 1,
+			expectedStderr:     "Error: invalid value for -canReattest: \"maybe\"\n",
+		},
+		{
+			name:               "expired and expires within",
+			args:               []string{"-expired", "-expiresWithin", "1h"},
+			expectedReturnCode: 1,
+			expectedStderr:     "Error: the -expired and -expiresWithin flags are mutually exclusive\n",
+		},
+		{
+			name:               "unsupported sort field",
+			args:               []string{"-sortBy", "selectors"},
+			expectedReturnCode: 1,
+			expectedStderr:     "Error: unsupported sort field \"selectors\"\n",
+		},
 		{
 			name:               "List by selectors: Invalid matcher",
 			args:               []string{"-selector", "foo:bar", "-selector", "bar:baz", "-matchSelectorsOn", "NO-MATCHER"},
@@ -419,7 +470,7 @@ func TestList(t *testing.T) {
 				returnCode := test.client.Run(append(test.args, args...))
 
 				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expectedStdoutPretty, tt.expectedStdoutJSON)
-				spiretest.RequireProtoEqual(t, tt.expectReq, test.server.gotListAgentRequest)
+				spiretest.RequireProtoEqual(t, tt.expectReq, test.server.gotSearchAgentsRequest)
 				require.Equal(t, tt.expectedStderr, test.stderr.String())
 				require.Equal(t, tt.expectedReturnCode, returnCode)
 			})
@@ -427,6 +478,105 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestListTimeFilters(t *testing.T) {
+	for _, tt := range []struct {
+		name                    string
+		args                    []string
+		expectExpiresBeforeIn   time.Duration
+		expectLastSeenBeforeAgo time.Duration
+	}{
+		{
+			name:                  "expired",
+			args:                  []string{"-expired"},
+			expectExpiresBeforeIn: 0,
+		},
+		{
+			name:                  "expires within",
+			args:                  []string{"-expiresWithin", "24h"},
+			expectExpiresBeforeIn: 24 * time.Hour,
+		},
+		{
+			name:                    "not seen for",
+			args:                    []string{"-notSeenFor", "72h"},
+			expectLastSeenBeforeAgo: 72 * time.Hour,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupTest(t, agent.NewListCommandWithEnv)
+
+			before := time.Now()
+			returnCode := test.client.Run(append(test.args, tt.args...))
+			after := time.Now()
+
+			require.Equal(t, 0, returnCode)
+			filter := test.server.gotSearchAgentsRequest.Filter
+			if tt.expectLastSeenBeforeAgo != 0 {
+				require.Zero(t, filter.ByExpiresBefore)
+				require.GreaterOrEqual(t, filter.ByLastSeenBefore, before.Add(-tt.expectLastSeenBeforeAgo).Unix())
+				require.LessOrEqual(t, filter.ByLastSeenBefore, after.Add(-tt.expectLastSeenBeforeAgo).Unix())
+				return
+			}
+			require.Zero(t, filter.ByLastSeenBefore)
+			require.GreaterOrEqual(t, filter.ByExpiresBefore, before.Add(tt.expectExpiresBeforeIn).Unix())
+			require.LessOrEqual(t, filter.ByExpiresBefore, after.Add(tt.expectExpiresBeforeIn).Unix())
+		})
+	}
+}
+
+func TestListOutputFormats(t *testing.T) {
+	agents := append(append([]*types.Agent{}, testAgents...), testAgentsWithSelectors...)
+	agents = append(agents, testAgentsWithBanned...)
+
+	for _, tt := range []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "list_yaml",
+			args: []string{"-output", "yaml"},
+		},
+		{
+			name: "list_table",
+			args: []string{"-output", "table"},
+		},
+		{
+			name: "list_table_columns",
+			args: []string{"-output", "table", "-columns", "id,banned,selectors"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupTest(t, agent.NewListCommandWithEnv)
+			test.server.agents = agents
+
+			returnCode := test.client.Run(append(test.args, tt.args...))
+
+			require.Equal(t, 0, returnCode)
+			require.Empty(t, test.stderr.String())
+			golden.RequireEqual(t, tt.name, test.stdout.Bytes())
+		})
+	}
+}
+
+func TestListUnknownColumn(t *testing.T) {
+	test := setupTest(t, agent.NewListCommandWithEnv)
+	test.server.agents = testAgents
+
+	returnCode := test.client.Run(append(test.args, "-output", "table", "-columns", "id,version"))
+
+	require.Equal(t, 1, returnCode)
+	require.Equal(t, "Error: unknown column \"version\"; available columns: id, attestation_type, x509svid_serial_number, x509svid_expires_at, selectors, banned, can_reattest\n", test.stderr.String())
+}
+
+func TestListColumnsWithoutTable(t *testing.T) {
+	test := setupTest(t, agent.NewListCommandWithEnv)
+	test.server.agents = testAgents
+
+	returnCode := test.client.Run(append(test.args, "-output", "json", "-columns", "id"))
+
+	require.Equal(t, 1, returnCode)
+	require.Equal(t, "Error: the -columns flag can only be used with the table output format\n", test.stderr.String())
+}
+
 func TestPurgeHelp(t *testing.T) {
 	test := setupTest(t, agent.NewPurgeCommandWithEnv)
 
@@ -649,6 +799,87 @@ SPIFFE ID         : spiffe://example.org/spire/agent/agent3
 	}
 }
 
+func TestPurgeNotSeenFor(t *testing.T) {
+	now := time.Now()
+	td := spiffeid.RequireTrustDomainFromString("example.org")
+
+	inactiveAgents := []*types.Agent{
+		{Id: &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/agent1"}, CanReattest: true, X509SvidExpiresAt: now.Add(time.Hour).Unix()},
+		{Id: &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/agent2"}, CanReattest: true, X509SvidExpiresAt: now.Add(-time.Hour).Unix()},
+	}
+
+	for _, tt := range []struct {
+		name                 string
+		args                 []string
+		lastSeenAt           int64
+		serverErr            error
+		expectedReturnCode   int
+		expectedStdoutPretty string
+		expectedStdoutJSON   string
+		expectedStderr       string
+		expectDeleteReqs     []*agentv1.DeleteAgentRequest
+	}{
+		{
+			name:               "error searching agents",
+			args:               []string{"-notSeenFor", "72h"},
+			lastSeenAt:         now.Add(-96 * time.Hour).Unix(),
+			serverErr:          status.Error(codes.Internal, "some error"),
+			expectedStderr:     "Error: failed to search agents: rpc error: code = Internal desc = some error\n",
+			expectedReturnCode: 1,
+		},
+		{
+			name:       "purge agents not seen regardless of expiration",
+			args:       []string{"-notSeenFor", "72h"},
+			lastSeenAt: now.Add(-96 * time.Hour).Unix(),
+			expectDeleteReqs: []*agentv1.DeleteAgentRequest{
+				{Id: inactiveAgents[0].Id},
+				{Id: inactiveAgents[1].Id},
+			},
+			expectedStdoutPretty: `Found 2 inactive agents
+
+Agents purged:
+SPIFFE ID         : spiffe://example.org/spire/agent/agent1
+SPIFFE ID         : spiffe://example.org/spire/agent/agent2
+`,
+			expectedStdoutJSON: fmt.Sprintf(
+				`[{"expired_agents":[{"agent_id":"%s","deleted":true},{"agent_id":"%s","deleted":true}]}]`,
+				spiffeid.RequireFromPath(td, inactiveAgents[0].Id.Path).String(),
+				spiffeid.RequireFromPath(td, inactiveAgents[1].Id.Path).String(),
+			),
+		},
+		{
+			name:                 "agents never seen are not purged",
+			args:                 []string{"-notSeenFor", "72h"},
+			expectedStdoutPretty: `No agents to purge.`,
+			expectedStdoutJSON:   `[{"expired_agents":[]}]`,
+		},
+	} {
+		for _, format := range availableFormats {
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, agent.NewPurgeCommandWithEnv)
+				test.server.agents = inactiveAgents
+				test.server.agentInfo = &agentextv1.AgentInfo{LastSeenAt: tt.lastSeenAt}
+				test.server.err = tt.serverErr
+				args := tt.args
+				args = append(args, "-output", format)
+
+				returnCode := test.client.Run(append(test.args, args...))
+
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expectedStdoutPretty, tt.expectedStdoutJSON)
+				spiretest.RequireProtoListEqual(t, tt.expectDeleteReqs, test.server.gotDeleteAgentRequests)
+				require.Contains(t, test.stderr.String(), tt.expectedStderr)
+				require.Equal(t, tt.expectedReturnCode, returnCode)
+
+				// Agents are searched by last seen time instead of listing them all
+				require.Nil(t, test.server.gotListAgentRequest)
+				filter := test.server.gotSearchAgentsRequest.GetFilter()
+				require.True(t, filter.GetByCanReattest().GetValue())
+				require.InDelta(t, now.Add(-72*time.Hour).Unix(), filter.GetByLastSeenBefore(), 60)
+			})
+		}
+	}
+}
+
 func TestShowHelp(t *testing.T) {
 	test := setupTest(t, agent.NewShowCommandWithEnv)
 
@@ -665,6 +896,7 @@ func TestShow(t *testing.T) {
 		expectedStdoutJSON   string
 		expectedStderr       string
 		existentAgents       []*types.Agent
+		agentInfo            *agentextv1.AgentInfo
 		serverErr            error
 	}{
 		{
@@ -710,12 +942,26 @@ func TestShow(t *testing.T) {
 			expectedStdoutPretty: "Banned            : true",
 			expectedStdoutJSON:   `{"id":{"trust_domain":"example.org","path":"/spire/agent/banned"},"attestation_type":"","x509svid_serial_number":"","x509svid_expires_at":"0","selectors":[],"banned":true,"can_reattest":false}`,
 		},
+		{
+			name:               "show activity",
+			args:               []string{"-spiffeID", "spiffe://example.org/spire/agent/agent1"},
+			existentAgents:     testAgents,
+			expectedReturnCode: 0,
+			agentInfo: &agentextv1.AgentInfo{
+				LastSeenAt:    1689000000,
+				AgentVersion:  "1.8.0",
+				RemoteAddress: "192.0.2.1",
+			},
+			expectedStdoutPretty: fmt.Sprintf("Last seen         : %s\nAgent version     : 1.8.0\nRemote address    : 192.0.2.1\n", time.Unix(1689000000, 0)),
+			expectedStdoutJSON:   `{"id":{"trust_domain":"example.org","path":"/spire/agent/agent1"},"attestation_type":"","x509svid_serial_number":"","x509svid_expires_at":"0","selectors":[],"banned":false,"can_reattest":true}`,
+		},
 	} {
 		for _, format := range availableFormats {
 			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
 				test := setupTest(t, agent.NewShowCommandWithEnv)
 				test.server.err = tt.serverErr
 				test.server.agents = tt.existentAgents
+				test.server.agentInfo = tt.agentInfo
 				args := tt.args
 				args = append(args, "-output", format)
 
@@ -734,6 +980,7 @@ func setupTest(t *testing.T, newClient func(*commoncli.Env) cli.Command) *agentT
 
 	addr := spiretest.StartGRPCServer(t, func(s *grpc.Server) {
 		agentv1.RegisterAgentServer(s, server)
+		agentextv1.RegisterAgentExtensionsServer(s, server)
 	})
 
 	stdin := new(bytes.Buffer)
@@ -764,9 +1011,12 @@ func setupTest(t *testing.T, newClient func(*commoncli.Env) cli.Command) *agentT
 
 type fakeAgentServer struct {
 	agentv1.UnimplementedAgentServer
+	agentextv1.UnimplementedAgentExtensionsServer
 
 	agents                 []*types.Agent
+	agentInfo              *agentextv1.AgentInfo
 	gotListAgentRequest    *agentv1.ListAgentsRequest
+	gotSearchAgentsRequest *agentextv1.SearchAgentsRequest
 	gotDeleteAgentRequests []*agentv1.DeleteAgentRequest
 	deleteErr              error
 	err                    error
@@ -794,6 +1044,21 @@ func (s *fakeAgentServer) ListAgents(_ context.Context, req *agentv1.ListAgentsR
 	}, s.err
 }
 
+func (s *fakeAgentServer) SearchAgents(_ context.Context, req *agentextv1.SearchAgentsRequest) (*agentextv1.SearchAgentsResponse, error) {
+	s.gotSearchAgentsRequest = req
+	resp := &agentextv1.SearchAgentsResponse{}
+	for _, agent := range s.agents {
+		info := &agentextv1.AgentInfo{Agent: agent}
+		if s.agentInfo != nil {
+			info.LastSeenAt = s.agentInfo.LastSeenAt
+			info.AgentVersion = s.agentInfo.AgentVersion
+			info.RemoteAddress = s.agentInfo.RemoteAddress
+		}
+		resp.Agents = append(resp.Agents, info)
+	}
+	return resp, s.err
+}
+
 func (s *fakeAgentServer) GetAgent(context.Context, *agentv1.GetAgentRequest) (*types.Agent, error) {
 	if len(s.agents) > 0 {
 		return s.agents[0], s.err
@@ -802,6 +1067,23 @@ func (s *fakeAgentServer) GetAgent(context.Context, *agentv1.GetAgentRequest) (*
 	return nil, s.err
 }
 
+func (s *fakeAgentServer) GetAgentInfo(context.Context, *agentextv1.GetAgentInfoRequest) (*agentextv1.AgentInfo, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if len(s.agents) == 0 {
+		return nil, status.Error(codes.NotFound, "agent not found")
+	}
+
+	info := &agentextv1.AgentInfo{Agent: s.agents[0]}
+	if s.agentInfo != nil {
+		info.LastSeenAt = s.agentInfo.LastSeenAt
+		info.AgentVersion = s.agentInfo.AgentVersion
+		info.RemoteAddress = s.agentInfo.RemoteAddress
+	}
+	return info, nil
+}
+
 func requireOutputBasedOnFormat(t *testing.T, format, stdoutString string, expectedStdoutPretty, expectedStdoutJSON string) {
 	switch format {
 	case "pretty":
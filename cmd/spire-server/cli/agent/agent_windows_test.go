@@ -11,24 +11,46 @@ var (
     	Amount of time that has passed since the agent's SVID has expired. It is used to determine which agents to purge. (default 720h0m0s)
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
+  -notSeenFor duration
+    	Amount of time that has passed since the agent was last seen by the server. When set, agents are purged based on this instead of -expiredFor.
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
 `
 	listUsage = `Usage of agent list:
+  -agentVersion string
+    	Filter agents by the version they last reported
+  -attestationType string
+    	Filter agents by attestation type
+  -banned string
+    	Filter agents by banned state (true or false)
+  -canReattest string
+    	Filter agents by whether they can re-attest (true or false)
+  -columns value
+    	Comma-separated list of the columns to print with the table output format; default: all columns.
+  -expired
+    	Filter agents with an expired SVID
+  -expiresWithin value
+    	Filter agents with an SVID expiring within the given duration (e.g. 24h)
   -matchSelectorsOn string
     	The match mode used when filtering by selectors. Options: exact, any, superset and subset (default "superset")
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
+  -notSeenFor value
+    	Filter agents not seen by the server for the given duration (e.g. 72h)
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table); default: pretty.
   -selector value
     	A colon-delimited type:value selector. Can be used more than once
+  -sortBy string
+    	Sort agents by the given field. Options: spiffe_id, attestation_type, expires_at and last_seen_at; default: attestation order
+  -sortDescending
+    	Sort agents in descending order
 `
 	banUsage = `Usage of agent ban:
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -spiffeID string
     	The SPIFFE ID of the agent to ban (agent identity)
 `
@@ -36,7 +58,7 @@ var (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -spiffeID string
     	The SPIFFE ID of the agent to evict (agent identity)
 `
@@ -44,13 +66,13 @@ var (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
 `
 	showUsage = `Usage of agent show:
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -spiffeID string
     	The SPIFFE ID of the agent to show (agent identity)
 `
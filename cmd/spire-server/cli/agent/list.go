@@ -4,6 +4,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/mitchellh/cli"
@@ -13,7 +14,9 @@ import (
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/cliprinter"
 	"github.com/spiffe/spire/pkg/common/idutil"
+	agentextv1 "github.com/spiffe/spire/proto/spire/api/server/agent/v1"
 	"golang.org/x/net/context"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 type listCommand struct {
@@ -23,7 +26,25 @@ type listCommand struct {
 	selectors commoncli.StringsFlag
 	// Match used when filtering agents by selectors
 	matchSelectorsOn string
-	printer          cliprinter.Printer
+	// Attestation type used when filtering agents
+	attestationType string
+	// Banned state used when filtering agents ("true" or "false")
+	banned string
+	// Can re-attest state used when filtering agents ("true" or "false")
+	canReattest string
+	// Whether to filter agents with an expired SVID
+	expired bool
+	// Filters agents with an SVID expiring within the given duration
+	expiresWithin commoncli.DurationFlag
+	// Agent version used when filtering agents
+	agentVersion string
+	// Filters agents that have not been seen for the given duration
+	notSeenFor commoncli.DurationFlag
+	// Field agents are sorted by
+	sortBy string
+	// Whether agents are sorted in descending order
+	sortDescending bool
+	printer        cliprinter.Printer
 }
 
 // NewListCommand creates a new "list" subcommand for "agent" command.
@@ -47,18 +68,58 @@ func (*listCommand) Synopsis() string {
 
 // Run lists attested agents
 func (c *listCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
-	filter := &agentv1.ListAgentsRequest_Filter{}
+	filter, err := c.parseFilter()
+	if err != nil {
+		return err
+	}
+
+	sortBy, err := parseToSortBy(c.sortBy)
+	if err != nil {
+		return err
+	}
+
+	agentClient := serverClient.NewAgentExtensionsClient()
+
+	pageToken := ""
+	response := new(agentv1.ListAgentsResponse)
+	for {
+		searchResponse, err := agentClient.SearchAgents(ctx, &agentextv1.SearchAgentsRequest{
+			PageSize:       1000, // comfortably under the (4 MB/theoretical maximum size of 1 agent in MB)
+			PageToken:      pageToken,
+			Filter:         filter,
+			SortBy:         sortBy,
+			SortDescending: c.sortDescending,
+		})
+		if err != nil {
+			return err
+		}
+		for _, info := range searchResponse.Agents {
+			response.Agents = append(response.Agents, info.Agent)
+		}
+		if pageToken = searchResponse.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	return c.printer.PrintProto(response)
+}
+
+func (c *listCommand) parseFilter() (*agentextv1.SearchAgentsRequest_Filter, error) {
+	filter := &agentextv1.SearchAgentsRequest_Filter{
+		ByAttestationType: c.attestationType,
+		ByAgentVersion:    c.agentVersion,
+	}
 	if len(c.selectors) > 0 {
 		matchBehavior, err := parseToSelectorMatch(c.matchSelectorsOn)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		selectors := make([]*types.Selector, len(c.selectors))
 		for i, sel := range c.selectors {
 			selector, err := util.ParseSelector(sel)
 			if err != nil {
-				return fmt.Errorf("error parsing selector %q: %w", sel, err)
+				return nil, fmt.Errorf("error parsing selector %q: %w", sel, err)
 			}
 			selectors[i] = selector
 		}
@@ -68,32 +129,52 @@ func (c *listCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient ut
 		}
 	}
 
-	agentClient := serverClient.NewAgentClient()
-
-	pageToken := ""
-	response := new(agentv1.ListAgentsResponse)
-	for {
-		listResponse, err := agentClient.ListAgents(ctx, &agentv1.ListAgentsRequest{
-			PageSize:  1000, // comfortably under the (4 MB/theoretical maximum size of 1 agent in MB)
-			PageToken: pageToken,
-			Filter:    filter,
-		})
+	if c.banned != "" {
+		banned, err := strconv.ParseBool(c.banned)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("invalid value for -banned: %q", c.banned)
 		}
-		response.Agents = append(response.Agents, listResponse.Agents...)
-		if pageToken = listResponse.NextPageToken; pageToken == "" {
-			break
+		filter.ByBanned = wrapperspb.Bool(banned)
+	}
+
+	if c.canReattest != "" {
+		canReattest, err := strconv.ParseBool(c.canReattest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for -canReattest: %q", c.canReattest)
 		}
+		filter.ByCanReattest = wrapperspb.Bool(canReattest)
 	}
 
-	return c.printer.PrintProto(response)
+	now := time.Now()
+	switch {
+	case c.expired && c.expiresWithin != 0:
+		return nil, errors.New("the -expired and -expiresWithin flags are mutually exclusive")
+	case c.expired:
+		filter.ByExpiresBefore = now.Unix()
+	case c.expiresWithin != 0:
+		filter.ByExpiresBefore = now.Add(time.Duration(c.expiresWithin)).Unix()
+	}
+
+	if c.notSeenFor != 0 {
+		filter.ByLastSeenBefore = now.Add(-time.Duration(c.notSeenFor)).Unix()
+	}
+
+	return filter, nil
 }
 
 func (c *listCommand) AppendFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.matchSelectorsOn, "matchSelectorsOn", "superset", "The match mode used when filtering by selectors. Options: exact, any, superset and subset")
 	fs.Var(&c.selectors, "selector", "A colon-delimited type:value selector. Can be used more than once")
-	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintAgents)
+	fs.StringVar(&c.attestationType, "attestationType", "", "Filter agents by attestation type")
+	fs.StringVar(&c.banned, "banned", "", "Filter agents by banned state (true or false)")
+	fs.StringVar(&c.canReattest, "canReattest", "", "Filter agents by whether they can re-attest (true or false)")
+	fs.BoolVar(&c.expired, "expired", false, "Filter agents with an expired SVID")
+	fs.Var(&c.expiresWithin, "expiresWithin", "Filter agents with an SVID expiring within the given duration (e.g. 24h)")
+	fs.StringVar(&c.agentVersion, "agentVersion", "", "Filter agents by the version they last reported")
+	fs.Var(&c.notSeenFor, "notSeenFor", "Filter agents not seen by the server for the given duration (e.g. 72h)")
+	fs.StringVar(&c.sortBy, "sortBy", "", "Sort agents by the given field. Options: spiffe_id, attestation_type, expires_at and last_seen_at; default: attestation order")
+	fs.BoolVar(&c.sortDescending, "sortDescending", false, "Sort agents in descending order")
+	cliprinter.AppendTableFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintAgents)
 }
 
 func prettyPrintAgents(env *commoncli.Env, results ...interface{}) error {
@@ -151,6 +232,23 @@ func printAgents(env *commoncli.Env, agents ...*types.Agent) error {
 	return nil
 }
 
+func parseToSortBy(sortBy string) (agentextv1.SearchAgentsRequest_SortBy, error) {
+	switch sortBy {
+	case "":
+		return agentextv1.SearchAgentsRequest_SORT_BY_DEFAULT, nil
+	case "spiffe_id":
+		return agentextv1.SearchAgentsRequest_SORT_BY_SPIFFE_ID, nil
+	case "attestation_type":
+		return agentextv1.SearchAgentsRequest_SORT_BY_ATTESTATION_TYPE, nil
+	case "expires_at":
+		return agentextv1.SearchAgentsRequest_SORT_BY_EXPIRES_AT, nil
+	case "last_seen_at":
+		return agentextv1.SearchAgentsRequest_SORT_BY_LAST_SEEN_AT, nil
+	default:
+		return agentextv1.SearchAgentsRequest_SORT_BY_DEFAULT, fmt.Errorf("unsupported sort field %q", sortBy)
+	}
+}
+
 func parseToSelectorMatch(match string) (types.SelectorMatch_MatchBehavior, error) {
 	switch match {
 	case "exact":
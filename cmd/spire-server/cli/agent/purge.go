@@ -13,6 +13,7 @@ import (
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/cliprinter"
 	"github.com/spiffe/spire/pkg/common/idutil"
+	agentextv1 "github.com/spiffe/spire/proto/spire/api/server/agent/v1"
 	"golang.org/x/net/context"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 )
@@ -20,6 +21,7 @@ import (
 type purgeCommand struct {
 	env        *commoncli.Env
 	expiredFor time.Duration
+	notSeenFor time.Duration
 	dryRun     bool
 	printer    cliprinter.Printer
 }
@@ -41,16 +43,17 @@ func (*purgeCommand) Synopsis() string {
 }
 
 func (c *purgeCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) (err error) {
-	agentClient := serverClient.NewAgentClient()
-	resp, err := agentClient.ListAgents(ctx, &agentv1.ListAgentsRequest{
-		Filter:     &agentv1.ListAgentsRequest_Filter{ByCanReattest: wrapperspb.Bool(true)},
-		OutputMask: &types.AgentMask{X509SvidExpiresAt: true},
-	})
+	var agents []*types.Agent
+	if c.notSeenFor > 0 {
+		agents, err = c.listInactiveAgents(ctx, serverClient)
+	} else {
+		agents, err = c.listExpiredAgents(ctx, serverClient)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to list agents: %w", err)
+		return err
 	}
 
-	agents := resp.GetAgents()
+	agentClient := serverClient.NewAgentClient()
 	expiredAgents := &expiredAgents{Agents: []*expiredAgent{}}
 
 	for _, agent := range agents {
@@ -59,27 +62,81 @@ func (c *purgeCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient u
 			return err
 		}
 
-		expirationTime := time.Unix(agent.X509SvidExpiresAt, 0)
+		result := &expiredAgent{AgentID: id}
 
-		if time.Since(expirationTime) > c.expiredFor {
-			result := &expiredAgent{AgentID: id}
-
-			if !c.dryRun {
-				if _, err := agentClient.DeleteAgent(ctx, &agentv1.DeleteAgentRequest{Id: agent.Id}); err != nil {
-					result.Error = err.Error()
-				} else {
-					result.Deleted = true
-				}
+		if !c.dryRun {
+			if _, err := agentClient.DeleteAgent(ctx, &agentv1.DeleteAgentRequest{Id: agent.Id}); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Deleted = true
 			}
-			expiredAgents.Agents = append(expiredAgents.Agents, result)
 		}
+		expiredAgents.Agents = append(expiredAgents.Agents, result)
 	}
 
 	return c.printer.PrintStruct(expiredAgents)
 }
 
+// listExpiredAgents returns the agents whose SVID expired longer ago than
+// the -expiredFor duration.
+func (c *purgeCommand) listExpiredAgents(ctx context.Context, serverClient util.ServerClient) ([]*types.Agent, error) {
+	resp, err := serverClient.NewAgentClient().ListAgents(ctx, &agentv1.ListAgentsRequest{
+		Filter:     &agentv1.ListAgentsRequest_Filter{ByCanReattest: wrapperspb.Bool(true)},
+		OutputMask: &types.AgentMask{X509SvidExpiresAt: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	var agents []*types.Agent
+	for _, agent := range resp.GetAgents() {
+		expirationTime := time.Unix(agent.X509SvidExpiresAt, 0)
+		if time.Since(expirationTime) > c.expiredFor {
+			agents = append(agents, agent)
+		}
+	}
+	return agents, nil
+}
+
+// listInactiveAgents returns the agents that have not been seen by the server
+// for longer than the -notSeenFor duration.
+func (c *purgeCommand) listInactiveAgents(ctx context.Context, serverClient util.ServerClient) ([]*types.Agent, error) {
+	agentClient := serverClient.NewAgentExtensionsClient()
+	filter := &agentextv1.SearchAgentsRequest_Filter{
+		ByCanReattest:    wrapperspb.Bool(true),
+		ByLastSeenBefore: time.Now().Add(-c.notSeenFor).Unix(),
+	}
+
+	var agents []*types.Agent
+	pageToken := ""
+	for {
+		resp, err := agentClient.SearchAgents(ctx, &agentextv1.SearchAgentsRequest{
+			Filter:     filter,
+			OutputMask: &types.AgentMask{},
+			PageSize:   1000,
+			PageToken:  pageToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search agents: %w", err)
+		}
+		for _, info := range resp.Agents {
+			// Agents that were never seen (e.g. attested before the server
+			// started recording activity) are left alone
+			if info.LastSeenAt == 0 {
+				continue
+			}
+			agents = append(agents, info.Agent)
+		}
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+	return agents, nil
+}
+
 func (c *purgeCommand) AppendFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&c.expiredFor, "expiredFor", 30*24*time.Hour, "Amount of time that has passed since the agent's SVID has expired. It is used to determine which agents to purge.")
+	fs.DurationVar(&c.notSeenFor, "notSeenFor", 0, "Amount of time that has passed since the agent was last seen by the server. When set, agents are purged based on this instead of -expiredFor.")
 	fs.BoolVar(&c.dryRun, "dryRun", false, "Indicates that the command will not perform any action, but will print the agents that would be purged.")
 
 	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, c.prettyPrintPurgeResult)
@@ -102,7 +159,11 @@ func (c *purgeCommand) prettyPrintPurgeResult(env *commoncli.Env, results ...int
 			return nil
 		}
 
-		msg := fmt.Sprintf("Found %d expired ", len(expAgents.Agents))
+		state := "expired"
+		if c.notSeenFor > 0 {
+			state = "inactive"
+		}
+		msg := fmt.Sprintf("Found %d %s ", len(expAgents.Agents), state)
 		msg = util.Pluralizer(msg, "agent", "agents", len(expAgents.Agents))
 		env.Printf("%s\n\n", msg)
 
@@ -3,15 +3,16 @@ package agent
 import (
 	"errors"
 	"flag"
+	"time"
 
 	"github.com/mitchellh/cli"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
-	agentv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/cmd/spire-server/util"
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/cliprinter"
 	"github.com/spiffe/spire/pkg/server/api"
+	agentextv1 "github.com/spiffe/spire/proto/spire/api/server/agent/v1"
 	"golang.org/x/net/context"
 )
 
@@ -19,7 +20,9 @@ type showCommand struct {
 	env *commoncli.Env
 	// SPIFFE ID of the agent being showed
 	spiffeID string
-	printer  cliprinter.Printer
+	// Activity of the agent being showed, only printed in pretty format
+	info    *agentextv1.AgentInfo
+	printer cliprinter.Printer
 }
 
 // NewShowCommand creates a new "show" subcommand for "agent" command.
@@ -52,21 +55,22 @@ func (c *showCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient ut
 		return err
 	}
 
-	agentClient := serverClient.NewAgentClient()
-	agent, err := agentClient.GetAgent(ctx, &agentv1.GetAgentRequest{Id: api.ProtoFromID(id)})
+	agentClient := serverClient.NewAgentExtensionsClient()
+	info, err := agentClient.GetAgentInfo(ctx, &agentextv1.GetAgentInfoRequest{Id: api.ProtoFromID(id)})
 	if err != nil {
 		return err
 	}
 
-	return c.printer.PrintProto(agent)
+	c.info = info
+	return c.printer.PrintProto(info.Agent)
 }
 
 func (c *showCommand) AppendFlags(fs *flag.FlagSet) {
 	fs.StringVar(&c.spiffeID, "spiffeID", "", "The SPIFFE ID of the agent to show (agent identity)")
-	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintAgent)
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, c.prettyPrintAgent)
 }
 
-func prettyPrintAgent(env *commoncli.Env, results ...interface{}) error {
+func (c *showCommand) prettyPrintAgent(env *commoncli.Env, results ...interface{}) error {
 	agent, ok := results[0].(*types.Agent)
 	if !ok {
 		return errors.New("internal error: cli printer; please report this bug")
@@ -80,5 +84,12 @@ func prettyPrintAgent(env *commoncli.Env, results ...interface{}) error {
 	for _, s := range agent.Selectors {
 		env.Printf("Selectors         : %s:%s\n", s.Type, s.Value)
 	}
+
+	// Agents are not seen until they sync with a server that records activity
+	if c.info != nil && c.info.LastSeenAt != 0 {
+		env.Printf("Last seen         : %s\n", time.Unix(c.info.LastSeenAt, 0))
+		env.Printf("Agent version     : %s\n", c.info.AgentVersion)
+		env.Printf("Remote address    : %s\n", c.info.RemoteAddress)
+	}
 	return nil
 }
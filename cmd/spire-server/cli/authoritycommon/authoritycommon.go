@@ -0,0 +1,24 @@
+package authoritycommon
+
+import (
+	"time"
+
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+)
+
+// PrettyPrintAuthorityState prints the given authority state under the
+// provided title (e.g. "Active X.509 authority"), or a message stating that
+// no such authority exists if the state is nil.
+func PrettyPrintAuthorityState(env *commoncli.Env, title string, state *localauthorityv1.AuthorityState) error {
+	if err := env.Printf("%s:\n", title); err != nil {
+		return err
+	}
+	if state == nil {
+		return env.Println("  No authority found")
+	}
+	if err := env.Printf("  Authority ID: %s\n", state.AuthorityId); err != nil {
+		return err
+	}
+	return env.Printf("  Expires at: %s\n", time.Unix(state.ExpiresAt, 0).UTC())
+}
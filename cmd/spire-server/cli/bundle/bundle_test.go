@@ -684,6 +684,55 @@ func TestList(t *testing.T) {
 	}
 }
 
+func TestListTable(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		args           []string
+		expectedStdout string
+	}{
+		{
+			name: "all bundles",
+			expectedStdout: `TRUST_DOMAIN           REFRESH_HINT  SEQUENCE_NUMBER
+spiffe://domain1.test  0             0
+spiffe://domain2.test  0             0
+`,
+		},
+		{
+			name: "one bundle",
+			args: []string{"-id", "spiffe://domain2.test"},
+			expectedStdout: `TRUST_DOMAIN           REFRESH_HINT  SEQUENCE_NUMBER
+spiffe://domain2.test  0             0
+`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupTest(t, newListCommand)
+			test.server.bundles = []*types.Bundle{
+				{
+					TrustDomain: "spiffe://domain1.test",
+					X509Authorities: []*types.X509Certificate{
+						{Asn1: test.cert1.Raw},
+					},
+				},
+				{
+					TrustDomain: "spiffe://domain2.test",
+					X509Authorities: []*types.X509Certificate{
+						{Asn1: test.cert2.Raw},
+					},
+				},
+			}
+			args := tt.args
+			args = append(args, "-output", "table", "-columns", "trust_domain,refresh_hint,sequence_number")
+
+			rc := test.client.Run(test.args(args...))
+
+			require.Equal(t, 0, rc)
+			require.Empty(t, test.stderr.String())
+			require.Equal(t, tt.expectedStdout, test.stdout.String())
+		})
+	}
+}
+
 func TestDeleteHelp(t *testing.T) {
 	test := setupTest(t, newDeleteCommand)
 	test.client.Help()
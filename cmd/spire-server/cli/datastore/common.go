@@ -0,0 +1,44 @@
+package datastore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+)
+
+// loadPassphrase reads the passphrase used to encrypt or decrypt the archive
+// from the given file. Surrounding whitespace is ignored.
+func loadPassphrase(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read passphrase file: %w", err)
+	}
+	passphrase := bytes.TrimSpace(data)
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase file is empty")
+	}
+	return passphrase, nil
+}
+
+func printRecordCounts(env *commoncli.Env, title string, counts *datastorev1.RecordCounts) error {
+	if err := env.Printf("%s:\n", title); err != nil {
+		return err
+	}
+	if err := env.Printf("  Bundles: %d\n", counts.GetBundles()); err != nil {
+		return err
+	}
+	if err := env.Printf("  Federation relationships: %d\n", counts.GetFederationRelationships()); err != nil {
+		return err
+	}
+	if err := env.Printf("  Attested nodes: %d\n", counts.GetAttestedNodes()); err != nil {
+		return err
+	}
+	if err := env.Printf("  Registration entries: %d\n", counts.GetRegistrationEntries()); err != nil {
+		return err
+	}
+	return env.Printf("  Join tokens: %d\n", counts.GetJoinTokens())
+}
@@ -0,0 +1,144 @@
+package datastore
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/cli/common"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+var availableFormats = []string{"pretty", "json"}
+
+type cmdTest struct {
+	stdin  *bytes.Buffer
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+
+	dir    string
+	addr   string
+	server *fakeServer
+
+	client cli.Command
+}
+
+func (c *cmdTest) afterTest(t *testing.T) {
+	t.Logf("TEST:%s", t.Name())
+	t.Logf("STDOUT:\n%s", c.stdout.String())
+	t.Logf("STDIN:\n%s", c.stdin.String())
+	t.Logf("STDERR:\n%s", c.stderr.String())
+}
+
+func (c *cmdTest) args(extra ...string) []string {
+	return append([]string{common.AddrArg, c.addr}, extra...)
+}
+
+func (c *cmdTest) writeFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(c.dir, name)
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return path
+}
+
+type fakeServer struct {
+	datastorev1.UnimplementedDataStoreServer
+
+	err error
+
+	// archive is streamed by Export, and holds the archive received by
+	// Import
+	archive []byte
+
+	exportCounts *datastorev1.RecordCounts
+	importResp   *datastorev1.ImportResponse
+}
+
+func (f *fakeServer) Export(_ *datastorev1.ExportRequest, stream datastorev1.DataStore_ExportServer) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	// Stream the archive in small chunks
+	archive := f.archive
+	for len(archive) > 0 {
+		n := 10
+		if n > len(archive) {
+			n = len(archive)
+		}
+		if err := stream.Send(&datastorev1.ExportResponse{Chunk: archive[:n]}); err != nil {
+			return err
+		}
+		archive = archive[n:]
+	}
+	return stream.Send(&datastorev1.ExportResponse{Counts: f.exportCounts})
+}
+
+func (f *fakeServer) Import(stream datastorev1.DataStore_ImportServer) error {
+	f.archive = nil
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		f.archive = append(f.archive, req.Chunk...)
+	}
+	if f.err != nil {
+		return f.err
+	}
+	return stream.SendAndClose(f.importResp)
+}
+
+func setupTest(t *testing.T, newClient func(*commoncli.Env) cli.Command) *cmdTest {
+	stdin := new(bytes.Buffer)
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	dir := t.TempDir()
+
+	client := newClient(&commoncli.Env{
+		Stdin:   stdin,
+		Stdout:  stdout,
+		Stderr:  stderr,
+		BaseDir: dir,
+	})
+
+	server := &fakeServer{}
+	addr := spiretest.StartGRPCServer(t, func(s *grpc.Server) {
+		datastorev1.RegisterDataStoreServer(s, server)
+	})
+
+	test := &cmdTest{
+		dir:    dir,
+		addr:   common.GetAddr(addr),
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		server: server,
+		client: client,
+	}
+
+	t.Cleanup(func() {
+		test.afterTest(t)
+	})
+
+	return test
+}
+
+func requireOutputBasedOnFormat(t *testing.T, format, stdoutString string, expectedStdoutPretty, expectedStdoutJSON string) {
+	switch format {
+	case "pretty":
+		require.Equal(t, expectedStdoutPretty, stdoutString)
+	case "json":
+		require.JSONEq(t, expectedStdoutJSON, stdoutString)
+	}
+}
@@ -0,0 +1,27 @@
+//go:build !windows
+// +build !windows
+
+package datastore
+
+var (
+	exportUsage = `Usage of datastore export:
+  -output value
+    	Desired output format (pretty, json, yaml); default: pretty.
+  -passphraseFile string
+    	Path to a file holding the passphrase used to encrypt the archive. If not set, the archive is not encrypted.
+  -path string
+    	Path to write the archive to
+  -socketPath string
+    	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
+`
+	importUsage = `Usage of datastore import:
+  -output value
+    	Desired output format (pretty, json, yaml); default: pretty.
+  -passphraseFile string
+    	Path to a file holding the passphrase used to decrypt the archive. Required if the archive is encrypted.
+  -path string
+    	Path to the archive to import
+  -socketPath string
+    	Path to the SPIRE Server API socket (default "/tmp/spire-server/private/api.sock")
+`
+)
@@ -0,0 +1,27 @@
+//go:build windows
+// +build windows
+
+package datastore
+
+var (
+	exportUsage = `Usage of datastore export:
+  -namedPipeName string
+    	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
+  -output value
+    	Desired output format (pretty, json, yaml); default: pretty.
+  -passphraseFile string
+    	Path to a file holding the passphrase used to encrypt the archive. If not set, the archive is not encrypted.
+  -path string
+    	Path to write the archive to
+`
+	importUsage = `Usage of datastore import:
+  -namedPipeName string
+    	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
+  -output value
+    	Desired output format (pretty, json, yaml); default: pretty.
+  -passphraseFile string
+    	Path to a file holding the passphrase used to decrypt the archive. Required if the archive is encrypted.
+  -path string
+    	Path to the archive to import
+`
+)
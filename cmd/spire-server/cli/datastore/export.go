@@ -0,0 +1,136 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"github.com/spiffe/spire/pkg/server/datastore/archive"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+)
+
+// NewExportCommand creates a new "datastore export" subcommand.
+func NewExportCommand() cli.Command {
+	return newExportCommand(commoncli.DefaultEnv)
+}
+
+func newExportCommand(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &exportCommand{env: env})
+}
+
+type exportCommand struct {
+	env     *commoncli.Env
+	printer cliprinter.Printer
+
+	// Path to the archive to write
+	path string
+
+	// Path to a file holding the passphrase used to encrypt the archive
+	// (optional)
+	passphraseFile string
+}
+
+func (c *exportCommand) Name() string {
+	return "datastore export"
+}
+
+func (*exportCommand) Synopsis() string {
+	return "Exports the contents of the datastore to an archive"
+}
+
+func (c *exportCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.path, "path", "", "Path to write the archive to")
+	fs.StringVar(&c.passphraseFile, "passphraseFile", "", "Path to a file holding the passphrase used to encrypt the archive. If not set, the archive is not encrypted.")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintExport)
+}
+
+func (c *exportCommand) Run(ctx context.Context, env *commoncli.Env, serverClient util.ServerClient) (err error) {
+	if c.path == "" {
+		return errors.New("path flag is required")
+	}
+
+	var passphrase []byte
+	if c.passphraseFile != "" {
+		passphrase, err = loadPassphrase(env.JoinPath(c.passphraseFile))
+		if err != nil {
+			return err
+		}
+	}
+
+	path := env.JoinPath(c.path)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to create archive: %w", err)
+	}
+	defer func() {
+		closeErr := f.Close()
+		if err == nil && closeErr != nil {
+			err = fmt.Errorf("unable to write archive: %w", closeErr)
+		}
+		// Do not leave partial archives behind
+		if err != nil {
+			_ = os.Remove(path)
+		}
+	}()
+
+	var w io.WriteCloser = nopWriteCloser{Writer: f}
+	if passphrase != nil {
+		w, err = archive.NewEncryptWriter(f, passphrase)
+		if err != nil {
+			return fmt.Errorf("unable to encrypt archive: %w", err)
+		}
+	}
+
+	stream, err := serverClient.NewDataStoreClient().Export(ctx, &datastorev1.ExportRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to export datastore: %w", err)
+	}
+
+	var counts *datastorev1.RecordCounts
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to export datastore: %w", err)
+		}
+		if _, err := w.Write(resp.Chunk); err != nil {
+			return fmt.Errorf("unable to write archive: %w", err)
+		}
+		if resp.Counts != nil {
+			counts = resp.Counts
+		}
+	}
+	if counts == nil {
+		return errors.New("failed to export datastore: export ended unexpectedly")
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("unable to write archive: %w", err)
+	}
+
+	return c.printer.PrintProto(counts)
+}
+
+func prettyPrintExport(env *commoncli.Env, results ...interface{}) error {
+	counts, ok := results[0].(*datastorev1.RecordCounts)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+	return printRecordCounts(env, "Exported records", counts)
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
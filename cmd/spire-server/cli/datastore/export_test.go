@@ -0,0 +1,121 @@
+package datastore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spiffe/spire/pkg/server/datastore/archive"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExportHelp(t *testing.T) {
+	test := setupTest(t, newExportCommand)
+	test.client.Help()
+
+	require.Equal(t, exportUsage, test.stderr.String())
+}
+
+func TestExportSynopsis(t *testing.T) {
+	test := setupTest(t, newExportCommand)
+	require.Equal(t, "Exports the contents of the datastore to an archive", test.client.Synopsis())
+}
+
+func TestExport(t *testing.T) {
+	archiveData := bytes.Repeat([]byte("archive"), 10)
+	counts := &datastorev1.RecordCounts{
+		Bundles:                 1,
+		FederationRelationships: 2,
+		AttestedNodes:           3,
+		RegistrationEntries:     4,
+		JoinTokens:              5,
+	}
+
+	for _, tt := range []struct {
+		name string
+
+		args      []string
+		encrypted bool
+		serverErr error
+
+		expectErr string
+	}{
+		{
+			name: "success",
+		},
+		{
+			name:      "success with encryption",
+			args:      []string{"-passphraseFile", "passphrase"},
+			encrypted: true,
+		},
+		{
+			name:      "missing path",
+			args:      []string{"-path", ""},
+			expectErr: "Error: path flag is required\n",
+		},
+		{
+			name:      "missing passphrase file",
+			args:      []string{"-passphraseFile", "does-not-exist"},
+			expectErr: "Error: unable to read passphrase file: open ",
+		},
+		{
+			name:      "server error",
+			serverErr: status.Error(codes.Internal, "oh no"),
+			expectErr: "Error: failed to export datastore: rpc error: code = Internal desc = oh no\n",
+		},
+	} {
+		tt := tt
+		for _, format := range availableFormats {
+			format := format
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, newExportCommand)
+				test.server.archive = archiveData
+				test.server.exportCounts = counts
+				test.server.err = tt.serverErr
+				test.writeFile(t, "passphrase", []byte("secret\n"))
+
+				args := append([]string{"-path", "archive", "-output", format}, tt.args...)
+				rc := test.client.Run(test.args(args...))
+				path := filepath.Join(test.dir, "archive")
+				if tt.expectErr != "" {
+					require.Equal(t, 1, rc)
+					require.Contains(t, test.stderr.String(), tt.expectErr)
+					require.NoFileExists(t, path)
+					return
+				}
+
+				require.Empty(t, test.stderr.String())
+				require.Equal(t, 0, rc)
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), `Exported records:
+  Bundles: 1
+  Federation relationships: 2
+  Attested nodes: 3
+  Registration entries: 4
+  Join tokens: 5
+`, `{"bundles":1,"federation_relationships":2,"attested_nodes":3,"registration_entries":4,"join_tokens":5}`)
+
+				f, err := os.Open(path)
+				require.NoError(t, err)
+				defer f.Close()
+				info, err := f.Stat()
+				require.NoError(t, err)
+				require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+				var r io.Reader = f
+				if tt.encrypted {
+					r, err = archive.NewDecryptReader(f, []byte("secret"))
+					require.NoError(t, err)
+				}
+				data, err := io.ReadAll(r)
+				require.NoError(t, err)
+				require.Equal(t, archiveData, data)
+			})
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package datastore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	"github.com/spiffe/spire/pkg/server/datastore/archive"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+)
+
+// chunkSize is the size of the archive chunks sent to the server.
+const chunkSize = 64 * 1024
+
+// NewImportCommand creates a new "datastore import" subcommand.
+func NewImportCommand() cli.Command {
+	return newImportCommand(commoncli.DefaultEnv)
+}
+
+func newImportCommand(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &importCommand{env: env})
+}
+
+type importCommand struct {
+	env     *commoncli.Env
+	printer cliprinter.Printer
+
+	// Path to the archive to read
+	path string
+
+	// Path to a file holding the passphrase used to decrypt the archive
+	// (required for encrypted archives)
+	passphraseFile string
+}
+
+func (c *importCommand) Name() string {
+	return "datastore import"
+}
+
+func (*importCommand) Synopsis() string {
+	return "Imports the contents of an archive into the datastore"
+}
+
+func (c *importCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.path, "path", "", "Path to the archive to import")
+	fs.StringVar(&c.passphraseFile, "passphraseFile", "", "Path to a file holding the passphrase used to decrypt the archive. Required if the archive is encrypted.")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintImport)
+}
+
+func (c *importCommand) Run(ctx context.Context, env *commoncli.Env, serverClient util.ServerClient) error {
+	if c.path == "" {
+		return errors.New("path flag is required")
+	}
+
+	f, err := os.Open(env.JoinPath(c.path))
+	if err != nil {
+		return fmt.Errorf("unable to open archive: %w", err)
+	}
+	defer f.Close()
+
+	br := bufio.NewReader(f)
+
+	var r io.Reader = br
+	switch {
+	case archive.IsEncrypted(br):
+		if c.passphraseFile == "" {
+			return errors.New("archive is encrypted; the passphraseFile flag is required")
+		}
+		passphrase, err := loadPassphrase(env.JoinPath(c.passphraseFile))
+		if err != nil {
+			return err
+		}
+		r, err = archive.NewDecryptReader(br, passphrase)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt archive: %w", err)
+		}
+	case c.passphraseFile != "":
+		return errors.New("archive is not encrypted; the passphraseFile flag must not be set")
+	}
+
+	stream, err := serverClient.NewDataStoreClient().Import(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to import datastore: %w", err)
+	}
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&datastorev1.ImportRequest{Chunk: buf[:n]}); err != nil {
+				// The server ended the stream; the actual error is
+				// returned by CloseAndRecv.
+				break
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read archive: %w", err)
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return fmt.Errorf("failed to import datastore: %w", err)
+	}
+
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintImport(env *commoncli.Env, results ...interface{}) error {
+	resp, ok := results[0].(*datastorev1.ImportResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+	if err := printRecordCounts(env, "Imported records", resp.Imported); err != nil {
+		return err
+	}
+	return printRecordCounts(env, "Skipped existing records", resp.Skipped)
+}
@@ -0,0 +1,133 @@
+package datastore
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/spiffe/spire/pkg/server/datastore/archive"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestImportHelp(t *testing.T) {
+	test := setupTest(t, newImportCommand)
+	test.client.Help()
+
+	require.Equal(t, importUsage, test.stderr.String())
+}
+
+func TestImportSynopsis(t *testing.T) {
+	test := setupTest(t, newImportCommand)
+	require.Equal(t, "Imports the contents of an archive into the datastore", test.client.Synopsis())
+}
+
+func TestImport(t *testing.T) {
+	// Large enough to be sent in several chunks
+	archiveData := bytes.Repeat([]byte("archive"), chunkSize/4)
+
+	encrypted := new(bytes.Buffer)
+	w, err := archive.NewEncryptWriter(encrypted, []byte("secret"))
+	require.NoError(t, err)
+	_, err = w.Write(archiveData)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	for _, tt := range []struct {
+		name string
+
+		archive   []byte
+		args      []string
+		serverErr error
+
+		expectErr string
+	}{
+		{
+			name:    "success",
+			archive: archiveData,
+		},
+		{
+			name:    "success with encrypted archive",
+			archive: encrypted.Bytes(),
+			args:    []string{"-passphraseFile", "passphrase"},
+		},
+		{
+			name:      "missing path",
+			archive:   archiveData,
+			args:      []string{"-path", ""},
+			expectErr: "Error: path flag is required\n",
+		},
+		{
+			name:      "archive does not exist",
+			archive:   archiveData,
+			args:      []string{"-path", "does-not-exist"},
+			expectErr: "Error: unable to open archive: open ",
+		},
+		{
+			name:      "encrypted archive without passphrase",
+			archive:   encrypted.Bytes(),
+			expectErr: "Error: archive is encrypted; the passphraseFile flag is required\n",
+		},
+		{
+			name:      "passphrase for archive that is not encrypted",
+			archive:   archiveData,
+			args:      []string{"-passphraseFile", "passphrase"},
+			expectErr: "Error: archive is not encrypted; the passphraseFile flag must not be set\n",
+		},
+		{
+			name:      "wrong passphrase",
+			archive:   encrypted.Bytes(),
+			args:      []string{"-passphraseFile", "wrong-passphrase"},
+			expectErr: "Error: unable to read archive: failed to decrypt archive: wrong key or corrupted archive\n",
+		},
+		{
+			name:      "server error",
+			archive:   archiveData,
+			serverErr: status.Error(codes.InvalidArgument, "invalid archive"),
+			expectErr: "Error: failed to import datastore: rpc error: code = InvalidArgument desc = invalid archive\n",
+		},
+	} {
+		tt := tt
+		for _, format := range availableFormats {
+			format := format
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, newImportCommand)
+				test.server.err = tt.serverErr
+				test.server.importResp = &datastorev1.ImportResponse{
+					Imported: &datastorev1.RecordCounts{Bundles: 1, RegistrationEntries: 2},
+					Skipped:  &datastorev1.RecordCounts{JoinTokens: 3},
+				}
+				test.writeFile(t, "archive", tt.archive)
+				test.writeFile(t, "passphrase", []byte("secret\n"))
+				test.writeFile(t, "wrong-passphrase", []byte("wrong\n"))
+
+				args := append([]string{"-path", "archive", "-output", format}, tt.args...)
+				rc := test.client.Run(test.args(args...))
+				if tt.expectErr != "" {
+					require.Equal(t, 1, rc)
+					require.Contains(t, test.stderr.String(), tt.expectErr)
+					return
+				}
+
+				require.Empty(t, test.stderr.String())
+				require.Equal(t, 0, rc)
+				require.Equal(t, archiveData, test.server.archive)
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), `Imported records:
+  Bundles: 1
+  Federation relationships: 0
+  Attested nodes: 0
+  Registration entries: 2
+  Join tokens: 0
+Skipped existing records:
+  Bundles: 0
+  Federation relationships: 0
+  Attested nodes: 0
+  Registration entries: 0
+  Join tokens: 3
+`, `{"imported":{"bundles":1,"federation_relationships":0,"attested_nodes":0,"registration_entries":2,"join_tokens":0},"skipped":{"bundles":0,"federation_relationships":0,"attested_nodes":0,"registration_entries":0,"join_tokens":3}}`)
+			})
+		}
+	}
+}
@@ -0,0 +1,551 @@
+package entry
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	commonutil "github.com/spiffe/spire/pkg/common/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"sigs.k8s.io/yaml"
+
+	"golang.org/x/net/context"
+)
+
+// applyBatchSize is the maximum number of entries sent in a single batch
+// request, to stay within the gRPC message size limits on large registries.
+const applyBatchSize = 500
+
+const (
+	applyActionCreate = "create"
+	applyActionUpdate = "update"
+	applyActionDelete = "delete"
+)
+
+// NewApplyCommand creates a new "apply" subcommand for "entry" command.
+func NewApplyCommand() cli.Command {
+	return newApplyCommand(commoncli.DefaultEnv)
+}
+
+func newApplyCommand(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &applyCommand{env: env})
+}
+
+type applyCommand struct {
+	// Paths to the entry documents, or to directories containing them
+	paths StringsFlag
+
+	// Parent ID of the entries managed by the documents
+	parentID string
+
+	// Hint of the entries managed by the documents
+	hint string
+
+	// Whether or not to only print the plan, without applying it
+	dryRun bool
+
+	printer cliprinter.Printer
+
+	env *commoncli.Env
+}
+
+func (*applyCommand) Name() string {
+	return "entry apply"
+}
+
+func (*applyCommand) Synopsis() string {
+	return "Reconciles registration entries with a set of entry documents"
+}
+
+func (c *applyCommand) AppendFlags(f *flag.FlagSet) {
+	f.Var(&c.paths, "f", "Path to a JSON or YAML file with registration entries, or to a directory containing them. Can be used more than once")
+	f.StringVar(&c.parentID, "parentID", "", "The Parent ID of the entries managed by the documents. Entries with this Parent ID that are not in the documents are deleted")
+	f.StringVar(&c.hint, "hint", "", "The hint of the entries managed by the documents. Entries with this hint that are not in the documents are deleted")
+	f.BoolVar(&c.dryRun, "dryRun", false, "Indicates that the command will not perform any action, but will print the plan to converge the entries")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, f, c.env, c.prettyPrintApply)
+}
+
+// Run executes all logic associated with a single invocation of the
+// `spire-server entry apply` CLI command
+func (c *applyCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
+	if err := c.validate(); err != nil {
+		return err
+	}
+
+	desired, err := c.loadEntries()
+	if err != nil {
+		return err
+	}
+
+	client := serverClient.NewEntryClient()
+	current, err := c.fetchEntries(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	result, err := planApply(desired, current)
+	if err != nil {
+		return err
+	}
+	result.DryRun = c.dryRun
+
+	if !c.dryRun {
+		if err := executeApply(ctx, client, result); err != nil {
+			return err
+		}
+	}
+
+	if err := c.printer.PrintStruct(result); err != nil {
+		return err
+	}
+	if result.failed() {
+		return errors.New("failed to apply one or more entries")
+	}
+	return nil
+}
+
+// validate ensures that the values in applyCommand are valid
+func (c *applyCommand) validate() error {
+	if len(c.paths) == 0 {
+		return errors.New("at least one path to entry documents is required")
+	}
+
+	// The scope prevents the deletion of entries not managed by the documents
+	if c.parentID == "" && c.hint == "" {
+		return errors.New("a parent ID or a hint is required to scope the entries to reconcile")
+	}
+
+	return nil
+}
+
+// loadEntries reads the entries in the documents, and checks that they are
+// in the scope of the command.
+func (c *applyCommand) loadEntries() ([]*types.Entry, error) {
+	var parentID *types.SPIFFEID
+	if c.parentID != "" {
+		id, err := idStringToProto(c.parentID)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing parent ID %q: %w", c.parentID, err)
+		}
+		parentID = id
+	}
+
+	files, err := documentFiles(c.paths)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*types.Entry
+	for _, file := range files {
+		fileEntries, err := parseEntryDocument(file)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", file, err)
+		}
+
+		for _, e := range fileEntries {
+			if parentID != nil && !proto.Equal(parentID, e.ParentId) {
+				return nil, fmt.Errorf("entry %q in %s has parent ID %q, which is out of the scope of parent ID %q",
+					protoToIDString(e.SpiffeId), file, protoToIDString(e.ParentId), c.parentID)
+			}
+
+			if c.hint != "" {
+				switch e.Hint {
+				case "":
+					e.Hint = c.hint
+				case c.hint:
+				default:
+					return nil, fmt.Errorf("entry %q in %s has hint %q, which is out of the scope of hint %q",
+						protoToIDString(e.SpiffeId), file, e.Hint, c.hint)
+				}
+			}
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	return entries, nil
+}
+
+// fetchEntries lists the entries in the scope of the command
+func (c *applyCommand) fetchEntries(ctx context.Context, client entryv1.EntryClient) ([]*types.Entry, error) {
+	filter := &entryv1.ListEntriesRequest_Filter{}
+	if c.parentID != "" {
+		id, err := idStringToProto(c.parentID)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing parent ID %q: %w", c.parentID, err)
+		}
+		filter.ByParentId = id
+	}
+	if c.hint != "" {
+		filter.ByHint = wrapperspb.String(c.hint)
+	}
+
+	var entries []*types.Entry
+	pageToken := ""
+	for {
+		resp, err := client.ListEntries(ctx, &entryv1.ListEntriesRequest{
+			PageSize:  listEntriesRequestPageSize,
+			PageToken: pageToken,
+			Filter:    filter,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching entries: %w", err)
+		}
+		entries = append(entries, resp.Entries...)
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// applyResult holds the actions needed to converge the entries, along with
+// their outcome when they are applied.
+type applyResult struct {
+	DryRun    bool           `json:"dry_run"`
+	Create    []*applyAction `json:"create"`
+	Update    []*applyAction `json:"update"`
+	Delete    []*applyAction `json:"delete"`
+	Unchanged int            `json:"unchanged"`
+}
+
+type applyAction struct {
+	Entry  *types.Entry  `json:"entry"`
+	Status *types.Status `json:"status,omitempty"`
+}
+
+func (r *applyResult) failed() bool {
+	for _, actions := range [][]*applyAction{r.Create, r.Update, r.Delete} {
+		for _, action := range actions {
+			if action.Status != nil && action.Status.Code != int32(codes.OK) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// planApply diffs the desired entries against the current ones. Desired
+// entries are matched to the current ones by entry ID when it is set, or
+// else by parent ID, SPIFFE ID and selectors, which identify an entry.
+func planApply(desired, current []*types.Entry) (*applyResult, error) {
+	currentByID := make(map[string]*types.Entry, len(current))
+	currentByKey := make(map[string]*types.Entry, len(current))
+	for _, e := range current {
+		currentByID[e.Id] = e
+		currentByKey[entryKey(e)] = e
+	}
+
+	result := &applyResult{
+		Create: []*applyAction{},
+		Update: []*applyAction{},
+		Delete: []*applyAction{},
+	}
+
+	seenKeys := make(map[string]struct{}, len(desired))
+	matched := make(map[string]struct{}, len(desired))
+	for _, e := range desired {
+		key := entryKey(e)
+		if _, ok := seenKeys[key]; ok {
+			return nil, fmt.Errorf("entry %q with parent ID %q is defined more than once",
+				protoToIDString(e.SpiffeId), protoToIDString(e.ParentId))
+		}
+		seenKeys[key] = struct{}{}
+
+		var existing *types.Entry
+		if e.Id != "" {
+			existing = currentByID[e.Id]
+		} else {
+			existing = currentByKey[key]
+		}
+
+		if existing == nil {
+			result.Create = append(result.Create, &applyAction{Entry: e})
+			continue
+		}
+		if _, ok := matched[existing.Id]; ok {
+			return nil, fmt.Errorf("entry %q is matched by more than one document entry", existing.Id)
+		}
+		matched[existing.Id] = struct{}{}
+
+		e.Id = existing.Id
+		if entriesEqual(e, existing) {
+			result.Unchanged++
+			continue
+		}
+		result.Update = append(result.Update, &applyAction{Entry: e})
+	}
+
+	for _, e := range current {
+		if _, ok := matched[e.Id]; !ok {
+			result.Delete = append(result.Delete, &applyAction{Entry: e})
+		}
+	}
+
+	for _, actions := range [][]*applyAction{result.Create, result.Update, result.Delete} {
+		sortActions(actions)
+	}
+
+	return result, nil
+}
+
+// executeApply issues the batch requests for the planned actions, recording
+// their status. Stale entries are deleted last, once their replacements exist.
+func executeApply(ctx context.Context, client entryv1.EntryClient, result *applyResult) error {
+	for _, batch := range batchActions(result.Create) {
+		resp, err := client.BatchCreateEntry(ctx, &entryv1.BatchCreateEntryRequest{Entries: entriesFromActions(batch)})
+		if err != nil {
+			return fmt.Errorf("error creating entries: %w", err)
+		}
+		if len(resp.Results) != len(batch) {
+			return fmt.Errorf("unexpected number of results creating entries: expected %d, got %d", len(batch), len(resp.Results))
+		}
+		for i, r := range resp.Results {
+			if r.Status == nil {
+				return fmt.Errorf("missing status creating entry %q", protoToIDString(batch[i].Entry.SpiffeId))
+			}
+			batch[i].Status = r.Status
+			if r.Status.Code == int32(codes.OK) {
+				batch[i].Entry = r.Entry
+			}
+		}
+	}
+
+	for _, batch := range batchActions(result.Update) {
+		resp, err := client.BatchUpdateEntry(ctx, &entryv1.BatchUpdateEntryRequest{Entries: entriesFromActions(batch)})
+		if err != nil {
+			return fmt.Errorf("error updating entries: %w", err)
+		}
+		if len(resp.Results) != len(batch) {
+			return fmt.Errorf("unexpected number of results updating entries: expected %d, got %d", len(batch), len(resp.Results))
+		}
+		for i, r := range resp.Results {
+			if r.Status == nil {
+				return fmt.Errorf("missing status updating entry %q", protoToIDString(batch[i].Entry.SpiffeId))
+			}
+			batch[i].Status = r.Status
+			if r.Status.Code == int32(codes.OK) {
+				batch[i].Entry = r.Entry
+			}
+		}
+	}
+
+	for _, batch := range batchActions(result.Delete) {
+		ids := make([]string, 0, len(batch))
+		for _, action := range batch {
+			ids = append(ids, action.Entry.Id)
+		}
+		resp, err := client.BatchDeleteEntry(ctx, &entryv1.BatchDeleteEntryRequest{Ids: ids})
+		if err != nil {
+			return fmt.Errorf("error deleting entries: %w", err)
+		}
+		if len(resp.Results) != len(batch) {
+			return fmt.Errorf("unexpected number of results deleting entries: expected %d, got %d", len(batch), len(resp.Results))
+		}
+		for i, r := range resp.Results {
+			if r.Status == nil {
+				return fmt.Errorf("missing status deleting entry %q", batch[i].Entry.Id)
+			}
+			batch[i].Status = r.Status
+		}
+	}
+
+	return nil
+}
+
+func (c *applyCommand) prettyPrintApply(env *commoncli.Env, results ...interface{}) error {
+	result, ok := results[0].([]interface{})[0].(*applyResult)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	if result.DryRun {
+		env.Printf("Plan: %d to create, %d to update, %d to delete, %d unchanged.\n",
+			len(result.Create), len(result.Update), len(result.Delete), result.Unchanged)
+
+		for _, section := range []struct {
+			title   string
+			actions []*applyAction
+		}{
+			{title: "Entries to create:", actions: result.Create},
+			{title: "Entries to update:", actions: result.Update},
+			{title: "Entries to delete:", actions: result.Delete},
+		} {
+			if len(section.actions) == 0 {
+				continue
+			}
+			env.Printf("\n%s\n", section.title)
+			for _, action := range section.actions {
+				printEntry(action.Entry, env.Printf)
+			}
+		}
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for _, section := range []struct {
+		action  string
+		actions []*applyAction
+	}{
+		{action: applyActionCreate, actions: result.Create},
+		{action: applyActionUpdate, actions: result.Update},
+		{action: applyActionDelete, actions: result.Delete},
+	} {
+		for _, action := range section.actions {
+			if action.Status == nil {
+				return fmt.Errorf("missing status of the %s of entry %q", section.action, protoToIDString(action.Entry.SpiffeId))
+			}
+			if action.Status.Code == int32(codes.OK) {
+				counts[section.action]++
+				continue
+			}
+			env.ErrPrintf("Failed to %s the following entry (code: %s, msg: %q):\n",
+				section.action,
+				codes.Code(action.Status.Code),
+				action.Status.Message)
+			printEntry(action.Entry, env.ErrPrintf)
+		}
+	}
+
+	env.Printf("Applied: %d created, %d updated, %d deleted, %d unchanged.\n",
+		counts[applyActionCreate], counts[applyActionUpdate], counts[applyActionDelete], result.Unchanged)
+	return nil
+}
+
+// documentFiles returns the JSON and YAML files in the given paths. The
+// files in a directory are returned in lexical order, and subdirectories are
+// not traversed.
+func documentFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		dirEntries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, dirEntry := range dirEntries {
+			if dirEntry.IsDir() || !isEntryDocument(dirEntry.Name()) {
+				continue
+			}
+			files = append(files, filepath.Join(path, dirEntry.Name()))
+		}
+	}
+	return files, nil
+}
+
+func isEntryDocument(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	default:
+		return false
+	}
+}
+
+// parseEntryDocument parses a document with registration entries, in the
+// same form expected by the -data flag of `entry create`. YAML documents are
+// supported for files with a .yaml or .yml extension.
+func parseEntryDocument(path string) ([]*types.Entry, error) {
+	dat, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dat, err = yaml.YAMLToJSON(dat)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entriesFromJSON(dat)
+}
+
+// entryKey returns a key identifying the entry by its parent ID, SPIFFE ID
+// and selectors.
+func entryKey(e *types.Entry) string {
+	selectors := make([]string, 0, len(e.Selectors))
+	for _, s := range e.Selectors {
+		selectors = append(selectors, s.Type+":"+s.Value)
+	}
+	sort.Strings(selectors)
+
+	return strings.Join(append([]string{
+		protoToIDString(e.ParentId),
+		protoToIDString(e.SpiffeId),
+	}, selectors...), "\x00")
+}
+
+// entriesEqual returns true if the entries have the same fields, ignoring the
+// fields set by the server and the order of selectors and federated trust
+// domains.
+func entriesEqual(a, b *types.Entry) bool {
+	return proto.Equal(normalizeEntry(a), normalizeEntry(b))
+}
+
+func normalizeEntry(e *types.Entry) *types.Entry {
+	n := proto.Clone(e).(*types.Entry)
+	n.RevisionNumber = 0
+	n.CreatedAt = 0
+	commonutil.SortTypesSelectors(n.Selectors)
+	sort.Strings(n.FederatesWith)
+	if len(n.Selectors) == 0 {
+		n.Selectors = nil
+	}
+	if len(n.FederatesWith) == 0 {
+		n.FederatesWith = nil
+	}
+	if len(n.DnsNames) == 0 {
+		n.DnsNames = nil
+	}
+	return n
+}
+
+func sortActions(actions []*applyAction) {
+	entries := entriesFromActions(actions)
+	commonutil.SortTypesEntries(entries)
+	for i, e := range entries {
+		actions[i] = &applyAction{Entry: e}
+	}
+}
+
+func entriesFromActions(actions []*applyAction) []*types.Entry {
+	entries := make([]*types.Entry, 0, len(actions))
+	for _, action := range actions {
+		entries = append(entries, action.Entry)
+	}
+	return entries
+}
+
+func batchActions(actions []*applyAction) [][]*applyAction {
+	var batches [][]*applyAction
+	for len(actions) > applyBatchSize {
+		batches = append(batches, actions[:applyBatchSize])
+		actions = actions[applyBatchSize:]
+	}
+	if len(actions) > 0 {
+		batches = append(batches, actions)
+	}
+	return batches
+}
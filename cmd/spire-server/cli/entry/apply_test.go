@@ -0,0 +1,487 @@
+package entry
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	applyJSONDocument = `{
+  "entries": [
+    {
+      "selectors": [{"type": "unix", "value": "uid:1111"}],
+      "spiffe_id": "spiffe://example.org/unchanged",
+      "parent_id": "spiffe://example.org/agent",
+      "x509_svid_ttl": 200,
+      "jwt_svid_ttl": 30
+    },
+    {
+      "selectors": [{"type": "unix", "value": "uid:2222"}],
+      "spiffe_id": "spiffe://example.org/updated",
+      "parent_id": "spiffe://example.org/agent",
+      "x509_svid_ttl": 300,
+      "jwt_svid_ttl": 30
+    }
+  ]
+}`
+	applyYAMLDocument = `entries:
+  - selectors:
+      - type: unix
+        value: uid:3333
+    spiffe_id: spiffe://example.org/created
+    parent_id: spiffe://example.org/agent
+    x509_svid_ttl: 200
+    jwt_svid_ttl: 30
+`
+)
+
+func TestApplyHelp(t *testing.T) {
+	test := setupTest(t, newApplyCommand)
+	test.client.Help()
+
+	require.Equal(t, applyUsage, test.stderr.String())
+}
+
+func TestApplySynopsis(t *testing.T) {
+	test := setupTest(t, newApplyCommand)
+	require.Equal(t, "Reconciles registration entries with a set of entry documents", test.client.Synopsis())
+}
+
+func TestApply(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.json"), []byte(applyJSONDocument), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte(applyYAMLDocument), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not an entry document"), 0600))
+
+	duplicatedPath := filepath.Join(t.TempDir(), "duplicated.yml")
+	require.NoError(t, os.WriteFile(duplicatedPath, []byte(applyYAMLDocument+applyYAMLDocument[len("entries:\n"):]), 0600))
+
+	invalidPath := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalidPath, []byte("{"), 0600))
+
+	agentID := &types.SPIFFEID{TrustDomain: "example.org", Path: "/agent"}
+
+	unchanged := &types.Entry{
+		Id:          "unchanged-id",
+		SpiffeId:    &types.SPIFFEID{TrustDomain: "example.org", Path: "/unchanged"},
+		ParentId:    agentID,
+		Selectors:   []*types.Selector{{Type: "unix", Value: "uid:1111"}},
+		X509SvidTtl: 200,
+		JwtSvidTtl:  30,
+		CreatedAt:   1678731397,
+	}
+	outdated := &types.Entry{
+		Id:             "updated-id",
+		SpiffeId:       &types.SPIFFEID{TrustDomain: "example.org", Path: "/updated"},
+		ParentId:       agentID,
+		Selectors:      []*types.Selector{{Type: "unix", Value: "uid:2222"}},
+		X509SvidTtl:    200,
+		JwtSvidTtl:     30,
+		RevisionNumber: 1,
+	}
+	stale := &types.Entry{
+		Id:          "stale-id",
+		SpiffeId:    &types.SPIFFEID{TrustDomain: "example.org", Path: "/stale"},
+		ParentId:    agentID,
+		Selectors:   []*types.Selector{{Type: "unix", Value: "uid:4444"}},
+		X509SvidTtl: 200,
+		JwtSvidTtl:  30,
+	}
+
+	toCreate := &types.Entry{
+		SpiffeId:    &types.SPIFFEID{TrustDomain: "example.org", Path: "/created"},
+		ParentId:    agentID,
+		Selectors:   []*types.Selector{{Type: "unix", Value: "uid:3333"}},
+		X509SvidTtl: 200,
+		JwtSvidTtl:  30,
+	}
+	created := &types.Entry{
+		Id:          "created-id",
+		SpiffeId:    &types.SPIFFEID{TrustDomain: "example.org", Path: "/created"},
+		ParentId:    agentID,
+		Selectors:   []*types.Selector{{Type: "unix", Value: "uid:3333"}},
+		X509SvidTtl: 200,
+		JwtSvidTtl:  30,
+	}
+	toUpdate := &types.Entry{
+		Id:          "updated-id",
+		SpiffeId:    &types.SPIFFEID{TrustDomain: "example.org", Path: "/updated"},
+		ParentId:    agentID,
+		Selectors:   []*types.Selector{{Type: "unix", Value: "uid:2222"}},
+		X509SvidTtl: 300,
+		JwtSvidTtl:  30,
+	}
+	updated := &types.Entry{
+		Id:             "updated-id",
+		SpiffeId:       &types.SPIFFEID{TrustDomain: "example.org", Path: "/updated"},
+		ParentId:       agentID,
+		Selectors:      []*types.Selector{{Type: "unix", Value: "uid:2222"}},
+		X509SvidTtl:    300,
+		JwtSvidTtl:     30,
+		RevisionNumber: 2,
+	}
+
+	expListReq := &entryv1.ListEntriesRequest{
+		PageSize: listEntriesRequestPageSize,
+		Filter: &entryv1.ListEntriesRequest_Filter{
+			ByParentId: agentID,
+		},
+	}
+	listResp := &entryv1.ListEntriesResponse{
+		Entries: []*types.Entry{unchanged, outdated, stale},
+	}
+	expCreateReq := &entryv1.BatchCreateEntryRequest{Entries: []*types.Entry{toCreate}}
+	expUpdateReq := &entryv1.BatchUpdateEntryRequest{Entries: []*types.Entry{toUpdate}}
+	expDeleteReq := &entryv1.BatchDeleteEntryRequest{Ids: []string{"stale-id"}}
+
+	createResp := &entryv1.BatchCreateEntryResponse{
+		Results: []*entryv1.BatchCreateEntryResponse_Result{
+			{Status: &types.Status{Code: int32(codes.OK), Message: "OK"}, Entry: created},
+		},
+	}
+	updateResp := &entryv1.BatchUpdateEntryResponse{
+		Results: []*entryv1.BatchUpdateEntryResponse_Result{
+			{Status: &types.Status{Code: int32(codes.OK), Message: "OK"}, Entry: updated},
+		},
+	}
+	deleteResp := &entryv1.BatchDeleteEntryResponse{
+		Results: []*entryv1.BatchDeleteEntryResponse_Result{
+			{Status: &types.Status{Code: int32(codes.OK), Message: "OK"}, Id: "stale-id"},
+		},
+	}
+	deleteRespErr := &entryv1.BatchDeleteEntryResponse{
+		Results: []*entryv1.BatchDeleteEntryResponse_Result{
+			{Status: &types.Status{Code: int32(codes.Internal), Message: "failed to delete entry"}, Id: "stale-id"},
+		},
+	}
+
+	createRespMissingResult := &entryv1.BatchCreateEntryResponse{}
+	updateRespMissingStatus := &entryv1.BatchUpdateEntryResponse{
+		Results: []*entryv1.BatchUpdateEntryResponse_Result{
+			{Entry: updated},
+		},
+	}
+
+	for _, tt := range []struct {
+		name string
+		args []string
+
+		expListReq   *entryv1.ListEntriesRequest
+		listResp     *entryv1.ListEntriesResponse
+		expCreateReq *entryv1.BatchCreateEntryRequest
+		createResp   *entryv1.BatchCreateEntryResponse
+		expUpdateReq *entryv1.BatchUpdateEntryRequest
+		updateResp   *entryv1.BatchUpdateEntryResponse
+		expDeleteReq *entryv1.BatchDeleteEntryRequest
+		deleteResp   *entryv1.BatchDeleteEntryResponse
+		serverErr    error
+
+		expOutPretty string
+		expOutJSON   string
+		expErrPretty string
+		expErrJSON   string
+	}{
+		{
+			name:         "Missing path",
+			args:         []string{"-parentID", "spiffe://example.org/agent"},
+			expErrPretty: "Error: at least one path to entry documents is required\n",
+			expErrJSON:   "Error: at least one path to entry documents is required\n",
+		},
+		{
+			name:         "Missing scope",
+			args:         []string{"-f", dir},
+			expErrPretty: "Error: a parent ID or a hint is required to scope the entries to reconcile\n",
+			expErrJSON:   "Error: a parent ID or a hint is required to scope the entries to reconcile\n",
+		},
+		{
+			name:         "Invalid parent ID",
+			args:         []string{"-f", dir, "-parentID", "example.org/agent"},
+			expErrPretty: "Error: error parsing parent ID \"example.org/agent\": scheme is missing or invalid\n",
+			expErrJSON:   "Error: error parsing parent ID \"example.org/agent\": scheme is missing or invalid\n",
+		},
+		{
+			name:         "Entry out of parent ID scope",
+			args:         []string{"-f", dir, "-parentID", "spiffe://example.org/other"},
+			expErrPretty: fmt.Sprintf("Error: entry \"spiffe://example.org/unchanged\" in %s has parent ID \"spiffe://example.org/agent\", which is out of the scope of parent ID \"spiffe://example.org/other\"\n", filepath.Join(dir, "a.json")),
+			expErrJSON:   fmt.Sprintf("Error: entry \"spiffe://example.org/unchanged\" in %s has parent ID \"spiffe://example.org/agent\", which is out of the scope of parent ID \"spiffe://example.org/other\"\n", filepath.Join(dir, "a.json")),
+		},
+		{
+			name:         "Invalid document",
+			args:         []string{"-f", invalidPath, "-parentID", "spiffe://example.org/agent"},
+			expErrPretty: fmt.Sprintf("Error: error parsing %s: unexpected end of JSON input\n", invalidPath),
+			expErrJSON:   fmt.Sprintf("Error: error parsing %s: unexpected end of JSON input\n", invalidPath),
+		},
+		{
+			name:         "Duplicated entries",
+			args:         []string{"-f", duplicatedPath, "-parentID", "spiffe://example.org/agent"},
+			expListReq:   expListReq,
+			listResp:     &entryv1.ListEntriesResponse{},
+			expErrPretty: "Error: entry \"spiffe://example.org/created\" with parent ID \"spiffe://example.org/agent\" is defined more than once\n",
+			expErrJSON:   "Error: entry \"spiffe://example.org/created\" with parent ID \"spiffe://example.org/agent\" is defined more than once\n",
+		},
+		{
+			name:         "Server error",
+			args:         []string{"-f", dir, "-parentID", "spiffe://example.org/agent"},
+			expListReq:   expListReq,
+			serverErr:    errors.New("server-error"),
+			expErrPretty: "Error: error fetching entries: rpc error: code = Unknown desc = server-error\n",
+			expErrJSON:   "Error: error fetching entries: rpc error: code = Unknown desc = server-error\n",
+		},
+		{
+			name:       "Dry run",
+			args:       []string{"-f", dir, "-parentID", "spiffe://example.org/agent", "-dryRun"},
+			expListReq: expListReq,
+			listResp:   listResp,
+			expOutPretty: `Plan: 1 to create, 1 to update, 1 to delete, 1 unchanged.
+
+Entries to create:
+Entry ID         : (none)
+SPIFFE ID        : spiffe://example.org/created
+Parent ID        : spiffe://example.org/agent
+Revision         : 0
+X509-SVID TTL    : 200
+JWT-SVID TTL     : 30
+Selector         : unix:uid:3333
+
+
+Entries to update:
+Entry ID         : updated-id
+SPIFFE ID        : spiffe://example.org/updated
+Parent ID        : spiffe://example.org/agent
+Revision         : 0
+X509-SVID TTL    : 300
+JWT-SVID TTL     : 30
+Selector         : unix:uid:2222
+
+
+Entries to delete:
+Entry ID         : stale-id
+SPIFFE ID        : spiffe://example.org/stale
+Parent ID        : spiffe://example.org/agent
+Revision         : 0
+X509-SVID TTL    : 200
+JWT-SVID TTL     : 30
+Selector         : unix:uid:4444
+
+`,
+			expOutJSON: `[{
+  "dry_run": true,
+  "create": [
+    {
+      "entry": {
+        "spiffe_id": {"trust_domain": "example.org", "path": "/created"},
+        "parent_id": {"trust_domain": "example.org", "path": "/agent"},
+        "selectors": [{"type": "unix", "value": "uid:3333"}],
+        "x509_svid_ttl": 200,
+        "jwt_svid_ttl": 30
+      }
+    }
+  ],
+  "update": [
+    {
+      "entry": {
+        "id": "updated-id",
+        "spiffe_id": {"trust_domain": "example.org", "path": "/updated"},
+        "parent_id": {"trust_domain": "example.org", "path": "/agent"},
+        "selectors": [{"type": "unix", "value": "uid:2222"}],
+        "x509_svid_ttl": 300,
+        "jwt_svid_ttl": 30
+      }
+    }
+  ],
+  "delete": [
+    {
+      "entry": {
+        "id": "stale-id",
+        "spiffe_id": {"trust_domain": "example.org", "path": "/stale"},
+        "parent_id": {"trust_domain": "example.org", "path": "/agent"},
+        "selectors": [{"type": "unix", "value": "uid:4444"}],
+        "x509_svid_ttl": 200,
+        "jwt_svid_ttl": 30
+      }
+    }
+  ],
+  "unchanged": 1
+}]`,
+		},
+		{
+			name:         "Apply succeeds",
+			args:         []string{"-f", filepath.Join(dir, "a.json"), "-f", filepath.Join(dir, "b.yaml"), "-parentID", "spiffe://example.org/agent"},
+			expListReq:   expListReq,
+			listResp:     listResp,
+			expCreateReq: expCreateReq,
+			createResp:   createResp,
+			expUpdateReq: expUpdateReq,
+			updateResp:   updateResp,
+			expDeleteReq: expDeleteReq,
+			deleteResp:   deleteResp,
+			expOutPretty: "Applied: 1 created, 1 updated, 1 deleted, 1 unchanged.\n",
+			expOutJSON: `[{
+  "dry_run": false,
+  "create": [
+    {
+      "entry": {
+        "id": "created-id",
+        "spiffe_id": {"trust_domain": "example.org", "path": "/created"},
+        "parent_id": {"trust_domain": "example.org", "path": "/agent"},
+        "selectors": [{"type": "unix", "value": "uid:3333"}],
+        "x509_svid_ttl": 200,
+        "jwt_svid_ttl": 30
+      },
+      "status": {"message": "OK"}
+    }
+  ],
+  "update": [
+    {
+      "entry": {
+        "id": "updated-id",
+        "spiffe_id": {"trust_domain": "example.org", "path": "/updated"},
+        "parent_id": {"trust_domain": "example.org", "path": "/agent"},
+        "selectors": [{"type": "unix", "value": "uid:2222"}],
+        "x509_svid_ttl": 300,
+        "jwt_svid_ttl": 30,
+        "revision_number": 2
+      },
+      "status": {"message": "OK"}
+    }
+  ],
+  "delete": [
+    {
+      "entry": {
+        "id": "stale-id",
+        "spiffe_id": {"trust_domain": "example.org", "path": "/stale"},
+        "parent_id": {"trust_domain": "example.org", "path": "/agent"},
+        "selectors": [{"type": "unix", "value": "uid:4444"}],
+        "x509_svid_ttl": 200,
+        "jwt_svid_ttl": 30
+      },
+      "status": {"message": "OK"}
+    }
+  ],
+  "unchanged": 1
+}]`,
+		},
+		{
+			name:         "Apply fails for some entries",
+			args:         []string{"-f", dir, "-parentID", "spiffe://example.org/agent"},
+			expListReq:   expListReq,
+			listResp:     listResp,
+			expCreateReq: expCreateReq,
+			createResp:   createResp,
+			expUpdateReq: expUpdateReq,
+			updateResp:   updateResp,
+			expDeleteReq: expDeleteReq,
+			deleteResp:   deleteRespErr,
+			expErrPretty: `Failed to delete the following entry (code: Internal, msg: "failed to delete entry"):
+Entry ID         : stale-id
+SPIFFE ID        : spiffe://example.org/stale
+Parent ID        : spiffe://example.org/agent
+Revision         : 0
+X509-SVID TTL    : 200
+JWT-SVID TTL     : 30
+Selector         : unix:uid:4444
+
+Error: failed to apply one or more entries
+`,
+			expErrJSON: "Error: failed to apply one or more entries\n",
+		},
+		{
+			name:         "Unexpected number of results",
+			args:         []string{"-f", dir, "-parentID", "spiffe://example.org/agent"},
+			expListReq:   expListReq,
+			listResp:     listResp,
+			expCreateReq: expCreateReq,
+			createResp:   createRespMissingResult,
+			expErrPretty: "Error: unexpected number of results creating entries: expected 1, got 0\n",
+			expErrJSON:   "Error: unexpected number of results creating entries: expected 1, got 0\n",
+		},
+		{
+			name:         "Missing result status",
+			args:         []string{"-f", dir, "-parentID", "spiffe://example.org/agent"},
+			expListReq:   expListReq,
+			listResp:     listResp,
+			expCreateReq: expCreateReq,
+			createResp:   createResp,
+			expUpdateReq: expUpdateReq,
+			updateResp:   updateRespMissingStatus,
+			expErrPretty: "Error: missing status updating entry \"spiffe://example.org/updated\"\n",
+			expErrJSON:   "Error: missing status updating entry \"spiffe://example.org/updated\"\n",
+		},
+	} {
+		for _, format := range availableFormats {
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, newApplyCommand)
+				test.server.err = tt.serverErr
+				test.server.expListEntriesReq = tt.expListReq
+				test.server.listEntriesResp = tt.listResp
+				test.server.expBatchCreateEntryReq = tt.expCreateReq
+				test.server.batchCreateEntryResp = tt.createResp
+				test.server.expBatchUpdateEntryReq = tt.expUpdateReq
+				test.server.batchUpdateEntryResp = tt.updateResp
+				test.server.expBatchDeleteEntryReq = tt.expDeleteReq
+				test.server.batchDeleteEntryResp = tt.deleteResp
+				args := tt.args
+				args = append(args, "-output", format)
+
+				rc := test.client.Run(test.args(args...))
+
+				if tt.expErrJSON != "" && format == "json" {
+					require.Equal(t, 1, rc)
+					require.Equal(t, tt.expErrJSON, test.stderr.String())
+					return
+				}
+				if tt.expErrPretty != "" && format == "pretty" {
+					require.Equal(t, 1, rc)
+					require.Equal(t, tt.expErrPretty, test.stderr.String())
+					return
+				}
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expOutPretty, tt.expOutJSON)
+				require.Equal(t, 0, rc)
+			})
+		}
+	}
+}
+
+func TestApplyWithHint(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "entries.yaml"), []byte(applyYAMLDocument), 0600))
+
+	test := setupTest(t, newApplyCommand)
+	test.server.expListEntriesReq = &entryv1.ListEntriesRequest{
+		PageSize: listEntriesRequestPageSize,
+		Filter: &entryv1.ListEntriesRequest_Filter{
+			ByHint: wrapperspb.String("gitops"),
+		},
+	}
+	test.server.listEntriesResp = &entryv1.ListEntriesResponse{}
+
+	rc := test.client.Run(test.args("-f", dir, "-hint", "gitops", "-dryRun", "-output", "json"))
+	require.Equal(t, 0, rc, test.stderr.String())
+	require.JSONEq(t, `[{
+  "dry_run": true,
+  "create": [
+    {
+      "entry": {
+        "spiffe_id": {"trust_domain": "example.org", "path": "/created"},
+        "parent_id": {"trust_domain": "example.org", "path": "/agent"},
+        "selectors": [{"type": "unix", "value": "uid:3333"}],
+        "x509_svid_ttl": 200,
+        "jwt_svid_ttl": 30,
+        "hint": "gitops"
+      }
+    }
+  ],
+  "update": [],
+  "delete": [],
+  "unchanged": 0
+}]`, test.stdout.String())
+}
@@ -4,14 +4,17 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"strings"
 
 	"github.com/mitchellh/cli"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/cmd/spire-server/util"
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/cliprinter"
 	commonutil "github.com/spiffe/spire/pkg/common/util"
+	entryextv1 "github.com/spiffe/spire/proto/spire/api/server/entry/v1"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
 	"golang.org/x/net/context"
@@ -39,9 +42,13 @@ type showCommand struct {
 	// Workload parent spiffeID
 	parentID string
 
-	// Workload spiffeID
+	// Workload spiffeID. A trailing "*" matches every SPIFFE ID with the
+	// given prefix
 	spiffeID string
 
+	// Type of any of the entry selectors
+	selectorType string
+
 	// Entry hint
 	hint string
 
@@ -51,6 +58,18 @@ type showCommand struct {
 	// Whether or not the entry is for a downstream SPIRE server
 	downstream bool
 
+	// Whether or not the entry grants access to the SPIRE Server's
+	// management APIs
+	admin bool
+
+	// Entry expiry bounds, from epoch in seconds
+	expiresBefore int64
+	expiresAfter  int64
+
+	// Lifetimes, in seconds, of the SVIDs issued based on the entry
+	x509SVIDTTL int
+	jwtSVIDTTL  int
+
 	// Match used when filtering by federates with
 	matchFederatesWithOn string
 
@@ -73,14 +92,20 @@ func (*showCommand) Synopsis() string {
 func (c *showCommand) AppendFlags(f *flag.FlagSet) {
 	f.StringVar(&c.entryID, "entryID", "", "The Entry ID of the records to show")
 	f.StringVar(&c.parentID, "parentID", "", "The Parent ID of the records to show")
-	f.StringVar(&c.spiffeID, "spiffeID", "", "The SPIFFE ID of the records to show")
-	f.BoolVar(&c.downstream, "downstream", false, "A boolean value that, when set, indicates that the entry describes a downstream SPIRE server")
+	f.StringVar(&c.spiffeID, "spiffeID", "", "The SPIFFE ID of the records to show. A trailing '*' shows the records with SPIFFE IDs starting with the given prefix (e.g. spiffe://example.org/ns/prod/*)")
+	f.BoolVar(&c.downstream, "downstream", false, "If set, only shows the records describing a downstream SPIRE server")
+	f.BoolVar(&c.admin, "admin", false, "If set, only shows the records granting access to the SPIRE Server's management APIs")
 	f.Var(&c.selectors, "selector", "A colon-delimited type:value selector. Can be used more than once")
+	f.StringVar(&c.selectorType, "selectorType", "", "Only shows the records with a selector of the given type")
+	f.Int64Var(&c.expiresBefore, "expiresBefore", 0, "Only shows the records expiring before the given time, from epoch in seconds")
+	f.Int64Var(&c.expiresAfter, "expiresAfter", 0, "Only shows the records expiring after the given time, from epoch in seconds")
+	f.IntVar(&c.x509SVIDTTL, "x509SVIDTTL", 0, "Only shows the records with the given lifetime, in seconds, for x509-SVIDs")
+	f.IntVar(&c.jwtSVIDTTL, "jwtSVIDTTL", 0, "Only shows the records with the given lifetime, in seconds, for JWT-SVIDs")
 	f.Var(&c.federatesWith, "federatesWith", "SPIFFE ID of a trust domain an entry is federate with. Can be used more than once")
 	f.StringVar(&c.matchFederatesWithOn, "matchFederatesWithOn", "superset", "The match mode used when filtering by federates with. Options: exact, any, superset and subset")
 	f.StringVar(&c.matchSelectorsOn, "matchSelectorsOn", "superset", "The match mode used when filtering by selectors. Options: exact, any, superset and subset")
 	f.StringVar(&c.hint, "hint", "", "The Hint of the records to show (optional)")
-	cliprinter.AppendFlagWithCustomPretty(&c.printer, f, c.env, prettyPrintShow)
+	cliprinter.AppendTableFlagWithCustomPretty(&c.printer, f, c.env, prettyPrintShow)
 }
 
 // Run executes all logic associated with a single invocation of the
@@ -90,7 +115,7 @@ func (c *showCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient ut
 		return err
 	}
 
-	resp, err := c.fetchEntries(ctx, serverClient.NewEntryClient())
+	resp, err := c.fetchEntries(ctx, serverClient)
 	if err != nil {
 		return err
 	}
@@ -103,7 +128,7 @@ func (c *showCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient ut
 func (c *showCommand) validate() error {
 	// If entryID is given, it should be the only constraint
 	if c.entryID != "" {
-		if c.parentID != "" || c.spiffeID != "" || len(c.selectors) > 0 {
+		if c.parentID != "" || c.spiffeID != "" || len(c.selectors) > 0 || c.hasSearchFilters() {
 			return errors.New("the -entryID flag can't be combined with others")
 		}
 	}
@@ -111,7 +136,21 @@ func (c *showCommand) validate() error {
 	return nil
 }
 
-func (c *showCommand) fetchEntries(ctx context.Context, client entryv1.EntryClient) (*entryv1.ListEntriesResponse, error) {
+// hasSearchFilters returns true if any of the filters only supported by the
+// SearchEntries RPC is set
+func (c *showCommand) hasSearchFilters() bool {
+	return strings.HasSuffix(c.spiffeID, "*") ||
+		c.selectorType != "" ||
+		c.downstream ||
+		c.admin ||
+		c.expiresBefore != 0 ||
+		c.expiresAfter != 0 ||
+		c.x509SVIDTTL != 0 ||
+		c.jwtSVIDTTL != 0
+}
+
+func (c *showCommand) fetchEntries(ctx context.Context, serverClient util.ServerClient) (*entryv1.ListEntriesResponse, error) {
+	client := serverClient.NewEntryClient()
 	listResp := &entryv1.ListEntriesResponse{}
 	// If an Entry ID was specified, look it up directly
 	if c.entryID != "" {
@@ -132,7 +171,7 @@ func (c *showCommand) fetchEntries(ctx context.Context, client entryv1.EntryClie
 		filter.ByParentId = id
 	}
 
-	if c.spiffeID != "" {
+	if c.spiffeID != "" && !strings.HasSuffix(c.spiffeID, "*") {
 		id, err := idStringToProto(c.spiffeID)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing SPIFFE ID %q: %w", c.spiffeID, err)
@@ -176,6 +215,15 @@ func (c *showCommand) fetchEntries(ctx context.Context, client entryv1.EntryClie
 		filter.ByHint = wrapperspb.String(c.hint)
 	}
 
+	if c.hasSearchFilters() {
+		entries, err := c.searchEntries(ctx, serverClient.NewEntryExtensionsClient(), filter)
+		if err != nil {
+			return nil, err
+		}
+		listResp.Entries = entries
+		return listResp, nil
+	}
+
 	pageToken := ""
 
 	for {
@@ -196,6 +244,78 @@ func (c *showCommand) fetchEntries(ctx context.Context, client entryv1.EntryClie
 	return listResp, nil
 }
 
+// searchEntries fetches the entries matching the given filter along with the
+// filters only supported by the SearchEntries RPC
+func (c *showCommand) searchEntries(ctx context.Context, client entryextv1.EntryExtensionsClient, listFilter *entryv1.ListEntriesRequest_Filter) ([]*types.Entry, error) {
+	filter := &entryextv1.SearchEntriesRequest_Filter{
+		BySpiffeId:      listFilter.BySpiffeId,
+		ByParentId:      listFilter.ByParentId,
+		BySelectors:     listFilter.BySelectors,
+		ByFederatesWith: listFilter.ByFederatesWith,
+		ByHint:          listFilter.ByHint,
+		BySelectorType:  c.selectorType,
+		ByExpiresBefore: c.expiresBefore,
+		ByExpiresAfter:  c.expiresAfter,
+		ByX509SvidTtl:   int32(c.x509SVIDTTL),
+		ByJwtSvidTtl:    int32(c.jwtSVIDTTL),
+	}
+
+	if prefix, ok := strings.CutSuffix(c.spiffeID, "*"); ok {
+		id, err := idPrefixStringToProto(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing SPIFFE ID prefix %q: %w", prefix, err)
+		}
+		filter.BySpiffeIdPrefix = id
+	}
+
+	if c.downstream {
+		filter.ByDownstream = wrapperspb.Bool(true)
+	}
+
+	if c.admin {
+		filter.ByAdmin = wrapperspb.Bool(true)
+	}
+
+	var entries []*types.Entry
+	pageToken := ""
+
+	for {
+		resp, err := client.SearchEntries(ctx, &entryextv1.SearchEntriesRequest{
+			PageSize:  listEntriesRequestPageSize,
+			PageToken: pageToken,
+			Filter:    filter,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error fetching entries: %w", err)
+		}
+		entries = append(entries, resp.Entries...)
+		if pageToken = resp.NextPageToken; pageToken == "" {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// idPrefixStringToProto converts a SPIFFE ID prefix (e.g.
+// "spiffe://example.org/ns/prod/") into a *types.SPIFFEID holding the prefix
+// as its path
+func idPrefixStringToProto(prefix string) (*types.SPIFFEID, error) {
+	rest, ok := strings.CutPrefix(prefix, "spiffe://")
+	if !ok {
+		return nil, errors.New("scheme is missing or invalid")
+	}
+	tdName, path, _ := strings.Cut(rest, "/")
+	td, err := spiffeid.TrustDomainFromString(tdName)
+	if err != nil {
+		return nil, err
+	}
+	return &types.SPIFFEID{
+		TrustDomain: td.Name(),
+		Path:        "/" + path,
+	}, nil
+}
+
 // fetchByEntryID uses the configured EntryID to fetch the appropriate registration entry
 func (c *showCommand) fetchByEntryID(ctx context.Context, id string, client entryv1.EntryClient) (*types.Entry, error) {
 	entry, err := client.GetEntry(ctx, &entryv1.GetEntryRequest{Id: id})
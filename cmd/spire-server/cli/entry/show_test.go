@@ -7,9 +7,12 @@ import (
 
 	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	entryextv1 "github.com/spiffe/spire/proto/spire/api/server/entry/v1"
+	"github.com/spiffe/spire/test/golden"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestShowHelp(t *testing.T) {
@@ -46,10 +49,12 @@ func TestShow(t *testing.T) {
 		name string
 		args []string
 
-		expListReq   *entryv1.ListEntriesRequest
-		fakeListResp *entryv1.ListEntriesResponse
-		expGetReq    *entryv1.GetEntryRequest
-		fakeGetResp  *types.Entry
+		expListReq     *entryv1.ListEntriesRequest
+		fakeListResp   *entryv1.ListEntriesResponse
+		expSearchReq   *entryextv1.SearchEntriesRequest
+		fakeSearchResp *entryextv1.SearchEntriesResponse
+		expGetReq      *entryv1.GetEntryRequest
+		fakeGetResp    *types.Entry
 
 		serverErr error
 
@@ -139,6 +144,86 @@ func TestShow(t *testing.T) {
 			args:   []string{"-spiffeID", "invalid-id"},
 			expErr: "Error: error parsing SPIFFE ID \"invalid-id\": scheme is missing or invalid\n",
 		},
+		{
+			name: "List by SPIFFE ID prefix",
+			args: []string{"-spiffeID", "spiffe://example.org/daugh*"},
+			expSearchReq: &entryextv1.SearchEntriesRequest{
+				PageSize: listEntriesRequestPageSize,
+				Filter: &entryextv1.SearchEntriesRequest_Filter{
+					BySpiffeIdPrefix: &types.SPIFFEID{TrustDomain: "example.org", Path: "/daugh"},
+				},
+			},
+			fakeSearchResp: &entryextv1.SearchEntriesResponse{
+				Entries: fakeRespDaughter.Entries,
+			},
+			expOutPretty: fmt.Sprintf("Found 2 entries\n%s%s",
+				getPrettyPrintedEntry(1),
+				getPrettyPrintedEntry(2),
+			),
+			expOutJSON: fmt.Sprintf(`{"entries": [%s, %s],"next_page_token": ""}`, getJSONPrintedEntry(1), getJSONPrintedEntry(2)),
+		},
+		{
+			name: "List by SPIFFE ID prefix matching the whole trust domain",
+			args: []string{"-spiffeID", "spiffe://example.org/*"},
+			expSearchReq: &entryextv1.SearchEntriesRequest{
+				PageSize: listEntriesRequestPageSize,
+				Filter: &entryextv1.SearchEntriesRequest_Filter{
+					BySpiffeIdPrefix: &types.SPIFFEID{TrustDomain: "example.org", Path: "/"},
+				},
+			},
+			fakeSearchResp: &entryextv1.SearchEntriesResponse{
+				Entries: fakeRespMotherDaughter.Entries,
+			},
+			expOutPretty: fmt.Sprintf("Found 1 entry\n%s", getPrettyPrintedEntry(2)),
+			expOutJSON:   fmt.Sprintf(`{"entries": [%s],"next_page_token": ""}`, getJSONPrintedEntry(2)),
+		},
+		{
+			name:   "List by SPIFFE ID prefix using invalid prefix",
+			args:   []string{"-spiffeID", "example.org/*"},
+			expErr: "Error: error parsing SPIFFE ID prefix \"example.org/\": scheme is missing or invalid\n",
+		},
+		{
+			name: "List by extended filters",
+			args: []string{
+				"-parentID", "spiffe://example.org/father",
+				"-selectorType", "foo",
+				"-downstream",
+				"-admin",
+				"-expiresBefore", "1700000000",
+				"-expiresAfter", "1600000000",
+				"-x509SVIDTTL", "3600",
+				"-jwtSVIDTTL", "300",
+			},
+			expSearchReq: &entryextv1.SearchEntriesRequest{
+				PageSize: listEntriesRequestPageSize,
+				Filter: &entryextv1.SearchEntriesRequest_Filter{
+					ByParentId:      &types.SPIFFEID{TrustDomain: "example.org", Path: "/father"},
+					BySelectorType:  "foo",
+					ByDownstream:    wrapperspb.Bool(true),
+					ByAdmin:         wrapperspb.Bool(true),
+					ByExpiresBefore: 1700000000,
+					ByExpiresAfter:  1600000000,
+					ByX509SvidTtl:   3600,
+					ByJwtSvidTtl:    300,
+				},
+			},
+			fakeSearchResp: &entryextv1.SearchEntriesResponse{
+				Entries: fakeRespFatherDaughter.Entries,
+			},
+			expOutPretty: fmt.Sprintf("Found 1 entry\n%s", getPrettyPrintedEntry(1)),
+			expOutJSON:   fmt.Sprintf(`{"entries": [%s],"next_page_token": ""}`, getJSONPrintedEntry(1)),
+		},
+		{
+			name:   "List by entry ID and extended filters",
+			args:   []string{"-entryID", "entry-id", "-admin"},
+			expErr: "Error: the -entryID flag can't be combined with others\n",
+		},
+		{
+			name:      "Search server error",
+			args:      []string{"-selectorType", "foo"},
+			serverErr: status.Error(codes.Internal, "internal server error"),
+			expErr:    "Error: error fetching entries: rpc error: code = Internal desc = internal server error\n",
+		},
 		{
 			name: "List by selectors: default matcher",
 			args: []string{"-selector", "foo:bar", "-selector", "bar:baz"},
@@ -368,6 +453,8 @@ func TestShow(t *testing.T) {
 				test.server.err = tt.serverErr
 				test.server.expListEntriesReq = tt.expListReq
 				test.server.listEntriesResp = tt.fakeListResp
+				test.server.expSearchEntriesReq = tt.expSearchReq
+				test.server.searchEntriesResp = tt.fakeSearchResp
 				test.server.expGetEntryReq = tt.expGetReq
 				test.server.getEntryResp = tt.fakeGetResp
 				args := tt.args
@@ -386,6 +473,43 @@ func TestShow(t *testing.T) {
 	}
 }
 
+func TestShowOutputFormats(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		args []string
+	}{
+		{
+			name: "show_yaml",
+			args: []string{"-output", "yaml"},
+		},
+		{
+			name: "show_table",
+			args: []string{"-output", "table"},
+		},
+		{
+			name: "show_table_columns",
+			args: []string{"-output", "table", "-columns", "id,spiffe_id,parent_id,selectors"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupTest(t, newShowCommand)
+			test.server.expListEntriesReq = &entryv1.ListEntriesRequest{
+				PageSize: listEntriesRequestPageSize,
+				Filter:   &entryv1.ListEntriesRequest_Filter{},
+			}
+			test.server.listEntriesResp = &entryv1.ListEntriesResponse{
+				Entries: getEntries(4),
+			}
+
+			rc := test.client.Run(test.args(tt.args...))
+
+			require.Equal(t, 0, rc)
+			require.Empty(t, test.stderr.String())
+			golden.RequireEqual(t, tt.name, test.stdout.Bytes())
+		})
+	}
+}
+
 // registrationEntries returns `count` registration entry records. At most 4.
 func getEntries(count int) []*types.Entry {
 	selectors := []*types.Selector{
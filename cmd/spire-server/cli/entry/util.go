@@ -100,8 +100,6 @@ func parseFile(path string) ([]*types.Entry, error) {
 }
 
 func parseEntryJSON(in io.Reader, path string) ([]*types.Entry, error) {
-	entries := &common.RegistrationEntries{}
-
 	r := in
 	if path != "-" {
 		f, err := os.Open(path)
@@ -117,6 +115,12 @@ func parseEntryJSON(in io.Reader, path string) ([]*types.Entry, error) {
 		return nil, err
 	}
 
+	return entriesFromJSON(dat)
+}
+
+// entriesFromJSON parses JSON represented RegistrationEntries
+func entriesFromJSON(dat []byte) ([]*types.Entry, error) {
+	entries := &common.RegistrationEntries{}
 	if err := json.Unmarshal(dat, &entries); err != nil {
 		return nil, err
 	}
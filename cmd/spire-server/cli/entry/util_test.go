@@ -11,6 +11,7 @@ import (
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/cmd/spire-server/cli/common"
 	common_cli "github.com/spiffe/spire/pkg/common/cli"
+	entryextv1 "github.com/spiffe/spire/proto/spire/api/server/entry/v1"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/spiffe/spire/test/util"
 	"github.com/stretchr/testify/assert"
@@ -160,12 +161,14 @@ func (e *entryTest) args(extra ...string) []string {
 
 type fakeEntryServer struct {
 	*entryv1.UnimplementedEntryServer
+	entryextv1.UnimplementedEntryExtensionsServer
 
 	t   *testing.T
 	err error
 
 	expGetEntryReq         *entryv1.GetEntryRequest
 	expListEntriesReq      *entryv1.ListEntriesRequest
+	expSearchEntriesReq    *entryextv1.SearchEntriesRequest
 	expBatchDeleteEntryReq *entryv1.BatchDeleteEntryRequest
 	expBatchCreateEntryReq *entryv1.BatchCreateEntryRequest
 	expBatchUpdateEntryReq *entryv1.BatchUpdateEntryRequest
@@ -173,6 +176,7 @@ type fakeEntryServer struct {
 	getEntryResp         *types.Entry
 	countEntriesResp     *entryv1.CountEntriesResponse
 	listEntriesResp      *entryv1.ListEntriesResponse
+	searchEntriesResp    *entryextv1.SearchEntriesResponse
 	batchDeleteEntryResp *entryv1.BatchDeleteEntryResponse
 	batchCreateEntryResp *entryv1.BatchCreateEntryResponse
 	batchUpdateEntryResp *entryv1.BatchUpdateEntryResponse
@@ -193,6 +197,14 @@ func (f fakeEntryServer) ListEntries(_ context.Context, req *entryv1.ListEntries
 	return f.listEntriesResp, nil
 }
 
+func (f fakeEntryServer) SearchEntries(_ context.Context, req *entryextv1.SearchEntriesRequest) (*entryextv1.SearchEntriesResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	spiretest.AssertProtoEqual(f.t, f.expSearchEntriesReq, req)
+	return f.searchEntriesResp, nil
+}
+
 func (f fakeEntryServer) GetEntry(_ context.Context, req *entryv1.GetEntryRequest) (*types.Entry, error) {
 	if f.err != nil {
 		return nil, f.err
@@ -239,6 +251,7 @@ func setupTest(t *testing.T, newClient func(*common_cli.Env) cli.Command) *entry
 	server := &fakeEntryServer{t: t}
 	addr := spiretest.StartGRPCServer(t, func(s *grpc.Server) {
 		entryv1.RegisterEntryServer(s, server)
+		entryextv1.RegisterEntryExtensionsServer(s, server)
 	})
 
 	test := &entryTest{
@@ -26,7 +26,7 @@ const (
   -node
     	If set, this entry will be applied to matching nodes rather than workloads
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -parentID string
     	The SPIFFE ID of this record's parent
   -selector value
@@ -41,14 +41,24 @@ const (
     	The lifetime, in seconds, for x509-SVIDs issued based on this registration entry. Overrides ttl flag
 `
 	showUsage = `Usage of entry show:
+  -admin
+    	If set, only shows the records granting access to the SPIRE Server's management APIs
+  -columns value
+    	Comma-separated list of the columns to print with the table output format; default: all columns.
   -downstream
-    	A boolean value that, when set, indicates that the entry describes a downstream SPIRE server
+    	If set, only shows the records describing a downstream SPIRE server
   -entryID string
     	The Entry ID of the records to show
+  -expiresAfter int
+    	Only shows the records expiring after the given time, from epoch in seconds
+  -expiresBefore int
+    	Only shows the records expiring before the given time, from epoch in seconds
   -federatesWith value
     	SPIFFE ID of a trust domain an entry is federate with. Can be used more than once
   -hint string
     	The Hint of the records to show (optional)
+  -jwtSVIDTTL int
+    	Only shows the records with the given lifetime, in seconds, for JWT-SVIDs
   -matchFederatesWithOn string
     	The match mode used when filtering by federates with. Options: exact, any, superset and subset (default "superset")
   -matchSelectorsOn string
@@ -56,13 +66,17 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml, table); default: pretty.
   -parentID string
     	The Parent ID of the records to show
   -selector value
     	A colon-delimited type:value selector. Can be used more than once
+  -selectorType string
+    	Only shows the records with a selector of the given type
   -spiffeID string
-    	The SPIFFE ID of the records to show
+    	The SPIFFE ID of the records to show. A trailing '*' shows the records with SPIFFE IDs starting with the given prefix (e.g. spiffe://example.org/ns/prod/*)
+  -x509SVIDTTL int
+    	Only shows the records with the given lifetime, in seconds, for x509-SVIDs
 `
 	updateUsage = `Usage of entry update:
   -admin
@@ -86,7 +100,7 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
   -parentID string
     	The SPIFFE ID of this record's parent
   -selector value
@@ -106,12 +120,26 @@ const (
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
 `
 	countUsage = `Usage of entry count:
   -namedPipeName string
     	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
   -output value
-    	Desired output format (pretty, json); default: pretty.
+    	Desired output format (pretty, json, yaml); default: pretty.
+`
+	applyUsage = `Usage of entry apply:
+  -dryRun
+    	Indicates that the command will not perform any action, but will print the plan to converge the entries
+  -f value
+    	Path to a JSON or YAML file with registration entries, or to a directory containing them. Can be used more than once
+  -hint string
+    	The hint of the entries managed by the documents. Entries with this hint that are not in the documents are deleted
+  -namedPipeName string
+    	Pipe name of the SPIRE Server API named pipe (default "\\spire-server\\private\\api")
+  -output value
+    	Desired output format (pretty, json, yaml); default: pretty.
+  -parentID string
+    	The Parent ID of the entries managed by the documents. Entries with this Parent ID that are not in the documents are deleted
 `
 )
@@ -182,3 +182,35 @@ Endpoint SPIFFE ID        : spiffe://baz.test/id
 		}
 	}
 }
+
+func TestListTable(t *testing.T) {
+	test := setupTest(t, newListCommand)
+	test.server.expectListReq = &trustdomainv1.ListFederationRelationshipsRequest{}
+	test.server.listResp = &trustdomainv1.ListFederationRelationshipsResponse{
+		FederationRelationships: []*types.FederationRelationship{
+			{
+				TrustDomain:           "foh.test",
+				BundleEndpointUrl:     "https://foo.test/endpoint",
+				BundleEndpointProfile: &types.FederationRelationship_HttpsWeb{},
+			},
+			{
+				TrustDomain:       "bar.test",
+				BundleEndpointUrl: "https://bar.test/endpoint",
+				BundleEndpointProfile: &types.FederationRelationship_HttpsSpiffe{
+					HttpsSpiffe: &types.HTTPSSPIFFEProfile{
+						EndpointSpiffeId: "spiffe://bar.test/id",
+					},
+				},
+			},
+		},
+	}
+
+	rc := test.client.Run(test.args("-output", "table", "-columns", "trust_domain,bundle_endpoint_url,https_spiffe.endpoint_spiffe_id"))
+
+	require.Equal(t, 0, rc)
+	require.Empty(t, test.stderr.String())
+	require.Equal(t, `TRUST_DOMAIN  BUNDLE_ENDPOINT_URL        HTTPS_SPIFFE.ENDPOINT_SPIFFE_ID
+foh.test      https://foo.test/endpoint
+bar.test      https://bar.test/endpoint  spiffe://bar.test/id
+`, test.stdout.String())
+}
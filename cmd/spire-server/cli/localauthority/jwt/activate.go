@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/cli/authoritycommon"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+)
+
+// NewActivateCommand creates a new "localauthority jwt activate" subcommand.
+func NewActivateCommand() cli.Command {
+	return newActivateCommand(commoncli.DefaultEnv)
+}
+
+func newActivateCommand(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &activateCommand{env: env})
+}
+
+type activateCommand struct {
+	authorityID string
+	printer     cliprinter.Printer
+	env         *commoncli.Env
+}
+
+func (c *activateCommand) Name() string {
+	return "localauthority jwt activate"
+}
+
+func (*activateCommand) Synopsis() string {
+	return "Activates a prepared JWT authority for use, which will cause it to be used for all JWT signing operations serviced by this server going forward"
+}
+
+func (c *activateCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.authorityID, "authorityID", "", "The authority ID of the JWT authority to activate")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintActivate)
+}
+
+func (c *activateCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
+	if c.authorityID == "" {
+		return errors.New("an authority ID is required")
+	}
+
+	client := serverClient.NewLocalAuthorityClient()
+	resp, err := client.ActivateJWTAuthority(ctx, &localauthorityv1.ActivateJWTAuthorityRequest{
+		AuthorityId: c.authorityID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not activate JWT authority: %w", err)
+	}
+
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintActivate(env *commoncli.Env, results ...interface{}) error {
+	r, ok := results[0].(*localauthorityv1.ActivateJWTAuthorityResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	return authoritycommon.PrettyPrintAuthorityState(env, "Activated JWT authority", r.ActivatedAuthority)
+}
@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/cli/authoritycommon"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+)
+
+// NewRevokeCommand creates a new "localauthority jwt revoke" subcommand.
+func NewRevokeCommand() cli.Command {
+	return newRevokeCommand(commoncli.DefaultEnv)
+}
+
+func newRevokeCommand(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &revokeCommand{env: env})
+}
+
+type revokeCommand struct {
+	authorityID string
+	printer     cliprinter.Printer
+	env         *commoncli.Env
+}
+
+func (c *revokeCommand) Name() string {
+	return "localauthority jwt revoke"
+}
+
+func (*revokeCommand) Synopsis() string {
+	return "Revokes the previously active JWT authority by removing it from the bundle and propagating this update throughout the cluster"
+}
+
+func (c *revokeCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.authorityID, "authorityID", "", "The authority ID of the JWT authority to revoke")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintRevoke)
+}
+
+func (c *revokeCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
+	if c.authorityID == "" {
+		return errors.New("an authority ID is required")
+	}
+
+	client := serverClient.NewLocalAuthorityClient()
+	resp, err := client.RevokeJWTAuthority(ctx, &localauthorityv1.RevokeJWTAuthorityRequest{
+		AuthorityId: c.authorityID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not revoke JWT authority: %w", err)
+	}
+
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintRevoke(env *commoncli.Env, results ...interface{}) error {
+	r, ok := results[0].(*localauthorityv1.RevokeJWTAuthorityResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	return authoritycommon.PrettyPrintAuthorityState(env, "Revoked JWT authority", r.RevokedAuthority)
+}
@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/cli/authoritycommon"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+)
+
+// NewTaintCommand creates a new "localauthority jwt taint" subcommand.
+func NewTaintCommand() cli.Command {
+	return newTaintCommand(commoncli.DefaultEnv)
+}
+
+func newTaintCommand(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &taintCommand{env: env})
+}
+
+type taintCommand struct {
+	authorityID string
+	printer     cliprinter.Printer
+	env         *commoncli.Env
+}
+
+func (c *taintCommand) Name() string {
+	return "localauthority jwt taint"
+}
+
+func (*taintCommand) Synopsis() string {
+	return "Marks the previously active JWT authority as being tainted"
+}
+
+func (c *taintCommand) AppendFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.authorityID, "authorityID", "", "The authority ID of the JWT authority to taint")
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintTaint)
+}
+
+func (c *taintCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
+	if c.authorityID == "" {
+		return errors.New("an authority ID is required")
+	}
+
+	client := serverClient.NewLocalAuthorityClient()
+	resp, err := client.TaintJWTAuthority(ctx, &localauthorityv1.TaintJWTAuthorityRequest{
+		AuthorityId: c.authorityID,
+	})
+	if err != nil {
+		return fmt.Errorf("could not taint JWT authority: %w", err)
+	}
+
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintTaint(env *commoncli.Env, results ...interface{}) error {
+	r, ok := results[0].(*localauthorityv1.TaintJWTAuthorityResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	return authoritycommon.PrettyPrintAuthorityState(env, "Tainted JWT authority", r.TaintedAuthority)
+}
@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spiffe/spire/cmd/spire-server/cli/common"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTaintHelp(t *testing.T) {
+	test := setupTest(t, newTaintCommand)
+	test.client.Help()
+
+	require.Equal(t, `Usage of localauthority jwt taint:
+  -authorityID string
+    	The authority ID of the JWT authority to taint`+common.AddrOutputUsage, test.stderr.String())
+}
+
+func TestTaintSynopsis(t *testing.T) {
+	test := setupTest(t, newTaintCommand)
+	require.Equal(t, "Marks the previously active JWT authority as being tainted", test.client.Synopsis())
+}
+
+func TestTaint(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		args []string
+
+		authority *localauthorityv1.AuthorityState
+		serverErr error
+
+		expectOutPretty string
+		expectOutJSON   string
+		expectErr       string
+	}{
+		{
+			name:      "success",
+			args:      []string{"-authorityID", "authority-id"},
+			authority: &localauthorityv1.AuthorityState{AuthorityId: "authority-id", ExpiresAt: 1001},
+			expectOutPretty: `Tainted JWT authority:
+  Authority ID: authority-id
+  Expires at: 1970-01-01 00:16:41 +0000 UTC
+`,
+			expectOutJSON: `{"tainted_authority":{"authority_id":"authority-id","expires_at":"1001"}}`,
+		},
+		{
+			name:      "no authority ID",
+			expectErr: "Error: an authority ID is required\n",
+		},
+		{
+			name:      "server error",
+			args:      []string{"-authorityID", "authority-id"},
+			serverErr: status.Error(codes.InvalidArgument, "invalid authority ID"),
+			expectErr: "Error: could not taint JWT authority: rpc error: code = InvalidArgument desc = invalid authority ID\n",
+		},
+	} {
+		for _, format := range availableFormats {
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, newTaintCommand)
+				test.server.old = tt.authority
+				test.server.expectAuthorityID = "authority-id"
+				test.server.err = tt.serverErr
+
+				args := append(tt.args, "-output", format)
+				rc := test.client.Run(test.args(args...))
+				if tt.expectErr != "" {
+					require.Equal(t, 1, rc)
+					require.Equal(t, tt.expectErr, test.stderr.String())
+					return
+				}
+
+				require.Equal(t, 0, rc)
+				require.Empty(t, test.stderr.String())
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expectOutPretty, tt.expectOutJSON)
+			})
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package x509
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/cli/common"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+var availableFormats = []string{"pretty", "json"}
+
+type cmdTest struct {
+	stdin  *bytes.Buffer
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+
+	addr   string
+	server *fakeServer
+
+	client cli.Command
+}
+
+func (c *cmdTest) afterTest(t *testing.T) {
+	t.Logf("TEST:%s", t.Name())
+	t.Logf("STDOUT:\n%s", c.stdout.String())
+	t.Logf("STDIN:\n%s", c.stdin.String())
+	t.Logf("STDERR:\n%s", c.stderr.String())
+}
+
+func (c *cmdTest) args(extra ...string) []string {
+	return append([]string{common.AddrArg, c.addr}, extra...)
+}
+
+type fakeServer struct {
+	localauthorityv1.UnimplementedLocalAuthorityServer
+
+	t   *testing.T
+	err error
+
+	expectAuthorityID string
+
+	active, prepared, old *localauthorityv1.AuthorityState
+}
+
+func (f *fakeServer) GetX509AuthorityState(context.Context, *localauthorityv1.GetX509AuthorityStateRequest) (*localauthorityv1.GetX509AuthorityStateResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &localauthorityv1.GetX509AuthorityStateResponse{
+		Active:   f.active,
+		Prepared: f.prepared,
+		Old:      f.old,
+	}, nil
+}
+
+func (f *fakeServer) PrepareX509Authority(context.Context, *localauthorityv1.PrepareX509AuthorityRequest) (*localauthorityv1.PrepareX509AuthorityResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	return &localauthorityv1.PrepareX509AuthorityResponse{
+		PreparedAuthority: f.prepared,
+	}, nil
+}
+
+func (f *fakeServer) ActivateX509Authority(_ context.Context, req *localauthorityv1.ActivateX509AuthorityRequest) (*localauthorityv1.ActivateX509AuthorityResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	require.Equal(f.t, f.expectAuthorityID, req.AuthorityId)
+	return &localauthorityv1.ActivateX509AuthorityResponse{
+		ActivatedAuthority: f.active,
+	}, nil
+}
+
+func (f *fakeServer) TaintX509Authority(_ context.Context, req *localauthorityv1.TaintX509AuthorityRequest) (*localauthorityv1.TaintX509AuthorityResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	require.Equal(f.t, f.expectAuthorityID, req.AuthorityId)
+	return &localauthorityv1.TaintX509AuthorityResponse{
+		TaintedAuthority: f.old,
+	}, nil
+}
+
+func (f *fakeServer) RevokeX509Authority(_ context.Context, req *localauthorityv1.RevokeX509AuthorityRequest) (*localauthorityv1.RevokeX509AuthorityResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	require.Equal(f.t, f.expectAuthorityID, req.AuthorityId)
+	return &localauthorityv1.RevokeX509AuthorityResponse{
+		RevokedAuthority: f.old,
+	}, nil
+}
+
+func setupTest(t *testing.T, newClient func(*commoncli.Env) cli.Command) *cmdTest {
+	stdin := new(bytes.Buffer)
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+
+	client := newClient(&commoncli.Env{
+		Stdin:  stdin,
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+
+	server := &fakeServer{t: t}
+	addr := spiretest.StartGRPCServer(t, func(s *grpc.Server) {
+		localauthorityv1.RegisterLocalAuthorityServer(s, server)
+	})
+
+	test := &cmdTest{
+		addr:   common.GetAddr(addr),
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		server: server,
+		client: client,
+	}
+
+	t.Cleanup(func() {
+		test.afterTest(t)
+	})
+
+	return test
+}
+
+func requireOutputBasedOnFormat(t *testing.T, format, stdoutString string, expectedStdoutPretty, expectedStdoutJSON string) {
+	switch format {
+	case "pretty":
+		require.Contains(t, stdoutString, expectedStdoutPretty)
+	case "json":
+		if expectedStdoutJSON != "" {
+			require.JSONEq(t, expectedStdoutJSON, stdoutString)
+		} else {
+			require.Empty(t, stdoutString)
+		}
+	}
+}
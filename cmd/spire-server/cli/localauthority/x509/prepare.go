@@ -0,0 +1,59 @@
+package x509
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/cli/authoritycommon"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+)
+
+// NewPrepareCommand creates a new "localauthority x509 prepare" subcommand.
+func NewPrepareCommand() cli.Command {
+	return newPrepareCommand(commoncli.DefaultEnv)
+}
+
+func newPrepareCommand(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &prepareCommand{env: env})
+}
+
+type prepareCommand struct {
+	printer cliprinter.Printer
+	env     *commoncli.Env
+}
+
+func (c *prepareCommand) Name() string {
+	return "localauthority x509 prepare"
+}
+
+func (*prepareCommand) Synopsis() string {
+	return "Prepares a new X.509 authority for use by generating a new key and injecting it into the bundle"
+}
+
+func (c *prepareCommand) AppendFlags(fs *flag.FlagSet) {
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintPrepare)
+}
+
+func (c *prepareCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
+	client := serverClient.NewLocalAuthorityClient()
+	resp, err := client.PrepareX509Authority(ctx, &localauthorityv1.PrepareX509AuthorityRequest{})
+	if err != nil {
+		return fmt.Errorf("could not prepare X.509 authority: %w", err)
+	}
+
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintPrepare(env *commoncli.Env, results ...interface{}) error {
+	r, ok := results[0].(*localauthorityv1.PrepareX509AuthorityResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	return authoritycommon.PrettyPrintAuthorityState(env, "Prepared X.509 authority", r.PreparedAuthority)
+}
@@ -0,0 +1,71 @@
+package x509
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spiffe/spire/cmd/spire-server/cli/common"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPrepareHelp(t *testing.T) {
+	test := setupTest(t, newPrepareCommand)
+	test.client.Help()
+
+	require.Equal(t, "Usage of localauthority x509 prepare:"+common.AddrOutputUsage, test.stderr.String())
+}
+
+func TestPrepareSynopsis(t *testing.T) {
+	test := setupTest(t, newPrepareCommand)
+	require.Equal(t, "Prepares a new X.509 authority for use by generating a new key and injecting it into the bundle", test.client.Synopsis())
+}
+
+func TestPrepare(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+
+		prepared  *localauthorityv1.AuthorityState
+		serverErr error
+
+		expectOutPretty string
+		expectOutJSON   string
+		expectErr       string
+	}{
+		{
+			name:     "success",
+			prepared: &localauthorityv1.AuthorityState{AuthorityId: "prepared-id", ExpiresAt: 1001},
+			expectOutPretty: `Prepared X.509 authority:
+  Authority ID: prepared-id
+  Expires at: 1970-01-01 00:16:41 +0000 UTC
+`,
+			expectOutJSON: `{"prepared_authority":{"authority_id":"prepared-id","expires_at":"1001"}}`,
+		},
+		{
+			name:      "server error",
+			serverErr: status.Error(codes.Unavailable, "server is not ready to prepare an X.509 authority"),
+			expectErr: "Error: could not prepare X.509 authority: rpc error: code = Unavailable desc = server is not ready to prepare an X.509 authority\n",
+		},
+	} {
+		for _, format := range availableFormats {
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, newPrepareCommand)
+				test.server.prepared = tt.prepared
+				test.server.err = tt.serverErr
+
+				rc := test.client.Run(test.args("-output", format))
+				if tt.expectErr != "" {
+					require.Equal(t, 1, rc)
+					require.Equal(t, tt.expectErr, test.stderr.String())
+					return
+				}
+
+				require.Equal(t, 0, rc)
+				require.Empty(t, test.stderr.String())
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expectOutPretty, tt.expectOutJSON)
+			})
+		}
+	}
+}
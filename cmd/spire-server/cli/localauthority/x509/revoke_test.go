@@ -0,0 +1,82 @@
+package x509
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spiffe/spire/cmd/spire-server/cli/common"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRevokeHelp(t *testing.T) {
+	test := setupTest(t, newRevokeCommand)
+	test.client.Help()
+
+	require.Equal(t, `Usage of localauthority x509 revoke:
+  -authorityID string
+    	The authority ID of the X.509 authority to revoke`+common.AddrOutputUsage, test.stderr.String())
+}
+
+func TestRevokeSynopsis(t *testing.T) {
+	test := setupTest(t, newRevokeCommand)
+	require.Equal(t, "Revokes the previously active X.509 authority by removing it from the bundle and propagating this update throughout the cluster", test.client.Synopsis())
+}
+
+func TestRevoke(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		args []string
+
+		authority *localauthorityv1.AuthorityState
+		serverErr error
+
+		expectOutPretty string
+		expectOutJSON   string
+		expectErr       string
+	}{
+		{
+			name:      "success",
+			args:      []string{"-authorityID", "authority-id"},
+			authority: &localauthorityv1.AuthorityState{AuthorityId: "authority-id", ExpiresAt: 1001},
+			expectOutPretty: `Revoked X.509 authority:
+  Authority ID: authority-id
+  Expires at: 1970-01-01 00:16:41 +0000 UTC
+`,
+			expectOutJSON: `{"revoked_authority":{"authority_id":"authority-id","expires_at":"1001"}}`,
+		},
+		{
+			name:      "no authority ID",
+			expectErr: "Error: an authority ID is required\n",
+		},
+		{
+			name:      "server error",
+			args:      []string{"-authorityID", "authority-id"},
+			serverErr: status.Error(codes.InvalidArgument, "invalid authority ID"),
+			expectErr: "Error: could not revoke X.509 authority: rpc error: code = InvalidArgument desc = invalid authority ID\n",
+		},
+	} {
+		for _, format := range availableFormats {
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, newRevokeCommand)
+				test.server.old = tt.authority
+				test.server.expectAuthorityID = "authority-id"
+				test.server.err = tt.serverErr
+
+				args := append(tt.args, "-output", format)
+				rc := test.client.Run(test.args(args...))
+				if tt.expectErr != "" {
+					require.Equal(t, 1, rc)
+					require.Equal(t, tt.expectErr, test.stderr.String())
+					return
+				}
+
+				require.Equal(t, 0, rc)
+				require.Empty(t, test.stderr.String())
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expectOutPretty, tt.expectOutJSON)
+			})
+		}
+	}
+}
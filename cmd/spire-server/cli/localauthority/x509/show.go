@@ -0,0 +1,65 @@
+package x509
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/mitchellh/cli"
+	"github.com/spiffe/spire/cmd/spire-server/cli/authoritycommon"
+	"github.com/spiffe/spire/cmd/spire-server/util"
+	commoncli "github.com/spiffe/spire/pkg/common/cli"
+	"github.com/spiffe/spire/pkg/common/cliprinter"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+)
+
+// NewShowCommand creates a new "localauthority x509 show" subcommand.
+func NewShowCommand() cli.Command {
+	return newShowCommand(commoncli.DefaultEnv)
+}
+
+func newShowCommand(env *commoncli.Env) cli.Command {
+	return util.AdaptCommand(env, &showCommand{env: env})
+}
+
+type showCommand struct {
+	printer cliprinter.Printer
+	env     *commoncli.Env
+}
+
+func (c *showCommand) Name() string {
+	return "localauthority x509 show"
+}
+
+func (*showCommand) Synopsis() string {
+	return "Shows the local X.509 authorities"
+}
+
+func (c *showCommand) AppendFlags(fs *flag.FlagSet) {
+	cliprinter.AppendFlagWithCustomPretty(&c.printer, fs, c.env, prettyPrintShow)
+}
+
+func (c *showCommand) Run(ctx context.Context, _ *commoncli.Env, serverClient util.ServerClient) error {
+	client := serverClient.NewLocalAuthorityClient()
+	resp, err := client.GetX509AuthorityState(ctx, &localauthorityv1.GetX509AuthorityStateRequest{})
+	if err != nil {
+		return fmt.Errorf("could not get X.509 authorities: %w", err)
+	}
+
+	return c.printer.PrintProto(resp)
+}
+
+func prettyPrintShow(env *commoncli.Env, results ...interface{}) error {
+	r, ok := results[0].(*localauthorityv1.GetX509AuthorityStateResponse)
+	if !ok {
+		return cliprinter.ErrInternalCustomPrettyFunc
+	}
+
+	if err := authoritycommon.PrettyPrintAuthorityState(env, "Active X.509 authority", r.Active); err != nil {
+		return err
+	}
+	if err := authoritycommon.PrettyPrintAuthorityState(env, "Prepared X.509 authority", r.Prepared); err != nil {
+		return err
+	}
+	return authoritycommon.PrettyPrintAuthorityState(env, "Old X.509 authority", r.Old)
+}
@@ -0,0 +1,96 @@
+package x509
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/spiffe/spire/cmd/spire-server/cli/common"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestShowHelp(t *testing.T) {
+	test := setupTest(t, newShowCommand)
+	test.client.Help()
+
+	require.Equal(t, "Usage of localauthority x509 show:"+common.AddrOutputUsage, test.stderr.String())
+}
+
+func TestShowSynopsis(t *testing.T) {
+	test := setupTest(t, newShowCommand)
+	require.Equal(t, "Shows the local X.509 authorities", test.client.Synopsis())
+}
+
+func TestShow(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+
+		active    *localauthorityv1.AuthorityState
+		prepared  *localauthorityv1.AuthorityState
+		old       *localauthorityv1.AuthorityState
+		serverErr error
+
+		expectOutPretty string
+		expectOutJSON   string
+		expectErr       string
+	}{
+		{
+			name:     "success",
+			active:   &localauthorityv1.AuthorityState{AuthorityId: "active-id", ExpiresAt: 1001},
+			prepared: &localauthorityv1.AuthorityState{AuthorityId: "prepared-id", ExpiresAt: 1002},
+			old:      &localauthorityv1.AuthorityState{AuthorityId: "old-id", ExpiresAt: 1003},
+			expectOutPretty: `Active X.509 authority:
+  Authority ID: active-id
+  Expires at: 1970-01-01 00:16:41 +0000 UTC
+Prepared X.509 authority:
+  Authority ID: prepared-id
+  Expires at: 1970-01-01 00:16:42 +0000 UTC
+Old X.509 authority:
+  Authority ID: old-id
+  Expires at: 1970-01-01 00:16:43 +0000 UTC
+`,
+			expectOutJSON: `{"active":{"authority_id":"active-id","expires_at":"1001"},"prepared":{"authority_id":"prepared-id","expires_at":"1002"},"old":{"authority_id":"old-id","expires_at":"1003"}}`,
+		},
+		{
+			name:   "only active authority",
+			active: &localauthorityv1.AuthorityState{AuthorityId: "active-id", ExpiresAt: 1001},
+			expectOutPretty: `Active X.509 authority:
+  Authority ID: active-id
+  Expires at: 1970-01-01 00:16:41 +0000 UTC
+Prepared X.509 authority:
+  No authority found
+Old X.509 authority:
+  No authority found
+`,
+			expectOutJSON: `{"active":{"authority_id":"active-id","expires_at":"1001"}}`,
+		},
+		{
+			name:      "server error",
+			serverErr: status.Error(codes.Internal, "oh no"),
+			expectErr: "Error: could not get X.509 authorities: rpc error: code = Internal desc = oh no\n",
+		},
+	} {
+		for _, format := range availableFormats {
+			t.Run(fmt.Sprintf("%s using %s format", tt.name, format), func(t *testing.T) {
+				test := setupTest(t, newShowCommand)
+				test.server.active = tt.active
+				test.server.prepared = tt.prepared
+				test.server.old = tt.old
+				test.server.err = tt.serverErr
+
+				rc := test.client.Run(test.args("-output", format))
+				if tt.expectErr != "" {
+					require.Equal(t, 1, rc)
+					require.Equal(t, tt.expectErr, test.stderr.String())
+					return
+				}
+
+				require.Equal(t, 0, rc)
+				require.Empty(t, test.stderr.String())
+				requireOutputBasedOnFormat(t, format, test.stdout.String(), tt.expectOutPretty, tt.expectOutJSON)
+			})
+		}
+	}
+}
@@ -97,8 +97,10 @@ type serverConfig struct {
 }
 
 type experimentalConfig struct {
-	AuthOpaPolicyEngine *authpolicy.OpaEngineConfig `hcl:"auth_opa_policy_engine"`
-	CacheReloadInterval string                      `hcl:"cache_reload_interval"`
+	AuthOpaPolicyEngine  *authpolicy.OpaEngineConfig `hcl:"auth_opa_policy_engine"`
+	CacheReloadInterval  string                      `hcl:"cache_reload_interval"`
+	EventsBasedCache     bool                        `hcl:"events_based_cache"`
+	PruneEventsOlderThan string                      `hcl:"prune_events_older_than"`
 
 	Flags fflag.RawConfig `hcl:"feature_flags"`
 
@@ -156,7 +158,14 @@ type httpsWebProfileConfig struct{}
 
 type rateLimitConfig struct {
 	Attestation        *bool                  `hcl:"attestation"`
+	AttestationLimit   int                    `hcl:"attestation_limit"`
+	AttestationBurst   int                    `hcl:"attestation_burst"`
 	Signing            *bool                  `hcl:"signing"`
+	SigningLimit       int                    `hcl:"signing_limit"`
+	SigningBurst       int                    `hcl:"signing_burst"`
+	PushJWTKeyLimit    int                    `hcl:"push_jwt_key_limit"`
+	PushJWTKeyBurst    int                    `hcl:"push_jwt_key_burst"`
+	PerCallerID        bool                   `hcl:"per_caller_id"`
 	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
 }
 
@@ -404,6 +413,13 @@ func NewServerConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool
 		c.Server.RateLimit.Signing = &defaultRateLimit
 	}
 	sc.RateLimit.Signing = *c.Server.RateLimit.Signing
+	sc.RateLimit.AttestationLimit = c.Server.RateLimit.AttestationLimit
+	sc.RateLimit.AttestationBurst = c.Server.RateLimit.AttestationBurst
+	sc.RateLimit.SigningLimit = c.Server.RateLimit.SigningLimit
+	sc.RateLimit.SigningBurst = c.Server.RateLimit.SigningBurst
+	sc.RateLimit.PushJWTKeyLimit = c.Server.RateLimit.PushJWTKeyLimit
+	sc.RateLimit.PushJWTKeyBurst = c.Server.RateLimit.PushJWTKeyBurst
+	sc.RateLimit.PerCallerID = c.Server.RateLimit.PerCallerID
 
 	if c.Server.Federation != nil {
 		if c.Server.Federation.BundleEndpoint != nil {
@@ -619,6 +635,15 @@ func NewServerConfig(c *Config, logOptions []log.Option, allowUnknownConfig bool
 		sc.CacheReloadInterval = interval
 	}
 
+	sc.EventsBasedCache = c.Server.Experimental.EventsBasedCache
+	if c.Server.Experimental.PruneEventsOlderThan != "" {
+		interval, err := time.ParseDuration(c.Server.Experimental.PruneEventsOlderThan)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse prune events older than interval: %w", err)
+		}
+		sc.PruneEventsOlderThan = interval
+	}
+
 	sc.AuthOpaPolicyEngineConfig = c.Server.Experimental.AuthOpaPolicyEngine
 
 	for _, f := range c.Server.Experimental.Flags {
@@ -714,9 +739,32 @@ func validateConfig(c *Config) error {
 		}
 	}
 
+	if err := c.Server.RateLimit.validate(); err != nil {
+		return err
+	}
+
 	return c.validateOS()
 }
 
+func (c *rateLimitConfig) validate() error {
+	for _, v := range []struct {
+		name  string
+		value int
+	}{
+		{name: "attestation_limit", value: c.AttestationLimit},
+		{name: "attestation_burst", value: c.AttestationBurst},
+		{name: "signing_limit", value: c.SigningLimit},
+		{name: "signing_burst", value: c.SigningBurst},
+		{name: "push_jwt_key_limit", value: c.PushJWTKeyLimit},
+		{name: "push_jwt_key_burst", value: c.PushJWTKeyBurst},
+	} {
+		if v.value < 0 {
+			return fmt.Errorf("ratelimit.%s must not be negative", v.name)
+		}
+	}
+	return nil
+}
+
 func checkForUnknownConfig(c *Config, l logrus.FieldLogger) (err error) {
 	detectedUnknown := func(section string, keyPositions map[string][]token.Pos) {
 		var keys []string
@@ -927,6 +927,27 @@ func TestNewServerConfig(t *testing.T) {
 				require.True(t, c.RateLimit.Signing)
 			},
 		},
+		{
+			msg: "rate limits and bursts are configurable",
+			input: func(c *Config) {
+				c.Server.RateLimit.AttestationLimit = 10
+				c.Server.RateLimit.AttestationBurst = 20
+				c.Server.RateLimit.SigningLimit = 1000
+				c.Server.RateLimit.SigningBurst = 2000
+				c.Server.RateLimit.PushJWTKeyLimit = 50
+				c.Server.RateLimit.PushJWTKeyBurst = 100
+				c.Server.RateLimit.PerCallerID = true
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Equal(t, 10, c.RateLimit.AttestationLimit)
+				require.Equal(t, 20, c.RateLimit.AttestationBurst)
+				require.Equal(t, 1000, c.RateLimit.SigningLimit)
+				require.Equal(t, 2000, c.RateLimit.SigningBurst)
+				require.Equal(t, 50, c.RateLimit.PushJWTKeyLimit)
+				require.Equal(t, 100, c.RateLimit.PushJWTKeyBurst)
+				require.True(t, c.RateLimit.PerCallerID)
+			},
+		},
 		{
 			msg: "warn_on_long_trust_domain",
 			input: func(c *Config) {
@@ -963,6 +984,27 @@ func TestNewServerConfig(t *testing.T) {
 				require.Nil(t, c)
 			},
 		},
+		{
+			msg: "events_based_cache and prune_events_older_than are correctly parsed",
+			input: func(c *Config) {
+				c.Server.Experimental.EventsBasedCache = true
+				c.Server.Experimental.PruneEventsOlderThan = "1h"
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.True(t, c.EventsBasedCache)
+				require.Equal(t, time.Hour, c.PruneEventsOlderThan)
+			},
+		},
+		{
+			msg:         "invalid prune_events_older_than returns an error",
+			expectError: true,
+			input: func(c *Config) {
+				c.Server.Experimental.PruneEventsOlderThan = "b"
+			},
+			test: func(t *testing.T, c *server.Config) {
+				require.Nil(t, c)
+			},
+		},
 		{
 			msg: "audit_log_enabled is enabled",
 			input: func(c *Config) {
@@ -1135,6 +1177,21 @@ func TestValidateConfig(t *testing.T) {
 			},
 			expectedErr: `federation.federates_with["domain.test"].bundle_endpoint_url must use the HTTPS protocol; URL found: "http://example.org/test"`,
 		},
+		{
+			name:        "ratelimit.attestation_limit must not be negative",
+			applyConf:   func(c *Config) { c.Server.RateLimit.AttestationLimit = -1 },
+			expectedErr: "ratelimit.attestation_limit must not be negative",
+		},
+		{
+			name:        "ratelimit.signing_burst must not be negative",
+			applyConf:   func(c *Config) { c.Server.RateLimit.SigningBurst = -1 },
+			expectedErr: "ratelimit.signing_burst must not be negative",
+		},
+		{
+			name:        "ratelimit.push_jwt_key_limit must not be negative",
+			applyConf:   func(c *Config) { c.Server.RateLimit.PushJWTKeyLimit = -1 },
+			expectedErr: "ratelimit.push_jwt_key_limit must not be negative",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -19,6 +19,10 @@ import (
 	api_types "github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	common_cli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/pemutil"
+	agentextv1 "github.com/spiffe/spire/proto/spire/api/server/agent/v1"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+	entryextv1 "github.com/spiffe/spire/proto/spire/api/server/entry/v1"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/health/grpc_health_v1"
@@ -43,10 +47,14 @@ func Dial(addr net.Addr) (*grpc.ClientConn, error) {
 type ServerClient interface {
 	Release()
 	NewAgentClient() agentv1.AgentClient
+	NewAgentExtensionsClient() agentextv1.AgentExtensionsClient
 	NewBundleClient() bundlev1.BundleClient
 	NewEntryClient() entryv1.EntryClient
+	NewEntryExtensionsClient() entryextv1.EntryExtensionsClient
 	NewSVIDClient() svidv1.SVIDClient
 	NewTrustDomainClient() trustdomainv1.TrustDomainClient
+	NewLocalAuthorityClient() localauthorityv1.LocalAuthorityClient
+	NewDataStoreClient() datastorev1.DataStoreClient
 	NewHealthClient() grpc_health_v1.HealthClient
 }
 
@@ -70,6 +78,10 @@ func (c *serverClient) NewAgentClient() agentv1.AgentClient {
 	return agentv1.NewAgentClient(c.conn)
 }
 
+func (c *serverClient) NewAgentExtensionsClient() agentextv1.AgentExtensionsClient {
+	return agentextv1.NewAgentExtensionsClient(c.conn)
+}
+
 func (c *serverClient) NewBundleClient() bundlev1.BundleClient {
 	return bundlev1.NewBundleClient(c.conn)
 }
@@ -78,6 +90,10 @@ func (c *serverClient) NewEntryClient() entryv1.EntryClient {
 	return entryv1.NewEntryClient(c.conn)
 }
 
+func (c *serverClient) NewEntryExtensionsClient() entryextv1.EntryExtensionsClient {
+	return entryextv1.NewEntryExtensionsClient(c.conn)
+}
+
 func (c *serverClient) NewSVIDClient() svidv1.SVIDClient {
 	return svidv1.NewSVIDClient(c.conn)
 }
@@ -86,6 +102,14 @@ func (c *serverClient) NewTrustDomainClient() trustdomainv1.TrustDomainClient {
 	return trustdomainv1.NewTrustDomainClient(c.conn)
 }
 
+func (c *serverClient) NewLocalAuthorityClient() localauthorityv1.LocalAuthorityClient {
+	return localauthorityv1.NewLocalAuthorityClient(c.conn)
+}
+
+func (c *serverClient) NewDataStoreClient() datastorev1.DataStoreClient {
+	return datastorev1.NewDataStoreClient(c.conn)
+}
+
 func (c *serverClient) NewHealthClient() grpc_health_v1.HealthClient {
 	return grpc_health_v1.NewHealthClient(c.conn)
 }
@@ -7,6 +7,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/andres-erbsen/clock"
 	"github.com/sirupsen/logrus"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	delegatedidentityv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/agent/delegatedidentity/v1"
@@ -19,18 +20,35 @@ import (
 	"github.com/spiffe/spire/pkg/agent/manager/cache"
 	"github.com/spiffe/spire/pkg/common/bundleutil"
 	"github.com/spiffe/spire/pkg/common/idutil"
+	"github.com/spiffe/spire/pkg/common/rotationutil"
+	"github.com/spiffe/spire/pkg/common/selector"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/x509util"
 	"github.com/spiffe/spire/pkg/server/api"
+	delegatedidentityextv1 "github.com/spiffe/spire/proto/spire/api/agent/delegatedidentity/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
+// minJWTSVIDRefreshInterval is the minimum amount of time between two
+// refreshes of the JWT-SVIDs streamed by SubscribeToJWTSVIDs. It prevents
+// the stream from spinning when a JWT-SVID cannot be renewed and the cached
+// copy, which is expiring soon, is returned instead.
+const minJWTSVIDRefreshInterval = 5 * time.Second
+
+// pidAttestationInterval is the interval at which the workloads addressed
+// by PID are attested again while they are subscribed. PIDs are reused once
+// the process exits, so subscriptions are closed as soon as the process
+// with the given PID no longer has the selectors it was first attested with.
+const pidAttestationInterval = 10 * time.Second
+
 // RegisterService registers the delegated identity service on the provided server
 func RegisterService(s *grpc.Server, service *Service) {
 	delegatedidentityv1.RegisterDelegatedIdentityServer(s, service)
+	delegatedidentityextv1.RegisterDelegatedIdentityExtensionsServer(s, service)
 }
 
 type attestor interface {
@@ -42,6 +60,7 @@ type Config struct {
 	Manager             manager.Manager
 	Attestor            workloadattestor.Attestor
 	AuthorizedDelegates []string
+	Clock               clock.Clock
 }
 
 func New(config Config) *Service {
@@ -51,19 +70,31 @@ func New(config Config) *Service {
 		AuthorizedDelegates[delegate] = true
 	}
 
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.New()
+	}
+
 	return &Service{
 		manager:             config.Manager,
 		attestor:            endpoints.PeerTrackerAttestor{Attestor: config.Attestor},
+		workloadAttestor:    config.Attestor,
 		authorizedDelegates: AuthorizedDelegates,
+		clk:                 clk,
 	}
 }
 
 // Service implements the delegated identity server
 type Service struct {
 	delegatedidentityv1.UnsafeDelegatedIdentityServer
+	delegatedidentityextv1.UnsafeDelegatedIdentityExtensionsServer
 
 	manager  manager.Manager
 	attestor attestor
+	clk      clock.Clock
+
+	// workloadAttestor attests the workloads addressed by PID
+	workloadAttestor workloadattestor.Attestor
 
 	// SPIFFE IDs of delegates that are authorized to use this API
 	authorizedDelegates map[string]bool
@@ -120,6 +151,35 @@ func (s *Service) SubscribeToX509SVIDs(req *delegatedidentityv1.SubscribeToX509S
 		return status.Error(codes.InvalidArgument, "could not parse provided selectors")
 	}
 
+	return s.subscribeToX509SVIDs(ctx, log, cachedSelectors, 0, selectors, stream)
+}
+
+func (s *Service) SubscribeToX509SVIDsByPID(req *delegatedidentityextv1.SubscribeToX509SVIDsByPIDRequest, stream delegatedidentityextv1.DelegatedIdentityExtensions_SubscribeToX509SVIDsByPIDServer) error {
+	ctx := stream.Context()
+	log := rpccontext.Logger(ctx)
+
+	cachedSelectors, err := s.isCallerAuthorized(ctx, log, nil)
+	if err != nil {
+		return err
+	}
+
+	selectors, err := s.attestPID(ctx, log, req.Pid)
+	if err != nil {
+		return err
+	}
+
+	return s.subscribeToX509SVIDs(ctx, log, cachedSelectors, req.Pid, selectors, stream)
+}
+
+type x509SVIDStream interface {
+	Send(*delegatedidentityv1.SubscribeToX509SVIDsResponse) error
+}
+
+// subscribeToX509SVIDs streams the X509-SVIDs of the workload with the given
+// selectors. If the workload was addressed by PID, pid is the PID of the
+// workload process, which is attested again before each response and
+// periodically, to detect PID reuse. Otherwise, pid is zero.
+func (s *Service) subscribeToX509SVIDs(ctx context.Context, log logrus.FieldLogger, cachedSelectors []*common.Selector, pid int32, selectors []*common.Selector, stream x509SVIDStream) error {
 	subscriber, err := s.manager.SubscribeToCacheChanges(ctx, selectors)
 	if err != nil {
 		log.WithError(err).Error("Subscribe to cache changes failed")
@@ -127,6 +187,9 @@ func (s *Service) SubscribeToX509SVIDs(req *delegatedidentityv1.SubscribeToX509S
 	}
 	defer subscriber.Finish()
 
+	reattest, stop := s.newPIDAttestationTicker(pid)
+	defer stop()
+
 	for {
 		select {
 		case update := <-subscriber.Updates():
@@ -134,16 +197,77 @@ func (s *Service) SubscribeToX509SVIDs(req *delegatedidentityv1.SubscribeToX509S
 				return err
 			}
 
+			if err := s.verifyPID(ctx, log, pid, selectors); err != nil {
+				return err
+			}
+
 			if err := sendX509SVIDResponse(update, stream, log); err != nil {
 				return err
 			}
+		case <-reattest:
+			if err := s.verifyPID(ctx, log, pid, selectors); err != nil {
+				return err
+			}
 		case <-ctx.Done():
 			return nil
 		}
 	}
 }
 
-func sendX509SVIDResponse(update *cache.WorkloadUpdate, stream delegatedidentityv1.DelegatedIdentity_SubscribeToX509SVIDsServer, log logrus.FieldLogger) (err error) {
+// attestPID attests the workload process with the given PID using the
+// configured workload attestor plugins.
+func (s *Service) attestPID(ctx context.Context, log logrus.FieldLogger, pid int32) ([]*common.Selector, error) {
+	if pid <= 0 {
+		log.WithField(telemetry.PID, pid).Error("Invalid argument; invalid PID")
+		return nil, status.Error(codes.InvalidArgument, "a valid PID must be specified")
+	}
+
+	selectors := s.workloadAttestor.Attest(ctx, int(pid))
+	if len(selectors) == 0 {
+		// Attestation errors are logged by the workload attestor
+		log.WithField(telemetry.PID, pid).Error("Could not attest workload process")
+		return nil, status.Errorf(codes.NotFound, "could not attest workload process with PID %d", pid)
+	}
+
+	log.WithFields(logrus.Fields{
+		telemetry.PID:       pid,
+		telemetry.Selectors: selectors,
+	}).Debug("Attested workload process")
+	return selectors, nil
+}
+
+// verifyPID attests the workload process with the given PID again, and fails
+// if its selectors changed since it was first attested, e.g. because the
+// process exited and the PID was reused by another process. It does nothing
+// if pid is zero, i.e. if the workload was addressed by selectors.
+func (s *Service) verifyPID(ctx context.Context, log logrus.FieldLogger, pid int32, selectors []*common.Selector) error {
+	if pid == 0 {
+		return nil
+	}
+
+	current := s.workloadAttestor.Attest(ctx, int(pid))
+	if !selector.NewSetFromRaw(current).Equal(selector.NewSetFromRaw(selectors)) {
+		log.WithFields(logrus.Fields{
+			telemetry.PID:       pid,
+			telemetry.Selectors: current,
+		}).Error("Selectors of workload process changed")
+		return status.Errorf(codes.Aborted, "selectors of workload process with PID %d changed", pid)
+	}
+	return nil
+}
+
+// newPIDAttestationTicker returns a channel that ticks when the workload
+// process with the given PID has to be attested again, along with a function
+// to stop the ticker. The channel is nil if pid is zero.
+func (s *Service) newPIDAttestationTicker(pid int32) (<-chan time.Time, func()) {
+	if pid == 0 {
+		return nil, func() {}
+	}
+	ticker := s.clk.Ticker(pidAttestationInterval)
+	return ticker.C, ticker.Stop
+}
+
+func sendX509SVIDResponse(update *cache.WorkloadUpdate, stream x509SVIDStream, log logrus.FieldLogger) (err error) {
 	resp, err := composeX509SVIDBySelectors(update)
 	if err != nil {
 		log.WithError(err).Error("Could not serialize X.509 SVID response")
@@ -272,25 +396,179 @@ func (s *Service) FetchJWTSVIDs(ctx context.Context, req *delegatedidentityv1.Fe
 		return nil, status.Error(codes.InvalidArgument, "could not parse provided selectors")
 	}
 
-	resp = new(delegatedidentityv1.FetchJWTSVIDsResponse)
+	return s.fetchJWTSVIDsResponse(ctx, log, selectors, req.Audience)
+}
+
+func (s *Service) FetchJWTSVIDsByPID(ctx context.Context, req *delegatedidentityextv1.FetchJWTSVIDsByPIDRequest) (resp *delegatedidentityv1.FetchJWTSVIDsResponse, err error) {
+	log := rpccontext.Logger(ctx)
+	if len(req.Audience) == 0 {
+		log.Error("Missing required audience parameter")
+		return nil, status.Error(codes.InvalidArgument, "audience must be specified")
+	}
+
+	if _, err = s.isCallerAuthorized(ctx, log, nil); err != nil {
+		return nil, err
+	}
+
+	selectors, err := s.attestPID(ctx, log, req.Pid)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err = s.fetchJWTSVIDsResponse(ctx, log, selectors, req.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the PID still refers to the attested process before
+	// returning the JWT-SVIDs.
+	if err := s.verifyPID(ctx, log, req.Pid, selectors); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
 
+func (s *Service) fetchJWTSVIDsResponse(ctx context.Context, log logrus.FieldLogger, selectors []*common.Selector, audience []string) (*delegatedidentityv1.FetchJWTSVIDsResponse, error) {
 	entries := s.manager.MatchingRegistrationEntries(selectors)
+	svids, _, err := s.fetchJWTSVIDs(ctx, log, entries, audience)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(svids) == 0 {
+		log.Error("No identity issued")
+		return nil, status.Error(codes.PermissionDenied, "no identity issued")
+	}
+
+	return &delegatedidentityv1.FetchJWTSVIDsResponse{
+		Svids: svids,
+	}, nil
+}
+
+func (s *Service) SubscribeToJWTSVIDs(req *delegatedidentityextv1.SubscribeToJWTSVIDsRequest, stream delegatedidentityextv1.DelegatedIdentityExtensions_SubscribeToJWTSVIDsServer) error {
+	ctx := stream.Context()
+	log := rpccontext.Logger(ctx)
+	if len(req.Audience) == 0 {
+		log.Error("Missing required audience parameter")
+		return status.Error(codes.InvalidArgument, "audience must be specified")
+	}
+
+	cachedSelectors, err := s.isCallerAuthorized(ctx, log, nil)
+	if err != nil {
+		return err
+	}
+
+	var selectors []*common.Selector
+	switch {
+	case req.Pid != 0 && len(req.Selectors) != 0:
+		log.Error("Invalid argument; both selectors and PID provided")
+		return status.Error(codes.InvalidArgument, "selectors and pid are mutually exclusive")
+	case req.Pid != 0:
+		selectors, err = s.attestPID(ctx, log, req.Pid)
+		if err != nil {
+			return err
+		}
+	default:
+		selectors, err = api.SelectorsFromProto(req.Selectors)
+		if err != nil {
+			log.WithError(err).Error("Invalid argument; could not parse provided selectors")
+			return status.Error(codes.InvalidArgument, "could not parse provided selectors")
+		}
+	}
+
+	subscriber, err := s.manager.SubscribeToCacheChanges(ctx, selectors)
+	if err != nil {
+		log.WithError(err).Error("Subscribe to cache changes failed")
+		return err
+	}
+	defer subscriber.Finish()
+
+	reattest, stop := s.newPIDAttestationTicker(req.Pid)
+	defer stop()
+
+	var entries []*common.RegistrationEntry
+	var lastResp *delegatedidentityextv1.SubscribeToJWTSVIDsResponse
+	var refresh <-chan time.Time
+	for {
+		select {
+		case update := <-subscriber.Updates():
+			entries = entries[:0]
+			for _, identity := range update.Identities {
+				entries = append(entries, identity.Entry)
+			}
+		case <-refresh:
+		case <-reattest:
+			if err := s.verifyPID(ctx, log, req.Pid, selectors); err != nil {
+				return err
+			}
+			continue
+		case <-ctx.Done():
+			return nil
+		}
+
+		if _, err := s.isCallerAuthorized(ctx, log, cachedSelectors); err != nil {
+			return err
+		}
+
+		if err := s.verifyPID(ctx, log, req.Pid, selectors); err != nil {
+			return err
+		}
+
+		svids, refreshAt, err := s.fetchJWTSVIDs(ctx, log, entries, req.Audience)
+		if err != nil {
+			return err
+		}
+
+		// Refresh the JWT-SVIDs as soon as any of them is about to expire
+		refresh = nil
+		if !refreshAt.IsZero() {
+			refreshIn := refreshAt.Sub(s.clk.Now())
+			if refreshIn < minJWTSVIDRefreshInterval {
+				refreshIn = minJWTSVIDRefreshInterval
+			}
+			refresh = s.clk.After(refreshIn)
+		}
+
+		resp := &delegatedidentityextv1.SubscribeToJWTSVIDsResponse{
+			Svids: svids,
+		}
+		// Workload updates are also triggered by X509-SVID rotations, which
+		// do not affect the JWT-SVIDs.
+		if lastResp != nil && proto.Equal(lastResp, resp) {
+			continue
+		}
+
+		if err := stream.Send(resp); err != nil {
+			log.WithError(err).Error("Failed to send JWT-SVID response")
+			return err
+		}
+		lastResp = resp
+	}
+}
+
+// fetchJWTSVIDs fetches JWT-SVIDs for the given entries and audience. The
+// agent JWT-SVID cache is used, so new JWT-SVIDs are only minted when the
+// cached ones are about to expire. It also returns the time at which the
+// earliest of the JWT-SVIDs is about to expire, and should be refreshed.
+func (s *Service) fetchJWTSVIDs(ctx context.Context, log logrus.FieldLogger, entries []*common.RegistrationEntry, audience []string) ([]*types.JWTSVID, time.Time, error) {
+	var svids []*types.JWTSVID
+	var refreshAt time.Time
 	for _, entry := range entries {
 		spiffeID, err := spiffeid.FromString(entry.SpiffeId)
 		if err != nil {
 			log.WithField(telemetry.SPIFFEID, entry.SpiffeId).WithError(err).Error("Invalid requested SPIFFE ID")
-			return nil, status.Errorf(codes.InvalidArgument, "invalid requested SPIFFE ID: %v", err)
+			return nil, time.Time{}, status.Errorf(codes.InvalidArgument, "invalid requested SPIFFE ID: %v", err)
 		}
 
 		loopLog := log.WithField(telemetry.SPIFFEID, spiffeID.String())
 
 		var svid *client.JWTSVID
-		svid, err = s.manager.FetchJWTSVID(ctx, entry, req.Audience)
+		svid, err = s.manager.FetchJWTSVID(ctx, entry, audience)
 		if err != nil {
 			loopLog.WithError(err).Error("Could not fetch JWT-SVID")
-			return nil, status.Errorf(codes.Unavailable, "could not fetch JWT-SVID: %v", err)
+			return nil, time.Time{}, status.Errorf(codes.Unavailable, "could not fetch JWT-SVID: %v", err)
 		}
-		resp.Svids = append(resp.Svids, &types.JWTSVID{
+		svids = append(svids, &types.JWTSVID{
 			Token: svid.Token,
 			Id: &types.SPIFFEID{
 				TrustDomain: spiffeID.TrustDomain().Name(),
@@ -301,16 +579,17 @@ func (s *Service) FetchJWTSVIDs(ctx context.Context, req *delegatedidentityv1.Fe
 			Hint:      entry.Hint,
 		})
 
+		// The agent mints new JWT-SVIDs once the cached ones expire soon.
+		expiresSoonAt := rotationutil.JWTSVIDExpiresSoonAt(svid)
+		if refreshAt.IsZero() || expiresSoonAt.Before(refreshAt) {
+			refreshAt = expiresSoonAt
+		}
+
 		ttl := time.Until(svid.ExpiresAt)
 		loopLog.WithField(telemetry.TTL, ttl.Seconds()).Debug("Fetched JWT SVID")
 	}
 
-	if len(resp.Svids) == 0 {
-		log.Error("No identity issued")
-		return nil, status.Error(codes.PermissionDenied, "no identity issued")
-	}
-
-	return resp, nil
+	return svids, refreshAt, nil
 }
 
 func (s *Service) SubscribeToJWTBundles(_ *delegatedidentityv1.SubscribeToJWTBundlesRequest, stream delegatedidentityv1.DelegatedIdentity_SubscribeToJWTBundlesServer) error {
@@ -5,6 +5,7 @@ import (
 	"crypto"
 	"crypto/x509"
 	"errors"
+	"reflect"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -23,10 +24,13 @@ import (
 	"github.com/spiffe/spire/pkg/common/api/middleware"
 	"github.com/spiffe/spire/pkg/common/bundleutil"
 	"github.com/spiffe/spire/pkg/common/idutil"
+	"github.com/spiffe/spire/pkg/common/rotationutil"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/x509util"
 	"github.com/spiffe/spire/pkg/server/api"
+	delegatedidentityextv1 "github.com/spiffe/spire/proto/spire/api/agent/delegatedidentity/v1"
 	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/spiffe/spire/test/testca"
 	"github.com/stretchr/testify/assert"
@@ -280,6 +284,152 @@ func TestSubscribeToX509SVIDs(t *testing.T) {
 	}
 }
 
+func TestSubscribeToX509SVIDsByPID(t *testing.T) {
+	ca := testca.New(t, trustDomain1)
+
+	x509SVID1 := ca.CreateX509SVID(id1)
+	identities := []cache.Identity{
+		identityFromX509SVID(x509SVID1),
+	}
+	workloadSelectors := []*common.Selector{{Type: "k8s", Value: "sa:foo"}}
+
+	for _, tt := range []struct {
+		testName     string
+		pid          int32
+		authSpiffeID []string
+		expectCode   codes.Code
+		expectMsg    string
+		attestErr    error
+		reuseAfter   int32
+		expectResp   *delegatedidentityv1.SubscribeToX509SVIDsResponse
+	}{
+		{
+			testName:   "Attest error",
+			pid:        1234,
+			attestErr:  errors.New("ohno"),
+			expectCode: codes.Internal,
+			expectMsg:  "workload attestation failed",
+		},
+		{
+			testName:     "Access to \"privileged\" admin API denied",
+			pid:          1234,
+			authSpiffeID: []string{"spiffe://example.org/one/wrong"},
+			expectCode:   codes.PermissionDenied,
+			expectMsg:    "caller not configured as an authorized delegate",
+		},
+		{
+			testName:     "missing PID",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			expectCode:   codes.InvalidArgument,
+			expectMsg:    "a valid PID must be specified",
+		},
+		{
+			testName:     "process cannot be attested",
+			pid:          4321,
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			expectCode:   codes.NotFound,
+			expectMsg:    "could not attest workload process with PID 4321",
+		},
+		{
+			testName:     "PID reused before the response is sent",
+			pid:          1234,
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			reuseAfter:   1,
+			expectCode:   codes.Aborted,
+			expectMsg:    "selectors of workload process with PID 1234 changed",
+		},
+		{
+			testName:     "success",
+			pid:          1234,
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			expectResp: &delegatedidentityv1.SubscribeToX509SVIDsResponse{
+				X509Svids: []*delegatedidentityv1.X509SVIDWithKey{
+					{
+						X509Svid: &types.X509SVID{
+							Id:        utilIDProtoFromString(t, x509SVID1.ID.String()),
+							CertChain: x509util.RawCertsFromCertificates(x509SVID1.Certificates),
+							ExpiresAt: x509SVID1.Certificates[0].NotAfter.Unix(),
+						},
+						X509SvidKey: pkcs8FromSigner(t, x509SVID1.PrivateKey),
+					},
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			params := testParams{
+				CA:           ca,
+				Identities:   identities,
+				Updates:      []*cache.WorkloadUpdate{{Identities: identities}},
+				AuthSpiffeID: tt.authSpiffeID,
+				AttestErr:    tt.attestErr,
+				WorkloadAttestor: &FakeWorkloadAttestor{
+					pid:        1234,
+					selectors:  workloadSelectors,
+					reuseAfter: tt.reuseAfter,
+				},
+			}
+			runExtensionsTest(t, params,
+				func(ctx context.Context, client delegatedidentityextv1.DelegatedIdentityExtensionsClient) {
+					stream, err := client.SubscribeToX509SVIDsByPID(ctx, &delegatedidentityextv1.SubscribeToX509SVIDsByPIDRequest{
+						Pid: tt.pid,
+					})
+					require.NoError(t, err)
+
+					resp, err := stream.Recv()
+					spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+					spiretest.RequireProtoEqual(t, tt.expectResp, resp)
+				})
+		})
+	}
+}
+
+func TestSubscribeToX509SVIDsByPIDReattestation(t *testing.T) {
+	ca := testca.New(t, trustDomain1)
+
+	identities := []cache.Identity{
+		identityFromX509SVID(ca.CreateX509SVID(id1)),
+	}
+	clk := clock.NewMock(t)
+
+	params := testParams{
+		CA:           ca,
+		Clock:        clk,
+		Identities:   identities,
+		Updates:      []*cache.WorkloadUpdate{{Identities: identities}},
+		AuthSpiffeID: []string{"spiffe://example.org/one"},
+		WorkloadAttestor: &FakeWorkloadAttestor{
+			pid:       1234,
+			selectors: []*common.Selector{{Type: "k8s", Value: "sa:foo"}},
+			// Attested when subscribing, before the first response, and on
+			// the first tick.
+			reuseAfter: 3,
+		},
+	}
+	runExtensionsTest(t, params,
+		func(ctx context.Context, client delegatedidentityextv1.DelegatedIdentityExtensionsClient) {
+			stream, err := client.SubscribeToX509SVIDsByPID(ctx, &delegatedidentityextv1.SubscribeToX509SVIDsByPIDRequest{
+				Pid: 1234,
+			})
+			require.NoError(t, err)
+
+			resp, err := stream.Recv()
+			require.NoError(t, err)
+			require.Len(t, resp.X509Svids, 1)
+
+			// The process is still the same on the first tick
+			clk.WaitForTicker(time.Minute, "waiting for the PID attestation ticker")
+			clk.Add(pidAttestationInterval)
+
+			// The PID is reused on the second tick, which closes the stream
+			clk.Add(pidAttestationInterval)
+			resp, err = stream.Recv()
+			spiretest.RequireGRPCStatus(t, err, codes.Aborted, "selectors of workload process with PID 1234 changed")
+			require.Nil(t, resp)
+		})
+}
+
 func TestSubscribeToX509Bundles(t *testing.T) {
 	ca := testca.New(t, trustDomain1)
 
@@ -567,6 +717,417 @@ func TestFetchJWTSVIDs(t *testing.T) {
 		})
 	}
 }
+func TestFetchJWTSVIDsByPID(t *testing.T) {
+	ca := testca.New(t, trustDomain1)
+
+	x509SVID1 := ca.CreateX509SVID(id1)
+	jwtSVID1Token := ca.CreateJWTSVID(id1, []string{"AUDIENCE"}).Marshal()
+	identities := []cache.Identity{
+		identityFromX509SVID(x509SVID1),
+	}
+	workloadSelectors := []*common.Selector{{Type: "k8s", Value: "sa:foo"}}
+
+	for _, tt := range []struct {
+		testName     string
+		pid          int32
+		audience     []string
+		authSpiffeID []string
+		expectCode   codes.Code
+		expectMsg    string
+		reuseAfter   int32
+		expectResp   *delegatedidentityv1.FetchJWTSVIDsResponse
+	}{
+		{
+			testName:   "missing required audience",
+			pid:        1234,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "audience must be specified",
+		},
+		{
+			testName:     "missing PID",
+			audience:     []string{"AUDIENCE"},
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			expectCode:   codes.InvalidArgument,
+			expectMsg:    "a valid PID must be specified",
+		},
+		{
+			testName:     "process cannot be attested",
+			pid:          4321,
+			audience:     []string{"AUDIENCE"},
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			expectCode:   codes.NotFound,
+			expectMsg:    "could not attest workload process with PID 4321",
+		},
+		{
+			testName:     "PID reused while fetching",
+			pid:          1234,
+			audience:     []string{"AUDIENCE"},
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			reuseAfter:   1,
+			expectCode:   codes.Aborted,
+			expectMsg:    "selectors of workload process with PID 1234 changed",
+		},
+		{
+			testName:     "success",
+			pid:          1234,
+			audience:     []string{"AUDIENCE"},
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			expectResp: &delegatedidentityv1.FetchJWTSVIDsResponse{
+				Svids: []*types.JWTSVID{
+					{
+						Token:     jwtSVID1Token,
+						Id:        api.ProtoFromID(id1),
+						ExpiresAt: 1680786600,
+						IssuedAt:  1680783000,
+					},
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			params := testParams{
+				CA:           ca,
+				Identities:   identities,
+				AuthSpiffeID: tt.authSpiffeID,
+				JwtSVIDS: map[spiffeid.ID]*client.JWTSVID{
+					id1: {
+						Token:     jwtSVID1Token,
+						ExpiresAt: time.Unix(1680786600, 0),
+						IssuedAt:  time.Unix(1680783000, 0),
+					},
+				},
+				WorkloadAttestor: &FakeWorkloadAttestor{
+					pid:        1234,
+					selectors:  workloadSelectors,
+					reuseAfter: tt.reuseAfter,
+				},
+			}
+			runExtensionsTest(t, params,
+				func(ctx context.Context, client delegatedidentityextv1.DelegatedIdentityExtensionsClient) {
+					resp, err := client.FetchJWTSVIDsByPID(ctx, &delegatedidentityextv1.FetchJWTSVIDsByPIDRequest{
+						Audience: tt.audience,
+						Pid:      tt.pid,
+					})
+					spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+					spiretest.RequireProtoEqual(t, tt.expectResp, resp)
+				})
+		})
+	}
+}
+
+func TestSubscribeToJWTSVIDs(t *testing.T) {
+	ca := testca.New(t, trustDomain1)
+
+	x509SVID1 := ca.CreateX509SVID(id1)
+	x509SVID2 := ca.CreateX509SVID(id2)
+
+	identities := []cache.Identity{
+		identityFromX509SVID(x509SVID1),
+		identityFromX509SVID(x509SVID2),
+	}
+	identities[0].Entry.Hint = "internal"
+
+	clk := clock.NewMock(t)
+	issuedAt := time.Unix(clk.Now().Unix(), 0)
+	expiresAt := issuedAt.Add(time.Hour)
+	jwtSVID := func(id spiffeid.ID, issuedAt time.Time) *client.JWTSVID {
+		return &client.JWTSVID{
+			Token:     ca.CreateJWTSVID(id, []string{"AUDIENCE"}).Marshal(),
+			IssuedAt:  issuedAt,
+			ExpiresAt: issuedAt.Add(time.Hour),
+		}
+	}
+	jwtSVIDs := map[spiffeid.ID]*client.JWTSVID{
+		id1: jwtSVID(id1, issuedAt),
+		id2: {
+			Token:     ca.CreateJWTSVID(id2, []string{"AUDIENCE"}).Marshal(),
+			IssuedAt:  issuedAt,
+			ExpiresAt: issuedAt.Add(2 * time.Hour),
+		},
+	}
+	// Only the JWT-SVID for id1 is rotated, since the one for id2 has a
+	// longer lifetime.
+	rotatedJWTSVIDs := map[spiffeid.ID]*client.JWTSVID{
+		id1: jwtSVID(id1, issuedAt.Add(30*time.Minute)),
+	}
+
+	for _, tt := range []struct {
+		testName     string
+		identities   []cache.Identity
+		updates      []*cache.WorkloadUpdate
+		authSpiffeID []string
+		audience     []string
+		selectors    []*types.Selector
+		pid          int32
+		reuseAfter   int32
+		expectCode   codes.Code
+		expectMsg    string
+		attestErr    error
+		managerErr   error
+		expectResp   []*delegatedidentityextv1.SubscribeToJWTSVIDsResponse
+	}{
+		{
+			testName:   "missing required audience",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "audience must be specified",
+		},
+		{
+			testName:   "Attest error",
+			attestErr:  errors.New("ohno"),
+			audience:   []string{"AUDIENCE"},
+			expectCode: codes.Internal,
+			expectMsg:  "workload attestation failed",
+		},
+		{
+			testName:     "Access to \"privileged\" admin API denied",
+			authSpiffeID: []string{"spiffe://example.org/one/wrong"},
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			expectCode: codes.PermissionDenied,
+			expectMsg:  "caller not configured as an authorized delegate",
+		},
+		{
+			testName:     "selectors missing type",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			selectors:    []*types.Selector{{Type: "", Value: "foo"}},
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "could not parse provided selectors",
+		},
+		{
+			testName:     "selectors and PID",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			selectors:    []*types.Selector{{Type: "sa", Value: "foo"}},
+			pid:          1234,
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "selectors and pid are mutually exclusive",
+		},
+		{
+			testName:     "process cannot be attested",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			pid:          4321,
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			expectCode: codes.NotFound,
+			expectMsg:  "could not attest workload process with PID 4321",
+		},
+		{
+			testName:     "PID reused before the response is sent",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			pid:          1234,
+			reuseAfter:   1,
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			updates: []*cache.WorkloadUpdate{
+				{
+					Identities: []cache.Identity{identities[0]},
+				},
+			},
+			expectCode: codes.Aborted,
+			expectMsg:  "selectors of workload process with PID 1234 changed",
+		},
+		{
+			testName:     "success by PID",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			pid:          1234,
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			updates: []*cache.WorkloadUpdate{
+				{
+					Identities: []cache.Identity{identities[0]},
+				},
+			},
+			expectResp: []*delegatedidentityextv1.SubscribeToJWTSVIDsResponse{
+				{
+					Svids: []*types.JWTSVID{
+						{
+							Token:     jwtSVIDs[id1].Token,
+							Id:        api.ProtoFromID(id1),
+							Hint:      "internal",
+							ExpiresAt: expiresAt.Unix(),
+							IssuedAt:  issuedAt.Unix(),
+						},
+					},
+				},
+			},
+		},
+		{
+			testName:     "subscribe to cache changes error",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			selectors:    []*types.Selector{{Type: "sa", Value: "foo"}},
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			managerErr: errors.New("err"),
+			expectCode: codes.Unknown,
+			expectMsg:  "err",
+		},
+		{
+			testName:     "fetch error",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			selectors:    []*types.Selector{{Type: "sa", Value: "foo"}},
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			updates: []*cache.WorkloadUpdate{
+				{
+					Identities: []cache.Identity{
+						identityFromX509SVID(ca.CreateX509SVID(spiffeid.RequireFromPath(trustDomain1, "/unknown"))),
+					},
+				},
+			},
+			expectCode: codes.Unavailable,
+			expectMsg:  "could not fetch JWT-SVID: not found",
+		},
+		{
+			testName:     "workload update without identities",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			selectors:    []*types.Selector{{Type: "sa", Value: "foo"}},
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			updates: []*cache.WorkloadUpdate{{}},
+			expectResp: []*delegatedidentityextv1.SubscribeToJWTSVIDsResponse{
+				{},
+			},
+		},
+		{
+			testName:     "success with workload update and rotation",
+			authSpiffeID: []string{"spiffe://example.org/one"},
+			selectors:    []*types.Selector{{Type: "sa", Value: "foo"}},
+			audience:     []string{"AUDIENCE"},
+			identities: []cache.Identity{
+				identities[0],
+			},
+			updates: []*cache.WorkloadUpdate{
+				{
+					Identities: []cache.Identity{identities[0]},
+				},
+				{
+					Identities: identities,
+				},
+			},
+			expectResp: []*delegatedidentityextv1.SubscribeToJWTSVIDsResponse{
+				{
+					Svids: []*types.JWTSVID{
+						{
+							Token:     jwtSVIDs[id1].Token,
+							Id:        api.ProtoFromID(id1),
+							Hint:      "internal",
+							ExpiresAt: expiresAt.Unix(),
+							IssuedAt:  issuedAt.Unix(),
+						},
+					},
+				},
+				{
+					Svids: []*types.JWTSVID{
+						{
+							Token:     jwtSVIDs[id1].Token,
+							Id:        api.ProtoFromID(id1),
+							Hint:      "internal",
+							ExpiresAt: expiresAt.Unix(),
+							IssuedAt:  issuedAt.Unix(),
+						},
+						{
+							Token:     jwtSVIDs[id2].Token,
+							Id:        api.ProtoFromID(id2),
+							ExpiresAt: expiresAt.Add(time.Hour).Unix(),
+							IssuedAt:  issuedAt.Unix(),
+						},
+					},
+				},
+				{
+					Svids: []*types.JWTSVID{
+						{
+							Token:     rotatedJWTSVIDs[id1].Token,
+							Id:        api.ProtoFromID(id1),
+							Hint:      "internal",
+							ExpiresAt: expiresAt.Add(30 * time.Minute).Unix(),
+							IssuedAt:  issuedAt.Add(30 * time.Minute).Unix(),
+						},
+						{
+							Token:     jwtSVIDs[id2].Token,
+							Id:        api.ProtoFromID(id2),
+							ExpiresAt: expiresAt.Add(time.Hour).Unix(),
+							IssuedAt:  issuedAt.Unix(),
+						},
+					},
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			clk := clock.NewMockAt(t, issuedAt)
+			params := testParams{
+				CA:              ca,
+				Clock:           clk,
+				Identities:      tt.identities,
+				Updates:         tt.updates,
+				AuthSpiffeID:    tt.authSpiffeID,
+				AttestErr:       tt.attestErr,
+				ManagerErr:      tt.managerErr,
+				JwtSVIDS:        jwtSVIDs,
+				RotatedJwtSVIDs: rotatedJWTSVIDs,
+			}
+			if tt.pid != 0 {
+				params.WorkloadAttestor = &FakeWorkloadAttestor{
+					pid:        1234,
+					selectors:  []*common.Selector{{Type: "k8s", Value: "sa:foo"}},
+					reuseAfter: tt.reuseAfter,
+				}
+			}
+			runExtensionsTest(t, params,
+				func(ctx context.Context, client delegatedidentityextv1.DelegatedIdentityExtensionsClient) {
+					stream, err := client.SubscribeToJWTSVIDs(ctx, &delegatedidentityextv1.SubscribeToJWTSVIDsRequest{
+						Audience:  tt.audience,
+						Selectors: tt.selectors,
+						Pid:       tt.pid,
+					})
+					require.NoError(t, err)
+
+					for i, expectResp := range tt.expectResp {
+						if i == len(tt.updates) {
+							// All updates were processed; rotate the
+							// JWT-SVIDs that are about to expire.
+							clk.WaitForAfter(time.Minute, "waiting for the JWT-SVID refresh")
+							clk.Add(30 * time.Minute)
+						}
+						resp, err := stream.Recv()
+						require.NoError(t, err)
+						spiretest.AssertProtoEqual(t, expectResp, resp)
+					}
+
+					if len(tt.expectResp) > 0 {
+						return
+					}
+					resp, err := stream.Recv()
+					spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+					require.Nil(t, resp)
+				})
+		})
+	}
+}
+
 func TestSubscribeToJWTBundles(t *testing.T) {
 	ca := testca.New(t, trustDomain1)
 
@@ -664,35 +1225,64 @@ func TestSubscribeToJWTBundles(t *testing.T) {
 }
 
 type testParams struct {
-	CA           *testca.CA
-	Identities   []cache.Identity
-	Updates      []*cache.WorkloadUpdate
-	CacheUpdates map[spiffeid.TrustDomain]*cache.Bundle
-	JwtSVIDS     map[spiffeid.ID]*client.JWTSVID
-	AuthSpiffeID []string
-	AttestErr    error
-	ManagerErr   error
+	CA              *testca.CA
+	Clock           *clock.Mock
+	Identities      []cache.Identity
+	Updates         []*cache.WorkloadUpdate
+	CacheUpdates    map[spiffeid.TrustDomain]*cache.Bundle
+	JwtSVIDS        map[spiffeid.ID]*client.JWTSVID
+	RotatedJwtSVIDs map[spiffeid.ID]*client.JWTSVID
+	// WorkloadAttestor attests the workloads addressed by PID
+	WorkloadAttestor *FakeWorkloadAttestor
+	AuthSpiffeID     []string
+	AttestErr        error
+	ManagerErr       error
 }
 
 func runTest(t *testing.T, params testParams, fn func(ctx context.Context, client delegatedidentityv1.DelegatedIdentityClient)) {
+	runTestWithConn(t, params, func(ctx context.Context, conn *grpc.ClientConn) {
+		fn(ctx, delegatedidentityv1.NewDelegatedIdentityClient(conn))
+	})
+}
+
+func runExtensionsTest(t *testing.T, params testParams, fn func(ctx context.Context, client delegatedidentityextv1.DelegatedIdentityExtensionsClient)) {
+	runTestWithConn(t, params, func(ctx context.Context, conn *grpc.ClientConn) {
+		fn(ctx, delegatedidentityextv1.NewDelegatedIdentityExtensionsClient(conn))
+	})
+}
+
+func runTestWithConn(t *testing.T, params testParams, fn func(ctx context.Context, conn *grpc.ClientConn)) {
 	log, _ := test.NewNullLogger()
 	log.Level = logrus.DebugLevel
 
+	clk := params.Clock
+	if clk == nil {
+		clk = clock.NewMock(t)
+	}
+
 	manager := &FakeManager{
-		Manager:     nil,
-		ca:          params.CA,
-		identities:  params.Identities,
-		updates:     params.Updates,
-		cacheUpdate: params.CacheUpdates,
-		jwtSVIDs:    params.JwtSVIDS,
-		err:         params.ManagerErr,
+		Manager:         nil,
+		ca:              params.CA,
+		clk:             clk,
+		identities:      params.Identities,
+		updates:         params.Updates,
+		cacheUpdate:     params.CacheUpdates,
+		jwtSVIDs:        params.JwtSVIDS,
+		rotatedJWTSVIDs: params.RotatedJwtSVIDs,
+		err:             params.ManagerErr,
 	}
 
-	service := New(Config{
+	config := Config{
 		Log:                 log,
 		Manager:             manager,
 		AuthorizedDelegates: params.AuthSpiffeID,
-	})
+		Clock:               clk,
+	}
+	if params.WorkloadAttestor != nil {
+		config.Attestor = params.WorkloadAttestor
+		manager.expectSelectors = params.WorkloadAttestor.selectors
+	}
+	service := New(config)
 
 	service.attestor = FakeAttestor{
 		err: params.AttestErr,
@@ -704,7 +1294,7 @@ func runTest(t *testing.T, params testParams, fn func(ctx context.Context, clien
 		grpc.StreamInterceptor(streamInterceptor),
 	)
 
-	delegatedidentityv1.RegisterDelegatedIdentityServer(server, service)
+	RegisterService(server, service)
 	addr := spiretest.ServeGRPCServerOnTempUDSSocket(t, server)
 	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 	defer cancel()
@@ -712,7 +1302,7 @@ func runTest(t *testing.T, params testParams, fn func(ctx context.Context, clien
 	conn, _ := grpc.DialContext(ctx, "unix:"+addr.String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
 	t.Cleanup(func() { conn.Close() })
 
-	fn(ctx, delegatedidentityv1.NewDelegatedIdentityClient(conn))
+	fn(ctx, conn)
 	cancel()
 	server.GracefulStop()
 }
@@ -726,15 +1316,44 @@ func (fa FakeAttestor) Attest(context.Context) ([]*common.Selector, error) {
 	return fa.selectors, fa.err
 }
 
+type FakeWorkloadAttestor struct {
+	pid       int
+	selectors []*common.Selector
+
+	// reuseAfter, if set, is the number of attestations after which the
+	// PID is reused by another process, with different selectors.
+	reuseAfter   int32
+	attestations int32
+}
+
+func (fa *FakeWorkloadAttestor) Attest(_ context.Context, pid int) []*common.Selector {
+	if pid != fa.pid {
+		return nil
+	}
+	if n := atomic.AddInt32(&fa.attestations, 1); fa.reuseAfter > 0 && n > fa.reuseAfter {
+		return []*common.Selector{{Type: "k8s", Value: "sa:other"}}
+	}
+	return fa.selectors
+}
+
 type FakeManager struct {
 	manager.Manager
 
 	ca          *testca.CA
+	clk         *clock.Mock
 	identities  []cache.Identity
 	jwtSVIDs    map[spiffeid.ID]*client.JWTSVID
 	updates     []*cache.WorkloadUpdate
 	cacheUpdate map[spiffeid.TrustDomain]*cache.Bundle
 
+	// rotatedJWTSVIDs are returned instead of the ones in jwtSVIDs once
+	// those are expiring soon.
+	rotatedJWTSVIDs map[spiffeid.ID]*client.JWTSVID
+
+	// expectSelectors, if set, are the only selectors workloads are
+	// expected to subscribe with.
+	expectSelectors []*common.Selector
+
 	subscribers int32
 	err         error
 }
@@ -747,10 +1366,13 @@ func (m *FakeManager) subscriberDone() {
 	atomic.AddInt32(&m.subscribers, -1)
 }
 
-func (m *FakeManager) SubscribeToCacheChanges(context.Context, cache.Selectors) (cache.Subscriber, error) {
+func (m *FakeManager) SubscribeToCacheChanges(_ context.Context, selectors cache.Selectors) (cache.Subscriber, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	if m.expectSelectors != nil && !reflect.DeepEqual(m.expectSelectors, []*common.Selector(selectors)) {
+		return nil, errors.New("unexpected selectors")
+	}
 	atomic.AddInt32(&m.subscribers, 1)
 	return newFakeSubscriber(m, m.updates), nil
 }
@@ -769,6 +1391,9 @@ func (m *FakeManager) FetchJWTSVID(_ context.Context, entry *common.Registration
 	if !ok {
 		return nil, errors.New("not found")
 	}
+	if rotatedSVID, ok := m.rotatedJWTSVIDs[spiffeID]; ok && rotationutil.JWTSVIDExpiresSoon(svid, m.clk.Now()) {
+		return rotatedSVID, nil
+	}
 	return svid, nil
 }
 
@@ -2,6 +2,8 @@ package catalog
 
 import (
 	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor"
+	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor/cgroup"
+	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor/container"
 	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor/docker"
 	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor/k8s"
 	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor/systemd"
@@ -28,6 +30,8 @@ func (repo *workloadAttestorRepository) Versions() []catalog.Version {
 
 func (repo *workloadAttestorRepository) BuiltIns() []catalog.BuiltIn {
 	return []catalog.BuiltIn{
+		cgroup.BuiltIn(),
+		container.BuiltIn(),
 		docker.BuiltIn(),
 		k8s.BuiltIn(),
 		systemd.BuiltIn(),
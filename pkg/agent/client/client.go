@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/tls"
@@ -19,6 +20,7 @@ import (
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
 	"github.com/spiffe/spire/pkg/common/bundleutil"
 	"github.com/spiffe/spire/pkg/common/telemetry"
+	bundleextv1 "github.com/spiffe/spire/proto/spire/api/server/bundle/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -71,10 +73,11 @@ type client struct {
 	m           sync.Mutex
 
 	// Constructor used for testing purposes.
-	createNewEntryClient  func(grpc.ClientConnInterface) entryv1.EntryClient
-	createNewBundleClient func(grpc.ClientConnInterface) bundlev1.BundleClient
-	createNewSVIDClient   func(grpc.ClientConnInterface) svidv1.SVIDClient
-	createNewAgentClient  func(grpc.ClientConnInterface) agentv1.AgentClient
+	createNewEntryClient            func(grpc.ClientConnInterface) entryv1.EntryClient
+	createNewBundleClient           func(grpc.ClientConnInterface) bundlev1.BundleClient
+	createNewBundleExtensionsClient func(grpc.ClientConnInterface) bundleextv1.BundleExtensionsClient
+	createNewSVIDClient             func(grpc.ClientConnInterface) svidv1.SVIDClient
+	createNewAgentClient            func(grpc.ClientConnInterface) agentv1.AgentClient
 
 	// Constructor used for testing purposes.
 	dialContext func(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error)
@@ -87,11 +90,12 @@ func New(c *Config) Client {
 
 func newClient(c *Config) *client {
 	return &client{
-		c:                     c,
-		createNewEntryClient:  entryv1.NewEntryClient,
-		createNewBundleClient: bundlev1.NewBundleClient,
-		createNewSVIDClient:   svidv1.NewSVIDClient,
-		createNewAgentClient:  agentv1.NewAgentClient,
+		c:                               c,
+		createNewEntryClient:            entryv1.NewEntryClient,
+		createNewBundleClient:           bundlev1.NewBundleClient,
+		createNewBundleExtensionsClient: bundleextv1.NewBundleExtensionsClient,
+		createNewSVIDClient:             svidv1.NewSVIDClient,
+		createNewAgentClient:            agentv1.NewAgentClient,
 	}
 }
 
@@ -133,7 +137,7 @@ func (c *client) FetchUpdates(ctx context.Context) (*Update, error) {
 		keys = append(keys, key)
 	}
 
-	protoBundles, err := c.fetchBundles(ctx, keys)
+	protoBundles, tainted, err := c.fetchBundles(ctx, keys)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +151,9 @@ func (c *client) FetchUpdates(ctx context.Context) (*Update, error) {
 		}
 		bundles[bundle.TrustDomainId] = bundle
 	}
+	if bundle, ok := bundles[c.c.TrustDomain.IDString()]; ok {
+		markTaintedAuthorities(bundle, tainted)
+	}
 
 	return &Update{
 		Entries: regEntries,
@@ -332,10 +339,10 @@ func (c *client) fetchEntries(ctx context.Context) ([]*types.Entry, error) {
 	return resp.Entries, err
 }
 
-func (c *client) fetchBundles(ctx context.Context, federatedBundles []string) ([]*types.Bundle, error) {
+func (c *client) fetchBundles(ctx context.Context, federatedBundles []string) ([]*types.Bundle, *bundleextv1.GetTaintedAuthoritiesResponse, error) {
 	bundleClient, connection, err := c.newBundleClient(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer connection.Release()
 
@@ -346,14 +353,28 @@ func (c *client) fetchBundles(ctx context.Context, federatedBundles []string) ([
 	if err != nil {
 		c.release(connection)
 		c.c.Log.WithError(err).Error("Failed to fetch bundle")
-		return nil, fmt.Errorf("failed to fetch bundle: %w", err)
+		return nil, nil, fmt.Errorf("failed to fetch bundle: %w", err)
 	}
 	bundles = append(bundles, bundle)
 
+	// Get tainted authorities. Servers that predate the BundleExtensions API
+	// do not implement it, in which case no authority is considered tainted.
+	bundleExtClient := c.createNewBundleExtensionsClient(connection.conn)
+	tainted, err := bundleExtClient.GetTaintedAuthorities(ctx, &bundleextv1.GetTaintedAuthoritiesRequest{})
+	switch status.Code(err) {
+	case codes.OK:
+	case codes.Unimplemented:
+		tainted = nil
+	default:
+		c.release(connection)
+		c.c.Log.WithError(err).Error("Failed to fetch tainted authorities")
+		return nil, nil, fmt.Errorf("failed to fetch tainted authorities: %w", err)
+	}
+
 	for _, b := range federatedBundles {
 		federatedTD, err := spiffeid.TrustDomainFromString(b)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		bundle, err := bundleClient.GetFederatedBundle(ctx, &bundlev1.GetFederatedBundleRequest{
 			TrustDomain: federatedTD.Name(),
@@ -365,11 +386,36 @@ func (c *client) fetchBundles(ctx context.Context, federatedBundles []string) ([
 			c.c.Log.WithError(err).WithField(telemetry.FederatedBundle, b).Warn("Federated bundle not found")
 		default:
 			c.c.Log.WithError(err).WithField(telemetry.FederatedBundle, b).Error("Failed to fetch federated bundle")
-			return nil, fmt.Errorf("failed to fetch federated bundle: %w", err)
+			return nil, nil, fmt.Errorf("failed to fetch federated bundle: %w", err)
 		}
 	}
 
-	return bundles, nil
+	return bundles, tainted, nil
+}
+
+// markTaintedAuthorities flags the authorities of the bundle that the server
+// reported as tainted.
+func markTaintedAuthorities(bundle *common.Bundle, tainted *bundleextv1.GetTaintedAuthoritiesResponse) {
+	if tainted == nil {
+		return
+	}
+
+	for _, rootCA := range bundle.RootCas {
+		for _, taintedDER := range tainted.X509Authorities {
+			if bytes.Equal(rootCA.DerBytes, taintedDER) {
+				rootCA.TaintedKey = true
+				break
+			}
+		}
+	}
+	for _, jwtSigningKey := range bundle.JwtSigningKeys {
+		for _, taintedKid := range tainted.JwtAuthorities {
+			if jwtSigningKey.Kid == taintedKid {
+				jwtSigningKey.TaintedKey = true
+				break
+			}
+		}
+	}
 }
 
 func (c *client) fetchSVIDs(ctx context.Context, params []*svidv1.NewX509SVIDParams) ([]*types.X509SVID, error) {
@@ -17,6 +17,7 @@ import (
 	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
 	svidv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/svid/v1"
 	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	bundleextv1 "github.com/spiffe/spire/proto/spire/api/server/bundle/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -195,6 +196,71 @@ func TestFetchUpdates(t *testing.T) {
 	assertConnectionIsNotNil(t, client)
 }
 
+func TestFetchUpdatesMarksTaintedAuthorities(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		tainted      *bundleextv1.GetTaintedAuthoritiesResponse
+		taintedErr   error
+		expectBundle *common.Bundle
+	}{
+		{
+			name: "tainted authorities",
+			tainted: &bundleextv1.GetTaintedAuthoritiesResponse{
+				X509Authorities: [][]byte{{50, 60, 70, 80}},
+				JwtAuthorities:  []string{"tainted-kid"},
+			},
+			expectBundle: &common.Bundle{
+				TrustDomainId: "spiffe://example.org",
+				RootCas: []*common.Certificate{
+					{DerBytes: []byte{10, 20, 30, 40}},
+					{DerBytes: []byte{50, 60, 70, 80}, TaintedKey: true},
+				},
+				JwtSigningKeys: []*common.PublicKey{
+					{Kid: "kid", PkixBytes: []byte{1}},
+					{Kid: "tainted-kid", PkixBytes: []byte{2}, TaintedKey: true},
+				},
+			},
+		},
+		{
+			name:       "server does not implement the bundle extensions",
+			taintedErr: status.Error(codes.Unimplemented, "unknown service"),
+			expectBundle: &common.Bundle{
+				TrustDomainId: "spiffe://example.org",
+				RootCas: []*common.Certificate{
+					{DerBytes: []byte{10, 20, 30, 40}},
+					{DerBytes: []byte{50, 60, 70, 80}},
+				},
+				JwtSigningKeys: []*common.PublicKey{
+					{Kid: "kid", PkixBytes: []byte{1}},
+					{Kid: "tainted-kid", PkixBytes: []byte{2}},
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			client, tc := createClient()
+			tc.bundleClient.agentBundle = &types.Bundle{
+				TrustDomain: "example.org",
+				X509Authorities: []*types.X509Certificate{
+					{Asn1: []byte{10, 20, 30, 40}},
+					{Asn1: []byte{50, 60, 70, 80}},
+				},
+				JwtAuthorities: []*types.JWTKey{
+					{KeyId: "kid", PublicKey: []byte{1}},
+					{KeyId: "tainted-kid", PublicKey: []byte{2}},
+				},
+			}
+			tc.bundleExtensionClient.tainted = tt.tainted
+			tc.bundleExtensionClient.taintedErr = tt.taintedErr
+
+			update, err := client.FetchUpdates(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectBundle, update.Bundles["spiffe://example.org"])
+		})
+	}
+}
+
 func TestRenewSVID(t *testing.T) {
 	client, tc := createClient()
 
@@ -544,6 +610,13 @@ func TestFetchUpdatesReleaseConnectionIfItFailsToFetch(t *testing.T) {
 			},
 			err: "failed to fetch bundle: an error",
 		},
+		{
+			name: "Tainted authorities",
+			setupTest: func(tc *testClient) {
+				tc.bundleExtensionClient.taintedErr = errors.New("an error")
+			},
+			err: "failed to fetch tainted authorities: an error",
+		},
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
@@ -717,10 +790,11 @@ func TestFetchJWTSVID(t *testing.T) {
 // createClient creates a sample client with mocked components for testing purposes
 func createClient() (*client, *testClient) {
 	tc := &testClient{
-		agentClient:  &fakeAgentClient{},
-		bundleClient: &fakeBundleClient{},
-		entryClient:  &fakeEntryClient{},
-		svidClient:   &fakeSVIDClient{},
+		agentClient:           &fakeAgentClient{},
+		bundleClient:          &fakeBundleClient{},
+		bundleExtensionClient: &fakeBundleExtensionsClient{},
+		entryClient:           &fakeEntryClient{},
+		svidClient:            &fakeSVIDClient{},
 	}
 
 	client := newClient(&Config{
@@ -736,6 +810,9 @@ func createClient() (*client, *testClient) {
 	client.createNewBundleClient = func(conn grpc.ClientConnInterface) bundlev1.BundleClient {
 		return tc.bundleClient
 	}
+	client.createNewBundleExtensionsClient = func(conn grpc.ClientConnInterface) bundleextv1.BundleExtensionsClient {
+		return tc.bundleExtensionClient
+	}
 	client.createNewEntryClient = func(conn grpc.ClientConnInterface) entryv1.EntryClient {
 		return tc.entryClient
 	}
@@ -829,6 +906,24 @@ func (c *fakeBundleClient) GetFederatedBundle(_ context.Context, in *bundlev1.Ge
 	return b, nil
 }
 
+type fakeBundleExtensionsClient struct {
+	bundleextv1.BundleExtensionsClient
+
+	tainted    *bundleextv1.GetTaintedAuthoritiesResponse
+	taintedErr error
+}
+
+func (c *fakeBundleExtensionsClient) GetTaintedAuthorities(context.Context, *bundleextv1.GetTaintedAuthoritiesRequest, ...grpc.CallOption) (*bundleextv1.GetTaintedAuthoritiesResponse, error) {
+	if c.taintedErr != nil {
+		return nil, c.taintedErr
+	}
+	if c.tainted == nil {
+		return &bundleextv1.GetTaintedAuthoritiesResponse{}, nil
+	}
+
+	return c.tainted, nil
+}
+
 type fakeSVIDClient struct {
 	svidv1.SVIDClient
 	batchSVIDErr    error
@@ -904,8 +999,9 @@ func (c *fakeAgentClient) RenewAgent(_ context.Context, in *agentv1.RenewAgentRe
 }
 
 type testClient struct {
-	agentClient  *fakeAgentClient
-	bundleClient *fakeBundleClient
-	entryClient  *fakeEntryClient
-	svidClient   *fakeSVIDClient
+	agentClient           *fakeAgentClient
+	bundleClient          *fakeBundleClient
+	bundleExtensionClient *fakeBundleExtensionsClient
+	entryClient           *fakeEntryClient
+	svidClient            *fakeSVIDClient
 }
@@ -0,0 +1,20 @@
+package procfs
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Path returns the path of an entry in the proc filesystem directory of the
+// process with the given pid. The proc filesystem is expected to be mounted
+// at /proc, unless a different location is given through the HOST_PROC
+// environment variable (e.g. when the agent runs in a container with the
+// host proc filesystem mounted elsewhere).
+func Path(pid int32, elem ...string) string {
+	procPath := os.Getenv("HOST_PROC")
+	if procPath == "" {
+		procPath = "/proc"
+	}
+	return filepath.Join(append([]string{procPath, strconv.FormatInt(int64(pid), 10)}, elem...)...)
+}
@@ -0,0 +1,17 @@
+package procfs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	t.Setenv("HOST_PROC", "")
+	require.Equal(t, filepath.Join("/proc", "123", "exe"), Path(123, "exe"))
+	require.Equal(t, filepath.Join("/proc", "123", "ns", "pid"), Path(123, "ns", "pid"))
+
+	t.Setenv("HOST_PROC", "/host/proc")
+	require.Equal(t, filepath.Join("/host/proc", "123", "status"), Path(123, "status"))
+}
@@ -0,0 +1,315 @@
+package sdsv3
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"sort"
+	"strconv"
+
+	core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	secret_v3 "github.com/envoyproxy/go-control-plane/envoy/service/secret/v3"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/spire/pkg/agent/api/rpccontext"
+	"github.com/spiffe/spire/pkg/agent/manager/cache"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// wildcardResourceName is the resource name used by clients to
+	// explicitly subscribe to all resources.
+	wildcardResourceName = "*"
+)
+
+func (h *Handler) DeltaSecrets(stream secret_v3.SecretDiscoveryService_DeltaSecretsServer) error {
+	log := rpccontext.Logger(stream.Context())
+
+	selectors, err := h.c.Attestor.Attest(stream.Context())
+	if err != nil {
+		log.WithError(err).Error("Failed to attest the workload")
+		return err
+	}
+
+	sub, err := h.c.Manager.SubscribeToCacheChanges(stream.Context(), selectors)
+	if err != nil {
+		log.WithError(err).Error("Subscribe to cache changes failed")
+		return err
+	}
+	defer sub.Finish()
+
+	updch := sub.Updates()
+	reqch := make(chan *discovery_v3.DeltaDiscoveryRequest, 1)
+	errch := make(chan error, 1)
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if err != nil {
+				if status.Code(err) == codes.Canceled || errors.Is(err, io.EOF) {
+					err = nil
+				}
+				errch <- err
+				return
+			}
+			reqch <- req
+		}
+	}()
+
+	var versionCounter int64
+	var lastNonce string
+	var upd *cache.WorkloadUpdate
+	var subs *deltaSubscriptions
+	for {
+		select {
+		case newReq := <-reqch:
+			log.WithFields(logrus.Fields{
+				telemetry.ResourceNames: newReq.ResourceNamesSubscribe,
+				telemetry.Nonce:         newReq.ResponseNonce,
+			}).Debug("Received DeltaSecrets request")
+			h.triggerReceivedHook()
+
+			// If there's error detail, always log it
+			if newReq.ErrorDetail != nil {
+				log.WithFields(logrus.Fields{
+					telemetry.Nonce: newReq.ResponseNonce,
+					telemetry.Error: newReq.ErrorDetail.Message,
+				}).Error("Envoy reported errors applying secrets")
+			}
+
+			// Unlike the state-of-the-world protocol, requests only carry
+			// changes to the subscriptions, so they have to be processed
+			// even if the nonce is stale.
+			if newReq.ResponseNonce != "" && newReq.ResponseNonce != lastNonce {
+				log.WithFields(logrus.Fields{
+					telemetry.Nonce:  newReq.ResponseNonce,
+					telemetry.Expect: lastNonce,
+				}).Warn("Received unexpected nonce")
+			}
+
+			// The node and type URL are only guaranteed to be set on the
+			// first request of the stream.
+			if subs == nil {
+				subs = newDeltaSubscriptions(newReq)
+			} else if !subs.update(newReq) {
+				// Plain ACK or NACK; nothing to send.
+				continue
+			}
+
+			if upd == nil {
+				// Workload update has not been received yet, defer sending updates until then
+				continue
+			}
+
+		case upd = <-updch:
+			versionCounter++
+			if subs == nil {
+				// Nothing has been requested yet.
+				continue
+			}
+		case err := <-errch:
+			log.WithError(err).Error("Received error from delta secrets server")
+			return err
+		}
+
+		resp, err := h.buildDeltaResponse(log, strconv.FormatInt(versionCounter, 10), subs, upd)
+		if err != nil {
+			log.WithError(err).Error("Error building delta secrets response")
+			return err
+		}
+		if resp == nil {
+			// None of the subscribed resources changed.
+			continue
+		}
+
+		log.WithFields(logrus.Fields{
+			telemetry.VersionInfo: resp.SystemVersionInfo,
+			telemetry.Nonce:       resp.Nonce,
+			telemetry.Count:       len(resp.Resources),
+		}).Debug("Sending DeltaSecrets response")
+		if err := stream.Send(resp); err != nil {
+			log.WithError(err).Error("Error sending secrets over stream")
+			return err
+		}
+
+		// remember the last nonce
+		lastNonce = resp.Nonce
+	}
+}
+
+// buildDeltaResponse builds a response holding the subscribed resources
+// whose versions differ from the ones known by the client, and the names of
+// the subscribed resources that are no longer (or were never) available to
+// the workload. It returns nil if there is nothing to send.
+func (h *Handler) buildDeltaResponse(log logrus.FieldLogger, systemVersionInfo string, subs *deltaSubscriptions, upd *cache.WorkloadUpdate) (*discovery_v3.DeltaDiscoveryResponse, error) {
+	var resources []secretResource
+	if subs.wildcard {
+		all, _, err := h.buildResources(subs.node, nil, upd)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, all...)
+	}
+
+	var missing map[string]bool
+	if len(subs.names) > 0 {
+		var named []secretResource
+		var err error
+		named, missing, err = h.buildResources(subs.node, sortedNames(subs.names), upd)
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, named...)
+	}
+
+	resp := &discovery_v3.DeltaDiscoveryResponse{
+		TypeUrl:           subs.typeURL,
+		SystemVersionInfo: systemVersionInfo,
+	}
+
+	available := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		if available[resource.name] {
+			// Subscribed both explicitly and through the wildcard
+			continue
+		}
+		available[resource.name] = true
+
+		version := resourceVersion(resource)
+		if knownVersion, ok := subs.versions[resource.name]; ok && knownVersion == version {
+			continue
+		}
+		subs.versions[resource.name] = version
+		resp.Resources = append(resp.Resources, &discovery_v3.Resource{
+			Name:     resource.name,
+			Version:  version,
+			Resource: resource.secret,
+		})
+	}
+
+	// Resources known by the client that are no longer available
+	for name, version := range subs.versions {
+		switch {
+		case available[name]:
+		case !subs.names[name]:
+			resp.RemovedResources = append(resp.RemovedResources, name)
+			delete(subs.versions, name)
+		case version != "":
+			resp.RemovedResources = append(resp.RemovedResources, name)
+			subs.versions[name] = ""
+		}
+	}
+
+	// Explicitly subscribed resources the workload is not authorized for
+	// are reported as removed so the client does not wait for them.
+	if len(missing) > 0 {
+		var unauthorized []string
+		for name := range missing {
+			if _, ok := subs.versions[name]; !ok {
+				unauthorized = append(unauthorized, name)
+				resp.RemovedResources = append(resp.RemovedResources, name)
+				subs.versions[name] = ""
+			}
+		}
+		if len(unauthorized) > 0 {
+			sort.Strings(unauthorized)
+			log.WithField(telemetry.ResourceNames, unauthorized).Warn("Workload is not authorized for the requested identities")
+		}
+	}
+
+	if len(resp.Resources) == 0 && len(resp.RemovedResources) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(resp.RemovedResources)
+
+	nonce, err := nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	resp.Nonce = nonce
+
+	return resp, nil
+}
+
+// deltaSubscriptions tracks the resources a delta stream is subscribed to,
+// and the versions of those resources known by the client.
+type deltaSubscriptions struct {
+	node    *core_v3.Node
+	typeURL string
+
+	// wildcard is true if the client is subscribed to all of the resources
+	// available to the workload.
+	wildcard bool
+
+	// names holds the names of the explicitly subscribed resources.
+	names map[string]bool
+
+	// versions holds the versions of the resources known by the client. An
+	// empty version means that the client was told that the resource does
+	// not exist.
+	versions map[string]string
+}
+
+func newDeltaSubscriptions(req *discovery_v3.DeltaDiscoveryRequest) *deltaSubscriptions {
+	subs := &deltaSubscriptions{
+		node:     req.Node,
+		typeURL:  req.TypeUrl,
+		names:    make(map[string]bool),
+		versions: make(map[string]string),
+		// A first request without any subscriptions is a legacy wildcard
+		// subscription.
+		wildcard: len(req.ResourceNamesSubscribe) == 0,
+	}
+	subs.update(req)
+
+	// Resources the client already has (e.g. when reconnecting) are only
+	// sent if their versions differ.
+	for name, version := range req.InitialResourceVersions {
+		subs.versions[name] = version
+	}
+	return subs
+}
+
+// update applies the subscription changes in the request. It returns true
+// if the subscriptions changed.
+func (s *deltaSubscriptions) update(req *discovery_v3.DeltaDiscoveryRequest) bool {
+	changed := false
+	for _, name := range req.ResourceNamesSubscribe {
+		changed = true
+		if name == wildcardResourceName {
+			s.wildcard = true
+			continue
+		}
+		s.names[name] = true
+		// Always send the current version of resubscribed resources
+		delete(s.versions, name)
+	}
+	for _, name := range req.ResourceNamesUnsubscribe {
+		changed = true
+		if name == wildcardResourceName {
+			s.wildcard = false
+			continue
+		}
+		delete(s.names, name)
+		delete(s.versions, name)
+	}
+	if !s.wildcard {
+		// The client drops the resources it was only subscribed to through
+		// the wildcard.
+		for name := range s.versions {
+			if !s.names[name] {
+				delete(s.versions, name)
+			}
+		}
+	}
+	return changed
+}
+
+func resourceVersion(resource secretResource) string {
+	sum := sha256.Sum256(resource.secret.Value)
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,324 @@
+package sdsv3
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	core_v3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	tls_v3 "github.com/envoyproxy/go-control-plane/envoy/extensions/transport_sockets/tls/v3"
+	discovery_v3 "github.com/envoyproxy/go-control-plane/envoy/service/discovery/v3"
+	secret_v3 "github.com/envoyproxy/go-control-plane/envoy/service/secret/v3"
+	"github.com/spiffe/spire/pkg/agent/manager/cache"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/status"
+)
+
+func TestDeltaSecrets(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test/workload", "spiffe://domain.test"},
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV17,
+		},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.SystemVersionInfo)
+	require.NotEmpty(t, resp.Nonce)
+	require.Empty(t, resp.RemovedResources)
+	requireDeltaSecrets(t, resp, tdValidationContext, workloadTLSCertificate1)
+	bundleVersion := resp.Resources[0].Version
+	workloadVersion := resp.Resources[1].Version
+	require.NotEmpty(t, bundleVersion)
+	require.NotEmpty(t, workloadVersion)
+
+	// Ack the response
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce: resp.Nonce,
+	})
+
+	// Only the rotated SVID is sent
+	test.setWorkloadUpdate(workloadCert2)
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Empty(t, resp.RemovedResources)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate2)
+	require.NotEqual(t, workloadVersion, resp.Resources[0].Version)
+
+	// Subscribing to a new resource only sends that resource
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce:          resp.Nonce,
+		ResourceNamesSubscribe: []string{"spiffe://otherdomain.test"},
+	})
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Empty(t, resp.RemovedResources)
+	requireDeltaSecrets(t, resp, fedValidationContext)
+}
+
+func TestDeltaSecretsRemovedResources(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test/workload", "spiffe://otherdomain.test"},
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV17,
+		},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, fedValidationContext, workloadTLSCertificate1)
+
+	// The workload is no longer federated with otherdomain.test
+	test.manager.SetWorkloadUpdate(&cache.WorkloadUpdate{
+		Identities: []cache.Identity{
+			{
+				Entry: &common.RegistrationEntry{
+					SpiffeId: "spiffe://domain.test/workload",
+				},
+				SVID:       []*x509.Certificate{workloadCert1},
+				PrivateKey: workloadKey,
+			},
+		},
+		Bundle: tdBundle,
+	})
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Empty(t, resp.Resources)
+	require.Equal(t, []string{"spiffe://otherdomain.test"}, resp.RemovedResources)
+
+	// The federated bundle is sent again once available
+	test.setWorkloadUpdate(workloadCert1)
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	require.Empty(t, resp.RemovedResources)
+	requireDeltaSecrets(t, resp, fedValidationContext)
+}
+
+func TestDeltaSecretsUnauthorizedResource(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test/workload", "spiffe://domain.test/other"},
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV17,
+		},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate1)
+	require.Equal(t, []string{"spiffe://domain.test/other"}, resp.RemovedResources)
+
+	// The missing resource is not reported again
+	test.setWorkloadUpdate(workloadCert2)
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate2)
+	require.Empty(t, resp.RemovedResources)
+}
+
+func TestDeltaSecretsWildcard(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	// A first request without subscriptions subscribes to everything
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV18,
+		},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, tdValidationContextSpiffeValidator, fedValidationContextSpiffeValidator, workloadTLSCertificate1)
+
+	// Explicitly subscribing to a default resource sends just that resource
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce:          resp.Nonce,
+		ResourceNamesSubscribe: []string{"default"},
+	})
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate3)
+}
+
+func TestDeltaSecretsInitialResourceVersions(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test/workload"},
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV17,
+		},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate1)
+	workloadVersion := resp.Resources[0].Version
+	require.NoError(t, stream.CloseSend())
+
+	// Reconnect, reporting the version already known
+	stream, err = test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test/workload", "spiffe://domain.test"},
+		InitialResourceVersions: map[string]string{
+			"spiffe://domain.test/workload": workloadVersion,
+		},
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV17,
+		},
+	})
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, tdValidationContext)
+}
+
+func TestDeltaSecretsNack(t *testing.T) {
+	test := setupTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe: []string{"spiffe://domain.test/workload"},
+		Node: &core_v3.Node{
+			UserAgentVersionType: userAgentVersionTypeV17,
+		},
+	})
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate1)
+
+	// Reject the update
+	test.sendDeltaAndWait(stream, &discovery_v3.DeltaDiscoveryRequest{
+		ResponseNonce: resp.Nonce,
+		ErrorDetail:   &status.Status{Message: "OHNO!"},
+	})
+
+	test.setWorkloadUpdate(workloadCert2)
+
+	resp, err = stream.Recv()
+	require.NoError(t, err)
+	requireDeltaSecrets(t, resp, workloadTLSCertificate2)
+}
+
+func TestDeltaSecretsErrInSubscribeToCacheChanges(t *testing.T) {
+	test := setupErrTest(t)
+	defer test.server.Stop()
+
+	stream, err := test.handler.DeltaSecrets(context.Background())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, stream.CloseSend())
+	}()
+
+	resp, err := stream.Recv()
+	require.Error(t, err)
+	require.Nil(t, resp)
+}
+
+func TestDeltaSubscriptions(t *testing.T) {
+	subs := newDeltaSubscriptions(&discovery_v3.DeltaDiscoveryRequest{
+		TypeUrl:                "type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.Secret",
+		ResourceNamesSubscribe: []string{"a", "b"},
+		InitialResourceVersions: map[string]string{
+			"a": "1",
+		},
+	})
+	require.False(t, subs.wildcard)
+	require.Equal(t, map[string]bool{"a": true, "b": true}, subs.names)
+	require.Equal(t, map[string]string{"a": "1"}, subs.versions)
+
+	// ACKs do not change the subscriptions
+	require.False(t, subs.update(&discovery_v3.DeltaDiscoveryRequest{ResponseNonce: "nonce"}))
+
+	require.True(t, subs.update(&discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesSubscribe:   []string{"*"},
+		ResourceNamesUnsubscribe: []string{"a"},
+	}))
+	require.True(t, subs.wildcard)
+	require.Equal(t, map[string]bool{"b": true}, subs.names)
+	require.Empty(t, subs.versions)
+
+	subs.versions["b"] = "2"
+	subs.versions["c"] = "3"
+	require.True(t, subs.update(&discovery_v3.DeltaDiscoveryRequest{
+		ResourceNamesUnsubscribe: []string{"*"},
+	}))
+	require.False(t, subs.wildcard)
+	require.Equal(t, map[string]string{"b": "2"}, subs.versions)
+}
+
+func (h *handlerTest) sendDeltaAndWait(stream secret_v3.SecretDiscoveryService_DeltaSecretsClient, req *discovery_v3.DeltaDiscoveryRequest) {
+	require.NoError(h.t, stream.Send(req))
+	timer := time.NewTimer(time.Second)
+	defer timer.Stop()
+	select {
+	case <-h.received:
+	case <-timer.C:
+		assert.Fail(h.t, "timed out waiting for request to be received")
+	}
+}
+
+func requireDeltaSecrets(t *testing.T, resp *discovery_v3.DeltaDiscoveryResponse, expectedSecrets ...*tls_v3.Secret) {
+	var actualSecrets []*tls_v3.Secret
+	for _, resource := range resp.Resources {
+		secret := new(tls_v3.Secret)
+		require.NoError(t, resource.Resource.UnmarshalTo(secret))
+		require.Equal(t, secret.Name, resource.Name)
+		require.NotEmpty(t, resource.Version)
+		actualSecrets = append(actualSecrets, secret)
+	}
+
+	spiretest.RequireProtoListEqual(t, expectedSecrets, actualSecrets)
+}
@@ -209,10 +209,6 @@ func subListChanged(oldSubs []string, newSubs []string) (b bool) {
 	return false
 }
 
-func (h *Handler) DeltaSecrets(secret_v3.SecretDiscoveryService_DeltaSecretsServer) error {
-	return status.Error(codes.Unimplemented, "Method is not implemented")
-}
-
 func (h *Handler) FetchSecrets(ctx context.Context, req *discovery_v3.DiscoveryRequest) (*discovery_v3.DiscoveryResponse, error) {
 	log := rpccontext.Logger(ctx).WithField(telemetry.ResourceNames, req.ResourceNames)
 
@@ -250,18 +246,45 @@ func (h *Handler) buildResponse(versionInfo string, req *discovery_v3.DiscoveryR
 		}
 	}
 
+	resources, missing, err := h.buildResources(req.Node, req.ResourceNames, upd)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "workload is not authorized for the requested identities %q", sortedNames(missing))
+	}
+
+	for _, resource := range resources {
+		resp.Resources = append(resp.Resources, resource.secret)
+	}
+
+	return resp, nil
+}
+
+// secretResource is a named secret resource.
+type secretResource struct {
+	name   string
+	secret *anypb.Any
+}
+
+// buildResources builds the secret resources with the given names out of
+// the workload update. If no names are given, all of the resources available
+// to the workload are built. The names of the requested resources that are
+// not available to the workload are returned as missing.
+func (h *Handler) buildResources(node *core_v3.Node, resourceNames []string, upd *cache.WorkloadUpdate) (resources []secretResource, missing map[string]bool, err error) {
 	// build a convenient set of names for lookups
 	names := make(map[string]bool)
-	for _, name := range req.ResourceNames {
+	for _, name := range resourceNames {
 		if name != "" {
 			names[name] = true
 		}
 	}
 	returnAllEntries := len(names) == 0
 
-	builder, err := h.getValidationContextBuilder(req, upd)
+	builder, err := h.getValidationContextBuilder(node, upd)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// TODO: verify the type url
@@ -270,29 +293,29 @@ func (h *Handler) buildResponse(versionInfo string, req *discovery_v3.DiscoveryR
 		case returnAllEntries || names[upd.Bundle.TrustDomain().IDString()]:
 			validationContext, err := builder.buildOne(upd.Bundle.TrustDomain().IDString(), upd.Bundle.TrustDomain().IDString())
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			delete(names, upd.Bundle.TrustDomain().IDString())
-			resp.Resources = append(resp.Resources, validationContext)
+			resources = append(resources, secretResource{name: upd.Bundle.TrustDomain().IDString(), secret: validationContext})
 
 		case names[h.c.DefaultBundleName]:
 			validationContext, err := builder.buildOne(h.c.DefaultBundleName, upd.Bundle.TrustDomain().IDString())
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			delete(names, h.c.DefaultBundleName)
-			resp.Resources = append(resp.Resources, validationContext)
+			resources = append(resources, secretResource{name: h.c.DefaultBundleName, secret: validationContext})
 
 		case names[h.c.DefaultAllBundlesName]:
 			validationContext, err := builder.buildAll(h.c.DefaultAllBundlesName)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			delete(names, h.c.DefaultAllBundlesName)
-			resp.Resources = append(resp.Resources, validationContext)
+			resources = append(resources, secretResource{name: h.c.DefaultAllBundlesName, secret: validationContext})
 		}
 	}
 
@@ -300,10 +323,10 @@ func (h *Handler) buildResponse(versionInfo string, req *discovery_v3.DiscoveryR
 		if returnAllEntries || names[federatedBundle.TrustDomain().IDString()] {
 			validationContext, err := builder.buildOne(td.IDString(), td.IDString())
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			delete(names, federatedBundle.TrustDomain().IDString())
-			resp.Resources = append(resp.Resources, validationContext)
+			resources = append(resources, secretResource{name: td.IDString(), secret: validationContext})
 		}
 	}
 
@@ -312,25 +335,21 @@ func (h *Handler) buildResponse(versionInfo string, req *discovery_v3.DiscoveryR
 		case returnAllEntries || names[identity.Entry.SpiffeId]:
 			tlsCertificate, err := buildTLSCertificate(identity, "")
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			delete(names, identity.Entry.SpiffeId)
-			resp.Resources = append(resp.Resources, tlsCertificate)
+			resources = append(resources, secretResource{name: identity.Entry.SpiffeId, secret: tlsCertificate})
 		case i == 0 && names[h.c.DefaultSVIDName]:
 			tlsCertificate, err := buildTLSCertificate(identity, h.c.DefaultSVIDName)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			delete(names, h.c.DefaultSVIDName)
-			resp.Resources = append(resp.Resources, tlsCertificate)
+			resources = append(resources, secretResource{name: h.c.DefaultSVIDName, secret: tlsCertificate})
 		}
 	}
 
-	if len(names) > 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "workload is not authorized for the requested identities %q", sortedNames(names))
-	}
-
-	return resp, nil
+	return resources, names, nil
 }
 
 func (h *Handler) triggerReceivedHook() {
@@ -344,12 +363,12 @@ type validationContextBuilder interface {
 	buildAll(resourceName string) (*any.Any, error)
 }
 
-func (h *Handler) getValidationContextBuilder(req *discovery_v3.DiscoveryRequest, upd *cache.WorkloadUpdate) (validationContextBuilder, error) {
+func (h *Handler) getValidationContextBuilder(node *core_v3.Node, upd *cache.WorkloadUpdate) (validationContextBuilder, error) {
 	federatedBundles := make(map[spiffeid.TrustDomain]*spiffebundle.Bundle)
 	for td, federatedBundle := range upd.FederatedBundles {
 		federatedBundles[td] = federatedBundle
 	}
-	if !h.isSPIFFECertValidationDisabled(req) && supportsSPIFFEAuthExtension(req) {
+	if !h.isSPIFFECertValidationDisabled(node) && supportsSPIFFEAuthExtension(node) {
 		return newSpiffeBuilder(upd.Bundle, federatedBundles)
 	}
 
@@ -504,17 +523,17 @@ func (b *spiffeBuilder) buildAll(resourceName string) (*any.Any, error) {
 	})
 }
 
-func supportsSPIFFEAuthExtension(req *discovery_v3.DiscoveryRequest) bool {
-	if buildVersion := req.Node.GetUserAgentBuildVersion(); buildVersion != nil {
+func supportsSPIFFEAuthExtension(node *core_v3.Node) bool {
+	if buildVersion := node.GetUserAgentBuildVersion(); buildVersion != nil {
 		version := buildVersion.Version
 		return (version.MajorNumber == 1 && version.MinorNumber > 17) || version.MajorNumber > 1
 	}
 	return false
 }
 
-func (h *Handler) isSPIFFECertValidationDisabled(req *discovery_v3.DiscoveryRequest) bool {
+func (h *Handler) isSPIFFECertValidationDisabled(node *core_v3.Node) bool {
 	disabled := h.c.DisableSPIFFECertValidation
-	if v, ok := req.Node.GetMetadata().GetFields()[disableSPIFFECertValidationKey]; ok {
+	if v, ok := node.GetMetadata().GetFields()[disableSPIFFECertValidationKey]; ok {
 		// error means that field have some unexpected value
 		// so it would be safer to assume that key doesn't exist in envoy node metadata
 		if override, err := parseBool(v); err == nil {
@@ -44,6 +44,10 @@ type UpdateEntries struct {
 	// RegistrationEntries is a set of ALL registration entries available to the
 	// agent, keyed by registration entry id.
 	RegistrationEntries map[string]*common.RegistrationEntry
+
+	// TaintedX509Authorities is a set of the X.509 authorities of the agent
+	// trust domain bundle that have been marked as tainted
+	TaintedX509Authorities []*x509.Certificate
 }
 
 // Update holds information for an SVIDs update to the cache.
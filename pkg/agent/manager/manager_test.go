@@ -27,12 +27,14 @@ import (
 	"github.com/spiffe/spire/pkg/agent/manager/storecache"
 	"github.com/spiffe/spire/pkg/agent/plugin/keymanager"
 	"github.com/spiffe/spire/pkg/agent/storage"
+	"github.com/spiffe/spire/pkg/agent/svid"
 	"github.com/spiffe/spire/pkg/agent/workloadkey"
 	"github.com/spiffe/spire/pkg/common/bundleutil"
 	"github.com/spiffe/spire/pkg/common/idutil"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/x509util"
 	"github.com/spiffe/spire/pkg/server/api"
+	bundleextv1 "github.com/spiffe/spire/proto/spire/api/server/bundle/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/fakes/fakeagentcatalog"
@@ -764,6 +766,77 @@ func TestSynchronizationUpdatesRegistrationEntries(t *testing.T) {
 		m.cache.Entries())
 }
 
+func TestSynchronizationRenewsSVIDsSignedByTaintedAuthority(t *testing.T) {
+	dir := spiretest.TempDir(t)
+	km := fakeagentkeymanager.New(t, dir)
+
+	clk := clock.NewMock(t)
+	api := newMockAPI(t, &mockAPIConfig{
+		km: km,
+		getAuthorizedEntries: func(*mockAPI, int32, *entryv1.GetAuthorizedEntriesRequest) (*entryv1.GetAuthorizedEntriesResponse, error) {
+			return makeGetAuthorizedEntriesResponse(t, "resp1", "resp2"), nil
+		},
+		batchNewX509SVIDEntries: func(*mockAPI, int32) []*common.RegistrationEntry {
+			return makeBatchNewX509SVIDEntries("resp1", "resp2")
+		},
+		svidTTL: 3600,
+		clk:     clk,
+	})
+
+	baseSVID, baseSVIDKey := api.newSVID(joinTokenID, 1*time.Hour)
+	cat := fakeagentcatalog.New()
+	cat.SetKeyManager(km)
+
+	c := &Config{
+		ServerAddr:       api.addr,
+		SVID:             baseSVID,
+		SVIDKey:          baseSVIDKey,
+		Log:              testLogger,
+		TrustDomain:      trustDomain,
+		Storage:          openStorage(t, dir),
+		Bundle:           api.bundle,
+		Metrics:          &telemetry.Blackhole{},
+		RotationInterval: time.Hour,
+		SyncInterval:     time.Hour,
+		Clk:              clk,
+		Catalog:          cat,
+		WorkloadKeyType:  workloadkey.ECP256,
+		SVIDStoreCache:   storecache.New(&storecache.Config{TrustDomain: trustDomain, Log: testLogger}),
+	}
+
+	m := newManager(c)
+	require.NoError(t, m.Initialize(context.Background()))
+
+	// All the identities are signed by the current CA
+	taintedCA := api.ca
+	identitiesBefore := identitiesByEntryID(m.cache.Identities())
+	require.Len(t, identitiesBefore, 3)
+	for _, identity := range identitiesBefore {
+		require.NoError(t, identity.SVID[len(identity.SVID)-1].CheckSignatureFrom(taintedCA))
+	}
+
+	// Rotate the CA and taint the previous one
+	api.rotateCA()
+	rotator := &fakeTaintRotator{Rotator: m.svid}
+	m.svid = rotator
+	api.taintedAuthorities = []*x509.Certificate{taintedCA}
+
+	require.NoError(t, m.synchronize(context.Background()))
+
+	// The agent SVID rotator is notified about the tainted authority
+	require.Len(t, rotator.taintedAuthorities, 1)
+	require.True(t, rotator.taintedAuthorities[0].Equal(taintedCA))
+
+	// All the identities are renewed and signed by the new CA, even though
+	// they are not close to expiration
+	identitiesAfter := identitiesByEntryID(m.cache.Identities())
+	require.Len(t, identitiesAfter, 3)
+	for id, identity := range identitiesAfter {
+		require.NotEqual(t, identitiesBefore[id].SVID, identity.SVID)
+		require.NoError(t, identity.SVID[len(identity.SVID)-1].CheckSignatureFrom(api.ca))
+	}
+}
+
 func TestSubscribersGetUpToDateBundle(t *testing.T) {
 	dir := spiretest.TempDir(t)
 	km := fakeagentkeymanager.New(t, dir)
@@ -1415,6 +1488,9 @@ type mockAPI struct {
 
 	svid []*x509.Certificate
 
+	// Authorities reported as tainted to the agents
+	taintedAuthorities []*x509.Certificate
+
 	// Counts the number of requests received from clients
 	getAuthorizedEntriesCount int32
 	batchNewX509SVIDCount     int32
@@ -1426,6 +1502,7 @@ type mockAPI struct {
 
 	agentv1.UnimplementedAgentServer
 	bundlev1.UnimplementedBundleServer
+	bundleextv1.UnimplementedBundleExtensionsServer
 	entryv1.UnimplementedEntryServer
 	svidv1.UnimplementedSVIDServer
 }
@@ -1455,6 +1532,7 @@ func newMockAPI(t *testing.T, config *mockAPIConfig) *mockAPI {
 	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(tlsConfig)))
 	agentv1.RegisterAgentServer(server, h)
 	bundlev1.RegisterBundleServer(server, h)
+	bundleextv1.RegisterBundleExtensionsServer(server, h)
 	entryv1.RegisterEntryServer(server, h)
 	svidv1.RegisterSVIDServer(server, h)
 
@@ -1550,6 +1628,14 @@ func (h *mockAPI) GetFederatedBundle(_ context.Context, req *bundlev1.GetFederat
 	}, nil
 }
 
+func (h *mockAPI) GetTaintedAuthorities(context.Context, *bundleextv1.GetTaintedAuthoritiesRequest) (*bundleextv1.GetTaintedAuthoritiesResponse, error) {
+	resp := &bundleextv1.GetTaintedAuthoritiesResponse{}
+	for _, authority := range h.taintedAuthorities {
+		resp.X509Authorities = append(resp.X509Authorities, authority.Raw)
+	}
+	return resp, nil
+}
+
 func (h *mockAPI) rotateCA() {
 	ca, caKey := createCA(h.t, h.clk)
 	h.ca = ca
@@ -1707,3 +1793,14 @@ func openStorage(t *testing.T, dir string) storage.Storage {
 	require.NoError(t, err)
 	return sto
 }
+
+// fakeTaintRotator records the tainted authorities the rotator is notified
+// about.
+type fakeTaintRotator struct {
+	svid.Rotator
+	taintedAuthorities []*x509.Certificate
+}
+
+func (r *fakeTaintRotator) NotifyTaintedAuthorities(taintedAuthorities []*x509.Certificate) {
+	r.taintedAuthorities = append(r.taintedAuthorities, taintedAuthorities...)
+}
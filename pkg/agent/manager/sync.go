@@ -15,6 +15,7 @@ import (
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	telemetry_agent "github.com/spiffe/spire/pkg/common/telemetry/agent"
 	"github.com/spiffe/spire/pkg/common/util"
+	"github.com/spiffe/spire/pkg/common/x509util"
 	"github.com/spiffe/spire/proto/spire/common"
 )
 
@@ -52,6 +53,11 @@ func (m *manager) synchronize(ctx context.Context) (err error) {
 		return err
 	}
 
+	// Rotate the agent SVID right away if it was signed by a tainted authority
+	if len(cacheUpdate.TaintedX509Authorities) > 0 {
+		m.svid.NotifyTaintedAuthorities(cacheUpdate.TaintedX509Authorities)
+	}
+
 	if err := m.updateCache(ctx, cacheUpdate, m.c.Log.WithField(telemetry.CacheType, "workload"), "", m.cache); err != nil {
 		return err
 	}
@@ -72,6 +78,7 @@ func (m *manager) updateCache(ctx context.Context, update *cache.UpdateEntries,
 	// the values in `update` now belong to the cache. DO NOT MODIFY.
 	var expiring int
 	var outdated int
+	var tainted int
 	c.UpdateEntries(update, func(existingEntry, newEntry *common.RegistrationEntry, svid *cache.X509SVID) bool {
 		switch {
 		case svid == nil:
@@ -82,6 +89,9 @@ func (m *manager) updateCache(ctx context.Context, update *cache.UpdateEntries,
 				telemetry.RegistrationID: newEntry.EntryId,
 				telemetry.SPIFFEID:       newEntry.SpiffeId,
 			}).Warn("cached X509 SVID is empty")
+		case x509util.IsSignedByRoot(svid.Chain, update.TaintedX509Authorities):
+			// SVID was signed by a tainted authority
+			tainted++
 		case rotationutil.ShouldRotateX509(m.c.Clk.Now(), svid.Chain[0]):
 			expiring++
 		case existingEntry != nil && existingEntry.RevisionNumber != newEntry.RevisionNumber:
@@ -104,6 +114,10 @@ func (m *manager) updateCache(ctx context.Context, update *cache.UpdateEntries,
 		telemetry_agent.AddCacheManagerOutdatedSVIDsSample(m.c.Metrics, cacheType, float32(outdated))
 		log.WithField(telemetry.OutdatedSVIDs, outdated).Debug("Updating SVIDs with outdated attributes in cache")
 	}
+	if tainted > 0 {
+		telemetry_agent.AddCacheManagerTaintedSVIDsSample(m.c.Metrics, cacheType, float32(tainted))
+		log.WithField(telemetry.TaintedSVIDs, tainted).Info("Updating SVIDs signed by a tainted authority in cache")
+	}
 
 	return m.updateSVIDs(ctx, log, c)
 }
@@ -228,6 +242,11 @@ func (m *manager) fetchEntries(ctx context.Context) (_ *cache.UpdateEntries, _ *
 		return nil, nil, err
 	}
 
+	taintedX509Authorities, err := parseTaintedX509Authorities(update.Bundles[m.c.TrustDomain.IDString()])
+	if err != nil {
+		return nil, nil, err
+	}
+
 	cacheEntries := make(map[string]*common.RegistrationEntry)
 	storeEntries := make(map[string]*common.RegistrationEntry)
 
@@ -241,11 +260,13 @@ func (m *manager) fetchEntries(ctx context.Context) (_ *cache.UpdateEntries, _ *
 	}
 
 	return &cache.UpdateEntries{
-			Bundles:             bundles,
-			RegistrationEntries: cacheEntries,
+			Bundles:                bundles,
+			RegistrationEntries:    cacheEntries,
+			TaintedX509Authorities: taintedX509Authorities,
 		}, &cache.UpdateEntries{
-			Bundles:             bundles,
-			RegistrationEntries: storeEntries,
+			Bundles:                bundles,
+			RegistrationEntries:    storeEntries,
+			TaintedX509Authorities: taintedX509Authorities,
 		}, nil
 }
 
@@ -277,3 +298,20 @@ func parseBundles(bundles map[string]*common.Bundle) (map[spiffeid.TrustDomain]*
 	}
 	return out, nil
 }
+
+// parseTaintedX509Authorities returns the X.509 authorities of the given
+// bundle that have been marked as tainted.
+func parseTaintedX509Authorities(bundle *common.Bundle) ([]*x509.Certificate, error) {
+	var taintedX509Authorities []*x509.Certificate
+	for _, rootCA := range bundle.GetRootCas() {
+		if !rootCA.TaintedKey {
+			continue
+		}
+		cert, err := x509.ParseCertificate(rootCA.DerBytes)
+		if err != nil {
+			return nil, err
+		}
+		taintedX509Authorities = append(taintedX509Authorities, cert)
+	}
+	return taintedX509Authorities, nil
+}
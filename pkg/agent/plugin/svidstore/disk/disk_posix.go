@@ -0,0 +1,363 @@
+//go:build !windows
+// +build !windows
+
+package disk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/token"
+	svidstorev1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/svidstore/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/diskutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultCertFileMode = 0644
+	defaultKeyFileMode  = 0600
+
+	defaultSVIDFileName             = "svid.pem"
+	defaultSVIDKeyFileName          = "svid_key.pem"
+	defaultSVIDBundleFileName       = "svid_bundle.pem"
+	defaultFederatedBundlesFileName = "federated_bundles.pem"
+)
+
+func builtin(p *DiskPlugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		svidstorev1.SVIDStorePluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+type Configuration struct {
+	// Directory is the base directory where the SVIDs are stored. The
+	// directories set through the `dir` selector are relative to it.
+	Directory string `hcl:"directory" json:"directory"`
+
+	// CertFileMode is the octal mode of the certificate chain and bundle
+	// files (default: "0644").
+	CertFileMode string `hcl:"cert_file_mode" json:"cert_file_mode"`
+
+	// KeyFileMode is the octal mode of the private key file (default: "0600").
+	KeyFileMode string `hcl:"key_file_mode" json:"key_file_mode"`
+
+	// FileOwner is the name or ID of the user that owns the stored files
+	// (default: the agent user).
+	FileOwner string `hcl:"file_owner" json:"file_owner"`
+
+	// FileGroup is the name or ID of the group that owns the stored files
+	// (default: the agent group).
+	FileGroup string `hcl:"file_group" json:"file_group"`
+
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions" json:",omitempty"`
+}
+
+type diskConfig struct {
+	directory    string
+	certFileMode os.FileMode
+	keyFileMode  os.FileMode
+	uid          int
+	gid          int
+}
+
+type DiskPlugin struct {
+	svidstorev1.UnsafeSVIDStoreServer
+	configv1.UnsafeConfigServer
+
+	log    hclog.Logger
+	mtx    sync.RWMutex
+	config *diskConfig
+
+	hooks struct {
+		lookupUser  func(string) (*user.User, error)
+		lookupGroup func(string) (*user.Group, error)
+	}
+}
+
+func New() *DiskPlugin {
+	p := &DiskPlugin{}
+	p.hooks.lookupUser = user.Lookup
+	p.hooks.lookupGroup = user.LookupGroup
+	return p
+}
+
+func (p *DiskPlugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+// Configure configures the DiskPlugin.
+func (p *DiskPlugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	// Parse HCL config payload into config struct
+	config := &Configuration{}
+	if err := hcl.Decode(config, req.HclConfiguration); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if len(config.UnusedKeyPositions) != 0 {
+		var keys []string
+		for k := range config.UnusedKeyPositions {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		return nil, status.Errorf(codes.InvalidArgument, "unknown configurations detected: %s", strings.Join(keys, ","))
+	}
+
+	if config.Directory == "" {
+		return nil, status.Error(codes.InvalidArgument, "directory is required")
+	}
+	if !filepath.IsAbs(config.Directory) {
+		return nil, status.Errorf(codes.InvalidArgument, "directory must be an absolute path: %q", config.Directory)
+	}
+
+	certFileMode, err := parseFileMode(config.CertFileMode, defaultCertFileMode)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cert_file_mode: %v", err)
+	}
+
+	keyFileMode, err := parseFileMode(config.KeyFileMode, defaultKeyFileMode)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid key_file_mode: %v", err)
+	}
+
+	uid, err := p.lookupUID(config.FileOwner)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid file_owner: %v", err)
+	}
+
+	gid, err := p.lookupGID(config.FileGroup)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid file_group: %v", err)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.config = &diskConfig{
+		directory:    filepath.Clean(config.Directory),
+		certFileMode: certFileMode,
+		keyFileMode:  keyFileMode,
+		uid:          uid,
+		gid:          gid,
+	}
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+// PutX509SVID writes the specified X509-SVID to the files described by the
+// entry selectors
+func (p *DiskPlugin) PutX509SVID(_ context.Context, req *svidstorev1.PutX509SVIDRequest) (*svidstorev1.PutX509SVIDResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filesFromMetadata(config.directory, req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	secretData, err := svidstore.SecretFromProto(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse request: %v", err)
+	}
+
+	if err := os.MkdirAll(files.dir, 0755); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create directory: %v", err)
+	}
+
+	if err := config.writeFile(files.bundle, secretData.Bundle, config.certFileMode); err != nil {
+		return nil, err
+	}
+
+	if len(secretData.FederatedBundles) > 0 {
+		if err := config.writeFile(files.federatedBundles, svidstore.ConcatFederatedBundles(secretData.FederatedBundles), config.certFileMode); err != nil {
+			return nil, err
+		}
+	} else if err := removeFile(files.federatedBundles); err != nil {
+		// Remove federated bundles written while the entry federated with
+		// other trust domains
+		return nil, err
+	}
+
+	if err := config.writeFile(files.svidKey, secretData.X509SVIDKey, config.keyFileMode); err != nil {
+		return nil, err
+	}
+
+	if err := config.writeFile(files.svid, secretData.X509SVID, config.certFileMode); err != nil {
+		return nil, err
+	}
+
+	p.log.With("spiffe_id", secretData.SPIFFEID).With("dir", files.dir).Debug("SVID stored")
+	return &svidstorev1.PutX509SVIDResponse{}, nil
+}
+
+// DeleteX509SVID removes the files described by the entry selectors
+func (p *DiskPlugin) DeleteX509SVID(_ context.Context, req *svidstorev1.DeleteX509SVIDRequest) (*svidstorev1.DeleteX509SVIDResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filesFromMetadata(config.directory, req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range []string{files.svid, files.svidKey, files.bundle, files.federatedBundles} {
+		if err := removeFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	p.log.With("dir", files.dir).Debug("SVID deleted")
+	return &svidstorev1.DeleteX509SVIDResponse{}, nil
+}
+
+func (p *DiskPlugin) getConfig() (*diskConfig, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	if p.config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, nil
+}
+
+// lookupUID returns the ID of the given user name or ID, or -1 to keep the
+// owner of the files unchanged.
+func (p *DiskPlugin) lookupUID(owner string) (int, error) {
+	if owner == "" {
+		return -1, nil
+	}
+	if uid, err := strconv.Atoi(owner); err == nil {
+		return uid, nil
+	}
+
+	u, err := p.hooks.lookupUser(owner)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(u.Uid)
+}
+
+// lookupGID returns the ID of the given group name or ID, or -1 to keep the
+// group of the files unchanged.
+func (p *DiskPlugin) lookupGID(group string) (int, error) {
+	if group == "" {
+		return -1, nil
+	}
+	if gid, err := strconv.Atoi(group); err == nil {
+		return gid, nil
+	}
+
+	g, err := p.hooks.lookupGroup(group)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(g.Gid)
+}
+
+func (c *diskConfig) writeFile(path string, data string, mode os.FileMode) error {
+	if err := diskutil.AtomicWriteFileWithOwner(path, []byte(data), mode, c.uid, c.gid); err != nil {
+		return status.Errorf(codes.Internal, "failed to write %q: %v", path, err)
+	}
+	return nil
+}
+
+type svidFiles struct {
+	dir              string
+	svid             string
+	svidKey          string
+	bundle           string
+	federatedBundles string
+}
+
+// filesFromMetadata returns the paths of the files where the SVID is stored,
+// which must be within the configured base directory.
+func filesFromMetadata(baseDir string, metadata []string) (*svidFiles, error) {
+	data, err := svidstore.ParseMetadata(metadata)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid metadata: %v", err)
+	}
+
+	dir, ok := data["dir"]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "dir is required")
+	}
+	if !filepath.IsLocal(dir) {
+		return nil, status.Errorf(codes.InvalidArgument, "dir must be a relative path within the base directory: %q", dir)
+	}
+	dir = filepath.Join(baseDir, dir)
+
+	fileName := func(key, defaultName string) (string, error) {
+		name, ok := data[key]
+		if !ok {
+			return filepath.Join(dir, defaultName), nil
+		}
+		if !filepath.IsLocal(name) || filepath.Base(name) != name {
+			return "", status.Errorf(codes.InvalidArgument, "%s must be a file name: %q", key, name)
+		}
+		return filepath.Join(dir, name), nil
+	}
+
+	files := &svidFiles{dir: dir}
+	if files.svid, err = fileName("svid_file_name", defaultSVIDFileName); err != nil {
+		return nil, err
+	}
+	if files.svidKey, err = fileName("svid_key_file_name", defaultSVIDKeyFileName); err != nil {
+		return nil, err
+	}
+	if files.bundle, err = fileName("svid_bundle_file_name", defaultSVIDBundleFileName); err != nil {
+		return nil, err
+	}
+	if files.federatedBundles, err = fileName("federated_bundles_file_name", defaultFederatedBundlesFileName); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	for _, path := range []string{files.svid, files.svidKey, files.bundle, files.federatedBundles} {
+		if seen[path] {
+			return nil, status.Errorf(codes.InvalidArgument, "file names must be unique: %q", filepath.Base(path))
+		}
+		seen[path] = true
+	}
+
+	return files, nil
+}
+
+func parseFileMode(s string, defaultMode os.FileMode) (os.FileMode, error) {
+	if s == "" {
+		return defaultMode, nil
+	}
+
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil {
+		return 0, err
+	}
+	if mode > 0777 {
+		return 0, errors.New("only permission bits are allowed")
+	}
+	return os.FileMode(mode), nil
+}
+
+func removeFile(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return status.Errorf(codes.Internal, "failed to remove %q: %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package disk
+
+import (
+	"testing"
+
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"google.golang.org/grpc/codes"
+)
+
+func TestConfigure(t *testing.T) {
+	var err error
+	ss := new(svidstore.V1)
+	plugintest.Load(t, BuiltIn(), ss,
+		plugintest.CaptureConfigureError(&err),
+		plugintest.Configure(`directory = "C:\\spire\\svids"`))
+	spiretest.RequireGRPCStatusContains(t, err, codes.Unimplemented, "plugin not supported in this platform")
+}
@@ -0,0 +1,317 @@
+package k8ssecret
+
+import (
+	"context"
+	"crypto/sha1" //nolint: gosec // We use sha1 to hash trust domain names in 128 bytes to avoid label value restrictions
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/token"
+	svidstorev1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/svidstore/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	pluginName = "k8s_secret"
+
+	// managedByLabel is the label set on the secrets managed by the plugin.
+	// Its value is the hashed trust domain name of the SPIRE deployment.
+	managedByLabel = "spiffe.io/spire-svid"
+
+	// spiffeIDAnnotation is the annotation holding the SPIFFE ID of the
+	// stored SVID.
+	spiffeIDAnnotation = "spiffe.io/spiffe-id"
+
+	// caCertKey is the key of the trust domain bundle in the secret.
+	caCertKey = "ca.crt"
+
+	// federatedCACertKey is the key of the federated bundles in the secret.
+	federatedCACertKey = "federated-ca.crt"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *SecretPlugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		svidstorev1.SVIDStorePluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+func New() *SecretPlugin {
+	return newPlugin(newKubeClient)
+}
+
+func newPlugin(newClient func(kubeConfigFilePath string) (kubernetes.Interface, error)) *SecretPlugin {
+	p := &SecretPlugin{}
+	p.hooks.newClient = newClient
+
+	return p
+}
+
+type Configuration struct {
+	// KubeConfigFilePath is the path to the kubeconfig file used to connect
+	// to the cluster. If unset, the in-cluster configuration is used.
+	KubeConfigFilePath string                 `hcl:"kube_config_file_path" json:"kube_config_file_path"`
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions" json:",omitempty"`
+}
+
+type SecretPlugin struct {
+	svidstorev1.UnsafeSVIDStoreServer
+	configv1.UnsafeConfigServer
+
+	log    hclog.Logger
+	mtx    sync.RWMutex
+	client kubernetes.Interface
+	tdHash string
+
+	hooks struct {
+		newClient func(kubeConfigFilePath string) (kubernetes.Interface, error)
+	}
+}
+
+func (p *SecretPlugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+// Configure configures the SecretPlugin.
+func (p *SecretPlugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	// Parse HCL config payload into config struct
+	config := &Configuration{}
+	if err := hcl.Decode(config, req.HclConfiguration); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if len(config.UnusedKeyPositions) != 0 {
+		var keys []string
+		for k := range config.UnusedKeyPositions {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		return nil, status.Errorf(codes.InvalidArgument, "unknown configurations detected: %s", strings.Join(keys, ","))
+	}
+
+	client, err := p.hooks.newClient(config.KubeConfigFilePath)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create kubernetes client: %v", err)
+	}
+
+	// Label values are limited to 63 characters, hash td as label
+	tdHash := sha1.Sum([]byte(req.CoreConfiguration.TrustDomain)) //nolint: gosec // We use sha1 to hash trust domain names in 128 bytes to avoid label value restrictions
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.client = client
+	p.tdHash = hex.EncodeToString(tdHash[:])
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+// PutX509SVID creates or updates the kubernetes.io/tls secret described by
+// the entry selectors with the specified X509-SVID
+func (p *SecretPlugin) PutX509SVID(ctx context.Context, req *svidstorev1.PutX509SVIDRequest) (*svidstorev1.PutX509SVIDResponse, error) {
+	client, tdHash, err := p.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	opt, err := optionsFromSecretData(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	secretData, err := svidstore.SecretFromProto(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse request: %v", err)
+	}
+
+	data := map[string][]byte{
+		corev1.TLSCertKey:       []byte(secretData.X509SVID),
+		corev1.TLSPrivateKeyKey: []byte(secretData.X509SVIDKey),
+		caCertKey:               []byte(secretData.Bundle),
+	}
+	if len(secretData.FederatedBundles) > 0 {
+		data[federatedCACertKey] = []byte(svidstore.ConcatFederatedBundles(secretData.FederatedBundles))
+	}
+
+	secret, found, err := getSecret(ctx, client, opt, tdHash)
+	if err != nil {
+		return nil, err
+	}
+
+	log := p.log.With("namespace", opt.namespace).With("secret_name", opt.name)
+
+	// Secret not found, create it
+	if !found {
+		if _, err := client.CoreV1().Secrets(opt.namespace).Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      opt.name,
+				Namespace: opt.namespace,
+				Labels: map[string]string{
+					managedByLabel: tdHash,
+				},
+				Annotations: map[string]string{
+					spiffeIDAnnotation: secretData.SPIFFEID,
+				},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}, metav1.CreateOptions{}); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create secret: %v", err)
+		}
+
+		log.Debug("Secret created")
+		return &svidstorev1.PutX509SVIDResponse{}, nil
+	}
+
+	// The update is rejected if the secret changed since it was read
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[spiffeIDAnnotation] = secretData.SPIFFEID
+	secret.Data = data
+	if _, err := client.CoreV1().Secrets(opt.namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update secret: %v", err)
+	}
+
+	log.Debug("Secret updated")
+	return &svidstorev1.PutX509SVIDResponse{}, nil
+}
+
+// DeleteX509SVID deletes the secret described by the entry selectors
+func (p *SecretPlugin) DeleteX509SVID(ctx context.Context, req *svidstorev1.DeleteX509SVIDRequest) (*svidstorev1.DeleteX509SVIDResponse, error) {
+	client, tdHash, err := p.getClient()
+	if err != nil {
+		return nil, err
+	}
+
+	opt, err := optionsFromSecretData(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	log := p.log.With("namespace", opt.namespace).With("secret_name", opt.name)
+
+	secret, found, err := getSecret(ctx, client, opt, tdHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		log.Debug("Secret to delete not found")
+		return &svidstorev1.DeleteX509SVIDResponse{}, nil
+	}
+
+	// Make sure that the secret that is deleted is the one that was validated
+	err = client.CoreV1().Secrets(opt.namespace).Delete(ctx, opt.name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{
+			UID:             &secret.UID,
+			ResourceVersion: &secret.ResourceVersion,
+		},
+	})
+	switch {
+	case k8serrors.IsNotFound(err):
+		log.Debug("Secret to delete not found")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to delete secret: %v", err)
+	default:
+		log.Debug("Secret deleted")
+	}
+
+	return &svidstorev1.DeleteX509SVIDResponse{}, nil
+}
+
+func (p *SecretPlugin) getClient() (kubernetes.Interface, string, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	if p.client == nil {
+		return nil, "", status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.client, p.tdHash, nil
+}
+
+// getSecret gets the secret from the cluster and validates that it is a TLS
+// secret with the `spiffe.io/spire-svid` label set to the hashed trust domain
+// as value, false if not found
+func getSecret(ctx context.Context, client kubernetes.Interface, opt *secretOptions, tdHash string) (*corev1.Secret, bool, error) {
+	secret, err := client.CoreV1().Secrets(opt.namespace).Get(ctx, opt.name, metav1.GetOptions{})
+	switch {
+	case err == nil:
+	case k8serrors.IsNotFound(err):
+		return nil, false, nil
+	default:
+		return nil, false, status.Errorf(codes.Internal, "failed to get secret: %v", err)
+	}
+
+	if secret.Labels[managedByLabel] != tdHash {
+		return nil, false, status.Error(codes.InvalidArgument, "secret is not managed by this SPIRE deployment")
+	}
+	if secret.Type != corev1.SecretTypeTLS {
+		return nil, false, status.Errorf(codes.InvalidArgument, "secret has unexpected type %q", secret.Type)
+	}
+
+	return secret, true, nil
+}
+
+type secretOptions struct {
+	namespace string
+	name      string
+}
+
+func optionsFromSecretData(selectorData []string) (*secretOptions, error) {
+	data, err := svidstore.ParseMetadata(selectorData)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid metadata: %v", err)
+	}
+
+	// Getting secret namespace and name, both are required.
+	namespace, ok := data["namespace"]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "namespace is required")
+	}
+
+	name, ok := data["name"]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	return &secretOptions{
+		namespace: namespace,
+		name:      name,
+	}, nil
+}
+
+func newKubeClient(kubeConfigFilePath string) (kubernetes.Interface, error) {
+	config, err := getKubeConfig(kubeConfigFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(config)
+}
+
+func getKubeConfig(configPath string) (*rest.Config, error) {
+	if configPath != "" {
+		return clientcmd.BuildConfigFromFlags("", configPath)
+	}
+	return rest.InClusterConfig()
+}
@@ -3,6 +3,7 @@ package svidstore
 import (
 	"crypto/x509"
 	"fmt"
+	"sort"
 	"strings"
 
 	svidstorev1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/svidstore/v1"
@@ -74,6 +75,22 @@ func ParseMetadata(metaData []string) (map[string]string, error) {
 	return data, nil
 }
 
+// ConcatFederatedBundles concatenates the PEM encoded federated bundles,
+// ordered by trust domain.
+func ConcatFederatedBundles(bundles map[string]string) string {
+	trustDomains := make([]string, 0, len(bundles))
+	for td := range bundles {
+		trustDomains = append(trustDomains, td)
+	}
+	sort.Strings(trustDomains)
+
+	var b strings.Builder
+	for _, td := range trustDomains {
+		b.WriteString(bundles[td])
+	}
+	return b.String()
+}
+
 func rawKeyToPem(rawKey []byte) (string, error) {
 	key, err := x509.ParsePKCS8PrivateKey(rawKey)
 	if err != nil {
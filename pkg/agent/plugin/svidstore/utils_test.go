@@ -107,6 +107,15 @@ func TestParseMetadata(t *testing.T) {
 	}
 }
 
+func TestConcatFederatedBundles(t *testing.T) {
+	require.Empty(t, svidstore.ConcatFederatedBundles(nil))
+	require.Equal(t, "a\nb\nc\n", svidstore.ConcatFederatedBundles(map[string]string{
+		"td3": "c\n",
+		"td1": "a\n",
+		"td2": "b\n",
+	}))
+}
+
 func TestSecretFromProto(t *testing.T) {
 	x509Cert, err := pemutil.ParseCertificate([]byte(x509CertPem))
 	require.NoError(t, err)
@@ -0,0 +1,392 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/token"
+	vapi "github.com/hashicorp/vault/api"
+	svidstorev1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/svidstore/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/agent/plugin/svidstore"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	vaultclient "github.com/spiffe/spire/pkg/common/plugin/vault"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pluginName = "vault"
+
+	defaultKVMountPoint = "secret"
+	defaultKVVersion    = 2
+
+	// managedByKey is the key set in the secrets managed by the plugin.
+	// Its value is the trust domain name of the SPIRE deployment.
+	managedByKey = "spire-svid"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *VaultPlugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		svidstorev1.SVIDStorePluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+func New() *VaultPlugin {
+	p := &VaultPlugin{}
+	p.hooks.lookupEnv = os.LookupEnv
+
+	return p
+}
+
+type Configuration struct {
+	// A URL of Vault server. (e.g., https://vault.example.com:8443/)
+	VaultAddr string `hcl:"vault_addr" json:"vault_addr"`
+	// Name of the mount point where the KV secrets engine is mounted (default: "secret")
+	KVMountPoint string `hcl:"kv_mount_point" json:"kv_mount_point"`
+	// Version of the KV secrets engine, 1 or 2 (default: 2)
+	KVVersion int `hcl:"kv_version" json:"kv_version"`
+	// Configuration for the Token authentication method
+	TokenAuth *vaultclient.TokenAuthConfig `hcl:"token_auth" json:"token_auth,omitempty"`
+	// Configuration for the Client Certificate authentication method
+	CertAuth *vaultclient.CertAuthConfig `hcl:"cert_auth" json:"cert_auth,omitempty"`
+	// Configuration for the AppRole authentication method
+	AppRoleAuth *vaultclient.AppRoleAuthConfig `hcl:"approle_auth" json:"approle_auth,omitempty"`
+	// Configuration for the Kubernetes authentication method
+	K8sAuth *vaultclient.K8sAuthConfig `hcl:"k8s_auth" json:"k8s_auth,omitempty"`
+	// Path to a CA certificate file that the client verifies the server certificate.
+	// Only PEM format is supported.
+	CACertPath string `hcl:"ca_cert_path" json:"ca_cert_path"`
+	// If true, vault client accepts any server certificates.
+	// It should be used only test environment so on.
+	InsecureSkipVerify bool `hcl:"insecure_skip_verify" json:"insecure_skip_verify"`
+	// Name of the Vault namespace
+	Namespace string `hcl:"namespace" json:"namespace"`
+
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions" json:",omitempty"`
+}
+
+func (c *Configuration) vaultConfig() *vaultclient.Config {
+	return &vaultclient.Config{
+		VaultAddr:          c.VaultAddr,
+		CACertPath:         c.CACertPath,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		Namespace:          c.Namespace,
+		TokenAuth:          c.TokenAuth,
+		CertAuth:           c.CertAuth,
+		AppRoleAuth:        c.AppRoleAuth,
+		K8sAuth:            c.K8sAuth,
+	}
+}
+
+type VaultPlugin struct {
+	svidstorev1.UnsafeSVIDStoreServer
+	configv1.UnsafeConfigServer
+
+	log hclog.Logger
+	mtx sync.Mutex
+
+	authMethod   vaultclient.AuthMethod
+	cc           *vaultclient.ClientConfig
+	vc           *vaultclient.Client
+	kvMountPoint string
+	kvVersion    int
+	trustDomain  string
+
+	hooks struct {
+		lookupEnv func(string) (string, bool)
+	}
+}
+
+func (p *VaultPlugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+// Configure configures the VaultPlugin.
+func (p *VaultPlugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	// Parse HCL config payload into config struct
+	config := &Configuration{}
+	if err := hcl.Decode(config, req.HclConfiguration); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if len(config.UnusedKeyPositions) != 0 {
+		var keys []string
+		for k := range config.UnusedKeyPositions {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		return nil, status.Errorf(codes.InvalidArgument, "unknown configurations detected: %s", strings.Join(keys, ","))
+	}
+
+	if config.KVMountPoint == "" {
+		config.KVMountPoint = defaultKVMountPoint
+	}
+
+	switch config.KVVersion {
+	case 0:
+		config.KVVersion = defaultKVVersion
+	case 1, 2:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported kv_version %d: must be 1 or 2", config.KVVersion)
+	}
+
+	am, err := vaultclient.ParseAuthMethod(config.vaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	cp, err := vaultclient.GenClientParams(am, config.vaultConfig(), p.hooks.lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	vcConfig, err := vaultclient.NewClientConfig(cp, p.log)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.authMethod = am
+	p.cc = vcConfig
+	p.vc = nil
+	p.kvMountPoint = strings.Trim(config.KVMountPoint, "/")
+	p.kvVersion = config.KVVersion
+	p.trustDomain = req.CoreConfiguration.TrustDomain
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+// PutX509SVID writes the specified X509-SVID to the configured Vault KV
+// secrets engine
+func (p *VaultPlugin) PutX509SVID(_ context.Context, req *svidstorev1.PutX509SVIDRequest) (*svidstorev1.PutX509SVIDResponse, error) {
+	kv, err := p.getKV()
+	if err != nil {
+		return nil, err
+	}
+
+	secretPath, err := pathFromSecretData(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	secretData, err := svidstore.SecretFromProto(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse request: %v", err)
+	}
+
+	if _, err := kv.getSecret(secretPath); err != nil {
+		return nil, err
+	}
+
+	data, err := kv.secretPayload(secretData)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := kv.putSecret(secretPath, data); err != nil {
+		return nil, err
+	}
+
+	p.log.With("path", secretPath).Debug("Secret stored")
+	return &svidstorev1.PutX509SVIDResponse{}, nil
+}
+
+// DeleteX509SVID deletes the secret, including all its versions, from the
+// configured Vault KV secrets engine
+func (p *VaultPlugin) DeleteX509SVID(_ context.Context, req *svidstorev1.DeleteX509SVIDRequest) (*svidstorev1.DeleteX509SVIDResponse, error) {
+	kv, err := p.getKV()
+	if err != nil {
+		return nil, err
+	}
+
+	secretPath, err := pathFromSecretData(req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	found, err := kv.getSecret(secretPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !found {
+		p.log.With("path", secretPath).Debug("Secret to delete not found")
+		return &svidstorev1.DeleteX509SVIDResponse{}, nil
+	}
+
+	if err := kv.deleteSecret(secretPath); err != nil {
+		return nil, err
+	}
+
+	p.log.With("path", secretPath).Debug("Secret deleted")
+	return &svidstorev1.DeleteX509SVIDResponse{}, nil
+}
+
+// getKV returns the KV secrets engine, authenticating to Vault if there is
+// no authenticated client or its token could not be renewed.
+func (p *VaultPlugin) getKV() (*kvEngine, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.cc == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+
+	if p.vc == nil {
+		renewCh := make(chan struct{})
+		vc, err := p.cc.NewAuthenticatedClient(p.authMethod, renewCh)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to prepare authenticated client: %v", err)
+		}
+		p.vc = vc
+
+		// if renewCh has been closed, the token can not be renewed and may expire,
+		// it needs to re-authenticate to the Vault.
+		go func() {
+			<-renewCh
+			p.mtx.Lock()
+			defer p.mtx.Unlock()
+			if p.vc == vc {
+				p.vc = nil
+			}
+			p.log.Debug("Going to re-authenticate to the Vault at the next store request time")
+		}()
+	}
+
+	return &kvEngine{
+		client:      p.vc,
+		mountPoint:  p.kvMountPoint,
+		version:     p.kvVersion,
+		trustDomain: p.trustDomain,
+	}, nil
+}
+
+// kvEngine reads and writes secrets in a KV secrets engine, hiding the
+// differences between the API of the versions 1 and 2.
+type kvEngine struct {
+	client      *vaultclient.Client
+	mountPoint  string
+	version     int
+	trustDomain string
+}
+
+// dataPath returns the path used to read and write the secret data
+func (kv *kvEngine) dataPath(secretPath string) string {
+	if kv.version == 1 {
+		return fmt.Sprintf("%s/%s", kv.mountPoint, secretPath)
+	}
+	return fmt.Sprintf("%s/data/%s", kv.mountPoint, secretPath)
+}
+
+// deletePath returns the path used to delete the secret. In version 2 the
+// metadata is deleted, which removes all the versions of the secret.
+func (kv *kvEngine) deletePath(secretPath string) string {
+	if kv.version == 1 {
+		return fmt.Sprintf("%s/%s", kv.mountPoint, secretPath)
+	}
+	return fmt.Sprintf("%s/metadata/%s", kv.mountPoint, secretPath)
+}
+
+// getSecret gets the secret from Vault and validates that it has the
+// `spire-svid` key with the trust domain name as value, false if not found
+func (kv *kvEngine) getSecret(secretPath string) (bool, error) {
+	secret, err := kv.client.Read(kv.dataPath(secretPath))
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to read secret: %v", err)
+	}
+
+	data := secretData(secret, kv.version)
+	if data == nil {
+		// The secret does not exist, or its latest version was deleted
+		return false, nil
+	}
+
+	if managedBy, ok := data[managedByKey].(string); !ok || managedBy != kv.trustDomain {
+		return false, status.Error(codes.InvalidArgument, "secret is not managed by this SPIRE deployment")
+	}
+	return true, nil
+}
+
+func (kv *kvEngine) putSecret(secretPath string, data map[string]interface{}) error {
+	if kv.version == 2 {
+		data = map[string]interface{}{"data": data}
+	}
+
+	if _, err := kv.client.Write(kv.dataPath(secretPath), data); err != nil {
+		return status.Errorf(codes.Internal, "failed to write secret: %v", err)
+	}
+	return nil
+}
+
+func (kv *kvEngine) deleteSecret(secretPath string) error {
+	if _, err := kv.client.Delete(kv.deletePath(secretPath)); err != nil {
+		return status.Errorf(codes.Internal, "failed to delete secret: %v", err)
+	}
+	return nil
+}
+
+// secretPayload returns the key/value pairs written to Vault, which are the
+// fields of the stored secret plus the `spire-svid` key.
+func (kv *kvEngine) secretPayload(secretData *svidstore.Data) (map[string]interface{}, error) {
+	b, err := json.Marshal(secretData)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal payload: %v", err)
+	}
+
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal payload: %v", err)
+	}
+	data[managedByKey] = kv.trustDomain
+
+	return data, nil
+}
+
+// secretData returns the key/value pairs of a secret read from Vault, or nil
+// if there are none.
+func secretData(secret *vapi.Secret, version int) map[string]interface{} {
+	if secret == nil || secret.Data == nil {
+		return nil
+	}
+	if version == 1 {
+		return secret.Data
+	}
+
+	data, _ := secret.Data["data"].(map[string]interface{})
+	return data
+}
+
+func pathFromSecretData(selectorData []string) (string, error) {
+	data, err := svidstore.ParseMetadata(selectorData)
+	if err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "invalid metadata: %v", err)
+	}
+
+	secretPath := strings.Trim(data["path"], "/")
+	if secretPath == "" {
+		return "", status.Error(codes.InvalidArgument, "path is required")
+	}
+
+	// Prevent paths from escaping the mount point of the KV secrets engine
+	for _, segment := range strings.Split(secretPath, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", status.Errorf(codes.InvalidArgument, "invalid path %q", secretPath)
+		}
+	}
+
+	return secretPath, nil
+}
@@ -0,0 +1,11 @@
+package cgroup
+
+import "github.com/spiffe/spire/pkg/common/catalog"
+
+const (
+	pluginName = "cgroup"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
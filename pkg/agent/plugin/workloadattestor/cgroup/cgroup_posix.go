@@ -0,0 +1,241 @@
+//go:build !windows
+// +build !windows
+
+package cgroup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/token"
+	workloadattestorv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/workloadattestor/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/agent/common/cgroups"
+	"github.com/spiffe/spire/pkg/agent/common/procfs"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// systemdController is the controller list of the named cgroup v1
+	// hierarchy managed by systemd.
+	systemdController = "name=systemd"
+
+	// unifiedHierarchyID is the hierarchy ID of the cgroup v2 hierarchy.
+	unifiedHierarchyID = "0"
+)
+
+var (
+	defaultNamespaces = []string{"pid", "net", "mnt"}
+
+	// supportedNamespaces are the namespace types found in /proc/<pid>/ns.
+	supportedNamespaces = map[string]bool{
+		"cgroup": true,
+		"ipc":    true,
+		"mnt":    true,
+		"net":    true,
+		"pid":    true,
+		"time":   true,
+		"user":   true,
+		"uts":    true,
+	}
+
+	// unitSuffixes are the suffixes of the types of systemd units that
+	// processes can belong to.
+	unitSuffixes = []string{".service", ".scope"}
+)
+
+func builtin(p *Plugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		workloadattestorv1.WorkloadAttestorPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+type Configuration struct {
+	// Namespaces are the types of the namespaces used to provide selectors
+	// (default: ["pid", "net", "mnt"]).
+	Namespaces []string `hcl:"namespaces"`
+
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
+}
+
+type Plugin struct {
+	workloadattestorv1.UnsafeWorkloadAttestorServer
+	configv1.UnsafeConfigServer
+
+	log hclog.Logger
+
+	mtx    sync.RWMutex
+	config *Configuration
+
+	// hooks for tests
+	hooks struct {
+		fs       cgroups.FileSystem
+		readlink func(name string) (string, error)
+	}
+}
+
+func New() *Plugin {
+	p := &Plugin{}
+	p.hooks.fs = cgroups.OSFileSystem{}
+	p.hooks.readlink = os.Readlink
+	return p
+}
+
+func (p *Plugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+func (p *Plugin) Attest(_ context.Context, req *workloadattestorv1.AttestRequest) (*workloadattestorv1.AttestResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	cgroupList, err := cgroups.GetCgroups(req.Pid, p.hooks.fs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get cgroups: %v", err)
+	}
+
+	var selectorValues []string
+
+	seenPaths := make(map[string]bool)
+	for _, cgroup := range cgroupList {
+		if seenPaths[cgroup.GroupPath] {
+			continue
+		}
+		seenPaths[cgroup.GroupPath] = true
+		selectorValues = append(selectorValues, makeSelectorValue("path", cgroup.GroupPath))
+	}
+
+	slices, unit := parseSystemdPath(getSystemdPath(cgroupList))
+	for _, slice := range slices {
+		selectorValues = append(selectorValues, makeSelectorValue("slice", slice))
+	}
+	if unit != "" {
+		selectorValues = append(selectorValues, makeSelectorValue("unit", unit))
+	}
+
+	for _, namespace := range config.Namespaces {
+		inode, err := p.getNamespaceInode(req.Pid, namespace)
+		if err != nil {
+			return nil, err
+		}
+		selectorValues = append(selectorValues, makeSelectorValue("ns", namespace+":"+inode))
+	}
+
+	return &workloadattestorv1.AttestResponse{
+		SelectorValues: selectorValues,
+	}, nil
+}
+
+func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	config := new(Configuration)
+	if err := hcl.Decode(config, req.HclConfiguration); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if len(config.UnusedKeyPositions) > 0 {
+		var keys []string
+		for k := range config.UnusedKeyPositions {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		return nil, status.Errorf(codes.InvalidArgument, "unknown configurations detected: %s", strings.Join(keys, ","))
+	}
+
+	// An empty list disables the namespace selectors
+	if config.Namespaces == nil {
+		config.Namespaces = defaultNamespaces
+	}
+	for _, namespace := range config.Namespaces {
+		if !supportedNamespaces[namespace] {
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported namespace type %q", namespace)
+		}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.config = config
+	return &configv1.ConfigureResponse{}, nil
+}
+
+func (p *Plugin) getConfig() (*Configuration, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	if p.config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, nil
+}
+
+// getNamespaceInode returns the inode number identifying the namespace of
+// the given type the process belongs to. The namespace links are of the form
+// "<type>:[<inode>]".
+func (p *Plugin) getNamespaceInode(pid int32, namespace string) (string, error) {
+	link, err := p.hooks.readlink(procfs.Path(pid, "ns", namespace))
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to get %s namespace: %v", namespace, err)
+	}
+
+	inode := strings.TrimPrefix(link, namespace+":[")
+	if inode == link || !strings.HasSuffix(inode, "]") {
+		return "", status.Errorf(codes.Internal, "malformed %s namespace link %q", namespace, link)
+	}
+	return strings.TrimSuffix(inode, "]"), nil
+}
+
+// getSystemdPath returns the path of the cgroup managed by systemd, which is
+// the path in the named "systemd" hierarchy on cgroup v1 (legacy and hybrid
+// modes) or the path in the unified hierarchy on cgroup v2.
+func getSystemdPath(cgroupList []cgroups.Cgroup) string {
+	var unifiedPath string
+	for _, cgroup := range cgroupList {
+		switch {
+		case cgroup.ControllerList == systemdController:
+			return cgroup.GroupPath
+		case cgroup.HierarchyID == unifiedHierarchyID && cgroup.ControllerList == "":
+			unifiedPath = cgroup.GroupPath
+		}
+	}
+	return unifiedPath
+}
+
+// parseSystemdPath returns the slices and the unit found in a cgroup path
+// laid out by systemd, e.g. "/system.slice/nginx.service". Parsing stops at
+// the first unit, which is the one owned by the system manager. Units may
+// delegate their cgroup subtree to unprivileged processes (e.g. the user
+// manager running as user@1000.service), so nothing below it is trusted.
+func parseSystemdPath(path string) (slices []string, unit string) {
+	for _, component := range strings.Split(path, "/") {
+		switch {
+		case strings.HasSuffix(component, ".slice"):
+			slices = append(slices, component)
+		case hasUnitSuffix(component):
+			return slices, component
+		}
+	}
+	return slices, ""
+}
+
+func hasUnitSuffix(component string) bool {
+	for _, suffix := range unitSuffixes {
+		if strings.HasSuffix(component, suffix) && len(component) > len(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func makeSelectorValue(kind, value string) string {
+	return fmt.Sprintf("%s:%s", kind, value)
+}
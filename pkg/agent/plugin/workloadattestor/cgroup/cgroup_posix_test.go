@@ -0,0 +1,263 @@
+//go:build !windows
+// +build !windows
+
+package cgroup
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ctx = context.Background()
+
+	testNamespaces = map[string]string{
+		"/proc/123/ns/pid":  "pid:[4026531836]",
+		"/proc/123/ns/net":  "net:[4026531840]",
+		"/proc/123/ns/mnt":  "mnt:[4026531841]",
+		"/proc/123/ns/user": "user:[4026531837]",
+		"/proc/123/ns/uts":  "garbage",
+	}
+)
+
+func TestAttest(t *testing.T) {
+	for _, tt := range []struct {
+		desc           string
+		config         string
+		cgroups        string
+		expectValues   []string
+		expectCode     codes.Code
+		expectMsg      string
+		missingCgroups bool
+	}{
+		{
+			desc:    "cgroup v2 service",
+			cgroups: "0::/system.slice/nginx.service",
+			expectValues: []string{
+				"path:/system.slice/nginx.service",
+				"slice:system.slice",
+				"unit:nginx.service",
+				"ns:pid:4026531836",
+				"ns:net:4026531840",
+				"ns:mnt:4026531841",
+			},
+		},
+		{
+			desc:    "cgroup v2 user service",
+			config:  "namespaces = []",
+			cgroups: "0::/user.slice/user-1000.slice/user@1000.service/app.slice/backup.service",
+			expectValues: []string{
+				"path:/user.slice/user-1000.slice/user@1000.service/app.slice/backup.service",
+				"slice:user.slice",
+				"slice:user-1000.slice",
+				"unit:user@1000.service",
+			},
+		},
+		{
+			desc:    "cgroup v2 user service mimicking a system unit",
+			config:  "namespaces = []",
+			cgroups: "0::/user.slice/user-1000.slice/user@1000.service/system.slice/nginx.service",
+			expectValues: []string{
+				"path:/user.slice/user-1000.slice/user@1000.service/system.slice/nginx.service",
+				"slice:user.slice",
+				"slice:user-1000.slice",
+				"unit:user@1000.service",
+			},
+		},
+		{
+			desc:    "cgroup v2 sub-cgroup of a delegated unit",
+			config:  "namespaces = []",
+			cgroups: "0::/system.slice/runner.service/jobs/42",
+			expectValues: []string{
+				"path:/system.slice/runner.service/jobs/42",
+				"slice:system.slice",
+				"unit:runner.service",
+			},
+		},
+		{
+			desc:   "cgroup v1",
+			config: `namespaces = ["user"]`,
+			cgroups: strings.Join([]string{
+				"12:memory:/system.slice/sshd.service",
+				"11:cpu,cpuacct:/system.slice/sshd.service",
+				"10:devices:/system.slice/sshd.service",
+				"1:name=systemd:/system.slice/sshd.service",
+			}, "\n"),
+			expectValues: []string{
+				"path:/system.slice/sshd.service",
+				"slice:system.slice",
+				"unit:sshd.service",
+				"ns:user:4026531837",
+			},
+		},
+		{
+			desc:   "cgroup v1 hybrid mode",
+			config: "namespaces = []",
+			cgroups: strings.Join([]string{
+				"3:memory:/user.slice",
+				"1:name=systemd:/user.slice/user-1000.slice/session-2.scope",
+				"0::/user.slice/user-1000.slice/session-2.scope",
+			}, "\n"),
+			expectValues: []string{
+				"path:/user.slice",
+				"path:/user.slice/user-1000.slice/session-2.scope",
+				"slice:user.slice",
+				"slice:user-1000.slice",
+				"unit:session-2.scope",
+			},
+		},
+		{
+			desc:    "root cgroup",
+			config:  "namespaces = []",
+			cgroups: "0::/",
+			expectValues: []string{
+				"path:/",
+			},
+		},
+		{
+			desc:           "fail to read cgroups",
+			missingCgroups: true,
+			expectCode:     codes.Internal,
+			expectMsg:      "workloadattestor(cgroup): failed to get cgroups: file does not exist",
+		},
+		{
+			desc:       "fail to read namespace",
+			config:     `namespaces = ["ipc"]`,
+			cgroups:    "0::/",
+			expectCode: codes.Internal,
+			expectMsg:  "workloadattestor(cgroup): failed to get ipc namespace: file does not exist",
+		},
+		{
+			desc:       "malformed namespace link",
+			config:     `namespaces = ["uts"]`,
+			cgroups:    "0::/",
+			expectCode: codes.Internal,
+			expectMsg:  `workloadattestor(cgroup): malformed uts namespace link "garbage"`,
+		},
+	} {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			files := map[string]string{}
+			if !tt.missingCgroups {
+				files["/proc/123/cgroup"] = tt.cgroups
+			}
+
+			p := loadPlugin(t, tt.config, fakeFileSystem(files))
+			selectors, err := p.Attest(ctx, 123)
+			spiretest.RequireGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+			if tt.expectCode != codes.OK {
+				require.Nil(t, selectors)
+				return
+			}
+
+			var selectorValues []string
+			for _, selector := range selectors {
+				require.Equal(t, pluginName, selector.Type)
+				selectorValues = append(selectorValues, selector.Value)
+			}
+			require.Equal(t, tt.expectValues, selectorValues)
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		desc       string
+		config     string
+		expectCode codes.Code
+		expectMsg  string
+	}{
+		{
+			desc: "defaults",
+		},
+		{
+			desc:   "namespaces",
+			config: `namespaces = ["cgroup", "ipc", "mnt", "net", "pid", "time", "user", "uts"]`,
+		},
+		{
+			desc:       "malformed configuration",
+			config:     "namespaces = [",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "unable to decode configuration",
+		},
+		{
+			desc:       "unknown configuration",
+			config:     `namespace = ["pid"]`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "unknown configurations detected: namespace",
+		},
+		{
+			desc:       "unsupported namespace",
+			config:     `namespaces = ["pid_for_children"]`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `unsupported namespace type "pid_for_children"`,
+		},
+	} {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			var err error
+			plugintest.Load(t, builtin(New()), nil,
+				plugintest.CaptureConfigureError(&err),
+				plugintest.Configure(tt.config))
+			spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMsg)
+		})
+	}
+}
+
+func TestAttestWithHostProc(t *testing.T) {
+	t.Setenv("HOST_PROC", "/host/proc")
+
+	p := New()
+	p.hooks.fs = fakeFileSystem{"/proc/123/cgroup": "0::/"}
+	var readlinkName string
+	p.hooks.readlink = func(name string) (string, error) {
+		readlinkName = name
+		return "pid:[4026531836]", nil
+	}
+
+	v1 := new(workloadattestor.V1)
+	plugintest.Load(t, builtin(p), v1, plugintest.Configure(`namespaces = ["pid"]`))
+
+	selectors, err := v1.Attest(ctx, 123)
+	require.NoError(t, err)
+	require.Equal(t, "/host/proc/123/ns/pid", readlinkName)
+	require.Len(t, selectors, 2)
+	require.Equal(t, "ns:pid:4026531836", selectors[1].Value)
+}
+
+func loadPlugin(t *testing.T, config string, fs fakeFileSystem) workloadattestor.WorkloadAttestor {
+	p := New()
+	p.hooks.fs = fs
+	p.hooks.readlink = fakeReadlink
+
+	v1 := new(workloadattestor.V1)
+	plugintest.Load(t, builtin(p), v1, plugintest.Configure(config))
+	return v1
+}
+
+func fakeReadlink(name string) (string, error) {
+	link, ok := testNamespaces[name]
+	if !ok {
+		return "", os.ErrNotExist
+	}
+	return link, nil
+}
+
+type fakeFileSystem map[string]string
+
+func (fs fakeFileSystem) Open(path string) (io.ReadCloser, error) {
+	data, ok := fs[path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
@@ -0,0 +1,11 @@
+package container
+
+import "github.com/spiffe/spire/pkg/common/catalog"
+
+const (
+	pluginName = "container"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
@@ -0,0 +1,315 @@
+//go:build !windows
+// +build !windows
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/token"
+	workloadattestorv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/workloadattestor/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/agent/common/cgroups"
+	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor/docker/cgroup"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	subselectorRuntime     = "runtime"
+	subselectorName        = "name"
+	subselectorNamespace   = "namespace"
+	subselectorImage       = "image"
+	subselectorImageDigest = "image_digest"
+	subselectorLabel       = "label"
+)
+
+func builtin(p *Plugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		workloadattestorv1.WorkloadAttestorPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+// runtimeClient queries a container runtime for container information.
+type runtimeClient interface {
+	// Name returns the name of the runtime.
+	Name() string
+
+	// GetContainer returns information about the container with the given
+	// ID, or nil if the container is not known by the runtime.
+	GetContainer(ctx context.Context, containerID string) (*containerInfo, error)
+
+	// Close releases the resources held by the client.
+	Close() error
+}
+
+type containerInfo struct {
+	Name        string
+	Namespace   string
+	Image       string
+	ImageDigest string
+	Labels      map[string]string
+}
+
+type ContainerdConfig struct {
+	// SocketPath is the path to the containerd socket (default: "/run/containerd/containerd.sock").
+	SocketPath string `hcl:"socket_path"`
+
+	// Namespaces are the containerd namespaces searched for the workload container (default: ["default", "k8s.io"]).
+	Namespaces []string `hcl:"namespaces"`
+}
+
+type PodmanConfig struct {
+	// SocketPath is the path to the podman API socket (default: "/run/podman/podman.sock").
+	SocketPath string `hcl:"socket_path"`
+}
+
+type CRIOConfig struct {
+	// SocketPath is the path to the CRI-O socket (default: "/var/run/crio/crio.sock").
+	SocketPath string `hcl:"socket_path"`
+}
+
+type Configuration struct {
+	Containerd *ContainerdConfig `hcl:"containerd"`
+	Podman     *PodmanConfig     `hcl:"podman"`
+	CRIO       *CRIOConfig       `hcl:"crio"`
+
+	// ContainerIDCGroupMatchers is a list of patterns used to discover container IDs from cgroup entries.
+	// See the documentation for cgroup.NewContainerIDFinder in the docker cgroup subpackage for more information.
+	ContainerIDCGroupMatchers []string `hcl:"container_id_cgroup_matchers"`
+
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions"`
+}
+
+type Plugin struct {
+	workloadattestorv1.UnsafeWorkloadAttestorServer
+	configv1.UnsafeConfigServer
+
+	log hclog.Logger
+	fs  cgroups.FileSystem
+
+	mtx               sync.RWMutex
+	runtimes          []runtimeClient
+	containerIDFinder cgroup.ContainerIDFinder
+}
+
+func New() *Plugin {
+	return &Plugin{
+		fs: cgroups.OSFileSystem{},
+	}
+}
+
+func (p *Plugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+func (p *Plugin) Attest(ctx context.Context, req *workloadattestorv1.AttestRequest) (*workloadattestorv1.AttestResponse, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	if p.runtimes == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+
+	cgroupList, err := cgroups.GetCgroups(req.Pid, p.fs)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get cgroups: %v", err)
+	}
+
+	containerID, err := cgroup.ContainerIDFromCgroups(p.containerIDFinder, cgroupList)
+	switch {
+	case err != nil:
+		return nil, status.Error(codes.Internal, err.Error())
+	case containerID == "":
+		// Not a container workload. Nothing more to do.
+		return &workloadattestorv1.AttestResponse{}, nil
+	}
+
+	for _, runtime := range p.runtimes {
+		info, err := runtime.GetContainer(ctx, containerID)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get container %q from %s: %v", containerID, runtime.Name(), err)
+		}
+		if info == nil {
+			continue
+		}
+		return &workloadattestorv1.AttestResponse{
+			SelectorValues: getSelectorValues(runtime.Name(), info),
+		}, nil
+	}
+
+	// The container might be managed by a runtime that is not configured
+	// (e.g. docker), so this is not an error.
+	p.log.Debug("Container not found in the configured runtimes", telemetry.ContainerID, containerID)
+	return &workloadattestorv1.AttestResponse{}, nil
+}
+
+func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	config := new(Configuration)
+	if err := hcl.Decode(config, req.HclConfiguration); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if len(config.UnusedKeyPositions) > 0 {
+		var keys []string
+		for k := range config.UnusedKeyPositions {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		return nil, status.Errorf(codes.InvalidArgument, "unknown configurations detected: %s", strings.Join(keys, ","))
+	}
+
+	if config.Containerd == nil && config.Podman == nil && config.CRIO == nil {
+		return nil, status.Error(codes.InvalidArgument, "at least one container runtime must be configured")
+	}
+
+	var containerIDFinder cgroup.ContainerIDFinder = &defaultContainerIDFinder{}
+	if len(config.ContainerIDCGroupMatchers) > 0 {
+		var err error
+		containerIDFinder, err = cgroup.NewContainerIDFinder(config.ContainerIDCGroupMatchers)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	runtimes, err := newRuntimeClients(config)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for _, runtime := range p.runtimes {
+		if err := runtime.Close(); err != nil {
+			p.log.Warn("Failed to close container runtime client", "runtime", runtime.Name(), "error", err)
+		}
+	}
+	p.runtimes = runtimes
+	p.containerIDFinder = containerIDFinder
+	return &configv1.ConfigureResponse{}, nil
+}
+
+func (p *Plugin) Close() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for _, runtime := range p.runtimes {
+		if err := runtime.Close(); err != nil {
+			p.log.Warn("Failed to close container runtime client", "runtime", runtime.Name(), "error", err)
+		}
+	}
+	p.runtimes = nil
+	return nil
+}
+
+func newRuntimeClients(config *Configuration) ([]runtimeClient, error) {
+	var runtimes []runtimeClient
+	if config.Containerd != nil {
+		socketPath := config.Containerd.SocketPath
+		if socketPath == "" {
+			socketPath = defaultContainerdSocketPath
+		}
+		namespaces := config.Containerd.Namespaces
+		if len(namespaces) == 0 {
+			namespaces = defaultContainerdNamespaces
+		}
+		client, err := newContainerdClient(socketPath, namespaces)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create containerd client: %v", err)
+		}
+		runtimes = append(runtimes, client)
+	}
+	if config.CRIO != nil {
+		socketPath := config.CRIO.SocketPath
+		if socketPath == "" {
+			socketPath = defaultCRIOSocketPath
+		}
+		runtimes = append(runtimes, newCRIOClient(socketPath))
+	}
+	if config.Podman != nil {
+		socketPath := config.Podman.SocketPath
+		if socketPath == "" {
+			socketPath = defaultPodmanSocketPath
+		}
+		runtimes = append(runtimes, newPodmanClient(socketPath))
+	}
+	return runtimes, nil
+}
+
+func getSelectorValues(runtimeName string, info *containerInfo) []string {
+	selectorValues := []string{makeSelectorValue(subselectorRuntime, runtimeName)}
+	if info.Name != "" {
+		selectorValues = append(selectorValues, makeSelectorValue(subselectorName, info.Name))
+	}
+	if info.Namespace != "" {
+		selectorValues = append(selectorValues, makeSelectorValue(subselectorNamespace, info.Namespace))
+	}
+	if info.Image != "" {
+		selectorValues = append(selectorValues, makeSelectorValue(subselectorImage, info.Image))
+	}
+	if info.ImageDigest != "" {
+		selectorValues = append(selectorValues, makeSelectorValue(subselectorImageDigest, info.ImageDigest))
+	}
+
+	labels := make([]string, 0, len(info.Labels))
+	for label := range info.Labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		selectorValues = append(selectorValues, fmt.Sprintf("%s:%s:%s", subselectorLabel, label, info.Labels[label]))
+	}
+	return selectorValues
+}
+
+// imageDigestFromRef returns the digest of an image reference, which only
+// includes one if the image was pulled by digest, e.g.
+// "quay.io/example/app@sha256:...".
+func imageDigestFromRef(ref string) string {
+	_, digest, _ := strings.Cut(ref, "@")
+	return digest
+}
+
+func makeSelectorValue(kind, value string) string {
+	return fmt.Sprintf("%s:%s", kind, value)
+}
+
+type defaultContainerIDFinder struct{}
+
+// FindContainerID returns the container ID in the given cgroup path. A path
+// component must be the 64 hex-character container ID prefixed by one of the
+// prefixes used by the supported runtimes when creating the container cgroups
+// (e.g. "libpod-<id>.scope"), or the cgroup path must be the one containerd
+// creates with the cgroupfs driver in the default namespace. If the cgroup
+// path does not match the above description, the method returns false.
+func (f *defaultContainerIDFinder) FindContainerID(cgroupPath string) (string, bool) {
+	if m := containerCGroupRE.FindStringSubmatch(cgroupPath); m != nil {
+		return m[1], true
+	}
+	if m := containerdCGroupfsRE.FindStringSubmatch(cgroupPath); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+// containerCGroupRE matches cgroup paths with a path component that is the
+// container ID prefixed by "libpod-" (podman), "crio-" (CRI-O),
+// "cri-containerd-" (containerd CRI plugin) or "nerdctl-" (nerdctl), with an
+// optional ".scope" suffix when the systemd cgroup driver is used. Conmon
+// cgroups (e.g. "libpod-conmon-<id>.scope") do not match.
+var containerCGroupRE = regexp.MustCompile(`/(?:libpod|crio|cri-containerd|nerdctl)-([[:xdigit:]]{64})(?:\.scope)?(?:/|$)`)
+
+// containerdCGroupfsRE matches the cgroup paths containerd creates with the
+// cgroupfs driver for the containers of the "default" namespace (e.g. the
+// containers created with ctr or nerdctl), i.e. "/default/<id>".
+var containerdCGroupfsRE = regexp.MustCompile(`^/default/([[:xdigit:]]{64})(?:/|$)`)
@@ -0,0 +1,479 @@
+//go:build !windows
+// +build !windows
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	containersv1 "github.com/containerd/containerd/api/services/containers/v1"
+	"github.com/containerd/containerd/namespaces"
+	"github.com/spiffe/spire/pkg/agent/common/cgroups"
+	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor"
+	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor/docker/cgroup"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testContainerID  = "6469646e742065787065637420616e796f6e6520746f20726561642074686973"
+	otherContainerID = "41e4ab61d2860b0e1467de0da0a9c6068012761febec402dc04a5a94f32ea867"
+
+	testCgroupEntries = "0::/machine.slice/libpod-" + testContainerID + ".scope/container"
+)
+
+var ctx = context.Background()
+
+func TestContainerIDExtraction(t *testing.T) {
+	for _, tt := range []struct {
+		desc        string
+		cgroups     string
+		expectID    string
+		expectError string
+	}{
+		{
+			desc:     "podman with systemd cgroup driver",
+			cgroups:  "0::/user.slice/user-1000.slice/user@1000.service/user.slice/libpod-" + testContainerID + ".scope",
+			expectID: testContainerID,
+		},
+		{
+			desc:     "podman with cgroupfs cgroup driver",
+			cgroups:  "11:devices:/libpod_parent/libpod-" + testContainerID,
+			expectID: testContainerID,
+		},
+		{
+			desc:    "podman conmon",
+			cgroups: "0::/machine.slice/libpod-conmon-" + testContainerID + ".scope",
+		},
+		{
+			desc:     "CRI-O",
+			cgroups:  "0::/kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1.slice/crio-" + testContainerID + ".scope",
+			expectID: testContainerID,
+		},
+		{
+			desc:     "containerd CRI plugin",
+			cgroups:  "0::/kubepods.slice/kubepods-pod1.slice/cri-containerd-" + testContainerID + ".scope",
+			expectID: testContainerID,
+		},
+		{
+			desc:     "containerd with cgroupfs cgroup driver",
+			cgroups:  "0::/default/" + testContainerID,
+			expectID: testContainerID,
+		},
+		{
+			desc:     "containerd with cgroupfs cgroup driver (cgroup v1)",
+			cgroups:  "4:devices:/default/" + testContainerID + "\n3:cpu:/default/" + testContainerID,
+			expectID: testContainerID,
+		},
+		{
+			desc:    "containerd with cgroupfs cgroup driver in another namespace",
+			cgroups: "0::/other/" + testContainerID,
+		},
+		{
+			desc:     "nerdctl",
+			cgroups:  "0::/system.slice/nerdctl-" + testContainerID + ".scope",
+			expectID: testContainerID,
+		},
+		{
+			desc:    "docker",
+			cgroups: "0::/system.slice/docker-" + testContainerID + ".scope",
+		},
+		{
+			desc:    "not a container",
+			cgroups: "0::/user.slice/user-1000.slice/session-1.scope",
+		},
+		{
+			desc:        "more than one id",
+			cgroups:     "4:devices:/libpod_parent/libpod-" + testContainerID + "\n3:cpu:/libpod_parent/libpod-" + otherContainerID,
+			expectError: "multiple container IDs found in cgroups",
+		},
+	} {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			cgroupList, err := cgroups.GetCgroups(123, newFakeFileSystem(tt.cgroups))
+			require.NoError(t, err)
+
+			containerID, err := cgroup.ContainerIDFromCgroups(&defaultContainerIDFinder{}, cgroupList)
+			if tt.expectError != "" {
+				require.ErrorContains(t, err, tt.expectError)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectID, containerID)
+		})
+	}
+}
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		desc       string
+		config     string
+		expectCode codes.Code
+		expectMsg  string
+	}{
+		{
+			desc:   "containerd with defaults",
+			config: "containerd {}",
+		},
+		{
+			desc: "all runtimes",
+			config: `
+containerd {
+	socket_path = "/run/containerd.sock"
+	namespaces = ["default"]
+}
+podman {
+	socket_path = "/run/podman.sock"
+}
+crio {
+	socket_path = "/run/crio.sock"
+}
+container_id_cgroup_matchers = ["/my.slice/<id>"]
+`,
+		},
+		{
+			desc:       "malformed configuration",
+			config:     "containerd {",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "unable to decode configuration",
+		},
+		{
+			desc:       "no runtimes",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "at least one container runtime must be configured",
+		},
+		{
+			desc:       "unknown configuration",
+			config:     "containerd {}\ndocker_socket_path = \"/run/docker.sock\"",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "unknown configurations detected: docker_socket_path",
+		},
+		{
+			desc:       "bad matcher",
+			config:     "containerd {}\ncontainer_id_cgroup_matchers = [\"/my.slice/\"]",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `must contain the container id token "<id>" exactly once`,
+		},
+	} {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			var err error
+			plugintest.Load(t, builtin(New()), nil,
+				plugintest.CaptureConfigureError(&err),
+				plugintest.Configure(tt.config))
+			spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMsg)
+		})
+	}
+}
+
+func TestAttestContainerd(t *testing.T) {
+	containerd := &fakeContainerd{
+		containers: map[string]map[string]*containersv1.Container{
+			"k8s.io": {
+				testContainerID: {
+					ID:    testContainerID,
+					Image: "docker.io/library/nginx:latest",
+					Labels: map[string]string{
+						"nerdctl/name": "web",
+						"app":          "frontend",
+					},
+				},
+				otherContainerID: {
+					ID:    otherContainerID,
+					Image: "docker.io/library/nginx@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+				},
+			},
+		},
+	}
+	socketPath := containerd.serve(t)
+	config := fmt.Sprintf(`containerd {
+	socket_path = %q
+}`, socketPath)
+
+	t.Run("container found", func(t *testing.T) {
+		p := loadPlugin(t, config, testCgroupEntries)
+		selectorValues, err := doAttest(t, p)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"runtime:containerd",
+			"name:web",
+			"namespace:k8s.io",
+			"image:docker.io/library/nginx:latest",
+			"label:app:frontend",
+			"label:nerdctl/name:web",
+		}, selectorValues)
+	})
+
+	t.Run("container created from an image digest", func(t *testing.T) {
+		p := loadPlugin(t, config, "0::/system.slice/nerdctl-"+otherContainerID+".scope")
+		selectorValues, err := doAttest(t, p)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"runtime:containerd",
+			"namespace:k8s.io",
+			"image:docker.io/library/nginx@sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+			"image_digest:sha256:0d17b565c37bcbd895e9d92315a05c1c3c9a29f762b011a10c54a66cd53c9b31",
+		}, selectorValues)
+	})
+
+	t.Run("container not in the configured namespaces", func(t *testing.T) {
+		p := loadPlugin(t, fmt.Sprintf(`containerd {
+	socket_path = %q
+	namespaces = ["default"]
+}`, socketPath), testCgroupEntries)
+		selectorValues, err := doAttest(t, p)
+		require.NoError(t, err)
+		require.Empty(t, selectorValues)
+	})
+
+	t.Run("not a container workload", func(t *testing.T) {
+		p := loadPlugin(t, config, "0::/user.slice/user-1000.slice/session-1.scope")
+		selectorValues, err := doAttest(t, p)
+		require.NoError(t, err)
+		require.Empty(t, selectorValues)
+	})
+
+	t.Run("containerd error", func(t *testing.T) {
+		failing := &fakeContainerd{err: status.Error(codes.Unavailable, "ohno")}
+		p := loadPlugin(t, fmt.Sprintf(`containerd {
+	socket_path = %q
+}`, failing.serve(t)), testCgroupEntries)
+		selectorValues, err := doAttest(t, p)
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, fmt.Sprintf("failed to get container %q from containerd", testContainerID))
+		require.Nil(t, selectorValues)
+	})
+}
+
+func TestAttestPodman(t *testing.T) {
+	socketPath := serveHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v4.0.0/libpod/containers/" + testContainerID + "/json":
+			_, _ = io.WriteString(w, `{
+	"Id": "`+testContainerID+`",
+	"Name": "billing",
+	"ImageName": "quay.io/example/billing:v1",
+	"ImageDigest": "sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb",
+	"Config": {"Labels": {"tier": "backend"}}
+}`)
+		case "/v4.0.0/libpod/containers/" + otherContainerID + "/json":
+			http.Error(w, `{"cause": "oops", "message": "internal error", "response": 500}`, http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	config := fmt.Sprintf(`podman {
+	socket_path = %q
+}`, socketPath)
+
+	t.Run("container found", func(t *testing.T) {
+		p := loadPlugin(t, config, testCgroupEntries)
+		selectorValues, err := doAttest(t, p)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"runtime:podman",
+			"name:billing",
+			"image:quay.io/example/billing:v1",
+			"image_digest:sha256:9b2a28eb47540823042a2ba401386845089bb7b62a9637d55816132c4c3c36eb",
+			"label:tier:backend",
+		}, selectorValues)
+	})
+
+	t.Run("container not found", func(t *testing.T) {
+		p := loadPlugin(t, config, "0::/machine.slice/libpod-0000000000000000000000000000000000000000000000000000000000000000.scope")
+		selectorValues, err := doAttest(t, p)
+		require.NoError(t, err)
+		require.Empty(t, selectorValues)
+	})
+
+	t.Run("podman error", func(t *testing.T) {
+		p := loadPlugin(t, config, "0::/machine.slice/libpod-"+otherContainerID+".scope")
+		selectorValues, err := doAttest(t, p)
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, "unexpected status code 500")
+		require.Nil(t, selectorValues)
+	})
+}
+
+func TestAttestCRIO(t *testing.T) {
+	crioSocketPath := serveHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/containers/"+testContainerID {
+			http.Error(w, "can't find the container", http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, `{
+	"name": "ingest",
+	"image": "quay.io/example/ingest:v2",
+	"image_ref": "quay.io/example/ingest@sha256:8c4b2f3f6a5f3d1d9d23ee5bd3e2a4b5d6f1e7e0ca2b2a1e9f3c8d7b6a5f4e3d",
+	"labels": {"team": "data"}
+}`)
+	}))
+	podmanSocketPath := serveHTTP(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"Name": "other"}`)
+	}))
+
+	// CRI-O is queried before podman, which is only asked about the
+	// containers that CRI-O does not know about.
+	config := fmt.Sprintf(`crio {
+	socket_path = %q
+}
+podman {
+	socket_path = %q
+}`, crioSocketPath, podmanSocketPath)
+
+	t.Run("container found", func(t *testing.T) {
+		p := loadPlugin(t, config, "0::/kubepods.slice/crio-"+testContainerID+".scope")
+		selectorValues, err := doAttest(t, p)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"runtime:crio",
+			"name:ingest",
+			"image:quay.io/example/ingest:v2",
+			"image_digest:sha256:8c4b2f3f6a5f3d1d9d23ee5bd3e2a4b5d6f1e7e0ca2b2a1e9f3c8d7b6a5f4e3d",
+			"label:team:data",
+		}, selectorValues)
+	})
+
+	t.Run("container found in the next runtime", func(t *testing.T) {
+		p := loadPlugin(t, config, "0::/machine.slice/libpod-"+otherContainerID+".scope")
+		selectorValues, err := doAttest(t, p)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"runtime:podman",
+			"name:other",
+		}, selectorValues)
+	})
+
+	t.Run("runtime unavailable", func(t *testing.T) {
+		p := loadPlugin(t, fmt.Sprintf(`crio {
+	socket_path = %q
+}`, filepath.Join(t.TempDir(), "missing.sock")), testCgroupEntries)
+		selectorValues, err := doAttest(t, p)
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, fmt.Sprintf("failed to get container %q from crio", testContainerID))
+		require.Nil(t, selectorValues)
+	})
+}
+
+func TestCgroupFileNotFound(t *testing.T) {
+	p := New()
+	p.fs = FakeFileSystem{}
+
+	v1 := new(workloadattestor.V1)
+	plugintest.Load(t, builtin(p), v1, plugintest.Configure("podman {}"))
+
+	selectors, err := v1.Attest(ctx, 123)
+	spiretest.RequireGRPCStatusContains(t, err, codes.Internal, "failed to get cgroups: file does not exist")
+	require.Nil(t, selectors)
+}
+
+func loadPlugin(t *testing.T, config string, cgroups string) workloadattestor.WorkloadAttestor {
+	p := New()
+	p.fs = newFakeFileSystem(cgroups)
+
+	v1 := new(workloadattestor.V1)
+	plugintest.Load(t, builtin(p), v1, plugintest.Configure(config))
+	return v1
+}
+
+func doAttest(t *testing.T, p workloadattestor.WorkloadAttestor) ([]string, error) {
+	selectors, err := p.Attest(ctx, 123)
+	if err != nil {
+		return nil, err
+	}
+	var selectorValues []string
+	for _, selector := range selectors {
+		require.Equal(t, pluginName, selector.Type)
+		selectorValues = append(selectorValues, selector.Value)
+	}
+	return selectorValues, nil
+}
+
+// socketPath returns a path for a unix socket. Paths under the test temporary
+// directory can exceed the maximum length of unix socket paths.
+func socketPath(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "container")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "runtime.sock")
+}
+
+func serveHTTP(t *testing.T, handler http.Handler) string {
+	path := socketPath(t)
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(handler)
+	server.Listener = listener
+	server.Start()
+	t.Cleanup(server.Close)
+	return path
+}
+
+type fakeContainerd struct {
+	// containers by namespace and ID
+	containers map[string]map[string]*containersv1.Container
+	err        error
+}
+
+func (c *fakeContainerd) serve(t *testing.T) string {
+	path := socketPath(t)
+	listener, err := net.Listen("unix", path)
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	containersv1.RegisterContainersServer(server, fakeContainersServer{c: c})
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return path
+}
+
+type fakeContainersServer struct {
+	containersv1.UnimplementedContainersServer
+	c *fakeContainerd
+}
+
+func (s fakeContainersServer) Get(ctx context.Context, req *containersv1.GetContainerRequest) (*containersv1.GetContainerResponse, error) {
+	if s.c.err != nil {
+		return nil, s.c.err
+	}
+	namespace, err := namespaces.NamespaceRequired(ctx)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	container, ok := s.c.containers[namespace][req.ID]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "container %q in namespace %q: not found", req.ID, namespace)
+	}
+	return &containersv1.GetContainerResponse{Container: container}, nil
+}
+
+func newFakeFileSystem(cgroups string) FakeFileSystem {
+	return FakeFileSystem{
+		Files: map[string]string{
+			"/proc/123/cgroup": cgroups,
+		},
+	}
+}
+
+type FakeFileSystem struct {
+	Files map[string]string
+}
+
+func (fs FakeFileSystem) Open(path string) (io.ReadCloser, error) {
+	data, ok := fs.Files[path]
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
@@ -0,0 +1,22 @@
+//go:build windows
+// +build windows
+
+package container
+
+import (
+	"testing"
+
+	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"google.golang.org/grpc/codes"
+)
+
+func TestConfigure(t *testing.T) {
+	var err error
+	p := new(workloadattestor.V1)
+	plugintest.Load(t, BuiltIn(), p,
+		plugintest.CaptureConfigureError(&err),
+		plugintest.Configure(""))
+	spiretest.RequireGRPCStatusContains(t, err, codes.Unimplemented, "plugin not supported in this platform")
+}
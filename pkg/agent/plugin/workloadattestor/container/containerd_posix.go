@@ -0,0 +1,92 @@
+//go:build !windows
+// +build !windows
+
+package container
+
+import (
+	"context"
+
+	containersv1 "github.com/containerd/containerd/api/services/containers/v1"
+	"github.com/containerd/containerd/namespaces"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultContainerdSocketPath = "/run/containerd/containerd.sock"
+)
+
+var (
+	defaultContainerdNamespaces = []string{"default", "k8s.io"}
+
+	// containerdNameLabels are the labels holding the container name, set
+	// by the clients creating the containers, in order of precedence.
+	containerdNameLabels = []string{
+		"nerdctl/name",
+		"io.kubernetes.container.name",
+	}
+)
+
+type containerdClient struct {
+	conn       *grpc.ClientConn
+	containers containersv1.ContainersClient
+	namespaces []string
+}
+
+func newContainerdClient(socketPath string, namespaces []string) (*containerdClient, error) {
+	// The connection is established lazily, so containerd does not need to
+	// be running when the plugin is configured.
+	conn, err := grpc.Dial("unix://"+socketPath, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	return &containerdClient{
+		conn:       conn,
+		containers: containersv1.NewContainersClient(conn),
+		namespaces: namespaces,
+	}, nil
+}
+
+func (c *containerdClient) Name() string {
+	return "containerd"
+}
+
+func (c *containerdClient) GetContainer(ctx context.Context, containerID string) (*containerInfo, error) {
+	for _, namespace := range c.namespaces {
+		nsCtx := namespaces.WithNamespace(ctx, namespace)
+
+		resp, err := c.containers.Get(nsCtx, &containersv1.GetContainerRequest{ID: containerID})
+		switch {
+		case status.Code(err) == codes.NotFound:
+			continue
+		case err != nil:
+			return nil, err
+		}
+
+		// The image store is not queried for the image digest, since the
+		// image name may have been pointed at a different image after the
+		// container was created. The digest is only known if the container
+		// was created from an image reference including it.
+		info := &containerInfo{
+			Namespace:   namespace,
+			Image:       resp.Container.Image,
+			ImageDigest: imageDigestFromRef(resp.Container.Image),
+			Labels:      resp.Container.Labels,
+		}
+		for _, label := range containerdNameLabels {
+			if name, ok := info.Labels[label]; ok {
+				info.Name = name
+				break
+			}
+		}
+
+		return info, nil
+	}
+	return nil, nil
+}
+
+func (c *containerdClient) Close() error {
+	return c.conn.Close()
+}
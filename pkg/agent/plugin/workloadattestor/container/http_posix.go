@@ -0,0 +1,142 @@
+//go:build !windows
+// +build !windows
+
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	defaultPodmanSocketPath = "/run/podman/podman.sock"
+	defaultCRIOSocketPath   = "/var/run/crio/crio.sock"
+
+	// maxErrorBodySize is the maximum number of bytes of an error response
+	// body included in the returned errors.
+	maxErrorBodySize = 1024
+)
+
+// podmanClient queries the libpod REST API (podman v4 or newer).
+type podmanClient struct {
+	client *http.Client
+}
+
+func newPodmanClient(socketPath string) *podmanClient {
+	return &podmanClient{client: newUnixSocketHTTPClient(socketPath)}
+}
+
+func (c *podmanClient) Name() string {
+	return "podman"
+}
+
+func (c *podmanClient) GetContainer(ctx context.Context, containerID string) (*containerInfo, error) {
+	var inspect struct {
+		Name        string `json:"Name"`
+		ImageName   string `json:"ImageName"`
+		ImageDigest string `json:"ImageDigest"`
+		Config      struct {
+			Labels map[string]string `json:"Labels"`
+		} `json:"Config"`
+	}
+	found, err := getJSON(ctx, c.client, "/v4.0.0/libpod/containers/"+url.PathEscape(containerID)+"/json", &inspect)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &containerInfo{
+		Name:        inspect.Name,
+		Image:       inspect.ImageName,
+		ImageDigest: inspect.ImageDigest,
+		Labels:      inspect.Config.Labels,
+	}, nil
+}
+
+func (c *podmanClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// crioClient queries the CRI-O inspect endpoint.
+type crioClient struct {
+	client *http.Client
+}
+
+func newCRIOClient(socketPath string) *crioClient {
+	return &crioClient{client: newUnixSocketHTTPClient(socketPath)}
+}
+
+func (c *crioClient) Name() string {
+	return "crio"
+}
+
+func (c *crioClient) GetContainer(ctx context.Context, containerID string) (*containerInfo, error) {
+	var inspect struct {
+		Name     string            `json:"name"`
+		Image    string            `json:"image"`
+		ImageRef string            `json:"image_ref"`
+		Labels   map[string]string `json:"labels"`
+	}
+	found, err := getJSON(ctx, c.client, "/containers/"+url.PathEscape(containerID), &inspect)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &containerInfo{
+		Name:        inspect.Name,
+		Image:       inspect.Image,
+		ImageDigest: imageDigestFromRef(inspect.ImageRef),
+		Labels:      inspect.Labels,
+	}, nil
+}
+
+func (c *crioClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+func newUnixSocketHTTPClient(socketPath string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socketPath)
+			},
+		},
+	}
+}
+
+// getJSON gets the resource at the given path and decodes it into out. It
+// returns false if the resource does not exist.
+func getJSON(ctx context.Context, client *http.Client, path string, out interface{}) (bool, error) {
+	// The host is ignored since requests are sent over the unix socket
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost"+path, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return false, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,48 @@
+package cgroup
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spiffe/spire/pkg/agent/common/cgroups"
+)
+
+// ContainerIDFromCgroups returns the container ID from a set of cgroups
+// using the given finder. The container ID found on each cgroup path (if any)
+// must be consistent. If no container ID is found among the cgroups, i.e.,
+// this isn't a container workload, the function returns an empty string. If
+// more than one container ID is found, or the "found" container ID is blank,
+// the function will fail.
+func ContainerIDFromCgroups(finder ContainerIDFinder, cgroupList []cgroups.Cgroup) (string, error) {
+	var hasContainerEntries bool
+	var containerID string
+	for _, cgroup := range cgroupList {
+		candidate, ok := finder.FindContainerID(cgroup.GroupPath)
+		if !ok {
+			continue
+		}
+
+		hasContainerEntries = true
+
+		switch {
+		case containerID == "":
+			// This is the first container ID found so far.
+			containerID = candidate
+		case containerID != candidate:
+			// More than one container ID found in the cgroups.
+			return "", fmt.Errorf("multiple container IDs found in cgroups (%s, %s)", containerID, candidate)
+		}
+	}
+
+	switch {
+	case !hasContainerEntries:
+		return "", nil
+	case containerID == "":
+		// The "finder" found a container ID, but it was blank. This is a
+		// defensive measure against bad matcher patterns and shouldn't
+		// be possible with the default finders.
+		return "", errors.New("a pattern matched, but no container id was found")
+	default:
+		return containerID, nil
+	}
+}
@@ -0,0 +1,60 @@
+package cgroup
+
+import (
+	"testing"
+
+	"github.com/spiffe/spire/pkg/agent/common/cgroups"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerIDFromCgroups(t *testing.T) {
+	finder, err := NewContainerIDFinder([]string{"/docker/<id>"})
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		desc      string
+		paths     []string
+		expectID  string
+		expectErr string
+	}{
+		{
+			desc:  "no cgroups",
+			paths: nil,
+		},
+		{
+			desc:  "not a container",
+			paths: []string{"/user.slice", "/"},
+		},
+		{
+			desc:     "same ID in every matching cgroup",
+			paths:    []string{"/docker/foo", "/user.slice", "/docker/foo"},
+			expectID: "foo",
+		},
+		{
+			desc:      "more than one ID",
+			paths:     []string{"/docker/foo", "/docker/bar"},
+			expectErr: "multiple container IDs found in cgroups (foo, bar)",
+		},
+		{
+			desc:      "blank ID",
+			paths:     []string{"/docker/"},
+			expectErr: "a pattern matched, but no container id was found",
+		},
+	} {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			var cgroupList []cgroups.Cgroup
+			for _, path := range tt.paths {
+				cgroupList = append(cgroupList, cgroups.Cgroup{GroupPath: path})
+			}
+
+			containerID, err := ContainerIDFromCgroups(finder, cgroupList)
+			if tt.expectErr != "" {
+				require.EqualError(t, err, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectID, containerID)
+		})
+	}
+}
@@ -4,7 +4,6 @@
 package docker
 
 import (
-	"errors"
 	"fmt"
 	"regexp"
 
@@ -49,56 +48,21 @@ func (h *containerHelper) getContainerID(pID int32, _ hclog.Logger) (string, err
 		return "", err
 	}
 
-	return getContainerIDFromCGroups(h.containerIDFinder, cgroupList)
+	containerID, err := cgroup.ContainerIDFromCgroups(h.containerIDFinder, cgroupList)
+	if err != nil {
+		return "", fmt.Errorf("workloadattestor/docker: %w", err)
+	}
+
+	// An empty container ID means this is not a docker workload. Since it is
+	// expected that non-docker workloads will call the workload API, it is
+	// fine to return a response without any selectors.
+	return containerID, nil
 }
 
 func getDockerHost(c *dockerPluginConfig) string {
 	return c.DockerSocketPath
 }
 
-// getContainerIDFromCGroups returns the container ID from a set of cgroups
-// using the given finder. The container ID found on each cgroup path (if any)
-// must be consistent. If no container ID is found among the cgroups, i.e.,
-// this isn't a docker workload, the function returns an empty string. If more
-// than one container ID is found, or the "found" container ID is blank, the
-// function will fail.
-func getContainerIDFromCGroups(finder cgroup.ContainerIDFinder, cgroups []cgroups.Cgroup) (string, error) {
-	var hasDockerEntries bool
-	var containerID string
-	for _, cgroup := range cgroups {
-		candidate, ok := finder.FindContainerID(cgroup.GroupPath)
-		if !ok {
-			continue
-		}
-
-		hasDockerEntries = true
-
-		switch {
-		case containerID == "":
-			// This is the first container ID found so far.
-			containerID = candidate
-		case containerID != candidate:
-			// More than one container ID found in the cgroups.
-			return "", fmt.Errorf("workloadattestor/docker: multiple container IDs found in cgroups (%s, %s)",
-				containerID, candidate)
-		}
-	}
-
-	switch {
-	case !hasDockerEntries:
-		// Not a docker workload. Since it is expected that non-docker workloads will call the
-		// workload API, it is fine to return a response without any selectors.
-		return "", nil
-	case containerID == "":
-		// The "finder" found a container ID, but it was blank. This is a
-		// defensive measure against bad matcher patterns and shouldn't
-		// be possible with the default finder.
-		return "", errors.New("workloadattestor/docker: a pattern matched, but no container id was found")
-	default:
-		return containerID, nil
-	}
-}
-
 type defaultContainerIDFinder struct{}
 
 // FindContainerID returns the container ID in the given cgroup path. The cgroup
@@ -5,13 +5,13 @@ package unix
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/user"
-	"path/filepath"
 	"runtime"
-	"strconv"
 	"strings"
 	"sync"
 
@@ -20,12 +20,23 @@ import (
 	"github.com/shirou/gopsutil/v3/process"
 	workloadattestorv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/agent/workloadattestor/v1"
 	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/agent/common/procfs"
 	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/common/util"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+const (
+	// defaultProcessInfoSizeLimit is the default limit, in bytes, of the
+	// command-line arguments and environment read from a workload.
+	defaultProcessInfoSizeLimit = 64 * 1024
+
+	// maxArgsPrefixes is the maximum number of "args_prefix" selectors
+	// produced for a workload.
+	maxArgsPrefixes = 16
+)
+
 func builtin(p *Plugin) catalog.BuiltIn {
 	return catalog.MakeBuiltIn(pluginName,
 		workloadattestorv1.WorkloadAttestorPluginServer(p),
@@ -39,6 +50,10 @@ type processInfo interface {
 	Groups() ([]string, error)
 	Exe() (string, error)
 	NamespacedExe() string
+	Args(limit int64) ([]string, error)
+	Env(limit int64) ([]string, error)
+	Cwd() (string, error)
+	Ppid() (int32, error)
 }
 
 type PSProcessInfo struct {
@@ -46,7 +61,7 @@ type PSProcessInfo struct {
 }
 
 func (ps PSProcessInfo) NamespacedExe() string {
-	return getProcPath(ps.Pid, "exe")
+	return procfs.Path(ps.Pid, "exe")
 }
 
 // Groups returns the supplementary group IDs
@@ -57,7 +72,7 @@ func (ps PSProcessInfo) Groups() ([]string, error) {
 		return []string{}, nil
 	}
 
-	statusPath := getProcPath(ps.Pid, "status")
+	statusPath := procfs.Path(ps.Pid, "status")
 
 	f, err := os.Open(statusPath)
 	if err != nil {
@@ -87,9 +102,47 @@ func (ps PSProcessInfo) Groups() ([]string, error) {
 	return []string{}, nil
 }
 
+// Args returns the command-line arguments of the process, failing if they
+// exceed the given size limit. Only supported on linux.
+func (ps PSProcessInfo) Args(limit int64) ([]string, error) {
+	return readProcList(ps.Pid, "cmdline", limit)
+}
+
+// Env returns the initial environment of the process, failing if it exceeds
+// the given size limit. Only supported on linux.
+func (ps PSProcessInfo) Env(limit int64) ([]string, error) {
+	return readProcList(ps.Pid, "environ", limit)
+}
+
 type Configuration struct {
-	DiscoverWorkloadPath bool  `hcl:"discover_workload_path"`
-	WorkloadSizeLimit    int64 `hcl:"workload_size_limit"`
+	DiscoverWorkloadPath       bool     `hcl:"discover_workload_path"`
+	WorkloadSizeLimit          int64    `hcl:"workload_size_limit"`
+	DiscoverWorkloadArgs       bool     `hcl:"discover_workload_args"`
+	WorkloadEnvVars            []string `hcl:"workload_env_vars"`
+	DiscoverWorkloadCwd        bool     `hcl:"discover_workload_cwd"`
+	DiscoverWorkloadParentPath bool     `hcl:"discover_workload_parent_path"`
+	ProcessInfoSizeLimit       *int64   `hcl:"process_info_size_limit"`
+}
+
+func (c *Configuration) validate() error {
+	for _, name := range c.WorkloadEnvVars {
+		if name == "" || strings.Contains(name, "=") {
+			return status.Errorf(codes.InvalidArgument, "invalid environment variable name %q", name)
+		}
+	}
+	if runtime.GOOS != "linux" {
+		switch {
+		case c.DiscoverWorkloadArgs:
+			return status.Error(codes.InvalidArgument, "discover_workload_args is only supported on linux")
+		case len(c.WorkloadEnvVars) > 0:
+			return status.Error(codes.InvalidArgument, "workload_env_vars is only supported on linux")
+		case c.DiscoverWorkloadCwd:
+			return status.Error(codes.InvalidArgument, "discover_workload_cwd is only supported on linux")
+		case c.DiscoverWorkloadParentPath:
+			return status.Error(codes.InvalidArgument, "discover_workload_parent_path is only supported on linux")
+		}
+	}
+	return nil
 }
 
 type Plugin struct {
@@ -187,6 +240,45 @@ func (p *Plugin) Attest(_ context.Context, req *workloadattestorv1.AttestRequest
 		}
 	}
 
+	// the selectors below are opt-in since they are based on information
+	// that might be sensitive (e.g. environment variables) and, as with the
+	// workload path, require additional permissions.
+	//
+	// like the workload size limit, a negative process info size limit
+	// disables reading the arguments and environment entirely.
+	processInfoSizeLimit := *config.ProcessInfoSizeLimit
+	if config.DiscoverWorkloadArgs && processInfoSizeLimit >= 0 {
+		args, err := proc.Args(processInfoSizeLimit)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "args lookup: %v", err)
+		}
+		selectorValues = append(selectorValues, makeArgsSelectorValues(args)...)
+	}
+
+	if len(config.WorkloadEnvVars) > 0 && processInfoSizeLimit >= 0 {
+		env, err := proc.Env(processInfoSizeLimit)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "environment lookup: %v", err)
+		}
+		selectorValues = append(selectorValues, makeEnvSelectorValues(env, config.WorkloadEnvVars)...)
+	}
+
+	if config.DiscoverWorkloadCwd {
+		cwd, err := proc.Cwd()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "working directory lookup: %v", err)
+		}
+		selectorValues = append(selectorValues, makeSelectorValue("cwd", cwd))
+	}
+
+	if config.DiscoverWorkloadParentPath {
+		parentPath, err := p.getParentPath(proc)
+		if err != nil {
+			return nil, err
+		}
+		selectorValues = append(selectorValues, makeSelectorValue("parent_path", parentPath))
+	}
+
 	return &workloadattestorv1.AttestResponse{
 		SelectorValues: selectorValues,
 	}, nil
@@ -197,6 +289,13 @@ func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*
 	if err := hcl.Decode(config, req.HclConfiguration); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "failed to decode configuration: %v", err)
 	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if config.ProcessInfoSizeLimit == nil {
+		processInfoSizeLimit := int64(defaultProcessInfoSizeLimit)
+		config.ProcessInfoSizeLimit = &processInfoSizeLimit
+	}
 	p.setConfig(config)
 	return &configv1.ConfigureResponse{}, nil
 }
@@ -280,14 +379,107 @@ func (p *Plugin) getNamespacedPath(proc processInfo) string {
 	return proc.NamespacedExe()
 }
 
+func (p *Plugin) getParentPath(proc processInfo) (string, error) {
+	ppid, err := proc.Ppid()
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "parent PID lookup: %v", err)
+	}
+
+	parent, err := p.hooks.newProcess(ppid)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to get parent process: %v", err)
+	}
+
+	path, err := parent.Exe()
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "parent path lookup: %v", err)
+	}
+	return path, nil
+}
+
 func makeSelectorValue(kind, value string) string {
 	return fmt.Sprintf("%s:%s", kind, value)
 }
 
-func getProcPath(pID int32, lastPath string) string {
-	procPath := os.Getenv("HOST_PROC")
-	if procPath == "" {
-		procPath = "/proc"
+// makeArgsSelectorValues returns the "args" selector, holding the whole
+// command line, and an "args_prefix" selector for each leading subset of up
+// to maxArgsPrefixes arguments. Arguments are joined with a single space.
+func makeArgsSelectorValues(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	numPrefixes := len(args)
+	if numPrefixes > maxArgsPrefixes {
+		numPrefixes = maxArgsPrefixes
+	}
+
+	selectorValues := make([]string, 0, numPrefixes+1)
+	selectorValues = append(selectorValues, makeSelectorValue("args", strings.Join(args, " ")))
+	for i := 0; i < numPrefixes; i++ {
+		selectorValues = append(selectorValues, makeSelectorValue("args_prefix", strings.Join(args[:i+1], " ")))
+	}
+	return selectorValues
+}
+
+// makeEnvSelectorValues returns an "env" selector for each of the given
+// variable names that is set in the environment.
+func makeEnvSelectorValues(env []string, names []string) []string {
+	values := make(map[string]string, len(env))
+	for _, kv := range env {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		// Like getenv(3), the first definition wins
+		if _, ok := values[name]; !ok {
+			values[name] = value
+		}
+	}
+
+	var selectorValues []string
+	for _, name := range names {
+		if value, ok := values[name]; ok {
+			selectorValues = append(selectorValues, makeSelectorValue("env", name+":"+value))
+		}
+	}
+	return selectorValues
+}
+
+// readProcList reads a NUL-separated list (e.g. cmdline or environ) from the
+// proc filesystem, failing if the contents exceed the given limit. If the
+// limit is zero, no limit is enforced.
+func readProcList(pID int32, lastPath string, limit int64) ([]string, error) {
+	path := procfs.Path(pID, lastPath)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// The size of proc files is not known ahead of time, so read up to one
+	// byte past the limit to detect that it was exceeded.
+	var r io.Reader = f
+	if limit > 0 {
+		r = io.LimitReader(f, limit+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s exceeds size limit (%d)", path, limit)
+	}
+
+	data = bytes.TrimSuffix(data, []byte{0})
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var list []string
+	for _, item := range bytes.Split(data, []byte{0}) {
+		list = append(list, string(item))
 	}
-	return filepath.Join(procPath, strconv.FormatInt(int64(pID), 10), lastPath)
+	return list, nil
 }
@@ -10,8 +10,10 @@ import (
 	"os/user"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/shirou/gopsutil/v3/process"
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/spiffe/spire/pkg/agent/plugin/workloadattestor"
@@ -227,6 +229,104 @@ func (s *Suite) TestAttest() {
 			expectCode: codes.Internal,
 			expectMsg:  "workloadattestor(unix): supplementary GIDs lookup: some error for PID 14",
 		},
+		{
+			name:   "success getting args",
+			pid:    15,
+			config: "discover_workload_args = true",
+			selectorValues: []string{
+				"uid:1000",
+				"user:u1000",
+				"gid:2000",
+				"group:g2000",
+				"args:java -jar app.jar",
+				"args_prefix:java",
+				"args_prefix:java -jar",
+				"args_prefix:java -jar app.jar",
+			},
+		},
+		{
+			name:   "args and environment are not read with a negative process info size limit",
+			pid:    16,
+			config: "discover_workload_args = true\nworkload_env_vars = [\"FOO\"]\nprocess_info_size_limit = -1",
+			selectorValues: []string{
+				"uid:1000",
+				"user:u1000",
+				"gid:2000",
+				"group:g2000",
+			},
+		},
+		{
+			name:       "fail to get args",
+			pid:        16,
+			config:     "discover_workload_args = true",
+			expectCode: codes.Internal,
+			expectMsg:  "workloadattestor(unix): args lookup: unable to get args for PID 16",
+		},
+		{
+			name:   "success getting environment variables",
+			pid:    17,
+			config: `workload_env_vars = ["FOO", "EMPTY", "MISSING"]`,
+			selectorValues: []string{
+				"uid:1000",
+				"user:u1000",
+				"gid:2000",
+				"group:g2000",
+				"env:FOO:bar",
+				"env:EMPTY:",
+			},
+		},
+		{
+			name:       "fail to get environment variables",
+			pid:        18,
+			config:     `workload_env_vars = ["FOO"]`,
+			expectCode: codes.Internal,
+			expectMsg:  "workloadattestor(unix): environment lookup: unable to get environment for PID 18",
+		},
+		{
+			name:   "success getting working directory",
+			pid:    19,
+			config: "discover_workload_cwd = true",
+			selectorValues: []string{
+				"uid:1000",
+				"user:u1000",
+				"gid:2000",
+				"group:g2000",
+				"cwd:/srv/app",
+			},
+		},
+		{
+			name:       "fail to get working directory",
+			pid:        20,
+			config:     "discover_workload_cwd = true",
+			expectCode: codes.Internal,
+			expectMsg:  "workloadattestor(unix): working directory lookup: unable to get cwd for PID 20",
+		},
+		{
+			name:   "success getting parent path",
+			pid:    21,
+			config: "discover_workload_parent_path = true",
+			selectorValues: []string{
+				"uid:1000",
+				"user:u1000",
+				"gid:2000",
+				"group:g2000",
+				fmt.Sprintf("parent_path:%s", filepath.Join(s.dir, "exe")),
+			},
+		},
+		{
+			name:       "fail to get parent pid",
+			pid:        22,
+			config:     "discover_workload_parent_path = true",
+			expectCode: codes.Internal,
+			expectMsg:  "workloadattestor(unix): parent PID lookup: unable to get parent PID for PID 22",
+		},
+		{
+			name:       "fail to get parent path",
+			pid:        23,
+			config:     "discover_workload_parent_path = true",
+			expectCode: codes.Internal,
+			expectMsg:  "workloadattestor(unix): parent path lookup: unable to get EXE for PID 9",
+		},
 	}
 
 	// prepare the "exe" for hashing
@@ -259,6 +359,84 @@ func (s *Suite) TestAttest() {
 	}
 }
 
+func (s *Suite) TestConfigure() {
+	for _, tt := range []struct {
+		name       string
+		config     string
+		expectCode codes.Code
+		expectMsg  string
+	}{
+		{
+			name:   "success",
+			config: "discover_workload_args = true\nworkload_env_vars = [\"FOO\"]\nprocess_info_size_limit = 4096",
+		},
+		{
+			name:   "negative process info size limit",
+			config: "discover_workload_args = true\nprocess_info_size_limit = -1",
+		},
+		{
+			name:       "invalid environment variable name",
+			config:     `workload_env_vars = ["FOO=BAR"]`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `invalid environment variable name "FOO=BAR"`,
+		},
+		{
+			name:       "empty environment variable name",
+			config:     `workload_env_vars = [""]`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `invalid environment variable name ""`,
+		},
+	} {
+		tt := tt
+		s.T().Run(tt.name, func(t *testing.T) {
+			var err error
+			plugintest.Load(t, builtin(s.newPlugin()), nil,
+				plugintest.CaptureConfigureError(&err),
+				plugintest.Configure(tt.config))
+			spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMsg)
+		})
+	}
+}
+
+func (s *Suite) TestReadProcList() {
+	s.T().Setenv("HOST_PROC", s.dir)
+	s.Require().NoError(os.Mkdir(filepath.Join(s.dir, "42"), 0755))
+	s.writeFile("42/cmdline", []byte("python\x00app.py\x00\x00--debug\x00"))
+	s.writeFile("42/environ", []byte{})
+
+	args, err := PSProcessInfo{&process.Process{Pid: 42}}.Args(0)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"python", "app.py", "", "--debug"}, args)
+
+	args, err = PSProcessInfo{&process.Process{Pid: 42}}.Args(23)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"python", "app.py", "", "--debug"}, args)
+
+	_, err = PSProcessInfo{&process.Process{Pid: 42}}.Args(22)
+	s.Require().EqualError(err, fmt.Sprintf("%s exceeds size limit (22)", filepath.Join(s.dir, "42", "cmdline")))
+
+	env, err := PSProcessInfo{&process.Process{Pid: 42}}.Env(0)
+	s.Require().NoError(err)
+	s.Require().Empty(env)
+
+	_, err = PSProcessInfo{&process.Process{Pid: 43}}.Env(0)
+	s.Require().Error(err)
+}
+
+func TestMakeArgsSelectorValues(t *testing.T) {
+	require.Nil(t, makeArgsSelectorValues(nil))
+
+	args := make([]string, maxArgsPrefixes+4)
+	for i := range args {
+		args[i] = strconv.Itoa(i)
+	}
+	selectorValues := makeArgsSelectorValues(args)
+	require.Len(t, selectorValues, maxArgsPrefixes+1)
+	require.Equal(t, "args:"+strings.Join(args, " "), selectorValues[0])
+	require.Equal(t, "args_prefix:0", selectorValues[1])
+	require.Equal(t, "args_prefix:"+strings.Join(args[:maxArgsPrefixes], " "), selectorValues[maxArgsPrefixes])
+}
+
 func (s *Suite) writeFile(path string, data []byte) {
 	s.Require().NoError(os.WriteFile(filepath.Join(s.dir, path), data, 0600))
 }
@@ -296,7 +474,7 @@ func (p fakeProcess) Uids() ([]int32, error) {
 		return nil, fmt.Errorf("unable to get UIDs for PID %d", p.pid)
 	case 3:
 		return []int32{1999}, nil
-	case 4, 5, 6, 7, 9, 10, 11, 12, 13, 14:
+	case 4, 5, 6, 7, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23:
 		return []int32{1000}, nil
 	case 8:
 		return []int32{1000, 1100}, nil
@@ -313,7 +491,7 @@ func (p fakeProcess) Gids() ([]int32, error) {
 		return nil, fmt.Errorf("unable to get GIDs for PID %d", p.pid)
 	case 6:
 		return []int32{2999}, nil
-	case 3, 7, 9, 10, 11, 12, 13, 14:
+	case 3, 7, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23:
 		return []int32{2000}, nil
 	case 8:
 		return []int32{2000, 2100}, nil
@@ -355,6 +533,47 @@ func (p fakeProcess) NamespacedExe() string {
 	}
 }
 
+func (p fakeProcess) Args(limit int64) ([]string, error) {
+	switch p.pid {
+	case 15:
+		if limit != defaultProcessInfoSizeLimit {
+			return nil, fmt.Errorf("unexpected size limit %d", limit)
+		}
+		return []string{"java", "-jar", "app.jar"}, nil
+	default:
+		return nil, fmt.Errorf("unable to get args for PID %d", p.pid)
+	}
+}
+
+func (p fakeProcess) Env(int64) ([]string, error) {
+	switch p.pid {
+	case 17:
+		return []string{"HOME=/root", "FOO=bar", "EMPTY=", "FOO=baz"}, nil
+	default:
+		return nil, fmt.Errorf("unable to get environment for PID %d", p.pid)
+	}
+}
+
+func (p fakeProcess) Cwd() (string, error) {
+	switch p.pid {
+	case 19:
+		return "/srv/app", nil
+	default:
+		return "", fmt.Errorf("unable to get cwd for PID %d", p.pid)
+	}
+}
+
+func (p fakeProcess) Ppid() (int32, error) {
+	switch p.pid {
+	case 21:
+		return 12, nil
+	case 23:
+		return 9, nil
+	default:
+		return 0, fmt.Errorf("unable to get parent PID for PID %d", p.pid)
+	}
+}
+
 func newFakeProcess(pid int32, dir string) processInfo {
 	return fakeProcess{pid: pid, dir: dir}
 }
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/andres-erbsen/clock"
 	"github.com/imkira/go-observer"
@@ -22,6 +23,7 @@ import (
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	telemetry_agent "github.com/spiffe/spire/pkg/common/telemetry/agent"
 	"github.com/spiffe/spire/pkg/common/util"
+	"github.com/spiffe/spire/pkg/common/x509util"
 	"google.golang.org/grpc"
 )
 
@@ -32,6 +34,10 @@ type Rotator interface {
 	Subscribe() observer.Stream
 	GetRotationMtx() *sync.RWMutex
 	SetRotationFinishedHook(func())
+
+	// NotifyTaintedAuthorities forces the rotation of the agent SVID if it
+	// was signed by one of the provided tainted X.509 authorities.
+	NotifyTaintedAuthorities([]*x509.Certificate)
 }
 
 type Client interface {
@@ -58,6 +64,11 @@ type rotator struct {
 
 	// Hook that will be called when the SVID rotation finishes
 	rotationFinishedHook func()
+
+	// Set when the current SVID was signed by a tainted authority
+	tainted atomic.Bool
+	// Used to trigger a rotation right away when the SVID is tainted
+	taintedCh chan struct{}
 }
 
 type State struct {
@@ -105,6 +116,7 @@ func (r *rotator) runRotation(ctx context.Context) error {
 		case <-ctx.Done():
 			return ctx.Err()
 		case <-r.clk.After(r.backoff.NextBackOff()):
+		case <-r.taintedCh:
 		}
 	}
 }
@@ -138,21 +150,45 @@ func (r *rotator) SetRotationFinishedHook(f func()) {
 	r.rotationFinishedHook = f
 }
 
+func (r *rotator) NotifyTaintedAuthorities(taintedAuthorities []*x509.Certificate) {
+	state, ok := r.state.Value().(State)
+	if !ok {
+		r.c.Log.Errorf("Unexpected value type: %T", r.state.Value())
+		return
+	}
+
+	if r.tainted.Load() || !x509util.IsSignedByRoot(state.SVID, taintedAuthorities) {
+		return
+	}
+
+	r.c.Log.Info("Agent SVID is signed by a tainted authority, forcing rotation")
+	telemetry_agent.IncrTaintedAgentSVIDCounter(r.c.Metrics)
+	r.tainted.Store(true)
+
+	select {
+	case r.taintedCh <- struct{}{}:
+	default:
+	}
+}
+
 func (r *rotator) rotateSVIDIfNeeded(ctx context.Context) (err error) {
 	state, ok := r.state.Value().(State)
 	if !ok {
 		return fmt.Errorf("unexpected value type: %T", r.state.Value())
 	}
 
-	if rotationutil.ShouldRotateX509(r.clk.Now(), state.SVID[0]) {
+	if r.tainted.Load() || rotationutil.ShouldRotateX509(r.clk.Now(), state.SVID[0]) {
 		if state.Reattestable && fflag.IsSet(fflag.FlagReattestToRenew) {
 			err = r.reattest(ctx)
 		} else {
 			err = r.rotateSVID(ctx)
 		}
 
-		if err == nil && r.rotationFinishedHook != nil {
-			r.rotationFinishedHook()
+		if err == nil {
+			r.tainted.Store(false)
+			if r.rotationFinishedHook != nil {
+				r.rotationFinishedHook()
+			}
 		}
 	}
 
@@ -93,5 +93,7 @@ func newRotator(c *RotatorConfig) (*rotator, client.Client) {
 		backoff: backoff.NewBackoff(c.Clk, c.Interval),
 		bsm:     bsm,
 		rotMtx:  rotMtx,
+
+		taintedCh: make(chan struct{}, 1),
 	}, client
 }
@@ -375,6 +375,62 @@ func TestRotationFails(t *testing.T) {
 	}
 }
 
+func TestRotatorTaintedAuthority(t *testing.T) {
+	caCert, caKey := testca.CreateCACertificate(t, nil, nil)
+	newCACert, newCAKey := testca.CreateCACertificate(t, nil, nil)
+
+	svidKM := keymanager.ForSVID(fakeagentkeymanager.New(t, ""))
+	clk := clock.NewMock(t)
+	log, hook := test.NewNullLogger()
+	mockClient := &fakeClient{
+		clk:    clk,
+		caCert: newCACert,
+		caKey:  newCAKey,
+	}
+
+	bundle := make(map[spiffeid.TrustDomain]*spiffebundle.Bundle)
+	bundle[trustDomain] = spiffebundle.FromX509Authorities(trustDomain, []*x509.Certificate{caCert, newCACert})
+
+	// Create a starting SVID that is not about to expire
+	svidKey, err := svidKM.GenerateKey(context.Background(), nil)
+	require.NoError(t, err)
+	svid, err := createTestSVID(svidKey.Public(), caCert, caKey, clk.Now(), clk.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	rotator, _ := newRotator(&RotatorConfig{
+		SVIDKeyManager: svidKM,
+		Log:            log,
+		Metrics:        telemetry.Blackhole{},
+		TrustDomain:    trustDomain,
+		BundleStream:   cache.NewBundleStream(observer.NewProperty(bundle).Observe()),
+		Clk:            clk,
+		SVID:           svid,
+		SVIDKey:        svidKey,
+	})
+	rotator.client = mockClient
+
+	// No rotation is needed while the signing authority is not tainted
+	rotator.NotifyTaintedAuthorities([]*x509.Certificate{newCACert})
+	require.False(t, rotator.tainted.Load())
+	require.NoError(t, rotator.rotateSVIDIfNeeded(context.Background()))
+	require.Equal(t, svid, rotator.State().SVID)
+
+	// Tainting the signing authority forces a rotation
+	rotator.NotifyTaintedAuthorities([]*x509.Certificate{caCert})
+	require.True(t, rotator.tainted.Load())
+	require.Len(t, rotator.taintedCh, 1)
+	require.Equal(t, "Agent SVID is signed by a tainted authority, forcing rotation", hook.LastEntry().Message)
+
+	require.NoError(t, rotator.rotateSVIDIfNeeded(context.Background()))
+	require.False(t, rotator.tainted.Load())
+	require.NotEqual(t, svid, rotator.State().SVID)
+	require.NoError(t, rotator.State().SVID[0].CheckSignatureFrom(newCACert))
+
+	// The new SVID is not affected by the tainted authority
+	rotator.NotifyTaintedAuthorities([]*x509.Certificate{caCert})
+	require.False(t, rotator.tainted.Load())
+}
+
 type fakeClient struct {
 	clk          clock.Clock
 	caCert       *x509.Certificate
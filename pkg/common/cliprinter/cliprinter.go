@@ -2,15 +2,21 @@ package cliprinter
 
 import (
 	"errors"
+	"fmt"
 	"io"
 
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/errorjson"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/errorpretty"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/erroryaml"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protojson"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protopretty"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/prototable"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protoyaml"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/structjson"
 	"github.com/spiffe/spire/pkg/common/cliprinter/internal/structpretty"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/structtable"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/structyaml"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -33,9 +39,10 @@ type CustomPrettyFunc func(*commoncli.Env, ...interface{}) error
 var ErrInternalCustomPrettyFunc = errors.New("internal error: cli printer; please report this bug")
 
 type printer struct {
-	format formatType
-	env    *commoncli.Env
-	cp     CustomPrettyFunc
+	format  formatType
+	env     *commoncli.Env
+	cp      CustomPrettyFunc
+	columns []string
 }
 
 func newPrinter(f formatType, env *commoncli.Env) *printer {
@@ -55,18 +62,35 @@ func (p *printer) PrintError(err error) error {
 
 // PrintProto prints a protobuf message and applies the configured formatting.
 func (p *printer) PrintProto(msg ...proto.Message) error {
+	if err := p.checkColumns(); err != nil {
+		return err
+	}
 	return p.printProto(msg...)
 }
 
 // PrintStruct prints a struct and applies the configured formatting.
 func (p *printer) PrintStruct(msg ...interface{}) error {
+	if err := p.checkColumns(); err != nil {
+		return err
+	}
 	return p.printStruct(msg)
 }
 
+// checkColumns returns an error if columns were selected for a format other
+// than table, which is the only one that honors them.
+func (p *printer) checkColumns() error {
+	if len(p.columns) > 0 && p.format != table {
+		return fmt.Errorf("the -%s flag can only be used with the %s output format", columnsFlagName, formatTypeToStr(table))
+	}
+	return nil
+}
+
 func (p *printer) printError(err error) error {
 	switch p.format {
 	case json:
 		return errorjson.Print(err, p.env.Stdout, p.env.Stderr)
+	case yaml:
+		return erroryaml.Print(err, p.env.Stdout, p.env.Stderr)
 	default:
 		return p.printPrettyError(err, p.env.Stdout, p.env.Stderr)
 	}
@@ -76,6 +100,10 @@ func (p *printer) printProto(msg ...proto.Message) error {
 	switch p.format {
 	case json:
 		return protojson.Print(msg, p.env.Stdout, p.env.Stderr)
+	case yaml:
+		return protoyaml.Print(msg, p.env.Stdout, p.env.Stderr)
+	case table:
+		return prototable.Print(msg, p.columns, p.env.Stdout, p.env.Stderr)
 	default:
 		return p.printPrettyProto(msg, p.env.Stdout, p.env.Stderr)
 	}
@@ -85,6 +113,10 @@ func (p *printer) printStruct(msg ...interface{}) error {
 	switch p.format {
 	case json:
 		return structjson.Print(msg, p.env.Stdout, p.env.Stderr)
+	case yaml:
+		return structyaml.Print(msg, p.env.Stdout, p.env.Stderr)
+	case table:
+		return structtable.Print(msg, p.columns, p.env.Stdout, p.env.Stderr)
 	default:
 		return p.printPrettyStruct(msg, p.env.Stdout, p.env.Stderr)
 	}
@@ -98,6 +130,10 @@ func (p *printer) setCustomPrettyPrinter(cp CustomPrettyFunc) {
 	p.cp = cp
 }
 
+func (p *printer) setColumns(columns []string) {
+	p.columns = columns
+}
+
 func (p *printer) printPrettyError(err error, stdout, stderr io.Writer) error {
 	if p.cp != nil {
 		return p.cp(p.env, err)
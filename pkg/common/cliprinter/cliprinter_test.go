@@ -83,6 +83,110 @@ func TestPrintStruct(t *testing.T) {
 	}
 }
 
+func TestPrintFormats(t *testing.T) {
+	msg := struct {
+		Name string `json:"name"`
+	}{
+		Name: "boaty",
+	}
+
+	for _, c := range []struct {
+		format formatType
+		proto  string
+		str    string
+		err    string
+	}{
+		{
+			format: json,
+			proto:  "{\"count\":42}\n",
+			str:    "{\"name\":\"boaty\"}\n",
+			err:    "{\"error\":\"red alert\"}\n",
+		},
+		{
+			format: yaml,
+			proto:  "count: 42\n",
+			str:    "name: boaty\n",
+			err:    "error: red alert\n",
+		},
+		{
+			format: table,
+			proto:  "COUNT\n42\n",
+			str:    "NAME\nboaty\n",
+			err:    "red alert\n",
+		},
+	} {
+		t.Run(formatTypeToStr(c.format), func(t *testing.T) {
+			p, stdout, _ := newTestPrinter()
+			p.format = c.format
+			p.setCustomPrettyPrinter(func(*commoncli.Env, ...interface{}) error {
+				t.Error("custom pretty func invoked")
+				return nil
+			})
+
+			if err := p.printProto(&agentapi.CountAgentsResponse{Count: 42}); err != nil {
+				t.Fatalf("failed to print proto: %v", err)
+			}
+			if stdout.String() != c.proto {
+				t.Errorf("expected proto output %q but got %q", c.proto, stdout.String())
+			}
+
+			stdout.Reset()
+			if err := p.printStruct(msg); err != nil {
+				t.Fatalf("failed to print struct: %v", err)
+			}
+			if stdout.String() != c.str {
+				t.Errorf("expected struct output %q but got %q", c.str, stdout.String())
+			}
+
+			if c.format == table {
+				// Errors are printed as in the pretty format
+				return
+			}
+			stdout.Reset()
+			if err := p.printError(errors.New("red alert")); err != nil {
+				t.Fatalf("failed to print error: %v", err)
+			}
+			if stdout.String() != c.err {
+				t.Errorf("expected error output %q but got %q", c.err, stdout.String())
+			}
+		})
+	}
+}
+
+func TestPrintTableColumns(t *testing.T) {
+	p, stdout, _ := newTestPrinter()
+	p.format = table
+	p.setColumns([]string{"count"})
+
+	if err := p.printProto(&agentapi.CountAgentsResponse{Count: 42}); err != nil {
+		t.Fatalf("failed to print proto: %v", err)
+	}
+	if stdout.String() != "COUNT\n42\n" {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+
+	p.setColumns([]string{"total"})
+	if err := p.printProto(&agentapi.CountAgentsResponse{Count: 42}); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestPrintColumnsWithoutTable(t *testing.T) {
+	p, stdout, _ := newTestPrinter()
+	p.format = json
+	p.setColumns([]string{"count"})
+
+	if err := p.PrintProto(&agentapi.CountAgentsResponse{Count: 42}); err == nil || err.Error() != "the -columns flag can only be used with the table output format" {
+		t.Errorf("unexpected error printing proto: %v", err)
+	}
+	if err := p.PrintStruct(struct{}{}); err == nil || err.Error() != "the -columns flag can only be used with the table output format" {
+		t.Errorf("unexpected error printing struct: %v", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("unexpected output %q", stdout.String())
+	}
+}
+
 func newTestPrinter() (p *printer, stdout, stderr *bytes.Buffer) {
 	stdout = new(bytes.Buffer)
 	stderr = new(bytes.Buffer)
@@ -4,17 +4,27 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"strings"
 
 	commoncli "github.com/spiffe/spire/pkg/common/cli"
 )
 
-const defaultFlagName = "output"
+const (
+	defaultFlagName = "output"
+	columnsFlagName = "columns"
+)
 
-var flagDescription = fmt.Sprintf(
-	"Desired output format (%s, %s); default: %s.",
-	formatTypeToStr(pretty),
-	formatTypeToStr(json),
-	formatTypeToStr(defaultFormatType),
+var (
+	// defaultFormats are the output formats supported by every command
+	defaultFormats = []formatType{pretty, json, yaml}
+	// tableFormats are the output formats supported by the commands that
+	// print their results as a table
+	tableFormats = []formatType{pretty, json, yaml, table}
+
+	columnsFlagDescription = fmt.Sprintf(
+		"Comma-separated list of the columns to print with the %s output format; default: all columns.",
+		formatTypeToStr(table),
+	)
 )
 
 // AppendFlag adds the -format flag to the provided flagset, and populates
@@ -29,6 +39,20 @@ func AppendFlag(p *Printer, fs *flag.FlagSet, env *commoncli.Env) *FormatterFlag
 // intended use is to allow for the adoption of cliprinter while still retaining
 // backwards compatibility with the legacy/bespoke pretty print output.
 func AppendFlagWithCustomPretty(p *Printer, fs *flag.FlagSet, env *commoncli.Env, cp CustomPrettyFunc) *FormatterFlag {
+	return appendFlag(p, fs, env, cp, defaultFormats)
+}
+
+// AppendTableFlagWithCustomPretty is the same as AppendFlagWithCustomPretty,
+// however it also supports the table output format, along with the -columns
+// flag to select the columns of the table. It is intended for the commands
+// that list items, where each item is printed as a row.
+func AppendTableFlagWithCustomPretty(p *Printer, fs *flag.FlagSet, env *commoncli.Env, cp CustomPrettyFunc) *FormatterFlag {
+	f := appendFlag(p, fs, env, cp, tableFormats)
+	fs.Var(&columnsFlag{f: f}, columnsFlagName, columnsFlagDescription)
+	return f
+}
+
+func appendFlag(p *Printer, fs *flag.FlagSet, env *commoncli.Env, cp CustomPrettyFunc, formats []formatType) *FormatterFlag {
 	// Set the default
 	np := newPrinter(defaultFormatType, env)
 	np.setCustomPrettyPrinter(cp)
@@ -37,23 +61,39 @@ func AppendFlagWithCustomPretty(p *Printer, fs *flag.FlagSet, env *commoncli.Env
 	f := &FormatterFlag{
 		p:            p,
 		f:            defaultFormatType,
+		formats:      formats,
 		env:          env,
 		customPretty: cp,
 	}
 
-	fs.Var(f, defaultFlagName, flagDescription)
+	fs.Var(f, defaultFlagName, flagDescription(formats))
 	return f
 }
 
+func flagDescription(formats []formatType) string {
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, formatTypeToStr(format))
+	}
+
+	return fmt.Sprintf(
+		"Desired output format (%s); default: %s.",
+		strings.Join(names, ", "),
+		formatTypeToStr(defaultFormatType),
+	)
+}
+
 type FormatterFlag struct {
 	customPretty CustomPrettyFunc
 
 	// A pointer to our consumer's Printer interface, along with
 	// its format type
-	p     *Printer
-	f     formatType
-	env   *commoncli.Env
-	isSet bool
+	p       *Printer
+	f       formatType
+	formats []formatType
+	columns []string
+	env     *commoncli.Env
+	isSet   bool
 }
 
 func (f *FormatterFlag) String() string {
@@ -76,12 +116,63 @@ func (f *FormatterFlag) Set(formatStr string) error {
 	if err != nil {
 		return fmt.Errorf("bad formatter flag: %w", err)
 	}
+	if !f.supports(format) {
+		return fmt.Errorf("bad formatter flag: unsupported format option: %q", formatStr)
+	}
 
-	np := newPrinter(format, f.env)
+	f.f = format
+	f.isSet = true
+	f.updatePrinter()
+	return nil
+}
+
+func (f *FormatterFlag) supports(format formatType) bool {
+	for _, supported := range f.formats {
+		if supported == format {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FormatterFlag) updatePrinter() {
+	np := newPrinter(f.f, f.env)
 	np.setCustomPrettyPrinter(f.customPretty)
+	np.setColumns(f.columns)
 
 	*f.p = np
-	f.f = format
-	f.isSet = true
+}
+
+// columnsFlag sets the columns printed by the table output format of the
+// printer configured by a FormatterFlag.
+type columnsFlag struct {
+	f *FormatterFlag
+}
+
+func (c *columnsFlag) String() string {
+	if c == nil || c.f == nil {
+		return ""
+	}
+
+	return strings.Join(c.f.columns, ",")
+}
+
+func (c *columnsFlag) Set(columnsStr string) error {
+	if c.f.p == nil {
+		return errors.New("internal error: formatter flag not correctly invoked; please report this bug")
+	}
+
+	var columns []string
+	for _, column := range strings.Split(columnsStr, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	if len(columns) == 0 {
+		return errors.New("at least one column is required")
+	}
+
+	c.f.columns = columns
+	c.f.updatePrinter()
 	return nil
 }
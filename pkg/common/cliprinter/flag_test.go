@@ -3,6 +3,7 @@ package cliprinter
 import (
 	"bytes"
 	"flag"
+	"reflect"
 	"testing"
 
 	agentapi "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
@@ -11,11 +12,13 @@ import (
 
 func TestAppendFlag(t *testing.T) {
 	flagCases := []struct {
-		name           string
-		input          []string
-		extraFlags     []string
-		expectedFormat formatType
-		expectError    bool
+		name            string
+		input           []string
+		extraFlags      []string
+		table           bool
+		expectedFormat  formatType
+		expectedColumns []string
+		expectError     bool
 	}{
 		{
 			name:           "defaults to pretty print when not specified",
@@ -55,6 +58,47 @@ func TestAppendFlag(t *testing.T) {
 			input:          []string{"-output", "json"},
 			expectedFormat: json,
 		},
+		{
+			name:           "works when specifying yaml",
+			input:          []string{"-output", "yaml"},
+			expectedFormat: yaml,
+		},
+		{
+			name:        "table is not supported by default",
+			input:       []string{"-output", "table"},
+			expectError: true,
+		},
+		{
+			name:        "columns are not supported by default",
+			input:       []string{"-columns", "id"},
+			expectError: true,
+		},
+		{
+			name:           "works when specifying table",
+			input:          []string{"-output", "table"},
+			table:          true,
+			expectedFormat: table,
+		},
+		{
+			name:            "works when specifying table with columns",
+			input:           []string{"-output", "table", "-columns", "id, spiffe_id"},
+			table:           true,
+			expectedFormat:  table,
+			expectedColumns: []string{"id", "spiffe_id"},
+		},
+		{
+			name:            "works when specifying columns before the format",
+			input:           []string{"-columns", "id", "-output", "table"},
+			table:           true,
+			expectedFormat:  table,
+			expectedColumns: []string{"id"},
+		},
+		{
+			name:        "requires at least one column",
+			input:       []string{"-output", "table", "-columns", " , "},
+			table:       true,
+			expectError: true,
+		},
 		{
 			name:           "input is case insensitive",
 			input:          []string{"-output", "jSoN"},
@@ -68,7 +112,12 @@ func TestAppendFlag(t *testing.T) {
 
 			fs := flag.NewFlagSet("testy", flag.ContinueOnError)
 			fs.SetOutput(new(bytes.Buffer))
-			defaultFlagValue := AppendFlag(&p, fs, nil)
+			var defaultFlagValue *FormatterFlag
+			if c.table {
+				defaultFlagValue = AppendTableFlagWithCustomPretty(&p, fs, nil, nil)
+			} else {
+				defaultFlagValue = AppendFlag(&p, fs, nil)
+			}
 			for _, flagName := range c.extraFlags {
 				fs.Var(defaultFlagValue, flagName, "")
 			}
@@ -96,6 +145,9 @@ func TestAppendFlag(t *testing.T) {
 			if pp.getFormat() != c.expectedFormat {
 				t.Errorf("expected format type %q but got %q", formatTypeToStr(c.expectedFormat), formatTypeToStr(pp.getFormat()))
 			}
+			if !reflect.DeepEqual(pp.columns, c.expectedColumns) {
+				t.Errorf("expected columns %q but got %q", c.expectedColumns, pp.columns)
+			}
 		})
 	}
 }
@@ -9,6 +9,8 @@ const (
 	_ formatType = iota
 	json
 	pretty
+	yaml
+	table
 
 	defaultFormatType = pretty
 )
@@ -21,6 +23,10 @@ func strToFormatType(f string) (formatType, error) {
 		return json, nil
 	case "pretty", "prettyprint":
 		return pretty, nil
+	case "yaml", "yml":
+		return yaml, nil
+	case "table":
+		return table, nil
 	default:
 		return 0, fmt.Errorf("unknown format option: %q", f)
 	}
@@ -32,6 +38,10 @@ func formatTypeToStr(f formatType) string {
 		return "json"
 	case pretty:
 		return "pretty"
+	case yaml:
+		return "yaml"
+	case table:
+		return "table"
 	default:
 		return "unknown"
 	}
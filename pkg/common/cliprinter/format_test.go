@@ -23,6 +23,14 @@ func TestStrFormatType(t *testing.T) {
 			name:  "json should work",
 			input: "json",
 		},
+		{
+			name:  "yaml should work",
+			input: "yaml",
+		},
+		{
+			name:  "table should work",
+			input: "table",
+		},
 	}
 
 	for _, c := range cases {
@@ -0,0 +1,21 @@
+package erroryaml
+
+import (
+	"io"
+
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/structyaml"
+)
+
+func Print(err error, stdout, stderr io.Writer) error {
+	if err == nil {
+		return nil
+	}
+
+	s := struct {
+		E string `json:"error"`
+	}{
+		E: err.Error(),
+	}
+
+	return structyaml.Print([]interface{}{s}, stdout, stderr)
+}
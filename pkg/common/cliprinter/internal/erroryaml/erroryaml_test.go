@@ -0,0 +1,49 @@
+package erroryaml
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrint(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		stdout string
+		stderr string
+	}{
+		{
+			name:   "normal_error",
+			err:    errors.New("failed to error"),
+			stdout: "error: failed to error\n",
+			stderr: "",
+		},
+		{
+			name:   "error_with_quotes",
+			err:    errors.New(`failed to parse "foo": bad value`),
+			stdout: "error: 'failed to parse \"foo\": bad value'\n",
+			stderr: "",
+		},
+		{
+			name:   "nil_error",
+			err:    nil,
+			stdout: "",
+			stderr: "",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			err := Print(c.err, stdout, stderr)
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.stdout, stdout.String())
+			assert.Equal(t, c.stderr, stderr.String())
+		})
+	}
+}
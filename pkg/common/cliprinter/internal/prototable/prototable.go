@@ -0,0 +1,203 @@
+package prototable
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/errorpretty"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/table"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	spiffeIDFullName = "spire.api.types.SPIFFEID"
+	selectorFullName = "spire.api.types.Selector"
+)
+
+// Print prints one or more protobuf messages as a table. The rows of the
+// table are the elements of the list wrapped by each message, or the
+// messages themselves when they do not wrap a list. Nested messages
+// are flattened into columns named after their path (e.g. "id.path").
+func Print(msgs []proto.Message, columns []string, stdout, stderr io.Writer) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	t := table.New()
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+		AddMessage(t, msg.ProtoReflect())
+	}
+
+	if err := t.Print(stdout, columns); err != nil {
+		_ = errorpretty.Print(err, stdout, stderr)
+		return err
+	}
+	return nil
+}
+
+// AddMessage adds the rows of a message to the table.
+func AddMessage(t *table.Table, m protoreflect.Message) {
+	if rows := rowsField(m.Descriptor()); rows != nil {
+		list := m.Get(rows).List()
+		if list.Len() == 0 {
+			t.AddColumns(MessageRow(list.NewElement().Message()))
+		}
+		for i := 0; i < list.Len(); i++ {
+			t.AddRow(MessageRow(list.Get(i).Message()))
+		}
+		return
+	}
+
+	t.AddRow(MessageRow(m))
+}
+
+// MessageRow returns a row with the fields of the message.
+func MessageRow(m protoreflect.Message) *table.Row {
+	row := table.NewRow()
+	AddFields(row, "", m)
+	return row
+}
+
+// AddFields sets the fields of the message in the row, prefixing the
+// column names with the given prefix.
+func AddFields(row *table.Row, prefix string, m protoreflect.Message) {
+	addFields(row, prefix, m, map[protoreflect.FullName]bool{})
+}
+
+func addFields(row *table.Row, prefix string, m protoreflect.Message, path map[protoreflect.FullName]bool) {
+	path[m.Descriptor().FullName()] = true
+	defer delete(path, m.Descriptor().FullName())
+
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		name := prefix + string(fd.Name())
+
+		// Nested messages are flattened, unless they have a compact
+		// representation or are recursive.
+		if fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap() &&
+			!isCompact(fd.Message()) && !path[fd.Message().FullName()] {
+			addFields(row, name+".", m.Get(fd).Message(), path)
+			continue
+		}
+
+		row.Set(name, fieldString(m, fd))
+	}
+}
+
+// rowsField returns the repeated message field holding the rows of a
+// message, or nil if the message is a row itself. Only messages wrapping a
+// list, such as responses with a single repeated field and scalar fields
+// like a page token, hold rows.
+func rowsField(md protoreflect.MessageDescriptor) protoreflect.FieldDescriptor {
+	var rows protoreflect.FieldDescriptor
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		switch {
+		case fd.IsList() && fd.Kind() == protoreflect.MessageKind && !isCompact(fd.Message()):
+			if rows != nil {
+				return nil
+			}
+			rows = fd
+		case fd.IsList(), fd.IsMap(), fd.Kind() == protoreflect.MessageKind:
+			return nil
+		}
+	}
+	return rows
+}
+
+func fieldString(m protoreflect.Message, fd protoreflect.FieldDescriptor) string {
+	v := m.Get(fd)
+	switch {
+	case fd.IsList():
+		list := v.List()
+		values := make([]string, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			values = append(values, valueString(fd, list.Get(i)))
+		}
+		return strings.Join(values, ",")
+	case fd.IsMap():
+		var values []string
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			values = append(values, k.String()+"="+valueString(fd.MapValue(), v))
+			return true
+		})
+		sort.Strings(values)
+		return strings.Join(values, ",")
+	case fd.Kind() == protoreflect.MessageKind && !m.Has(fd):
+		return ""
+	default:
+		return valueString(fd, v)
+	}
+}
+
+func valueString(fd protoreflect.FieldDescriptor, v protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageString(v.Message())
+	default:
+		return v.String()
+	}
+}
+
+// messageString returns a compact representation of a message, to be
+// printed in a single cell.
+func messageString(m protoreflect.Message) string {
+	switch m.Descriptor().FullName() {
+	case spiffeIDFullName:
+		fields := m.Descriptor().Fields()
+		td := m.Get(fields.ByName("trust_domain")).String()
+		if td == "" {
+			return ""
+		}
+		return "spiffe://" + td + m.Get(fields.ByName("path")).String()
+	case selectorFullName:
+		fields := m.Descriptor().Fields()
+		return m.Get(fields.ByName("type")).String() + ":" + m.Get(fields.ByName("value")).String()
+	}
+
+	jb, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m.Interface())
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+
+	// The protojson output is unstable on purpose, so it is compacted
+	var s string
+	if err := json.Unmarshal(jb, &s); err == nil {
+		return s
+	}
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, jb); err != nil {
+		return string(jb)
+	}
+	return buf.String()
+}
+
+// isCompact returns true if messages of the given type are printed in a
+// single cell rather than flattened.
+func isCompact(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case spiffeIDFullName, selectorFullName:
+		return true
+	}
+	return md.FullName().Parent() == "google.protobuf"
+}
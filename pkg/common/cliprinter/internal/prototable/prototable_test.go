@@ -0,0 +1,151 @@
+package prototable
+
+import (
+	"bytes"
+	"testing"
+
+	agentapi "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
+	entryapi "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/test/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPrint(t *testing.T) {
+	cases := []struct {
+		name    string
+		msgs    []proto.Message
+		columns []string
+	}{
+		{
+			name: "count",
+			msgs: []proto.Message{
+				&agentapi.CountAgentsResponse{Count: 42},
+			},
+		},
+		{
+			name: "double_count",
+			msgs: []proto.Message{
+				&agentapi.CountAgentsResponse{Count: 42},
+				&agentapi.CountAgentsResponse{Count: 7},
+			},
+		},
+		{
+			name: "agent",
+			msgs: []proto.Message{agent("agent-1", false)},
+		},
+		{
+			name: "list_agents",
+			msgs: []proto.Message{
+				&agentapi.ListAgentsResponse{
+					Agents: []*types.Agent{
+						agent("agent-1", false),
+						agent("a-much-longer-agent-2", true),
+					},
+				},
+			},
+		},
+		{
+			name: "list_agents_empty",
+			msgs: []proto.Message{&agentapi.ListAgentsResponse{}},
+		},
+		{
+			name: "list_entries",
+			msgs: []proto.Message{
+				&entryapi.ListEntriesResponse{
+					Entries: []*types.Entry{
+						{
+							Id:            "entry-1",
+							SpiffeId:      &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload"},
+							ParentId:      &types.SPIFFEID{TrustDomain: "example.org", Path: "/agent"},
+							Selectors:     []*types.Selector{{Type: "unix", Value: "uid:1000"}, {Type: "unix", Value: "gid:1000"}},
+							X509SvidTtl:   3600,
+							FederatesWith: []string{"domain1.org", "domain2.org"},
+							DnsNames:      []string{"workload.example.org"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "list_entries_with_columns",
+			msgs: []proto.Message{
+				&entryapi.ListEntriesResponse{
+					Entries: []*types.Entry{
+						{
+							Id:        "entry-1",
+							SpiffeId:  &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload"},
+							Selectors: []*types.Selector{{Type: "unix", Value: "uid:1000"}},
+						},
+						{
+							Id:       "entry-2",
+							SpiffeId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/other\tworkload"},
+						},
+					},
+				},
+			},
+			columns: []string{"SPIFFE_ID", "id", "selectors"},
+		},
+		{
+			name: "batch_create_results",
+			msgs: []proto.Message{
+				&entryapi.BatchCreateEntryResponse{
+					Results: []*entryapi.BatchCreateEntryResponse_Result{
+						{
+							Status: &types.Status{Message: "OK"},
+							Entry: &types.Entry{
+								Id:       "entry-1",
+								SpiffeId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload"},
+							},
+						},
+					},
+				},
+			},
+			columns: []string{"status.code", "status.message", "entry.id", "entry.spiffe_id"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			err := Print(c.msgs, c.columns, stdout, stderr)
+
+			require.NoError(t, err)
+			assert.Empty(t, stderr.String())
+			golden.RequireEqual(t, c.name, stdout.Bytes())
+		})
+	}
+}
+
+func TestPrintNoMessages(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	require.NoError(t, Print(nil, nil, stdout, stderr))
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestPrintUnknownColumn(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	err := Print([]proto.Message{&agentapi.CountAgentsResponse{Count: 42}}, []string{"total"}, stdout, stderr)
+	require.EqualError(t, err, `unknown column "total"; available columns: count`)
+	assert.Equal(t, "unknown column \"total\"; available columns: count\n", stdout.String())
+}
+
+func agent(name string, banned bool) *types.Agent {
+	return &types.Agent{
+		Id:                   &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/join_token/" + name},
+		AttestationType:      "join_token",
+		X509SvidSerialNumber: "1234",
+		X509SvidExpiresAt:    1700000000,
+		Selectors:            []*types.Selector{{Type: "join_token", Value: name}},
+		Banned:               banned,
+		CanReattest:          true,
+	}
+}
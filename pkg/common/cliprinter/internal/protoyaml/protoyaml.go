@@ -0,0 +1,35 @@
+package protoyaml
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/erroryaml"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/protojson"
+	"google.golang.org/protobuf/proto"
+	"sigs.k8s.io/yaml"
+)
+
+// Print prints one or more protobuf messages formatted as YAML. The
+// messages are marshaled as in the json output format, so both formats
+// have the same field names and values.
+func Print(msgs []proto.Message, stdout, stderr io.Writer) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	jb := new(bytes.Buffer)
+	if err := protojson.Print(msgs, jb, stderr); err != nil {
+		_ = erroryaml.Print(err, stdout, stderr)
+		return err
+	}
+
+	yb, err := yaml.JSONToYAML(jb.Bytes())
+	if err != nil {
+		_ = erroryaml.Print(err, stdout, stderr)
+		return err
+	}
+
+	_, err = stdout.Write(yb)
+	return err
+}
@@ -0,0 +1,71 @@
+package protoyaml
+
+import (
+	"bytes"
+	"testing"
+
+	agentapi "github.com/spiffe/spire-api-sdk/proto/spire/api/server/agent/v1"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/test/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPrint(t *testing.T) {
+	cases := []struct {
+		name string
+		msgs []proto.Message
+	}{
+		{
+			name: "count",
+			msgs: []proto.Message{
+				&agentapi.CountAgentsResponse{Count: 42},
+			},
+		},
+		{
+			name: "double_count",
+			msgs: []proto.Message{
+				&agentapi.CountAgentsResponse{Count: 42},
+				&agentapi.CountAgentsResponse{Count: 7},
+			},
+		},
+		{
+			name: "list_agents",
+			msgs: []proto.Message{
+				&agentapi.ListAgentsResponse{
+					Agents: []*types.Agent{
+						{
+							Id:                   &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/join_token/token"},
+							AttestationType:      "join_token",
+							X509SvidSerialNumber: "1234",
+							X509SvidExpiresAt:    1700000000,
+							Selectors:            []*types.Selector{{Type: "join_token", Value: "token"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			err := Print(c.msgs, stdout, stderr)
+
+			require.NoError(t, err)
+			assert.Empty(t, stderr.String())
+			golden.RequireEqual(t, c.name, stdout.Bytes())
+		})
+	}
+}
+
+func TestPrintNoMessages(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	require.NoError(t, Print(nil, stdout, stderr))
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+}
@@ -0,0 +1,263 @@
+package structtable
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/errorpretty"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/prototable"
+	"github.com/spiffe/spire/pkg/common/cliprinter/internal/table"
+	"google.golang.org/protobuf/proto"
+)
+
+var protoMessageType = reflect.TypeOf((*proto.Message)(nil)).Elem()
+
+// Print prints one or more structs as a table. The rows of the table are
+// the elements of the list wrapped by each struct, or the structs themselves
+// when they do not wrap a list. Nested structs are flattened into columns
+// named after their path (e.g. "agent.id"). Column names are taken from the
+// json tags of the fields, as in the json output format.
+func Print(msgs []interface{}, columns []string, stdout, stderr io.Writer) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	t := table.New()
+	for _, msg := range msgs {
+		if msg == nil {
+			continue
+		}
+
+		if err := addStruct(t, msg); err != nil {
+			_ = errorpretty.Print(err, stdout, stderr)
+			return err
+		}
+	}
+
+	if err := t.Print(stdout, columns); err != nil {
+		_ = errorpretty.Print(err, stdout, stderr)
+		return err
+	}
+	return nil
+}
+
+func addStruct(t *table.Table, msg interface{}) error {
+	if msg == nil {
+		return nil
+	}
+	if m, ok := msg.(proto.Message); ok {
+		prototable.AddMessage(t, m.ProtoReflect())
+		return nil
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(msg))
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			if err := addStruct(t, v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot print unsupported type %q", v.Kind().String())
+	}
+
+	if rows, ok := rowsField(v); ok {
+		if rows.Len() == 0 {
+			t.AddColumns(valueRow(reflect.New(rows.Type().Elem()).Elem()))
+		}
+		for i := 0; i < rows.Len(); i++ {
+			t.AddRow(valueRow(rows.Index(i)))
+		}
+		return nil
+	}
+
+	t.AddRow(valueRow(v))
+	return nil
+}
+
+// rowsField returns the slice field holding the rows of a struct. Only
+// structs wrapping a list, with a single slice of nested values and scalar
+// fields, hold rows.
+func rowsField(v reflect.Value) (reflect.Value, bool) {
+	var rows reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		ft := v.Type().Field(i)
+		if !ft.IsExported() || fieldName(ft) == "" {
+			continue
+		}
+		switch {
+		case (ft.Type.Kind() == reflect.Slice || ft.Type.Kind() == reflect.Array) && isNested(ft.Type.Elem()):
+			if rows.IsValid() {
+				return reflect.Value{}, false
+			}
+			rows = v.Field(i)
+		case isNested(ft.Type):
+			return reflect.Value{}, false
+		}
+	}
+	return rows, rows.IsValid()
+}
+
+func valueRow(v reflect.Value) *table.Row {
+	row := table.NewRow()
+	addValue(row, "", v, map[reflect.Type]bool{})
+	return row
+}
+
+func addValue(row *table.Row, prefix string, v reflect.Value, path map[reflect.Type]bool) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return
+	}
+
+	if v.Type().Implements(protoMessageType) {
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+		prototable.AddFields(row, prefix, v.Interface().(proto.Message).ProtoReflect())
+		return
+	}
+
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v = reflect.New(v.Type().Elem())
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		row.Set(strings.TrimSuffix(prefix, "."), valueString(v))
+		return
+	}
+
+	path[v.Type()] = true
+	defer delete(path, v.Type())
+
+	for i := 0; i < v.NumField(); i++ {
+		ft := v.Type().Field(i)
+		name := fieldName(ft)
+		if !ft.IsExported() || name == "" {
+			continue
+		}
+		name = prefix + name
+
+		fv := v.Field(i)
+		// Nested structs are flattened, unless they are recursive
+		if isNested(ft.Type) && !path[indirectType(ft.Type)] {
+			addValue(row, name+".", fv, path)
+			continue
+		}
+		row.Set(name, valueString(fv))
+	}
+}
+
+// isNested returns true if the fields of values of the given type are
+// flattened into the row.
+func isNested(t reflect.Type) bool {
+	if t.Implements(protoMessageType) {
+		return true
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isText(t)
+}
+
+func valueString(v reflect.Value) string {
+	if !v.IsValid() {
+		return ""
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+	}
+
+	if m, ok := v.Interface().(proto.Message); ok {
+		return structString(m)
+	}
+	if tm, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		if err != nil {
+			return fmt.Sprintf("<%v>", err)
+		}
+		return string(text)
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return valueString(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return fmt.Sprint(v.Interface())
+		}
+		values := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, valueString(v.Index(i)))
+		}
+		return strings.Join(values, ",")
+	case reflect.Map:
+		values := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			values = append(values, valueString(iter.Key())+"="+valueString(iter.Value()))
+		}
+		sort.Strings(values)
+		return strings.Join(values, ",")
+	case reflect.Struct:
+		return structString(v.Interface())
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// structString returns a compact representation of a struct, to be printed
+// in a single cell.
+func structString(v interface{}) string {
+	jb, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return string(jb)
+}
+
+// fieldName returns the name of the column of a field, which is the name
+// in its json tag, if any. An empty name is returned for ignored fields.
+func fieldName(f reflect.StructField) string {
+	tag, ok := f.Tag.Lookup("json")
+	if !ok {
+		return f.Name
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	default:
+		return name
+	}
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
+
+func isText(t reflect.Type) bool {
+	textMarshaler := reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	return t.Implements(textMarshaler) || reflect.PtrTo(t).Implements(textMarshaler)
+}
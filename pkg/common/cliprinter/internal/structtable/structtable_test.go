@@ -0,0 +1,140 @@
+package structtable
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/test/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	cases := []struct {
+		name    string
+		s       []interface{}
+		columns []string
+	}{
+		{
+			name: "struct",
+			s: []interface{}{
+				&agentResult{
+					AgentID: spiffeid.RequireFromString("spiffe://example.org/agent"),
+					Deleted: true,
+					Labels:  map[string]string{"b": "2", "a": "1"},
+				},
+			},
+		},
+		{
+			name: "list",
+			s: []interface{}{
+				&agentResults{
+					Agents: []*agentResult{
+						{AgentID: spiffeid.RequireFromString("spiffe://example.org/agent-1"), Deleted: true},
+						{AgentID: spiffeid.RequireFromString("spiffe://example.org/agent-2"), Error: "some error"},
+						nil,
+					},
+				},
+			},
+		},
+		{
+			name: "list_empty",
+			s:    []interface{}{&agentResults{Agents: []*agentResult{}}},
+		},
+		{
+			name: "list_wrapped_in_slice",
+			s: []interface{}{
+				[]interface{}{
+					&agentResults{
+						Agents: []*agentResult{
+							{AgentID: spiffeid.RequireFromString("spiffe://example.org/agent-1"), Deleted: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "nested",
+			s: []interface{}{
+				nestedResult{
+					Name:      "nested",
+					Timestamp: time.Unix(1700000000, 0).UTC(),
+					Agent:     &agentResult{Deleted: true},
+					Entry: &types.Entry{
+						Id:       "entry-1",
+						SpiffeId: &types.SPIFFEID{TrustDomain: "example.org", Path: "/workload"},
+					},
+					Tags: []string{"a", "b"},
+				},
+			},
+			columns: []string{"name", "timestamp", "agent.deleted", "entry.id", "entry.spiffe_id", "Tags"},
+		},
+		{
+			name: "proto",
+			s: []interface{}{
+				&types.Selector{Type: "unix", Value: "uid:1000"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			err := Print(c.s, c.columns, stdout, stderr)
+
+			require.NoError(t, err)
+			assert.Empty(t, stderr.String())
+			golden.RequireEqual(t, c.name, stdout.Bytes())
+		})
+	}
+}
+
+func TestPrintNoStructs(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	require.NoError(t, Print(nil, nil, stdout, stderr))
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+func TestPrintUnsupportedType(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	err := Print([]interface{}{42}, nil, stdout, stderr)
+	require.EqualError(t, err, `cannot print unsupported type "int"`)
+	assert.Equal(t, "cannot print unsupported type \"int\"\n", stdout.String())
+}
+
+func TestPrintUnknownColumn(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	err := Print([]interface{}{&agentResult{}}, []string{"id"}, stdout, stderr)
+	require.EqualError(t, err, `unknown column "id"; available columns: agent_id, deleted, error, labels`)
+}
+
+type agentResults struct {
+	Agents []*agentResult `json:"agents"`
+}
+
+type agentResult struct {
+	AgentID spiffeid.ID       `json:"agent_id"`
+	Deleted bool              `json:"deleted"`
+	Error   string            `json:"error,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+type nestedResult struct {
+	Name      string       `json:"name"`
+	Timestamp time.Time    `json:"timestamp"`
+	Agent     *agentResult `json:"agent"`
+	Entry     *types.Entry `json:"entry"`
+	Tags      []string
+	Skipped   string `json:"-"`
+}
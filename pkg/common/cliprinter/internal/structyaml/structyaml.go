@@ -0,0 +1,32 @@
+package structyaml
+
+import (
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Print prints one or more structs formatted as YAML. Field names are taken
+// from the json tags of the fields, as in the json output format.
+func Print(msgs []interface{}, stdout, _ io.Writer) error {
+	var yb []byte
+	var err error
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	if len(msgs) == 1 {
+		yb, err = yaml.Marshal(msgs[0])
+	} else {
+		yb, err = yaml.Marshal(msgs)
+	}
+	if err != nil {
+		_, _ = fmt.Fprintf(stdout, "error: %q\n", err.Error())
+		return err
+	}
+
+	_, err = stdout.Write(yb)
+	return err
+}
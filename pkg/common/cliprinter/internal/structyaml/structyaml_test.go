@@ -0,0 +1,73 @@
+package structyaml
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spiffe/spire/test/golden"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+	cases := []struct {
+		name string
+		s    []interface{}
+	}{
+		{
+			name: "friendly_struct",
+			s: []interface{}{
+				&friendlyStruct{Friendly: true, Names: []string{"boaty", "mcboatface"}},
+			},
+		},
+		{
+			name: "double_friendly_struct",
+			s: []interface{}{
+				&friendlyStruct{Friendly: true},
+				&friendlyStruct{Friendly: false},
+			},
+		},
+		{
+			name: "nested_struct",
+			s: []interface{}{
+				&nestedStruct{Inner: &friendlyStruct{Friendly: true}},
+			},
+		},
+		{
+			name: "empty_struct",
+			s: []interface{}{
+				struct{}{},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			err := Print(c.s, stdout, stderr)
+
+			require.NoError(t, err)
+			assert.Empty(t, stderr.String())
+			golden.RequireEqual(t, c.name, stdout.Bytes())
+		})
+	}
+}
+
+func TestPrintNoStructs(t *testing.T) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	require.NoError(t, Print(nil, stdout, stderr))
+	assert.Empty(t, stdout.String())
+	assert.Empty(t, stderr.String())
+}
+
+type friendlyStruct struct {
+	Friendly bool     `json:"friendly"`
+	Names    []string `json:"names,omitempty"`
+}
+
+type nestedStruct struct {
+	Inner *friendlyStruct `json:"inner"`
+}
@@ -0,0 +1,134 @@
+package table
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Row holds the values of a table row, along with the order in which
+// its columns were added.
+type Row struct {
+	columns []string
+	values  map[string]string
+}
+
+// NewRow returns an empty row.
+func NewRow() *Row {
+	return &Row{
+		values: make(map[string]string),
+	}
+}
+
+// Set sets the value of a column. Columns keep the order in which they
+// were first set.
+func (r *Row) Set(column, value string) {
+	if _, ok := r.values[column]; !ok {
+		r.columns = append(r.columns, column)
+	}
+	r.values[column] = value
+}
+
+// Table is a set of rows, printed as aligned columns.
+type Table struct {
+	columns []string
+	known   map[string]struct{}
+	rows    []*Row
+}
+
+// New returns an empty table.
+func New() *Table {
+	return &Table{
+		known: make(map[string]struct{}),
+	}
+}
+
+// AddColumns adds the columns of the row to the table, without adding the
+// row itself. It is used to print the header of tables without rows.
+func (t *Table) AddColumns(row *Row) {
+	for _, column := range row.columns {
+		if _, ok := t.known[column]; ok {
+			continue
+		}
+		t.known[column] = struct{}{}
+		t.columns = append(t.columns, column)
+	}
+}
+
+// AddRow adds a row to the table.
+func (t *Table) AddRow(row *Row) {
+	t.AddColumns(row)
+	t.rows = append(t.rows, row)
+}
+
+// Print prints the table with a header, keeping only the given columns.
+// All the columns are printed when no columns are given.
+func (t *Table) Print(w io.Writer, columns []string) error {
+	selected, err := t.selectColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	buf := new(bytes.Buffer)
+	tw := tabwriter.NewWriter(buf, 0, 0, 2, ' ', 0)
+
+	header := make([]string, 0, len(selected))
+	for _, column := range selected {
+		header = append(header, strings.ToUpper(column))
+	}
+	if _, err := fmt.Fprintln(tw, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+
+	for _, row := range t.rows {
+		values := make([]string, 0, len(selected))
+		for _, column := range selected {
+			values = append(values, sanitize(row.values[column]))
+		}
+		if _, err := fmt.Fprintln(tw, strings.Join(values, "\t")); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// Trailing padding is left when the last columns of a row are empty
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	_, err = fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func (t *Table) selectColumns(columns []string) ([]string, error) {
+	if len(columns) == 0 {
+		return t.columns, nil
+	}
+
+	selected := make([]string, 0, len(columns))
+	for _, column := range columns {
+		found := false
+		for _, known := range t.columns {
+			if strings.EqualFold(column, known) {
+				selected = append(selected, known)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown column %q; available columns: %s", column, strings.Join(t.columns, ", "))
+		}
+	}
+	return selected, nil
+}
+
+// sanitize replaces the characters that would break the alignment of the
+// table.
+func sanitize(value string) string {
+	return strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(value)
+}
@@ -29,6 +29,28 @@ func AtomicWritePubliclyReadableFile(path string, data []byte) error {
 	return atomicWrite(path, data, fileModePubliclyReadable)
 }
 
+// AtomicWriteFileWithOwner writes data out to a file with the given mode and
+// ownership. The mode is set regardless of the process umask, and a uid or gid
+// of -1 leaves that ownership unchanged. As with the other atomic functions,
+// the attributes are set on a temp file before it is swapped in, so readers
+// never observe the new file with the wrong permissions.
+func AtomicWriteFileWithOwner(path string, data []byte, mode os.FileMode, uid, gid int) error {
+	tmpPath := path + ".tmp"
+	if err := write(tmpPath, data, mode, true); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+
+	if err := os.Lchown(tmpPath, uid, gid); err != nil {
+		return err
+	}
+
+	return rename(tmpPath, path)
+}
+
 func CreateDataDirectory(path string) error {
 	return os.MkdirAll(path, 0755)
 }
@@ -113,3 +113,31 @@ func TestWriteFile(t *testing.T) {
 		})
 	}
 }
+
+func TestAtomicWriteFileWithOwner(t *testing.T) {
+	dir := spiretest.TempDir(t)
+	file := filepath.Join(dir, "file")
+
+	// The mode is not subject to the umask
+	err := AtomicWriteFileWithOwner(file, []byte("Hello, World"), 0666, -1, -1)
+	require.NoError(t, err)
+
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+	require.EqualValues(t, 0666, info.Mode())
+
+	// The file is replaced, including its mode
+	err = AtomicWriteFileWithOwner(file, []byte("Bye, World"), 0640, os.Getuid(), os.Getgid())
+	require.NoError(t, err)
+
+	info, err = os.Stat(file)
+	require.NoError(t, err)
+	require.EqualValues(t, 0640, info.Mode())
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Equal(t, []byte("Bye, World"), content)
+
+	_, err = os.Stat(file + ".tmp")
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,107 @@
+// Package bundleformat provides the formats supported by the built-in
+// BundlePublisher plugins to serialize a trust bundle.
+package bundleformat
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	plugintypes "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/types"
+	"github.com/spiffe/spire/pkg/common/bundleutil"
+	"github.com/spiffe/spire/pkg/common/coretypes/bundle"
+)
+
+// Format is a trust bundle serialization format.
+type Format int
+
+const (
+	// Unset is the zero value of Format.
+	Unset Format = iota
+
+	// SPIFFE formats the bundle as a SPIFFE trust bundle, i.e. a JWKS
+	// document with the SPIFFE-specific parameters.
+	SPIFFE
+
+	// JWKS formats the bundle as a standard JWKS document, without the
+	// SPIFFE-specific parameters.
+	JWKS
+
+	// PEM formats the X.509 authorities of the bundle as PEM encoded
+	// certificates. JWT authorities are not included.
+	PEM
+)
+
+// FromString returns the Format that corresponds to the given name. Names
+// are case insensitive.
+func FromString(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "spiffe":
+		return SPIFFE, nil
+	case "jwks":
+		return JWKS, nil
+	case "pem":
+		return PEM, nil
+	default:
+		return Unset, fmt.Errorf("unknown bundle format: %q", s)
+	}
+}
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case Unset:
+		return "UNSET"
+	case SPIFFE:
+		return "spiffe"
+	case JWKS:
+		return "jwks"
+	case PEM:
+		return "pem"
+	default:
+		return fmt.Sprintf("UNKNOWN(%d)", int(f))
+	}
+}
+
+// Format serializes the given bundle using the provided format.
+func (f Format) Format(b *plugintypes.Bundle) ([]byte, error) {
+	if b == nil {
+		return nil, errors.New("missing bundle")
+	}
+
+	switch f {
+	case PEM:
+		return formatPEM(b), nil
+	case SPIFFE, JWKS:
+	default:
+		return nil, fmt.Errorf("unsupported bundle format: %s", f)
+	}
+
+	commonBundle, err := bundle.ToCommonFromPluginProto(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	spiffeBundle, err := bundleutil.SPIFFEBundleFromProto(commonBundle)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+
+	if f == JWKS {
+		return bundleutil.Marshal(spiffeBundle, bundleutil.StandardJWKS())
+	}
+	return bundleutil.Marshal(spiffeBundle, bundleutil.OverrideSequenceNumber(b.SequenceNumber))
+}
+
+func formatPEM(b *plugintypes.Bundle) []byte {
+	buf := new(bytes.Buffer)
+	for _, x509Authority := range b.X509Authorities {
+		// no need to check the error since we're encoding into a memory buffer
+		_ = pem.Encode(buf, &pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: x509Authority.Asn1,
+		})
+	}
+	return buf.Bytes()
+}
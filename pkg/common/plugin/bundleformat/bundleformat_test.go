@@ -0,0 +1,98 @@
+package bundleformat
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/types"
+	"github.com/spiffe/spire/pkg/common/bundleutil"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/spiffe/spire/test/testkey"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromString(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		expect    Format
+		expectErr string
+	}{
+		{name: "spiffe", expect: SPIFFE},
+		{name: "JWKS", expect: JWKS},
+		{name: "Pem", expect: PEM},
+		{name: "unknown", expect: Unset, expectErr: `unknown bundle format: "unknown"`},
+	} {
+		format, err := FromString(tt.name)
+		if tt.expectErr != "" {
+			require.EqualError(t, err, tt.expectErr)
+		} else {
+			require.NoError(t, err)
+		}
+		require.Equal(t, tt.expect, format)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	td := spiffeid.RequireTrustDomainFromString("example.org")
+	ca := testca.New(t, td).X509Authorities()[0]
+	jwtKey, err := x509.MarshalPKIXPublicKey(testkey.MustEC256().Public())
+	require.NoError(t, err)
+
+	bundle := &types.Bundle{
+		TrustDomain:     td.Name(),
+		X509Authorities: []*types.X509Certificate{{Asn1: ca.Raw}},
+		JwtAuthorities:  []*types.JWTKey{{KeyId: "KID", PublicKey: jwtKey}},
+		RefreshHint:     60,
+		SequenceNumber:  42,
+	}
+
+	t.Run("spiffe", func(t *testing.T) {
+		data, err := SPIFFE.Format(bundle)
+		require.NoError(t, err)
+
+		spiffeBundle, err := bundleutil.Unmarshal(td, data)
+		require.NoError(t, err)
+		require.Equal(t, []*x509.Certificate{ca}, spiffeBundle.X509Authorities())
+		require.Contains(t, spiffeBundle.JWTAuthorities(), "KID")
+
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &doc))
+		require.Equal(t, float64(42), doc["spiffe_sequence"])
+		require.Equal(t, float64(60), doc["spiffe_refresh_hint"])
+	})
+
+	t.Run("jwks", func(t *testing.T) {
+		data, err := JWKS.Format(bundle)
+		require.NoError(t, err)
+
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &doc))
+		require.Len(t, doc["keys"], 2)
+		require.NotContains(t, doc, "spiffe_sequence")
+		require.NotContains(t, doc, "spiffe_refresh_hint")
+	})
+
+	t.Run("pem", func(t *testing.T) {
+		data, err := PEM.Format(bundle)
+		require.NoError(t, err)
+		require.Equal(t, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), data)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		_, err := Unset.Format(bundle)
+		require.EqualError(t, err, "unsupported bundle format: UNSET")
+	})
+
+	t.Run("missing bundle", func(t *testing.T) {
+		_, err := PEM.Format(nil)
+		require.EqualError(t, err, "missing bundle")
+	})
+
+	t.Run("invalid bundle", func(t *testing.T) {
+		_, err := SPIFFE.Format(&types.Bundle{TrustDomain: td.Name(), X509Authorities: []*types.X509Certificate{{Asn1: []byte("bad")}}})
+		require.ErrorContains(t, err, "invalid bundle:")
+	})
+}
@@ -0,0 +1,159 @@
+package vault
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Config holds the configuration shared by the Vault plugins to connect and
+// authenticate to Vault. Only one authentication method can be configured.
+type Config struct {
+	// A URL of Vault server. (e.g., https://vault.example.com:8443/)
+	VaultAddr string
+	// Path to a CA certificate file that the client verifies the server certificate.
+	// Only PEM format is supported.
+	CACertPath string
+	// If true, vault client accepts any server certificates.
+	// It should be used only test environment so on.
+	InsecureSkipVerify bool
+	// Name of the Vault namespace
+	Namespace string
+	// Configuration for the Token authentication method
+	TokenAuth *TokenAuthConfig
+	// Configuration for the Client Certificate authentication method
+	CertAuth *CertAuthConfig
+	// Configuration for the AppRole authentication method
+	AppRoleAuth *AppRoleAuthConfig
+	// Configuration for the Kubernetes authentication method
+	K8sAuth *K8sAuthConfig
+}
+
+// TokenAuthConfig represents parameters for token auth method
+type TokenAuthConfig struct {
+	// Token string to set into "X-Vault-Token" header
+	Token string `hcl:"token" json:"token"`
+}
+
+// CertAuthConfig represents parameters for cert auth method
+type CertAuthConfig struct {
+	// Name of the mount point where Client Certificate Auth method is mounted. (e.g., /auth/<mount_point>/login)
+	// If the value is empty, use default mount point (/auth/cert)
+	CertAuthMountPoint string `hcl:"cert_auth_mount_point" json:"cert_auth_mount_point"`
+	// Name of the Vault role.
+	// If given, the plugin authenticates against only the named role.
+	CertAuthRoleName string `hcl:"cert_auth_role_name" json:"cert_auth_role_name"`
+	// Path to a client certificate file.
+	// Only PEM format is supported.
+	ClientCertPath string `hcl:"client_cert_path" json:"client_cert_path"`
+	// Path to a client private key file.
+	// Only PEM format is supported.
+	ClientKeyPath string `hcl:"client_key_path" json:"client_key_path"`
+}
+
+// AppRoleAuthConfig represents parameters for AppRole auth method.
+type AppRoleAuthConfig struct {
+	// Name of the mount point where AppRole auth method is mounted. (e.g., /auth/<mount_point>/login)
+	// If the value is empty, use default mount point (/auth/approle)
+	AppRoleMountPoint string `hcl:"approle_auth_mount_point" json:"approle_auth_mount_point"`
+	// An identifier that selects the AppRole
+	RoleID string `hcl:"approle_id" json:"approle_id"`
+	// A credential that is required for login.
+	SecretID string `hcl:"approle_secret_id" json:"approle_secret_id"`
+}
+
+// K8sAuthConfig represents parameters for Kubernetes auth method.
+type K8sAuthConfig struct {
+	// Name of the mount point where Kubernetes auth method is mounted. (e.g., /auth/<mount_point>/login)
+	// If the value is empty, use default mount point (/auth/kubernetes)
+	K8sAuthMountPoint string `hcl:"k8s_auth_mount_point" json:"k8s_auth_mount_point"`
+	// Name of the Vault role.
+	// The plugin authenticates against the named role.
+	K8sAuthRoleName string `hcl:"k8s_auth_role_name" json:"k8s_auth_role_name"`
+	// Path to the Kubernetes Service Account Token to use authentication with the Vault.
+	TokenPath string `hcl:"token_path" json:"token_path"`
+}
+
+// ParseAuthMethod returns the authentication method configured. It fails if
+// none or more than one authentication method is configured.
+func ParseAuthMethod(config *Config) (AuthMethod, error) {
+	var authMethod AuthMethod
+	if config.TokenAuth != nil {
+		authMethod = TOKEN
+	}
+	if config.CertAuth != nil {
+		if err := checkForAuthMethodConfigured(authMethod); err != nil {
+			return 0, err
+		}
+		authMethod = CERT
+	}
+	if config.AppRoleAuth != nil {
+		if err := checkForAuthMethodConfigured(authMethod); err != nil {
+			return 0, err
+		}
+		authMethod = APPROLE
+	}
+	if config.K8sAuth != nil {
+		if err := checkForAuthMethodConfigured(authMethod); err != nil {
+			return 0, err
+		}
+		authMethod = K8S
+	}
+
+	if authMethod != 0 {
+		return authMethod, nil
+	}
+
+	return 0, status.Error(codes.InvalidArgument, "must be configured one of these authentication method 'Token, Client Certificate, AppRole or Kubernetes")
+}
+
+func checkForAuthMethodConfigured(authMethod AuthMethod) error {
+	if authMethod != 0 {
+		return status.Error(codes.InvalidArgument, "only one authentication method can be configured")
+	}
+	return nil
+}
+
+// GenClientParams returns the client parameters to authenticate with the
+// given method. The values set in the VAULT_* environment variables, looked
+// up with lookupEnv, take precedence over the configured ones.
+func GenClientParams(method AuthMethod, config *Config, lookupEnv func(string) (string, bool)) (*ClientParams, error) {
+	getEnvOrDefault := func(envKey, fallback string) string {
+		if value, ok := lookupEnv(envKey); ok {
+			return value
+		}
+		return fallback
+	}
+
+	cp := &ClientParams{
+		VaultAddr:     getEnvOrDefault(envVaultAddr, config.VaultAddr),
+		CACertPath:    getEnvOrDefault(envVaultCACert, config.CACertPath),
+		TLSSKipVerify: config.InsecureSkipVerify,
+		Namespace:     getEnvOrDefault(envVaultNamespace, config.Namespace),
+	}
+
+	switch method {
+	case TOKEN:
+		cp.Token = getEnvOrDefault(envVaultToken, config.TokenAuth.Token)
+	case CERT:
+		cp.CertAuthMountPoint = config.CertAuth.CertAuthMountPoint
+		cp.CertAuthRoleName = config.CertAuth.CertAuthRoleName
+		cp.ClientCertPath = getEnvOrDefault(envVaultClientCert, config.CertAuth.ClientCertPath)
+		cp.ClientKeyPath = getEnvOrDefault(envVaultClientKey, config.CertAuth.ClientKeyPath)
+	case APPROLE:
+		cp.AppRoleAuthMountPoint = config.AppRoleAuth.AppRoleMountPoint
+		cp.AppRoleID = getEnvOrDefault(envVaultAppRoleID, config.AppRoleAuth.RoleID)
+		cp.AppRoleSecretID = getEnvOrDefault(envVaultAppRoleSecretID, config.AppRoleAuth.SecretID)
+	case K8S:
+		if config.K8sAuth.K8sAuthRoleName == "" {
+			return nil, status.Error(codes.InvalidArgument, "k8s_auth_role_name is required")
+		}
+		if config.K8sAuth.TokenPath == "" {
+			return nil, status.Error(codes.InvalidArgument, "token_path is required")
+		}
+		cp.K8sAuthMountPoint = config.K8sAuth.K8sAuthMountPoint
+		cp.K8sAuthRoleName = config.K8sAuth.K8sAuthRoleName
+		cp.K8sAuthTokenPath = config.K8sAuth.TokenPath
+	}
+
+	return cp, nil
+}
@@ -0,0 +1,202 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestParseAuthMethod(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		config        *Config
+		expectMethod  AuthMethod
+		expectCode    codes.Code
+		expectMsgPrfx string
+	}{
+		{
+			name:         "token",
+			config:       &Config{TokenAuth: &TokenAuthConfig{}},
+			expectMethod: TOKEN,
+		},
+		{
+			name:         "cert",
+			config:       &Config{CertAuth: &CertAuthConfig{}},
+			expectMethod: CERT,
+		},
+		{
+			name:         "approle",
+			config:       &Config{AppRoleAuth: &AppRoleAuthConfig{}},
+			expectMethod: APPROLE,
+		},
+		{
+			name:         "k8s",
+			config:       &Config{K8sAuth: &K8sAuthConfig{}},
+			expectMethod: K8S,
+		},
+		{
+			name:          "none",
+			config:        &Config{},
+			expectCode:    codes.InvalidArgument,
+			expectMsgPrfx: "must be configured one of these authentication method",
+		},
+		{
+			name: "multiple",
+			config: &Config{
+				TokenAuth: &TokenAuthConfig{},
+				K8sAuth:   &K8sAuthConfig{},
+			},
+			expectCode:    codes.InvalidArgument,
+			expectMsgPrfx: "only one authentication method can be configured",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			method, err := ParseAuthMethod(tt.config)
+			spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMsgPrfx)
+			require.Equal(t, tt.expectMethod, method)
+		})
+	}
+}
+
+func TestGenClientParams(t *testing.T) {
+	config := &Config{
+		VaultAddr:          "https://vault.example.org",
+		CACertPath:         "ca.pem",
+		InsecureSkipVerify: true,
+		Namespace:          "ns",
+		TokenAuth:          &TokenAuthConfig{Token: "token"},
+		CertAuth: &CertAuthConfig{
+			CertAuthMountPoint: "cert-mount",
+			CertAuthRoleName:   "cert-role",
+			ClientCertPath:     "client.pem",
+			ClientKeyPath:      "client-key.pem",
+		},
+		AppRoleAuth: &AppRoleAuthConfig{
+			AppRoleMountPoint: "approle-mount",
+			RoleID:            "role-id",
+			SecretID:          "secret-id",
+		},
+		K8sAuth: &K8sAuthConfig{
+			K8sAuthMountPoint: "k8s-mount",
+			K8sAuthRoleName:   "k8s-role",
+			TokenPath:         "token-path",
+		},
+	}
+	noEnv := func(string) (string, bool) { return "", false }
+
+	for _, tt := range []struct {
+		name          string
+		method        AuthMethod
+		config        *Config
+		env           map[string]string
+		expectParams  *ClientParams
+		expectCode    codes.Code
+		expectMsgPrfx string
+	}{
+		{
+			name:   "token",
+			method: TOKEN,
+			config: config,
+			expectParams: &ClientParams{
+				VaultAddr:     "https://vault.example.org",
+				CACertPath:    "ca.pem",
+				TLSSKipVerify: true,
+				Namespace:     "ns",
+				Token:         "token",
+			},
+		},
+		{
+			name:   "cert",
+			method: CERT,
+			config: config,
+			expectParams: &ClientParams{
+				VaultAddr:          "https://vault.example.org",
+				CACertPath:         "ca.pem",
+				TLSSKipVerify:      true,
+				Namespace:          "ns",
+				CertAuthMountPoint: "cert-mount",
+				CertAuthRoleName:   "cert-role",
+				ClientCertPath:     "client.pem",
+				ClientKeyPath:      "client-key.pem",
+			},
+		},
+		{
+			name:   "approle",
+			method: APPROLE,
+			config: config,
+			expectParams: &ClientParams{
+				VaultAddr:             "https://vault.example.org",
+				CACertPath:            "ca.pem",
+				TLSSKipVerify:         true,
+				Namespace:             "ns",
+				AppRoleAuthMountPoint: "approle-mount",
+				AppRoleID:             "role-id",
+				AppRoleSecretID:       "secret-id",
+			},
+		},
+		{
+			name:   "k8s",
+			method: K8S,
+			config: config,
+			expectParams: &ClientParams{
+				VaultAddr:         "https://vault.example.org",
+				CACertPath:        "ca.pem",
+				TLSSKipVerify:     true,
+				Namespace:         "ns",
+				K8sAuthMountPoint: "k8s-mount",
+				K8sAuthRoleName:   "k8s-role",
+				K8sAuthTokenPath:  "token-path",
+			},
+		},
+		{
+			name:   "env overrides config",
+			method: TOKEN,
+			config: config,
+			env: map[string]string{
+				envVaultAddr:      "https://env.example.org",
+				envVaultCACert:    "env-ca.pem",
+				envVaultNamespace: "env-ns",
+				envVaultToken:     "env-token",
+			},
+			expectParams: &ClientParams{
+				VaultAddr:     "https://env.example.org",
+				CACertPath:    "env-ca.pem",
+				TLSSKipVerify: true,
+				Namespace:     "env-ns",
+				Token:         "env-token",
+			},
+		},
+		{
+			name:          "k8s without role name",
+			method:        K8S,
+			config:        &Config{K8sAuth: &K8sAuthConfig{TokenPath: "token-path"}},
+			expectCode:    codes.InvalidArgument,
+			expectMsgPrfx: "k8s_auth_role_name is required",
+		},
+		{
+			name:          "k8s without token path",
+			method:        K8S,
+			config:        &Config{K8sAuth: &K8sAuthConfig{K8sAuthRoleName: "k8s-role"}},
+			expectCode:    codes.InvalidArgument,
+			expectMsgPrfx: "token_path is required",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv := noEnv
+			if tt.env != nil {
+				lookupEnv = func(key string) (string, bool) {
+					value, ok := tt.env[key]
+					return value, ok
+				}
+			}
+
+			cp, err := GenClientParams(tt.method, tt.config, lookupEnv)
+			spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMsgPrfx)
+			require.Equal(t, tt.expectParams, cp)
+		})
+	}
+}
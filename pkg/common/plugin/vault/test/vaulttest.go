@@ -0,0 +1,133 @@
+// Package vaulttest provides a fake Vault server and the fixtures shared by
+// the tests of the Vault plugins.
+package vaulttest
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+)
+
+const (
+	defaultTLSAuthEndpoint          = "/v1/auth/cert/login"
+	defaultAppRoleAuthEndpoint      = "/v1/auth/approle/login"
+	defaultK8sAuthEndpoint          = "/v1/auth/kubernetes/login"
+	defaultSignIntermediateEndpoint = "/v1/pki/root/sign-intermediate"
+	defaultRenewEndpoint            = "/v1/auth/token/renew-self"
+	defaultLookupSelfEndpoint       = "/v1/auth/token/lookup-self"
+
+	listenAddr = "127.0.0.1:0"
+)
+
+// Paths to the files under testdata.
+var (
+	RootCert          = testdataPath("root-cert.pem")
+	InvalidRootCert   = testdataPath("invalid-root-cert.pem")
+	ServerCert        = testdataPath("server-cert.pem")
+	ServerKey         = testdataPath("server-key.pem")
+	ClientCert        = testdataPath("client-cert.pem")
+	ClientKey         = testdataPath("client-key.pem")
+	InvalidClientCert = testdataPath("invalid-client-cert.pem")
+	InvalidClientKey  = testdataPath("invalid-client-key.pem")
+	IntermediateCSR   = testdataPath("intermediate-csr.pem")
+	K8sToken          = testdataPath("k8s", "token")
+)
+
+// FakeServerConfig configures the endpoints, handlers and responses of the
+// fake Vault server.
+type FakeServerConfig struct {
+	ListenAddr                   string
+	ServerCertificatePemPath     string
+	ServerKeyPemPath             string
+	CertAuthReqEndpoint          string
+	CertAuthReqHandler           func(code int, resp []byte) func(http.ResponseWriter, *http.Request)
+	CertAuthResponseCode         int
+	CertAuthResponse             []byte
+	AppRoleAuthReqEndpoint       string
+	AppRoleAuthReqHandler        func(code int, resp []byte) func(w http.ResponseWriter, r *http.Request)
+	AppRoleAuthResponseCode      int
+	AppRoleAuthResponse          []byte
+	K8sAuthReqEndpoint           string
+	K8sAuthReqHandler            func(code int, resp []byte) func(w http.ResponseWriter, r *http.Request)
+	K8sAuthResponseCode          int
+	K8sAuthResponse              []byte
+	SignIntermediateReqEndpoint  string
+	SignIntermediateReqHandler   func(code int, resp []byte) func(http.ResponseWriter, *http.Request)
+	SignIntermediateResponseCode int
+	SignIntermediateResponse     []byte
+	RenewReqEndpoint             string
+	RenewReqHandler              func(code int, resp []byte) func(http.ResponseWriter, *http.Request)
+	RenewResponseCode            int
+	RenewResponse                []byte
+	LookupSelfReqEndpoint        string
+	LookupSelfReqHandler         func(code int, resp []byte) func(w http.ResponseWriter, r *http.Request)
+	LookupSelfResponseCode       int
+	LookupSelfResponse           []byte
+}
+
+// NewFakeServerConfig returns a FakeServerConfig with default values
+func NewFakeServerConfig() *FakeServerConfig {
+	return &FakeServerConfig{
+		ListenAddr:                  listenAddr,
+		CertAuthReqEndpoint:         defaultTLSAuthEndpoint,
+		CertAuthReqHandler:          defaultReqHandler,
+		AppRoleAuthReqEndpoint:      defaultAppRoleAuthEndpoint,
+		AppRoleAuthReqHandler:       defaultReqHandler,
+		K8sAuthReqEndpoint:          defaultK8sAuthEndpoint,
+		K8sAuthReqHandler:           defaultReqHandler,
+		SignIntermediateReqEndpoint: defaultSignIntermediateEndpoint,
+		SignIntermediateReqHandler:  defaultReqHandler,
+		RenewReqEndpoint:            defaultRenewEndpoint,
+		RenewReqHandler:             defaultReqHandler,
+		LookupSelfReqEndpoint:       defaultLookupSelfEndpoint,
+		LookupSelfReqHandler:        defaultReqHandler,
+	}
+}
+
+func defaultReqHandler(code int, resp []byte) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(code)
+		_, _ = w.Write(resp)
+	}
+}
+
+// NewTLSServer returns an unstarted fake Vault server that serves the
+// configured responses over TLS, and the address it listens on.
+func (v *FakeServerConfig) NewTLSServer() (srv *httptest.Server, addr string, err error) {
+	cert, err := tls.LoadX509KeyPair(ServerCert, ServerKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load key-pair: %w", err)
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	l, err := tls.Listen("tcp", v.ListenAddr, config)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to listen test server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(v.CertAuthReqEndpoint, v.CertAuthReqHandler(v.CertAuthResponseCode, v.CertAuthResponse))
+	mux.HandleFunc(v.AppRoleAuthReqEndpoint, v.AppRoleAuthReqHandler(v.AppRoleAuthResponseCode, v.AppRoleAuthResponse))
+	mux.HandleFunc(v.K8sAuthReqEndpoint, v.AppRoleAuthReqHandler(v.K8sAuthResponseCode, v.K8sAuthResponse))
+	mux.HandleFunc(v.SignIntermediateReqEndpoint, v.SignIntermediateReqHandler(v.SignIntermediateResponseCode, v.SignIntermediateResponse))
+	mux.HandleFunc(v.RenewReqEndpoint, v.RenewReqHandler(v.RenewResponseCode, v.RenewResponse))
+	mux.HandleFunc(v.LookupSelfReqEndpoint, v.LookupSelfReqHandler(v.LookupSelfResponseCode, v.LookupSelfResponse))
+
+	srv = httptest.NewUnstartedServer(mux)
+	srv.Listener = l
+	return srv, l.Addr().String(), nil
+}
+
+func testdataPath(parts ...string) string {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		panic("unable to obtain caller information")
+	}
+	return filepath.Join(append([]string{filepath.Dir(file), "testdata"}, parts...)...)
+}
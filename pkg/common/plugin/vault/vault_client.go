@@ -126,6 +126,11 @@ func NewClientConfig(cp *ClientParams, logger hclog.Logger) (*ClientConfig, erro
 	return cc, nil
 }
 
+// ClientParams returns the parameters used to create the clients.
+func (c *ClientConfig) ClientParams() *ClientParams {
+	return c.clientParams
+}
+
 // NewAuthenticatedClient returns a new authenticated vault client with given authentication method
 func (c *ClientConfig) NewAuthenticatedClient(method AuthMethod, renewCh chan struct{}) (client *Client, err error) {
 	config := vapi.DefaultConfig()
@@ -295,6 +300,26 @@ func (c *Client) SetToken(v string) {
 	c.vaultClient.SetToken(v)
 }
 
+// Read wraps vapi.Logical.Read()
+func (c *Client) Read(path string) (*vapi.Secret, error) {
+	return c.vaultClient.Logical().Read(path)
+}
+
+// Write wraps vapi.Logical.Write()
+func (c *Client) Write(path string, data map[string]interface{}) (*vapi.Secret, error) {
+	return c.vaultClient.Logical().Write(path, data)
+}
+
+// List wraps vapi.Logical.List()
+func (c *Client) List(path string) (*vapi.Secret, error) {
+	return c.vaultClient.Logical().List(path)
+}
+
+// Delete wraps vapi.Logical.Delete()
+func (c *Client) Delete(path string) (*vapi.Secret, error) {
+	return c.vaultClient.Logical().Delete(path)
+}
+
 // Auth authenticates to vault server with TLS certificate method
 func (c *Client) Auth(path string, body map[string]interface{}) (*vapi.Secret, error) {
 	c.vaultClient.ClearToken()
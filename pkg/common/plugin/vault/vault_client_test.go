@@ -13,23 +13,12 @@ import (
 	vapi "github.com/hashicorp/vault/api"
 	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/spiffe/spire/pkg/common/pemutil"
+	vaulttest "github.com/spiffe/spire/pkg/common/plugin/vault/test"
 	"github.com/spiffe/spire/test/spiretest"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 )
 
-const (
-	testRootCert          = "testdata/root-cert.pem"
-	testInvalidRootCert   = "testdata/invalid-root-cert.pem"
-	testServerCert        = "testdata/server-cert.pem"
-	testServerKey         = "testdata/server-key.pem"
-	testClientCert        = "testdata/client-cert.pem"
-	testClientKey         = "testdata/client-key.pem"
-	testInvalidClientCert = "testdata/invalid-client-cert.pem"
-	testInvalidClientKey  = "testdata/invalid-client-key.pem"
-	testReqCSR            = "testdata/intermediate-csr.pem"
-)
-
 func TestNewClientConfigWithDefaultValues(t *testing.T) {
 	p := &ClientParams{
 		VaultAddr:             "http://example.org:8200/",
@@ -77,16 +66,16 @@ func TestNewAuthenticatedClientCertAuth(t *testing.T) {
 	}{
 		{
 			name:     "Cert Authentication success / Token is renewable",
-			response: []byte(testCertAuthResponse),
+			response: []byte(vaulttest.CertAuthResponse),
 			renew:    true,
 		},
 		{
 			name:     "Cert Authentication success / Token is not renewable",
-			response: []byte(testCertAuthResponseNotRenewable),
+			response: []byte(vaulttest.CertAuthResponseNotRenewable),
 		},
 		{
 			name:      "Cert Authentication success / Token is renewable / Namespace is given",
-			response:  []byte(testCertAuthResponse),
+			response:  []byte(vaulttest.CertAuthResponse),
 			renew:     true,
 			namespace: "test-ns",
 		},
@@ -104,9 +93,9 @@ func TestNewAuthenticatedClientCertAuth(t *testing.T) {
 			cp := &ClientParams{
 				VaultAddr:      fmt.Sprintf("https://%v/", addr),
 				Namespace:      tt.namespace,
-				CACertPath:     testRootCert,
-				ClientCertPath: testClientCert,
-				ClientKeyPath:  testClientKey,
+				CACertPath:     vaulttest.RootCert,
+				ClientCertPath: vaulttest.ClientCert,
+				ClientKeyPath:  vaulttest.ClientKey,
 			}
 			cc, err := NewClientConfig(cp, hclog.Default())
 			require.NoError(t, err)
@@ -145,31 +134,31 @@ func TestNewAuthenticatedClientTokenAuth(t *testing.T) {
 		{
 			name:     "Token Authentication success / Token never expire",
 			token:    "test-token",
-			response: []byte(testLookupSelfResponseNeverExpire),
+			response: []byte(vaulttest.LookupSelfResponseNeverExpire),
 			renew:    true,
 		},
 		{
 			name:     "Token Authentication success / Token is renewable",
 			token:    "test-token",
-			response: []byte(testLookupSelfResponse),
+			response: []byte(vaulttest.LookupSelfResponse),
 			renew:    true,
 		},
 		{
 			name:     "Token Authentication success / Token is not renewable",
 			token:    "test-token",
-			response: []byte(testLookupSelfResponseNotRenewable),
+			response: []byte(vaulttest.LookupSelfResponseNotRenewable),
 		},
 		{
 			name:      "Token Authentication success / Token is renewable / Namespace is given",
 			token:     "test-token",
-			response:  []byte(testCertAuthResponse),
+			response:  []byte(vaulttest.CertAuthResponse),
 			renew:     true,
 			namespace: "test-ns",
 		},
 		{
 			name:            "Token Authentication error / Token is empty",
 			token:           "",
-			response:        []byte(testCertAuthResponse),
+			response:        []byte(vaulttest.CertAuthResponse),
 			renew:           true,
 			namespace:       "test-ns",
 			expectCode:      codes.InvalidArgument,
@@ -189,7 +178,7 @@ func TestNewAuthenticatedClientTokenAuth(t *testing.T) {
 			cp := &ClientParams{
 				VaultAddr:  fmt.Sprintf("https://%v/", addr),
 				Namespace:  tt.namespace,
-				CACertPath: testRootCert,
+				CACertPath: vaulttest.RootCert,
 				Token:      tt.token,
 			}
 			cc, err := NewClientConfig(cp, hclog.Default())
@@ -230,16 +219,16 @@ func TestNewAuthenticatedClientAppRoleAuth(t *testing.T) {
 	}{
 		{
 			name:     "AppRole Authentication success / Token is renewable",
-			response: []byte(testAppRoleAuthResponse),
+			response: []byte(vaulttest.AppRoleAuthResponse),
 			renew:    true,
 		},
 		{
 			name:     "AppRole Authentication success / Token is not renewable",
-			response: []byte(testAppRoleAuthResponseNotRenewable),
+			response: []byte(vaulttest.AppRoleAuthResponseNotRenewable),
 		},
 		{
 			name:      "AppRole Authentication success / Token is renewable / Namespace is given",
-			response:  []byte(testAppRoleAuthResponse),
+			response:  []byte(vaulttest.AppRoleAuthResponse),
 			renew:     true,
 			namespace: "test-ns",
 		},
@@ -257,7 +246,7 @@ func TestNewAuthenticatedClientAppRoleAuth(t *testing.T) {
 			cp := &ClientParams{
 				VaultAddr:       fmt.Sprintf("https://%v/", addr),
 				Namespace:       tt.namespace,
-				CACertPath:      testRootCert,
+				CACertPath:      vaulttest.RootCert,
 				AppRoleID:       "test-approle-id",
 				AppRoleSecretID: "test-approle-secret-id",
 			}
@@ -294,16 +283,16 @@ func TestNewAuthenticatedClientK8sAuth(t *testing.T) {
 	}{
 		{
 			name:     "K8s Authentication success / Token is renewable",
-			response: []byte(testK8sAuthResponse),
+			response: []byte(vaulttest.K8sAuthResponse),
 			renew:    true,
 		},
 		{
 			name:     "K8s Authentication success / Token is not renewable",
-			response: []byte(testK8sAuthResponseNotRenewable),
+			response: []byte(vaulttest.K8sAuthResponseNotRenewable),
 		},
 		{
 			name:      "K8s Authentication success / Token is renewable / Namespace is given",
-			response:  []byte(testK8sAuthResponse),
+			response:  []byte(vaulttest.K8sAuthResponse),
 			renew:     true,
 			namespace: "test-ns",
 		},
@@ -321,9 +310,9 @@ func TestNewAuthenticatedClientK8sAuth(t *testing.T) {
 			cp := &ClientParams{
 				VaultAddr:        fmt.Sprintf("https://%v/", addr),
 				Namespace:        tt.namespace,
-				CACertPath:       testRootCert,
+				CACertPath:       vaulttest.RootCert,
 				K8sAuthRoleName:  "my-role",
-				K8sAuthTokenPath: "testdata/k8s/token",
+				K8sAuthTokenPath: vaulttest.K8sToken,
 			}
 			cc, err := NewClientConfig(cp, hclog.Default())
 			require.NoError(t, err)
@@ -361,9 +350,9 @@ func TestNewAuthenticatedClientCertAuthFailed(t *testing.T) {
 	cp := &ClientParams{
 		MaxRetries:     &retry,
 		VaultAddr:      fmt.Sprintf("https://%v/", addr),
-		CACertPath:     testRootCert,
-		ClientCertPath: testClientCert,
-		ClientKeyPath:  testClientKey,
+		CACertPath:     vaulttest.RootCert,
+		ClientCertPath: vaulttest.ClientCert,
+		ClientKeyPath:  vaulttest.ClientKey,
 	}
 
 	cc, err := NewClientConfig(cp, hclog.Default())
@@ -388,7 +377,7 @@ func TestNewAuthenticatedClientAppRoleAuthFailed(t *testing.T) {
 	cp := &ClientParams{
 		MaxRetries:      &retry,
 		VaultAddr:       fmt.Sprintf("https://%v/", addr),
-		CACertPath:      testRootCert,
+		CACertPath:      vaulttest.RootCert,
 		AppRoleID:       "test-approle-id",
 		AppRoleSecretID: "test-approle-secret-id",
 	}
@@ -414,9 +403,9 @@ func TestNewAuthenticatedClientK8sAuthFailed(t *testing.T) {
 	cp := &ClientParams{
 		MaxRetries:       &retry,
 		VaultAddr:        fmt.Sprintf("https://%v/", addr),
-		CACertPath:       testRootCert,
+		CACertPath:       vaulttest.RootCert,
 		K8sAuthRoleName:  "my-role",
-		K8sAuthTokenPath: "testdata/k8s/token",
+		K8sAuthTokenPath: vaulttest.K8sToken,
 	}
 	cc, err := NewClientConfig(cp, hclog.Default())
 	require.NoError(t, err)
@@ -431,7 +420,7 @@ func TestNewAuthenticatedClientK8sAuthInvalidPath(t *testing.T) {
 	cp := &ClientParams{
 		MaxRetries:       &retry,
 		VaultAddr:        "https://example.org:8200",
-		CACertPath:       testRootCert,
+		CACertPath:       vaulttest.RootCert,
 		K8sAuthTokenPath: "invalid/k8s/token",
 	}
 	cc, err := NewClientConfig(cp, hclog.Default())
@@ -444,7 +433,7 @@ func TestNewAuthenticatedClientK8sAuthInvalidPath(t *testing.T) {
 
 func TestRenewTokenFailed(t *testing.T) {
 	fakeVaultServer := newFakeVaultServer()
-	fakeVaultServer.LookupSelfResponse = []byte(testLookupSelfResponseShortTTL)
+	fakeVaultServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponseShortTTL)
 	fakeVaultServer.LookupSelfResponseCode = 200
 	fakeVaultServer.RenewResponse = []byte("fake renew error")
 	fakeVaultServer.RenewResponseCode = 500
@@ -459,7 +448,7 @@ func TestRenewTokenFailed(t *testing.T) {
 	cp := &ClientParams{
 		MaxRetries: &retry,
 		VaultAddr:  fmt.Sprintf("https://%v/", addr),
-		CACertPath: testRootCert,
+		CACertPath: vaulttest.RootCert,
 		Token:      "test-token",
 	}
 	cc, err := NewClientConfig(cp, hclog.Default())
@@ -479,9 +468,9 @@ func TestRenewTokenFailed(t *testing.T) {
 func TestConfigureTLSWithCertAuth(t *testing.T) {
 	cp := &ClientParams{
 		VaultAddr:      "http://example.org:8200",
-		ClientCertPath: testClientCert,
-		ClientKeyPath:  testClientKey,
-		CACertPath:     testRootCert,
+		ClientCertPath: vaulttest.ClientCert,
+		ClientKeyPath:  vaulttest.ClientKey,
+		CACertPath:     vaulttest.RootCert,
 	}
 	cc, err := NewClientConfig(cp, hclog.Default())
 	require.NoError(t, err)
@@ -506,7 +495,7 @@ func TestConfigureTLSWithCertAuth(t *testing.T) {
 func TestConfigureTLSWithTokenAuth(t *testing.T) {
 	cp := &ClientParams{
 		VaultAddr:  "http://example.org:8200",
-		CACertPath: testRootCert,
+		CACertPath: vaulttest.RootCert,
 		Token:      "test-token",
 	}
 	cc, err := NewClientConfig(cp, hclog.Default())
@@ -527,7 +516,7 @@ func TestConfigureTLSWithTokenAuth(t *testing.T) {
 func TestConfigureTLSWithAppRoleAuth(t *testing.T) {
 	cp := &ClientParams{
 		VaultAddr:       "http://example.org:8200",
-		CACertPath:      testRootCert,
+		CACertPath:      vaulttest.RootCert,
 		AppRoleID:       "test-approle-id",
 		AppRoleSecretID: "test-approle-secret",
 	}
@@ -549,9 +538,9 @@ func TestConfigureTLSWithAppRoleAuth(t *testing.T) {
 func TestConfigureTLSInvalidCACert(t *testing.T) {
 	cp := &ClientParams{
 		VaultAddr:      "http://example.org:8200",
-		ClientCertPath: testClientCert,
-		ClientKeyPath:  testClientKey,
-		CACertPath:     testInvalidRootCert,
+		ClientCertPath: vaulttest.ClientCert,
+		ClientKeyPath:  vaulttest.ClientKey,
+		CACertPath:     vaulttest.InvalidRootCert,
 	}
 	cc, err := NewClientConfig(cp, hclog.Default())
 	require.NoError(t, err)
@@ -564,9 +553,9 @@ func TestConfigureTLSInvalidCACert(t *testing.T) {
 func TestConfigureTLSInvalidClientKey(t *testing.T) {
 	cp := &ClientParams{
 		VaultAddr:      "http://example.org:8200",
-		ClientCertPath: testClientCert,
-		ClientKeyPath:  testInvalidClientKey,
-		CACertPath:     testRootCert,
+		ClientCertPath: vaulttest.ClientCert,
+		ClientKeyPath:  vaulttest.InvalidClientKey,
+		CACertPath:     vaulttest.RootCert,
 	}
 	cc, err := NewClientConfig(cp, hclog.Default())
 	require.NoError(t, err)
@@ -579,9 +568,9 @@ func TestConfigureTLSInvalidClientKey(t *testing.T) {
 func TestConfigureTLSInvalidClientCert(t *testing.T) {
 	cp := &ClientParams{
 		VaultAddr:      "http://example.org:8200",
-		ClientCertPath: testInvalidClientCert,
-		ClientKeyPath:  testClientKey,
-		CACertPath:     testRootCert,
+		ClientCertPath: vaulttest.InvalidClientCert,
+		ClientKeyPath:  vaulttest.ClientKey,
+		CACertPath:     vaulttest.RootCert,
 	}
 	cc, err := NewClientConfig(cp, hclog.Default())
 	require.NoError(t, err)
@@ -594,8 +583,8 @@ func TestConfigureTLSInvalidClientCert(t *testing.T) {
 func TestConfigureTLSRequireClientCertAndKey(t *testing.T) {
 	cp := &ClientParams{
 		VaultAddr:      "http://example.org:8200",
-		ClientCertPath: testClientCert,
-		CACertPath:     testRootCert,
+		ClientCertPath: vaulttest.ClientCert,
+		CACertPath:     vaulttest.RootCert,
 	}
 	cc, err := NewClientConfig(cp, hclog.Default())
 	require.NoError(t, err)
@@ -608,9 +597,9 @@ func TestConfigureTLSRequireClientCertAndKey(t *testing.T) {
 func TestSignIntermediate(t *testing.T) {
 	fakeVaultServer := newFakeVaultServer()
 	fakeVaultServer.CertAuthResponseCode = 200
-	fakeVaultServer.CertAuthResponse = []byte(testCertAuthResponse)
+	fakeVaultServer.CertAuthResponse = []byte(vaulttest.CertAuthResponse)
 	fakeVaultServer.SignIntermediateResponseCode = 200
-	fakeVaultServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+	fakeVaultServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 	s, addr, err := fakeVaultServer.NewTLSServer()
 	require.NoError(t, err)
@@ -620,9 +609,9 @@ func TestSignIntermediate(t *testing.T) {
 
 	cp := &ClientParams{
 		VaultAddr:      fmt.Sprintf("https://%v/", addr),
-		CACertPath:     testRootCert,
-		ClientCertPath: testClientCert,
-		ClientKeyPath:  testClientKey,
+		CACertPath:     vaulttest.RootCert,
+		ClientCertPath: vaulttest.ClientCert,
+		ClientKeyPath:  vaulttest.ClientKey,
 	}
 
 	cc, err := NewClientConfig(cp, hclog.Default())
@@ -634,7 +623,7 @@ func TestSignIntermediate(t *testing.T) {
 
 	testTTL := "0"
 	spiffeID := "spiffe://intermediate-spire"
-	csr, err := pemutil.LoadCertificateRequest(testReqCSR)
+	csr, err := pemutil.LoadCertificateRequest(vaulttest.IntermediateCSR)
 	require.NoError(t, err)
 
 	resp, err := client.SignIntermediate(testTTL, csr)
@@ -660,7 +649,7 @@ func TestSignIntermediate(t *testing.T) {
 func TestSignIntermediateErrorFromEndpoint(t *testing.T) {
 	fakeVaultServer := newFakeVaultServer()
 	fakeVaultServer.CertAuthResponseCode = 200
-	fakeVaultServer.CertAuthResponse = []byte(testCertAuthResponse)
+	fakeVaultServer.CertAuthResponse = []byte(vaulttest.CertAuthResponse)
 	fakeVaultServer.SignIntermediateResponseCode = 500
 	fakeVaultServer.SignIntermediateResponse = []byte("test error")
 
@@ -674,9 +663,9 @@ func TestSignIntermediateErrorFromEndpoint(t *testing.T) {
 	cp := &ClientParams{
 		MaxRetries:     &retry,
 		VaultAddr:      fmt.Sprintf("https://%v/", addr),
-		CACertPath:     testRootCert,
-		ClientCertPath: testClientCert,
-		ClientKeyPath:  testClientKey,
+		CACertPath:     vaulttest.RootCert,
+		ClientCertPath: vaulttest.ClientCert,
+		ClientKeyPath:  vaulttest.ClientKey,
 	}
 
 	cc, err := NewClientConfig(cp, hclog.Default())
@@ -687,26 +676,26 @@ func TestSignIntermediateErrorFromEndpoint(t *testing.T) {
 	require.NoError(t, err)
 
 	testTTL := "0"
-	csr, err := pemutil.LoadCertificateRequest(testReqCSR)
+	csr, err := pemutil.LoadCertificateRequest(vaulttest.IntermediateCSR)
 	require.NoError(t, err)
 
 	_, err = client.SignIntermediate(testTTL, csr)
 	spiretest.RequireGRPCStatusHasPrefix(t, err, codes.Internal, "failed to sign intermediate: Error making API request.")
 }
 
-func newFakeVaultServer() *FakeVaultServerConfig {
-	fakeVaultServer := NewFakeVaultServerConfig()
+func newFakeVaultServer() *vaulttest.FakeServerConfig {
+	fakeVaultServer := vaulttest.NewFakeServerConfig()
 	fakeVaultServer.RenewResponseCode = 200
-	fakeVaultServer.RenewResponse = []byte(testRenewResponse)
+	fakeVaultServer.RenewResponse = []byte(vaulttest.RenewResponse)
 	return fakeVaultServer
 }
 
 func testClientCertificatePair() (tls.Certificate, error) {
-	cert, err := os.ReadFile(testClientCert)
+	cert, err := os.ReadFile(vaulttest.ClientCert)
 	if err != nil {
 		return tls.Certificate{}, err
 	}
-	key, err := os.ReadFile(testClientKey)
+	key, err := os.ReadFile(vaulttest.ClientKey)
 	if err != nil {
 		return tls.Certificate{}, err
 	}
@@ -716,7 +705,7 @@ func testClientCertificatePair() (tls.Certificate, error) {
 
 func testRootCAs() (*x509.CertPool, error) {
 	pool := x509.NewCertPool()
-	pem, err := os.ReadFile(testRootCert)
+	pem, err := os.ReadFile(vaulttest.RootCert)
 	if err != nil {
 		return nil, err
 	}
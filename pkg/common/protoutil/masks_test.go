@@ -57,6 +57,9 @@ func TestAllTrueMasks(t *testing.T) {
 		NewCertSerialNumber: true,
 		NewCertNotAfter:     true,
 		CanReattest:         true,
+		LastSeenAt:          true,
+		AgentVersion:        true,
+		RemoteAddress:       true,
 	}, protoutil.AllTrueCommonAgentMask)
 
 	spiretest.AssertProtoEqual(t, &types.FederationRelationshipMask{
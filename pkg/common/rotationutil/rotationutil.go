@@ -31,13 +31,24 @@ func JWTSVIDExpiresSoon(svid *client.JWTSVID, now time.Time) bool {
 	return shouldRotate(now, svid.IssuedAt, svid.ExpiresAt)
 }
 
+// JWTSVIDExpiresSoonAt returns the time at which the given JWT SVID starts
+// being considered as expiring soon by JWTSVIDExpiresSoon.
+func JWTSVIDExpiresSoonAt(svid *client.JWTSVID) time.Time {
+	return rotationTime(svid.IssuedAt, svid.ExpiresAt)
+}
+
 // JWTSVIDExpired returns true if the given SVID is expired.
 func JWTSVIDExpired(svid *client.JWTSVID, now time.Time) bool {
 	return !now.Before(svid.ExpiresAt)
 }
 
 func shouldRotate(now, beginTime, expiryTime time.Time) bool {
-	ttl := expiryTime.Sub(now)
+	return !now.Before(rotationTime(beginTime, expiryTime))
+}
+
+// rotationTime returns the time at which less than half of the lifetime
+// is left.
+func rotationTime(beginTime, expiryTime time.Time) time.Time {
 	lifetime := expiryTime.Sub(beginTime)
-	return ttl <= lifetime/2
+	return expiryTime.Add(-lifetime / 2)
 }
@@ -86,3 +86,16 @@ func TestJWTSVIDExpiresSoon(t *testing.T) {
 
 	assert.True(t, JWTSVIDExpiresSoon(expiredJWT, mockClk.Now()))
 }
+
+func TestJWTSVIDExpiresSoonAt(t *testing.T) {
+	mockClk := clock.NewMock(t)
+	svid := &client.JWTSVID{
+		IssuedAt:  mockClk.Now(),
+		ExpiresAt: mockClk.Now().Add(time.Hour),
+	}
+
+	expiresSoonAt := JWTSVIDExpiresSoonAt(svid)
+	assert.Equal(t, mockClk.Now().Add(30*time.Minute), expiresSoonAt)
+	assert.False(t, JWTSVIDExpiresSoon(svid, expiresSoonAt.Add(-time.Nanosecond)))
+	assert.True(t, JWTSVIDExpiresSoon(svid, expiresSoonAt))
+}
@@ -20,3 +20,13 @@ func StartReattestAgentCall(m telemetry.Metrics) *telemetry.CallCounter {
 }
 
 // End Call Counters
+
+// Counters (literal increments, not call counters)
+
+// IncrTaintedAgentSVIDCounter indicates that the agent SVID was found
+// to be signed by a tainted authority and must be rotated
+func IncrTaintedAgentSVIDCounter(m telemetry.Metrics) {
+	m.IncrCounter([]string{telemetry.AgentSVID, telemetry.Taint}, 1)
+}
+
+// End Counters
@@ -89,6 +89,10 @@ const (
 	// Reload functionality related to reloading of a cache
 	Reload = "reload"
 
+	// Restore functionality related to restoring some entity from a backup;
+	// should be used with other tags to add clarity
+	Restore = "restore"
+
 	// Rotate functionality related to rotation of SVID; should be used with other tags
 	// to add clarity
 	Rotate = "rotate"
@@ -128,6 +132,18 @@ const (
 
 	// Revoke functionality related with revoking a key from the bundle
 	Revoke = "revoke"
+
+	// Rejected functionality related to rejecting some element (such as a
+	// call exceeding a rate limit); should be used with other tags to add clarity
+	Rejected = "rejected"
+
+	// Timeout functionality related to some element timing out (such as a
+	// call waiting on a rate limit); should be used with other tags to add clarity
+	Timeout = "timeout"
+
+	// Wait functionality related to waiting on some element (such as a rate
+	// limit); should be used with other tags to add clarity
+	Wait = "wait"
 )
 
 // Attribute metric tags or labels that are typically an attribute of a
@@ -157,24 +173,57 @@ const (
 	// AuthorizedVia indicates by what means an entity was authorized
 	AuthorizedVia = "authorized_via"
 
+	// BucketName is the name of an object storage bucket
+	BucketName = "bucket_name"
+
 	// BundleEndpointProfile is the name of the bundle endpoint profile
 	BundleEndpointProfile = "bundle_endpoint_profile"
 
 	// BundleEndpointURL is the URL of the bundle endpoint
 	BundleEndpointURL = "bundle_endpoint_url"
 
+	// ByAdmin tags filtering by admin entries
+	ByAdmin = "by_admin"
+
+	// ByAgentVersion tags filtering by agent build version
+	ByAgentVersion = "by_agent_version"
+
 	// ByBanned tags filtering by banned agents
 	ByBanned = "by_banned"
 
+	// ByDownstream tags filtering by downstream entries
+	ByDownstream = "by_downstream"
+
+	// ByExpiresAfter tags filtering by expiration after a given time
+	ByExpiresAfter = "by_expires_after"
+
+	// ByExpiresBefore tags filtering by expiration before a given time
+	ByExpiresBefore = "by_expires_before"
+
 	// ByCanReattest tags filtering by agents that can re-attest
 	ByCanReattest = "by_can_reattest"
 
+	// ByJWTSVIDTTL tags filtering by JWT-SVID TTL
+	ByJWTSVIDTTL = "by_jwt_svid_ttl"
+
+	// ByLastSeenBefore tags filtering by agents last seen before a given time
+	ByLastSeenBefore = "by_last_seen_before"
+
 	// BySelectorMatch tags Match used when filtering by Selectors
 	BySelectorMatch = "by_selector_match"
 
 	// BySelectors tags selectors used when filtering
 	BySelectors = "by_selectors"
 
+	// BySelectorType tags the selector type used when filtering
+	BySelectorType = "by_selector_type"
+
+	// BySpiffeIDPrefix tags the SPIFFE ID prefix used when filtering
+	BySpiffeIDPrefix = "by_spiffe_id_prefix"
+
+	// ByX509SVIDTTL tags filtering by X509-SVID TTL
+	ByX509SVIDTTL = "by_x509_svid_ttl"
+
 	// CallerAddr labels an API caller address
 	CallerAddr = "caller_addr"
 
@@ -345,6 +394,9 @@ const (
 	// Kid tags some key ID
 	Kid = "kid"
 
+	// LocalAuthorityID tags a local authority ID
+	LocalAuthorityID = "local_authority_id"
+
 	// Mode tags a bundle deletion mode
 	Mode = "mode"
 
@@ -360,6 +412,9 @@ const (
 	// Nonce tags some nonce for communication
 	Nonce = "nonce"
 
+	// ObjectKey is the key of an object in an object storage bucket
+	ObjectKey = "object_key"
+
 	// ParentID tags parent ID for an entry
 	ParentID = "parent_id"
 
@@ -428,6 +483,9 @@ const (
 	// RegistrationEntry tags a registration entry
 	RegistrationEntry = "registration_entry"
 
+	// RegistrationEntryEvent tags a registration entry event
+	RegistrationEntryEvent = "registration_entry_event"
+
 	// RequestID tags a request identifier
 	RequestID = "request_id"
 
@@ -474,6 +532,12 @@ const (
 	// Slot X509 CA Slot ID
 	Slot = "slot"
 
+	// SortBy tags the field a list is sorted by
+	SortBy = "sort_by"
+
+	// SortDescending tags whether a list is sorted in descending order
+	SortDescending = "sort_descending"
+
 	// SPIFFEID tags a SPIFFE ID
 	SPIFFEID = "spiffe_id"
 
@@ -533,6 +597,9 @@ const (
 	// with other tags to add clarity
 	Updated = "updated"
 
+	// URL tags some URL
+	URL = "url"
+
 	// StoreSvid tags if entry is storable
 	StoreSvid = "store_svid"
 
@@ -618,6 +685,9 @@ const (
 	// OutdatedSVIDs tags SVID with outdated attributes count/list
 	OutdatedSVIDs = "outdated_svids"
 
+	// TaintedSVIDs tags SVIDs signed by a tainted authority count/list
+	TaintedSVIDs = "tainted_svids"
+
 	// FederatedBundle functionality related to a federated bundle; should be used
 	// with other tags to add clarity
 	FederatedBundle = "federated_bundle"
@@ -651,6 +721,9 @@ const (
 	// to add clarity
 	Node = "node"
 
+	// NodeEvent functionality related to a node entity or type being created, updated, or deleted
+	NodeEvent = "node_event"
+
 	// Notifier functionality related to some notifying entity; should be used with other tags
 	// to add clarity
 	Notifier = "notifier"
@@ -813,6 +886,9 @@ const (
 	// PushJWTKeyUpstream functionality related to pushing a public JWT Key to an upstream server.
 	PushJWTKeyUpstream = "push_jwtkey_upstream"
 
+	// RateLimit functionality related to rate limiting calls to the server APIs
+	RateLimit = "rateLimit"
+
 	// SDSAPI functionality related to SDS; should be used with other tags
 	// to add clarity
 	SDSAPI = "sds_api"
@@ -37,6 +37,18 @@ func StartListNodeCall(m telemetry.Metrics) *telemetry.CallCounter {
 	return telemetry.StartCall(m, telemetry.Datastore, telemetry.Node, telemetry.List)
 }
 
+// StartListAttestedNodesEventsCall return metric
+// for server's datastore, on listing attested node events.
+func StartListAttestedNodesEventsCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.NodeEvent, telemetry.List)
+}
+
+// StartPruneAttestedNodesEventsCall return metric
+// for server's datastore, on pruning attested node events.
+func StartPruneAttestedNodesEventsCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.NodeEvent, telemetry.Prune)
+}
+
 // StartGetNodeSelectorsCall return metric
 // for server's datastore, on getting selectors for a node.
 func StartGetNodeSelectorsCall(m telemetry.Metrics) *telemetry.CallCounter {
@@ -43,10 +43,28 @@ func StartPruneRegistrationCall(m telemetry.Metrics) *telemetry.CallCounter {
 	return telemetry.StartCall(m, telemetry.Datastore, telemetry.RegistrationEntry, telemetry.Prune)
 }
 
+// StartRestoreRegistrationCall return metric
+// for server's datastore, on restoring a registration.
+func StartRestoreRegistrationCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.RegistrationEntry, telemetry.Restore)
+}
+
 // StartUpdateRegistrationCall return metric
 // for server's datastore, on updating a registration.
 func StartUpdateRegistrationCall(m telemetry.Metrics) *telemetry.CallCounter {
 	return telemetry.StartCall(m, telemetry.Datastore, telemetry.RegistrationEntry, telemetry.Update)
 }
 
+// StartListRegistrationEntriesEventsCall return metric
+// for server's datastore, on listing registration entry events.
+func StartListRegistrationEntriesEventsCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.RegistrationEntryEvent, telemetry.List)
+}
+
+// StartPruneRegistrationEntriesEventsCall return metric
+// for server's datastore, on pruning registration entry events.
+func StartPruneRegistrationEntriesEventsCall(m telemetry.Metrics) *telemetry.CallCounter {
+	return telemetry.StartCall(m, telemetry.Datastore, telemetry.RegistrationEntryEvent, telemetry.Prune)
+}
+
 // End Call Counters
@@ -126,10 +126,18 @@ func TestWithMetrics(t *testing.T) {
 			key:        "datastore.node.list",
 			methodName: "ListAttestedNodes",
 		},
+		{
+			key:        "datastore.node_event.list",
+			methodName: "ListAttestedNodesEvents",
+		},
 		{
 			key:        "datastore.bundle.list",
 			methodName: "ListBundles",
 		},
+		{
+			key:        "datastore.join_token.list",
+			methodName: "ListJoinTokens",
+		},
 		{
 			key:        "datastore.node.selectors.list",
 			methodName: "ListNodeSelectors",
@@ -138,6 +146,10 @@ func TestWithMetrics(t *testing.T) {
 			key:        "datastore.registration_entry.list",
 			methodName: "ListRegistrationEntries",
 		},
+		{
+			key:        "datastore.registration_entry_event.list",
+			methodName: "ListRegistrationEntriesEvents",
+		},
 		{
 			key:        "datastore.federation_relationship.list",
 			methodName: "ListFederationRelationships",
@@ -154,6 +166,18 @@ func TestWithMetrics(t *testing.T) {
 			key:        "datastore.registration_entry.prune",
 			methodName: "PruneRegistrationEntries",
 		},
+		{
+			key:        "datastore.registration_entry.restore",
+			methodName: "RestoreRegistrationEntry",
+		},
+		{
+			key:        "datastore.registration_entry_event.prune",
+			methodName: "PruneRegistrationEntriesEvents",
+		},
+		{
+			key:        "datastore.node_event.prune",
+			methodName: "PruneAttestedNodesEvents",
+		},
 		{
 			key:        "datastore.bundle.set",
 			methodName: "SetBundle",
@@ -366,10 +390,18 @@ func (ds *fakeDataStore) ListAttestedNodes(context.Context, *datastore.ListAttes
 	return &datastore.ListAttestedNodesResponse{}, ds.err
 }
 
+func (ds *fakeDataStore) ListAttestedNodesEvents(context.Context, *datastore.ListAttestedNodesEventsRequest) (*datastore.ListAttestedNodesEventsResponse, error) {
+	return &datastore.ListAttestedNodesEventsResponse{}, ds.err
+}
+
 func (ds *fakeDataStore) ListBundles(context.Context, *datastore.ListBundlesRequest) (*datastore.ListBundlesResponse, error) {
 	return &datastore.ListBundlesResponse{}, ds.err
 }
 
+func (ds *fakeDataStore) ListJoinTokens(context.Context, *datastore.ListJoinTokensRequest) (*datastore.ListJoinTokensResponse, error) {
+	return &datastore.ListJoinTokensResponse{}, ds.err
+}
+
 func (ds *fakeDataStore) ListNodeSelectors(context.Context, *datastore.ListNodeSelectorsRequest) (*datastore.ListNodeSelectorsResponse, error) {
 	return &datastore.ListNodeSelectorsResponse{}, ds.err
 }
@@ -378,6 +410,10 @@ func (ds *fakeDataStore) ListRegistrationEntries(context.Context, *datastore.Lis
 	return &datastore.ListRegistrationEntriesResponse{}, ds.err
 }
 
+func (ds *fakeDataStore) ListRegistrationEntriesEvents(context.Context, *datastore.ListRegistrationEntriesEventsRequest) (*datastore.ListRegistrationEntriesEventsResponse, error) {
+	return &datastore.ListRegistrationEntriesEventsResponse{}, ds.err
+}
+
 func (ds *fakeDataStore) PruneBundle(context.Context, string, time.Time) (bool, error) {
 	return false, ds.err
 }
@@ -390,6 +426,18 @@ func (ds *fakeDataStore) PruneRegistrationEntries(context.Context, time.Time) er
 	return ds.err
 }
 
+func (ds *fakeDataStore) RestoreRegistrationEntry(context.Context, *common.RegistrationEntry) (*common.RegistrationEntry, error) {
+	return &common.RegistrationEntry{}, ds.err
+}
+
+func (ds *fakeDataStore) PruneRegistrationEntriesEvents(context.Context, time.Duration) error {
+	return ds.err
+}
+
+func (ds *fakeDataStore) PruneAttestedNodesEvents(context.Context, time.Duration) error {
+	return ds.err
+}
+
 func (ds *fakeDataStore) SetBundle(context.Context, *common.Bundle) (*common.Bundle, error) {
 	return &common.Bundle{}, ds.err
 }
@@ -1,6 +1,9 @@
 package version
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 const (
 	// Base is the base version for the codebase.
@@ -9,6 +12,10 @@ const (
 	// is part of the upgrade integration test. See
 	// test/integration/suites/upgrade/README.md for details.
 	Base = "1.7.1"
+
+	// agentUserAgentPrefix prefixes the version in the user agent SPIRE Agent
+	// presents to the server.
+	agentUserAgentPrefix = "spire-agent/"
 )
 
 var (
@@ -22,3 +29,21 @@ func Version() string {
 	}
 	return gittag
 }
+
+// AgentUserAgent returns the user agent SPIRE Agent presents to the server.
+func AgentUserAgent() string {
+	return agentUserAgentPrefix + Version()
+}
+
+// AgentVersionFromUserAgent returns the agent version from the given user
+// agent. The user agent may be followed by the user agents of the libraries
+// used by the agent (e.g. "spire-agent/1.7.1 grpc-go/1.57.0"). It returns
+// false if the user agent was not presented by SPIRE Agent.
+func AgentVersionFromUserAgent(userAgent string) (string, bool) {
+	userAgent, _, _ = strings.Cut(userAgent, " ")
+	version, ok := strings.CutPrefix(userAgent, agentUserAgentPrefix)
+	if !ok || version == "" {
+		return "", false
+	}
+	return version, true
+}
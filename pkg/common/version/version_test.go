@@ -0,0 +1,47 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentVersionFromUserAgent(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		userAgent     string
+		expectVersion string
+		expectOK      bool
+	}{
+		{
+			name:          "agent user agent",
+			userAgent:     AgentUserAgent(),
+			expectVersion: Version(),
+			expectOK:      true,
+		},
+		{
+			name:          "followed by grpc user agent",
+			userAgent:     "spire-agent/1.8.0 grpc-go/1.57.0",
+			expectVersion: "1.8.0",
+			expectOK:      true,
+		},
+		{
+			name:      "grpc user agent only",
+			userAgent: "grpc-go/1.57.0",
+		},
+		{
+			name:      "missing version",
+			userAgent: "spire-agent/ grpc-go/1.57.0",
+		},
+		{
+			name: "empty",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			version, ok := AgentVersionFromUserAgent(tt.userAgent)
+			assert.Equal(t, tt.expectVersion, version)
+			assert.Equal(t, tt.expectOK, ok)
+		})
+	}
+}
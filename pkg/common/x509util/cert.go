@@ -1,6 +1,7 @@
 package x509util
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/rand"
 	"crypto/x509"
@@ -72,3 +73,20 @@ func RawCertsFromCertificates(certs []*x509.Certificate) [][]byte {
 	}
 	return rawCerts
 }
+
+// IsSignedByRoot checks if the provided certificate chain is signed by one of
+// the given root CAs. The chain is expected to be ordered from the leaf up,
+// so only the last certificate in the chain is checked against the roots.
+func IsSignedByRoot(chain []*x509.Certificate, rootCAs []*x509.Certificate) bool {
+	if len(chain) == 0 {
+		return false
+	}
+
+	top := chain[len(chain)-1]
+	for _, rootCA := range rootCAs {
+		if bytes.Equal(top.Raw, rootCA.Raw) || top.CheckSignatureFrom(rootCA) == nil {
+			return true
+		}
+	}
+	return false
+}
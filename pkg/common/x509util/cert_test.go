@@ -0,0 +1,28 @@
+package x509util
+
+import (
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSignedByRoot(t *testing.T) {
+	td := spiffeid.RequireTrustDomainFromString("example.org")
+	id := spiffeid.RequireFromPath(td, "/workload")
+
+	ca := testca.New(t, td)
+	intermediate := ca.ChildCA()
+	otherCA := testca.New(t, td)
+
+	leafFromRoot := ca.CreateX509SVID(id).Certificates
+	leafFromIntermediate := intermediate.CreateX509SVID(id).Certificates
+
+	assert.True(t, IsSignedByRoot(leafFromRoot, ca.X509Authorities()))
+	assert.True(t, IsSignedByRoot(leafFromIntermediate, ca.X509Authorities()))
+	assert.True(t, IsSignedByRoot(ca.X509Authorities(), ca.X509Authorities()))
+	assert.False(t, IsSignedByRoot(leafFromRoot, otherCA.X509Authorities()))
+	assert.False(t, IsSignedByRoot(leafFromIntermediate, nil))
+	assert.False(t, IsSignedByRoot(nil, ca.X509Authorities()))
+}
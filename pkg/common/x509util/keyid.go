@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/hex"
 )
 
 // GetSubjectKeyID calculates a subject key identifier by doing a SHA-1 hash
@@ -27,3 +28,9 @@ func GetSubjectKeyID(pubKey interface{}) ([]byte, error) {
 	keyID := sha1.Sum(subjectKeyInfo.SubjectPublicKey.Bytes) //nolint: gosec // usage of SHA1 is according to specification
 	return keyID[:], nil
 }
+
+// SubjectKeyIDToString encodes a subject key identifier as a lowercase
+// hexadecimal string.
+func SubjectKeyIDToString(ski []byte) string {
+	return hex.EncodeToString(ski)
+}
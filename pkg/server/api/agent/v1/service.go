@@ -26,6 +26,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/catalog"
 	"github.com/spiffe/spire/pkg/server/datastore"
 	"github.com/spiffe/spire/pkg/server/plugin/nodeattestor"
+	agentextv1 "github.com/spiffe/spire/proto/spire/api/server/agent/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -36,17 +37,20 @@ import (
 
 // Config is the service configuration
 type Config struct {
-	Catalog     catalog.Catalog
-	Clock       clock.Clock
-	DataStore   datastore.DataStore
-	ServerCA    ca.ServerCA
-	TrustDomain spiffeid.TrustDomain
+	ActivityRecorder api.AgentActivityRecorder
+	Catalog          catalog.Catalog
+	Clock            clock.Clock
+	DataStore        datastore.DataStore
+	ServerCA         ca.ServerCA
+	TrustDomain      spiffeid.TrustDomain
 }
 
 // Service implements the v1 agent service
 type Service struct {
 	agentv1.UnsafeAgentServer
+	agentextv1.UnsafeAgentExtensionsServer
 
+	ar  api.AgentActivityRecorder
 	cat catalog.Catalog
 	clk clock.Clock
 	ds  datastore.DataStore
@@ -57,6 +61,7 @@ type Service struct {
 // New creates a new agent service
 func New(config Config) *Service {
 	return &Service{
+		ar:  config.ActivityRecorder,
 		cat: config.Catalog,
 		clk: config.Clock,
 		ds:  config.DataStore,
@@ -68,6 +73,7 @@ func New(config Config) *Service {
 // RegisterService registers the agent service on the gRPC server/
 func RegisterService(s *grpc.Server, service *Service) {
 	agentv1.RegisterAgentServer(s, service)
+	agentextv1.RegisterAgentExtensionsServer(s, service)
 }
 
 // CountAgents returns the total number of agents.
@@ -156,6 +162,102 @@ func (s *Service) ListAgents(ctx context.Context, req *agentv1.ListAgentsRequest
 	return resp, nil
 }
 
+// SearchAgents returns an optionally filtered, sorted and/or paginated list
+// of agents, along with information about their activity.
+func (s *Service) SearchAgents(ctx context.Context, req *agentextv1.SearchAgentsRequest) (*agentextv1.SearchAgentsResponse, error) {
+	log := rpccontext.Logger(ctx)
+
+	listReq := &datastore.ListAttestedNodesRequest{
+		SortDescending: req.SortDescending,
+	}
+
+	if req.OutputMask == nil || req.OutputMask.Selectors {
+		listReq.FetchSelectors = true
+	}
+	// Parse proto filter into datastore request
+	if req.Filter != nil {
+		filter := req.Filter
+		rpccontext.AddRPCAuditFields(ctx, fieldsFromSearchFilter(filter))
+
+		if filter.ByBanned != nil {
+			listReq.ByBanned = &filter.ByBanned.Value
+		}
+		if filter.ByCanReattest != nil {
+			listReq.ByCanReattest = &filter.ByCanReattest.Value
+		}
+		if filter.ByExpiresBefore != 0 {
+			listReq.ByExpiresBefore = time.Unix(filter.ByExpiresBefore, 0)
+		}
+		if filter.ByLastSeenBefore != 0 {
+			listReq.ByLastSeenBefore = time.Unix(filter.ByLastSeenBefore, 0)
+		}
+		listReq.ByAttestationType = filter.ByAttestationType
+		listReq.ByAgentVersion = filter.ByAgentVersion
+
+		if filter.BySelectorMatch != nil {
+			selectors, err := api.SelectorsFromProto(filter.BySelectorMatch.Selectors)
+			if err != nil {
+				return nil, api.MakeErr(log, codes.InvalidArgument, "failed to parse selectors", err)
+			}
+			listReq.BySelectorMatch = &datastore.BySelectors{
+				Match:     datastore.MatchBehavior(filter.BySelectorMatch.Match),
+				Selectors: selectors,
+			}
+		}
+	}
+
+	sortBy, err := nodeSortFieldFromProto(req.SortBy)
+	if err != nil {
+		return nil, api.MakeErr(log, codes.InvalidArgument, "failed to parse sort field", err)
+	}
+	listReq.SortBy = sortBy
+	if sortBy != datastore.SortNodesByID || req.SortDescending {
+		rpccontext.AddRPCAuditFields(ctx, logrus.Fields{
+			telemetry.SortBy:         req.SortBy.String(),
+			telemetry.SortDescending: req.SortDescending,
+		})
+	}
+
+	// Set pagination parameters
+	if req.PageSize > 0 {
+		listReq.Pagination = &datastore.Pagination{
+			PageSize: req.PageSize,
+			Token:    req.PageToken,
+		}
+	}
+
+	dsResp, err := s.ds.ListAttestedNodes(ctx, listReq)
+	if err != nil {
+		return nil, api.MakeErr(log, codes.Internal, "failed to search agents", err)
+	}
+
+	resp := &agentextv1.SearchAgentsResponse{}
+
+	if dsResp.Pagination != nil {
+		resp.NextPageToken = dsResp.Pagination.Token
+	}
+
+	// Parse nodes into proto and apply output mask
+	for _, node := range dsResp.Nodes {
+		a, err := api.ProtoFromAttestedNode(node)
+		if err != nil {
+			log.WithError(err).WithField(telemetry.SPIFFEID, node.SpiffeId).Warn("Failed to parse agent")
+			continue
+		}
+
+		applyMask(a, req.OutputMask)
+		resp.Agents = append(resp.Agents, &agentextv1.AgentInfo{
+			Agent:         a,
+			LastSeenAt:    node.LastSeenAt,
+			AgentVersion:  node.AgentVersion,
+			RemoteAddress: node.RemoteAddress,
+		})
+	}
+	rpccontext.AuditRPC(ctx)
+
+	return resp, nil
+}
+
 // GetAgent returns the agent associated with the given SpiffeID.
 func (s *Service) GetAgent(ctx context.Context, req *agentv1.GetAgentRequest) (*types.Agent, error) {
 	log := rpccontext.Logger(ctx)
@@ -167,28 +269,64 @@ func (s *Service) GetAgent(ctx context.Context, req *agentv1.GetAgentRequest) (*
 	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{telemetry.SPIFFEID: agentID.String()})
 
 	log = log.WithField(telemetry.SPIFFEID, agentID.String())
+	_, agent, err := s.fetchAgent(ctx, log, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	rpccontext.AuditRPC(ctx)
+	applyMask(agent, req.OutputMask)
+	return agent, nil
+}
+
+// GetAgentInfo returns the agent associated with the given SpiffeID, along
+// with information about its activity.
+func (s *Service) GetAgentInfo(ctx context.Context, req *agentextv1.GetAgentInfoRequest) (*agentextv1.AgentInfo, error) {
+	log := rpccontext.Logger(ctx)
+
+	agentID, err := api.TrustDomainAgentIDFromProto(ctx, s.td, req.Id)
+	if err != nil {
+		return nil, api.MakeErr(log, codes.InvalidArgument, "invalid agent ID", err)
+	}
+	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{telemetry.SPIFFEID: agentID.String()})
+
+	log = log.WithField(telemetry.SPIFFEID, agentID.String())
+	attestedNode, agent, err := s.fetchAgent(ctx, log, agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	rpccontext.AuditRPC(ctx)
+	applyMask(agent, req.OutputMask)
+	return &agentextv1.AgentInfo{
+		Agent:         agent,
+		LastSeenAt:    attestedNode.LastSeenAt,
+		AgentVersion:  attestedNode.AgentVersion,
+		RemoteAddress: attestedNode.RemoteAddress,
+	}, nil
+}
+
+func (s *Service) fetchAgent(ctx context.Context, log logrus.FieldLogger, agentID spiffeid.ID) (*common.AttestedNode, *types.Agent, error) {
 	attestedNode, err := s.ds.FetchAttestedNode(ctx, agentID.String())
 	if err != nil {
-		return nil, api.MakeErr(log, codes.Internal, "failed to fetch agent", err)
+		return nil, nil, api.MakeErr(log, codes.Internal, "failed to fetch agent", err)
 	}
 
 	if attestedNode == nil {
-		return nil, api.MakeErr(log, codes.NotFound, "agent not found", err)
+		return nil, nil, api.MakeErr(log, codes.NotFound, "agent not found", err)
 	}
 
 	selectors, err := s.getSelectorsFromAgentID(ctx, attestedNode.SpiffeId)
 	if err != nil {
-		return nil, api.MakeErr(log, codes.Internal, "failed to get selectors from agent", err)
+		return nil, nil, api.MakeErr(log, codes.Internal, "failed to get selectors from agent", err)
 	}
 
 	agent, err := api.AttestedNodeToProto(attestedNode, selectors)
 	if err != nil {
-		return nil, api.MakeErr(log, codes.Internal, "failed to convert attested node to agent", err)
+		return nil, nil, api.MakeErr(log, codes.Internal, "failed to convert attested node to agent", err)
 	}
 
-	rpccontext.AuditRPC(ctx)
-	applyMask(agent, req.OutputMask)
-	return agent, nil
+	return attestedNode, agent, nil
 }
 
 // DeleteAgent removes the agent with the given SpiffeID.
@@ -350,10 +488,18 @@ func (s *Service) AttestAgent(stream agentv1.Agent_AttestAgentServer) error {
 			CertSerialNumber: svid[0].SerialNumber.String(),
 			CanReattest:      attestResult.CanReattest,
 		}
-		if _, err := s.ds.UpdateAttestedNode(ctx, node, nil); err != nil {
+		mask := &common.AttestedNodeMask{
+			CertNotAfter:        true,
+			CertSerialNumber:    true,
+			NewCertNotAfter:     true,
+			NewCertSerialNumber: true,
+			CanReattest:         true,
+		}
+		if _, err := s.ds.UpdateAttestedNode(ctx, node, mask); err != nil {
 			return api.MakeErr(log, codes.Internal, "failed to update attested agent", err)
 		}
 	}
+	s.ar.RecordAgentActivity(ctx, agentID)
 
 	// build and send response
 	response := getAttestAgentResponse(agentID, svid, attestResult.CanReattest)
@@ -427,6 +573,7 @@ func (s *Service) RenewAgent(ctx context.Context, req *agentv1.RenewAgentRequest
 	if err := s.updateAttestedNode(ctx, update, mask, log); err != nil {
 		return nil, err
 	}
+	s.ar.RecordAgentActivity(ctx, callerID)
 	rpccontext.AuditRPC(ctx)
 
 	// Send response with new X509 SVID
@@ -705,6 +852,46 @@ func fieldsFromFilterRequest(filter *agentv1.ListAgentsRequest_Filter) logrus.Fi
 	return fields
 }
 
+func fieldsFromSearchFilter(filter *agentextv1.SearchAgentsRequest_Filter) logrus.Fields {
+	fields := fieldsFromFilterRequest(&agentv1.ListAgentsRequest_Filter{
+		ByAttestationType: filter.ByAttestationType,
+		BySelectorMatch:   filter.BySelectorMatch,
+		ByBanned:          filter.ByBanned,
+		ByCanReattest:     filter.ByCanReattest,
+	})
+
+	if filter.ByExpiresBefore != 0 {
+		fields[telemetry.ByExpiresBefore] = filter.ByExpiresBefore
+	}
+
+	if filter.ByAgentVersion != "" {
+		fields[telemetry.ByAgentVersion] = filter.ByAgentVersion
+	}
+
+	if filter.ByLastSeenBefore != 0 {
+		fields[telemetry.ByLastSeenBefore] = filter.ByLastSeenBefore
+	}
+
+	return fields
+}
+
+func nodeSortFieldFromProto(sortBy agentextv1.SearchAgentsRequest_SortBy) (datastore.NodeSortField, error) {
+	switch sortBy {
+	case agentextv1.SearchAgentsRequest_SORT_BY_DEFAULT:
+		return datastore.SortNodesByID, nil
+	case agentextv1.SearchAgentsRequest_SORT_BY_SPIFFE_ID:
+		return datastore.SortNodesBySpiffeID, nil
+	case agentextv1.SearchAgentsRequest_SORT_BY_ATTESTATION_TYPE:
+		return datastore.SortNodesByAttestationType, nil
+	case agentextv1.SearchAgentsRequest_SORT_BY_EXPIRES_AT:
+		return datastore.SortNodesByExpiresAt, nil
+	case agentextv1.SearchAgentsRequest_SORT_BY_LAST_SEEN_AT:
+		return datastore.SortNodesByLastSeenAt, nil
+	default:
+		return 0, fmt.Errorf("unsupported sort field %q", sortBy)
+	}
+}
+
 func joinTokenID(td spiffeid.TrustDomain, token string) (spiffeid.ID, error) {
 	return spiffeid.FromSegments(td, "spire", "agent", "join_token", token)
 }
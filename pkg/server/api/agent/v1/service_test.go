@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/api/middleware"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	agentextv1 "github.com/spiffe/spire/proto/spire/api/server/agent/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/fakes/fakedatastore"
@@ -927,6 +929,266 @@ func TestListAgents(t *testing.T) {
 	}
 }
 
+func TestSearchAgents(t *testing.T) {
+	test := setupServiceTest(t, 0)
+	defer test.Cleanup()
+
+	now := time.Now()
+	node1ID := spiffeid.RequireFromPath(td, "/node1")
+	node1 := &common.AttestedNode{
+		SpiffeId:            node1ID.String(),
+		AttestationDataType: "t1",
+		CertSerialNumber:    "badcafe",
+		CertNotAfter:        now.Add(time.Hour).Unix(),
+		LastSeenAt:          now.Add(-time.Minute).Unix(),
+		AgentVersion:        "1.8.0",
+	}
+	_, err := test.ds.CreateAttestedNode(ctx, node1)
+	require.NoError(t, err)
+
+	node2ID := spiffeid.RequireFromPath(td, "/node2")
+	node2 := &common.AttestedNode{
+		SpiffeId:            node2ID.String(),
+		AttestationDataType: "t2",
+		CertSerialNumber:    "deadbeef",
+		CertNotAfter:        now.Add(-time.Hour).Unix(),
+		LastSeenAt:          now.Add(-48 * time.Hour).Unix(),
+		AgentVersion:        "1.7.2",
+	}
+	_, err = test.ds.CreateAttestedNode(ctx, node2)
+	require.NoError(t, err)
+
+	node3ID := spiffeid.RequireFromPath(td, "/node3")
+	node3 := &common.AttestedNode{
+		SpiffeId:            node3ID.String(),
+		AttestationDataType: "t1",
+		CertSerialNumber:    "",
+		CertNotAfter:        now.Add(2 * time.Hour).Unix(),
+		LastSeenAt:          now.Add(-time.Hour).Unix(),
+		AgentVersion:        "1.8.0",
+		CanReattest:         true,
+	}
+	_, err = test.ds.CreateAttestedNode(ctx, node3)
+	require.NoError(t, err)
+
+	agentInfo := func(node *common.AttestedNode, id spiffeid.ID) *agentextv1.AgentInfo {
+		return &agentextv1.AgentInfo{
+			Agent:        &types.Agent{Id: api.ProtoFromID(id), AttestationType: node.AttestationDataType},
+			LastSeenAt:   node.LastSeenAt,
+			AgentVersion: node.AgentVersion,
+		}
+	}
+	info1 := agentInfo(node1, node1ID)
+	info2 := agentInfo(node2, node2ID)
+	info3 := agentInfo(node3, node3ID)
+	outputMask := &types.AgentMask{AttestationType: true}
+
+	for _, tt := range []struct {
+		name string
+
+		code       codes.Code
+		dsError    error
+		err        string
+		expectLogs []spiretest.LogEntry
+		expectResp *agentextv1.SearchAgentsResponse
+		req        *agentextv1.SearchAgentsRequest
+	}{
+		{
+			name: "success",
+			req: &agentextv1.SearchAgentsRequest{
+				OutputMask: outputMask,
+			},
+			expectResp: &agentextv1.SearchAgentsResponse{
+				Agents: []*agentextv1.AgentInfo{info1, info2, info3},
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status: "success",
+						telemetry.Type:   "audit",
+					},
+				},
+			},
+		},
+		{
+			name: "by agent version and attestation type",
+			req: &agentextv1.SearchAgentsRequest{
+				OutputMask: outputMask,
+				Filter: &agentextv1.SearchAgentsRequest_Filter{
+					ByAttestationType: "t1",
+					ByAgentVersion:    "1.8.0",
+					ByCanReattest:     wrapperspb.Bool(false),
+				},
+			},
+			expectResp: &agentextv1.SearchAgentsResponse{
+				Agents: []*agentextv1.AgentInfo{info1},
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:           "success",
+						telemetry.Type:             "audit",
+						telemetry.NodeAttestorType: "t1",
+						telemetry.ByAgentVersion:   "1.8.0",
+						telemetry.ByCanReattest:    "false",
+					},
+				},
+			},
+		},
+		{
+			name: "by expires before",
+			req: &agentextv1.SearchAgentsRequest{
+				OutputMask: outputMask,
+				Filter: &agentextv1.SearchAgentsRequest_Filter{
+					ByExpiresBefore: now.Unix(),
+				},
+			},
+			expectResp: &agentextv1.SearchAgentsResponse{
+				Agents: []*agentextv1.AgentInfo{info2},
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:          "success",
+						telemetry.Type:            "audit",
+						telemetry.ByExpiresBefore: fmt.Sprint(now.Unix()),
+					},
+				},
+			},
+		},
+		{
+			name: "by last seen before, sorted by last seen",
+			req: &agentextv1.SearchAgentsRequest{
+				OutputMask: outputMask,
+				Filter: &agentextv1.SearchAgentsRequest_Filter{
+					ByLastSeenBefore: now.Add(-30 * time.Minute).Unix(),
+				},
+				SortBy: agentextv1.SearchAgentsRequest_SORT_BY_LAST_SEEN_AT,
+			},
+			expectResp: &agentextv1.SearchAgentsResponse{
+				Agents: []*agentextv1.AgentInfo{info2, info3},
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:           "success",
+						telemetry.Type:             "audit",
+						telemetry.ByLastSeenBefore: fmt.Sprint(now.Add(-30 * time.Minute).Unix()),
+						telemetry.SortBy:           "SORT_BY_LAST_SEEN_AT",
+						telemetry.SortDescending:   "false",
+					},
+				},
+			},
+		},
+		{
+			name: "sorted by expiration descending with pagination",
+			req: &agentextv1.SearchAgentsRequest{
+				OutputMask:     outputMask,
+				SortBy:         agentextv1.SearchAgentsRequest_SORT_BY_EXPIRES_AT,
+				SortDescending: true,
+				PageSize:       2,
+			},
+			expectResp: &agentextv1.SearchAgentsResponse{
+				Agents:        []*agentextv1.AgentInfo{info3, info1},
+				NextPageToken: "1:" + time.Unix(node1.CertNotAfter, 0).Format(time.RFC3339Nano),
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:         "success",
+						telemetry.Type:           "audit",
+						telemetry.SortBy:         "SORT_BY_EXPIRES_AT",
+						telemetry.SortDescending: "true",
+					},
+				},
+			},
+		},
+		{
+			name: "unsupported sort field",
+			req: &agentextv1.SearchAgentsRequest{
+				SortBy: 100,
+			},
+			code: codes.InvalidArgument,
+			err:  `failed to parse sort field: unsupported sort field "100"`,
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Invalid argument: failed to parse sort field",
+					Data: logrus.Fields{
+						logrus.ErrorKey: `unsupported sort field "100"`,
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.Type:          "audit",
+						telemetry.StatusCode:    "InvalidArgument",
+						telemetry.StatusMessage: `failed to parse sort field: unsupported sort field "100"`,
+					},
+				},
+			},
+		},
+		{
+			name:    "ds fails",
+			req:     &agentextv1.SearchAgentsRequest{},
+			code:    codes.Internal,
+			dsError: errors.New("some error"),
+			err:     "failed to search agents: some error",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Failed to search agents",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "some error",
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.Type:          "audit",
+						telemetry.StatusCode:    "Internal",
+						telemetry.StatusMessage: "failed to search agents: some error",
+					},
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.logHook.Reset()
+			test.ds.SetNextError(tt.dsError)
+
+			resp, err := test.extClient.SearchAgents(ctx, tt.req)
+
+			spiretest.AssertLogs(t, test.logHook.AllEntries(), tt.expectLogs)
+			if tt.err != "" {
+				spiretest.RequireGRPCStatusContains(t, err, tt.code, tt.err)
+				require.Nil(t, resp)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+
+			spiretest.RequireProtoEqual(t, tt.expectResp, resp)
+		})
+	}
+}
+
 func TestBanAgent(t *testing.T) {
 	agentPath := "/spire/agent/agent-1"
 
@@ -1657,6 +1919,126 @@ func TestGetAgent(t *testing.T) {
 	}
 }
 
+func TestGetAgentInfo(t *testing.T) {
+	node := &common.AttestedNode{
+		SpiffeId:            agent1,
+		AttestationDataType: "type-1",
+		CertSerialNumber:    "CertSerialNumber-1",
+		CertNotAfter:        1,
+		LastSeenAt:          12345,
+		AgentVersion:        "1.8.0",
+		RemoteAddress:       "192.0.2.1",
+	}
+
+	for _, tt := range []struct {
+		name       string
+		req        *agentextv1.GetAgentInfoRequest
+		expectInfo *agentextv1.AgentInfo
+		code       codes.Code
+		err        string
+		logs       []spiretest.LogEntry
+	}{
+		{
+			name: "success",
+			req:  &agentextv1.GetAgentInfoRequest{Id: &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/agent-1"}},
+			expectInfo: &agentextv1.AgentInfo{
+				Agent: &types.Agent{
+					Id:                   &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/agent-1"},
+					AttestationType:      "type-1",
+					X509SvidSerialNumber: "CertSerialNumber-1",
+					X509SvidExpiresAt:    1,
+				},
+				LastSeenAt:    12345,
+				AgentVersion:  "1.8.0",
+				RemoteAddress: "192.0.2.1",
+			},
+			logs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:   "success",
+						telemetry.Type:     "audit",
+						telemetry.SPIFFEID: agent1,
+					},
+				},
+			},
+		},
+		{
+			name: "success - with all false mask",
+			req: &agentextv1.GetAgentInfoRequest{
+				Id:         &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/agent-1"},
+				OutputMask: &types.AgentMask{},
+			},
+			expectInfo: &agentextv1.AgentInfo{
+				Agent: &types.Agent{
+					Id: &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/agent-1"},
+				},
+				LastSeenAt:    12345,
+				AgentVersion:  "1.8.0",
+				RemoteAddress: "192.0.2.1",
+			},
+			logs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:   "success",
+						telemetry.Type:     "audit",
+						telemetry.SPIFFEID: agent1,
+					},
+				},
+			},
+		},
+		{
+			name: "agent does not exist",
+			req:  &agentextv1.GetAgentInfoRequest{Id: &types.SPIFFEID{TrustDomain: "example.org", Path: "/spire/agent/does-not-exist"}},
+			logs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Agent not found",
+					Data: logrus.Fields{
+						telemetry.SPIFFEID: "spiffe://example.org/spire/agent/does-not-exist",
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.Type:          "audit",
+						telemetry.SPIFFEID:      "spiffe://example.org/spire/agent/does-not-exist",
+						telemetry.StatusCode:    "NotFound",
+						telemetry.StatusMessage: "agent not found",
+					},
+				},
+			},
+			err:  "agent not found",
+			code: codes.NotFound,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupServiceTest(t, 0)
+			defer test.Cleanup()
+
+			_, err := test.ds.CreateAttestedNode(ctx, node)
+			require.NoError(t, err)
+
+			info, err := test.extClient.GetAgentInfo(ctx, tt.req)
+			spiretest.AssertLogs(t, test.logHook.AllEntries(), tt.logs)
+			if tt.err != "" {
+				require.Nil(t, info)
+				spiretest.RequireGRPCStatusContains(t, err, tt.code, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			spiretest.AssertProtoEqual(t, tt.expectInfo, info)
+		})
+	}
+}
+
 func TestRenewAgent(t *testing.T) {
 	agentIDType := &types.SPIFFEID{TrustDomain: "example.org", Path: "/agent"}
 
@@ -1985,12 +2367,14 @@ func TestRenewAgent(t *testing.T) {
 
 			if tt.expectCode != codes.OK {
 				require.Nil(t, resp)
+				require.Empty(t, test.activityRecorder.recorded)
 				spiretest.AssertLogs(t, test.logHook.AllEntries(), tt.expectLogs)
 				return
 			}
 
 			require.NoError(t, err)
 			require.NotNil(t, resp)
+			require.Equal(t, []spiffeid.ID{agentID}, test.activityRecorder.recorded)
 
 			// Validate SVID
 			spiretest.AssertProtoEqual(t, agentIDType, resp.Svid.Id)
@@ -3152,22 +3536,25 @@ func TestAttestAgent(t *testing.T) {
 				require.NotNil(t, result)
 				test.assertAttestAgentResult(t, tt.expectedID, result)
 				test.assertAgentWasStored(t, tt.expectedID.String(), tt.expectedSelectors)
+				require.Contains(t, test.activityRecorder.recorded, tt.expectedID)
 			}
 		})
 	}
 }
 
 type serviceTest struct {
-	client       agentv1.AgentClient
-	done         func()
-	ds           *fakedatastore.DataStore
-	ca           *fakeserverca.CA
-	cat          *fakeservercatalog.Catalog
-	clk          clock.Clock
-	logHook      *test.Hook
-	rateLimiter  *fakeRateLimiter
-	withCallerID bool
-	pluginCloser func()
+	client           agentv1.AgentClient
+	extClient        agentextv1.AgentExtensionsClient
+	done             func()
+	ds               *fakedatastore.DataStore
+	ca               *fakeserverca.CA
+	cat              *fakeservercatalog.Catalog
+	clk              clock.Clock
+	logHook          *test.Hook
+	rateLimiter      *fakeRateLimiter
+	activityRecorder *fakeActivityRecorder
+	withCallerID     bool
+	pluginCloser     func()
 }
 
 func (s *serviceTest) Cleanup() {
@@ -3184,13 +3571,15 @@ func setupServiceTest(t *testing.T, agentSVIDTTL time.Duration) *serviceTest {
 	ds := fakedatastore.New(t)
 	cat := fakeservercatalog.New()
 	clk := clock.NewMock(t)
+	activityRecorder := &fakeActivityRecorder{}
 
 	service := agent.New(agent.Config{
-		ServerCA:    ca,
-		DataStore:   ds,
-		TrustDomain: td,
-		Clock:       clk,
-		Catalog:     cat,
+		ServerCA:         ca,
+		DataStore:        ds,
+		TrustDomain:      td,
+		Clock:            clk,
+		Catalog:          cat,
+		ActivityRecorder: activityRecorder,
 	})
 
 	log, logHook := test.NewNullLogger()
@@ -3202,12 +3591,13 @@ func setupServiceTest(t *testing.T, agentSVIDTTL time.Duration) *serviceTest {
 	rateLimiter := &fakeRateLimiter{}
 
 	test := &serviceTest{
-		ca:          ca,
-		ds:          ds,
-		cat:         cat,
-		clk:         clk,
-		logHook:     logHook,
-		rateLimiter: rateLimiter,
+		ca:               ca,
+		ds:               ds,
+		cat:              cat,
+		clk:              clk,
+		logHook:          logHook,
+		rateLimiter:      rateLimiter,
+		activityRecorder: activityRecorder,
 	}
 
 	ppMiddleware := middleware.Preprocess(func(ctx context.Context, fullMethod string, req interface{}) (context.Context, error) {
@@ -3231,6 +3621,7 @@ func setupServiceTest(t *testing.T, agentSVIDTTL time.Duration) *serviceTest {
 	conn, done := spiretest.NewAPIServerWithMiddleware(t, registerFn, server)
 	test.done = done
 	test.client = agentv1.NewAgentClient(conn)
+	test.extClient = agentextv1.NewAgentExtensionsClient(conn)
 
 	return test
 }
@@ -3366,6 +3757,17 @@ func (f *fakeRateLimiter) RateLimit(_ context.Context, count int) error {
 	return f.err
 }
 
+type fakeActivityRecorder struct {
+	mu       sync.Mutex
+	recorded []spiffeid.ID
+}
+
+func (f *fakeActivityRecorder) RecordAgentActivity(_ context.Context, agentID spiffeid.ID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recorded = append(f.recorded, agentID)
+}
+
 func cloneAttestedNode(aNode *common.AttestedNode) *common.AttestedNode {
 	return proto.Clone(aNode).(*common.AttestedNode)
 }
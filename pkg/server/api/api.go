@@ -28,6 +28,23 @@ func (fn AuthorizedEntryFetcherFunc) FetchAuthorizedEntries(ctx context.Context,
 	return fn(ctx, id)
 }
 
+// AgentActivityRecorder records the activity of agents calling the server
+type AgentActivityRecorder interface {
+	// RecordAgentActivity records that the specified agent was seen by the
+	// server, along with the version and address it called the server with
+	RecordAgentActivity(ctx context.Context, agentID spiffeid.ID)
+}
+
+// AgentActivityRecorderFunc is an implementation of AgentActivityRecorder
+// using a function.
+type AgentActivityRecorderFunc func(ctx context.Context, agentID spiffeid.ID)
+
+// RecordAgentActivity records that the specified agent was seen by the
+// server, along with the version and address it called the server with
+func (fn AgentActivityRecorderFunc) RecordAgentActivity(ctx context.Context, agentID spiffeid.ID) {
+	fn(ctx, agentID)
+}
+
 // AttestedNodeToProto converts an agent from the given *common.AttestedNode with
 // the provided selectors to *types.Agent
 func AttestedNodeToProto(node *common.AttestedNode, selectors []*types.Selector) (*types.Agent, error) {
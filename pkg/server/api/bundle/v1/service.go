@@ -13,6 +13,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
 	"github.com/spiffe/spire/pkg/server/cache/dscache"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	bundleextv1 "github.com/spiffe/spire/proto/spire/api/server/bundle/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -42,6 +43,7 @@ type Config struct {
 // Service defines the v1 bundle service properties.
 type Service struct {
 	bundlev1.UnsafeBundleServer
+	bundleextv1.UnsafeBundleExtensionsServer
 
 	ds datastore.DataStore
 	td spiffeid.TrustDomain
@@ -60,6 +62,7 @@ func New(config Config) *Service {
 // RegisterService registers the bundle service on the gRPC server.
 func RegisterService(s *grpc.Server, service *Service) {
 	bundlev1.RegisterBundleServer(s, service)
+	bundleextv1.RegisterBundleExtensionsServer(s, service)
 }
 
 // CountBundles returns the total number of bundles.
@@ -98,6 +101,37 @@ func (s *Service) GetBundle(ctx context.Context, req *bundlev1.GetBundleRequest)
 	return bundle, nil
 }
 
+// GetTaintedAuthorities returns the tainted authorities of the bundle
+// associated with the trust domain of the server.
+func (s *Service) GetTaintedAuthorities(ctx context.Context, _ *bundleextv1.GetTaintedAuthoritiesRequest) (*bundleextv1.GetTaintedAuthoritiesResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, logrus.Fields{telemetry.TrustDomainID: s.td.Name()})
+	log := rpccontext.Logger(ctx)
+
+	commonBundle, err := s.ds.FetchBundle(dscache.WithCache(ctx), s.td.IDString())
+	if err != nil {
+		return nil, api.MakeErr(log, codes.Internal, "failed to fetch bundle", err)
+	}
+
+	if commonBundle == nil {
+		return nil, api.MakeErr(log, codes.NotFound, "bundle not found", nil)
+	}
+
+	resp := &bundleextv1.GetTaintedAuthoritiesResponse{}
+	for _, rootCA := range commonBundle.RootCas {
+		if rootCA.TaintedKey {
+			resp.X509Authorities = append(resp.X509Authorities, rootCA.DerBytes)
+		}
+	}
+	for _, jwtSigningKey := range commonBundle.JwtSigningKeys {
+		if jwtSigningKey.TaintedKey {
+			resp.JwtAuthorities = append(resp.JwtAuthorities, jwtSigningKey.Kid)
+		}
+	}
+
+	rpccontext.AuditRPC(ctx)
+	return resp, nil
+}
+
 // AppendBundle appends the given authorities to the given bundlev1.
 func (s *Service) AppendBundle(ctx context.Context, req *bundlev1.AppendBundleRequest) (*types.Bundle, error) {
 	parseRequest := func() logrus.Fields {
@@ -24,6 +24,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/api/middleware"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	bundleextv1 "github.com/spiffe/spire/proto/spire/api/server/bundle/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/fakes/fakedatastore"
 	"github.com/spiffe/spire/test/spiretest"
@@ -389,6 +390,106 @@ func TestGetBundle(t *testing.T) {
 	}
 }
 
+func TestGetTaintedAuthorities(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		err        string
+		expectResp *bundleextv1.GetTaintedAuthoritiesResponse
+		expectLogs []spiretest.LogEntry
+		setBundle  bool
+		taint      bool
+	}{
+		{
+			name:       "No tainted authorities",
+			setBundle:  true,
+			expectResp: &bundleextv1.GetTaintedAuthoritiesResponse{},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "success",
+						telemetry.TrustDomainID: "example.org",
+						telemetry.Type:          "audit",
+					},
+				},
+			},
+		},
+		{
+			name:      "Tainted authorities",
+			setBundle: true,
+			taint:     true,
+			expectResp: &bundleextv1.GetTaintedAuthoritiesResponse{
+				X509Authorities: [][]byte{[]byte("tainted-root")},
+				JwtAuthorities:  []string{"tainted-kid"},
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "success",
+						telemetry.TrustDomainID: "example.org",
+						telemetry.Type:          "audit",
+					},
+				},
+			},
+		},
+		{
+			name: "Bundle not found",
+			err:  "bundle not found",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Bundle not found",
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.StatusCode:    "NotFound",
+						telemetry.StatusMessage: "bundle not found",
+						telemetry.TrustDomainID: "example.org",
+						telemetry.Type:          "audit",
+					},
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupServiceTest(t)
+			defer test.Cleanup()
+
+			if tt.setBundle {
+				test.setBundle(t, &common.Bundle{
+					TrustDomainId: serverTrustDomain.IDString(),
+					RootCas: []*common.Certificate{
+						{DerBytes: []byte("root")},
+						{DerBytes: []byte("tainted-root"), TaintedKey: tt.taint},
+					},
+					JwtSigningKeys: []*common.PublicKey{
+						{Kid: "kid", PkixBytes: []byte("key")},
+						{Kid: "tainted-kid", PkixBytes: []byte("tainted-key"), TaintedKey: tt.taint},
+					},
+				})
+			}
+
+			resp, err := test.extClient.GetTaintedAuthorities(ctx, &bundleextv1.GetTaintedAuthoritiesRequest{})
+			spiretest.AssertLogs(t, test.logHook.AllEntries(), tt.expectLogs)
+			if tt.err != "" {
+				spiretest.RequireGRPCStatusContains(t, err, codes.NotFound, tt.err)
+				require.Nil(t, resp)
+				return
+			}
+
+			require.NoError(t, err)
+			spiretest.AssertProtoEqual(t, tt.expectResp, resp)
+		})
+	}
+}
+
 func TestAppendBundle(t *testing.T) {
 	ca := testca.New(t, serverTrustDomain)
 	rootCA := ca.X509Authorities()[0]
@@ -2879,6 +2980,7 @@ func (c *serviceTest) setBundle(t *testing.T, b *common.Bundle) {
 
 type serviceTest struct {
 	client      bundlev1.BundleClient
+	extClient   bundleextv1.BundleExtensionsClient
 	ds          *fakedatastore.DataStore
 	logHook     *test.Hook
 	up          *fakeUpstreamPublisher
@@ -2947,6 +3049,7 @@ func setupServiceTest(t *testing.T) *serviceTest {
 	conn, done := spiretest.NewAPIServerWithMiddleware(t, registerFn, server)
 	test.done = done
 	test.client = bundlev1.NewBundleClient(conn)
+	test.extClient = bundleextv1.NewBundleExtensionsClient(conn)
 
 	return test
 }
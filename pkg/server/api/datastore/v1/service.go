@@ -0,0 +1,148 @@
+package datastore
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/api"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/pkg/server/datastore/archive"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// chunkSize is the maximum size of the archive chunks sent on the stream.
+const chunkSize = 64 * 1024
+
+// RegisterService registers the service on the gRPC server.
+func RegisterService(s *grpc.Server, service *Service) {
+	datastorev1.RegisterDataStoreServer(s, service)
+}
+
+// Config is the service configuration
+type Config struct {
+	TrustDomain spiffeid.TrustDomain
+	DataStore   datastore.DataStore
+}
+
+// New creates a new DataStore service
+func New(config Config) *Service {
+	return &Service{
+		td: config.TrustDomain,
+		ds: config.DataStore,
+	}
+}
+
+// Service implements the v1 DataStore service
+type Service struct {
+	datastorev1.UnsafeDataStoreServer
+
+	td spiffeid.TrustDomain
+	ds datastore.DataStore
+}
+
+func (s *Service) Export(_ *datastorev1.ExportRequest, stream datastorev1.DataStore_ExportServer) error {
+	ctx := stream.Context()
+	log := rpccontext.Logger(ctx)
+
+	w := bufio.NewWriterSize(exportWriter{stream: stream}, chunkSize)
+	counts, err := archive.Export(ctx, s.ds, s.td, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		return api.MakeErr(log, codes.Internal, "failed to export datastore", err)
+	}
+
+	resp := &datastorev1.ExportResponse{
+		Counts: recordCountsToProto(counts),
+	}
+	if err := stream.Send(resp); err != nil {
+		return api.MakeErr(log, codes.Unknown, "failed to send response", err)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("Datastore exported")
+	return nil
+}
+
+func (s *Service) Import(stream datastorev1.DataStore_ImportServer) error {
+	ctx := stream.Context()
+	log := rpccontext.Logger(ctx)
+
+	result, err := archive.Import(ctx, s.ds, s.td, &importReader{stream: stream})
+	if err != nil {
+		var recvErr *receiveError
+		if errors.As(err, &recvErr) {
+			return api.MakeErr(log, codes.Unknown, "failed to receive request", recvErr.err)
+		}
+		return api.MakeErr(log, codes.InvalidArgument, "failed to import datastore", err)
+	}
+
+	if err := stream.SendAndClose(&datastorev1.ImportResponse{
+		Imported: recordCountsToProto(&result.Imported),
+		Skipped:  recordCountsToProto(&result.Skipped),
+	}); err != nil {
+		return api.MakeErr(log, codes.Unknown, "failed to send response", err)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("Datastore imported")
+	return nil
+}
+
+// exportWriter sends each write as a chunk of the archive on the stream.
+type exportWriter struct {
+	stream datastorev1.DataStore_ExportServer
+}
+
+func (w exportWriter) Write(p []byte) (int, error) {
+	if err := w.stream.Send(&datastorev1.ExportResponse{Chunk: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// importReader reads the chunks of the archive received on the stream.
+type importReader struct {
+	stream datastorev1.DataStore_ImportServer
+	buf    []byte
+}
+
+func (r *importReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		switch {
+		case errors.Is(err, io.EOF):
+			return 0, io.EOF
+		case err != nil:
+			return 0, &receiveError{err: err}
+		}
+		r.buf = req.Chunk
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+type receiveError struct {
+	err error
+}
+
+func (e *receiveError) Error() string {
+	return e.err.Error()
+}
+
+func recordCountsToProto(counts *archive.Counts) *datastorev1.RecordCounts {
+	return &datastorev1.RecordCounts{
+		Bundles:                 counts.Bundles,
+		FederationRelationships: counts.FederationRelationships,
+		AttestedNodes:           counts.AttestedNodes,
+		RegistrationEntries:     counts.RegistrationEntries,
+		JoinTokens:              counts.JoinTokens,
+	}
+}
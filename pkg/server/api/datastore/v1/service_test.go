@@ -0,0 +1,216 @@
+package datastore_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	datastore "github.com/spiffe/spire/pkg/server/api/datastore/v1"
+	"github.com/spiffe/spire/pkg/server/api/middleware"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ctx = context.Background()
+	td  = spiffeid.RequireTrustDomainFromString("example.org")
+)
+
+func TestExportImport(t *testing.T) {
+	src := setupServiceTest(t)
+	defer src.Cleanup()
+
+	_, err := src.ds.CreateBundle(ctx, &common.Bundle{
+		TrustDomainId: td.IDString(),
+		RootCas:       []*common.Certificate{{DerBytes: []byte("root")}},
+	})
+	require.NoError(t, err)
+	entry, err := src.ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+		ParentId:  "spiffe://example.org/agent",
+		SpiffeId:  "spiffe://example.org/workload",
+		Selectors: []*common.Selector{{Type: "unix", Value: "uid:1000"}},
+	})
+	require.NoError(t, err)
+
+	archive, counts := src.export(t)
+	spiretest.AssertProtoEqual(t, &datastorev1.RecordCounts{
+		Bundles:             1,
+		RegistrationEntries: 1,
+	}, counts)
+	spiretest.AssertLastLogs(t, src.logHook.AllEntries(), []spiretest.LogEntry{
+		{
+			Level:   logrus.InfoLevel,
+			Message: "API accessed",
+			Data: logrus.Fields{
+				telemetry.Status: "success",
+				telemetry.Type:   "audit",
+			},
+		},
+		{
+			Level:   logrus.InfoLevel,
+			Message: "Datastore exported",
+		},
+	})
+
+	dst := setupServiceTest(t)
+	defer dst.Cleanup()
+
+	resp, err := dst.importArchive(t, archive)
+	require.NoError(t, err)
+	spiretest.AssertProtoEqual(t, &datastorev1.ImportResponse{
+		Imported: counts,
+		Skipped:  &datastorev1.RecordCounts{},
+	}, resp)
+
+	restored, err := dst.ds.FetchRegistrationEntry(ctx, entry.EntryId)
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	require.Equal(t, entry.SpiffeId, restored.SpiffeId)
+
+	// Importing again skips the existing entry and merges the bundle
+	resp, err = dst.importArchive(t, archive)
+	require.NoError(t, err)
+	spiretest.AssertProtoEqual(t, &datastorev1.ImportResponse{
+		Imported: &datastorev1.RecordCounts{Bundles: 1},
+		Skipped:  &datastorev1.RecordCounts{RegistrationEntries: 1},
+	}, resp)
+}
+
+func TestExportFailure(t *testing.T) {
+	test := setupServiceTest(t)
+	defer test.Cleanup()
+
+	test.ds.SetNextError(errors.New("oh no"))
+
+	stream, err := test.client.Export(ctx, &datastorev1.ExportRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	spiretest.RequireGRPCStatus(t, err, codes.Internal, "failed to export datastore: failed to list bundles: oh no")
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	test := setupServiceTest(t)
+	defer test.Cleanup()
+
+	_, err := test.importArchive(t, []byte("not an archive"))
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "failed to import datastore: invalid archive: gzip: invalid header")
+	spiretest.AssertLastLogs(t, test.logHook.AllEntries(), []spiretest.LogEntry{
+		{
+			Level:   logrus.ErrorLevel,
+			Message: "Invalid argument: failed to import datastore",
+			Data: logrus.Fields{
+				logrus.ErrorKey: "invalid archive: gzip: invalid header",
+			},
+		},
+		{
+			Level:   logrus.InfoLevel,
+			Message: "API accessed",
+			Data: logrus.Fields{
+				telemetry.Status:        "error",
+				telemetry.StatusCode:    "InvalidArgument",
+				telemetry.StatusMessage: "failed to import datastore: invalid archive: gzip: invalid header",
+				telemetry.Type:          "audit",
+			},
+		},
+	})
+}
+
+type serviceTest struct {
+	client  datastorev1.DataStoreClient
+	done    func()
+	ds      *fakedatastore.DataStore
+	logHook *test.Hook
+}
+
+func (s *serviceTest) Cleanup() {
+	s.done()
+}
+
+func (s *serviceTest) export(t *testing.T) ([]byte, *datastorev1.RecordCounts) {
+	stream, err := s.client.Export(ctx, &datastorev1.ExportRequest{})
+	require.NoError(t, err)
+
+	archive := new(bytes.Buffer)
+	var counts *datastorev1.RecordCounts
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		archive.Write(resp.Chunk)
+		if resp.Counts != nil {
+			counts = resp.Counts
+		}
+	}
+	require.NotNil(t, counts)
+	return archive.Bytes(), counts
+}
+
+func (s *serviceTest) importArchive(t *testing.T, archive []byte) (*datastorev1.ImportResponse, error) {
+	stream, err := s.client.Import(ctx)
+	require.NoError(t, err)
+
+	// Send the archive in small chunks to exercise reassembly
+	for len(archive) > 0 {
+		n := 100
+		if n > len(archive) {
+			n = len(archive)
+		}
+		if err := stream.Send(&datastorev1.ImportRequest{Chunk: archive[:n]}); err != nil {
+			break
+		}
+		archive = archive[n:]
+	}
+	return stream.CloseAndRecv()
+}
+
+func setupServiceTest(t *testing.T) *serviceTest {
+	ds := fakedatastore.New(t)
+	service := datastore.New(datastore.Config{
+		TrustDomain: td,
+		DataStore:   ds,
+	})
+
+	log, logHook := test.NewNullLogger()
+	log.Level = logrus.DebugLevel
+	registerFn := func(s *grpc.Server) {
+		datastore.RegisterService(s, service)
+	}
+
+	ppMiddleware := middleware.Preprocess(func(ctx context.Context, fullMethod string, req interface{}) (context.Context, error) {
+		return rpccontext.WithLogger(ctx, log), nil
+	})
+
+	unaryInterceptor, streamInterceptor := middleware.Interceptors(middleware.Chain(
+		ppMiddleware,
+		// Add audit log with local tracking disabled
+		middleware.WithAuditLog(false),
+	))
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(unaryInterceptor),
+		grpc.StreamInterceptor(streamInterceptor),
+	)
+
+	conn, done := spiretest.NewAPIServerWithMiddleware(t, registerFn, server)
+
+	return &serviceTest{
+		client:  datastorev1.NewDataStoreClient(conn),
+		done:    done,
+		ds:      ds,
+		logHook: logHook,
+	}
+}
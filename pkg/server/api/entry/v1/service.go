@@ -3,7 +3,9 @@ package entry
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
@@ -13,6 +15,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/api"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	entryextv1 "github.com/spiffe/spire/proto/spire/api/server/entry/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -21,18 +24,21 @@ import (
 
 // Config defines the service configuration.
 type Config struct {
-	TrustDomain  spiffeid.TrustDomain
-	EntryFetcher api.AuthorizedEntryFetcher
-	DataStore    datastore.DataStore
+	TrustDomain      spiffeid.TrustDomain
+	EntryFetcher     api.AuthorizedEntryFetcher
+	DataStore        datastore.DataStore
+	ActivityRecorder api.AgentActivityRecorder
 }
 
 // Service defines the v1 entry service.
 type Service struct {
 	entryv1.UnsafeEntryServer
+	entryextv1.UnsafeEntryExtensionsServer
 
 	td spiffeid.TrustDomain
 	ds datastore.DataStore
 	ef api.AuthorizedEntryFetcher
+	ar api.AgentActivityRecorder
 }
 
 // New creates a new v1 entry service.
@@ -41,12 +47,14 @@ func New(config Config) *Service {
 		td: config.TrustDomain,
 		ds: config.DataStore,
 		ef: config.EntryFetcher,
+		ar: config.ActivityRecorder,
 	}
 }
 
 // RegisterService registers the entry service on the gRPC server.
 func RegisterService(s *grpc.Server, service *Service) {
 	entryv1.RegisterEntryServer(s, service)
+	entryextv1.RegisterEntryExtensionsServer(s, service)
 }
 
 // CountEntries returns the total number of entries.
@@ -77,65 +85,93 @@ func (s *Service) ListEntries(ctx context.Context, req *entryv1.ListEntriesReque
 	if req.Filter != nil {
 		rpccontext.AddRPCAuditFields(ctx, fieldsFromListEntryFilter(ctx, s.td, req.Filter))
 
-		if req.Filter.ByHint != nil {
-			listReq.ByHint = req.Filter.ByHint.GetValue()
+		if err := s.parseListEntriesFilter(ctx, log, req.Filter, listReq); err != nil {
+			return nil, err
 		}
+	}
 
-		if req.Filter.ByParentId != nil {
-			parentID, err := api.TrustDomainMemberIDFromProto(ctx, s.td, req.Filter.ByParentId)
-			if err != nil {
-				return nil, api.MakeErr(log, codes.InvalidArgument, "malformed parent ID filter", err)
-			}
-			listReq.ByParentID = parentID.String()
+	dsResp, err := s.ds.ListRegistrationEntries(ctx, listReq)
+	if err != nil {
+		return nil, api.MakeErr(log, codes.Internal, "failed to list entries", err)
+	}
+
+	resp := &entryv1.ListEntriesResponse{}
+	if dsResp.Pagination != nil {
+		resp.NextPageToken = dsResp.Pagination.Token
+	}
+
+	for _, regEntry := range dsResp.Entries {
+		entry, err := api.RegistrationEntryToProto(regEntry)
+		if err != nil {
+			log.WithError(err).Errorf("Failed to convert entry: %q", regEntry.EntryId)
+			continue
 		}
+		applyMask(entry, req.OutputMask)
+		resp.Entries = append(resp.Entries, entry)
+	}
+	rpccontext.AuditRPC(ctx)
 
-		if req.Filter.BySpiffeId != nil {
-			spiffeID, err := api.TrustDomainWorkloadIDFromProto(ctx, s.td, req.Filter.BySpiffeId)
-			if err != nil {
-				return nil, api.MakeErr(log, codes.InvalidArgument, "malformed SPIFFE ID filter", err)
-			}
-			listReq.BySpiffeID = spiffeID.String()
+	return resp, nil
+}
+
+// SearchEntries returns the registration entries matching the filters, which
+// include filters beyond those supported by ListEntries.
+func (s *Service) SearchEntries(ctx context.Context, req *entryextv1.SearchEntriesRequest) (*entryextv1.SearchEntriesResponse, error) {
+	log := rpccontext.Logger(ctx)
+
+	listReq := &datastore.ListRegistrationEntriesRequest{}
+
+	if req.PageSize > 0 {
+		listReq.Pagination = &datastore.Pagination{
+			PageSize: req.PageSize,
+			Token:    req.PageToken,
 		}
+	}
 
-		if req.Filter.BySelectors != nil {
-			dsSelectors, err := api.SelectorsFromProto(req.Filter.BySelectors.Selectors)
-			if err != nil {
-				return nil, api.MakeErr(log, codes.InvalidArgument, "malformed selectors filter", err)
-			}
-			if len(dsSelectors) == 0 {
-				return nil, api.MakeErr(log, codes.InvalidArgument, "malformed selectors filter", errors.New("empty selector set"))
-			}
-			listReq.BySelectors = &datastore.BySelectors{
-				Match:     datastore.MatchBehavior(req.Filter.BySelectors.Match),
-				Selectors: dsSelectors,
-			}
+	if req.Filter != nil {
+		filter := req.Filter
+		rpccontext.AddRPCAuditFields(ctx, fieldsFromSearchEntriesFilter(ctx, s.td, filter))
+
+		if err := s.parseListEntriesFilter(ctx, log, listFilterFromSearchFilter(filter), listReq); err != nil {
+			return nil, err
 		}
 
-		if req.Filter.ByFederatesWith != nil {
-			trustDomains := make([]string, 0, len(req.Filter.ByFederatesWith.TrustDomains))
-			for _, tdStr := range req.Filter.ByFederatesWith.TrustDomains {
-				td, err := spiffeid.TrustDomainFromString(tdStr)
-				if err != nil {
-					return nil, api.MakeErr(log, codes.InvalidArgument, "malformed federates with filter", err)
-				}
-				trustDomains = append(trustDomains, td.IDString())
-			}
-			if len(trustDomains) == 0 {
-				return nil, api.MakeErr(log, codes.InvalidArgument, "malformed federates with filter", errors.New("empty trust domain set"))
-			}
-			listReq.ByFederatesWith = &datastore.ByFederatesWith{
-				Match:        datastore.MatchBehavior(req.Filter.ByFederatesWith.Match),
-				TrustDomains: trustDomains,
+		if filter.BySpiffeIdPrefix != nil {
+			prefix, err := spiffeIDPrefixFromProto(s.td, filter.BySpiffeIdPrefix)
+			if err != nil {
+				return nil, api.MakeErr(log, codes.InvalidArgument, "malformed SPIFFE ID prefix filter", err)
 			}
+			listReq.BySpiffeIDPrefix = prefix
+		}
+		if filter.ByDownstream != nil {
+			listReq.ByDownstream = &filter.ByDownstream.Value
+		}
+		if filter.ByAdmin != nil {
+			listReq.ByAdmin = &filter.ByAdmin.Value
+		}
+		if filter.ByExpiresBefore != 0 {
+			listReq.ByExpiresBefore = time.Unix(filter.ByExpiresBefore, 0)
+		}
+		if filter.ByExpiresAfter != 0 {
+			listReq.ByExpiresAfter = time.Unix(filter.ByExpiresAfter, 0)
 		}
+		if filter.ByX509SvidTtl < 0 {
+			return nil, api.MakeErr(log, codes.InvalidArgument, "malformed X509-SVID TTL filter", errors.New("TTL cannot be negative"))
+		}
+		if filter.ByJwtSvidTtl < 0 {
+			return nil, api.MakeErr(log, codes.InvalidArgument, "malformed JWT-SVID TTL filter", errors.New("TTL cannot be negative"))
+		}
+		listReq.BySelectorType = filter.BySelectorType
+		listReq.ByX509SVIDTTL = filter.ByX509SvidTtl
+		listReq.ByJWTSVIDTTL = filter.ByJwtSvidTtl
 	}
 
 	dsResp, err := s.ds.ListRegistrationEntries(ctx, listReq)
 	if err != nil {
-		return nil, api.MakeErr(log, codes.Internal, "failed to list entries", err)
+		return nil, api.MakeErr(log, codes.Internal, "failed to search entries", err)
 	}
 
-	resp := &entryv1.ListEntriesResponse{}
+	resp := &entryextv1.SearchEntriesResponse{}
 	if dsResp.Pagination != nil {
 		resp.NextPageToken = dsResp.Pagination.Token
 	}
@@ -154,6 +190,64 @@ func (s *Service) ListEntries(ctx context.Context, req *entryv1.ListEntriesReque
 	return resp, nil
 }
 
+// parseListEntriesFilter sets the filters of the datastore request from the
+// given ListEntries filter.
+func (s *Service) parseListEntriesFilter(ctx context.Context, log logrus.FieldLogger, filter *entryv1.ListEntriesRequest_Filter, listReq *datastore.ListRegistrationEntriesRequest) error {
+	if filter.ByHint != nil {
+		listReq.ByHint = filter.ByHint.GetValue()
+	}
+
+	if filter.ByParentId != nil {
+		parentID, err := api.TrustDomainMemberIDFromProto(ctx, s.td, filter.ByParentId)
+		if err != nil {
+			return api.MakeErr(log, codes.InvalidArgument, "malformed parent ID filter", err)
+		}
+		listReq.ByParentID = parentID.String()
+	}
+
+	if filter.BySpiffeId != nil {
+		spiffeID, err := api.TrustDomainWorkloadIDFromProto(ctx, s.td, filter.BySpiffeId)
+		if err != nil {
+			return api.MakeErr(log, codes.InvalidArgument, "malformed SPIFFE ID filter", err)
+		}
+		listReq.BySpiffeID = spiffeID.String()
+	}
+
+	if filter.BySelectors != nil {
+		dsSelectors, err := api.SelectorsFromProto(filter.BySelectors.Selectors)
+		if err != nil {
+			return api.MakeErr(log, codes.InvalidArgument, "malformed selectors filter", err)
+		}
+		if len(dsSelectors) == 0 {
+			return api.MakeErr(log, codes.InvalidArgument, "malformed selectors filter", errors.New("empty selector set"))
+		}
+		listReq.BySelectors = &datastore.BySelectors{
+			Match:     datastore.MatchBehavior(filter.BySelectors.Match),
+			Selectors: dsSelectors,
+		}
+	}
+
+	if filter.ByFederatesWith != nil {
+		trustDomains := make([]string, 0, len(filter.ByFederatesWith.TrustDomains))
+		for _, tdStr := range filter.ByFederatesWith.TrustDomains {
+			td, err := spiffeid.TrustDomainFromString(tdStr)
+			if err != nil {
+				return api.MakeErr(log, codes.InvalidArgument, "malformed federates with filter", err)
+			}
+			trustDomains = append(trustDomains, td.IDString())
+		}
+		if len(trustDomains) == 0 {
+			return api.MakeErr(log, codes.InvalidArgument, "malformed federates with filter", errors.New("empty trust domain set"))
+		}
+		listReq.ByFederatesWith = &datastore.ByFederatesWith{
+			Match:        datastore.MatchBehavior(filter.ByFederatesWith.Match),
+			TrustDomains: trustDomains,
+		}
+	}
+
+	return nil
+}
+
 // GetEntry returns the registration entry associated with the given SpiffeID
 func (s *Service) GetEntry(ctx context.Context, req *entryv1.GetEntryRequest) (*types.Entry, error) {
 	log := rpccontext.Logger(ctx)
@@ -309,6 +403,13 @@ func (s *Service) GetAuthorizedEntries(ctx context.Context, req *entryv1.GetAuth
 	if err != nil {
 		return nil, err
 	}
+
+	// Agents sync their authorized entries periodically, which makes this a
+	// good indicator that the agent is alive.
+	if callerID, ok := rpccontext.CallerID(ctx); ok {
+		s.ar.RecordAgentActivity(ctx, callerID)
+	}
+
 	for i, entry := range entries {
 		applyMask(entry, req.OutputMask)
 		entries[i] = entry
@@ -531,6 +632,72 @@ func fieldsFromEntryProto(ctx context.Context, proto *types.Entry, inputMask *ty
 	return fields
 }
 
+func fieldsFromSearchEntriesFilter(ctx context.Context, td spiffeid.TrustDomain, filter *entryextv1.SearchEntriesRequest_Filter) logrus.Fields {
+	fields := fieldsFromListEntryFilter(ctx, td, listFilterFromSearchFilter(filter))
+
+	if filter.BySpiffeIdPrefix != nil {
+		if prefix, err := spiffeIDPrefixFromProto(td, filter.BySpiffeIdPrefix); err == nil {
+			fields[telemetry.BySpiffeIDPrefix] = prefix
+		}
+	}
+	if filter.BySelectorType != "" {
+		fields[telemetry.BySelectorType] = filter.BySelectorType
+	}
+	if filter.ByDownstream != nil {
+		fields[telemetry.ByDownstream] = filter.ByDownstream.Value
+	}
+	if filter.ByAdmin != nil {
+		fields[telemetry.ByAdmin] = filter.ByAdmin.Value
+	}
+	if filter.ByExpiresBefore != 0 {
+		fields[telemetry.ByExpiresBefore] = filter.ByExpiresBefore
+	}
+	if filter.ByExpiresAfter != 0 {
+		fields[telemetry.ByExpiresAfter] = filter.ByExpiresAfter
+	}
+	if filter.ByX509SvidTtl != 0 {
+		fields[telemetry.ByX509SVIDTTL] = filter.ByX509SvidTtl
+	}
+	if filter.ByJwtSvidTtl != 0 {
+		fields[telemetry.ByJWTSVIDTTL] = filter.ByJwtSvidTtl
+	}
+
+	return fields
+}
+
+// listFilterFromSearchFilter returns the ListEntries filter with the
+// SearchEntries filters that ListEntries supports.
+func listFilterFromSearchFilter(filter *entryextv1.SearchEntriesRequest_Filter) *entryv1.ListEntriesRequest_Filter {
+	return &entryv1.ListEntriesRequest_Filter{
+		BySpiffeId:      filter.BySpiffeId,
+		ByParentId:      filter.ByParentId,
+		BySelectors:     filter.BySelectors,
+		ByFederatesWith: filter.ByFederatesWith,
+		ByHint:          filter.ByHint,
+	}
+}
+
+// spiffeIDPrefixFromProto returns the SPIFFE ID prefix the path of the given
+// SPIFFE ID stands for. The path may end with a slash, unlike SPIFFE ID paths.
+func spiffeIDPrefixFromProto(td spiffeid.TrustDomain, protoID *types.SPIFFEID) (string, error) {
+	prefixTD, err := spiffeid.TrustDomainFromString(protoID.TrustDomain)
+	if err != nil {
+		return "", err
+	}
+	if prefixTD != td {
+		return "", fmt.Errorf("%q is not a member of trust domain %q", prefixTD, td)
+	}
+	if !strings.HasPrefix(protoID.Path, "/") {
+		return "", errors.New("path must start with a slash")
+	}
+	if path := strings.TrimSuffix(protoID.Path, "/"); path != "" {
+		if err := spiffeid.ValidatePath(path); err != nil {
+			return "", err
+		}
+	}
+	return td.IDString() + protoID.Path, nil
+}
+
 func fieldsFromListEntryFilter(ctx context.Context, td spiffeid.TrustDomain, filter *entryv1.ListEntriesRequest_Filter) logrus.Fields {
 	fields := logrus.Fields{}
 
@@ -19,6 +19,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/api/middleware"
 	"github.com/spiffe/spire/pkg/server/api/rpccontext"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	entryextv1 "github.com/spiffe/spire/proto/spire/api/server/entry/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/fakes/fakedatastore"
 	"github.com/spiffe/spire/test/spiretest"
@@ -1213,6 +1214,349 @@ func TestListEntries(t *testing.T) {
 	}
 }
 
+func TestSearchEntries(t *testing.T) {
+	parentID := spiffeid.RequireFromSegments(td, "parent")
+	prodAID := spiffeid.RequireFromSegments(td, "ns", "prod", "a")
+	prodBID := spiffeid.RequireFromSegments(td, "ns", "prod", "b")
+	stagingID := spiffeid.RequireFromSegments(td, "ns", "staging", "a")
+
+	ds := fakedatastore.New(t)
+	test := setupServiceTest(t, ds)
+	defer test.Cleanup()
+
+	prodAEntry, err := test.ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+		ParentId:    parentID.String(),
+		SpiffeId:    prodAID.String(),
+		Selectors:   []*common.Selector{{Type: "unix", Value: "uid:1000"}},
+		Admin:       true,
+		EntryExpiry: 2000000000,
+		X509SvidTtl: 3600,
+		JwtSvidTtl:  300,
+	})
+	require.NoError(t, err)
+
+	prodBEntry, err := test.ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+		ParentId:    parentID.String(),
+		SpiffeId:    prodBID.String(),
+		Selectors:   []*common.Selector{{Type: "k8s", Value: "ns:prod"}},
+		Downstream:  true,
+		X509SvidTtl: 7200,
+		JwtSvidTtl:  600,
+	})
+	require.NoError(t, err)
+
+	stagingEntry, err := test.ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+		ParentId:    parentID.String(),
+		SpiffeId:    stagingID.String(),
+		Selectors:   []*common.Selector{{Type: "unix", Value: "uid:1001"}},
+		EntryExpiry: 1000000000,
+		X509SvidTtl: 3600,
+		JwtSvidTtl:  600,
+	})
+	require.NoError(t, err)
+
+	expectedProdA := &types.Entry{Id: prodAEntry.EntryId, SpiffeId: api.ProtoFromID(prodAID)}
+	expectedProdB := &types.Entry{Id: prodBEntry.EntryId, SpiffeId: api.ProtoFromID(prodBID)}
+	expectedStaging := &types.Entry{Id: stagingEntry.EntryId, SpiffeId: api.ProtoFromID(stagingID)}
+
+	successLogs := func(fields logrus.Fields) []spiretest.LogEntry {
+		data := logrus.Fields{
+			telemetry.Status: "success",
+			telemetry.Type:   "audit",
+		}
+		for k, v := range fields {
+			data[k] = v
+		}
+		return []spiretest.LogEntry{
+			{
+				Level:   logrus.InfoLevel,
+				Message: "API accessed",
+				Data:    data,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name            string
+		err             string
+		code            codes.Code
+		expectLogs      []spiretest.LogEntry
+		dsError         error
+		expectedEntries []*types.Entry
+		filter          *entryextv1.SearchEntriesRequest_Filter
+	}{
+		{
+			name:            "no filter",
+			expectedEntries: []*types.Entry{expectedProdA, expectedProdB, expectedStaging},
+			expectLogs:      successLogs(nil),
+		},
+		{
+			name:            "filter by SPIFFE ID prefix",
+			expectedEntries: []*types.Entry{expectedProdA, expectedProdB},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				BySpiffeIdPrefix: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/ns/prod/"},
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.BySpiffeIDPrefix: "spiffe://example.org/ns/prod/",
+			}),
+		},
+		{
+			name:            "filter by SPIFFE ID prefix matching the whole trust domain",
+			expectedEntries: []*types.Entry{expectedProdA, expectedProdB, expectedStaging},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				BySpiffeIdPrefix: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/"},
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.BySpiffeIDPrefix: "spiffe://example.org/",
+			}),
+		},
+		{
+			name:            "filter by selector type",
+			expectedEntries: []*types.Entry{expectedProdB},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				BySelectorType: "k8s",
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.BySelectorType: "k8s",
+			}),
+		},
+		{
+			name:            "filter by downstream",
+			expectedEntries: []*types.Entry{expectedProdB},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				ByDownstream: wrapperspb.Bool(true),
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.ByDownstream: "true",
+			}),
+		},
+		{
+			name:            "filter by not admin",
+			expectedEntries: []*types.Entry{expectedProdB, expectedStaging},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				ByAdmin: wrapperspb.Bool(false),
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.ByAdmin: "false",
+			}),
+		},
+		{
+			name:            "filter by expires before",
+			expectedEntries: []*types.Entry{expectedStaging},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				ByExpiresBefore: 1500000000,
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.ByExpiresBefore: "1500000000",
+			}),
+		},
+		{
+			name:            "filter by expires after",
+			expectedEntries: []*types.Entry{expectedProdA},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				ByExpiresAfter: 1500000000,
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.ByExpiresAfter: "1500000000",
+			}),
+		},
+		{
+			name:            "filter by X509-SVID TTL",
+			expectedEntries: []*types.Entry{expectedProdA, expectedStaging},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				ByX509SvidTtl: 3600,
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.ByX509SVIDTTL: "3600",
+			}),
+		},
+		{
+			name:            "filter by JWT-SVID TTL",
+			expectedEntries: []*types.Entry{expectedProdB, expectedStaging},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				ByJwtSvidTtl: 600,
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.ByJWTSVIDTTL: "600",
+			}),
+		},
+		{
+			name:            "filters are combined",
+			expectedEntries: []*types.Entry{expectedProdA},
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				ByParentId:       api.ProtoFromID(parentID),
+				BySpiffeIdPrefix: &types.SPIFFEID{TrustDomain: td.Name(), Path: "/ns/"},
+				BySelectorType:   "unix",
+				ByX509SvidTtl:    3600,
+				ByJwtSvidTtl:     300,
+			},
+			expectLogs: successLogs(logrus.Fields{
+				telemetry.ParentID:         "spiffe://example.org/parent",
+				telemetry.BySpiffeIDPrefix: "spiffe://example.org/ns/",
+				telemetry.BySelectorType:   "unix",
+				telemetry.ByX509SVIDTTL:    "3600",
+				telemetry.ByJWTSVIDTTL:     "300",
+			}),
+		},
+		{
+			name: "bad SPIFFE ID prefix filter (foreign trust domain)",
+			err:  `malformed SPIFFE ID prefix filter: "domain1.org" is not a member of trust domain "example.org"`,
+			code: codes.InvalidArgument,
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				BySpiffeIdPrefix: &types.SPIFFEID{TrustDomain: federatedTd.Name(), Path: "/ns/prod/"},
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Invalid argument: malformed SPIFFE ID prefix filter",
+					Data: logrus.Fields{
+						logrus.ErrorKey: `"domain1.org" is not a member of trust domain "example.org"`,
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.Type:          "audit",
+						telemetry.StatusCode:    "InvalidArgument",
+						telemetry.StatusMessage: `malformed SPIFFE ID prefix filter: "domain1.org" is not a member of trust domain "example.org"`,
+					},
+				},
+			},
+		},
+		{
+			name: "bad SPIFFE ID prefix filter (no leading slash)",
+			err:  "malformed SPIFFE ID prefix filter: path must start with a slash",
+			code: codes.InvalidArgument,
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				BySpiffeIdPrefix: &types.SPIFFEID{TrustDomain: td.Name(), Path: "ns/prod/"},
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Invalid argument: malformed SPIFFE ID prefix filter",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "path must start with a slash",
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.Type:          "audit",
+						telemetry.StatusCode:    "InvalidArgument",
+						telemetry.StatusMessage: "malformed SPIFFE ID prefix filter: path must start with a slash",
+					},
+				},
+			},
+		},
+		{
+			name: "bad X509-SVID TTL filter",
+			err:  "malformed X509-SVID TTL filter: TTL cannot be negative",
+			code: codes.InvalidArgument,
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				ByX509SvidTtl: -1,
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Invalid argument: malformed X509-SVID TTL filter",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "TTL cannot be negative",
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.Type:          "audit",
+						telemetry.StatusCode:    "InvalidArgument",
+						telemetry.StatusMessage: "malformed X509-SVID TTL filter: TTL cannot be negative",
+						telemetry.ByX509SVIDTTL: "-1",
+					},
+				},
+			},
+		},
+		{
+			name: "bad JWT-SVID TTL filter",
+			err:  "malformed JWT-SVID TTL filter: TTL cannot be negative",
+			code: codes.InvalidArgument,
+			filter: &entryextv1.SearchEntriesRequest_Filter{
+				ByJwtSvidTtl: -1,
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Invalid argument: malformed JWT-SVID TTL filter",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "TTL cannot be negative",
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.Type:          "audit",
+						telemetry.StatusCode:    "InvalidArgument",
+						telemetry.StatusMessage: "malformed JWT-SVID TTL filter: TTL cannot be negative",
+						telemetry.ByJWTSVIDTTL:  "-1",
+					},
+				},
+			},
+		},
+		{
+			name:    "ds error",
+			err:     "failed to search entries: ds error",
+			code:    codes.Internal,
+			dsError: errors.New("ds error"),
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Failed to search entries",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "ds error",
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.Type:          "audit",
+						telemetry.StatusCode:    "Internal",
+						telemetry.StatusMessage: "failed to search entries: ds error",
+					},
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test.logHook.Reset()
+			ds.SetNextError(tt.dsError)
+
+			resp, err := test.extClient.SearchEntries(context.Background(), &entryextv1.SearchEntriesRequest{
+				Filter:     tt.filter,
+				OutputMask: &types.EntryMask{SpiffeId: true},
+			})
+			spiretest.AssertLogs(t, test.logHook.AllEntries(), tt.expectLogs)
+
+			if tt.err != "" {
+				require.Nil(t, resp)
+				spiretest.RequireGRPCStatusContains(t, err, tt.code, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			spiretest.AssertProtoListEqual(t, tt.expectedEntries, resp.Entries)
+		})
+	}
+}
+
 func TestGetEntry(t *testing.T) {
 	now := time.Now().Unix()
 	ds := fakedatastore.New(t)
@@ -2683,11 +3027,13 @@ func TestGetAuthorizedEntries(t *testing.T) {
 			if tt.err != "" {
 				spiretest.RequireGRPCStatusContains(t, err, tt.code, tt.err)
 				require.Nil(t, resp)
+				require.Empty(t, test.ar.recorded)
 				return
 			}
 
 			require.NoError(t, err)
 			require.NotNil(t, resp)
+			require.Equal(t, []spiffeid.ID{agentID}, test.ar.recorded)
 			expectResponse := &entryv1.GetAuthorizedEntriesResponse{
 				Entries: tt.expectEntries,
 			}
@@ -3988,7 +4334,9 @@ func createTestEntries(t *testing.T, ds datastore.DataStore, entry ...*common.Re
 
 type serviceTest struct {
 	client       entryv1.EntryClient
+	extClient    entryextv1.EntryExtensionsClient
 	ef           *entryFetcher
+	ar           *activityRecorder
 	done         func()
 	ds           datastore.DataStore
 	logHook      *test.Hook
@@ -4001,10 +4349,12 @@ func (s *serviceTest) Cleanup() {
 
 func setupServiceTest(t *testing.T, ds datastore.DataStore) *serviceTest {
 	ef := &entryFetcher{}
+	ar := &activityRecorder{}
 	service := entry.New(entry.Config{
-		TrustDomain:  td,
-		DataStore:    ds,
-		EntryFetcher: ef,
+		TrustDomain:      td,
+		DataStore:        ds,
+		EntryFetcher:     ef,
+		ActivityRecorder: ar,
 	})
 
 	log, logHook := test.NewNullLogger()
@@ -4016,6 +4366,7 @@ func setupServiceTest(t *testing.T, ds datastore.DataStore) *serviceTest {
 		ds:      ds,
 		logHook: logHook,
 		ef:      ef,
+		ar:      ar,
 	}
 
 	ppMiddleware := middleware.Preprocess(func(ctx context.Context, fullMethod string, req interface{}) (context.Context, error) {
@@ -4039,6 +4390,7 @@ func setupServiceTest(t *testing.T, ds datastore.DataStore) *serviceTest {
 	conn, done := spiretest.NewAPIServerWithMiddleware(t, registerFn, server)
 	test.done = done
 	test.client = entryv1.NewEntryClient(conn)
+	test.extClient = entryextv1.NewEntryExtensionsClient(conn)
 
 	return test
 }
@@ -4111,3 +4463,11 @@ func (f *entryFetcher) FetchAuthorizedEntries(ctx context.Context, agentID spiff
 
 	return f.entries, nil
 }
+
+type activityRecorder struct {
+	recorded []spiffeid.ID
+}
+
+func (r *activityRecorder) RecordAgentActivity(_ context.Context, agentID spiffeid.ID) {
+	r.recorded = append(r.recorded, agentID)
+}
@@ -0,0 +1,314 @@
+package localauthority
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/api"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/ca/manager"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/private/server/journal"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CAManager manages the local authorities of the server
+type CAManager interface {
+	GetCurrentJWTKeySlot() manager.Slot
+	GetNextJWTKeySlot() manager.Slot
+	PrepareJWTKey(ctx context.Context) error
+	RotateJWTKeyIf(check func(next manager.Slot) error) (manager.Slot, error)
+
+	GetCurrentX509CASlot() manager.Slot
+	GetNextX509CASlot() manager.Slot
+	PrepareX509CA(ctx context.Context) error
+	RotateX509CAIf(check func(next manager.Slot) error) (manager.Slot, error)
+}
+
+// RegisterService registers the service on the gRPC server.
+func RegisterService(s *grpc.Server, service *Service) {
+	localauthorityv1.RegisterLocalAuthorityServer(s, service)
+}
+
+// Config is the service configuration
+type Config struct {
+	TrustDomain spiffeid.TrustDomain
+	DataStore   datastore.DataStore
+	CAManager   CAManager
+}
+
+// New creates a new LocalAuthority service
+func New(config Config) *Service {
+	return &Service{
+		td: config.TrustDomain,
+		ds: config.DataStore,
+		ca: config.CAManager,
+	}
+}
+
+// Service implements the v1 LocalAuthority service
+type Service struct {
+	localauthorityv1.UnsafeLocalAuthorityServer
+
+	td spiffeid.TrustDomain
+	ds datastore.DataStore
+	ca CAManager
+}
+
+func (s *Service) GetJWTAuthorityState(ctx context.Context, _ *localauthorityv1.GetJWTAuthorityStateRequest) (*localauthorityv1.GetJWTAuthorityStateResponse, error) {
+	current := s.ca.GetCurrentJWTKeySlot()
+	next := s.ca.GetNextJWTKeySlot()
+
+	resp := &localauthorityv1.GetJWTAuthorityStateResponse{
+		Active: stateFromSlot(current),
+	}
+	switch next.Status() {
+	case journal.Status_PREPARED:
+		resp.Prepared = stateFromSlot(next)
+	case journal.Status_OLD:
+		resp.Old = stateFromSlot(next)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	return resp, nil
+}
+
+func (s *Service) PrepareJWTAuthority(ctx context.Context, _ *localauthorityv1.PrepareJWTAuthorityRequest) (*localauthorityv1.PrepareJWTAuthorityResponse, error) {
+	log := rpccontext.Logger(ctx)
+
+	if s.ca.GetCurrentJWTKeySlot().Status() != journal.Status_ACTIVE {
+		return nil, api.MakeErr(log, codes.Unavailable, "server is not ready to prepare a JWT authority", nil)
+	}
+
+	if err := s.ca.PrepareJWTKey(ctx); err != nil {
+		return nil, api.MakeErr(log, codes.Internal, "failed to prepare JWT authority", err)
+	}
+
+	prepared := stateFromSlot(s.ca.GetNextJWTKeySlot())
+	rpccontext.AuditRPCWithFields(ctx, logrus.Fields{
+		telemetry.LocalAuthorityID: prepared.GetAuthorityId(),
+	})
+	log.WithField(telemetry.LocalAuthorityID, prepared.GetAuthorityId()).Info("JWT authority prepared")
+
+	return &localauthorityv1.PrepareJWTAuthorityResponse{
+		PreparedAuthority: prepared,
+	}, nil
+}
+
+func (s *Service) ActivateJWTAuthority(ctx context.Context, req *localauthorityv1.ActivateJWTAuthorityRequest) (*localauthorityv1.ActivateJWTAuthorityResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, auditFields(req.AuthorityId))
+	log := rpccontext.Logger(ctx).WithField(telemetry.LocalAuthorityID, req.AuthorityId)
+
+	// The authority is validated while rotating so concurrent calls
+	// cannot activate it twice
+	current, err := s.ca.RotateJWTKeyIf(func(next manager.Slot) error {
+		return validateAuthorityID(next, req.AuthorityId, journal.Status_PREPARED)
+	})
+	if err != nil {
+		return nil, api.MakeErr(log, codes.InvalidArgument, "invalid authority ID", err)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("JWT authority activated")
+
+	return &localauthorityv1.ActivateJWTAuthorityResponse{
+		ActivatedAuthority: stateFromSlot(current),
+	}, nil
+}
+
+func (s *Service) TaintJWTAuthority(ctx context.Context, req *localauthorityv1.TaintJWTAuthorityRequest) (*localauthorityv1.TaintJWTAuthorityResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, auditFields(req.AuthorityId))
+	log := rpccontext.Logger(ctx).WithField(telemetry.LocalAuthorityID, req.AuthorityId)
+
+	next := s.ca.GetNextJWTKeySlot()
+	if err := validateAuthorityID(next, req.AuthorityId, journal.Status_OLD); err != nil {
+		return nil, api.MakeErr(log, codes.InvalidArgument, "invalid authority ID", err)
+	}
+
+	if _, err := s.ds.TaintJWTKey(ctx, s.td.IDString(), req.AuthorityId); err != nil {
+		return nil, api.MakeErr(log, status.Code(err), "failed to taint JWT authority", err)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("JWT authority tainted")
+
+	return &localauthorityv1.TaintJWTAuthorityResponse{
+		TaintedAuthority: stateFromSlot(next),
+	}, nil
+}
+
+func (s *Service) RevokeJWTAuthority(ctx context.Context, req *localauthorityv1.RevokeJWTAuthorityRequest) (*localauthorityv1.RevokeJWTAuthorityResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, auditFields(req.AuthorityId))
+	log := rpccontext.Logger(ctx).WithField(telemetry.LocalAuthorityID, req.AuthorityId)
+
+	next := s.ca.GetNextJWTKeySlot()
+	if err := validateAuthorityID(next, req.AuthorityId, journal.Status_OLD); err != nil {
+		return nil, api.MakeErr(log, codes.InvalidArgument, "invalid authority ID", err)
+	}
+
+	if _, err := s.ds.RevokeJWTKey(ctx, s.td.IDString(), req.AuthorityId); err != nil {
+		return nil, api.MakeErr(log, status.Code(err), "failed to revoke JWT authority", err)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("JWT authority revoked")
+
+	return &localauthorityv1.RevokeJWTAuthorityResponse{
+		RevokedAuthority: stateFromSlot(next),
+	}, nil
+}
+
+func (s *Service) GetX509AuthorityState(ctx context.Context, _ *localauthorityv1.GetX509AuthorityStateRequest) (*localauthorityv1.GetX509AuthorityStateResponse, error) {
+	current := s.ca.GetCurrentX509CASlot()
+	next := s.ca.GetNextX509CASlot()
+
+	resp := &localauthorityv1.GetX509AuthorityStateResponse{
+		Active: stateFromSlot(current),
+	}
+	switch next.Status() {
+	case journal.Status_PREPARED:
+		resp.Prepared = stateFromSlot(next)
+	case journal.Status_OLD:
+		resp.Old = stateFromSlot(next)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	return resp, nil
+}
+
+func (s *Service) PrepareX509Authority(ctx context.Context, _ *localauthorityv1.PrepareX509AuthorityRequest) (*localauthorityv1.PrepareX509AuthorityResponse, error) {
+	log := rpccontext.Logger(ctx)
+
+	if s.ca.GetCurrentX509CASlot().Status() != journal.Status_ACTIVE {
+		return nil, api.MakeErr(log, codes.Unavailable, "server is not ready to prepare an X.509 authority", nil)
+	}
+
+	if err := s.ca.PrepareX509CA(ctx); err != nil {
+		return nil, api.MakeErr(log, codes.Internal, "failed to prepare X.509 authority", err)
+	}
+
+	prepared := stateFromSlot(s.ca.GetNextX509CASlot())
+	rpccontext.AuditRPCWithFields(ctx, logrus.Fields{
+		telemetry.LocalAuthorityID: prepared.GetAuthorityId(),
+	})
+	log.WithField(telemetry.LocalAuthorityID, prepared.GetAuthorityId()).Info("X.509 authority prepared")
+
+	return &localauthorityv1.PrepareX509AuthorityResponse{
+		PreparedAuthority: prepared,
+	}, nil
+}
+
+func (s *Service) ActivateX509Authority(ctx context.Context, req *localauthorityv1.ActivateX509AuthorityRequest) (*localauthorityv1.ActivateX509AuthorityResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, auditFields(req.AuthorityId))
+	log := rpccontext.Logger(ctx).WithField(telemetry.LocalAuthorityID, req.AuthorityId)
+
+	// The authority is validated while rotating so concurrent calls
+	// cannot activate it twice
+	current, err := s.ca.RotateX509CAIf(func(next manager.Slot) error {
+		return validateAuthorityID(next, req.AuthorityId, journal.Status_PREPARED)
+	})
+	if err != nil {
+		return nil, api.MakeErr(log, codes.InvalidArgument, "invalid authority ID", err)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("X.509 authority activated")
+
+	return &localauthorityv1.ActivateX509AuthorityResponse{
+		ActivatedAuthority: stateFromSlot(current),
+	}, nil
+}
+
+func (s *Service) TaintX509Authority(ctx context.Context, req *localauthorityv1.TaintX509AuthorityRequest) (*localauthorityv1.TaintX509AuthorityResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, auditFields(req.AuthorityId))
+	log := rpccontext.Logger(ctx).WithField(telemetry.LocalAuthorityID, req.AuthorityId)
+
+	next := s.ca.GetNextX509CASlot()
+	if err := validateAuthorityID(next, req.AuthorityId, journal.Status_OLD); err != nil {
+		return nil, api.MakeErr(log, codes.InvalidArgument, "invalid authority ID", err)
+	}
+
+	if err := s.ds.TaintX509CA(ctx, s.td.IDString(), next.PublicKey()); err != nil {
+		return nil, api.MakeErr(log, status.Code(err), "failed to taint X.509 authority", err)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("X.509 authority tainted")
+
+	return &localauthorityv1.TaintX509AuthorityResponse{
+		TaintedAuthority: stateFromSlot(next),
+	}, nil
+}
+
+func (s *Service) RevokeX509Authority(ctx context.Context, req *localauthorityv1.RevokeX509AuthorityRequest) (*localauthorityv1.RevokeX509AuthorityResponse, error) {
+	rpccontext.AddRPCAuditFields(ctx, auditFields(req.AuthorityId))
+	log := rpccontext.Logger(ctx).WithField(telemetry.LocalAuthorityID, req.AuthorityId)
+
+	next := s.ca.GetNextX509CASlot()
+	if err := validateAuthorityID(next, req.AuthorityId, journal.Status_OLD); err != nil {
+		return nil, api.MakeErr(log, codes.InvalidArgument, "invalid authority ID", err)
+	}
+
+	if err := s.ds.RevokeX509CA(ctx, s.td.IDString(), next.PublicKey()); err != nil {
+		return nil, api.MakeErr(log, status.Code(err), "failed to revoke X.509 authority", err)
+	}
+
+	rpccontext.AuditRPC(ctx)
+	log.Info("X.509 authority revoked")
+
+	return &localauthorityv1.RevokeX509AuthorityResponse{
+		RevokedAuthority: stateFromSlot(next),
+	}, nil
+}
+
+// validateAuthorityID verifies that the authority ID refers to the authority
+// held by the slot and that the slot is in the expected status.
+func validateAuthorityID(slot manager.Slot, authorityID string, expectedStatus journal.Status) error {
+	statusName := statusName(expectedStatus)
+	switch {
+	case authorityID == "":
+		return errors.New("no authority ID provided")
+	case slot.Status() != expectedStatus || slot.AuthorityID() == "":
+		return fmt.Errorf("no %s authority found", statusName)
+	case slot.AuthorityID() != authorityID:
+		return fmt.Errorf("only the %s authority can be used, found %q", statusName, slot.AuthorityID())
+	default:
+		return nil
+	}
+}
+
+func statusName(s journal.Status) string {
+	switch s {
+	case journal.Status_PREPARED:
+		return "prepared"
+	case journal.Status_ACTIVE:
+		return "active"
+	case journal.Status_OLD:
+		return "old"
+	default:
+		return "unknown"
+	}
+}
+
+func stateFromSlot(slot manager.Slot) *localauthorityv1.AuthorityState {
+	if slot.AuthorityID() == "" {
+		return nil
+	}
+	return &localauthorityv1.AuthorityState{
+		AuthorityId: slot.AuthorityID(),
+		ExpiresAt:   slot.NotAfter().Unix(),
+	}
+}
+
+func auditFields(authorityID string) logrus.Fields {
+	return logrus.Fields{
+		telemetry.LocalAuthorityID: authorityID,
+	}
+}
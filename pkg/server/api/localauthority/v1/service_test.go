@@ -0,0 +1,523 @@
+package localauthority_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	localauthority "github.com/spiffe/spire/pkg/server/api/localauthority/v1"
+	"github.com/spiffe/spire/pkg/server/api/middleware"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/ca/manager"
+	"github.com/spiffe/spire/proto/private/server/journal"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/spiffe/spire/test/testkey"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ctx = context.Background()
+	td  = spiffeid.RequireTrustDomainFromString("example.org")
+
+	notAfterCurrent = time.Now().Add(time.Hour).Truncate(time.Second)
+	notAfterNext    = time.Now().Add(2 * time.Hour).Truncate(time.Second)
+)
+
+func TestGetJWTAuthorityState(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		next       *fakeSlot
+		expectResp *localauthorityv1.GetJWTAuthorityStateResponse
+	}{
+		{
+			name: "no next authority",
+			next: &fakeSlot{},
+			expectResp: &localauthorityv1.GetJWTAuthorityStateResponse{
+				Active: &localauthorityv1.AuthorityState{AuthorityId: "current", ExpiresAt: notAfterCurrent.Unix()},
+			},
+		},
+		{
+			name: "prepared authority",
+			next: &fakeSlot{authorityID: "next", notAfter: notAfterNext, status: journal.Status_PREPARED},
+			expectResp: &localauthorityv1.GetJWTAuthorityStateResponse{
+				Active:   &localauthorityv1.AuthorityState{AuthorityId: "current", ExpiresAt: notAfterCurrent.Unix()},
+				Prepared: &localauthorityv1.AuthorityState{AuthorityId: "next", ExpiresAt: notAfterNext.Unix()},
+			},
+		},
+		{
+			name: "old authority",
+			next: &fakeSlot{authorityID: "next", notAfter: notAfterNext, status: journal.Status_OLD},
+			expectResp: &localauthorityv1.GetJWTAuthorityStateResponse{
+				Active: &localauthorityv1.AuthorityState{AuthorityId: "current", ExpiresAt: notAfterCurrent.Unix()},
+				Old:    &localauthorityv1.AuthorityState{AuthorityId: "next", ExpiresAt: notAfterNext.Unix()},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupServiceTest(t)
+			defer test.Cleanup()
+
+			test.ca.currentJWTKey = activeSlot("current")
+			test.ca.nextJWTKey = tt.next
+
+			resp, err := test.client.GetJWTAuthorityState(ctx, &localauthorityv1.GetJWTAuthorityStateRequest{})
+			require.NoError(t, err)
+			spiretest.AssertProtoEqual(t, tt.expectResp, resp)
+		})
+	}
+}
+
+func TestPrepareJWTAuthority(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		current    *fakeSlot
+		prepareErr error
+		expectCode codes.Code
+		expectMsg  string
+		expectResp *localauthorityv1.PrepareJWTAuthorityResponse
+		expectLogs []spiretest.LogEntry
+	}{
+		{
+			name:    "success",
+			current: activeSlot("current"),
+			expectResp: &localauthorityv1.PrepareJWTAuthorityResponse{
+				PreparedAuthority: &localauthorityv1.AuthorityState{AuthorityId: "prepared", ExpiresAt: notAfterNext.Unix()},
+			},
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:           "success",
+						telemetry.Type:             "audit",
+						telemetry.LocalAuthorityID: "prepared",
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "JWT authority prepared",
+					Data: logrus.Fields{
+						telemetry.LocalAuthorityID: "prepared",
+					},
+				},
+			},
+		},
+		{
+			name:       "current authority is not active",
+			current:    &fakeSlot{},
+			expectCode: codes.Unavailable,
+			expectMsg:  "server is not ready to prepare a JWT authority",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Server is not ready to prepare a JWT authority",
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.StatusCode:    "Unavailable",
+						telemetry.StatusMessage: "server is not ready to prepare a JWT authority",
+						telemetry.Type:          "audit",
+					},
+				},
+			},
+		},
+		{
+			name:       "prepare fails",
+			current:    activeSlot("current"),
+			prepareErr: errors.New("oh no"),
+			expectCode: codes.Internal,
+			expectMsg:  "failed to prepare JWT authority: oh no",
+			expectLogs: []spiretest.LogEntry{
+				{
+					Level:   logrus.ErrorLevel,
+					Message: "Failed to prepare JWT authority",
+					Data: logrus.Fields{
+						logrus.ErrorKey: "oh no",
+					},
+				},
+				{
+					Level:   logrus.InfoLevel,
+					Message: "API accessed",
+					Data: logrus.Fields{
+						telemetry.Status:        "error",
+						telemetry.StatusCode:    "Internal",
+						telemetry.StatusMessage: "failed to prepare JWT authority: oh no",
+						telemetry.Type:          "audit",
+					},
+				},
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupServiceTest(t)
+			defer test.Cleanup()
+
+			test.ca.currentJWTKey = tt.current
+			test.ca.prepareErr = tt.prepareErr
+
+			resp, err := test.client.PrepareJWTAuthority(ctx, &localauthorityv1.PrepareJWTAuthorityRequest{})
+			spiretest.AssertGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+			spiretest.AssertProtoEqual(t, tt.expectResp, resp)
+			spiretest.AssertLogs(t, test.logHook.AllEntries(), tt.expectLogs)
+		})
+	}
+}
+
+func TestActivateJWTAuthority(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		next        *fakeSlot
+		authorityID string
+		expectCode  codes.Code
+		expectMsg   string
+		expectResp  *localauthorityv1.ActivateJWTAuthorityResponse
+	}{
+		{
+			name:        "success",
+			next:        &fakeSlot{authorityID: "next", notAfter: notAfterNext, status: journal.Status_PREPARED},
+			authorityID: "next",
+			expectResp: &localauthorityv1.ActivateJWTAuthorityResponse{
+				ActivatedAuthority: &localauthorityv1.AuthorityState{AuthorityId: "next", ExpiresAt: notAfterNext.Unix()},
+			},
+		},
+		{
+			name:       "no authority ID",
+			next:       &fakeSlot{authorityID: "next", status: journal.Status_PREPARED},
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "invalid authority ID: no authority ID provided",
+		},
+		{
+			name:        "no prepared authority",
+			next:        &fakeSlot{authorityID: "next", status: journal.Status_OLD},
+			authorityID: "next",
+			expectCode:  codes.InvalidArgument,
+			expectMsg:   "invalid authority ID: no prepared authority found",
+		},
+		{
+			name:        "wrong authority ID",
+			next:        &fakeSlot{authorityID: "next", status: journal.Status_PREPARED},
+			authorityID: "other",
+			expectCode:  codes.InvalidArgument,
+			expectMsg:   `invalid authority ID: only the prepared authority can be used, found "next"`,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			test := setupServiceTest(t)
+			defer test.Cleanup()
+
+			test.ca.currentJWTKey = activeSlot("current")
+			test.ca.nextJWTKey = tt.next
+
+			resp, err := test.client.ActivateJWTAuthority(ctx, &localauthorityv1.ActivateJWTAuthorityRequest{
+				AuthorityId: tt.authorityID,
+			})
+			spiretest.AssertGRPCStatus(t, err, tt.expectCode, tt.expectMsg)
+			spiretest.AssertProtoEqual(t, tt.expectResp, resp)
+			if tt.expectCode == codes.OK {
+				require.Equal(t, "next", test.ca.currentJWTKey.authorityID)
+				require.Equal(t, journal.Status_OLD, test.ca.nextJWTKey.status)
+			}
+		})
+	}
+}
+
+func TestTaintAndRevokeJWTAuthority(t *testing.T) {
+	test := setupServiceTest(t)
+	defer test.Cleanup()
+
+	oldKey := testkey.NewEC256(t)
+	oldPkix, err := x509.MarshalPKIXPublicKey(oldKey.Public())
+	require.NoError(t, err)
+	test.createBundle(t, &common.Bundle{
+		TrustDomainId:  td.IDString(),
+		JwtSigningKeys: []*common.PublicKey{{Kid: "old", PkixBytes: oldPkix, NotAfter: notAfterNext.Unix()}},
+	})
+
+	test.ca.currentJWTKey = activeSlot("current")
+	test.ca.nextJWTKey = &fakeSlot{authorityID: "old", publicKey: oldKey.Public(), notAfter: notAfterNext, status: journal.Status_OLD}
+	oldState := &localauthorityv1.AuthorityState{AuthorityId: "old", ExpiresAt: notAfterNext.Unix()}
+
+	// Only the old authority can be tainted
+	_, err = test.client.TaintJWTAuthority(ctx, &localauthorityv1.TaintJWTAuthorityRequest{AuthorityId: "current"})
+	spiretest.AssertGRPCStatus(t, err, codes.InvalidArgument, `invalid authority ID: only the old authority can be used, found "old"`)
+
+	// Untainted authorities cannot be revoked
+	_, err = test.client.RevokeJWTAuthority(ctx, &localauthorityv1.RevokeJWTAuthorityRequest{AuthorityId: "old"})
+	spiretest.AssertGRPCStatus(t, err, codes.InvalidArgument, "failed to revoke JWT authority: it is not possible to revoke an untainted key")
+
+	taintResp, err := test.client.TaintJWTAuthority(ctx, &localauthorityv1.TaintJWTAuthorityRequest{AuthorityId: "old"})
+	require.NoError(t, err)
+	spiretest.AssertProtoEqual(t, &localauthorityv1.TaintJWTAuthorityResponse{TaintedAuthority: oldState}, taintResp)
+
+	// Tainting twice fails
+	_, err = test.client.TaintJWTAuthority(ctx, &localauthorityv1.TaintJWTAuthorityRequest{AuthorityId: "old"})
+	spiretest.AssertGRPCStatus(t, err, codes.InvalidArgument, "failed to taint JWT authority: key is already tainted")
+
+	revokeResp, err := test.client.RevokeJWTAuthority(ctx, &localauthorityv1.RevokeJWTAuthorityRequest{AuthorityId: "old"})
+	require.NoError(t, err)
+	spiretest.AssertProtoEqual(t, &localauthorityv1.RevokeJWTAuthorityResponse{RevokedAuthority: oldState}, revokeResp)
+
+	bundle, err := test.ds.FetchBundle(ctx, td.IDString())
+	require.NoError(t, err)
+	require.Empty(t, bundle.JwtSigningKeys)
+}
+
+func TestGetX509AuthorityState(t *testing.T) {
+	test := setupServiceTest(t)
+	defer test.Cleanup()
+
+	test.ca.currentX509CA = activeSlot("current")
+	test.ca.nextX509CA = &fakeSlot{authorityID: "next", notAfter: notAfterNext, status: journal.Status_PREPARED}
+
+	resp, err := test.client.GetX509AuthorityState(ctx, &localauthorityv1.GetX509AuthorityStateRequest{})
+	require.NoError(t, err)
+	spiretest.AssertProtoEqual(t, &localauthorityv1.GetX509AuthorityStateResponse{
+		Active:   &localauthorityv1.AuthorityState{AuthorityId: "current", ExpiresAt: notAfterCurrent.Unix()},
+		Prepared: &localauthorityv1.AuthorityState{AuthorityId: "next", ExpiresAt: notAfterNext.Unix()},
+	}, resp)
+}
+
+func TestPrepareAndActivateX509Authority(t *testing.T) {
+	test := setupServiceTest(t)
+	defer test.Cleanup()
+
+	test.ca.currentX509CA = &fakeSlot{}
+	_, err := test.client.PrepareX509Authority(ctx, &localauthorityv1.PrepareX509AuthorityRequest{})
+	spiretest.AssertGRPCStatus(t, err, codes.Unavailable, "server is not ready to prepare an X.509 authority")
+
+	test.ca.currentX509CA = activeSlot("current")
+	test.ca.prepareErr = errors.New("oh no")
+	_, err = test.client.PrepareX509Authority(ctx, &localauthorityv1.PrepareX509AuthorityRequest{})
+	spiretest.AssertGRPCStatus(t, err, codes.Internal, "failed to prepare X.509 authority: oh no")
+
+	test.ca.prepareErr = nil
+	prepareResp, err := test.client.PrepareX509Authority(ctx, &localauthorityv1.PrepareX509AuthorityRequest{})
+	require.NoError(t, err)
+	preparedState := &localauthorityv1.AuthorityState{AuthorityId: "prepared", ExpiresAt: notAfterNext.Unix()}
+	spiretest.AssertProtoEqual(t, &localauthorityv1.PrepareX509AuthorityResponse{PreparedAuthority: preparedState}, prepareResp)
+
+	_, err = test.client.ActivateX509Authority(ctx, &localauthorityv1.ActivateX509AuthorityRequest{AuthorityId: "current"})
+	spiretest.AssertGRPCStatus(t, err, codes.InvalidArgument, `invalid authority ID: only the prepared authority can be used, found "prepared"`)
+
+	activateResp, err := test.client.ActivateX509Authority(ctx, &localauthorityv1.ActivateX509AuthorityRequest{AuthorityId: "prepared"})
+	require.NoError(t, err)
+	spiretest.AssertProtoEqual(t, &localauthorityv1.ActivateX509AuthorityResponse{ActivatedAuthority: preparedState}, activateResp)
+	require.Equal(t, journal.Status_OLD, test.ca.nextX509CA.status)
+	require.Equal(t, "current", test.ca.nextX509CA.authorityID)
+}
+
+func TestTaintAndRevokeX509Authority(t *testing.T) {
+	test := setupServiceTest(t)
+	defer test.Cleanup()
+
+	currentCA, _ := testca.CreateCACertificate(t, nil, nil)
+	oldCA, _ := testca.CreateCACertificate(t, nil, nil)
+	test.createBundle(t, &common.Bundle{
+		TrustDomainId: td.IDString(),
+		RootCas:       []*common.Certificate{{DerBytes: currentCA.Raw}, {DerBytes: oldCA.Raw}},
+	})
+
+	test.ca.currentX509CA = activeSlot("current")
+	test.ca.nextX509CA = &fakeSlot{authorityID: "old", publicKey: oldCA.PublicKey, notAfter: notAfterNext, status: journal.Status_PREPARED}
+
+	// Prepared authorities cannot be tainted
+	_, err := test.client.TaintX509Authority(ctx, &localauthorityv1.TaintX509AuthorityRequest{AuthorityId: "old"})
+	spiretest.AssertGRPCStatus(t, err, codes.InvalidArgument, "invalid authority ID: no old authority found")
+
+	test.ca.nextX509CA.status = journal.Status_OLD
+	oldState := &localauthorityv1.AuthorityState{AuthorityId: "old", ExpiresAt: notAfterNext.Unix()}
+
+	_, err = test.client.RevokeX509Authority(ctx, &localauthorityv1.RevokeX509AuthorityRequest{AuthorityId: "old"})
+	spiretest.AssertGRPCStatus(t, err, codes.InvalidArgument, "failed to revoke X.509 authority: it is not possible to revoke an untainted root CA")
+
+	taintResp, err := test.client.TaintX509Authority(ctx, &localauthorityv1.TaintX509AuthorityRequest{AuthorityId: "old"})
+	require.NoError(t, err)
+	spiretest.AssertProtoEqual(t, &localauthorityv1.TaintX509AuthorityResponse{TaintedAuthority: oldState}, taintResp)
+
+	_, err = test.client.TaintX509Authority(ctx, &localauthorityv1.TaintX509AuthorityRequest{AuthorityId: "old"})
+	spiretest.AssertGRPCStatus(t, err, codes.InvalidArgument, "failed to taint X.509 authority: root CA is already tainted")
+
+	revokeResp, err := test.client.RevokeX509Authority(ctx, &localauthorityv1.RevokeX509AuthorityRequest{AuthorityId: "old"})
+	require.NoError(t, err)
+	spiretest.AssertProtoEqual(t, &localauthorityv1.RevokeX509AuthorityResponse{RevokedAuthority: oldState}, revokeResp)
+
+	bundle, err := test.ds.FetchBundle(ctx, td.IDString())
+	require.NoError(t, err)
+	require.Len(t, bundle.RootCas, 1)
+	require.Equal(t, currentCA.Raw, bundle.RootCas[0].DerBytes)
+}
+
+type serviceTest struct {
+	client  localauthorityv1.LocalAuthorityClient
+	done    func()
+	ds      *fakedatastore.DataStore
+	ca      *fakeCAManager
+	logHook *test.Hook
+}
+
+func (s *serviceTest) Cleanup() {
+	s.done()
+}
+
+func (s *serviceTest) createBundle(t *testing.T, bundle *common.Bundle) {
+	_, err := s.ds.CreateBundle(ctx, bundle)
+	require.NoError(t, err)
+}
+
+func setupServiceTest(t *testing.T) *serviceTest {
+	ds := fakedatastore.New(t)
+	ca := &fakeCAManager{
+		currentJWTKey: &fakeSlot{},
+		nextJWTKey:    &fakeSlot{},
+		currentX509CA: &fakeSlot{},
+		nextX509CA:    &fakeSlot{},
+	}
+	service := localauthority.New(localauthority.Config{
+		TrustDomain: td,
+		DataStore:   ds,
+		CAManager:   ca,
+	})
+
+	log, logHook := test.NewNullLogger()
+	log.Level = logrus.DebugLevel
+	registerFn := func(s *grpc.Server) {
+		localauthority.RegisterService(s, service)
+	}
+
+	ppMiddleware := middleware.Preprocess(func(ctx context.Context, fullMethod string, req interface{}) (context.Context, error) {
+		return rpccontext.WithLogger(ctx, log), nil
+	})
+
+	unaryInterceptor, streamInterceptor := middleware.Interceptors(middleware.Chain(
+		ppMiddleware,
+		// Add audit log with local tracking disabled
+		middleware.WithAuditLog(false),
+	))
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(unaryInterceptor),
+		grpc.StreamInterceptor(streamInterceptor),
+	)
+
+	conn, done := spiretest.NewAPIServerWithMiddleware(t, registerFn, server)
+
+	return &serviceTest{
+		client:  localauthorityv1.NewLocalAuthorityClient(conn),
+		done:    done,
+		ds:      ds,
+		ca:      ca,
+		logHook: logHook,
+	}
+}
+
+func activeSlot(authorityID string) *fakeSlot {
+	return &fakeSlot{
+		authorityID: authorityID,
+		notAfter:    notAfterCurrent,
+		status:      journal.Status_ACTIVE,
+	}
+}
+
+type fakeCAManager struct {
+	currentJWTKey *fakeSlot
+	nextJWTKey    *fakeSlot
+	currentX509CA *fakeSlot
+	nextX509CA    *fakeSlot
+	prepareErr    error
+}
+
+func (m *fakeCAManager) GetCurrentJWTKeySlot() manager.Slot {
+	return m.currentJWTKey
+}
+
+func (m *fakeCAManager) GetNextJWTKeySlot() manager.Slot {
+	return m.nextJWTKey
+}
+
+func (m *fakeCAManager) PrepareJWTKey(context.Context) error {
+	if m.prepareErr != nil {
+		return m.prepareErr
+	}
+	m.nextJWTKey = &fakeSlot{authorityID: "prepared", notAfter: notAfterNext, status: journal.Status_PREPARED}
+	return nil
+}
+
+func (m *fakeCAManager) RotateJWTKeyIf(check func(next manager.Slot) error) (manager.Slot, error) {
+	if err := check(m.nextJWTKey); err != nil {
+		return nil, err
+	}
+	m.currentJWTKey, m.nextJWTKey = m.nextJWTKey, m.currentJWTKey
+	m.currentJWTKey.status = journal.Status_ACTIVE
+	m.nextJWTKey.status = journal.Status_OLD
+	return m.currentJWTKey, nil
+}
+
+func (m *fakeCAManager) GetCurrentX509CASlot() manager.Slot {
+	return m.currentX509CA
+}
+
+func (m *fakeCAManager) GetNextX509CASlot() manager.Slot {
+	return m.nextX509CA
+}
+
+func (m *fakeCAManager) PrepareX509CA(context.Context) error {
+	if m.prepareErr != nil {
+		return m.prepareErr
+	}
+	m.nextX509CA = &fakeSlot{authorityID: "prepared", notAfter: notAfterNext, status: journal.Status_PREPARED}
+	return nil
+}
+
+func (m *fakeCAManager) RotateX509CAIf(check func(next manager.Slot) error) (manager.Slot, error) {
+	if err := check(m.nextX509CA); err != nil {
+		return nil, err
+	}
+	m.currentX509CA, m.nextX509CA = m.nextX509CA, m.currentX509CA
+	m.currentX509CA.status = journal.Status_ACTIVE
+	m.nextX509CA.status = journal.Status_OLD
+	return m.currentX509CA, nil
+}
+
+type fakeSlot struct {
+	manager.Slot
+
+	authorityID string
+	publicKey   crypto.PublicKey
+	notAfter    time.Time
+	status      journal.Status
+}
+
+func (s *fakeSlot) AuthorityID() string {
+	return s.authorityID
+}
+
+func (s *fakeSlot) PublicKey() crypto.PublicKey {
+	return s.publicKey
+}
+
+func (s *fakeSlot) NotAfter() time.Time {
+	return s.notAfter
+}
+
+func (s *fakeSlot) Status() journal.Status {
+	return s.status
+}
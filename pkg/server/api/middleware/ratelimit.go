@@ -18,8 +18,8 @@ import (
 )
 
 const (
-	// gcInterval is the interval at which per-ip limiters are garbage
-	// collected.
+	// gcInterval is the interval at which per-ip and per-caller limiters are
+	// garbage collected.
 	gcInterval = time.Minute
 )
 
@@ -40,11 +40,36 @@ type noopRateLimiter interface {
 	noop()
 }
 
+// outcomeRateLimiter is implemented by rate limiters that can report the
+// outcome of a rate limiting call, so that it can be reflected in metrics.
+type outcomeRateLimiter interface {
+	rateLimit(ctx context.Context, count int) (rateLimitOutcome, error)
+}
+
+// rateLimitOutcome describes how a call to a rate limiter was resolved.
+type rateLimitOutcome int
+
+const (
+	// allowed means the call was allowed without waiting.
+	allowed rateLimitOutcome = iota
+
+	// waited means the call was allowed after waiting on the limiter.
+	waited
+
+	// rejected means the call exceeded the burst size of the limiter.
+	rejected
+
+	// timedOut means the call was canceled, or would have exceeded its
+	// deadline, while waiting on the limiter.
+	timedOut
+)
+
 // rawRateLimiter represents the raw limiter functionality.
 type rawRateLimiter interface {
 	WaitN(ctx context.Context, count int) error
 	Limit() rate.Limit
 	Burst() int
+	Tokens() float64
 }
 
 // NoLimit returns a rate limiter that does not rate limit. It is used to
@@ -70,7 +95,23 @@ func PerCallLimit(limit int) api.RateLimiter {
 // to a method. It can be shared across methods to enforce per-ip limits for
 // a group of methods.
 func PerIPLimit(limit int) api.RateLimiter {
-	return newPerIPLimiter(limit)
+	return PerIPLimitWithBurst(limit, limit)
+}
+
+// PerIPLimitWithBurst is like PerIPLimit but allows bursts of up to burst
+// calls.
+func PerIPLimitWithBurst(limit, burst int) api.RateLimiter {
+	return newKeyedLimiter(limit, burst, callerIPKey)
+}
+
+// PerCallerLimit returns a rate limiter that imposes a per-caller limit on
+// calls to a method, allowing bursts of up to burst calls. Callers are
+// identified by their SPIFFE ID, so that callers sharing an IP address (e.g.
+// behind NAT or a load balancer) are limited separately. Callers without a
+// SPIFFE ID are limited per-ip. It can be shared across methods to enforce
+// per-caller limits for a group of methods.
+func PerCallerLimit(limit, burst int) api.RateLimiter {
+	return newKeyedLimiter(limit, burst, callerIDKey)
 }
 
 // WithRateLimits returns a middleware that performs rate limiting for the
@@ -120,11 +161,40 @@ func newPerCallLimiter(limit int) *perCallLimiter {
 }
 
 func (lim *perCallLimiter) RateLimit(ctx context.Context, count int) error {
+	_, err := lim.rateLimit(ctx, count)
+	return err
+}
+
+func (lim *perCallLimiter) rateLimit(ctx context.Context, count int) (rateLimitOutcome, error) {
 	return waitN(ctx, lim.limiter, count)
 }
 
-type perIPLimiter struct {
+// limiterKeyFunc returns the key identifying the caller for rate limiting
+// purposes. Callers that are not identified are not limited.
+type limiterKeyFunc func(ctx context.Context) (string, bool)
+
+func callerIPKey(ctx context.Context) (string, bool) {
+	tcpAddr, ok := rpccontext.CallerAddr(ctx).(*net.TCPAddr)
+	if !ok {
+		// Calls not via TCP/IP aren't limited
+		return "", false
+	}
+	return tcpAddr.IP.String(), true
+}
+
+func callerIDKey(ctx context.Context) (string, bool) {
+	if id, ok := rpccontext.CallerID(ctx); ok {
+		return id.String(), true
+	}
+	return callerIPKey(ctx)
+}
+
+// keyedLimiter imposes a limit per caller, where callers are identified by
+// the key returned by the key function.
+type keyedLimiter struct {
 	limit int
+	burst int
+	key   limiterKeyFunc
 
 	mtx sync.RWMutex
 
@@ -139,51 +209,59 @@ type perIPLimiter struct {
 	lastGC time.Time
 }
 
-func newPerIPLimiter(limit int) *perIPLimiter {
-	return &perIPLimiter{limit: limit,
+func newKeyedLimiter(limit, burst int, key limiterKeyFunc) *keyedLimiter {
+	return &keyedLimiter{
+		limit:   limit,
+		burst:   burst,
+		key:     key,
 		current: make(map[string]rawRateLimiter),
 		lastGC:  clk.Now(),
 	}
 }
 
-func (lim *perIPLimiter) RateLimit(ctx context.Context, count int) error {
-	tcpAddr, ok := rpccontext.CallerAddr(ctx).(*net.TCPAddr)
+func (lim *keyedLimiter) RateLimit(ctx context.Context, count int) error {
+	_, err := lim.rateLimit(ctx, count)
+	return err
+}
+
+func (lim *keyedLimiter) rateLimit(ctx context.Context, count int) (rateLimitOutcome, error) {
+	key, ok := lim.key(ctx)
 	if !ok {
-		// Calls not via TCP/IP aren't limited
-		return nil
+		return allowed, nil
 	}
-	limiter := lim.getLimiter(tcpAddr.IP.String())
+	limiter := lim.getLimiter(key)
 	return waitN(ctx, limiter, count)
 }
 
-func (lim *perIPLimiter) getLimiter(ip string) rawRateLimiter {
+func (lim *keyedLimiter) getLimiter(key string) rawRateLimiter {
 	lim.mtx.RLock()
-	limiter, ok := lim.current[ip]
+	limiter, ok := lim.current[key]
 	if ok {
 		lim.mtx.RUnlock()
 		return limiter
 	}
 	lim.mtx.RUnlock()
 
-	// A limiter does not exist for that address.
+	// A limiter does not exist for that caller.
 	lim.mtx.Lock()
 	defer lim.mtx.Unlock()
 
-	// Check the "current" entries in case another goroutine raced on this IP.
-	if limiter, ok = lim.current[ip]; ok {
+	// Check the "current" entries in case another goroutine raced on this
+	// caller.
+	if limiter, ok = lim.current[key]; ok {
 		return limiter
 	}
 
 	// Then check the "previous" entries to see if a limiter exists for this
-	// IP as of the last GC. If so, move it to current and return it.
-	if limiter, ok = lim.previous[ip]; ok {
-		lim.current[ip] = limiter
-		delete(lim.previous, ip)
+	// caller as of the last GC. If so, move it to current and return it.
+	if limiter, ok = lim.previous[key]; ok {
+		lim.current[key] = limiter
+		delete(lim.previous, key)
 		return limiter
 	}
 
-	// There is no limiter for this IP. Before we create one, we should see
-	// if we need to do GC.
+	// There is no limiter for this caller. Before we create one, we should
+	// see if we need to do GC.
 	now := clk.Now()
 	if now.Sub(lim.lastGC) >= gcInterval {
 		lim.previous = lim.current
@@ -191,8 +269,8 @@ func (lim *perIPLimiter) getLimiter(ip string) rawRateLimiter {
 		lim.lastGC = now
 	}
 
-	limiter = newRawRateLimiter(rate.Limit(lim.limit), lim.limit)
-	lim.current[ip] = limiter
+	limiter = newRawRateLimiter(rate.Limit(lim.limit), lim.burst)
+	lim.current[key] = limiter
 	return limiter
 }
 
@@ -207,7 +285,7 @@ func (i rateLimitsMiddleware) Preprocess(ctx context.Context, fullMethod string,
 		middleware.LogMisconfiguration(ctx, "Rate limiting misconfigured; this is a bug")
 		return nil, status.Errorf(codes.Internal, "rate limiting misconfigured for %q", fullMethod)
 	}
-	return rpccontext.WithRateLimiter(ctx, &rateLimiterWrapper{rateLimiter: rateLimiter, metrics: i.metrics}), nil
+	return rpccontext.WithRateLimiter(ctx, &rateLimiterWrapper{rateLimiter: rateLimiter, metrics: i.metrics, method: fullMethod}), nil
 }
 
 func (i rateLimitsMiddleware) Postprocess(ctx context.Context, _ string, handlerInvoked bool, rpcErr error) {
@@ -267,16 +345,40 @@ type rateLimiterWrapper struct {
 	rateLimiter api.RateLimiter
 	used        bool
 	metrics     telemetry.Metrics
+	method      string
 }
 
 func (w *rateLimiterWrapper) RateLimit(ctx context.Context, count int) (err error) {
 	w.used = true
 	if _, noop := w.rateLimiter.(noopRateLimiter); !noop {
-		counter := telemetry.StartCall(w.metrics, "rateLimit", getNames(ctx)...)
+		counter := telemetry.StartCall(w.metrics, telemetry.RateLimit, getNames(ctx)...)
 		defer counter.Done(&err)
 	}
 
-	return w.rateLimiter.RateLimit(ctx, count)
+	limiter, ok := w.rateLimiter.(outcomeRateLimiter)
+	if !ok {
+		return w.rateLimiter.RateLimit(ctx, count)
+	}
+
+	outcome, err := limiter.rateLimit(ctx, count)
+
+	// Count the calls delayed, rejected or timed out by the limiter
+	// separately so that throttling can be monitored per method.
+	var key []string
+	switch outcome {
+	case waited:
+		key = []string{telemetry.RateLimit, telemetry.Wait}
+	case rejected:
+		key = []string{telemetry.RateLimit, telemetry.Rejected}
+	case timedOut:
+		key = []string{telemetry.RateLimit, telemetry.Timeout}
+	default:
+		return err
+	}
+	w.metrics.IncrCounterWithLabels(key, 1, []telemetry.Label{
+		{Name: telemetry.Method, Value: w.method},
+	})
+	return err
 }
 
 func (w *rateLimiterWrapper) Used() bool {
@@ -291,21 +393,28 @@ func getNames(ctx context.Context) []string {
 	return []string{}
 }
 
-func waitN(ctx context.Context, limiter rawRateLimiter, count int) (err error) {
+func waitN(ctx context.Context, limiter rawRateLimiter, count int) (rateLimitOutcome, error) {
 	// limiter.WaitN already provides this check but the error returned is not
 	// strongly typed and is a little messy. Lifting this check so we can
 	// provide a clean error message.
 	if count > limiter.Burst() && limiter.Limit() != rate.Inf {
-		return status.Errorf(codes.ResourceExhausted, "rate (%d) exceeds burst size (%d)", count, limiter.Burst())
+		return rejected, status.Errorf(codes.ResourceExhausted, "rate (%d) exceeds burst size (%d)", count, limiter.Burst())
 	}
 
-	err = limiter.WaitN(ctx, count)
+	// The tokens are sampled before waiting, so a concurrent call may cause
+	// a wait to go unnoticed (or vice versa). That is fine for metrics.
+	mustWait := limiter.Tokens() < float64(count)
+
+	err := limiter.WaitN(ctx, count)
 	switch {
+	case err == nil && mustWait:
+		return waited, nil
 	case err == nil:
-		return nil
+		return allowed, nil
 	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
-		return ctx.Err()
+		return timedOut, ctx.Err()
 	default:
-		return status.Error(codes.ResourceExhausted, err.Error())
+		// The only other failure is the wait exceeding the context deadline.
+		return timedOut, status.Error(codes.ResourceExhausted, err.Error())
 	}
 }
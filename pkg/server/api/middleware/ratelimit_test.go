@@ -8,6 +8,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/common/api/middleware"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/server/api"
@@ -141,6 +142,58 @@ func TestPerIPLimitGC(t *testing.T) {
 	require.Equal(t, 5, limiters.Count)
 }
 
+func TestPerIPLimitWithBurst(t *testing.T) {
+	limiters := NewFakeLimiters()
+
+	m := PerIPLimitWithBurst(1, 5)
+
+	// Within burst size
+	require.NoError(t, m.RateLimit(tcpCallerContext("1.1.1.1"), 5))
+
+	// Exceeds burst size
+	err := m.RateLimit(tcpCallerContext("1.1.1.1"), 6)
+	spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate (6) exceeds burst size (5)")
+
+	assert.Equal(t, 1, limiters.Count)
+	assert.Equal(t, []WaitNEvent{
+		{ID: 1, Count: 5},
+	}, limiters.WaitNEvents)
+}
+
+func TestPerCallerLimit(t *testing.T) {
+	limiters := NewFakeLimiters()
+
+	m := PerCallerLimit(10, 20)
+
+	// Does not rate limit non-TCP/IP callers without an ID
+	require.NoError(t, m.RateLimit(unixCallerContext(), 21))
+
+	// Callers with different IDs behind the same IP are limited separately
+	require.NoError(t, m.RateLimit(callerIDContext("1.1.1.1", "spiffe://example.org/agent/1"), 20))
+	require.NoError(t, m.RateLimit(callerIDContext("1.1.1.1", "spiffe://example.org/agent/2"), 20))
+
+	// The same caller ID shares the limiter, whatever the IP
+	require.NoError(t, m.RateLimit(callerIDContext("2.2.2.2", "spiffe://example.org/agent/1"), 1))
+
+	// Callers without an ID are limited per-ip
+	require.NoError(t, m.RateLimit(tcpCallerContext("1.1.1.1"), 1))
+	require.NoError(t, m.RateLimit(tcpCallerContext("1.1.1.1"), 2))
+
+	// Exceeds burst size
+	err := m.RateLimit(callerIDContext("1.1.1.1", "spiffe://example.org/agent/1"), 21)
+	spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate (21) exceeds burst size (20)")
+
+	// There should be three rate limiters; agent/1, agent/2 and 1.1.1.1
+	assert.Equal(t, 3, limiters.Count)
+	assert.Equal(t, []WaitNEvent{
+		{ID: 1, Count: 20},
+		{ID: 2, Count: 20},
+		{ID: 1, Count: 1},
+		{ID: 3, Count: 1},
+		{ID: 3, Count: 2},
+	}, limiters.WaitNEvents)
+}
+
 func TestRateLimits(t *testing.T) {
 	for _, tt := range []struct {
 		name            string
@@ -250,6 +303,12 @@ func TestRateLimits(t *testing.T) {
 			expectCode:     codes.ResourceExhausted,
 			expectMsg:      "rate (3) exceeds burst size (2)",
 			expectedMetrics: []fakemetrics.MetricItem{
+				{
+					Type:   fakemetrics.IncrCounterWithLabelsType,
+					Key:    []string{"rateLimit", "rejected"},
+					Val:    1,
+					Labels: []telemetry.Label{{Name: "method", Value: "_fake_Service_WithLimit"}},
+				},
 				{
 					Type:   fakemetrics.IncrCounterWithLabelsType,
 					Key:    []string{"rateLimit"},
@@ -315,6 +374,104 @@ func TestRateLimits(t *testing.T) {
 	}
 }
 
+func TestRateLimitOutcomeMetrics(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		count      int
+		tokens     float64
+		waitNErr   error
+		expectCode codes.Code
+		expectKey  []string
+	}{
+		{
+			name:       "allowed",
+			count:      1,
+			tokens:     2,
+			expectCode: codes.OK,
+		},
+		{
+			name:       "waited",
+			count:      2,
+			tokens:     1,
+			expectCode: codes.OK,
+			expectKey:  []string{"rateLimit", "wait"},
+		},
+		{
+			name:       "rejected",
+			count:      3,
+			tokens:     2,
+			expectCode: codes.ResourceExhausted,
+			expectKey:  []string{"rateLimit", "rejected"},
+		},
+		{
+			name:       "canceled while waiting",
+			count:      2,
+			tokens:     1,
+			waitNErr:   context.Canceled,
+			expectCode: codes.Canceled,
+			expectKey:  []string{"rateLimit", "timeout"},
+		},
+		{
+			name:       "wait would exceed deadline",
+			count:      2,
+			tokens:     1,
+			waitNErr:   errors.New("rate: Wait(n=2) would exceed context deadline"),
+			expectCode: codes.ResourceExhausted,
+			expectKey:  []string{"rateLimit", "timeout"},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			limiter := &fakeLimiter{
+				waitN: func(context.Context, int, int) error {
+					if errors.Is(tt.waitNErr, context.Canceled) {
+						cancel()
+					}
+					return tt.waitNErr
+				},
+				limit:  1,
+				burst:  2,
+				tokens: tt.tokens,
+			}
+			metrics := fakemetrics.New()
+			w := &rateLimiterWrapper{
+				rateLimiter: &perCallLimiter{limiter: limiter},
+				metrics:     metrics,
+				method:      "_fake_Service_WithLimit",
+			}
+
+			err := w.RateLimit(ctx, tt.count)
+			code := status.Code(err)
+			if errors.Is(err, context.Canceled) {
+				code = codes.Canceled
+			}
+			assert.Equal(t, tt.expectCode, code)
+
+			var counters []fakemetrics.MetricItem
+			for _, metric := range metrics.AllMetrics() {
+				if len(metric.Key) == 2 && metric.Key[0] == "rateLimit" && metric.Key[1] != "elapsed_time" {
+					counters = append(counters, metric)
+				}
+			}
+			if tt.expectKey == nil {
+				assert.Empty(t, counters)
+				return
+			}
+			assert.Equal(t, []fakemetrics.MetricItem{
+				{
+					Type:   fakemetrics.IncrCounterWithLabelsType,
+					Key:    tt.expectKey,
+					Val:    1,
+					Labels: []telemetry.Label{{Name: "method", Value: "_fake_Service_WithLimit"}},
+				},
+			}, counters)
+		})
+	}
+}
+
 type WaitNEvent struct {
 	ID    int
 	Count int
@@ -334,10 +491,11 @@ func NewFakeLimiters() *FakeLimiters {
 func (ls *FakeLimiters) newRawRateLimiter(limit rate.Limit, burst int) rawRateLimiter {
 	ls.Count++
 	return &fakeLimiter{
-		id:    ls.Count,
-		waitN: ls.waitN,
-		limit: limit,
-		burst: burst,
+		id:     ls.Count,
+		waitN:  ls.waitN,
+		limit:  limit,
+		burst:  burst,
+		tokens: float64(burst),
 	}
 }
 
@@ -350,10 +508,11 @@ func (ls *FakeLimiters) waitN(_ context.Context, id, count int) error {
 }
 
 type fakeLimiter struct {
-	id    int
-	waitN func(ctx context.Context, id, count int) error
-	limit rate.Limit
-	burst int
+	id     int
+	waitN  func(ctx context.Context, id, count int) error
+	limit  rate.Limit
+	burst  int
+	tokens float64
 }
 
 func (l *fakeLimiter) WaitN(ctx context.Context, count int) error {
@@ -378,6 +537,10 @@ func (l *fakeLimiter) Burst() int {
 	return l.burst
 }
 
+func (l *fakeLimiter) Tokens() float64 {
+	return l.tokens
+}
+
 func unixCallerContext() context.Context {
 	return rpccontext.WithCallerAddr(context.Background(), &net.UnixAddr{
 		Net:  "unix",
@@ -391,6 +554,10 @@ func tcpCallerContext(ip string) context.Context {
 	})
 }
 
+func callerIDContext(ip, id string) context.Context {
+	return rpccontext.WithCallerID(tcpCallerContext(ip), spiffeid.RequireFromString(id))
+}
+
 func setupClock(t *testing.T) (*clock.Mock, func()) {
 	mockClk := clock.NewMock(t)
 	oldClk := clk
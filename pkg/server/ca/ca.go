@@ -81,6 +81,10 @@ type WorkloadX509SVIDParams struct {
 
 	// Subject of the SVID. Default subject is used if it is empty.
 	Subject pkix.Name
+
+	// Hint of the registration entry the SVID is signed for, if any. It is
+	// made available to the credential composers.
+	Hint string
 }
 
 // WorkloadJWTSVIDParams are parameters relevant to workload JWT-SVID creation
@@ -94,6 +98,10 @@ type WorkloadJWTSVIDParams struct {
 
 	// Audience is used for audience claims
 	Audience []string
+
+	// Hint of the registration entry the SVID is signed for, if any. It is
+	// made available to the credential composers.
+	Hint string
 }
 
 type X509CA struct {
@@ -283,6 +291,7 @@ func (ca *CA) SignWorkloadX509SVID(ctx context.Context, params WorkloadX509SVIDP
 		DNSNames:    params.DNSNames,
 		TTL:         params.TTL,
 		Subject:     params.Subject,
+		Hint:        params.Hint,
 	})
 	if err != nil {
 		return nil, err
@@ -311,6 +320,7 @@ func (ca *CA) SignWorkloadJWTSVID(ctx context.Context, params WorkloadJWTSVIDPar
 		Audience:      params.Audience,
 		TTL:           params.TTL,
 		ExpirationCap: jwtKey.NotAfter,
+		Hint:          params.Hint,
 	})
 	if err != nil {
 		return "", err
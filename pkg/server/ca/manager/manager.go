@@ -248,8 +248,27 @@ func (m *Manager) RotateX509CA() {
 	m.x509CAMutex.Lock()
 	defer m.x509CAMutex.Unlock()
 
+	m.rotateX509CA()
+}
+
+// RotateX509CAIf rotates the X509CA only if the next X509CA slot passes the
+// given check. The check and the rotation happen atomically, so concurrent
+// callers checking the same slot cannot both rotate. The new current slot is
+// returned.
+func (m *Manager) RotateX509CAIf(check func(next Slot) error) (Slot, error) {
+	m.x509CAMutex.Lock()
+	defer m.x509CAMutex.Unlock()
+
+	if err := check(m.nextX509CA); err != nil {
+		return nil, err
+	}
+	m.rotateX509CA()
+	return m.currentX509CA, nil
+}
+
+func (m *Manager) rotateX509CA() {
 	m.currentX509CA, m.nextX509CA = m.nextX509CA, m.currentX509CA
-	m.nextX509CA.Reset()
+	m.nextX509CA.MarkAsOld()
 	if err := m.journal.UpdateX509CAStatus(m.nextX509CA.issuedAt, journal.Status_OLD); err != nil {
 		m.c.Log.WithError(err).Error("Failed to update status on X509CA journal entry")
 	}
@@ -339,8 +358,27 @@ func (m *Manager) RotateJWTKey() {
 	m.jwtKeyMutex.Lock()
 	defer m.jwtKeyMutex.Unlock()
 
+	m.rotateJWTKey()
+}
+
+// RotateJWTKeyIf rotates the JWT key only if the next JWT key slot passes the
+// given check. The check and the rotation happen atomically, so concurrent
+// callers checking the same slot cannot both rotate. The new current slot is
+// returned.
+func (m *Manager) RotateJWTKeyIf(check func(next Slot) error) (Slot, error) {
+	m.jwtKeyMutex.Lock()
+	defer m.jwtKeyMutex.Unlock()
+
+	if err := check(m.nextJWTKey); err != nil {
+		return nil, err
+	}
+	m.rotateJWTKey()
+	return m.currentJWTKey, nil
+}
+
+func (m *Manager) rotateJWTKey() {
 	m.currentJWTKey, m.nextJWTKey = m.nextJWTKey, m.currentJWTKey
-	m.nextJWTKey.Reset()
+	m.nextJWTKey.MarkAsOld()
 
 	if err := m.journal.UpdateJWTKeyStatus(m.nextJWTKey.issuedAt, journal.Status_OLD); err != nil {
 		m.c.Log.WithError(err).Error("Failed to update status on JWTKey journal entry")
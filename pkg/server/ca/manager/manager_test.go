@@ -385,12 +385,13 @@ func TestX509CARotation(t *testing.T) {
 	test.waitForBundleUpdatedNotification(ctx, notifyCh)
 
 	// Rotate "next" should become "current" and
-	// "next" should be reset.
+	// "next" should hold the old X509CA.
 	test.m.RotateX509CA()
 	test.requireX509CAEqual(t, second, test.currentX509CA())
 	require.Equal(t, journal.Status_ACTIVE, test.currentX509CAStatus())
-	assert.Nil(t, test.nextX509CA())
+	test.requireX509CAEqual(t, first, test.nextX509CA())
 	require.Equal(t, journal.Status_OLD, test.nextX509CAStatus())
+	require.True(t, test.m.GetNextX509CASlot().IsEmpty())
 
 	// Prepare new X509CA. the current X509CA should stay
 	// the same but the next X509CA should have been prepared and added to
@@ -407,14 +408,74 @@ func TestX509CARotation(t *testing.T) {
 	test.waitForBundleUpdatedNotification(ctx, notifyCh)
 
 	// Rotate again, "next" should become "current" and
-	// "next" should be reset.
+	// "next" should hold the old X509CA.
 	test.m.RotateX509CA()
 	test.requireX509CAEqual(t, third, test.currentX509CA())
 	require.Equal(t, journal.Status_ACTIVE, test.currentX509CAStatus())
-	assert.Nil(t, test.nextX509CA())
+	test.requireX509CAEqual(t, second, test.nextX509CA())
 	require.Equal(t, journal.Status_OLD, test.nextX509CAStatus())
 }
 
+func TestRotateIfIsAtomic(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	test := setupTest(t)
+	test.initAndActivateSelfSignedManager(ctx)
+
+	require.NoError(t, test.m.PrepareX509CA(ctx))
+	require.NoError(t, test.m.PrepareJWTKey(ctx))
+	preparedX509CA := test.m.GetNextX509CASlot().AuthorityID()
+	preparedJWTKey := test.m.GetNextJWTKeySlot().AuthorityID()
+
+	isPrepared := func(authorityID string) func(next Slot) error {
+		return func(next Slot) error {
+			if next.Status() != journal.Status_PREPARED || next.AuthorityID() != authorityID {
+				return errors.New("not prepared")
+			}
+			return nil
+		}
+	}
+
+	// Concurrent rotations of the same prepared authority only rotate once
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	x509CARotations, jwtKeyRotations := 0, 0
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := test.m.RotateX509CAIf(isPrepared(preparedX509CA)); err == nil {
+				mu.Lock()
+				x509CARotations++
+				mu.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := test.m.RotateJWTKeyIf(isPrepared(preparedJWTKey)); err == nil {
+				mu.Lock()
+				jwtKeyRotations++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 1, x509CARotations)
+	require.Equal(t, 1, jwtKeyRotations)
+	require.Equal(t, preparedX509CA, test.m.GetCurrentX509CASlot().AuthorityID())
+	require.Equal(t, journal.Status_OLD, test.m.GetNextX509CASlot().Status())
+	require.Equal(t, preparedJWTKey, test.m.GetCurrentJWTKeySlot().AuthorityID())
+	require.Equal(t, journal.Status_OLD, test.m.GetNextJWTKeySlot().Status())
+
+	// A failed check leaves the slots untouched
+	current, err := test.m.RotateX509CAIf(func(Slot) error { return errors.New("oh no") })
+	require.EqualError(t, err, "oh no")
+	require.Nil(t, current)
+	require.Equal(t, preparedX509CA, test.m.GetCurrentX509CASlot().AuthorityID())
+}
+
 func TestX509CARotationMetric(t *testing.T) {
 	ctx := context.Background()
 	test := setupTest(t)
@@ -474,12 +535,13 @@ func TestJWTKeyRotation(t *testing.T) {
 	test.waitForBundleUpdatedNotification(ctx, notifyCh)
 
 	// rotate, "next" should become "current" and
-	// "next" should be reset.
+	// "next" should hold the old JWTKey.
 	test.m.RotateJWTKey()
 	test.requireJWTKeyEqual(t, second, test.currentJWTKey())
 	require.Equal(t, journal.Status_ACTIVE, test.currentJWTKeyStatus())
-	assert.Nil(t, test.nextJWTKey())
+	test.requireJWTKeyEqual(t, first, test.nextJWTKey())
 	require.Equal(t, journal.Status_OLD, test.nextJWTKeyStatus())
+	require.True(t, test.m.GetNextJWTKeySlot().IsEmpty())
 
 	// Prepare next, the current JWTKey should stay
 	// the same but the next JWTKey should have been prepared and added to
@@ -496,11 +558,11 @@ func TestJWTKeyRotation(t *testing.T) {
 	test.waitForBundleUpdatedNotification(ctx, notifyCh)
 
 	// rotate again. "next" should become "current" and
-	// "next" should be reset.
+	// "next" should hold the old JWTKey.
 	test.m.RotateJWTKey()
 	test.requireJWTKeyEqual(t, third, test.currentJWTKey())
 	require.Equal(t, journal.Status_ACTIVE, test.currentJWTKeyStatus())
-	assert.Nil(t, test.nextJWTKey())
+	test.requireJWTKeyEqual(t, second, test.nextJWTKey())
 	require.Equal(t, journal.Status_OLD, test.nextJWTKeyStatus())
 }
 
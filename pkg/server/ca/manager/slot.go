@@ -14,6 +14,7 @@ import (
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/common/cryptoutil"
 	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/common/x509util"
 	"github.com/spiffe/spire/pkg/server/ca"
 	"github.com/spiffe/spire/pkg/server/catalog"
 	"github.com/spiffe/spire/proto/private/server/journal"
@@ -39,6 +40,9 @@ type Slot interface {
 	ShouldPrepareNext(now time.Time) bool
 	ShouldActivateNext(now time.Time) bool
 	Status() journal.Status
+	AuthorityID() string
+	PublicKey() crypto.PublicKey
+	NotAfter() time.Time
 }
 
 type SlotLoader struct {
@@ -529,6 +533,12 @@ func (s *X509CASlot) Reset() {
 	s.status = journal.Status_OLD
 }
 
+// MarkAsOld marks the slot as old, keeping the X509CA around so it can still
+// be tainted or revoked.
+func (s *X509CASlot) MarkAsOld() {
+	s.status = journal.Status_OLD
+}
+
 func (s *X509CASlot) ShouldPrepareNext(now time.Time) bool {
 	return s.x509CA != nil && now.After(preparationThreshold(s.issuedAt, s.x509CA.Certificate.NotAfter))
 }
@@ -541,6 +551,29 @@ func (s *X509CASlot) Status() journal.Status {
 	return s.status
 }
 
+// AuthorityID returns the subject key ID of the X509CA, encoded as a
+// hexadecimal string, or an empty string if the slot holds no X509CA.
+func (s *X509CASlot) AuthorityID() string {
+	if s.x509CA == nil {
+		return ""
+	}
+	return x509util.SubjectKeyIDToString(s.x509CA.Certificate.SubjectKeyId)
+}
+
+func (s *X509CASlot) PublicKey() crypto.PublicKey {
+	if s.x509CA == nil {
+		return nil
+	}
+	return s.x509CA.Certificate.PublicKey
+}
+
+func (s *X509CASlot) NotAfter() time.Time {
+	if s.x509CA == nil {
+		return time.Time{}
+	}
+	return s.x509CA.Certificate.NotAfter
+}
+
 type JwtKeySlot struct {
 	id       string
 	issuedAt time.Time
@@ -571,6 +604,35 @@ func (s *JwtKeySlot) Reset() {
 	s.status = journal.Status_OLD
 }
 
+// MarkAsOld marks the slot as old, keeping the JWT key around so it can still
+// be tainted or revoked.
+func (s *JwtKeySlot) MarkAsOld() {
+	s.status = journal.Status_OLD
+}
+
+// AuthorityID returns the key ID of the JWT key, or an empty string if the
+// slot holds no JWT key.
+func (s *JwtKeySlot) AuthorityID() string {
+	if s.jwtKey == nil {
+		return ""
+	}
+	return s.jwtKey.Kid
+}
+
+func (s *JwtKeySlot) PublicKey() crypto.PublicKey {
+	if s.jwtKey == nil {
+		return nil
+	}
+	return s.jwtKey.Signer.Public()
+}
+
+func (s *JwtKeySlot) NotAfter() time.Time {
+	if s.jwtKey == nil {
+		return time.Time{}
+	}
+	return s.jwtKey.NotAfter
+}
+
 func (s *JwtKeySlot) ShouldPrepareNext(now time.Time) bool {
 	return s.jwtKey == nil || now.After(preparationThreshold(s.issuedAt, s.jwtKey.NotAfter))
 }
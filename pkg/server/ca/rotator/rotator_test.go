@@ -2,6 +2,7 @@ package rotator
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"testing"
 	"time"
@@ -577,6 +578,18 @@ func (s *fakeSlot) Status() journal.Status {
 	return s.status
 }
 
+func (s *fakeSlot) AuthorityID() string {
+	return ""
+}
+
+func (s *fakeSlot) PublicKey() crypto.PublicKey {
+	return nil
+}
+
+func (s *fakeSlot) NotAfter() time.Time {
+	return time.Time{}
+}
+
 func createSlot(id string, now time.Time, hasValue bool) *fakeSlot {
 	return &fakeSlot{
 		keyID:           id,
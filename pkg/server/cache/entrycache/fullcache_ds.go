@@ -21,6 +21,11 @@ func BuildFromDataStore(ctx context.Context, ds datastore.DataStore) (*FullEntry
 	return Build(ctx, makeEntryIteratorDS(ds), makeAgentIteratorDS(ds))
 }
 
+// BuildMutableFromDataStore builds a MutableEntryCache using the provided datastore as the data source
+func BuildMutableFromDataStore(ctx context.Context, ds datastore.DataStore) (*MutableEntryCache, error) {
+	return BuildMutable(ctx, makeEntryIteratorDS(ds), makeAgentIteratorDS(ds))
+}
+
 type entryIteratorDS struct {
 	ds      datastore.DataStore
 	entries []*types.Entry
@@ -0,0 +1,222 @@
+package entrycache
+
+import (
+	"context"
+	"sync"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+)
+
+var _ Cache = (*MutableEntryCache)(nil)
+
+// MutableEntryCache is an in-memory index of registration entries and Agent
+// selectors. Unlike FullEntryCache, it is safe for concurrent use and can be
+// updated in place as individual entries and agents change, which avoids
+// having to rebuild the whole index to pick up a change.
+type MutableEntryCache struct {
+	mu sync.RWMutex
+
+	// entries holds all entries (including aliases) by entry ID
+	entries map[string]*types.Entry
+	// entriesByParent holds the entry IDs of all non-alias entries by parent ID
+	entriesByParent map[spiffeID]stringSet
+	// aliases holds the selectors of all alias entries (i.e. entries parented
+	// to the server) by entry ID
+	aliases map[string]selectorSet
+	// aliasesBySelector holds the alias entry IDs by selector
+	aliasesBySelector map[Selector]stringSet
+	// agents holds the agent selectors by agent ID
+	agents map[spiffeID]selectorSet
+}
+
+// NewMutableEntryCache returns an empty MutableEntryCache.
+func NewMutableEntryCache() *MutableEntryCache {
+	return &MutableEntryCache{
+		entries:           make(map[string]*types.Entry),
+		entriesByParent:   make(map[spiffeID]stringSet),
+		aliases:           make(map[string]selectorSet),
+		aliasesBySelector: make(map[Selector]stringSet),
+		agents:            make(map[spiffeID]selectorSet),
+	}
+}
+
+// BuildMutable queries the data source for all registration entries and Agent
+// selectors and builds a MutableEntryCache out of them.
+func BuildMutable(ctx context.Context, entryIter EntryIterator, agentIter AgentIterator) (*MutableEntryCache, error) {
+	c := NewMutableEntryCache()
+	for entryIter.Next(ctx) {
+		c.updateEntry(entryIter.Entry())
+	}
+	if err := entryIter.Err(); err != nil {
+		return nil, err
+	}
+
+	for agentIter.Next(ctx) {
+		agent := agentIter.Agent()
+		c.agents[spiffeIDFromID(agent.ID)] = selectorSetFromProto(agent.Selectors)
+	}
+	if err := agentIter.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// GetAuthorizedEntries gets all authorized registration entries for a given Agent SPIFFE ID.
+func (c *MutableEntryCache) GetAuthorizedEntries(agentID spiffeid.ID) []*types.Entry {
+	seen := allocSeenSet()
+	defer freeSeenSet(seen)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return cloneEntries(c.getAuthorizedEntries(spiffeIDFromID(agentID), seen))
+}
+
+// UpdateEntry adds the entry to the cache, replacing the previous version of
+// the entry with the same ID, if any.
+func (c *MutableEntryCache) UpdateEntry(entry *types.Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.updateEntry(entry)
+}
+
+// RemoveEntry removes the entry with the given ID from the cache. It is a
+// no-op if the entry is not in the cache.
+func (c *MutableEntryCache) RemoveEntry(entryID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeEntry(entryID)
+}
+
+// UpdateAgent sets the selectors for the given agent, replacing the previous
+// selectors, if any.
+func (c *MutableEntryCache) UpdateAgent(agentID spiffeid.ID, selectors []*types.Selector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.agents[spiffeIDFromID(agentID)] = selectorSetFromProto(selectors)
+}
+
+// RemoveAgent removes the given agent from the cache. It is a no-op if the
+// agent is not in the cache.
+func (c *MutableEntryCache) RemoveAgent(agentID spiffeid.ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.agents, spiffeIDFromID(agentID))
+}
+
+// Stats returns the number of entries, alias entries and agents in the cache.
+func (c *MutableEntryCache) Stats() (entries, aliases, agents int) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.entries), len(c.aliases), len(c.agents)
+}
+
+func (c *MutableEntryCache) updateEntry(entry *types.Entry) {
+	c.removeEntry(entry.Id)
+	c.entries[entry.Id] = entry
+
+	parentID := spiffeIDFromProto(entry.ParentId)
+	if parentID.Path == "/spire/server" {
+		selectors := selectorSetFromProto(entry.Selectors)
+		c.aliases[entry.Id] = selectors
+		for selector := range selectors {
+			aliasIDs, ok := c.aliasesBySelector[selector]
+			if !ok {
+				aliasIDs = make(stringSet)
+				c.aliasesBySelector[selector] = aliasIDs
+			}
+			aliasIDs[entry.Id] = struct{}{}
+		}
+		return
+	}
+
+	entryIDs, ok := c.entriesByParent[parentID]
+	if !ok {
+		entryIDs = make(stringSet)
+		c.entriesByParent[parentID] = entryIDs
+	}
+	entryIDs[entry.Id] = struct{}{}
+}
+
+func (c *MutableEntryCache) removeEntry(entryID string) {
+	entry, ok := c.entries[entryID]
+	if !ok {
+		return
+	}
+	delete(c.entries, entryID)
+
+	if selectors, ok := c.aliases[entryID]; ok {
+		delete(c.aliases, entryID)
+		for selector := range selectors {
+			aliasIDs := c.aliasesBySelector[selector]
+			delete(aliasIDs, entryID)
+			if len(aliasIDs) == 0 {
+				delete(c.aliasesBySelector, selector)
+			}
+		}
+		return
+	}
+
+	parentID := spiffeIDFromProto(entry.ParentId)
+	entryIDs := c.entriesByParent[parentID]
+	delete(entryIDs, entryID)
+	if len(entryIDs) == 0 {
+		delete(c.entriesByParent, parentID)
+	}
+}
+
+func (c *MutableEntryCache) getAuthorizedEntries(id spiffeID, seen seenSet) []*types.Entry {
+	entries := c.crawl(id, seen)
+	for _, descendant := range entries {
+		entries = append(entries, c.getAuthorizedEntries(spiffeIDFromProto(descendant.SpiffeId), seen)...)
+	}
+
+	for _, alias := range c.getAgentAliases(id) {
+		entries = append(entries, alias)
+		entries = append(entries, c.getAuthorizedEntries(spiffeIDFromProto(alias.SpiffeId), seen)...)
+	}
+	return entries
+}
+
+func (c *MutableEntryCache) crawl(parentID spiffeID, seen seenSet) []*types.Entry {
+	if _, ok := seen[parentID]; ok {
+		return nil
+	}
+	seen[parentID] = struct{}{}
+
+	entryIDs := c.entriesByParent[parentID]
+	entries := make([]*types.Entry, 0, len(entryIDs))
+	for entryID := range entryIDs {
+		entries = append(entries, c.entries[entryID])
+	}
+	for _, entry := range entries {
+		entries = append(entries, c.crawl(spiffeIDFromProto(entry.SpiffeId), seen)...)
+	}
+	return entries
+}
+
+func (c *MutableEntryCache) getAgentAliases(agentID spiffeID) []*types.Entry {
+	agentSelectors, ok := c.agents[agentID]
+	if !ok {
+		return nil
+	}
+
+	// track which aliases we've evaluated so far to make sure we don't
+	// add one twice.
+	aliasSeen := allocStringSet()
+	defer freeStringSet(aliasSeen)
+
+	var aliases []*types.Entry
+	for s := range agentSelectors {
+		for aliasID := range c.aliasesBySelector[s] {
+			if _, ok := aliasSeen[aliasID]; ok {
+				continue
+			}
+			aliasSeen[aliasID] = struct{}{}
+			if isSubset(c.aliases[aliasID], agentSelectors) {
+				aliases = append(aliases, c.entries[aliasID])
+			}
+		}
+	}
+	return aliases
+}
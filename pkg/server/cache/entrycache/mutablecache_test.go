@@ -0,0 +1,144 @@
+package entrycache
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMutableCache(t *testing.T) {
+	serverID := spiffeid.RequireFromString("spiffe://domain.test/spire/server")
+	agentID := spiffeid.RequireFromString("spiffe://domain.test/spire/agent/1")
+	otherAgentID := spiffeid.RequireFromString("spiffe://domain.test/spire/agent/2")
+
+	a1 := &types.Selector{Type: "a", Value: "1"}
+	b2 := &types.Selector{Type: "b", Value: "2"}
+
+	alias := &types.Entry{
+		Id:        "alias",
+		ParentId:  protoFromID(serverID),
+		SpiffeId:  &types.SPIFFEID{TrustDomain: "domain.test", Path: "/alias"},
+		Selectors: []*types.Selector{a1, b2},
+	}
+	workload := &types.Entry{
+		Id:        "workload",
+		ParentId:  protoFromID(agentID),
+		SpiffeId:  &types.SPIFFEID{TrustDomain: "domain.test", Path: "/workload"},
+		Selectors: []*types.Selector{{Type: "not", Value: "relevant"}},
+	}
+	aliasWorkload := &types.Entry{
+		Id:        "alias-workload",
+		ParentId:  alias.SpiffeId,
+		SpiffeId:  &types.SPIFFEID{TrustDomain: "domain.test", Path: "/alias-workload"},
+		Selectors: []*types.Selector{{Type: "not", Value: "relevant"}},
+	}
+
+	cache, err := BuildMutable(context.Background(),
+		makeEntryIterator([]*types.Entry{alias, workload}),
+		makeAgentIterator([]Agent{{ID: agentID, Selectors: []*types.Selector{a1}}}))
+	require.NoError(t, err)
+
+	assertAuthorizedEntries := func(agentID spiffeid.ID, expected ...*types.Entry) {
+		t.Helper()
+		actual := cache.GetAuthorizedEntries(agentID)
+		sortEntries(expected)
+		sortEntries(actual)
+		spiretest.AssertProtoListEqual(t, expected, actual)
+	}
+
+	// The agent selectors do not match the alias yet
+	assertAuthorizedEntries(agentID, workload)
+	assertAuthorizedEntries(otherAgentID)
+
+	// Updating the agent selectors makes the alias (and its descendants)
+	// authorized for the agent
+	cache.UpdateEntry(aliasWorkload)
+	cache.UpdateAgent(agentID, []*types.Selector{a1, b2})
+	assertAuthorizedEntries(agentID, workload, alias, aliasWorkload)
+
+	// Updating the alias selectors so they no longer match the agent
+	updatedAlias := proto.Clone(alias).(*types.Entry)
+	updatedAlias.Selectors = []*types.Selector{a1, {Type: "c", Value: "3"}}
+	cache.UpdateEntry(updatedAlias)
+	assertAuthorizedEntries(agentID, workload)
+
+	// Reparenting an entry moves it to the new parent
+	updatedWorkload := proto.Clone(workload).(*types.Entry)
+	updatedWorkload.ParentId = protoFromID(otherAgentID)
+	cache.UpdateEntry(updatedWorkload)
+	assertAuthorizedEntries(agentID)
+	assertAuthorizedEntries(otherAgentID, updatedWorkload)
+
+	// Restoring the alias and removing the agent
+	cache.UpdateEntry(alias)
+	cache.RemoveAgent(agentID)
+	assertAuthorizedEntries(agentID)
+
+	entries, aliases, agents := cache.Stats()
+	assert.Equal(t, 3, entries)
+	assert.Equal(t, 1, aliases)
+	assert.Equal(t, 0, agents)
+
+	// Removing entries, including unknown ones
+	cache.RemoveEntry(alias.Id)
+	cache.RemoveEntry(updatedWorkload.Id)
+	cache.RemoveEntry("unknown")
+	assertAuthorizedEntries(otherAgentID)
+
+	entries, aliases, agents = cache.Stats()
+	assert.Equal(t, 1, entries)
+	assert.Equal(t, 0, aliases)
+	assert.Equal(t, 0, agents)
+}
+
+func TestMutableCacheReturnsClonedEntries(t *testing.T) {
+	agentID := spiffeid.RequireFromString("spiffe://domain.test/spire/agent/1")
+	expected := &types.Entry{
+		Id:        "workload",
+		ParentId:  protoFromID(agentID),
+		SpiffeId:  &types.SPIFFEID{TrustDomain: "domain.test", Path: "/workload"},
+		Selectors: []*types.Selector{{Type: "T", Value: "V"}},
+		DnsNames:  []string{"dns"},
+	}
+
+	cache := NewMutableEntryCache()
+	cache.UpdateEntry(proto.Clone(expected).(*types.Entry))
+
+	actual := cache.GetAuthorizedEntries(agentID)
+	spiretest.RequireProtoListEqual(t, []*types.Entry{expected}, actual)
+
+	// Now mutate the returned entry, refetch, and assert the cache copy was
+	// not altered.
+	actual[0].DnsNames = nil
+	actual = cache.GetAuthorizedEntries(agentID)
+	spiretest.RequireProtoListEqual(t, []*types.Entry{expected}, actual)
+}
+
+func TestBuildMutableIteratorError(t *testing.T) {
+	ctx := context.Background()
+
+	cache, err := BuildMutable(ctx, &errorEntryIterator{}, makeAgentIterator(nil))
+	assert.Error(t, err)
+	assert.Nil(t, cache)
+
+	cache, err = BuildMutable(ctx, makeEntryIterator(nil), &errorAgentIterator{})
+	assert.Error(t, err)
+	assert.Nil(t, cache)
+}
+
+func protoFromID(id spiffeid.ID) *types.SPIFFEID {
+	return &types.SPIFFEID{TrustDomain: id.TrustDomain().String(), Path: id.Path()}
+}
+
+func sortEntries(es []*types.Entry) {
+	sort.Slice(es, func(a, b int) bool {
+		return es[a].Id < es[b].Id
+	})
+}
@@ -3,6 +3,9 @@ package catalog
 import (
 	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/server/plugin/bundlepublisher"
+	"github.com/spiffe/spire/pkg/server/plugin/bundlepublisher/awss3"
+	"github.com/spiffe/spire/pkg/server/plugin/bundlepublisher/disk"
+	"github.com/spiffe/spire/pkg/server/plugin/bundlepublisher/httpput"
 )
 
 type bundlePublisherRepository struct {
@@ -22,7 +25,11 @@ func (repo *bundlePublisherRepository) Versions() []catalog.Version {
 }
 
 func (repo *bundlePublisherRepository) BuiltIns() []catalog.BuiltIn {
-	return nil
+	return []catalog.BuiltIn{
+		awss3.BuiltIn(),
+		disk.BuiltIn(),
+		httpput.BuiltIn(),
+	}
 }
 
 type bundlePublisherV1 struct{}
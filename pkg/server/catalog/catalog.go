@@ -21,6 +21,7 @@ import (
 	km_telemetry "github.com/spiffe/spire/pkg/common/telemetry/server/keymanager"
 	"github.com/spiffe/spire/pkg/server/cache/dscache"
 	"github.com/spiffe/spire/pkg/server/datastore"
+	ds_kv "github.com/spiffe/spire/pkg/server/datastore/kvstore"
 	ds_sql "github.com/spiffe/spire/pkg/server/datastore/sqlstore"
 	"github.com/spiffe/spire/pkg/server/hostservice/agentstore"
 	"github.com/spiffe/spire/pkg/server/hostservice/identityprovider"
@@ -133,14 +134,15 @@ func Load(ctx context.Context, config Config) (_ *Repository, err error) {
 		}
 	}()
 
-	// Strip out the Datastore plugin configuration and load the SQL plugin
-	// directly. This allows us to bypass gRPC and get rid of response limits.
+	// Strip out the Datastore plugin configuration and load the built-in
+	// plugin directly. This allows us to bypass gRPC and get rid of response
+	// limits.
 	dataStoreConfigs, pluginConfigs := config.PluginConfigs.FilterByType(dataStoreType)
-	sqlDataStore, err := loadSQLDataStore(ctx, config.Log, dataStoreConfigs)
+	builtInDataStore, err := loadDataStore(ctx, config.Log, dataStoreConfigs)
 	if err != nil {
 		return nil, err
 	}
-	repo.dataStoreCloser = sqlDataStore
+	repo.dataStoreCloser = builtInDataStore
 
 	repo.catalogCloser, err = catalog.Load(ctx, catalog.Config{
 		Log: config.Log,
@@ -158,7 +160,7 @@ func Load(ctx context.Context, config Config) (_ *Repository, err error) {
 		return nil, err
 	}
 
-	var dataStore datastore.DataStore = sqlDataStore
+	var dataStore datastore.DataStore = builtInDataStore
 	_ = config.HealthChecker.AddCheck("catalog.datastore", &datastore.Health{
 		DataStore: dataStore,
 	})
@@ -172,7 +174,14 @@ func Load(ctx context.Context, config Config) (_ *Repository, err error) {
 	return repo, nil
 }
 
-func loadSQLDataStore(ctx context.Context, log logrus.FieldLogger, datastoreConfigs catalog.PluginConfigs) (*ds_sql.Plugin, error) {
+// builtInDataStore is implemented by the built-in DataStore plugins.
+type builtInDataStore interface {
+	datastore.DataStore
+	Configure(ctx context.Context, hclConfiguration string) error
+	Close() error
+}
+
+func loadDataStore(ctx context.Context, log logrus.FieldLogger, datastoreConfigs catalog.PluginConfigs) (builtInDataStore, error) {
 	switch {
 	case len(datastoreConfigs) == 0:
 		return nil, errors.New("expecting a DataStore plugin")
@@ -180,18 +189,29 @@ func loadSQLDataStore(ctx context.Context, log logrus.FieldLogger, datastoreConf
 		return nil, errors.New("only one DataStore plugin is allowed")
 	}
 
-	sqlConfig := datastoreConfigs[0]
-
-	if sqlConfig.Name != ds_sql.PluginName {
-		return nil, fmt.Errorf("pluggability for the DataStore is deprecated; only the built-in %q plugin is supported", ds_sql.PluginName)
+	dsConfig := datastoreConfigs[0]
+	if dsConfig.IsExternal() {
+		return nil, dataStorePluggabilityError()
 	}
-	if sqlConfig.IsExternal() {
-		return nil, fmt.Errorf("pluggability for the DataStore is deprecated; only the built-in %q plugin is supported", ds_sql.PluginName)
+
+	dsLog := log.WithField(telemetry.SubsystemName, dsConfig.Name)
+
+	var ds builtInDataStore
+	switch dsConfig.Name {
+	case ds_sql.PluginName:
+		ds = ds_sql.New(dsLog)
+	case ds_kv.PluginName:
+		ds = ds_kv.New(dsLog)
+	default:
+		return nil, dataStorePluggabilityError()
 	}
 
-	ds := ds_sql.New(log.WithField(telemetry.SubsystemName, sqlConfig.Name))
-	if err := ds.Configure(ctx, sqlConfig.Data); err != nil {
+	if err := ds.Configure(ctx, dsConfig.Data); err != nil {
 		return nil, err
 	}
 	return ds, nil
 }
+
+func dataStorePluggabilityError() error {
+	return fmt.Errorf("pluggability for the DataStore is deprecated; only the built-in %q and %q plugins are supported", ds_sql.PluginName, ds_kv.PluginName)
+}
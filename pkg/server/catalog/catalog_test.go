@@ -40,7 +40,29 @@ func Test(t *testing.T) {
 					}
 				}
 			},
-			expectErr: `pluggability for the DataStore is deprecated; only the built-in "sql" plugin is supported`,
+			expectErr: `pluggability for the DataStore is deprecated; only the built-in "sql" and "kv" plugins are supported`,
+		},
+		{
+			desc: "unknown datastore",
+			prepareConfig: func(dir string, config *catalog.Config) {
+				for i, pluginConfig := range config.PluginConfigs {
+					if pluginConfig.Type == "DataStore" {
+						config.PluginConfigs[i].Name = "unknown"
+					}
+				}
+			},
+			expectErr: `pluggability for the DataStore is deprecated; only the built-in "sql" and "kv" plugins are supported`,
+		},
+		{
+			desc: "kv datastore",
+			prepareConfig: func(dir string, config *catalog.Config) {
+				for i, pluginConfig := range config.PluginConfigs {
+					if pluginConfig.Type == "DataStore" {
+						config.PluginConfigs[i].Name = "kv"
+						config.PluginConfigs[i].Data = fmt.Sprintf("path = %q", filepath.Join(dir, "datastore.db"))
+					}
+				}
+			},
 		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
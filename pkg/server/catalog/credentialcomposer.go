@@ -3,6 +3,8 @@ package catalog
 import (
 	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer/declarative"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer/uniqueid"
 )
 
 type credentialComposerRepository struct {
@@ -22,7 +24,10 @@ func (repo *credentialComposerRepository) Versions() []catalog.Version {
 }
 
 func (repo *credentialComposerRepository) BuiltIns() []catalog.BuiltIn {
-	return nil
+	return []catalog.BuiltIn{
+		declarative.BuiltIn(),
+		uniqueid.BuiltIn(),
+	}
 }
 
 type credentialComposerV1 struct{}
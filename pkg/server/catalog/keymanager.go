@@ -7,6 +7,7 @@ import (
 	"github.com/spiffe/spire/pkg/server/plugin/keymanager/awskms"
 	"github.com/spiffe/spire/pkg/server/plugin/keymanager/disk"
 	"github.com/spiffe/spire/pkg/server/plugin/keymanager/gcpkms"
+	"github.com/spiffe/spire/pkg/server/plugin/keymanager/hashicorpvault"
 	"github.com/spiffe/spire/pkg/server/plugin/keymanager/memory"
 )
 
@@ -31,6 +32,7 @@ func (repo *keyManagerRepository) BuiltIns() []catalog.BuiltIn {
 		awskms.BuiltIn(),
 		disk.BuiltIn(),
 		gcpkms.BuiltIn(),
+		hashicorpvault.BuiltIn(),
 		memory.BuiltIn(),
 	}
 }
@@ -98,6 +98,14 @@ type Config struct {
 	// CacheReloadInterval controls how often the in-memory entry cache reloads
 	CacheReloadInterval time.Duration
 
+	// EventsBasedCache enables keeping the in-memory entry cache up to date
+	// with the datastore events instead of periodically reloading it
+	EventsBasedCache bool
+
+	// PruneEventsOlderThan controls how long events are kept in the datastore
+	// when EventsBasedCache is enabled
+	PruneEventsOlderThan time.Duration
+
 	// AuthPolicyEngineConfig determines the config for authz policy
 	AuthOpaPolicyEngineConfig *authpolicy.OpaEngineConfig
 
@@ -90,6 +90,7 @@ type WorkloadX509SVIDParams struct {
 	DNSNames    []string
 	TTL         time.Duration
 	Subject     pkix.Name
+	Hint        string
 }
 
 type WorkloadJWTSVIDParams struct {
@@ -97,6 +98,7 @@ type WorkloadJWTSVIDParams struct {
 	Audience      []string
 	TTL           time.Duration
 	ExpirationCap time.Time
+	Hint          string
 }
 
 type Config struct {
@@ -293,6 +295,7 @@ func (b *Builder) BuildWorkloadX509SVIDTemplate(ctx context.Context, params Work
 		tmpl.DNSNames = params.DNSNames
 	}
 
+	ctx = credentialcomposer.WithEntryHint(ctx, params.Hint)
 	for _, cc := range b.config.CredentialComposers {
 		attributes, err := cc.ComposeWorkloadX509SVID(ctx, params.SPIFFEID, params.PublicKey, x509SVIDAttributesFromTemplate(tmpl))
 		if err != nil {
@@ -337,6 +340,7 @@ func (b *Builder) BuildWorkloadJWTSVIDClaims(ctx context.Context, params Workloa
 		attributes.Claims["iss"] = b.config.JWTIssuer
 	}
 
+	ctx = credentialcomposer.WithEntryHint(ctx, params.Hint)
 	for _, cc := range b.config.CredentialComposers {
 		var err error
 		attributes, err = cc.ComposeWorkloadJWTSVID(ctx, params.SPIFFEID, attributes)
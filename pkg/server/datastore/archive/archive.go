@@ -0,0 +1,539 @@
+// Package archive exports the contents of a datastore to an archive, and
+// imports them back into any datastore. It can be used to back up the
+// datastore for disaster recovery, or to migrate to a different backend.
+//
+// An archive is a gzip compressed stream of JSON records, one per line. The
+// first record is a header holding the archive version and the trust domain
+// of the server. It is followed by the bundles, federation relationships,
+// attested nodes (with their selectors), registration entries and join
+// tokens, in that order, so records are imported after the records they
+// depend on. The last record holds the number of records of each kind, which
+// is used to detect truncated archives.
+package archive
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Version is the version of the archives produced by Export.
+	Version = 1
+
+	// pageSize is the number of records listed at once when exporting.
+	pageSize = 1000
+)
+
+// Counts holds the number of records of each kind.
+type Counts struct {
+	Bundles                 int32 `json:"bundles"`
+	FederationRelationships int32 `json:"federation_relationships"`
+	AttestedNodes           int32 `json:"attested_nodes"`
+	RegistrationEntries     int32 `json:"registration_entries"`
+	JoinTokens              int32 `json:"join_tokens"`
+}
+
+// ImportResult holds the number of records imported, and the number of
+// records skipped because they already existed in the datastore.
+type ImportResult struct {
+	Imported Counts
+	Skipped  Counts
+}
+
+type header struct {
+	Version     int    `json:"version"`
+	TrustDomain string `json:"trust_domain"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+// record holds a single record of the archive. Only one of the fields is set.
+type record struct {
+	Bundle                 json.RawMessage         `json:"bundle,omitempty"`
+	FederationRelationship *federationRelationship `json:"federation_relationship,omitempty"`
+	AttestedNode           json.RawMessage         `json:"attested_node,omitempty"`
+	RegistrationEntry      json.RawMessage         `json:"registration_entry,omitempty"`
+	JoinToken              *joinToken              `json:"join_token,omitempty"`
+	End                    *Counts                 `json:"end,omitempty"`
+}
+
+type federationRelationship struct {
+	TrustDomain           string `json:"trust_domain"`
+	BundleEndpointURL     string `json:"bundle_endpoint_url"`
+	BundleEndpointProfile string `json:"bundle_endpoint_profile"`
+	EndpointSPIFFEID      string `json:"endpoint_spiffe_id,omitempty"`
+}
+
+type joinToken struct {
+	Token  string `json:"token"`
+	Expiry int64  `json:"expiry"`
+}
+
+// Export writes an archive with the contents of the datastore to w. The
+// datastore is read page by page while the server is running, so the archive
+// is not a point-in-time snapshot of the whole datastore.
+func Export(ctx context.Context, ds datastore.DataStore, td spiffeid.TrustDomain, w io.Writer) (*Counts, error) {
+	gz := gzip.NewWriter(w)
+	e := &exporter{
+		ds:     ds,
+		enc:    json.NewEncoder(gz),
+		counts: new(Counts),
+	}
+
+	if err := e.enc.Encode(header{
+		Version:     Version,
+		TrustDomain: td.Name(),
+		CreatedAt:   time.Now().Unix(),
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, export := range []func(context.Context) error{
+		e.exportBundles,
+		e.exportFederationRelationships,
+		e.exportAttestedNodes,
+		e.exportRegistrationEntries,
+		e.exportJoinTokens,
+	} {
+		if err := export(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := e.enc.Encode(record{End: e.counts}); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return e.counts, nil
+}
+
+type exporter struct {
+	ds     datastore.DataStore
+	enc    *json.Encoder
+	counts *Counts
+}
+
+func (e *exporter) exportBundles(ctx context.Context) error {
+	req := &datastore.ListBundlesRequest{
+		Pagination: &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := e.ds.ListBundles(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list bundles: %w", err)
+		}
+		for _, bundle := range resp.Bundles {
+			data, err := marshalProto(bundle)
+			if err != nil {
+				return err
+			}
+			if err := e.enc.Encode(record{Bundle: data}); err != nil {
+				return err
+			}
+			e.counts.Bundles++
+		}
+		if resp.Pagination == nil || resp.Pagination.Token == "" {
+			return nil
+		}
+		req.Pagination = resp.Pagination
+	}
+}
+
+func (e *exporter) exportFederationRelationships(ctx context.Context) error {
+	req := &datastore.ListFederationRelationshipsRequest{
+		Pagination: &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := e.ds.ListFederationRelationships(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list federation relationships: %w", err)
+		}
+		for _, fr := range resp.FederationRelationships {
+			r := &federationRelationship{
+				TrustDomain:           fr.TrustDomain.Name(),
+				BundleEndpointURL:     fr.BundleEndpointURL.String(),
+				BundleEndpointProfile: string(fr.BundleEndpointProfile),
+			}
+			if fr.BundleEndpointProfile == datastore.BundleEndpointSPIFFE {
+				r.EndpointSPIFFEID = fr.EndpointSPIFFEID.String()
+			}
+			if err := e.enc.Encode(record{FederationRelationship: r}); err != nil {
+				return err
+			}
+			e.counts.FederationRelationships++
+		}
+		if resp.Pagination == nil || resp.Pagination.Token == "" {
+			return nil
+		}
+		req.Pagination = resp.Pagination
+	}
+}
+
+func (e *exporter) exportAttestedNodes(ctx context.Context) error {
+	req := &datastore.ListAttestedNodesRequest{
+		FetchSelectors: true,
+		Pagination:     &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := e.ds.ListAttestedNodes(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list attested nodes: %w", err)
+		}
+		for _, node := range resp.Nodes {
+			data, err := marshalProto(node)
+			if err != nil {
+				return err
+			}
+			if err := e.enc.Encode(record{AttestedNode: data}); err != nil {
+				return err
+			}
+			e.counts.AttestedNodes++
+		}
+		if resp.Pagination == nil || resp.Pagination.Token == "" {
+			return nil
+		}
+		req.Pagination = resp.Pagination
+	}
+}
+
+func (e *exporter) exportRegistrationEntries(ctx context.Context) error {
+	req := &datastore.ListRegistrationEntriesRequest{
+		Pagination: &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := e.ds.ListRegistrationEntries(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list registration entries: %w", err)
+		}
+		for _, entry := range resp.Entries {
+			data, err := marshalProto(entry)
+			if err != nil {
+				return err
+			}
+			if err := e.enc.Encode(record{RegistrationEntry: data}); err != nil {
+				return err
+			}
+			e.counts.RegistrationEntries++
+		}
+		if resp.Pagination == nil || resp.Pagination.Token == "" {
+			return nil
+		}
+		req.Pagination = resp.Pagination
+	}
+}
+
+func (e *exporter) exportJoinTokens(ctx context.Context) error {
+	req := &datastore.ListJoinTokensRequest{
+		Pagination: &datastore.Pagination{PageSize: pageSize},
+	}
+	for {
+		resp, err := e.ds.ListJoinTokens(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list join tokens: %w", err)
+		}
+		for _, token := range resp.JoinTokens {
+			if err := e.enc.Encode(record{JoinToken: &joinToken{
+				Token:  token.Token,
+				Expiry: token.Expiry.Unix(),
+			}}); err != nil {
+				return err
+			}
+			e.counts.JoinTokens++
+		}
+		if resp.Pagination == nil || resp.Pagination.Token == "" {
+			return nil
+		}
+		req.Pagination = resp.Pagination
+	}
+}
+
+// Import restores the records of the archive read from r into the
+// datastore. Registration entries keep their IDs and revision numbers.
+// Records that already exist in the datastore are skipped, except for
+// bundles, which are merged into the existing ones. The whole archive is read
+// and validated before the first record is written, so a corrupted or
+// truncated archive leaves the datastore untouched.
+func Import(ctx context.Context, ds datastore.DataStore, td spiffeid.TrustDomain, r io.Reader) (*ImportResult, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive: %w", err)
+	}
+	dec := json.NewDecoder(gz)
+
+	var h header
+	if err := dec.Decode(&h); err != nil {
+		return nil, fmt.Errorf("invalid archive header: %w", err)
+	}
+	if h.Version != Version {
+		return nil, fmt.Errorf("unsupported archive version %d", h.Version)
+	}
+	if h.TrustDomain != td.Name() {
+		return nil, fmt.Errorf("archive is for trust domain %q, not %q", h.TrustDomain, td.Name())
+	}
+
+	c, err := readContents(dec)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &importer{
+		ds:     ds,
+		result: new(ImportResult),
+	}
+	for _, bundle := range c.bundles {
+		if err := i.importBundle(ctx, bundle); err != nil {
+			return nil, err
+		}
+	}
+	for _, fr := range c.federationRelationships {
+		if err := i.importFederationRelationship(ctx, fr); err != nil {
+			return nil, err
+		}
+	}
+	for _, node := range c.attestedNodes {
+		if err := i.importAttestedNode(ctx, node); err != nil {
+			return nil, err
+		}
+	}
+	for _, entry := range c.registrationEntries {
+		if err := i.importRegistrationEntry(ctx, entry); err != nil {
+			return nil, err
+		}
+	}
+	for _, token := range c.joinTokens {
+		if err := i.importJoinToken(ctx, token); err != nil {
+			return nil, err
+		}
+	}
+	return i.result, nil
+}
+
+// contents holds the records of an archive, parsed and ready to be imported.
+type contents struct {
+	bundles                 []*common.Bundle
+	federationRelationships []*datastore.FederationRelationship
+	attestedNodes           []*common.AttestedNode
+	registrationEntries     []*common.RegistrationEntry
+	joinTokens              []*datastore.JoinToken
+}
+
+func (c *contents) counts() Counts {
+	return Counts{
+		Bundles:                 int32(len(c.bundles)),
+		FederationRelationships: int32(len(c.federationRelationships)),
+		AttestedNodes:           int32(len(c.attestedNodes)),
+		RegistrationEntries:     int32(len(c.registrationEntries)),
+		JoinTokens:              int32(len(c.joinTokens)),
+	}
+}
+
+// readContents reads and parses the records following the archive header,
+// up to and including the end record.
+func readContents(dec *json.Decoder) (*contents, error) {
+	c := new(contents)
+	for {
+		var rec record
+		if err := dec.Decode(&rec); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil, errors.New("archive is truncated")
+			}
+			return nil, fmt.Errorf("invalid archive record: %w", err)
+		}
+
+		switch {
+		case rec.Bundle != nil:
+			bundle, err := parseBundle(rec.Bundle)
+			if err != nil {
+				return nil, err
+			}
+			c.bundles = append(c.bundles, bundle)
+		case rec.FederationRelationship != nil:
+			fr, err := parseFederationRelationship(rec.FederationRelationship)
+			if err != nil {
+				return nil, err
+			}
+			c.federationRelationships = append(c.federationRelationships, fr)
+		case rec.AttestedNode != nil:
+			node, err := parseAttestedNode(rec.AttestedNode)
+			if err != nil {
+				return nil, err
+			}
+			c.attestedNodes = append(c.attestedNodes, node)
+		case rec.RegistrationEntry != nil:
+			entry, err := parseRegistrationEntry(rec.RegistrationEntry)
+			if err != nil {
+				return nil, err
+			}
+			c.registrationEntries = append(c.registrationEntries, entry)
+		case rec.JoinToken != nil:
+			c.joinTokens = append(c.joinTokens, &datastore.JoinToken{
+				Token:  rec.JoinToken.Token,
+				Expiry: time.Unix(rec.JoinToken.Expiry, 0),
+			})
+		case rec.End != nil:
+			if *rec.End != c.counts() {
+				return nil, errors.New("archive is corrupted: record counts do not match")
+			}
+			return c, nil
+		default:
+			return nil, errors.New("invalid archive record: unknown record type")
+		}
+	}
+}
+
+func parseBundle(data []byte) (*common.Bundle, error) {
+	bundle := new(common.Bundle)
+	if err := protojson.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("invalid bundle record: %w", err)
+	}
+	return bundle, nil
+}
+
+func parseFederationRelationship(r *federationRelationship) (*datastore.FederationRelationship, error) {
+	td, err := spiffeid.TrustDomainFromString(r.TrustDomain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid federation relationship record: %w", err)
+	}
+	bundleEndpointURL, err := url.Parse(r.BundleEndpointURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid federation relationship record: %w", err)
+	}
+	fr := &datastore.FederationRelationship{
+		TrustDomain:           td,
+		BundleEndpointURL:     bundleEndpointURL,
+		BundleEndpointProfile: datastore.BundleEndpointType(r.BundleEndpointProfile),
+	}
+	if fr.BundleEndpointProfile == datastore.BundleEndpointSPIFFE {
+		fr.EndpointSPIFFEID, err = spiffeid.FromString(r.EndpointSPIFFEID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid federation relationship record: %w", err)
+		}
+	}
+	return fr, nil
+}
+
+func parseAttestedNode(data []byte) (*common.AttestedNode, error) {
+	node := new(common.AttestedNode)
+	if err := protojson.Unmarshal(data, node); err != nil {
+		return nil, fmt.Errorf("invalid attested node record: %w", err)
+	}
+	return node, nil
+}
+
+func parseRegistrationEntry(data []byte) (*common.RegistrationEntry, error) {
+	entry := new(common.RegistrationEntry)
+	if err := protojson.Unmarshal(data, entry); err != nil {
+		return nil, fmt.Errorf("invalid registration entry record: %w", err)
+	}
+	if entry.EntryId == "" {
+		return nil, errors.New("invalid registration entry record: missing entry ID")
+	}
+	return entry, nil
+}
+
+type importer struct {
+	ds     datastore.DataStore
+	result *ImportResult
+}
+
+func (i *importer) importBundle(ctx context.Context, bundle *common.Bundle) error {
+	if _, err := i.ds.AppendBundle(ctx, bundle); err != nil {
+		return fmt.Errorf("failed to import bundle %q: %w", bundle.TrustDomainId, err)
+	}
+	i.result.Imported.Bundles++
+	return nil
+}
+
+func (i *importer) importFederationRelationship(ctx context.Context, fr *datastore.FederationRelationship) error {
+	existing, err := i.ds.FetchFederationRelationship(ctx, fr.TrustDomain)
+	if err != nil {
+		return fmt.Errorf("failed to fetch federation relationship %q: %w", fr.TrustDomain.Name(), err)
+	}
+	if existing != nil {
+		i.result.Skipped.FederationRelationships++
+		return nil
+	}
+
+	if _, err := i.ds.CreateFederationRelationship(ctx, fr); err != nil {
+		return fmt.Errorf("failed to import federation relationship %q: %w", fr.TrustDomain.Name(), err)
+	}
+	i.result.Imported.FederationRelationships++
+	return nil
+}
+
+func (i *importer) importAttestedNode(ctx context.Context, node *common.AttestedNode) error {
+	existing, err := i.ds.FetchAttestedNode(ctx, node.SpiffeId)
+	if err != nil {
+		return fmt.Errorf("failed to fetch attested node %q: %w", node.SpiffeId, err)
+	}
+	if existing != nil {
+		i.result.Skipped.AttestedNodes++
+		return nil
+	}
+
+	if _, err := i.ds.CreateAttestedNode(ctx, node); err != nil {
+		return fmt.Errorf("failed to import attested node %q: %w", node.SpiffeId, err)
+	}
+	if len(node.Selectors) > 0 {
+		if err := i.ds.SetNodeSelectors(ctx, node.SpiffeId, node.Selectors); err != nil {
+			return fmt.Errorf("failed to import selectors of attested node %q: %w", node.SpiffeId, err)
+		}
+	}
+	i.result.Imported.AttestedNodes++
+	return nil
+}
+
+func (i *importer) importRegistrationEntry(ctx context.Context, entry *common.RegistrationEntry) error {
+	// Entries that already exist, either with the same ID or as a similar
+	// entry with a different ID, are skipped.
+	_, err := i.ds.RestoreRegistrationEntry(ctx, entry)
+	switch {
+	case status.Code(err) == codes.AlreadyExists:
+		i.result.Skipped.RegistrationEntries++
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to import registration entry %q: %w", entry.EntryId, err)
+	}
+	i.result.Imported.RegistrationEntries++
+	return nil
+}
+
+func (i *importer) importJoinToken(ctx context.Context, token *datastore.JoinToken) error {
+	existing, err := i.ds.FetchJoinToken(ctx, token.Token)
+	if err != nil {
+		return fmt.Errorf("failed to fetch join token: %w", err)
+	}
+	if existing != nil {
+		i.result.Skipped.JoinTokens++
+		return nil
+	}
+
+	if err := i.ds.CreateJoinToken(ctx, token); err != nil {
+		return fmt.Errorf("failed to import join token: %w", err)
+	}
+	i.result.Imported.JoinTokens++
+	return nil
+}
+
+func marshalProto(m proto.Message) (json.RawMessage, error) {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
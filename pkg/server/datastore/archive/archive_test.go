@@ -0,0 +1,360 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/pkg/server/datastore/kvstore"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ctx         = context.Background()
+	td          = spiffeid.RequireTrustDomainFromString("example.org")
+	federatedTD = spiffeid.RequireTrustDomainFromString("federated.org")
+)
+
+func TestExportImport(t *testing.T) {
+	src := fakedatastore.New(t)
+	populate(t, src)
+
+	archive := new(bytes.Buffer)
+	counts, err := Export(ctx, src, td, archive)
+	require.NoError(t, err)
+	require.Equal(t, &Counts{
+		Bundles:                 2,
+		FederationRelationships: 1,
+		AttestedNodes:           2,
+		RegistrationEntries:     3,
+		JoinTokens:              2,
+	}, counts)
+
+	// Restore into a different backend
+	dst := newKVStore(t)
+	result, err := Import(ctx, dst, td, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, *counts, result.Imported)
+	require.Equal(t, Counts{}, result.Skipped)
+
+	assertSameContents(t, src, dst)
+
+	// Importing the same archive again skips existing records, except for
+	// bundles, which are merged.
+	result, err = Import(ctx, dst, td, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, Counts{Bundles: 2}, result.Imported)
+	require.Equal(t, Counts{
+		FederationRelationships: 1,
+		AttestedNodes:           2,
+		RegistrationEntries:     3,
+		JoinTokens:              2,
+	}, result.Skipped)
+
+	assertSameContents(t, src, dst)
+}
+
+func TestImportSkipsSimilarEntries(t *testing.T) {
+	src := fakedatastore.New(t)
+	populate(t, src)
+
+	archive := new(bytes.Buffer)
+	_, err := Export(ctx, src, td, archive)
+	require.NoError(t, err)
+
+	// The destination already has an entry similar to an exported one,
+	// created with a different ID.
+	dst := newKVStore(t)
+	_, err = dst.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+		ParentId:  spiffeid.RequireFromPath(td, "/spire/agent/join_token/0").String(),
+		SpiffeId:  spiffeid.RequireFromPath(td, "/workload/0").String(),
+		Selectors: []*common.Selector{{Type: "unix", Value: "uid:0"}},
+	})
+	require.NoError(t, err)
+
+	result, err := Import(ctx, dst, td, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), result.Imported.RegistrationEntries)
+	require.Equal(t, int32(1), result.Skipped.RegistrationEntries)
+}
+
+func TestImportInvalidArchive(t *testing.T) {
+	src := fakedatastore.New(t)
+	populate(t, src)
+
+	archive := new(bytes.Buffer)
+	_, err := Export(ctx, src, td, archive)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name   string
+		data   []byte
+		td     spiffeid.TrustDomain
+		expErr string
+	}{
+		{
+			name:   "not gzipped",
+			data:   []byte("{}"),
+			td:     td,
+			expErr: "invalid archive: unexpected EOF",
+		},
+		{
+			name:   "wrong trust domain",
+			data:   archive.Bytes(),
+			td:     federatedTD,
+			expErr: `archive is for trust domain "example.org", not "federated.org"`,
+		},
+		{
+			name:   "unsupported version",
+			data:   gzipLines(t, `{"version":2,"trust_domain":"example.org"}`),
+			td:     td,
+			expErr: "unsupported archive version 2",
+		},
+		{
+			name:   "truncated",
+			data:   gzipLines(t, `{"version":1,"trust_domain":"example.org"}`, `{"join_token":{"token":"foo","expiry":1}}`),
+			td:     td,
+			expErr: "archive is truncated",
+		},
+		{
+			name:   "counts do not match",
+			data:   gzipLines(t, `{"version":1,"trust_domain":"example.org"}`, `{"end":{"join_tokens":1}}`),
+			td:     td,
+			expErr: "archive is corrupted: record counts do not match",
+		},
+		{
+			name:   "unknown record",
+			data:   gzipLines(t, `{"version":1,"trust_domain":"example.org"}`, `{"foo":{}}`),
+			td:     td,
+			expErr: "invalid archive record: unknown record type",
+		},
+		{
+			name:   "entry without ID",
+			data:   gzipLines(t, `{"version":1,"trust_domain":"example.org"}`, `{"registration_entry":{"spiffeId":"spiffe://example.org/foo"}}`),
+			td:     td,
+			expErr: "invalid registration entry record: missing entry ID",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Import(ctx, newKVStore(t), tt.td, bytes.NewReader(tt.data))
+			require.EqualError(t, err, tt.expErr)
+		})
+	}
+}
+
+func TestImportCorruptedRecordMidStream(t *testing.T) {
+	src := fakedatastore.New(t)
+	populate(t, src)
+
+	archive := new(bytes.Buffer)
+	_, err := Export(ctx, src, td, archive)
+	require.NoError(t, err)
+
+	gz, err := gzip.NewReader(archive)
+	require.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	require.NoError(t, err)
+
+	// Corrupt the first registration entry, which comes after the bundles,
+	// federation relationships and attested nodes.
+	var lines []string
+	corrupted := false
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if !corrupted && strings.HasPrefix(line, `{"registration_entry":`) {
+			line = `{"registration_entry":{"entryId":}}`
+			corrupted = true
+		}
+		lines = append(lines, line)
+	}
+	require.True(t, corrupted)
+
+	dst := newKVStore(t)
+	_, err = Import(ctx, dst, td, bytes.NewReader(gzipLines(t, lines...)))
+	require.ErrorContains(t, err, "invalid archive record")
+
+	// None of the records preceding the corrupted one were imported
+	assertSameContents(t, newKVStore(t), dst)
+}
+
+func TestEncryption(t *testing.T) {
+	// Large enough to span several chunks
+	plaintext := bytes.Repeat([]byte("0123456789abcdef"), chunkSize/8+3)
+	passphrase := []byte("passphrase")
+
+	encrypted := new(bytes.Buffer)
+	w, err := NewEncryptWriter(encrypted, passphrase)
+	require.NoError(t, err)
+	_, err = w.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.True(t, IsEncrypted(bufio.NewReader(bytes.NewReader(encrypted.Bytes()))))
+	require.NotContains(t, encrypted.String(), "0123456789abcdef")
+
+	decrypt := func(data []byte, passphrase []byte) ([]byte, error) {
+		r, err := NewDecryptReader(bytes.NewReader(data), passphrase)
+		if err != nil {
+			return nil, err
+		}
+		out := new(bytes.Buffer)
+		_, err = out.ReadFrom(r)
+		return out.Bytes(), err
+	}
+
+	t.Run("success", func(t *testing.T) {
+		out, err := decrypt(encrypted.Bytes(), passphrase)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, out)
+	})
+
+	t.Run("wrong passphrase", func(t *testing.T) {
+		_, err := decrypt(encrypted.Bytes(), []byte("wrong"))
+		require.EqualError(t, err, "failed to decrypt archive: wrong key or corrupted archive")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		// Drop the final chunk
+		_, err := decrypt(encrypted.Bytes()[:encrypted.Len()-100], passphrase)
+		require.EqualError(t, err, "encrypted archive is truncated")
+	})
+
+	t.Run("not encrypted", func(t *testing.T) {
+		_, err := decrypt(bytes.Repeat([]byte{0}, 64), passphrase)
+		require.EqualError(t, err, "archive is not encrypted")
+	})
+
+	t.Run("empty passphrase", func(t *testing.T) {
+		_, err := NewEncryptWriter(new(bytes.Buffer), nil)
+		require.EqualError(t, err, "passphrase is required")
+	})
+}
+
+func populate(t *testing.T, ds datastore.DataStore) {
+	_, err := ds.CreateBundle(ctx, &common.Bundle{
+		TrustDomainId: td.IDString(),
+		RootCas:       []*common.Certificate{{DerBytes: []byte("root")}},
+	})
+	require.NoError(t, err)
+
+	_, err = ds.CreateBundle(ctx, &common.Bundle{
+		TrustDomainId: federatedTD.IDString(),
+		RootCas:       []*common.Certificate{{DerBytes: []byte("federated root")}},
+	})
+	require.NoError(t, err)
+
+	_, err = ds.CreateFederationRelationship(ctx, &datastore.FederationRelationship{
+		TrustDomain:           federatedTD,
+		BundleEndpointURL:     &url.URL{Scheme: "https", Host: "federated.org"},
+		BundleEndpointProfile: datastore.BundleEndpointSPIFFE,
+		EndpointSPIFFEID:      spiffeid.RequireFromPath(federatedTD, "/spire/server"),
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		spiffeID := spiffeid.RequireFromPathf(td, "/spire/agent/join_token/%d", i)
+		_, err = ds.CreateAttestedNode(ctx, &common.AttestedNode{
+			SpiffeId:            spiffeID.String(),
+			AttestationDataType: "join_token",
+			CertSerialNumber:    fmt.Sprintf("%d", i),
+			CertNotAfter:        time.Now().Add(time.Hour).Unix(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, ds.SetNodeSelectors(ctx, spiffeID.String(), []*common.Selector{
+			{Type: "foo", Value: fmt.Sprintf("bar%d", i)},
+		}))
+	}
+
+	for i := 0; i < 3; i++ {
+		entry, err := ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+			ParentId:  spiffeid.RequireFromPath(td, "/spire/agent/join_token/0").String(),
+			SpiffeId:  spiffeid.RequireFromPathf(td, "/workload/%d", i).String(),
+			Selectors: []*common.Selector{{Type: "unix", Value: fmt.Sprintf("uid:%d", i)}},
+			DnsNames:  []string{"example.org"},
+		})
+		require.NoError(t, err)
+
+		// Bump the revision number
+		_, err = ds.UpdateRegistrationEntry(ctx, entry, &common.RegistrationEntryMask{DnsNames: true})
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, ds.CreateJoinToken(ctx, &datastore.JoinToken{
+			Token:  fmt.Sprintf("token-%d", i),
+			Expiry: time.Unix(time.Now().Add(time.Hour).Unix(), 0),
+		}))
+	}
+}
+
+func assertSameContents(t *testing.T, expected, actual datastore.DataStore) {
+	expBundles, err := expected.ListBundles(ctx, &datastore.ListBundlesRequest{})
+	require.NoError(t, err)
+	bundles, err := actual.ListBundles(ctx, &datastore.ListBundlesRequest{})
+	require.NoError(t, err)
+	spiretest.AssertProtoListEqual(t, expBundles.Bundles, bundles.Bundles)
+
+	expFRs, err := expected.ListFederationRelationships(ctx, &datastore.ListFederationRelationshipsRequest{})
+	require.NoError(t, err)
+	frs, err := actual.ListFederationRelationships(ctx, &datastore.ListFederationRelationshipsRequest{})
+	require.NoError(t, err)
+	require.Equal(t, expFRs.FederationRelationships, frs.FederationRelationships)
+
+	expNodes, err := expected.ListAttestedNodes(ctx, &datastore.ListAttestedNodesRequest{FetchSelectors: true})
+	require.NoError(t, err)
+	nodes, err := actual.ListAttestedNodes(ctx, &datastore.ListAttestedNodesRequest{FetchSelectors: true})
+	require.NoError(t, err)
+	spiretest.AssertProtoListEqual(t, expNodes.Nodes, nodes.Nodes)
+
+	expEntries, err := expected.ListRegistrationEntries(ctx, &datastore.ListRegistrationEntriesRequest{})
+	require.NoError(t, err)
+	entries, err := actual.ListRegistrationEntries(ctx, &datastore.ListRegistrationEntriesRequest{})
+	require.NoError(t, err)
+	require.Len(t, entries.Entries, len(expEntries.Entries))
+	for _, expEntry := range expEntries.Entries {
+		entry, err := actual.FetchRegistrationEntry(ctx, expEntry.EntryId)
+		require.NoError(t, err)
+		require.NotNil(t, entry, "entry %q was not restored", expEntry.EntryId)
+		require.Equal(t, int64(1), entry.RevisionNumber)
+		expEntry.CreatedAt = entry.CreatedAt
+		spiretest.AssertProtoEqual(t, expEntry, entry)
+	}
+
+	expTokens, err := expected.ListJoinTokens(ctx, &datastore.ListJoinTokensRequest{})
+	require.NoError(t, err)
+	tokens, err := actual.ListJoinTokens(ctx, &datastore.ListJoinTokensRequest{})
+	require.NoError(t, err)
+	require.ElementsMatch(t, expTokens.JoinTokens, tokens.JoinTokens)
+}
+
+func newKVStore(t *testing.T) datastore.DataStore {
+	log, _ := test.NewNullLogger()
+	ds := kvstore.New(log)
+	require.NoError(t, ds.Configure(ctx, fmt.Sprintf(`path = %q`, filepath.Join(t.TempDir(), "datastore.db"))))
+	t.Cleanup(func() { ds.Close() })
+	return ds
+}
+
+func gzipLines(t *testing.T, lines ...string) []byte {
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	for _, line := range lines {
+		_, err := gz.Write([]byte(line + "\n"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,248 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted archives start with a header made of the magic string, the
+// version of the encryption scheme, the salt used to derive the key from the
+// passphrase and a random nonce prefix. The header is followed by chunks of
+// at most chunkSize bytes of plaintext, each sealed with AES-256-GCM using
+// the nonce prefix and a chunk counter as nonce. Each chunk is framed with a
+// flag that marks the final chunk (also authenticated as additional data, so
+// truncation is detected) and the length of the ciphertext.
+const (
+	encryptionMagic   = "SPIREDSE"
+	encryptionVersion = 1
+
+	saltSize        = 16
+	noncePrefixSize = 4
+	chunkSize       = 64 * 1024
+
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+	keySize = 32
+
+	chunkFlagFinal = 1
+)
+
+var errTruncated = errors.New("encrypted archive is truncated")
+
+// IsEncrypted returns true if the archive read by r is encrypted. The
+// reader is not advanced.
+func IsEncrypted(r *bufio.Reader) bool {
+	prefix, _ := r.Peek(len(encryptionMagic))
+	return hasEncryptionMagic(prefix)
+}
+
+func hasEncryptionMagic(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptionMagic))
+}
+
+// NewEncryptWriter returns a writer that encrypts an archive with a key
+// derived from the given passphrase and writes it to w. The writer must be
+// closed to flush the final chunk.
+func NewEncryptWriter(w io.Writer, passphrase []byte) (io.WriteCloser, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase is required")
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	noncePrefix := make([]byte, noncePrefixSize)
+	if _, err := rand.Read(noncePrefix); err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(encryptionMagic)+1+saltSize+noncePrefixSize)
+	header = append(header, encryptionMagic...)
+	header = append(header, encryptionVersion)
+	header = append(header, salt...)
+	header = append(header, noncePrefix...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:           w,
+		aead:        aead,
+		noncePrefix: noncePrefix,
+		buf:         make([]byte, 0, chunkSize),
+	}, nil
+}
+
+type encryptWriter struct {
+	w           io.Writer
+	aead        cipher.AEAD
+	noncePrefix []byte
+	counter     uint64
+	buf         []byte
+	closed      bool
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write on closed encrypt writer")
+	}
+
+	n := len(p)
+	for len(p) > 0 {
+		if len(e.buf) == chunkSize {
+			if err := e.writeChunk(false); err != nil {
+				return 0, err
+			}
+		}
+		c := copy(e.buf[len(e.buf):chunkSize], p)
+		e.buf = e.buf[:len(e.buf)+c]
+		p = p[c:]
+	}
+	return n, nil
+}
+
+func (e *encryptWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.writeChunk(true)
+}
+
+func (e *encryptWriter) writeChunk(final bool) error {
+	flag := chunkFlags(final)
+	ciphertext := e.aead.Seal(nil, chunkNonce(e.noncePrefix, e.counter), e.buf, flag)
+	e.counter++
+	e.buf = e.buf[:0]
+
+	frame := make([]byte, 5, 5+len(ciphertext))
+	frame[0] = flag[0]
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(ciphertext)))
+	frame = append(frame, ciphertext...)
+	_, err := e.w.Write(frame)
+	return err
+}
+
+// NewDecryptReader returns a reader that decrypts an archive encrypted by
+// NewEncryptWriter with the same passphrase.
+func NewDecryptReader(r io.Reader, passphrase []byte) (io.Reader, error) {
+	header := make([]byte, len(encryptionMagic)+1+saltSize+noncePrefixSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("failed to read encryption header: %w", err)
+	}
+	if !hasEncryptionMagic(header) {
+		return nil, errors.New("archive is not encrypted")
+	}
+	header = header[len(encryptionMagic):]
+	if header[0] != encryptionVersion {
+		return nil, fmt.Errorf("unsupported encryption version %d", header[0])
+	}
+	salt := header[1 : 1+saltSize]
+	noncePrefix := header[1+saltSize:]
+
+	aead, err := newAEAD(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:           r,
+		aead:        aead,
+		noncePrefix: noncePrefix,
+	}, nil
+}
+
+type decryptReader struct {
+	r           io.Reader
+	aead        cipher.AEAD
+	noncePrefix []byte
+	counter     uint64
+	buf         []byte
+	final       bool
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.final {
+			return 0, io.EOF
+		}
+		if err := d.readChunk(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *decryptReader) readChunk() error {
+	var frame [5]byte
+	if _, err := io.ReadFull(d.r, frame[:]); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return errTruncated
+		}
+		return err
+	}
+	size := binary.BigEndian.Uint32(frame[1:])
+	if size > chunkSize+uint32(d.aead.Overhead()) {
+		return errors.New("encrypted archive is corrupted: chunk too large")
+	}
+	ciphertext := make([]byte, size)
+	if _, err := io.ReadFull(d.r, ciphertext); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return errTruncated
+		}
+		return err
+	}
+
+	plaintext, err := d.aead.Open(nil, chunkNonce(d.noncePrefix, d.counter), ciphertext, frame[:1])
+	if err != nil {
+		return errors.New("failed to decrypt archive: wrong key or corrupted archive")
+	}
+	d.counter++
+	d.buf = plaintext
+	d.final = frame[0] == chunkFlagFinal
+	return nil
+}
+
+func newAEAD(passphrase, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, scryptN, scryptR, scryptP, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func chunkNonce(prefix []byte, counter uint64) []byte {
+	nonce := make([]byte, noncePrefixSize+8)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint64(nonce[noncePrefixSize:], counter)
+	return nonce
+}
+
+func chunkFlags(final bool) []byte {
+	if final {
+		return []byte{chunkFlagFinal}
+	}
+	return []byte{0}
+}
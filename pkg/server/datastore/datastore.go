@@ -38,8 +38,13 @@ type DataStore interface {
 	FetchRegistrationEntry(ctx context.Context, entryID string) (*common.RegistrationEntry, error)
 	ListRegistrationEntries(context.Context, *ListRegistrationEntriesRequest) (*ListRegistrationEntriesResponse, error)
 	PruneRegistrationEntries(ctx context.Context, expiresBefore time.Time) error
+	RestoreRegistrationEntry(context.Context, *common.RegistrationEntry) (*common.RegistrationEntry, error)
 	UpdateRegistrationEntry(context.Context, *common.RegistrationEntry, *common.RegistrationEntryMask) (*common.RegistrationEntry, error)
 
+	// Entries Events
+	ListRegistrationEntriesEvents(context.Context, *ListRegistrationEntriesEventsRequest) (*ListRegistrationEntriesEventsResponse, error)
+	PruneRegistrationEntriesEvents(ctx context.Context, olderThan time.Duration) error
+
 	// Nodes
 	CountAttestedNodes(context.Context) (int32, error)
 	CreateAttestedNode(context.Context, *common.AttestedNode) (*common.AttestedNode, error)
@@ -48,6 +53,10 @@ type DataStore interface {
 	ListAttestedNodes(context.Context, *ListAttestedNodesRequest) (*ListAttestedNodesResponse, error)
 	UpdateAttestedNode(context.Context, *common.AttestedNode, *common.AttestedNodeMask) (*common.AttestedNode, error)
 
+	// Nodes Events
+	ListAttestedNodesEvents(context.Context, *ListAttestedNodesEventsRequest) (*ListAttestedNodesEventsResponse, error)
+	PruneAttestedNodesEvents(ctx context.Context, olderThan time.Duration) error
+
 	// Node selectors
 	GetNodeSelectors(ctx context.Context, spiffeID string, dataConsistency DataConsistency) ([]*common.Selector, error)
 	ListNodeSelectors(context.Context, *ListNodeSelectorsRequest) (*ListNodeSelectorsResponse, error)
@@ -57,6 +66,7 @@ type DataStore interface {
 	CreateJoinToken(context.Context, *JoinToken) error
 	DeleteJoinToken(ctx context.Context, token string) error
 	FetchJoinToken(ctx context.Context, token string) (*JoinToken, error)
+	ListJoinTokens(context.Context, *ListJoinTokensRequest) (*ListJoinTokensResponse, error)
 	PruneJoinTokens(context.Context, time.Time) error
 
 	// Federation Relationships
@@ -115,6 +125,45 @@ const (
 	MatchAny MatchBehavior = 3
 )
 
+// NodeSortField is the field attested nodes are sorted by when listed.
+type NodeSortField int32
+
+const (
+	// SortNodesByID sorts attested nodes in the order they were created
+	SortNodesByID NodeSortField = iota
+
+	// SortNodesBySpiffeID sorts attested nodes by SPIFFE ID
+	SortNodesBySpiffeID
+
+	// SortNodesByAttestationType sorts attested nodes by attestation type
+	SortNodesByAttestationType
+
+	// SortNodesByExpiresAt sorts attested nodes by the expiration of their
+	// SVID
+	SortNodesByExpiresAt
+
+	// SortNodesByLastSeenAt sorts attested nodes by the last time they were
+	// seen by the server
+	SortNodesByLastSeenAt
+)
+
+func (f NodeSortField) String() string {
+	switch f {
+	case SortNodesByID:
+		return "ID"
+	case SortNodesBySpiffeID:
+		return "SPIFFE_ID"
+	case SortNodesByAttestationType:
+		return "ATTESTATION_TYPE"
+	case SortNodesByExpiresAt:
+		return "EXPIRES_AT"
+	case SortNodesByLastSeenAt:
+		return "LAST_SEEN_AT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
 type ByFederatesWith struct {
 	TrustDomains []string
 	Match        MatchBehavior
@@ -143,6 +192,15 @@ type ListAttestedNodesRequest struct {
 	FetchSelectors    bool
 	Pagination        *Pagination
 	ByCanReattest     *bool
+	ByAgentVersion    string
+	ByLastSeenBefore  time.Time
+
+	// SortBy and SortDescending set the order of the listed nodes. Ties are
+	// broken by ID. Pagination tokens are keyed on the sort field and ID of
+	// the last node listed, so the pages are not shifted by nodes created or
+	// deleted while paginating.
+	SortBy         NodeSortField
+	SortDescending bool
 }
 
 type ListAttestedNodesResponse struct {
@@ -150,6 +208,21 @@ type ListAttestedNodesResponse struct {
 	Pagination *Pagination
 }
 
+type ListAttestedNodesEventsRequest struct {
+	GreaterThanEventID uint
+}
+
+// AttestedNodeEvent records that the attested node identified by SpiffeID
+// was created, updated or deleted.
+type AttestedNodeEvent struct {
+	EventID  uint
+	SpiffeID string
+}
+
+type ListAttestedNodesEventsResponse struct {
+	Events []AttestedNodeEvent
+}
+
 type ListBundlesRequest struct {
 	Pagination *Pagination
 }
@@ -159,6 +232,15 @@ type ListBundlesResponse struct {
 	Pagination *Pagination
 }
 
+type ListJoinTokensRequest struct {
+	Pagination *Pagination
+}
+
+type ListJoinTokensResponse struct {
+	JoinTokens []*JoinToken
+	Pagination *Pagination
+}
+
 type ListNodeSelectorsRequest struct {
 	DataConsistency DataConsistency
 	ValidAt         time.Time
@@ -176,6 +258,22 @@ type ListRegistrationEntriesRequest struct {
 	Pagination      *Pagination
 	ByFederatesWith *ByFederatesWith
 	ByHint          string
+
+	// BySpiffeIDPrefix filters entries to those with a SPIFFE ID starting
+	// with the given prefix (e.g. "spiffe://example.org/ns/prod/").
+	BySpiffeIDPrefix string
+	// BySelectorType filters entries to those having at least one selector
+	// of the given type, whatever its value.
+	BySelectorType string
+	ByDownstream   *bool
+	ByAdmin        *bool
+	// ByExpiresBefore and ByExpiresAfter filter entries to those expiring
+	// before or after the given time. Entries that never expire are not
+	// matched by either filter.
+	ByExpiresBefore time.Time
+	ByExpiresAfter  time.Time
+	ByX509SVIDTTL   int32
+	ByJWTSVIDTTL    int32
 }
 
 type ListRegistrationEntriesResponse struct {
@@ -183,6 +281,21 @@ type ListRegistrationEntriesResponse struct {
 	Pagination *Pagination
 }
 
+type ListRegistrationEntriesEventsRequest struct {
+	GreaterThanEventID uint
+}
+
+// RegistrationEntryEvent records that the registration entry identified by
+// EntryID was created, updated or deleted.
+type RegistrationEntryEvent struct {
+	EventID uint
+	EntryID string
+}
+
+type ListRegistrationEntriesEventsResponse struct {
+	Events []RegistrationEntryEvent
+}
+
 type ListFederationRelationshipsRequest struct {
 	Pagination *Pagination
 }
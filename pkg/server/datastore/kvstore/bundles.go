@@ -0,0 +1,570 @@
+package kvstore
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/spiffe/spire/pkg/common/bundleutil"
+	"github.com/spiffe/spire/pkg/common/cryptoutil"
+	"github.com/spiffe/spire/pkg/common/protoutil"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// CreateBundle stores the given bundle
+func (ds *Plugin) CreateBundle(ctx context.Context, b *common.Bundle) (bundle *common.Bundle, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		bundle, err = createBundle(tx, b)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// UpdateBundle updates an existing bundle with the given CAs. Overwrites any
+// existing certificates.
+func (ds *Plugin) UpdateBundle(ctx context.Context, b *common.Bundle, mask *common.BundleMask) (bundle *common.Bundle, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		bundle, err = updateBundle(tx, b, mask)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// SetBundle sets bundle contents. If no bundle exists for the trust domain, it is created.
+func (ds *Plugin) SetBundle(ctx context.Context, b *common.Bundle) (bundle *common.Bundle, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		bundle, err = setBundle(tx, b)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// AppendBundle append bundle contents to the existing bundle (by trust domain). If no existing one is present, create it.
+func (ds *Plugin) AppendBundle(ctx context.Context, b *common.Bundle) (bundle *common.Bundle, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		bundle, err = appendBundle(tx, b)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+// DeleteBundle deletes the bundle with the matching TrustDomain. Any CACert data passed is ignored.
+func (ds *Plugin) DeleteBundle(ctx context.Context, trustDomainID string, mode datastore.DeleteMode) error {
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return deleteBundle(tx, trustDomainID, mode)
+	})
+}
+
+// FetchBundle returns the bundle matching the specified Trust Domain.
+func (ds *Plugin) FetchBundle(ctx context.Context, trustDomainID string) (resp *common.Bundle, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) (err error) {
+		resp, err = fetchBundle(tx, trustDomainID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// CountBundles can be used to count all existing bundles.
+func (ds *Plugin) CountBundles(ctx context.Context) (count int32, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) error {
+		count = int32(tx.Bucket(bundlesBucket).Stats().KeyN)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListBundles can be used to fetch all existing bundles.
+func (ds *Plugin) ListBundles(ctx context.Context, req *datastore.ListBundlesRequest) (resp *datastore.ListBundlesResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) (err error) {
+		resp, err = listBundles(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PruneBundle removes expired certs and keys from a bundle
+func (ds *Plugin) PruneBundle(ctx context.Context, trustDomainID string, expiresBefore time.Time) (changed bool, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		changed, err = ds.pruneBundle(tx, trustDomainID, expiresBefore)
+		return err
+	}); err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+// TaintX509CA taints an X.509 CA signed using the provided public key
+func (ds *Plugin) TaintX509CA(ctx context.Context, trustDomainID string, publicKey crypto.PublicKey) error {
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return taintX509CA(tx, trustDomainID, publicKey)
+	})
+}
+
+// RevokeX509CA removes a Root CA from the bundle
+func (ds *Plugin) RevokeX509CA(ctx context.Context, trustDomainID string, publicKey crypto.PublicKey) error {
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return revokeX509CA(tx, trustDomainID, publicKey)
+	})
+}
+
+// TaintJWTKey taints a JWT Authority key
+func (ds *Plugin) TaintJWTKey(ctx context.Context, trustDomainID string, keyID string) (taintedKey *common.PublicKey, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		taintedKey, err = taintJWTKey(tx, trustDomainID, keyID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return taintedKey, nil
+}
+
+// RevokeJWTKey removes JWT key from the bundle
+func (ds *Plugin) RevokeJWTKey(ctx context.Context, trustDomainID string, keyID string) (revokedKey *common.PublicKey, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		revokedKey, err = revokeJWTKey(tx, trustDomainID, keyID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return revokedKey, nil
+}
+
+func createBundle(tx *bolt.Tx, bundle *common.Bundle) (*common.Bundle, error) {
+	data, err := marshalBundle(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	index := tx.Bucket(bundlesByTrustDomainBucket)
+	if index.Get([]byte(bundle.TrustDomainId)) != nil {
+		return nil, kvError.Wrap(errAlreadyExists)
+	}
+
+	id, err := putNew(tx.Bucket(bundlesBucket), data)
+	if err != nil {
+		return nil, err
+	}
+	if err := index.Put([]byte(bundle.TrustDomainId), itob(id)); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+
+	return bundle, nil
+}
+
+func updateBundle(tx *bolt.Tx, newBundle *common.Bundle, mask *common.BundleMask) (*common.Bundle, error) {
+	if newBundle == nil {
+		return nil, kvError.New("missing bundle in request")
+	}
+
+	id, bundle, err := getBundle(tx, newBundle.TrustDomainId)
+	if err != nil {
+		return nil, err
+	}
+
+	if mask == nil {
+		mask = protoutil.AllTrueCommonBundleMask
+	}
+	if mask.RefreshHint {
+		bundle.RefreshHint = newBundle.RefreshHint
+	}
+	if mask.RootCas {
+		bundle.RootCas = newBundle.RootCas
+	}
+	if mask.JwtSigningKeys {
+		bundle.JwtSigningKeys = newBundle.JwtSigningKeys
+	}
+	if mask.SequenceNumber {
+		bundle.SequenceNumber = newBundle.SequenceNumber
+	}
+
+	if err := putBundle(tx, id, bundle); err != nil {
+		return nil, err
+	}
+
+	return bundle, nil
+}
+
+func setBundle(tx *bolt.Tx, b *common.Bundle) (*common.Bundle, error) {
+	if b == nil {
+		return nil, kvError.New("missing bundle in request")
+	}
+
+	if tx.Bucket(bundlesByTrustDomainBucket).Get([]byte(b.TrustDomainId)) == nil {
+		return createBundle(tx, b)
+	}
+	return updateBundle(tx, b, nil)
+}
+
+func appendBundle(tx *bolt.Tx, b *common.Bundle) (*common.Bundle, error) {
+	if b == nil {
+		return nil, kvError.New("missing bundle in request")
+	}
+
+	if tx.Bucket(bundlesByTrustDomainBucket).Get([]byte(b.TrustDomainId)) == nil {
+		return createBundle(tx, b)
+	}
+
+	id, bundle, err := getBundle(tx, b.TrustDomainId)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, changed := bundleutil.MergeBundles(bundle, b)
+	if changed {
+		bundle.SequenceNumber++
+		if err := putBundle(tx, id, bundle); err != nil {
+			return nil, err
+		}
+	}
+
+	return bundle, nil
+}
+
+func deleteBundle(tx *bolt.Tx, trustDomainID string, mode datastore.DeleteMode) error {
+	id, _, err := getBundle(tx, trustDomainID)
+	if err != nil {
+		return err
+	}
+
+	var federatedEntries []*common.RegistrationEntry
+	if err := forEachEntry(tx, 0, func(_ uint64, entry *common.RegistrationEntry) (bool, error) {
+		for _, td := range entry.FederatesWith {
+			if td == trustDomainID {
+				federatedEntries = append(federatedEntries, entry)
+				break
+			}
+		}
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	if len(federatedEntries) > 0 {
+		switch mode {
+		case datastore.Delete:
+			for _, entry := range federatedEntries {
+				if err := removeEntry(tx, entry.EntryId); err != nil {
+					return err
+				}
+			}
+		case datastore.Dissociate:
+			for _, entry := range federatedEntries {
+				federatesWith := make([]string, 0, len(entry.FederatesWith)-1)
+				for _, td := range entry.FederatesWith {
+					if td != trustDomainID {
+						federatesWith = append(federatesWith, td)
+					}
+				}
+				entry.FederatesWith = federatesWith
+				if err := putEntry(tx, entry); err != nil {
+					return err
+				}
+			}
+		default:
+			return status.Newf(codes.FailedPrecondition, "datastore-kv: cannot delete bundle; federated with %d registration entries", len(federatedEntries)).Err()
+		}
+
+		// Both deleting and dissociating change the associated entries, so
+		// events need to be recorded for them
+		for _, entry := range federatedEntries {
+			if err := createRegistrationEntryEvent(tx, entry.EntryId); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tx.Bucket(bundlesBucket).Delete(itob(id)); err != nil {
+		return kvError.Wrap(err)
+	}
+	if err := tx.Bucket(bundlesByTrustDomainBucket).Delete([]byte(trustDomainID)); err != nil {
+		return kvError.Wrap(err)
+	}
+
+	return nil
+}
+
+// fetchBundle returns the bundle matching the specified Trust Domain, or nil
+// if there is no such bundle.
+func fetchBundle(tx *bolt.Tx, trustDomainID string) (*common.Bundle, error) {
+	if tx.Bucket(bundlesByTrustDomainBucket).Get([]byte(trustDomainID)) == nil {
+		return nil, nil
+	}
+	_, bundle, err := getBundle(tx, trustDomainID)
+	return bundle, err
+}
+
+func listBundles(tx *bolt.Tx, req *datastore.ListBundlesRequest) (*datastore.ListBundlesResponse, error) {
+	p := req.Pagination
+	var after uint64
+	if p != nil {
+		var err error
+		after, err = parsePagination(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &datastore.ListBundlesResponse{
+		Pagination: p,
+	}
+	var lastID uint64
+	if err := forEachAfter(tx.Bucket(bundlesBucket), after, func(id uint64, v []byte) (bool, error) {
+		bundle, err := unmarshalBundle(v)
+		if err != nil {
+			return false, err
+		}
+		resp.Bundles = append(resp.Bundles, bundle)
+		lastID = id
+		return p == nil || len(resp.Bundles) < int(p.PageSize), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if p != nil {
+		p.Token = ""
+		if len(resp.Bundles) > 0 {
+			p.Token = fmt.Sprint(lastID)
+		}
+	}
+
+	return resp, nil
+}
+
+func (ds *Plugin) pruneBundle(tx *bolt.Tx, trustDomainID string, expiry time.Time) (bool, error) {
+	currentBundle, err := fetchBundle(tx, trustDomainID)
+	if err != nil {
+		return false, fmt.Errorf("unable to fetch current bundle: %w", err)
+	}
+
+	if currentBundle == nil {
+		// No bundle to prune
+		return false, nil
+	}
+
+	newBundle, changed, err := bundleutil.PruneBundle(currentBundle, expiry, ds.log)
+	if err != nil {
+		return false, fmt.Errorf("prune failed: %w", err)
+	}
+
+	// Update only if bundle was modified
+	if changed {
+		newBundle.SequenceNumber = currentBundle.SequenceNumber + 1
+		if _, err := updateBundle(tx, newBundle, nil); err != nil {
+			return false, fmt.Errorf("unable to write new bundle: %w", err)
+		}
+	}
+
+	return changed, nil
+}
+
+func taintX509CA(tx *bolt.Tx, trustDomainID string, taintedPublicKey crypto.PublicKey) error {
+	id, bundle, err := getBundle(tx, trustDomainID)
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for _, ca := range bundle.RootCas {
+		rootCACert, err := x509.ParseCertificate(ca.DerBytes)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to parse root CA: %v", err)
+		}
+
+		ok, err := cryptoutil.PublicKeyEqual(rootCACert.PublicKey, taintedPublicKey)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to compare public key: %v", err)
+		}
+		if ok {
+			if ca.TaintedKey {
+				return status.Errorf(codes.InvalidArgument, "root CA is already tainted")
+			}
+
+			// Keep looking so every root CA associated with the given
+			// public key is tainted.
+			found = true
+			ca.TaintedKey = true
+		}
+	}
+
+	if !found {
+		return status.Error(codes.NotFound, "no root CA found with provided public key")
+	}
+
+	return putBundle(tx, id, bundle)
+}
+
+func revokeX509CA(tx *bolt.Tx, trustDomainID string, publicKey crypto.PublicKey) error {
+	id, bundle, err := getBundle(tx, trustDomainID)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	var rootCAs []*common.Certificate
+	for _, ca := range bundle.RootCas {
+		cert, err := x509.ParseCertificate(ca.DerBytes)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to parse root CA: %v", err)
+		}
+
+		ok, err := cryptoutil.PublicKeyEqual(cert.PublicKey, publicKey)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to compare public key: %v", err)
+		}
+
+		if ok {
+			if !ca.TaintedKey {
+				return status.Error(codes.InvalidArgument, "it is not possible to revoke an untainted root CA")
+			}
+			found = true
+			continue
+		}
+		rootCAs = append(rootCAs, ca)
+	}
+	bundle.RootCas = rootCAs
+
+	if !found {
+		return status.Error(codes.NotFound, "no root CA found with provided public key")
+	}
+
+	if err := putBundle(tx, id, bundle); err != nil {
+		return status.Errorf(codes.Internal, "failed to update bundle: %v", err)
+	}
+
+	return nil
+}
+
+func taintJWTKey(tx *bolt.Tx, trustDomainID string, keyID string) (*common.PublicKey, error) {
+	id, bundle, err := getBundle(tx, trustDomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var taintedKey *common.PublicKey
+	for _, jwtKey := range bundle.JwtSigningKeys {
+		if jwtKey.Kid != keyID {
+			continue
+		}
+
+		if jwtKey.TaintedKey {
+			return nil, status.Error(codes.InvalidArgument, "key is already tainted")
+		}
+
+		// Purely defensive, since repeated key IDs are not allowed.
+		if taintedKey != nil {
+			return nil, status.Error(codes.Internal, "another JWT Key found with the same KeyID")
+		}
+		taintedKey = jwtKey
+		jwtKey.TaintedKey = true
+	}
+
+	if taintedKey == nil {
+		return nil, status.Error(codes.NotFound, "no JWT Key found with provided key ID")
+	}
+
+	if err := putBundle(tx, id, bundle); err != nil {
+		return nil, err
+	}
+
+	return taintedKey, nil
+}
+
+func revokeJWTKey(tx *bolt.Tx, trustDomainID string, keyID string) (*common.PublicKey, error) {
+	id, bundle, err := getBundle(tx, trustDomainID)
+	if err != nil {
+		return nil, err
+	}
+
+	var publicKeys []*common.PublicKey
+	var revokedKey *common.PublicKey
+	for _, key := range bundle.JwtSigningKeys {
+		if key.Kid == keyID {
+			// Purely defensive, since repeated key IDs are not allowed.
+			if revokedKey != nil {
+				return nil, status.Error(codes.Internal, "another key found with the same KeyID")
+			}
+
+			if !key.TaintedKey {
+				return nil, status.Error(codes.InvalidArgument, "it is not possible to revoke an untainted key")
+			}
+
+			revokedKey = key
+			continue
+		}
+		publicKeys = append(publicKeys, key)
+	}
+	bundle.JwtSigningKeys = publicKeys
+
+	if revokedKey == nil {
+		return nil, status.Error(codes.NotFound, "no JWT Key found with provided key ID")
+	}
+
+	if err := putBundle(tx, id, bundle); err != nil {
+		return nil, err
+	}
+
+	return revokedKey, nil
+}
+
+// getBundle returns the ID and contents of the bundle for the trust domain,
+// failing with a not found error if it does not exist.
+func getBundle(tx *bolt.Tx, trustDomainID string) (uint64, *common.Bundle, error) {
+	key := tx.Bucket(bundlesByTrustDomainBucket).Get([]byte(trustDomainID))
+	if key == nil {
+		return 0, nil, kvError.Wrap(errNotFound)
+	}
+
+	bundle, err := unmarshalBundle(tx.Bucket(bundlesBucket).Get(key))
+	if err != nil {
+		return 0, nil, err
+	}
+	return btoi(key), bundle, nil
+}
+
+func putBundle(tx *bolt.Tx, id uint64, bundle *common.Bundle) error {
+	data, err := marshalBundle(bundle)
+	if err != nil {
+		return err
+	}
+	return kvError.Wrap(tx.Bucket(bundlesBucket).Put(itob(id), data))
+}
+
+func marshalBundle(bundle *common.Bundle) ([]byte, error) {
+	if bundle == nil {
+		return nil, kvError.New("missing bundle in request")
+	}
+	data, err := proto.Marshal(proto.Clone(bundle))
+	if err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	return data, nil
+}
+
+func unmarshalBundle(data []byte) (*common.Bundle, error) {
+	bundle := new(common.Bundle)
+	if err := proto.Unmarshal(data, bundle); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,798 @@
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// CreateRegistrationEntry stores the given registration entry
+func (ds *Plugin) CreateRegistrationEntry(ctx context.Context, entry *common.RegistrationEntry) (*common.RegistrationEntry, error) {
+	out, _, err := ds.createOrReturnRegistrationEntry(ctx, entry)
+	return out, err
+}
+
+// CreateOrReturnRegistrationEntry stores the given registration entry. If an
+// entry already exists with the same (parentID, spiffeID, selector) tuple,
+// that entry is returned instead.
+func (ds *Plugin) CreateOrReturnRegistrationEntry(ctx context.Context, entry *common.RegistrationEntry) (*common.RegistrationEntry, bool, error) {
+	return ds.createOrReturnRegistrationEntry(ctx, entry)
+}
+
+func (ds *Plugin) createOrReturnRegistrationEntry(ctx context.Context, entry *common.RegistrationEntry) (registrationEntry *common.RegistrationEntry, existing bool, err error) {
+	if err = validateRegistrationEntry(entry); err != nil {
+		return nil, false, err
+	}
+
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		registrationEntry, err = lookupSimilarEntry(tx, entry)
+		if err != nil {
+			return err
+		}
+		if registrationEntry != nil {
+			existing = true
+			return nil
+		}
+		registrationEntry, err = createRegistrationEntry(tx, entry)
+		return err
+	}); err != nil {
+		return nil, false, err
+	}
+	return registrationEntry, existing, nil
+}
+
+// RestoreRegistrationEntry stores the given registration entry, keeping its
+// entry ID and revision number. It is meant for restoring exported entries.
+// An error with the AlreadyExists code is returned if an entry with the same
+// entry ID or the same (parentID, spiffeID, selector) tuple already exists.
+func (ds *Plugin) RestoreRegistrationEntry(ctx context.Context, entry *common.RegistrationEntry) (registrationEntry *common.RegistrationEntry, err error) {
+	if err = validateRegistrationEntry(entry); err != nil {
+		return nil, err
+	}
+	if entry.EntryId == "" {
+		return nil, kvError.New("invalid registration entry: missing entry ID")
+	}
+
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		registrationEntry, err = restoreRegistrationEntry(tx, entry)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return registrationEntry, nil
+}
+
+// FetchRegistrationEntry fetches an existing registration by entry ID
+func (ds *Plugin) FetchRegistrationEntry(ctx context.Context, entryID string) (entry *common.RegistrationEntry, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) error {
+		_, entry, err = getEntry(tx, entryID)
+		if errors.Is(err, errNotFound) {
+			entry, err = nil, nil
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// CountRegistrationEntries counts all registrations
+func (ds *Plugin) CountRegistrationEntries(ctx context.Context) (count int32, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) error {
+		count = int32(tx.Bucket(entriesBucket).Stats().KeyN)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListRegistrationEntries lists all registrations (pagination available)
+func (ds *Plugin) ListRegistrationEntries(ctx context.Context, req *datastore.ListRegistrationEntriesRequest) (resp *datastore.ListRegistrationEntriesResponse, err error) {
+	if req.Pagination != nil && req.Pagination.PageSize == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cannot paginate with pagesize = 0")
+	}
+	if req.BySelectors != nil && len(req.BySelectors.Selectors) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cannot list by empty selector set")
+	}
+
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) (err error) {
+		resp, err = ds.listRegistrationEntries(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateRegistrationEntry updates an existing registration entry
+func (ds *Plugin) UpdateRegistrationEntry(ctx context.Context, e *common.RegistrationEntry, mask *common.RegistrationEntryMask) (entry *common.RegistrationEntry, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		entry, err = updateRegistrationEntry(tx, e, mask)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteRegistrationEntry deletes the given registration
+func (ds *Plugin) DeleteRegistrationEntry(ctx context.Context, entryID string) (registrationEntry *common.RegistrationEntry, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		_, registrationEntry, err = getEntry(tx, entryID)
+		if err != nil {
+			return err
+		}
+		return deleteRegistrationEntry(tx, entryID)
+	}); err != nil {
+		return nil, err
+	}
+	return registrationEntry, nil
+}
+
+// PruneRegistrationEntries takes a registration entry message, and deletes all entries which have expired
+// before the date in the message
+func (ds *Plugin) PruneRegistrationEntries(ctx context.Context, expiresBefore time.Time) error {
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return ds.pruneRegistrationEntries(tx, expiresBefore)
+	})
+}
+
+// ListRegistrationEntriesEvents lists the registration entry events that
+// happened after the given event ID
+func (ds *Plugin) ListRegistrationEntriesEvents(ctx context.Context, req *datastore.ListRegistrationEntriesEventsRequest) (resp *datastore.ListRegistrationEntriesEventsResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) error {
+		resp = &datastore.ListRegistrationEntriesEventsResponse{}
+		return forEachEvent(tx.Bucket(entryEventsBucket), req.GreaterThanEventID, func(id uint, event *eventRecord) {
+			resp.Events = append(resp.Events, datastore.RegistrationEntryEvent{
+				EventID: id,
+				EntryID: event.ID,
+			})
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PruneRegistrationEntriesEvents deletes all registration entry events older
+// than the given duration
+func (ds *Plugin) PruneRegistrationEntriesEvents(ctx context.Context, olderThan time.Duration) error {
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return pruneEvents(tx.Bucket(entryEventsBucket), ds.clock.Now().Add(-olderThan))
+	})
+}
+
+func createRegistrationEntry(tx *bolt.Tx, entry *common.RegistrationEntry) (*common.RegistrationEntry, error) {
+	entryID, err := newRegistrationEntryID()
+	if err != nil {
+		return nil, err
+	}
+
+	return insertRegistrationEntry(tx, entry, entryID, 0)
+}
+
+func restoreRegistrationEntry(tx *bolt.Tx, entry *common.RegistrationEntry) (*common.RegistrationEntry, error) {
+	if tx.Bucket(entriesByEntryIDBucket).Get([]byte(entry.EntryId)) != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "registration entry %q already exists", entry.EntryId)
+	}
+
+	similarEntry, err := lookupSimilarEntry(tx, entry)
+	if err != nil {
+		return nil, err
+	}
+	if similarEntry != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "registration entry %q is similar to the existing entry %q", entry.EntryId, similarEntry.EntryId)
+	}
+
+	return insertRegistrationEntry(tx, entry, entry.EntryId, entry.RevisionNumber)
+}
+
+func insertRegistrationEntry(tx *bolt.Tx, entry *common.RegistrationEntry, entryID string, revisionNumber int64) (*common.RegistrationEntry, error) {
+	federatesWith, err := makeFederatesWith(tx, entry.FederatesWith)
+	if err != nil {
+		return nil, err
+	}
+
+	newEntry := &common.RegistrationEntry{
+		EntryId:        entryID,
+		SpiffeId:       entry.SpiffeId,
+		ParentId:       entry.ParentId,
+		X509SvidTtl:    entry.X509SvidTtl,
+		JwtSvidTtl:     entry.JwtSvidTtl,
+		Admin:          entry.Admin,
+		Downstream:     entry.Downstream,
+		EntryExpiry:    entry.EntryExpiry,
+		RevisionNumber: revisionNumber,
+		StoreSvid:      entry.StoreSvid,
+		Hint:           entry.Hint,
+		FederatesWith:  federatesWith,
+		CreatedAt:      roundedInSecondsUnix(time.Now()),
+	}
+	if newEntry.Selectors, err = copySelectors(entry.Selectors); err != nil {
+		return nil, err
+	}
+	if newEntry.DnsNames, err = copyDNSNames(entry.DnsNames); err != nil {
+		return nil, err
+	}
+
+	data, err := marshalEntry(newEntry)
+	if err != nil {
+		return nil, err
+	}
+	id, err := putNew(tx.Bucket(entriesBucket), data)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Bucket(entriesByEntryIDBucket).Put([]byte(entryID), itob(id)); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+
+	if err := createRegistrationEntryEvent(tx, entryID); err != nil {
+		return nil, err
+	}
+
+	return newEntry, nil
+}
+
+func (ds *Plugin) listRegistrationEntries(tx *bolt.Tx, req *datastore.ListRegistrationEntriesRequest) (*datastore.ListRegistrationEntriesResponse, error) {
+	// Exact/subset selector matching requires filtering out the entries that
+	// have selectors not represented in the request, which happens after a
+	// page has been gathered. It's possible that a page ends up completely
+	// filtered out. If that happens, keep listing until a page gets at least
+	// one result.
+	for {
+		resp, err := listRegistrationEntriesOnce(tx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.BySelectors == nil || len(resp.Entries) == 0 {
+			return resp, nil
+		}
+
+		switch req.BySelectors.Match {
+		case datastore.Exact, datastore.Subset:
+			resp.Entries = filterEntriesBySelectorSet(resp.Entries, req.BySelectors.Selectors)
+		default:
+		}
+
+		if len(resp.Entries) > 0 || resp.Pagination == nil || len(resp.Pagination.Token) == 0 {
+			return resp, nil
+		}
+
+		if resp.Pagination.Token == req.Pagination.Token {
+			// This check is purely defensive, a request with a given token
+			// should never yield that same token.
+			ds.log.Warn("Filtered registration entry pagination would recurse. Please report this bug.")
+			resp.Pagination.Token = ""
+			return resp, nil
+		}
+
+		req.Pagination = resp.Pagination
+	}
+}
+
+func listRegistrationEntriesOnce(tx *bolt.Tx, req *datastore.ListRegistrationEntriesRequest) (*datastore.ListRegistrationEntriesResponse, error) {
+	var after uint64
+	if req.Pagination != nil {
+		var err error
+		after, err = parsePagination(req.Pagination)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	matches, err := makeEntryMatcher(req)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*common.RegistrationEntry, 0, 64)
+	var lastID uint64
+	if err := forEachEntry(tx, after, func(id uint64, entry *common.RegistrationEntry) (bool, error) {
+		if !matches(entry) {
+			return true, nil
+		}
+		entries = append(entries, entry)
+		lastID = id
+		return req.Pagination == nil || len(entries) < int(req.Pagination.PageSize), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	resp := &datastore.ListRegistrationEntriesResponse{
+		Entries: entries,
+	}
+
+	if req.Pagination != nil {
+		resp.Pagination = &datastore.Pagination{
+			PageSize: req.Pagination.PageSize,
+		}
+		if len(resp.Entries) > 0 {
+			resp.Pagination.Token = strconv.FormatUint(lastID, 10)
+		}
+	}
+
+	return resp, nil
+}
+
+// makeEntryMatcher returns a function that matches the entries satisfying
+// the filters in the request. Exact and subset selector matches also match
+// the entries having additional selectors, which are filtered out afterwards.
+func makeEntryMatcher(req *datastore.ListRegistrationEntriesRequest) (func(*common.RegistrationEntry) bool, error) {
+	var matchSelectors func([]*common.Selector) bool
+	if req.BySelectors != nil {
+		switch req.BySelectors.Match {
+		case datastore.Subset, datastore.MatchAny:
+			matchSelectors = func(ss []*common.Selector) bool {
+				return containsAnySelector(ss, req.BySelectors.Selectors)
+			}
+		case datastore.Exact, datastore.Superset:
+			matchSelectors = func(ss []*common.Selector) bool {
+				return containsAllSelectors(ss, req.BySelectors.Selectors)
+			}
+		default:
+			return nil, kvError.New("unhandled selectors match behavior %q", req.BySelectors.Match)
+		}
+	}
+
+	var matchFederatesWith func([]string) bool
+	if req.ByFederatesWith != nil && len(req.ByFederatesWith.TrustDomains) > 0 {
+		// Take the trust domains from the request without duplicates
+		tdSet := make(map[string]struct{})
+		for _, td := range req.ByFederatesWith.TrustDomains {
+			tdSet[td] = struct{}{}
+		}
+
+		// countInSet returns how many distinct trust domains of the entry are
+		// in the requested set, and whether all of them are.
+		countInSet := func(tds []string) (int, bool) {
+			found := make(map[string]struct{})
+			for _, td := range tds {
+				if _, ok := tdSet[td]; ok {
+					found[td] = struct{}{}
+				}
+			}
+			return len(found), len(found) == len(tds)
+		}
+
+		switch req.ByFederatesWith.Match {
+		case datastore.Subset:
+			matchFederatesWith = func(tds []string) bool {
+				n, isSubset := countInSet(tds)
+				return isSubset && n > 0
+			}
+		case datastore.Exact:
+			matchFederatesWith = func(tds []string) bool {
+				n, isSubset := countInSet(tds)
+				return isSubset && n == len(tdSet)
+			}
+		case datastore.MatchAny:
+			matchFederatesWith = func(tds []string) bool {
+				n, _ := countInSet(tds)
+				return n > 0
+			}
+		case datastore.Superset:
+			matchFederatesWith = func(tds []string) bool {
+				n, _ := countInSet(tds)
+				return n == len(tdSet)
+			}
+		default:
+			return nil, kvError.New("unhandled federates with match behavior %q", req.ByFederatesWith.Match)
+		}
+	}
+
+	return func(entry *common.RegistrationEntry) bool {
+		switch {
+		case req.ByParentID != "" && entry.ParentId != req.ByParentID:
+			return false
+		case req.BySpiffeID != "" && entry.SpiffeId != req.BySpiffeID:
+			return false
+		case req.ByHint != "" && entry.Hint != req.ByHint:
+			return false
+		case matchSelectors != nil && !matchSelectors(entry.Selectors):
+			return false
+		case matchFederatesWith != nil && (len(entry.FederatesWith) == 0 || !matchFederatesWith(entry.FederatesWith)):
+			return false
+		case req.BySpiffeIDPrefix != "" && !strings.HasPrefix(entry.SpiffeId, req.BySpiffeIDPrefix):
+			return false
+		case req.BySelectorType != "" && !containsSelectorType(entry.Selectors, req.BySelectorType):
+			return false
+		case req.ByDownstream != nil && entry.Downstream != *req.ByDownstream:
+			return false
+		case req.ByAdmin != nil && entry.Admin != *req.ByAdmin:
+			return false
+		case !req.ByExpiresBefore.IsZero() && (entry.EntryExpiry == 0 || entry.EntryExpiry >= req.ByExpiresBefore.Unix()):
+			return false
+		case !req.ByExpiresAfter.IsZero() && entry.EntryExpiry <= req.ByExpiresAfter.Unix():
+			return false
+		case req.ByX509SVIDTTL != 0 && entry.X509SvidTtl != req.ByX509SVIDTTL:
+			return false
+		case req.ByJWTSVIDTTL != 0 && entry.JwtSvidTtl != req.ByJWTSVIDTTL:
+			return false
+		default:
+			return true
+		}
+	}, nil
+}
+
+// containsSelectorType returns whether any of the selectors is of the given
+// type
+func containsSelectorType(selectors []*common.Selector, selectorType string) bool {
+	for _, s := range selectors {
+		if s.Type == selectorType {
+			return true
+		}
+	}
+	return false
+}
+
+// filterEntriesBySelectorSet filters out entries with selectors not in the set
+func filterEntriesBySelectorSet(entries []*common.RegistrationEntry, selectors []*common.Selector) []*common.RegistrationEntry {
+	filtered := make([]*common.RegistrationEntry, 0, len(entries))
+	for _, entry := range entries {
+		if containsAllSelectors(selectors, entry.Selectors) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+func updateRegistrationEntry(tx *bolt.Tx, e *common.RegistrationEntry, mask *common.RegistrationEntryMask) (*common.RegistrationEntry, error) {
+	if err := validateRegistrationEntryForUpdate(e, mask); err != nil {
+		return nil, err
+	}
+
+	_, entry, err := getEntry(tx, e.EntryId)
+	if err != nil {
+		return nil, err
+	}
+
+	if mask == nil || mask.StoreSvid {
+		entry.StoreSvid = e.StoreSvid
+	}
+	if mask == nil || mask.Selectors {
+		if entry.Selectors, err = copySelectors(e.Selectors); err != nil {
+			return nil, err
+		}
+
+		// Verify that final selectors contains the same 'type' when entry is used for store SVIDs
+		if entry.StoreSvid && !equalSelectorTypes(entry.Selectors) {
+			return nil, kvError.New("invalid registration entry: selector types must be the same when store SVID is enabled")
+		}
+	}
+	if mask == nil || mask.DnsNames {
+		if entry.DnsNames, err = copyDNSNames(e.DnsNames); err != nil {
+			return nil, err
+		}
+	}
+	if mask == nil || mask.SpiffeId {
+		entry.SpiffeId = e.SpiffeId
+	}
+	if mask == nil || mask.ParentId {
+		entry.ParentId = e.ParentId
+	}
+	if mask == nil || mask.X509SvidTtl {
+		entry.X509SvidTtl = e.X509SvidTtl
+	}
+	if mask == nil || mask.Admin {
+		entry.Admin = e.Admin
+	}
+	if mask == nil || mask.Downstream {
+		entry.Downstream = e.Downstream
+	}
+	if mask == nil || mask.EntryExpiry {
+		entry.EntryExpiry = e.EntryExpiry
+	}
+	if mask == nil || mask.JwtSvidTtl {
+		entry.JwtSvidTtl = e.JwtSvidTtl
+	}
+	if mask == nil || mask.Hint {
+		entry.Hint = e.Hint
+	}
+	if mask == nil || mask.FederatesWith {
+		if entry.FederatesWith, err = makeFederatesWith(tx, e.FederatesWith); err != nil {
+			return nil, err
+		}
+	}
+
+	// Revision number is increased by 1 on every update call
+	entry.RevisionNumber++
+
+	if err := putEntry(tx, entry); err != nil {
+		return nil, err
+	}
+
+	if err := createRegistrationEntryEvent(tx, entry.EntryId); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func deleteRegistrationEntry(tx *bolt.Tx, entryID string) error {
+	if err := removeEntry(tx, entryID); err != nil {
+		return err
+	}
+	return createRegistrationEntryEvent(tx, entryID)
+}
+
+func (ds *Plugin) pruneRegistrationEntries(tx *bolt.Tx, expiresBefore time.Time) error {
+	var expired []*common.RegistrationEntry
+	if err := forEachEntry(tx, 0, func(_ uint64, entry *common.RegistrationEntry) (bool, error) {
+		if entry.EntryExpiry != 0 && entry.EntryExpiry < expiresBefore.Unix() {
+			expired = append(expired, entry)
+		}
+		return true, nil
+	}); err != nil {
+		return err
+	}
+
+	for _, entry := range expired {
+		if err := deleteRegistrationEntry(tx, entry.EntryId); err != nil {
+			return err
+		}
+		ds.log.WithFields(logrus.Fields{
+			telemetry.SPIFFEID:       entry.SpiffeId,
+			telemetry.ParentID:       entry.ParentId,
+			telemetry.RegistrationID: entry.EntryId,
+		}).Info("Pruned an expired registration")
+	}
+
+	return nil
+}
+
+func lookupSimilarEntry(tx *bolt.Tx, entry *common.RegistrationEntry) (*common.RegistrationEntry, error) {
+	resp, err := listRegistrationEntriesOnce(tx, &datastore.ListRegistrationEntriesRequest{
+		BySpiffeID: entry.SpiffeId,
+		ByParentID: entry.ParentId,
+		BySelectors: &datastore.BySelectors{
+			Match:     datastore.Exact,
+			Selectors: entry.Selectors,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// listRegistrationEntriesOnce returns both exact and superset matches.
+	// Filter out the superset matches to get an exact match
+	entries := filterEntriesBySelectorSet(resp.Entries, entry.Selectors)
+	if len(entries) > 0 {
+		return entries[0], nil
+	}
+
+	return nil, nil
+}
+
+func createRegistrationEntryEvent(tx *bolt.Tx, entryID string) error {
+	return createEvent(tx.Bucket(entryEventsBucket), entryID)
+}
+
+// forEachEntry calls fn for each registration entry with an ID greater than
+// the given one, in ID order, until fn returns false.
+func forEachEntry(tx *bolt.Tx, after uint64, fn func(id uint64, entry *common.RegistrationEntry) (bool, error)) error {
+	return forEachAfter(tx.Bucket(entriesBucket), after, func(id uint64, v []byte) (bool, error) {
+		entry, err := unmarshalEntry(v)
+		if err != nil {
+			return false, err
+		}
+		return fn(id, entry)
+	})
+}
+
+// getEntry returns the ID and registration entry for the entry ID, failing
+// with a not found error if it does not exist.
+func getEntry(tx *bolt.Tx, entryID string) (uint64, *common.RegistrationEntry, error) {
+	key := tx.Bucket(entriesByEntryIDBucket).Get([]byte(entryID))
+	if key == nil {
+		return 0, nil, kvError.Wrap(errNotFound)
+	}
+
+	entry, err := unmarshalEntry(tx.Bucket(entriesBucket).Get(key))
+	if err != nil {
+		return 0, nil, err
+	}
+	return btoi(key), entry, nil
+}
+
+// putEntry overwrites the existing registration entry with the same entry ID.
+func putEntry(tx *bolt.Tx, entry *common.RegistrationEntry) error {
+	key := tx.Bucket(entriesByEntryIDBucket).Get([]byte(entry.EntryId))
+	if key == nil {
+		return kvError.Wrap(errNotFound)
+	}
+
+	data, err := marshalEntry(entry)
+	if err != nil {
+		return err
+	}
+	return kvError.Wrap(tx.Bucket(entriesBucket).Put(key, data))
+}
+
+// removeEntry removes the registration entry without recording an event.
+func removeEntry(tx *bolt.Tx, entryID string) error {
+	index := tx.Bucket(entriesByEntryIDBucket)
+	key := index.Get([]byte(entryID))
+	if key == nil {
+		return kvError.Wrap(errNotFound)
+	}
+
+	if err := tx.Bucket(entriesBucket).Delete(key); err != nil {
+		return kvError.Wrap(err)
+	}
+	return kvError.Wrap(index.Delete([]byte(entryID)))
+}
+
+// marshalEntry marshals a copy of the entry so the size cached by the
+// marshaling does not leak into the entry returned to the caller.
+func marshalEntry(entry *common.RegistrationEntry) ([]byte, error) {
+	data, err := proto.Marshal(proto.Clone(entry))
+	if err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	return data, nil
+}
+
+func unmarshalEntry(data []byte) (*common.RegistrationEntry, error) {
+	entry := new(common.RegistrationEntry)
+	if err := proto.Unmarshal(data, entry); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	return entry, nil
+}
+
+// makeFederatesWith returns the trust domains of the federated bundles,
+// ordered as the bundles were created, failing if any of them is missing.
+func makeFederatesWith(tx *bolt.Tx, ids []string) ([]string, error) {
+	index := tx.Bucket(bundlesByTrustDomainBucket)
+
+	bundleIDs := make(map[string]uint64, len(ids))
+	for _, id := range ids {
+		key := index.Get([]byte(id))
+		if key == nil {
+			return nil, fmt.Errorf("unable to find federated bundle %q", id)
+		}
+		bundleIDs[id] = btoi(key)
+	}
+
+	if len(bundleIDs) == 0 {
+		return nil, nil
+	}
+
+	federatesWith := make([]string, 0, len(bundleIDs))
+	for id := range bundleIDs {
+		federatesWith = append(federatesWith, id)
+	}
+	sort.Slice(federatesWith, func(i, j int) bool {
+		return bundleIDs[federatesWith[i]] < bundleIDs[federatesWith[j]]
+	})
+	return federatesWith, nil
+}
+
+// copySelectors copies the selectors, failing if any of them is repeated.
+func copySelectors(selectors []*common.Selector) ([]*common.Selector, error) {
+	out := make([]*common.Selector, 0, len(selectors))
+	for _, selector := range selectors {
+		if containsSelector(out, selector) {
+			return nil, kvError.Wrap(errAlreadyExists)
+		}
+		out = append(out, &common.Selector{
+			Type:  selector.Type,
+			Value: selector.Value,
+		})
+	}
+	return out, nil
+}
+
+// copyDNSNames copies the DNS names, failing if any of them is repeated.
+func copyDNSNames(dnsNames []string) ([]string, error) {
+	if len(dnsNames) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]struct{}, len(dnsNames))
+	out := make([]string, 0, len(dnsNames))
+	for _, dnsName := range dnsNames {
+		if _, ok := seen[dnsName]; ok {
+			return nil, kvError.Wrap(errAlreadyExists)
+		}
+		seen[dnsName] = struct{}{}
+		out = append(out, dnsName)
+	}
+	return out, nil
+}
+
+func validateRegistrationEntry(entry *common.RegistrationEntry) error {
+	if entry == nil {
+		return kvError.New("invalid request: missing registered entry")
+	}
+
+	if len(entry.Selectors) == 0 {
+		return kvError.New("invalid registration entry: missing selector list")
+	}
+
+	// In case of StoreSvid is set, all entries 'must' be the same type,
+	// it is done to avoid users to mix selectors from different platforms in
+	// entries with storable SVIDs
+	if entry.StoreSvid && !equalSelectorTypes(entry.Selectors) {
+		return kvError.New("invalid registration entry: selector types must be the same when store SVID is enabled")
+	}
+
+	if len(entry.SpiffeId) == 0 {
+		return kvError.New("invalid registration entry: missing SPIFFE ID")
+	}
+
+	if entry.X509SvidTtl < 0 {
+		return kvError.New("invalid registration entry: X509SvidTtl is not set")
+	}
+
+	if entry.JwtSvidTtl < 0 {
+		return kvError.New("invalid registration entry: JwtSvidTtl is not set")
+	}
+
+	return nil
+}
+
+func validateRegistrationEntryForUpdate(entry *common.RegistrationEntry, mask *common.RegistrationEntryMask) error {
+	if entry == nil {
+		return kvError.New("invalid request: missing registered entry")
+	}
+
+	if (mask == nil || mask.Selectors) && len(entry.Selectors) == 0 {
+		return kvError.New("invalid registration entry: missing selector list")
+	}
+
+	if (mask == nil || mask.SpiffeId) && entry.SpiffeId == "" {
+		return kvError.New("invalid registration entry: missing SPIFFE ID")
+	}
+
+	if (mask == nil || mask.X509SvidTtl) && entry.X509SvidTtl < 0 {
+		return kvError.New("invalid registration entry: X509SvidTtl is not set")
+	}
+
+	if (mask == nil || mask.JwtSvidTtl) && entry.JwtSvidTtl < 0 {
+		return kvError.New("invalid registration entry: JwtSvidTtl is not set")
+	}
+
+	return nil
+}
+
+// equalSelectorTypes validates that all selectors has the same type
+func equalSelectorTypes(selectors []*common.Selector) bool {
+	for _, s := range selectors {
+		if s.Type != selectors[0].Type {
+			return false
+		}
+	}
+	return true
+}
+
+func newRegistrationEntryID() (string, error) {
+	u, err := uuid.NewV4()
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// roundedInSecondsUnix rounds the time to the nearest second, and return the
+// time in seconds since the unix epoch.
+func roundedInSecondsUnix(t time.Time) int64 {
+	return t.Round(time.Second).Unix()
+}
@@ -0,0 +1,67 @@
+package kvstore
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// eventRecord is the stored form of both registration entry and attested
+// node events.
+type eventRecord struct {
+	// ID is the registration entry ID or the attested node SPIFFE ID,
+	// depending on the event bucket.
+	ID        string `json:"id"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+func createEvent(b *bolt.Bucket, id string) error {
+	data, err := json.Marshal(eventRecord{
+		ID:        id,
+		CreatedAt: time.Now().UnixNano(),
+	})
+	if err != nil {
+		return kvError.Wrap(err)
+	}
+	_, err = putNew(b, data)
+	return err
+}
+
+func forEachEvent(b *bolt.Bucket, greaterThan uint, fn func(id uint, event *eventRecord)) error {
+	return forEachAfter(b, uint64(greaterThan), func(id uint64, v []byte) (bool, error) {
+		event := new(eventRecord)
+		if err := json.Unmarshal(v, event); err != nil {
+			return false, kvError.Wrap(err)
+		}
+		fn(uint(id), event)
+		return true, nil
+	})
+}
+
+func pruneEvents(b *bolt.Bucket, createdBefore time.Time) error {
+	threshold := createdBefore.UnixNano()
+
+	var ids [][]byte
+	if err := b.ForEach(func(k, v []byte) error {
+		event := new(eventRecord)
+		if err := json.Unmarshal(v, event); err != nil {
+			return kvError.Wrap(err)
+		}
+		if event.CreatedAt < threshold {
+			// Keys are copied since they are only valid until the bucket
+			// is modified.
+			ids = append(ids, append([]byte(nil), k...))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := b.Delete(id); err != nil {
+			return kvError.Wrap(err)
+		}
+	}
+	return nil
+}
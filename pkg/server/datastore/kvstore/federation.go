@@ -0,0 +1,324 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/pkg/common/protoutil"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// federationRelationshipRecord is the stored form of a federation
+// relationship. The trust domain bundle is stored separately.
+type federationRelationshipRecord struct {
+	TrustDomain           string `json:"trust_domain"`
+	BundleEndpointURL     string `json:"bundle_endpoint_url"`
+	BundleEndpointProfile string `json:"bundle_endpoint_profile"`
+	EndpointSPIFFEID      string `json:"endpoint_spiffe_id,omitempty"`
+}
+
+// CreateFederationRelationship creates a new federation relationship. If the bundle endpoint
+// profile is 'https_spiffe' and the given federation relationship contains a bundle, the current
+// stored bundle is overridden.
+// If no bundle is provided and there is not a previously stored bundle in the datastore, the
+// federation relationship is not created.
+func (ds *Plugin) CreateFederationRelationship(ctx context.Context, fr *datastore.FederationRelationship) (newFr *datastore.FederationRelationship, err error) {
+	if err := validateFederationRelationship(fr, protoutil.AllTrueFederationRelationshipMask); err != nil {
+		return nil, err
+	}
+
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		newFr, err = createFederationRelationship(tx, fr)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return newFr, nil
+}
+
+// DeleteFederationRelationship deletes the federation relationship to the
+// given trust domain. The associated trust bundle is not deleted.
+func (ds *Plugin) DeleteFederationRelationship(ctx context.Context, trustDomain spiffeid.TrustDomain) error {
+	if trustDomain.IsZero() {
+		return status.Error(codes.InvalidArgument, "trust domain is required")
+	}
+
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return deleteFederationRelationship(tx, trustDomain)
+	})
+}
+
+// FetchFederationRelationship fetches the federation relationship that matches
+// the given trust domain. If the federation relationship is not found, nil is returned.
+func (ds *Plugin) FetchFederationRelationship(ctx context.Context, trustDomain spiffeid.TrustDomain) (fr *datastore.FederationRelationship, err error) {
+	if trustDomain.IsZero() {
+		return nil, status.Error(codes.InvalidArgument, "trust domain is required")
+	}
+
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) (err error) {
+		fr, err = fetchFederationRelationship(tx, trustDomain)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return fr, nil
+}
+
+// ListFederationRelationships can be used to list all existing federation relationships
+func (ds *Plugin) ListFederationRelationships(ctx context.Context, req *datastore.ListFederationRelationshipsRequest) (resp *datastore.ListFederationRelationshipsResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) (err error) {
+		resp, err = listFederationRelationships(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateFederationRelationship updates the given federation relationship.
+// Attributes are only updated if the correspondent mask value is set to true.
+func (ds *Plugin) UpdateFederationRelationship(ctx context.Context, fr *datastore.FederationRelationship, mask *types.FederationRelationshipMask) (newFr *datastore.FederationRelationship, err error) {
+	if err := validateFederationRelationship(fr, mask); err != nil {
+		return nil, err
+	}
+
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		newFr, err = updateFederationRelationship(tx, fr, mask)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return newFr, nil
+}
+
+func createFederationRelationship(tx *bolt.Tx, fr *datastore.FederationRelationship) (*datastore.FederationRelationship, error) {
+	index := tx.Bucket(federationRelationshipsByTDBucket)
+	if index.Get([]byte(fr.TrustDomain.Name())) != nil {
+		return nil, kvError.Wrap(errAlreadyExists)
+	}
+
+	record := &federationRelationshipRecord{
+		TrustDomain:           fr.TrustDomain.Name(),
+		BundleEndpointURL:     fr.BundleEndpointURL.String(),
+		BundleEndpointProfile: string(fr.BundleEndpointProfile),
+	}
+
+	if fr.BundleEndpointProfile == datastore.BundleEndpointSPIFFE {
+		record.EndpointSPIFFEID = fr.EndpointSPIFFEID.String()
+	}
+
+	if fr.TrustDomainBundle != nil {
+		// overwrite current bundle
+		_, err := setBundle(tx, fr.TrustDomainBundle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set bundle: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	id, err := putNew(tx.Bucket(federationRelationshipsBucket), data)
+	if err != nil {
+		return nil, err
+	}
+	if err := index.Put([]byte(record.TrustDomain), itob(id)); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+
+	return fr, nil
+}
+
+func deleteFederationRelationship(tx *bolt.Tx, trustDomain spiffeid.TrustDomain) error {
+	index := tx.Bucket(federationRelationshipsByTDBucket)
+	key := index.Get([]byte(trustDomain.Name()))
+	if key == nil {
+		return kvError.Wrap(errNotFound)
+	}
+
+	if err := tx.Bucket(federationRelationshipsBucket).Delete(key); err != nil {
+		return kvError.Wrap(err)
+	}
+	return kvError.Wrap(index.Delete([]byte(trustDomain.Name())))
+}
+
+func fetchFederationRelationship(tx *bolt.Tx, trustDomain spiffeid.TrustDomain) (*datastore.FederationRelationship, error) {
+	key := tx.Bucket(federationRelationshipsByTDBucket).Get([]byte(trustDomain.Name()))
+	if key == nil {
+		return nil, nil
+	}
+
+	record, err := unmarshalFederationRelationship(tx.Bucket(federationRelationshipsBucket).Get(key))
+	if err != nil {
+		return nil, err
+	}
+	return recordToFederationRelationship(tx, record)
+}
+
+// listFederationRelationships can be used to fetch all existing federation relationships.
+func listFederationRelationships(tx *bolt.Tx, req *datastore.ListFederationRelationshipsRequest) (*datastore.ListFederationRelationshipsResponse, error) {
+	p := req.Pagination
+	var after uint64
+	if p != nil {
+		var err error
+		after, err = parsePagination(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := &datastore.ListFederationRelationshipsResponse{
+		Pagination:              p,
+		FederationRelationships: []*datastore.FederationRelationship{},
+	}
+	var lastID uint64
+	if err := forEachAfter(tx.Bucket(federationRelationshipsBucket), after, func(id uint64, v []byte) (bool, error) {
+		record, err := unmarshalFederationRelationship(v)
+		if err != nil {
+			return false, err
+		}
+		fr, err := recordToFederationRelationship(tx, record)
+		if err != nil {
+			return false, err
+		}
+		resp.FederationRelationships = append(resp.FederationRelationships, fr)
+		lastID = id
+		return p == nil || len(resp.FederationRelationships) < int(p.PageSize), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if p != nil {
+		p.Token = ""
+		if len(resp.FederationRelationships) > 0 {
+			p.Token = fmt.Sprint(lastID)
+		}
+	}
+
+	return resp, nil
+}
+
+func updateFederationRelationship(tx *bolt.Tx, fr *datastore.FederationRelationship, mask *types.FederationRelationshipMask) (*datastore.FederationRelationship, error) {
+	key := tx.Bucket(federationRelationshipsByTDBucket).Get([]byte(fr.TrustDomain.Name()))
+	if key == nil {
+		return nil, fmt.Errorf("unable to fetch federation relationship: %w", errNotFound)
+	}
+
+	b := tx.Bucket(federationRelationshipsBucket)
+	record, err := unmarshalFederationRelationship(b.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch federation relationship: %w", err)
+	}
+
+	if mask.BundleEndpointUrl {
+		record.BundleEndpointURL = fr.BundleEndpointURL.String()
+	}
+
+	if mask.BundleEndpointProfile {
+		record.BundleEndpointProfile = string(fr.BundleEndpointProfile)
+
+		if fr.BundleEndpointProfile == datastore.BundleEndpointSPIFFE {
+			record.EndpointSPIFFEID = fr.EndpointSPIFFEID.String()
+		}
+	}
+
+	if mask.TrustDomainBundle && fr.TrustDomainBundle != nil {
+		// overwrite current bundle
+		_, err := setBundle(tx, fr.TrustDomainBundle)
+		if err != nil {
+			return nil, fmt.Errorf("unable to set bundle: %w", err)
+		}
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	if err := b.Put(key, data); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+
+	return recordToFederationRelationship(tx, record)
+}
+
+func validateFederationRelationship(fr *datastore.FederationRelationship, mask *types.FederationRelationshipMask) error {
+	if fr == nil {
+		return status.Error(codes.InvalidArgument, "federation relationship is nil")
+	}
+
+	if fr.TrustDomain.IsZero() {
+		return status.Error(codes.InvalidArgument, "trust domain is required")
+	}
+
+	if mask.BundleEndpointUrl && fr.BundleEndpointURL == nil {
+		return status.Error(codes.InvalidArgument, "bundle endpoint URL is required")
+	}
+
+	if mask.BundleEndpointProfile {
+		switch fr.BundleEndpointProfile {
+		case datastore.BundleEndpointWeb:
+		case datastore.BundleEndpointSPIFFE:
+			if fr.EndpointSPIFFEID.IsZero() {
+				return status.Error(codes.InvalidArgument, "bundle endpoint SPIFFE ID is required")
+			}
+		default:
+			return status.Errorf(codes.InvalidArgument, "unknown bundle endpoint profile type: %q", fr.BundleEndpointProfile)
+		}
+	}
+
+	return nil
+}
+
+func unmarshalFederationRelationship(data []byte) (*federationRelationshipRecord, error) {
+	record := new(federationRelationshipRecord)
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	return record, nil
+}
+
+func recordToFederationRelationship(tx *bolt.Tx, record *federationRelationshipRecord) (*datastore.FederationRelationship, error) {
+	bundleEndpointURL, err := url.Parse(record.BundleEndpointURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse URL: %w", err)
+	}
+
+	td, err := spiffeid.TrustDomainFromString(record.TrustDomain)
+	if err != nil {
+		return nil, kvError.Wrap(err)
+	}
+
+	fr := &datastore.FederationRelationship{
+		TrustDomain:           td,
+		BundleEndpointURL:     bundleEndpointURL,
+		BundleEndpointProfile: datastore.BundleEndpointType(record.BundleEndpointProfile),
+	}
+
+	switch fr.BundleEndpointProfile {
+	case datastore.BundleEndpointWeb:
+	case datastore.BundleEndpointSPIFFE:
+		endpointSPIFFEID, err := spiffeid.FromString(record.EndpointSPIFFEID)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse bundle endpoint SPIFFE ID: %w", err)
+		}
+		fr.EndpointSPIFFEID = endpointSPIFFEID
+	default:
+		return nil, fmt.Errorf("unknown bundle endpoint profile type: %q", record.BundleEndpointProfile)
+	}
+
+	trustDomainBundle, err := fetchBundle(tx, td.IDString())
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch bundle: %w", err)
+	}
+	fr.TrustDomainBundle = trustDomainBundle
+
+	return fr, nil
+}
@@ -0,0 +1,152 @@
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/spiffe/spire/pkg/server/datastore"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CreateJoinToken takes a Token message and stores it
+func (ds *Plugin) CreateJoinToken(ctx context.Context, token *datastore.JoinToken) error {
+	if token == nil || token.Token == "" || token.Expiry.IsZero() {
+		return errors.New("token and expiry are required")
+	}
+
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return createJoinToken(tx, token)
+	})
+}
+
+// FetchJoinToken takes a Token message and returns one, populating the fields
+// we have knowledge of
+func (ds *Plugin) FetchJoinToken(ctx context.Context, token string) (resp *datastore.JoinToken, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) error {
+		resp = fetchJoinToken(tx, token)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ListJoinTokens lists all join tokens (pagination available)
+func (ds *Plugin) ListJoinTokens(ctx context.Context, req *datastore.ListJoinTokensRequest) (resp *datastore.ListJoinTokensResponse, err error) {
+	if req.Pagination != nil && req.Pagination.PageSize == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cannot paginate with pagesize = 0")
+	}
+
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) error {
+		resp = listJoinTokens(tx, req)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// DeleteJoinToken deletes the given join token
+func (ds *Plugin) DeleteJoinToken(ctx context.Context, token string) error {
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return deleteJoinToken(tx, token)
+	})
+}
+
+// PruneJoinTokens takes a Token message, and deletes all tokens which have expired
+// before the date in the message
+func (ds *Plugin) PruneJoinTokens(ctx context.Context, expiry time.Time) error {
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return pruneJoinTokens(tx, expiry)
+	})
+}
+
+// Join tokens are keyed by the token, with the expiry in seconds since the
+// unix epoch as the value.
+func createJoinToken(tx *bolt.Tx, token *datastore.JoinToken) error {
+	b := tx.Bucket(joinTokensBucket)
+	if b.Get([]byte(token.Token)) != nil {
+		return kvError.Wrap(errAlreadyExists)
+	}
+	return kvError.Wrap(b.Put([]byte(token.Token), itob(uint64(token.Expiry.Unix()))))
+}
+
+func fetchJoinToken(tx *bolt.Tx, token string) *datastore.JoinToken {
+	expiry := tx.Bucket(joinTokensBucket).Get([]byte(token))
+	if expiry == nil {
+		return nil
+	}
+	return &datastore.JoinToken{
+		Token:  token,
+		Expiry: time.Unix(int64(btoi(expiry)), 0),
+	}
+}
+
+// listJoinTokens lists the join tokens in token order. Unlike other records,
+// join tokens are keyed by the token itself, which is then used as the
+// pagination token.
+func listJoinTokens(tx *bolt.Tx, req *datastore.ListJoinTokensRequest) *datastore.ListJoinTokensResponse {
+	p := req.Pagination
+	resp := &datastore.ListJoinTokensResponse{
+		Pagination: p,
+	}
+
+	c := tx.Bucket(joinTokensBucket).Cursor()
+	k, v := c.First()
+	if p != nil && p.Token != "" {
+		k, v = c.Seek([]byte(p.Token))
+		if k != nil && string(k) == p.Token {
+			k, v = c.Next()
+		}
+	}
+	for ; k != nil; k, v = c.Next() {
+		if p != nil && len(resp.JoinTokens) == int(p.PageSize) {
+			break
+		}
+		resp.JoinTokens = append(resp.JoinTokens, &datastore.JoinToken{
+			Token:  string(k),
+			Expiry: time.Unix(int64(btoi(v)), 0),
+		})
+	}
+
+	if p != nil {
+		p.Token = ""
+		if len(resp.JoinTokens) > 0 {
+			p.Token = resp.JoinTokens[len(resp.JoinTokens)-1].Token
+		}
+	}
+
+	return resp
+}
+
+func deleteJoinToken(tx *bolt.Tx, token string) error {
+	b := tx.Bucket(joinTokensBucket)
+	if b.Get([]byte(token)) == nil {
+		return kvError.Wrap(errNotFound)
+	}
+	return kvError.Wrap(b.Delete([]byte(token)))
+}
+
+func pruneJoinTokens(tx *bolt.Tx, expiresBefore time.Time) error {
+	b := tx.Bucket(joinTokensBucket)
+
+	var expired [][]byte
+	if err := b.ForEach(func(k, v []byte) error {
+		if int64(btoi(v)) < expiresBefore.Unix() {
+			expired = append(expired, append([]byte(nil), k...))
+		}
+		return nil
+	}); err != nil {
+		return kvError.Wrap(err)
+	}
+
+	for _, token := range expired {
+		if err := b.Delete(token); err != nil {
+			return kvError.Wrap(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package kvstore
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+	"github.com/hashicorp/hcl"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/zeebo/errs"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var kvError = errs.Class("datastore-kv")
+
+const (
+	PluginName = "kv"
+
+	// openTimeout is how long to wait to obtain the exclusive lock on the
+	// database file before giving up. The lock is held by another process
+	// (e.g. another server) using the same file.
+	openTimeout = 5 * time.Second
+)
+
+var (
+	errNotFound      = errors.New("record not found")
+	errAlreadyExists = errors.New("record already exists")
+)
+
+// Buckets holding the records. Records in buckets keyed by ID use the
+// big-endian encoding of a sequence number so that iteration follows
+// insertion order, which is what pagination tokens rely on.
+var (
+	bundlesBucket                     = []byte("bundles")
+	bundlesByTrustDomainBucket        = []byte("bundles_by_trust_domain")
+	nodesBucket                       = []byte("attested_nodes")
+	nodesBySPIFFEIDBucket             = []byte("attested_nodes_by_spiffe_id")
+	nodeSelectorsBucket               = []byte("node_selectors")
+	nodeEventsBucket                  = []byte("attested_node_events")
+	entriesBucket                     = []byte("registration_entries")
+	entriesByEntryIDBucket            = []byte("registration_entries_by_entry_id")
+	entryEventsBucket                 = []byte("registration_entry_events")
+	joinTokensBucket                  = []byte("join_tokens")
+	federationRelationshipsBucket     = []byte("federation_relationships")
+	federationRelationshipsByTDBucket = []byte("federation_relationships_by_trust_domain")
+
+	allBuckets = [][]byte{
+		bundlesBucket,
+		bundlesByTrustDomainBucket,
+		nodesBucket,
+		nodesBySPIFFEIDBucket,
+		nodeSelectorsBucket,
+		nodeEventsBucket,
+		entriesBucket,
+		entriesByEntryIDBucket,
+		entryEventsBucket,
+		joinTokensBucket,
+		federationRelationshipsBucket,
+		federationRelationshipsByTDBucket,
+	}
+)
+
+// Configuration for the key-value datastore implementation.
+type configuration struct {
+	Path string `hcl:"path" json:"path"`
+}
+
+func (cfg *configuration) Validate() error {
+	if cfg.Path == "" {
+		return kvError.New("path must be set")
+	}
+	return nil
+}
+
+// Plugin is a DataStore plugin implemented via an embedded key-value store
+type Plugin struct {
+	mu    sync.Mutex
+	db    *bolt.DB
+	path  string
+	log   logrus.FieldLogger
+	clock clock.Clock
+}
+
+// New creates a new kv plugin struct. Configure must be called
+// in order to open the database.
+func New(log logrus.FieldLogger) *Plugin {
+	return &Plugin{
+		log:   log,
+		clock: clock.New(),
+	}
+}
+
+// SetClock sets the clock used to determine which events are old enough to be
+// pruned. This is only intended to be used by tests.
+func (ds *Plugin) SetClock(clk clock.Clock) {
+	ds.clock = clk
+}
+
+// Configure parses HCL config payload into config struct and opens the
+// database file, creating it if it does not exist.
+func (ds *Plugin) Configure(_ context.Context, hclConfiguration string) error {
+	config := &configuration{}
+	if err := hcl.Decode(config, hclConfiguration); err != nil {
+		return err
+	}
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.db != nil && ds.path == config.Path {
+		return nil
+	}
+
+	db, err := openDB(config.Path)
+	if err != nil {
+		return err
+	}
+
+	if ds.db != nil {
+		ds.db.Close()
+	}
+
+	ds.log.WithField(telemetry.Path, config.Path).Info("Opened key-value database")
+	ds.db = db
+	ds.path = config.Path
+	return nil
+}
+
+func (ds *Plugin) Close() error {
+	ds.mu.Lock()
+	defer ds.mu.Unlock()
+
+	if ds.db == nil {
+		return nil
+	}
+	err := ds.db.Close()
+	ds.db = nil
+	return kvError.Wrap(err)
+}
+
+func openDB(path string) (*bolt.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, kvError.New("unable to create database directory: %v", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, kvError.New("unable to open database: %v", err)
+	}
+
+	if err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range allBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, kvError.New("unable to initialize database: %v", err)
+	}
+
+	return db, nil
+}
+
+// withWriteTx wraps the operation in a read-write transaction. The store only
+// allows one read-write transaction at a time, so operations that read and
+// then modify records are serialized.
+func (ds *Plugin) withWriteTx(ctx context.Context, op func(tx *bolt.Tx) error) error {
+	db, err := ds.getDB(ctx)
+	if err != nil {
+		return err
+	}
+	if err := db.Update(op); err != nil {
+		return toGRPCStatus(err)
+	}
+	return nil
+}
+
+// withReadTx wraps the operation in a read-only transaction.
+func (ds *Plugin) withReadTx(ctx context.Context, op func(tx *bolt.Tx) error) error {
+	db, err := ds.getDB(ctx)
+	if err != nil {
+		return err
+	}
+	if err := db.View(op); err != nil {
+		return toGRPCStatus(err)
+	}
+	return nil
+}
+
+func (ds *Plugin) getDB(ctx context.Context) (*bolt.DB, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	ds.mu.Lock()
+	db := ds.db
+	ds.mu.Unlock()
+
+	if db == nil {
+		return nil, status.Error(codes.FailedPrecondition, "datastore-kv: not configured")
+	}
+	return db, nil
+}
+
+// toGRPCStatus takes an error, and converts it to a GRPC error. If the
+// error is already a gRPC status, it will be returned unmodified. Otherwise
+// if the error wraps a known storage error the matching code will be set,
+// otherwise the code will be set to Unknown.
+func toGRPCStatus(err error) error {
+	unwrapped := errs.Unwrap(err)
+	if _, ok := status.FromError(unwrapped); ok {
+		return unwrapped
+	}
+
+	code := codes.Unknown
+	switch {
+	case errors.Is(err, errNotFound):
+		code = codes.NotFound
+	case errors.Is(err, errAlreadyExists):
+		code = codes.AlreadyExists
+	}
+
+	return status.Error(code, err.Error())
+}
+
+// parsePagination validates the pagination request and returns the ID of
+// the last record returned in the previous page, or zero for the first page.
+func parsePagination(p *datastore.Pagination) (uint64, error) {
+	if p.PageSize == 0 {
+		return 0, status.Error(codes.InvalidArgument, "cannot paginate with pagesize = 0")
+	}
+	if len(p.Token) == 0 {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(p.Token, 10, 32)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "could not parse token '%v'", p.Token)
+	}
+	return id, nil
+}
+
+// forEachAfter calls fn for each record in the ID-keyed bucket with an ID
+// greater than the given one, in ID order, until fn returns false.
+func forEachAfter(b *bolt.Bucket, after uint64, fn func(id uint64, v []byte) (bool, error)) error {
+	c := b.Cursor()
+	for k, v := c.Seek(itob(after + 1)); k != nil; k, v = c.Next() {
+		more, err := fn(btoi(k), v)
+		if err != nil {
+			return err
+		}
+		if !more {
+			return nil
+		}
+	}
+	return nil
+}
+
+// putNew stores the value under a new sequence ID in the bucket and returns
+// the ID.
+func putNew(b *bolt.Bucket, v []byte) (uint64, error) {
+	id, err := b.NextSequence()
+	if err != nil {
+		return 0, kvError.Wrap(err)
+	}
+	if err := b.Put(itob(id), v); err != nil {
+		return 0, kvError.Wrap(err)
+	}
+	return id, nil
+}
+
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+func btoi(b []byte) uint64 {
+	return binary.BigEndian.Uint64(b)
+}
@@ -0,0 +1,52 @@
+package kvstore
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	datastoretest "github.com/spiffe/spire/pkg/server/datastore/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataStore(t *testing.T) {
+	datastoretest.Test(t, datastoretest.Config{
+		Create: func(t *testing.T, log logrus.FieldLogger) datastoretest.DataStore {
+			ds := New(log)
+			err := ds.Configure(context.Background(), fmt.Sprintf(`path = %q`, filepath.Join(t.TempDir(), "datastore.db")))
+			require.NoError(t, err)
+			return ds
+		},
+		ErrorClass: "datastore-kv",
+	})
+}
+
+func TestConfigure(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	ds := New(log)
+	defer ds.Close()
+
+	err := ds.Configure(context.Background(), "")
+	require.EqualError(t, err, "datastore-kv: path must be set")
+
+	path := filepath.Join(t.TempDir(), "nested", "datastore.db")
+	require.NoError(t, ds.Configure(context.Background(), fmt.Sprintf(`path = %q`, path)))
+	require.FileExists(t, path)
+
+	// Reconfiguring with the same path keeps the database open
+	require.NoError(t, ds.Configure(context.Background(), fmt.Sprintf(`path = %q`, path)))
+	count, err := ds.CountBundles(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestNotConfigured(t *testing.T) {
+	log, _ := test.NewNullLogger()
+	ds := New(log)
+
+	_, err := ds.CountBundles(context.Background())
+	require.EqualError(t, err, "rpc error: code = FailedPrecondition desc = datastore-kv: not configured")
+}
@@ -0,0 +1,744 @@
+package kvstore
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spiffe/spire/pkg/common/protoutil"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+	bolt "go.etcd.io/bbolt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// CreateAttestedNode stores the given attested node
+func (ds *Plugin) CreateAttestedNode(ctx context.Context, node *common.AttestedNode) (attestedNode *common.AttestedNode, err error) {
+	if node == nil {
+		return nil, kvError.New("invalid request: missing attested node")
+	}
+
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		attestedNode, err = createAttestedNode(tx, node)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return attestedNode, nil
+}
+
+// FetchAttestedNode fetches an existing attested node by SPIFFE ID
+func (ds *Plugin) FetchAttestedNode(ctx context.Context, spiffeID string) (attestedNode *common.AttestedNode, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) (err error) {
+		attestedNode, err = fetchAttestedNode(tx, spiffeID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return attestedNode, nil
+}
+
+// CountAttestedNodes counts all attested nodes
+func (ds *Plugin) CountAttestedNodes(ctx context.Context) (count int32, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) error {
+		count = int32(tx.Bucket(nodesBucket).Stats().KeyN)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListAttestedNodes lists all attested nodes (pagination available)
+func (ds *Plugin) ListAttestedNodes(ctx context.Context, req *datastore.ListAttestedNodesRequest) (resp *datastore.ListAttestedNodesResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) (err error) {
+		resp, err = ds.listAttestedNodes(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// UpdateAttestedNode updates the given node's cert serial and expiration.
+func (ds *Plugin) UpdateAttestedNode(ctx context.Context, n *common.AttestedNode, mask *common.AttestedNodeMask) (node *common.AttestedNode, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		node, err = updateAttestedNode(tx, n, mask)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// DeleteAttestedNode deletes the given attested node and the associated node selectors.
+func (ds *Plugin) DeleteAttestedNode(ctx context.Context, spiffeID string) (attestedNode *common.AttestedNode, err error) {
+	if err = ds.withWriteTx(ctx, func(tx *bolt.Tx) (err error) {
+		attestedNode, err = deleteAttestedNodeAndSelectors(tx, spiffeID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return attestedNode, nil
+}
+
+// ListAttestedNodesEvents lists the attested node events that happened after
+// the given event ID
+func (ds *Plugin) ListAttestedNodesEvents(ctx context.Context, req *datastore.ListAttestedNodesEventsRequest) (resp *datastore.ListAttestedNodesEventsResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) error {
+		resp = &datastore.ListAttestedNodesEventsResponse{}
+		return forEachEvent(tx.Bucket(nodeEventsBucket), req.GreaterThanEventID, func(id uint, event *eventRecord) {
+			resp.Events = append(resp.Events, datastore.AttestedNodeEvent{
+				EventID:  id,
+				SpiffeID: event.ID,
+			})
+		})
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PruneAttestedNodesEvents deletes all attested node events older than the
+// given duration
+func (ds *Plugin) PruneAttestedNodesEvents(ctx context.Context, olderThan time.Duration) error {
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return pruneEvents(tx.Bucket(nodeEventsBucket), ds.clock.Now().Add(-olderThan))
+	})
+}
+
+// SetNodeSelectors sets node (agent) selectors by SPIFFE ID, deleting old selectors first
+func (ds *Plugin) SetNodeSelectors(ctx context.Context, spiffeID string, selectors []*common.Selector) error {
+	return ds.withWriteTx(ctx, func(tx *bolt.Tx) error {
+		return setNodeSelectors(tx, spiffeID, selectors)
+	})
+}
+
+// GetNodeSelectors gets node (agent) selectors by SPIFFE ID
+func (ds *Plugin) GetNodeSelectors(ctx context.Context, spiffeID string, _ datastore.DataConsistency) (selectors []*common.Selector, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) (err error) {
+		selectors, err = getNodeSelectors(tx, spiffeID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return selectors, nil
+}
+
+// ListNodeSelectors gets node (agent) selectors by SPIFFE ID
+func (ds *Plugin) ListNodeSelectors(ctx context.Context, req *datastore.ListNodeSelectorsRequest) (resp *datastore.ListNodeSelectorsResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *bolt.Tx) (err error) {
+		resp, err = listNodeSelectors(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func createAttestedNode(tx *bolt.Tx, node *common.AttestedNode) (*common.AttestedNode, error) {
+	index := tx.Bucket(nodesBySPIFFEIDBucket)
+	if index.Get([]byte(node.SpiffeId)) != nil {
+		return nil, kvError.Wrap(errAlreadyExists)
+	}
+
+	stored := &common.AttestedNode{
+		SpiffeId:            node.SpiffeId,
+		AttestationDataType: node.AttestationDataType,
+		CertSerialNumber:    node.CertSerialNumber,
+		CertNotAfter:        node.CertNotAfter,
+		NewCertSerialNumber: node.NewCertSerialNumber,
+		NewCertNotAfter:     node.NewCertNotAfter,
+		CanReattest:         node.CanReattest,
+		LastSeenAt:          node.LastSeenAt,
+		AgentVersion:        node.AgentVersion,
+		RemoteAddress:       node.RemoteAddress,
+	}
+	data, err := proto.Marshal(proto.Clone(stored))
+	if err != nil {
+		return nil, kvError.Wrap(err)
+	}
+
+	id, err := putNew(tx.Bucket(nodesBucket), data)
+	if err != nil {
+		return nil, err
+	}
+	if err := index.Put([]byte(node.SpiffeId), itob(id)); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+
+	if err := createAttestedNodeEvent(tx, node.SpiffeId); err != nil {
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func fetchAttestedNode(tx *bolt.Tx, spiffeID string) (*common.AttestedNode, error) {
+	_, node, err := getAttestedNode(tx, spiffeID)
+	switch {
+	case errors.Is(err, errNotFound):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	return node, nil
+}
+
+func (ds *Plugin) listAttestedNodes(tx *bolt.Tx, req *datastore.ListAttestedNodesRequest) (*datastore.ListAttestedNodesResponse, error) {
+	if req.Pagination != nil && req.Pagination.PageSize == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cannot paginate with pagesize = 0")
+	}
+	if req.BySelectorMatch != nil && len(req.BySelectorMatch.Selectors) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cannot list by empty selectors set")
+	}
+
+	// Exact/subset selector matching requires filtering out the nodes that
+	// have selectors not represented in the request, which happens after a
+	// page has been gathered. It's possible that a page ends up completely
+	// filtered out. If that happens, keep listing until a page gets at least
+	// one result.
+	for {
+		resp, err := listAttestedNodesOnce(tx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.BySelectorMatch == nil || len(resp.Nodes) == 0 {
+			return resp, nil
+		}
+
+		switch req.BySelectorMatch.Match {
+		case datastore.Exact, datastore.Subset:
+			resp.Nodes = filterNodesBySelectorSet(resp.Nodes, req.BySelectorMatch.Selectors)
+		default:
+		}
+
+		// Now that we've filtered the nodes based on selectors, prune off
+		// selectors from the response if they were not requested.
+		if !req.FetchSelectors {
+			for _, node := range resp.Nodes {
+				node.Selectors = nil
+			}
+		}
+
+		if len(resp.Nodes) > 0 || resp.Pagination == nil || len(resp.Pagination.Token) == 0 {
+			return resp, nil
+		}
+
+		if resp.Pagination.Token == req.Pagination.Token {
+			// This check is purely defensive, a request with a given token
+			// should never yield that same token.
+			ds.log.Warn("Filtered attested node pagination would recurse. Please report this bug.")
+			resp.Pagination.Token = ""
+			return resp, nil
+		}
+
+		req.Pagination = resp.Pagination
+	}
+}
+
+func listAttestedNodesOnce(tx *bolt.Tx, req *datastore.ListAttestedNodesRequest) (*datastore.ListAttestedNodesResponse, error) {
+	// Sorted lists are gathered entirely and then paginated from the node
+	// the pagination token is keyed on
+	sorted := req.SortBy != datastore.SortNodesByID || req.SortDescending
+	compare, err := attestedNodesComparator(req.SortBy)
+	if err != nil {
+		return nil, err
+	}
+
+	var after, keyID uint64
+	var keyNode *common.AttestedNode
+	if req.Pagination != nil {
+		if sorted && req.Pagination.Token != "" {
+			keyID, keyNode, err = parseSortedNodesToken(req.Pagination.Token, req.SortBy)
+		} else {
+			after, err = parsePagination(req.Pagination)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fetchSelectors := req.FetchSelectors || req.BySelectorMatch != nil
+
+	var matchSelectors func([]*common.Selector) bool
+	if req.BySelectorMatch != nil {
+		switch req.BySelectorMatch.Match {
+		case datastore.Subset, datastore.MatchAny:
+			matchSelectors = func(ss []*common.Selector) bool {
+				return containsAnySelector(ss, req.BySelectorMatch.Selectors)
+			}
+		case datastore.Exact, datastore.Superset:
+			matchSelectors = func(ss []*common.Selector) bool {
+				return containsAllSelectors(ss, req.BySelectorMatch.Selectors)
+			}
+		default:
+			return nil, kvError.New("unhandled selectors match behavior %q", req.BySelectorMatch.Match)
+		}
+	}
+
+	nodes := make([]*common.AttestedNode, 0, 64)
+	var ids []uint64
+	var lastID uint64
+	if err := forEachAfter(tx.Bucket(nodesBucket), after, func(id uint64, v []byte) (bool, error) {
+		node, err := unmarshalAttestedNode(v)
+		if err != nil {
+			return false, err
+		}
+
+		if !req.ByExpiresBefore.IsZero() && node.CertNotAfter >= req.ByExpiresBefore.Unix() {
+			return true, nil
+		}
+		if req.ByAttestationType != "" && node.AttestationDataType != req.ByAttestationType {
+			return true, nil
+		}
+		if req.ByBanned != nil && *req.ByBanned != (node.CertSerialNumber == "") {
+			return true, nil
+		}
+		if req.ByCanReattest != nil && *req.ByCanReattest != node.CanReattest {
+			return true, nil
+		}
+		if req.ByAgentVersion != "" && node.AgentVersion != req.ByAgentVersion {
+			return true, nil
+		}
+		if !req.ByLastSeenBefore.IsZero() && node.LastSeenAt >= req.ByLastSeenBefore.Unix() {
+			return true, nil
+		}
+
+		if fetchSelectors {
+			node.Selectors, err = getNodeSelectors(tx, node.SpiffeId)
+			if err != nil {
+				return false, err
+			}
+		}
+		if matchSelectors != nil && !matchSelectors(node.Selectors) {
+			return true, nil
+		}
+
+		nodes = append(nodes, node)
+		lastID = id
+		if sorted {
+			ids = append(ids, id)
+			return true, nil
+		}
+		return req.Pagination == nil || len(nodes) < int(req.Pagination.PageSize), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if sorted {
+		sorter := &nodesSorter{
+			nodes:      nodes,
+			ids:        ids,
+			compare:    compare,
+			descending: req.SortDescending,
+		}
+		sort.Sort(sorter)
+		if req.Pagination != nil {
+			start := 0
+			if keyNode != nil {
+				start = sort.Search(len(nodes), func(i int) bool {
+					return sorter.follows(i, keyID, keyNode)
+				})
+			}
+			end := start + int(req.Pagination.PageSize)
+			if end > len(nodes) {
+				end = len(nodes)
+			}
+			if end > start {
+				lastID = ids[end-1]
+			}
+			nodes = nodes[start:end]
+		}
+	}
+
+	resp := &datastore.ListAttestedNodesResponse{
+		Nodes: nodes,
+	}
+
+	if req.Pagination != nil {
+		resp.Pagination = &datastore.Pagination{
+			PageSize: req.Pagination.PageSize,
+		}
+		if len(resp.Nodes) > 0 {
+			resp.Pagination.Token = strconv.FormatUint(lastID, 10)
+			if sorted {
+				resp.Pagination.Token = sortedNodesToken(req.SortBy, lastID, resp.Nodes[len(resp.Nodes)-1])
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// attestedNodesComparator returns a function comparing attested nodes by
+// the given field.
+func attestedNodesComparator(sortBy datastore.NodeSortField) (func(a, b *common.AttestedNode) int, error) {
+	switch sortBy {
+	case datastore.SortNodesByID:
+		return func(a, b *common.AttestedNode) int { return 0 }, nil
+	case datastore.SortNodesBySpiffeID:
+		return func(a, b *common.AttestedNode) int {
+			return strings.Compare(a.SpiffeId, b.SpiffeId)
+		}, nil
+	case datastore.SortNodesByAttestationType:
+		return func(a, b *common.AttestedNode) int {
+			return strings.Compare(a.AttestationDataType, b.AttestationDataType)
+		}, nil
+	case datastore.SortNodesByExpiresAt:
+		return func(a, b *common.AttestedNode) int {
+			return compareInt64(a.CertNotAfter, b.CertNotAfter)
+		}, nil
+	case datastore.SortNodesByLastSeenAt:
+		return func(a, b *common.AttestedNode) int {
+			return compareInt64(a.LastSeenAt, b.LastSeenAt)
+		}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported sort field %q", sortBy)
+	}
+}
+
+// sortedNodesToken returns the pagination token for the page following the
+// given node in a sorted list, which holds the ID and the sort field value of
+// the node.
+func sortedNodesToken(sortBy datastore.NodeSortField, id uint64, node *common.AttestedNode) string {
+	token := strconv.FormatUint(id, 10)
+	switch sortBy {
+	case datastore.SortNodesBySpiffeID:
+		token += ":" + node.SpiffeId
+	case datastore.SortNodesByAttestationType:
+		token += ":" + node.AttestationDataType
+	case datastore.SortNodesByExpiresAt:
+		token += ":" + strconv.FormatInt(node.CertNotAfter, 10)
+	case datastore.SortNodesByLastSeenAt:
+		token += ":" + strconv.FormatInt(node.LastSeenAt, 10)
+	}
+	return token
+}
+
+// parseSortedNodesToken parses the pagination token of a sorted list into the
+// ID of the last node listed and a node holding its sort field value.
+func parseSortedNodesToken(token string, sortBy datastore.NodeSortField) (uint64, *common.AttestedNode, error) {
+	idToken, valueToken, hasValue := strings.Cut(token, ":")
+	id, err := strconv.ParseUint(idToken, 10, 32)
+	if err != nil || hasValue != (sortBy != datastore.SortNodesByID) {
+		return 0, nil, status.Errorf(codes.InvalidArgument, "could not parse token '%v'", token)
+	}
+
+	node := new(common.AttestedNode)
+	switch sortBy {
+	case datastore.SortNodesBySpiffeID:
+		node.SpiffeId = valueToken
+	case datastore.SortNodesByAttestationType:
+		node.AttestationDataType = valueToken
+	case datastore.SortNodesByExpiresAt, datastore.SortNodesByLastSeenAt:
+		value, err := strconv.ParseInt(valueToken, 10, 64)
+		if err != nil {
+			return 0, nil, status.Errorf(codes.InvalidArgument, "could not parse token '%v'", token)
+		}
+		if sortBy == datastore.SortNodesByExpiresAt {
+			node.CertNotAfter = value
+		} else {
+			node.LastSeenAt = value
+		}
+	}
+	return id, node, nil
+}
+
+// nodesSorter sorts attested nodes along with their IDs, which are used as
+// tiebreaker so the order is stable across pages.
+type nodesSorter struct {
+	nodes      []*common.AttestedNode
+	ids        []uint64
+	compare    func(a, b *common.AttestedNode) int
+	descending bool
+}
+
+func (s *nodesSorter) Len() int {
+	return len(s.nodes)
+}
+
+func (s *nodesSorter) Less(i, j int) bool {
+	return s.order(s.nodes[i], s.ids[i], s.nodes[j], s.ids[j]) < 0
+}
+
+// follows returns true if the i-th node comes after the given node in the
+// sort order.
+func (s *nodesSorter) follows(i int, id uint64, node *common.AttestedNode) bool {
+	return s.order(s.nodes[i], s.ids[i], node, id) > 0
+}
+
+func (s *nodesSorter) order(a *common.AttestedNode, aID uint64, b *common.AttestedNode, bID uint64) int {
+	c := s.compare(a, b)
+	if c == 0 {
+		c = compareInt64(int64(aID), int64(bID))
+	}
+	if s.descending {
+		return -c
+	}
+	return c
+}
+
+func (s *nodesSorter) Swap(i, j int) {
+	s.nodes[i], s.nodes[j] = s.nodes[j], s.nodes[i]
+	s.ids[i], s.ids[j] = s.ids[j], s.ids[i]
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// filterNodesBySelectorSet filters out nodes with selectors not in the set
+func filterNodesBySelectorSet(nodes []*common.AttestedNode, selectors []*common.Selector) []*common.AttestedNode {
+	filtered := make([]*common.AttestedNode, 0, len(nodes))
+	for _, node := range nodes {
+		if containsAllSelectors(selectors, node.Selectors) {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+func updateAttestedNode(tx *bolt.Tx, n *common.AttestedNode, mask *common.AttestedNodeMask) (*common.AttestedNode, error) {
+	id, node, err := getAttestedNode(tx, n.SpiffeId)
+	if err != nil {
+		return nil, err
+	}
+
+	if mask == nil {
+		mask = protoutil.AllTrueCommonAgentMask
+	}
+
+	if mask.CertNotAfter {
+		node.CertNotAfter = n.CertNotAfter
+	}
+	if mask.CertSerialNumber {
+		node.CertSerialNumber = n.CertSerialNumber
+	}
+	if mask.NewCertNotAfter {
+		node.NewCertNotAfter = n.NewCertNotAfter
+	}
+	if mask.NewCertSerialNumber {
+		node.NewCertSerialNumber = n.NewCertSerialNumber
+	}
+	if mask.CanReattest {
+		node.CanReattest = n.CanReattest
+	}
+	if mask.LastSeenAt {
+		node.LastSeenAt = n.LastSeenAt
+	}
+	if mask.AgentVersion {
+		node.AgentVersion = n.AgentVersion
+	}
+	if mask.RemoteAddress {
+		node.RemoteAddress = n.RemoteAddress
+	}
+
+	data, err := proto.Marshal(proto.Clone(node))
+	if err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	if err := tx.Bucket(nodesBucket).Put(itob(id), data); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+
+	if !isAgentActivityMask(mask) {
+		if err := createAttestedNodeEvent(tx, node.SpiffeId); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+// isAgentActivityMask returns true if the mask only updates the activity
+// recorded for the agent, which does not need an attested node event.
+func isAgentActivityMask(mask *common.AttestedNodeMask) bool {
+	return !mask.AttestationDataType &&
+		!mask.CertSerialNumber &&
+		!mask.CertNotAfter &&
+		!mask.NewCertSerialNumber &&
+		!mask.NewCertNotAfter &&
+		!mask.CanReattest
+}
+
+func deleteAttestedNodeAndSelectors(tx *bolt.Tx, spiffeID string) (*common.AttestedNode, error) {
+	id, node, err := getAttestedNode(tx, spiffeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Bucket(nodeSelectorsBucket).Delete([]byte(spiffeID)); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	if err := tx.Bucket(nodesBucket).Delete(itob(id)); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	if err := tx.Bucket(nodesBySPIFFEIDBucket).Delete([]byte(spiffeID)); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+
+	if err := createAttestedNodeEvent(tx, spiffeID); err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// getAttestedNode returns the ID and attested node for the SPIFFE ID,
+// failing with a not found error if it does not exist.
+func getAttestedNode(tx *bolt.Tx, spiffeID string) (uint64, *common.AttestedNode, error) {
+	key := tx.Bucket(nodesBySPIFFEIDBucket).Get([]byte(spiffeID))
+	if key == nil {
+		return 0, nil, kvError.Wrap(errNotFound)
+	}
+
+	node, err := unmarshalAttestedNode(tx.Bucket(nodesBucket).Get(key))
+	if err != nil {
+		return 0, nil, err
+	}
+	return btoi(key), node, nil
+}
+
+func unmarshalAttestedNode(data []byte) (*common.AttestedNode, error) {
+	node := new(common.AttestedNode)
+	if err := proto.Unmarshal(data, node); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	return node, nil
+}
+
+func createAttestedNodeEvent(tx *bolt.Tx, spiffeID string) error {
+	return createEvent(tx.Bucket(nodeEventsBucket), spiffeID)
+}
+
+func setNodeSelectors(tx *bolt.Tx, spiffeID string, selectors []*common.Selector) error {
+	b := tx.Bucket(nodeSelectorsBucket)
+	if len(selectors) == 0 {
+		if err := b.Delete([]byte(spiffeID)); err != nil {
+			return kvError.Wrap(err)
+		}
+		return createAttestedNodeEvent(tx, spiffeID)
+	}
+
+	type selectorKey struct {
+		Type  string
+		Value string
+	}
+	seen := make(map[selectorKey]struct{}, len(selectors))
+	stored := &common.Selectors{
+		Entries: make([]*common.Selector, 0, len(selectors)),
+	}
+	for _, selector := range selectors {
+		key := selectorKey{Type: selector.Type, Value: selector.Value}
+		if _, ok := seen[key]; ok {
+			return kvError.Wrap(errAlreadyExists)
+		}
+		seen[key] = struct{}{}
+		stored.Entries = append(stored.Entries, &common.Selector{
+			Type:  selector.Type,
+			Value: selector.Value,
+		})
+	}
+
+	data, err := proto.Marshal(stored)
+	if err != nil {
+		return kvError.Wrap(err)
+	}
+	if err := b.Put([]byte(spiffeID), data); err != nil {
+		return kvError.Wrap(err)
+	}
+
+	return createAttestedNodeEvent(tx, spiffeID)
+}
+
+func getNodeSelectors(tx *bolt.Tx, spiffeID string) ([]*common.Selector, error) {
+	data := tx.Bucket(nodeSelectorsBucket).Get([]byte(spiffeID))
+	if data == nil {
+		return nil, nil
+	}
+	return unmarshalSelectors(data)
+}
+
+func listNodeSelectors(tx *bolt.Tx, req *datastore.ListNodeSelectorsRequest) (*datastore.ListNodeSelectorsResponse, error) {
+	resp := &datastore.ListNodeSelectorsResponse{
+		Selectors: make(map[string][]*common.Selector),
+	}
+
+	if err := tx.Bucket(nodeSelectorsBucket).ForEach(func(k, v []byte) error {
+		spiffeID := string(k)
+		if !req.ValidAt.IsZero() {
+			_, node, err := getAttestedNode(tx, spiffeID)
+			switch {
+			case errors.Is(err, errNotFound):
+				return nil
+			case err != nil:
+				return err
+			}
+			if node.CertNotAfter <= req.ValidAt.Unix() {
+				return nil
+			}
+		}
+
+		selectors, err := unmarshalSelectors(v)
+		if err != nil {
+			return err
+		}
+		resp.Selectors[spiffeID] = selectors
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func unmarshalSelectors(data []byte) ([]*common.Selector, error) {
+	selectors := new(common.Selectors)
+	if err := proto.Unmarshal(data, selectors); err != nil {
+		return nil, kvError.Wrap(err)
+	}
+	return selectors.Entries, nil
+}
+
+// containsAnySelector returns true if any of the wanted selectors is in ss.
+func containsAnySelector(ss, wanted []*common.Selector) bool {
+	for _, w := range wanted {
+		if containsSelector(ss, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAllSelectors returns true if all of the wanted selectors are in ss.
+func containsAllSelectors(ss, wanted []*common.Selector) bool {
+	for _, w := range wanted {
+		if !containsSelector(ss, w) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsSelector(ss []*common.Selector, selector *common.Selector) bool {
+	for _, s := range ss {
+		if s.Type == selector.Type && s.Value == selector.Value {
+			return true
+		}
+	}
+	return false
+}
@@ -184,11 +184,17 @@ import (
 // | v1.6.4  |        |                                                                           |
 // |*********|        |                                                                           |
 // | v1.7.0  |        |                                                                           |
+// |---------|        |                                                                           |
+// | v1.7.1  |        |                                                                           |
+// |*********|********|***************************************************************************|
+// | v1.8.0  | 22     | Added registered_entries_events and attested_node_entries_events tables   |
+// |         |--------|---------------------------------------------------------------------------|
+// |         | 23     | Added last_seen_at, agent_version and remote_address to attested nodes    |
 // ================================================================================================
 
 const (
 	// the latest schema version of the database in the code
-	latestSchemaVersion = 21
+	latestSchemaVersion = 23
 
 	// lastMinorReleaseSchemaVersion is the schema version supported by the
 	// last minor release. When the migrations are opportunistically pruned
@@ -350,6 +356,8 @@ func initDB(db *gorm.DB, dbType string, log logrus.FieldLogger) (err error) {
 		&Migration{},
 		&DNSName{},
 		&FederatedTrustDomain{},
+		&RegisteredEntryEvent{},
+		&AttestedNodeEvent{},
 	}
 
 	if err := tableOptionsForDialect(tx, dbType).AutoMigrate(tables...).Error; err != nil {
@@ -404,7 +412,11 @@ func migrateVersion(tx *gorm.DB, currVersion int, log logrus.FieldLogger) (versi
 	// Place all migrations handled by the current minor release here. This
 	// list can be opportunistically pruned after every minor release but won't
 	// break things if it isn't.
-	switch currVersion { //nolint: gocritic // No upgrade required yet, keeping switch for future additions
+	switch currVersion {
+	case 21:
+		err = migrateToV22(tx)
+	case 22:
+		err = migrateToV23(tx)
 	default:
 		err = sqlError.New("no migration support for unknown schema version %d", currVersion)
 	}
@@ -415,6 +427,26 @@ func migrateVersion(tx *gorm.DB, currVersion int, log logrus.FieldLogger) (versi
 	return nextVersion, nil
 }
 
+func migrateToV22(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&RegisteredEntryEvent{}, &AttestedNodeEvent{}).Error; err != nil {
+		return sqlError.Wrap(err)
+	}
+	return nil
+}
+
+func migrateToV23(tx *gorm.DB) error {
+	if err := tx.AutoMigrate(&AttestedNode{}).Error; err != nil {
+		return sqlError.Wrap(err)
+	}
+	// Nodes attested before the last seen time was recorded are considered
+	// to have been seen the last time they were updated, which happens at
+	// least every time they renew their SVID.
+	if err := tx.Exec("UPDATE attested_node_entries SET last_seen_at = updated_at, agent_version = '', remote_address = ''").Error; err != nil {
+		return sqlError.Wrap(err)
+	}
+	return nil
+}
+
 func addFederatedRegistrationEntriesRegisteredEntryIDIndex(tx *gorm.DB) error {
 	// GORM creates the federated_registration_entries implicitly with a primary
 	// key tuple (bundle_id, registered_entry_id). Unfortunately, MySQL5 does
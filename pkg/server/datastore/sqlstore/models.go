@@ -33,6 +33,9 @@ type AttestedNode struct {
 	NewSerialNumber string
 	NewExpiresAt    *time.Time
 	CanReattest     bool
+	LastSeenAt      time.Time `gorm:"index"`
+	AgentVersion    string
+	RemoteAddress   string
 
 	Selectors []*NodeSelector
 }
@@ -55,6 +58,19 @@ func (V3AttestedNode) TableName() string {
 	return "attested_node_entries"
 }
 
+// AttestedNodeEvent holds the SPIFFE ID of an attested node that was
+// created, updated or deleted
+type AttestedNodeEvent struct {
+	Model
+
+	SpiffeID string
+}
+
+// TableName gets table name for AttestedNodeEvent
+func (AttestedNodeEvent) TableName() string {
+	return "attested_node_entries_events"
+}
+
 // NodeSelector holds a node selector by spiffe ID
 type NodeSelector struct {
 	Model
@@ -102,6 +118,19 @@ type RegisteredEntry struct {
 	JWTSvidTTL int32 `gorm:"column:jwt_svid_ttl"`
 }
 
+// RegisteredEntryEvent holds the entry ID of a registered entry that was
+// created, updated or deleted
+type RegisteredEntryEvent struct {
+	Model
+
+	EntryID string
+}
+
+// TableName gets table name for RegisteredEntryEvent
+func (RegisteredEntryEvent) TableName() string {
+	return "registered_entries_events"
+}
+
 // JoinToken holds a join token
 type JoinToken struct {
 	Model
@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/andres-erbsen/clock"
 	"github.com/gofrs/uuid/v5"
 	"github.com/hashicorp/hcl"
 	"github.com/jinzhu/gorm"
@@ -93,13 +94,17 @@ type Plugin struct {
 	db                  *sqlDB
 	roDb                *sqlDB
 	log                 logrus.FieldLogger
+	clock               clock.Clock
 	useServerTimestamps bool
 }
 
 // New creates a new sql plugin struct. Configure must be called
 // in order to start the db.
 func New(log logrus.FieldLogger) *Plugin {
-	return &Plugin{log: log}
+	return &Plugin{
+		log:   log,
+		clock: clock.New(),
+	}
 }
 
 // CreateBundle stores the given bundle
@@ -311,6 +316,27 @@ func (ds *Plugin) DeleteAttestedNode(ctx context.Context, spiffeID string) (atte
 	return attestedNode, nil
 }
 
+// ListAttestedNodesEvents lists the attested node events that happened after
+// the given event ID
+func (ds *Plugin) ListAttestedNodesEvents(ctx context.Context, req *datastore.ListAttestedNodesEventsRequest) (resp *datastore.ListAttestedNodesEventsResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *gorm.DB) (err error) {
+		resp, err = listAttestedNodesEvents(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PruneAttestedNodesEvents deletes all attested node events older than the
+// given duration
+func (ds *Plugin) PruneAttestedNodesEvents(ctx context.Context, olderThan time.Duration) (err error) {
+	return ds.withWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		err = pruneAttestedNodesEvents(tx, ds.clock.Now().Add(-olderThan))
+		return err
+	})
+}
+
 // SetNodeSelectors sets node (agent) selectors by SPIFFE ID, deleting old selectors first
 func (ds *Plugin) SetNodeSelectors(ctx context.Context, spiffeID string, selectors []*common.Selector) (err error) {
 	return ds.withWriteTx(ctx, func(tx *gorm.DB) (err error) {
@@ -381,6 +407,29 @@ func (ds *Plugin) createOrReturnRegistrationEntry(ctx context.Context,
 	return registrationEntry, existing, nil
 }
 
+// RestoreRegistrationEntry stores the given registration entry, keeping its
+// entry ID and revision number. It is meant for restoring exported entries.
+// An error with the AlreadyExists code is returned if an entry with the same
+// entry ID or the same (parentID, spiffeID, selector) tuple already exists.
+func (ds *Plugin) RestoreRegistrationEntry(ctx context.Context,
+	entry *common.RegistrationEntry,
+) (registrationEntry *common.RegistrationEntry, err error) {
+	if err = validateRegistrationEntry(entry); err != nil {
+		return nil, err
+	}
+	if entry.EntryId == "" {
+		return nil, sqlError.New("invalid registration entry: missing entry ID")
+	}
+
+	if err = ds.withWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		registrationEntry, err = restoreRegistrationEntry(ctx, ds.db, tx, entry)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return registrationEntry, nil
+}
+
 // FetchRegistrationEntry fetches an existing registration by entry ID
 func (ds *Plugin) FetchRegistrationEntry(ctx context.Context,
 	entryID string,
@@ -443,6 +492,27 @@ func (ds *Plugin) PruneRegistrationEntries(ctx context.Context, expiresBefore ti
 	})
 }
 
+// ListRegistrationEntriesEvents lists the registration entry events that
+// happened after the given event ID
+func (ds *Plugin) ListRegistrationEntriesEvents(ctx context.Context, req *datastore.ListRegistrationEntriesEventsRequest) (resp *datastore.ListRegistrationEntriesEventsResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *gorm.DB) (err error) {
+		resp, err = listRegistrationEntriesEvents(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PruneRegistrationEntriesEvents deletes all registration entry events older
+// than the given duration
+func (ds *Plugin) PruneRegistrationEntriesEvents(ctx context.Context, olderThan time.Duration) (err error) {
+	return ds.withWriteTx(ctx, func(tx *gorm.DB) (err error) {
+		err = pruneRegistrationEntriesEvents(tx, ds.clock.Now().Add(-olderThan))
+		return err
+	})
+}
+
 // CreateJoinToken takes a Token message and stores it
 func (ds *Plugin) CreateJoinToken(ctx context.Context, token *datastore.JoinToken) (err error) {
 	if token == nil || token.Token == "" || token.Expiry.IsZero() {
@@ -468,6 +538,17 @@ func (ds *Plugin) FetchJoinToken(ctx context.Context, token string) (resp *datas
 	return resp, nil
 }
 
+// ListJoinTokens lists all join tokens (pagination available)
+func (ds *Plugin) ListJoinTokens(ctx context.Context, req *datastore.ListJoinTokensRequest) (resp *datastore.ListJoinTokensResponse, err error) {
+	if err = ds.withReadTx(ctx, func(tx *gorm.DB) (err error) {
+		resp, err = listJoinTokens(tx, req)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // DeleteJoinToken deletes the given join token
 func (ds *Plugin) DeleteJoinToken(ctx context.Context, token string) (err error) {
 	return ds.withWriteTx(ctx, func(tx *gorm.DB) (err error) {
@@ -555,6 +636,12 @@ func (ds *Plugin) UpdateFederationRelationship(ctx context.Context, fr *datastor
 	})
 }
 
+// SetClock sets the clock used to determine which events are old enough to be
+// pruned. This is only intended to be used by tests.
+func (ds *Plugin) SetClock(clk clock.Clock) {
+	ds.clock = clk
+}
+
 // SetUseServerTimestamps controls whether server-generated timestamps should be used in the database.
 // This is only intended to be used by tests in order to produce deterministic timestamp data,
 // since some databases round off timestamp data with lower precision.
@@ -980,6 +1067,19 @@ func deleteBundle(tx *gorm.DB, trustDomainID string, mode datastore.DeleteMode)
 	}
 
 	if entriesCount > 0 {
+		// Both deleting and dissociating change the associated entries, so
+		// events need to be recorded for them
+		var entryIDs []string
+		if err := tx.Model(&RegisteredEntry{}).Where(`id IN (
+				SELECT
+					registered_entry_id
+				FROM
+					federated_registration_entries
+				WHERE
+					bundle_id = ?)`, model.ID).Pluck("entry_id", &entryIDs).Error; err != nil {
+			return sqlError.Wrap(err)
+		}
+
 		switch mode {
 		case datastore.Delete:
 			// TODO: figure out how to do this gracefully with GORM.
@@ -999,6 +1099,12 @@ func deleteBundle(tx *gorm.DB, trustDomainID string, mode datastore.DeleteMode)
 		default:
 			return status.Newf(codes.FailedPrecondition, "datastore-sql: cannot delete bundle; federated with %d registration entries", entriesCount).Err()
 		}
+
+		for _, entryID := range entryIDs {
+			if err := createRegistrationEntryEvent(tx, entryID); err != nil {
+				return err
+			}
+		}
 	}
 
 	if err := tx.Delete(model).Error; err != nil {
@@ -1299,12 +1405,19 @@ func createAttestedNode(tx *gorm.DB, node *common.AttestedNode) (*common.Atteste
 		NewSerialNumber: node.NewCertSerialNumber,
 		NewExpiresAt:    nullableUnixTimeToDBTime(node.NewCertNotAfter),
 		CanReattest:     node.CanReattest,
+		LastSeenAt:      time.Unix(node.LastSeenAt, 0),
+		AgentVersion:    node.AgentVersion,
+		RemoteAddress:   node.RemoteAddress,
 	}
 
 	if err := tx.Create(&model).Error; err != nil {
 		return nil, sqlError.Wrap(err)
 	}
 
+	if err := createAttestedNodeEvent(tx, model.SpiffeID); err != nil {
+		return nil, err
+	}
+
 	return modelToAttestedNode(model), nil
 }
 
@@ -1434,7 +1547,7 @@ func listAttestedNodesOnce(ctx context.Context, db *sqlDB, req *datastore.ListAt
 		}
 	}
 
-	var lastEID uint64
+	var lastRow nodeRow
 	var node *common.AttestedNode
 	for rows.Next() {
 		var r nodeRow
@@ -1442,8 +1555,8 @@ func listAttestedNodesOnce(ctx context.Context, db *sqlDB, req *datastore.ListAt
 			return nil, err
 		}
 
-		if node == nil || lastEID != r.EId {
-			lastEID = r.EId
+		if node == nil || lastRow.EId != r.EId {
+			lastRow = r
 			pushNode(node)
 			node = new(common.AttestedNode)
 		}
@@ -1467,7 +1580,7 @@ func listAttestedNodesOnce(ctx context.Context, db *sqlDB, req *datastore.ListAt
 			PageSize: req.Pagination.PageSize,
 		}
 		if len(resp.Nodes) > 0 {
-			resp.Pagination.Token = strconv.FormatUint(lastEID, 10)
+			resp.Pagination.Token = attestedNodesToken(req, &lastRow)
 		}
 	}
 
@@ -1503,18 +1616,28 @@ func buildListAttestedNodesQueryCTE(req *datastore.ListAttestedNodesRequest, dbT
 	// Selectors will be fetched only when `FetchSelectors` or BySelectorMatch are in request
 	fetchSelectors := req.FetchSelectors || req.BySelectorMatch != nil
 
+	sortColumn, err := attestedNodesSortColumn(req.SortBy)
+	if err != nil {
+		return "", nil, err
+	}
+	sorted := isSortedNodesRequest(req)
+	orderBy := attestedNodesOrderBy(sortColumn, "", req.SortDescending)
+
 	// Creates filtered nodes, `true` is added to simplify code, all filters will start with `AND`
 	builder.WriteString("\nWITH filtered_nodes AS (\n")
 	builder.WriteString("\tSELECT * FROM attested_node_entries WHERE true\n")
 
-	// Filter by pagination token
+	// Filter by pagination token, which is keyed on the sort column for
+	// sorted lists
 	if req.Pagination != nil && req.Pagination.Token != "" {
-		token, err := strconv.ParseUint(req.Pagination.Token, 10, 32)
+		keyset, keysetArgs, err := attestedNodesKeyset(req, sortColumn, "")
 		if err != nil {
-			return "", nil, status.Errorf(codes.InvalidArgument, "could not parse token '%v'", req.Pagination.Token)
+			return "", nil, err
 		}
-		builder.WriteString("\t\tAND id > ?")
-		args = append(args, token)
+		builder.WriteString("\t\tAND ")
+		builder.WriteString(keyset)
+		builder.WriteString("\n")
+		args = append(args, keysetArgs...)
 	}
 
 	// Filter by expiration
@@ -1551,6 +1674,18 @@ func buildListAttestedNodesQueryCTE(req *datastore.ListAttestedNodesRequest, dbT
 		}
 	}
 
+	// Filter by agent version
+	if req.ByAgentVersion != "" {
+		builder.WriteString("\t\tAND agent_version = ?\n")
+		args = append(args, req.ByAgentVersion)
+	}
+
+	// Filter by last seen time
+	if !req.ByLastSeenBefore.IsZero() {
+		builder.WriteString("\t\tAND last_seen_at < ?\n")
+		args = append(args, req.ByLastSeenBefore)
+	}
+
 	builder.WriteString(")")
 	// Fetch all selectors from filtered entries
 	if fetchSelectors {
@@ -1577,7 +1712,10 @@ SELECT
 	expires_at,
 	new_serial_number,
 	new_expires_at,
-	can_reattest,`)
+	can_reattest,
+	last_seen_at,
+	agent_version,
+	remote_address,`)
 
 	// Add "optional" fields for selectors
 	if fetchSelectors {
@@ -1606,6 +1744,12 @@ SELECT
 		builder.WriteString("\tSELECT id FROM (\n")
 	}
 
+	// The sort columns are not available in the subqueries selecting the
+	// IDs, so the page is taken from the filtered nodes with those IDs
+	if sorted && req.Pagination != nil {
+		builder.WriteString("\tSELECT id FROM filtered_nodes WHERE id IN (\n")
+	}
+
 	// Add filter by selectors
 	if req.BySelectorMatch != nil && len(req.BySelectorMatch.Selectors) > 0 {
 		// Select IDs, that will be used to fetch "paged" entrieSelect IDs, that will be used to fetch "paged" entries
@@ -1679,8 +1823,14 @@ SELECT
 		builder.WriteString(" AS result_nodes")
 	}
 
+	if sorted && req.Pagination != nil {
+		builder.WriteString("\n\t)")
+	}
+
 	if req.Pagination != nil {
-		builder.WriteString(" ORDER BY id ASC LIMIT ")
+		builder.WriteString(" ORDER BY ")
+		builder.WriteString(orderBy)
+		builder.WriteString(" LIMIT ")
 		builder.WriteString(strconv.FormatInt(int64(req.Pagination.PageSize), 10))
 
 		// Add workaround for limit
@@ -1689,7 +1839,9 @@ SELECT
 		}
 	}
 
-	builder.WriteString("\n) ORDER BY id ASC\n")
+	builder.WriteString("\n) ORDER BY ")
+	builder.WriteString(orderBy)
+	builder.WriteString("\n")
 
 	return builder.String(), args, nil
 }
@@ -1701,6 +1853,12 @@ func buildListAttestedNodesQueryMySQL(req *datastore.ListAttestedNodesRequest) (
 	// Selectors will be fetched only when `FetchSelectors` or `BySelectorMatch` are in request
 	fetchSelectors := req.FetchSelectors || req.BySelectorMatch != nil
 
+	sortColumn, err := attestedNodesSortColumn(req.SortBy)
+	if err != nil {
+		return "", nil, err
+	}
+	sorted := isSortedNodesRequest(req)
+
 	// Add expected fields
 	builder.WriteString(`
 SELECT 
@@ -1711,7 +1869,10 @@ SELECT
 	N.expires_at,
 	N.new_serial_number,
 	N.new_expires_at,
-	N.can_reattest,`)
+	N.can_reattest,
+	N.last_seen_at,
+	N.agent_version,
+	N.remote_address,`)
 	// Add "optional" fields for selectors
 	if fetchSelectors {
 		builder.WriteString(`
@@ -1731,17 +1892,24 @@ FROM attested_node_entries N
 `)
 	}
 
+	writeLimit := func() {
+		builder.WriteString(" LIMIT ")
+		builder.WriteString(strconv.FormatInt(int64(req.Pagination.PageSize), 10))
+	}
+
 	writeFilter := func() error {
 		builder.WriteString("WHERE true")
 
-		// Filter by pagination token
+		// Filter by pagination token, which is keyed on the sort column for
+		// sorted lists
 		if req.Pagination != nil && req.Pagination.Token != "" {
-			token, err := strconv.ParseUint(req.Pagination.Token, 10, 32)
+			keyset, keysetArgs, err := attestedNodesKeyset(req, sortColumn, "N.")
 			if err != nil {
-				return status.Errorf(codes.InvalidArgument, "could not parse token '%v'", req.Pagination.Token)
+				return err
 			}
-			builder.WriteString(" AND N.id > ?")
-			args = append(args, token)
+			builder.WriteString(" AND ")
+			builder.WriteString(keyset)
+			args = append(args, keysetArgs...)
 		}
 
 		// Filter by expiration
@@ -1777,18 +1945,43 @@ FROM attested_node_entries N
 				builder.WriteString("\t\tAND can_reattest = false\n")
 			}
 		}
+
+		// Filter by agent version
+		if req.ByAgentVersion != "" {
+			builder.WriteString(" AND N.agent_version = ?")
+			args = append(args, req.ByAgentVersion)
+		}
+
+		// Filter by last seen time
+		if !req.ByLastSeenBefore.IsZero() {
+			builder.WriteString(" AND N.last_seen_at < ?")
+			args = append(args, req.ByLastSeenBefore)
+		}
 		return nil
 	}
 
 	// Add filter by selectors
 	if fetchSelectors {
 		builder.WriteString("WHERE N.id IN (\n")
+		// The sort column is needed to take the page of a sorted list
+		sortPage := sorted && req.Pagination != nil && sortColumn != "id"
 		if req.Pagination != nil {
 			builder.WriteString("\tSELECT id FROM (\n")
 		}
-		builder.WriteString("\t\tSELECT DISTINCT id FROM (\n")
+		if sortPage {
+			builder.WriteString("\t\tSELECT DISTINCT id, ")
+			builder.WriteString(sortColumn)
+			builder.WriteString(" FROM (\n")
+		} else {
+			builder.WriteString("\t\tSELECT DISTINCT id FROM (\n")
+		}
 
-		builder.WriteString("\t\t\t(SELECT N.id, N.spiffe_id FROM attested_node_entries N ")
+		builder.WriteString("\t\t\t(SELECT N.id, N.spiffe_id")
+		if sortPage && sortColumn != "spiffe_id" {
+			builder.WriteString(", N.")
+			builder.WriteString(sortColumn)
+		}
+		builder.WriteString(" FROM attested_node_entries N ")
 		if err := writeFilter(); err != nil {
 			return "", nil, err
 		}
@@ -1831,22 +2024,32 @@ FROM attested_node_entries N
 			}
 		}
 		if req.Pagination != nil {
-			builder.WriteString("\t\t) ORDER BY id ASC LIMIT ")
-			builder.WriteString(strconv.FormatInt(int64(req.Pagination.PageSize), 10))
+			builder.WriteString("\t\t) ORDER BY ")
+			builder.WriteString(attestedNodesOrderBy(sortColumn, "", req.SortDescending))
+			writeLimit()
 			builder.WriteString("\n")
 
 			builder.WriteString("\t) workaround_for_mysql_subquery_limit\n")
 		} else {
 			builder.WriteString("\t)\n")
 		}
-		builder.WriteString(") ORDER BY e_id, S.id\n")
+		if sorted {
+			builder.WriteString(") ORDER BY ")
+			builder.WriteString(attestedNodesOrderBy(sortColumn, "N.", req.SortDescending))
+			builder.WriteString(", S.id\n")
+		} else {
+			builder.WriteString(") ORDER BY e_id, S.id\n")
+		}
 	} else {
 		if err := writeFilter(); err != nil {
 			return "", nil, err
 		}
+		if sorted || req.Pagination != nil {
+			builder.WriteString(" ORDER BY ")
+			builder.WriteString(attestedNodesOrderBy(sortColumn, "N.", req.SortDescending))
+		}
 		if req.Pagination != nil {
-			builder.WriteString(" ORDER BY N.id ASC LIMIT ")
-			builder.WriteString(strconv.FormatInt(int64(req.Pagination.PageSize), 10))
+			writeLimit()
 		}
 		builder.WriteString("\n")
 	}
@@ -1854,6 +2057,91 @@ FROM attested_node_entries N
 	return builder.String(), args, nil
 }
 
+// isSortedNodesRequest returns true if the attested nodes are not listed by
+// ascending ID.
+func isSortedNodesRequest(req *datastore.ListAttestedNodesRequest) bool {
+	return req.SortBy != datastore.SortNodesByID || req.SortDescending
+}
+
+// attestedNodesSortColumn returns the column attested nodes are sorted by.
+func attestedNodesSortColumn(sortBy datastore.NodeSortField) (string, error) {
+	switch sortBy {
+	case datastore.SortNodesByID:
+		return "id", nil
+	case datastore.SortNodesBySpiffeID:
+		return "spiffe_id", nil
+	case datastore.SortNodesByAttestationType:
+		return "data_type", nil
+	case datastore.SortNodesByExpiresAt:
+		return "expires_at", nil
+	case datastore.SortNodesByLastSeenAt:
+		return "last_seen_at", nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unsupported sort field %q", sortBy)
+	}
+}
+
+// attestedNodesOrderBy returns the ORDER BY clause for the sort column, with
+// the ID as tiebreaker so the order is stable across pages.
+func attestedNodesOrderBy(sortColumn, qualifier string, descending bool) string {
+	direction := " ASC"
+	if descending {
+		direction = " DESC"
+	}
+	if sortColumn == "id" {
+		return qualifier + "id" + direction
+	}
+	return qualifier + sortColumn + direction + ", " + qualifier + "id" + direction
+}
+
+// attestedNodesToken returns the pagination token for the page following the
+// given row. The token of sorted lists holds the ID and the sort column value
+// of the row, which the next page is keyed on.
+func attestedNodesToken(req *datastore.ListAttestedNodesRequest, r *nodeRow) string {
+	token := strconv.FormatUint(r.EId, 10)
+	switch req.SortBy {
+	case datastore.SortNodesBySpiffeID:
+		token += ":" + r.SpiffeID
+	case datastore.SortNodesByAttestationType:
+		token += ":" + r.DataType.String
+	case datastore.SortNodesByExpiresAt:
+		token += ":" + r.ExpiresAt.Time.Format(time.RFC3339Nano)
+	case datastore.SortNodesByLastSeenAt:
+		token += ":" + r.LastSeenAt.Time.Format(time.RFC3339Nano)
+	}
+	return token
+}
+
+// attestedNodesKeyset returns the condition, and its arguments, selecting the
+// attested nodes that follow the ones listed up to the pagination token in
+// the requested order.
+func attestedNodesKeyset(req *datastore.ListAttestedNodesRequest, sortColumn, qualifier string) (string, []interface{}, error) {
+	idToken, valueToken, hasValue := strings.Cut(req.Pagination.Token, ":")
+	id, err := strconv.ParseUint(idToken, 10, 32)
+	if err != nil || hasValue != (req.SortBy != datastore.SortNodesByID) {
+		return "", nil, status.Errorf(codes.InvalidArgument, "could not parse token '%v'", req.Pagination.Token)
+	}
+
+	op := " > ?"
+	if req.SortDescending {
+		op = " < ?"
+	}
+	if !hasValue {
+		return qualifier + "id" + op, []interface{}{id}, nil
+	}
+
+	var value interface{} = valueToken
+	if req.SortBy == datastore.SortNodesByExpiresAt || req.SortBy == datastore.SortNodesByLastSeenAt {
+		value, err = time.Parse(time.RFC3339Nano, valueToken)
+		if err != nil {
+			return "", nil, status.Errorf(codes.InvalidArgument, "could not parse token '%v'", req.Pagination.Token)
+		}
+	}
+
+	column := qualifier + sortColumn
+	return "(" + column + op + " OR (" + column + " = ? AND " + qualifier + "id" + op + "))", []interface{}{value, value, id}, nil
+}
+
 func updateAttestedNode(tx *gorm.DB, n *common.AttestedNode, mask *common.AttestedNodeMask) (*common.AttestedNode, error) {
 	var model AttestedNode
 	if err := tx.Find(&model, "spiffe_id = ?", n.SpiffeId).Error; err != nil {
@@ -1880,13 +2168,40 @@ func updateAttestedNode(tx *gorm.DB, n *common.AttestedNode, mask *common.Attest
 	if mask.CanReattest {
 		updates["can_reattest"] = n.CanReattest
 	}
+	if mask.LastSeenAt {
+		updates["last_seen_at"] = time.Unix(n.LastSeenAt, 0)
+	}
+	if mask.AgentVersion {
+		updates["agent_version"] = n.AgentVersion
+	}
+	if mask.RemoteAddress {
+		updates["remote_address"] = n.RemoteAddress
+	}
 	if err := tx.Model(&model).Updates(updates).Error; err != nil {
 		return nil, sqlError.Wrap(err)
 	}
 
+	if !isAgentActivityMask(mask) {
+		if err := createAttestedNodeEvent(tx, model.SpiffeID); err != nil {
+			return nil, err
+		}
+	}
+
 	return modelToAttestedNode(model), nil
 }
 
+// isAgentActivityMask returns true if the mask only updates the activity
+// recorded for the agent. The activity does not affect what the agent is
+// authorized for, so it is updated without creating an attested node event.
+func isAgentActivityMask(mask *common.AttestedNodeMask) bool {
+	return !mask.AttestationDataType &&
+		!mask.CertSerialNumber &&
+		!mask.CertNotAfter &&
+		!mask.NewCertSerialNumber &&
+		!mask.NewCertNotAfter &&
+		!mask.CanReattest
+}
+
 func deleteAttestedNodeAndSelectors(tx *gorm.DB, spiffeID string) (*common.AttestedNode, error) {
 	var (
 		nodeModel         AttestedNode
@@ -1906,9 +2221,45 @@ func deleteAttestedNodeAndSelectors(tx *gorm.DB, spiffeID string) (*common.Attes
 		return nil, sqlError.Wrap(err)
 	}
 
+	if err := createAttestedNodeEvent(tx, spiffeID); err != nil {
+		return nil, err
+	}
+
 	return modelToAttestedNode(nodeModel), nil
 }
 
+func createAttestedNodeEvent(tx *gorm.DB, spiffeID string) error {
+	if err := tx.Create(&AttestedNodeEvent{SpiffeID: spiffeID}).Error; err != nil {
+		return sqlError.Wrap(err)
+	}
+	return nil
+}
+
+func listAttestedNodesEvents(tx *gorm.DB, req *datastore.ListAttestedNodesEventsRequest) (*datastore.ListAttestedNodesEventsResponse, error) {
+	var events []AttestedNodeEvent
+	if err := tx.Where("id > ?", req.GreaterThanEventID).Order("id asc").Find(&events).Error; err != nil {
+		return nil, sqlError.Wrap(err)
+	}
+
+	resp := &datastore.ListAttestedNodesEventsResponse{
+		Events: make([]datastore.AttestedNodeEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, datastore.AttestedNodeEvent{
+			EventID:  event.ID,
+			SpiffeID: event.SpiffeID,
+		})
+	}
+	return resp, nil
+}
+
+func pruneAttestedNodesEvents(tx *gorm.DB, createdBefore time.Time) error {
+	if err := tx.Where("created_at < ?", createdBefore).Delete(&AttestedNodeEvent{}).Error; err != nil {
+		return sqlError.Wrap(err)
+	}
+	return nil
+}
+
 func setNodeSelectors(tx *gorm.DB, spiffeID string, selectors []*common.Selector) error {
 	// Previously the deletion of the previous set of node selectors was
 	// implemented via query like DELETE FROM node_resolver_map_entries WHERE
@@ -1942,7 +2293,7 @@ func setNodeSelectors(tx *gorm.DB, spiffeID string, selectors []*common.Selector
 		}
 	}
 
-	return nil
+	return createAttestedNodeEvent(tx, spiffeID)
 }
 
 func getNodeSelectors(ctx context.Context, db *sqlDB, spiffeID string) ([]*common.Selector, error) {
@@ -2046,17 +2397,42 @@ func createRegistrationEntry(tx *gorm.DB, entry *common.RegistrationEntry) (*com
 		return nil, err
 	}
 
+	return insertRegistrationEntry(tx, entry, entryID, 0)
+}
+
+func restoreRegistrationEntry(ctx context.Context, db *sqlDB, tx *gorm.DB, entry *common.RegistrationEntry) (*common.RegistrationEntry, error) {
+	var count int
+	if err := tx.Model(&RegisteredEntry{}).Where("entry_id = ?", entry.EntryId).Count(&count).Error; err != nil {
+		return nil, sqlError.Wrap(err)
+	}
+	if count > 0 {
+		return nil, status.Errorf(codes.AlreadyExists, "registration entry %q already exists", entry.EntryId)
+	}
+
+	similarEntry, err := lookupSimilarEntry(ctx, db, tx, entry)
+	if err != nil {
+		return nil, err
+	}
+	if similarEntry != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "registration entry %q is similar to the existing entry %q", entry.EntryId, similarEntry.EntryId)
+	}
+
+	return insertRegistrationEntry(tx, entry, entry.EntryId, entry.RevisionNumber)
+}
+
+func insertRegistrationEntry(tx *gorm.DB, entry *common.RegistrationEntry, entryID string, revisionNumber int64) (*common.RegistrationEntry, error) {
 	newRegisteredEntry := RegisteredEntry{
-		EntryID:    entryID,
-		SpiffeID:   entry.SpiffeId,
-		ParentID:   entry.ParentId,
-		TTL:        entry.X509SvidTtl,
-		Admin:      entry.Admin,
-		Downstream: entry.Downstream,
-		Expiry:     entry.EntryExpiry,
-		StoreSvid:  entry.StoreSvid,
-		JWTSvidTTL: entry.JwtSvidTtl,
-		Hint:       entry.Hint,
+		EntryID:        entryID,
+		SpiffeID:       entry.SpiffeId,
+		ParentID:       entry.ParentId,
+		TTL:            entry.X509SvidTtl,
+		Admin:          entry.Admin,
+		Downstream:     entry.Downstream,
+		Expiry:         entry.EntryExpiry,
+		RevisionNumber: revisionNumber,
+		StoreSvid:      entry.StoreSvid,
+		JWTSvidTTL:     entry.JwtSvidTtl,
+		Hint:           entry.Hint,
 	}
 
 	if err := tx.Create(&newRegisteredEntry).Error; err != nil {
@@ -2095,6 +2471,10 @@ func createRegistrationEntry(tx *gorm.DB, entry *common.RegistrationEntry) (*com
 		}
 	}
 
+	if err := createRegistrationEntryEvent(tx, newRegisteredEntry.EntryID); err != nil {
+		return nil, err
+	}
+
 	registrationEntry, err := modelToEntry(tx, newRegisteredEntry)
 	if err != nil {
 		return nil, err
@@ -3031,6 +3411,53 @@ func appendListRegistrationEntriesFilterQuery(filterExp string, builder *strings
 		args = append(args, req.ByHint)
 	}
 
+	// Filters on the columns of the registered_entries table are grouped in a
+	// single subquery
+	var conditions []string
+	if req.BySpiffeIDPrefix != "" {
+		conditions = append(conditions, "spiffe_id LIKE ? ESCAPE '!'")
+		args = append(args, escapeLikePattern(req.BySpiffeIDPrefix)+"%")
+	}
+	if req.ByDownstream != nil {
+		conditions = append(conditions, "downstream = ?")
+		args = append(args, *req.ByDownstream)
+	}
+	if req.ByAdmin != nil {
+		conditions = append(conditions, "admin = ?")
+		args = append(args, *req.ByAdmin)
+	}
+	if !req.ByExpiresBefore.IsZero() {
+		// Entries that never expire have an expiry of zero
+		conditions = append(conditions, "expiry <> 0 AND expiry < ?")
+		args = append(args, req.ByExpiresBefore.Unix())
+	}
+	if !req.ByExpiresAfter.IsZero() {
+		conditions = append(conditions, "expiry > ?")
+		args = append(args, req.ByExpiresAfter.Unix())
+	}
+	if req.ByX509SVIDTTL != 0 {
+		conditions = append(conditions, "ttl = ?")
+		args = append(args, req.ByX509SVIDTTL)
+	}
+	if req.ByJWTSVIDTTL != 0 {
+		conditions = append(conditions, "jwt_svid_ttl = ?")
+		args = append(args, req.ByJWTSVIDTTL)
+	}
+	if len(conditions) > 0 {
+		root.children = append(root.children, idFilterNode{
+			idColumn: "id",
+			query:    []string{"SELECT id AS e_id FROM registered_entries WHERE " + strings.Join(conditions, " AND ")},
+		})
+	}
+
+	if req.BySelectorType != "" {
+		root.children = append(root.children, idFilterNode{
+			idColumn: "registered_entry_id",
+			query:    []string{"SELECT DISTINCT registered_entry_id AS e_id FROM selectors WHERE type = ?"},
+		})
+		args = append(args, req.BySelectorType)
+	}
+
 	if req.BySelectors != nil && len(req.BySelectors.Selectors) > 0 {
 		switch req.BySelectors.Match {
 		case datastore.Subset, datastore.MatchAny:
@@ -3204,6 +3631,15 @@ func appendListRegistrationEntriesFilterQuery(filterExp string, builder *strings
 	return filtered, args, nil
 }
 
+// escapeLikePattern escapes the wildcards of a LIKE pattern, using "!" as
+// the escape character since backslashes are handled differently across
+// database engines.
+func escapeLikePattern(s string) string {
+	return likePatternEscaper.Replace(s)
+}
+
+var likePatternEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
 func buildSliceArg(length int) string {
 	strBuilder := new(strings.Builder)
 	strBuilder.WriteString("(?")
@@ -3223,6 +3659,9 @@ type nodeRow struct {
 	NewSerialNumber sql.NullString
 	NewExpiresAt    sql.NullTime
 	CanReattest     sql.NullBool
+	LastSeenAt      sql.NullTime
+	AgentVersion    sql.NullString
+	RemoteAddress   sql.NullString
 	SelectorType    sql.NullString
 	SelectorValue   sql.NullString
 }
@@ -3237,6 +3676,9 @@ func scanNodeRow(rs *sql.Rows, r *nodeRow) error {
 		&r.NewSerialNumber,
 		&r.NewExpiresAt,
 		&r.CanReattest,
+		&r.LastSeenAt,
+		&r.AgentVersion,
+		&r.RemoteAddress,
 		&r.SelectorType,
 		&r.SelectorValue,
 	))
@@ -3281,6 +3723,18 @@ func fillNodeFromRow(node *common.AttestedNode, r *nodeRow) error {
 		node.CanReattest = r.CanReattest.Bool
 	}
 
+	if r.LastSeenAt.Valid {
+		node.LastSeenAt = r.LastSeenAt.Time.Unix()
+	}
+
+	if r.AgentVersion.Valid {
+		node.AgentVersion = r.AgentVersion.String
+	}
+
+	if r.RemoteAddress.Valid {
+		node.RemoteAddress = r.RemoteAddress.String
+	}
+
 	return nil
 }
 
@@ -3524,6 +3978,10 @@ func updateRegistrationEntry(tx *gorm.DB, e *common.RegistrationEntry, mask *com
 		// The FederatesWith field in entry is filled in by the call to modelToEntry below
 	}
 
+	if err := createRegistrationEntryEvent(tx, entry.EntryID); err != nil {
+		return nil, err
+	}
+
 	returnEntry, err := modelToEntry(tx, entry)
 	if err != nil {
 		return nil, err
@@ -3570,6 +4028,38 @@ func deleteRegistrationEntrySupport(tx *gorm.DB, entry RegisteredEntry) error {
 		return sqlError.Wrap(err)
 	}
 
+	return createRegistrationEntryEvent(tx, entry.EntryID)
+}
+
+func createRegistrationEntryEvent(tx *gorm.DB, entryID string) error {
+	if err := tx.Create(&RegisteredEntryEvent{EntryID: entryID}).Error; err != nil {
+		return sqlError.Wrap(err)
+	}
+	return nil
+}
+
+func listRegistrationEntriesEvents(tx *gorm.DB, req *datastore.ListRegistrationEntriesEventsRequest) (*datastore.ListRegistrationEntriesEventsResponse, error) {
+	var events []RegisteredEntryEvent
+	if err := tx.Where("id > ?", req.GreaterThanEventID).Order("id asc").Find(&events).Error; err != nil {
+		return nil, sqlError.Wrap(err)
+	}
+
+	resp := &datastore.ListRegistrationEntriesEventsResponse{
+		Events: make([]datastore.RegistrationEntryEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, datastore.RegistrationEntryEvent{
+			EventID: event.ID,
+			EntryID: event.EntryID,
+		})
+	}
+	return resp, nil
+}
+
+func pruneRegistrationEntriesEvents(tx *gorm.DB, createdBefore time.Time) error {
+	if err := tx.Where("created_at < ?", createdBefore).Delete(&RegisteredEntryEvent{}).Error; err != nil {
+		return sqlError.Wrap(err)
+	}
 	return nil
 }
 
@@ -3618,6 +4108,43 @@ func fetchJoinToken(tx *gorm.DB, token string) (*datastore.JoinToken, error) {
 	return modelToJoinToken(model), nil
 }
 
+func listJoinTokens(tx *gorm.DB, req *datastore.ListJoinTokensRequest) (*datastore.ListJoinTokensResponse, error) {
+	if req.Pagination != nil && req.Pagination.PageSize == 0 {
+		return nil, status.Error(codes.InvalidArgument, "cannot paginate with pagesize = 0")
+	}
+
+	p := req.Pagination
+	var err error
+	if p != nil {
+		tx, err = applyPagination(p, tx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var models []JoinToken
+	if err := tx.Find(&models).Error; err != nil {
+		return nil, sqlError.Wrap(err)
+	}
+
+	if p != nil {
+		p.Token = ""
+		if len(models) > 0 {
+			lastEntry := models[len(models)-1]
+			p.Token = fmt.Sprint(lastEntry.ID)
+		}
+	}
+
+	resp := &datastore.ListJoinTokensResponse{
+		Pagination: p,
+	}
+	for _, model := range models {
+		resp.JoinTokens = append(resp.JoinTokens, modelToJoinToken(model))
+	}
+
+	return resp, nil
+}
+
 func deleteJoinToken(tx *gorm.DB, token string) error {
 	var model JoinToken
 	if err := tx.Find(&model, "token = ?", token).Error; err != nil {
@@ -4015,6 +4542,9 @@ func modelToAttestedNode(model AttestedNode) *common.AttestedNode {
 		NewCertSerialNumber: model.NewSerialNumber,
 		NewCertNotAfter:     nullableDBTimeToUnixTime(model.NewExpiresAt),
 		CanReattest:         model.CanReattest,
+		LastSeenAt:          model.LastSeenAt.Unix(),
+		AgentVersion:        model.AgentVersion,
+		RemoteAddress:       model.RemoteAddress,
 	}
 }
 
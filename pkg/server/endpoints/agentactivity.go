@@ -0,0 +1,135 @@
+package endpoints
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/common/version"
+	"github.com/spiffe/spire/pkg/server/api"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+	"google.golang.org/grpc/metadata"
+)
+
+// defaultAgentActivityRecordInterval is how often the activity of an agent is
+// written to the datastore while its version and address remain unchanged.
+// Agents sync every few seconds, so writing on every call would put a
+// significant load on the datastore of large deployments.
+const defaultAgentActivityRecordInterval = 5 * time.Minute
+
+var _ api.AgentActivityRecorder = (*AgentActivityRecorder)(nil)
+
+// AgentActivityRecorder records the last time agents were seen by the server,
+// along with the version and address they called the server with. Writes to
+// the datastore are throttled per agent.
+type AgentActivityRecorder struct {
+	ds       datastore.DataStore
+	clk      clock.Clock
+	interval time.Duration
+
+	mu        sync.Mutex
+	recorded  map[spiffeid.ID]agentActivity
+	lastPrune time.Time
+}
+
+type agentActivity struct {
+	seenAt  time.Time
+	version string
+	address string
+}
+
+func NewAgentActivityRecorder(ds datastore.DataStore, clk clock.Clock) *AgentActivityRecorder {
+	return &AgentActivityRecorder{
+		ds:        ds,
+		clk:       clk,
+		interval:  defaultAgentActivityRecordInterval,
+		recorded:  make(map[spiffeid.ID]agentActivity),
+		lastPrune: clk.Now(),
+	}
+}
+
+// RecordAgentActivity records that the agent was seen by the server. The
+// activity is only written to the datastore if it was not written within the
+// record interval, or if the agent version or address changed since.
+func (r *AgentActivityRecorder) RecordAgentActivity(ctx context.Context, agentID spiffeid.ID) {
+	activity := agentActivity{
+		seenAt:  r.clk.Now(),
+		version: agentVersionFromContext(ctx),
+		address: agentAddressFromContext(ctx),
+	}
+
+	if !r.shouldRecord(agentID, activity) {
+		return
+	}
+
+	_, err := r.ds.UpdateAttestedNode(ctx, &common.AttestedNode{
+		SpiffeId:      agentID.String(),
+		LastSeenAt:    activity.seenAt.Unix(),
+		AgentVersion:  activity.version,
+		RemoteAddress: activity.address,
+	}, &common.AttestedNodeMask{
+		LastSeenAt:    true,
+		AgentVersion:  true,
+		RemoteAddress: true,
+	})
+	if err != nil {
+		rpccontext.Logger(ctx).WithError(err).WithField(telemetry.AgentID, agentID.String()).Warn("Failed to record agent activity")
+
+		// Try again on the next call
+		r.mu.Lock()
+		delete(r.recorded, agentID)
+		r.mu.Unlock()
+	}
+}
+
+func (r *AgentActivityRecorder) shouldRecord(agentID spiffeid.ID, activity agentActivity) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Forget about agents that have not been seen for a while (e.g. deleted
+	// or re-attested with a different ID) so the map does not grow unbounded.
+	if activity.seenAt.Sub(r.lastPrune) >= r.interval {
+		for id, recorded := range r.recorded {
+			if activity.seenAt.Sub(recorded.seenAt) >= r.interval {
+				delete(r.recorded, id)
+			}
+		}
+		r.lastPrune = activity.seenAt
+	}
+
+	recorded, ok := r.recorded[agentID]
+	if ok && activity.seenAt.Sub(recorded.seenAt) < r.interval &&
+		recorded.version == activity.version && recorded.address == activity.address {
+		return false
+	}
+	r.recorded[agentID] = activity
+	return true
+}
+
+func agentVersionFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, userAgent := range md.Get("user-agent") {
+		if agentVersion, ok := version.AgentVersionFromUserAgent(userAgent); ok {
+			return agentVersion
+		}
+	}
+	return ""
+}
+
+func agentAddressFromContext(ctx context.Context) string {
+	addr, ok := rpccontext.CallerAddr(ctx).(*net.TCPAddr)
+	if !ok {
+		return ""
+	}
+	// The port is left out since it changes with every connection
+	return addr.IP.String()
+}
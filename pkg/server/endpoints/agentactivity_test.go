@@ -0,0 +1,104 @@
+package endpoints
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/api/rpccontext"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/clock"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAgentActivityRecorder(t *testing.T) {
+	log, logHook := test.NewNullLogger()
+	ds := fakedatastore.New(t)
+	clk := clock.NewMock(t)
+	recorder := NewAgentActivityRecorder(ds, clk)
+
+	agentID := spiffeid.RequireFromString("spiffe://example.org/spire/agent/foo")
+	_, err := ds.CreateAttestedNode(context.Background(), &common.AttestedNode{
+		SpiffeId:            agentID.String(),
+		AttestationDataType: "test",
+		CertSerialNumber:    "1234",
+		CertNotAfter:        clk.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	callerCtx := func(userAgent, ip string) context.Context {
+		ctx := rpccontext.WithLogger(context.Background(), log)
+		ctx = rpccontext.WithCallerAddr(ctx, &net.TCPAddr{IP: net.ParseIP(ip), Port: 12345})
+		return metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", userAgent))
+	}
+
+	assertActivity := func(t *testing.T, lastSeenAt time.Time, agentVersion, remoteAddress string) {
+		node, err := ds.FetchAttestedNode(context.Background(), agentID.String())
+		require.NoError(t, err)
+		require.Equal(t, lastSeenAt.Unix(), node.LastSeenAt)
+		require.Equal(t, agentVersion, node.AgentVersion)
+		require.Equal(t, remoteAddress, node.RemoteAddress)
+		// Other fields must be left untouched
+		require.Equal(t, "1234", node.CertSerialNumber)
+	}
+
+	// The first call is always recorded
+	firstSeen := clk.Now()
+	recorder.RecordAgentActivity(callerCtx("spire-agent/1.8.0 grpc-go/1.56.2", "192.0.2.1"), agentID)
+	assertActivity(t, firstSeen, "1.8.0", "192.0.2.1")
+
+	// Calls within the interval are not recorded
+	clk.Add(time.Minute)
+	recorder.RecordAgentActivity(callerCtx("spire-agent/1.8.0 grpc-go/1.56.2", "192.0.2.1"), agentID)
+	assertActivity(t, firstSeen, "1.8.0", "192.0.2.1")
+
+	// Calls within the interval are recorded when the version changes
+	clk.Add(time.Minute)
+	recorder.RecordAgentActivity(callerCtx("spire-agent/1.8.1 grpc-go/1.56.2", "192.0.2.1"), agentID)
+	assertActivity(t, clk.Now(), "1.8.1", "192.0.2.1")
+
+	// Calls within the interval are recorded when the address changes
+	clk.Add(time.Minute)
+	recorder.RecordAgentActivity(callerCtx("spire-agent/1.8.1 grpc-go/1.56.2", "192.0.2.2"), agentID)
+	assertActivity(t, clk.Now(), "1.8.1", "192.0.2.2")
+	lastRecorded := clk.Now()
+
+	// Calls are recorded again once the interval elapses
+	clk.Add(defaultAgentActivityRecordInterval - time.Second)
+	recorder.RecordAgentActivity(callerCtx("spire-agent/1.8.1 grpc-go/1.56.2", "192.0.2.2"), agentID)
+	assertActivity(t, lastRecorded, "1.8.1", "192.0.2.2")
+	clk.Add(time.Second)
+	recorder.RecordAgentActivity(callerCtx("spire-agent/1.8.1 grpc-go/1.56.2", "192.0.2.2"), agentID)
+	assertActivity(t, clk.Now(), "1.8.1", "192.0.2.2")
+
+	// Unknown user agents are recorded without a version
+	clk.Add(defaultAgentActivityRecordInterval)
+	recorder.RecordAgentActivity(callerCtx("grpc-go/1.56.2", "192.0.2.2"), agentID)
+	assertActivity(t, clk.Now(), "", "192.0.2.2")
+
+	// Failures are logged and retried on the next call
+	clk.Add(defaultAgentActivityRecordInterval)
+	ds.SetNextError(errors.New("oh no"))
+	recorder.RecordAgentActivity(callerCtx("spire-agent/1.8.1 grpc-go/1.56.2", "192.0.2.2"), agentID)
+	spiretest.AssertLastLogs(t, logHook.AllEntries(), []spiretest.LogEntry{
+		{
+			Level:   logrus.WarnLevel,
+			Message: "Failed to record agent activity",
+			Data: logrus.Fields{
+				telemetry.AgentID: agentID.String(),
+				logrus.ErrorKey:   "oh no",
+			},
+		},
+	})
+	recorder.RecordAgentActivity(callerCtx("spire-agent/1.8.1 grpc-go/1.56.2", "192.0.2.2"), agentID)
+	assertActivity(t, clk.Now(), "1.8.1", "192.0.2.2")
+}
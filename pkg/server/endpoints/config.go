@@ -16,9 +16,11 @@ import (
 	"github.com/spiffe/spire/pkg/server/api"
 	agentv1 "github.com/spiffe/spire/pkg/server/api/agent/v1"
 	bundlev1 "github.com/spiffe/spire/pkg/server/api/bundle/v1"
+	datastorev1 "github.com/spiffe/spire/pkg/server/api/datastore/v1"
 	debugv1 "github.com/spiffe/spire/pkg/server/api/debug/v1"
 	entryv1 "github.com/spiffe/spire/pkg/server/api/entry/v1"
 	healthv1 "github.com/spiffe/spire/pkg/server/api/health/v1"
+	localauthorityv1 "github.com/spiffe/spire/pkg/server/api/localauthority/v1"
 	svidv1 "github.com/spiffe/spire/pkg/server/api/svid/v1"
 	trustdomainv1 "github.com/spiffe/spire/pkg/server/api/trustdomain/v1"
 	"github.com/spiffe/spire/pkg/server/authpolicy"
@@ -58,6 +60,10 @@ type Config struct {
 	// JWTKey publisher
 	JWTKeyPublisher manager.JwtKeyPublisher
 
+	// AuthorityManager manages the local authorities (i.e. X.509 CA and JWT
+	// key) of the server
+	AuthorityManager localauthorityv1.CAManager
+
 	// Makes policy decisions
 	AuthPolicyEngine *authpolicy.Engine
 
@@ -74,6 +80,14 @@ type Config struct {
 	// CacheReloadInterval controls how often the in-memory entry cache reloads
 	CacheReloadInterval time.Duration
 
+	// EventsBasedCache enables keeping the in-memory entry cache up to date
+	// with the datastore events instead of periodically reloading it
+	EventsBasedCache bool
+
+	// PruneEventsOlderThan controls how long events are kept in the datastore
+	// when EventsBasedCache is enabled
+	PruneEventsOlderThan time.Duration
+
 	AuditLogEnabled bool
 
 	// AdminIDs are a list of fixed IDs that when presented by a caller in an
@@ -120,19 +134,35 @@ func (c *Config) maybeMakeBundleEndpointServer() Server {
 func (c *Config) makeAPIServers(entryFetcher api.AuthorizedEntryFetcher) APIServers {
 	ds := c.Catalog.GetDataStore()
 	upstreamPublisher := UpstreamPublisher(c.JWTKeyPublisher)
+	activityRecorder := NewAgentActivityRecorder(ds, c.Clock)
+	agentServer := agentv1.New(agentv1.Config{
+		DataStore:        ds,
+		ServerCA:         c.ServerCA,
+		TrustDomain:      c.TrustDomain,
+		Catalog:          c.Catalog,
+		Clock:            c.Clock,
+		ActivityRecorder: activityRecorder,
+	})
+	entryServer := entryv1.New(entryv1.Config{
+		TrustDomain:      c.TrustDomain,
+		DataStore:        ds,
+		EntryFetcher:     entryFetcher,
+		ActivityRecorder: activityRecorder,
+	})
+	bundleServer := bundlev1.New(bundlev1.Config{
+		TrustDomain:       c.TrustDomain,
+		DataStore:         ds,
+		UpstreamPublisher: upstreamPublisher,
+	})
 
 	return APIServers{
-		AgentServer: agentv1.New(agentv1.Config{
-			DataStore:   ds,
-			ServerCA:    c.ServerCA,
+		AgentServer:            agentServer,
+		AgentExtensionsServer:  agentServer,
+		BundleServer:           bundleServer,
+		BundleExtensionsServer: bundleServer,
+		DataStoreServer: datastorev1.New(datastorev1.Config{
 			TrustDomain: c.TrustDomain,
-			Catalog:     c.Catalog,
-			Clock:       c.Clock,
-		}),
-		BundleServer: bundlev1.New(bundlev1.Config{
-			TrustDomain:       c.TrustDomain,
-			DataStore:         ds,
-			UpstreamPublisher: upstreamPublisher,
+			DataStore:   ds,
 		}),
 		DebugServer: debugv1.New(debugv1.Config{
 			TrustDomain:  c.TrustDomain,
@@ -141,15 +171,17 @@ func (c *Config) makeAPIServers(entryFetcher api.AuthorizedEntryFetcher) APIServ
 			SVIDObserver: c.SVIDObserver,
 			Uptime:       c.Uptime,
 		}),
-		EntryServer: entryv1.New(entryv1.Config{
-			TrustDomain:  c.TrustDomain,
-			DataStore:    ds,
-			EntryFetcher: entryFetcher,
-		}),
+		EntryServer:           entryServer,
+		EntryExtensionsServer: entryServer,
 		HealthServer: healthv1.New(healthv1.Config{
 			TrustDomain: c.TrustDomain,
 			DataStore:   ds,
 		}),
+		LocalAuthorityServer: localauthorityv1.New(localauthorityv1.Config{
+			TrustDomain: c.TrustDomain,
+			DataStore:   ds,
+			CAManager:   c.AuthorityManager,
+		}),
 		SVIDServer: svidv1.New(svidv1.Config{
 			TrustDomain:  c.TrustDomain,
 			EntryFetcher: entryFetcher,
@@ -28,13 +28,20 @@ import (
 	svidv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/svid/v1"
 	trustdomainv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/trustdomain/v1"
 	"github.com/spiffe/spire/pkg/common/auth"
+	"github.com/spiffe/spire/pkg/common/fflag"
 	"github.com/spiffe/spire/pkg/common/peertracker"
 	"github.com/spiffe/spire/pkg/common/telemetry"
 	"github.com/spiffe/spire/pkg/common/util"
+	"github.com/spiffe/spire/pkg/server/api"
 	"github.com/spiffe/spire/pkg/server/api/middleware"
 	"github.com/spiffe/spire/pkg/server/authpolicy"
 	"github.com/spiffe/spire/pkg/server/datastore"
 	"github.com/spiffe/spire/pkg/server/svid"
+	agentextv1 "github.com/spiffe/spire/proto/spire/api/server/agent/v1"
+	bundleextv1 "github.com/spiffe/spire/proto/spire/api/server/bundle/v1"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+	entryextv1 "github.com/spiffe/spire/proto/spire/api/server/entry/v1"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
 )
 
 const (
@@ -46,6 +53,10 @@ const (
 	// This is the default amount of time between two reloads of the in-memory
 	// entry cache.
 	defaultCacheReloadInterval = 5 * time.Second
+
+	// This is the default amount of time events are kept in the datastore
+	// when the events based cache is enabled.
+	defaultPruneEventsOlderThan = 12 * time.Hour
 )
 
 // Server manages gRPC and HTTP endpoint lifecycle
@@ -76,13 +87,18 @@ type Endpoints struct {
 }
 
 type APIServers struct {
-	AgentServer       agentv1.AgentServer
-	BundleServer      bundlev1.BundleServer
-	DebugServer       debugv1_pb.DebugServer
-	EntryServer       entryv1.EntryServer
-	HealthServer      grpc_health_v1.HealthServer
-	SVIDServer        svidv1.SVIDServer
-	TrustDomainServer trustdomainv1.TrustDomainServer
+	AgentServer            agentv1.AgentServer
+	AgentExtensionsServer  agentextv1.AgentExtensionsServer
+	BundleServer           bundlev1.BundleServer
+	BundleExtensionsServer bundleextv1.BundleExtensionsServer
+	DataStoreServer        datastorev1.DataStoreServer
+	DebugServer            debugv1_pb.DebugServer
+	EntryServer            entryv1.EntryServer
+	EntryExtensionsServer  entryextv1.EntryExtensionsServer
+	HealthServer           grpc_health_v1.HealthServer
+	LocalAuthorityServer   localauthorityv1.LocalAuthorityServer
+	SVIDServer             svidv1.SVIDServer
+	TrustDomainServer      trustdomainv1.TrustDomainServer
 }
 
 // RateLimitConfig holds rate limiting configurations.
@@ -92,6 +108,32 @@ type RateLimitConfig struct {
 
 	// Signing, if true, rate limits JWT and X509 signing requests
 	Signing bool
+
+	// AttestationLimit and AttestationBurst are the number of node
+	// attestation attempts allowed per second, and the maximum burst size,
+	// per IP. If zero, limits.AttestLimitPerIP is used, and the burst size
+	// defaults to the limit.
+	AttestationLimit int
+	AttestationBurst int
+
+	// SigningLimit and SigningBurst are the number of JWT and X509 signing
+	// requests allowed per second (separately), and the maximum burst size,
+	// per caller. If zero, limits.SignLimitPerIP is used, and the burst size
+	// defaults to the limit.
+	SigningLimit int
+	SigningBurst int
+
+	// PushJWTKeyLimit and PushJWTKeyBurst are the number of JWT authorities
+	// that can be published per second, and the maximum burst size, per
+	// caller. If zero, limits.PushJWTKeyLimitPerIP is used, and the burst
+	// size defaults to the limit.
+	PushJWTKeyLimit int
+	PushJWTKeyBurst int
+
+	// PerCallerID, if true, applies the signing and JWT authority publishing
+	// limits per caller SPIFFE ID instead of per IP. Callers without a
+	// SPIFFE ID are still limited per IP.
+	PerCallerID bool
 }
 
 // New creates new endpoints struct
@@ -104,23 +146,40 @@ func New(ctx context.Context, c Config) (*Endpoints, error) {
 		return nil, errors.New("policy engine not provided for new endpoint")
 	}
 
-	buildCacheFn := func(ctx context.Context) (_ entrycache.Cache, err error) {
-		call := telemetry.StartCall(c.Metrics, telemetry.Entry, telemetry.Cache, telemetry.Reload)
-		defer call.Done(&err)
-		return entrycache.BuildFromDataStore(ctx, c.Catalog.GetDataStore())
-	}
-
 	if c.CacheReloadInterval == 0 {
 		c.CacheReloadInterval = defaultCacheReloadInterval
 	}
 
-	ef, err := NewAuthorizedEntryFetcherWithFullCache(ctx, buildCacheFn, c.Log, c.Clock, c.CacheReloadInterval)
-	if err != nil {
-		return nil, err
+	if c.PruneEventsOlderThan == 0 {
+		c.PruneEventsOlderThan = defaultPruneEventsOlderThan
 	}
 
 	ds := c.Catalog.GetDataStore()
 
+	var ef api.AuthorizedEntryFetcher
+	var cacheRebuildTask func(context.Context) error
+	if c.EventsBasedCache {
+		efEventsBasedCache, err := NewAuthorizedEntryFetcherWithEventsBasedCache(ctx, c.Log, c.Metrics, c.Clock, ds, c.CacheReloadInterval, c.PruneEventsOlderThan)
+		if err != nil {
+			return nil, err
+		}
+		ef = efEventsBasedCache
+		cacheRebuildTask = efEventsBasedCache.RunUpdateCacheTask
+	} else {
+		buildCacheFn := func(ctx context.Context) (_ entrycache.Cache, err error) {
+			call := telemetry.StartCall(c.Metrics, telemetry.Entry, telemetry.Cache, telemetry.Reload)
+			defer call.Done(&err)
+			return entrycache.BuildFromDataStore(ctx, ds)
+		}
+
+		efFullCache, err := NewAuthorizedEntryFetcherWithFullCache(ctx, buildCacheFn, c.Log, c.Clock, c.CacheReloadInterval)
+		if err != nil {
+			return nil, err
+		}
+		ef = efFullCache
+		cacheRebuildTask = efFullCache.RunRebuildCacheTask
+	}
+
 	return &Endpoints{
 		TCPAddr:                      c.TCPAddr,
 		LocalAddr:                    c.LocalAddr,
@@ -133,7 +192,7 @@ func New(ctx context.Context, c Config) (*Endpoints, error) {
 		Log:                          c.Log,
 		Metrics:                      c.Metrics,
 		RateLimit:                    c.RateLimit,
-		EntryFetcherCacheRebuildTask: ef.RunRebuildCacheTask,
+		EntryFetcherCacheRebuildTask: cacheRebuildTask,
 		AuditLogEnabled:              c.AuditLogEnabled,
 		AuthPolicyEngine:             c.AuthPolicyEngine,
 		AdminIDs:                     c.AdminIDs,
@@ -154,15 +213,29 @@ func (e *Endpoints) ListenAndServe(ctx context.Context) error {
 	// New APIs
 	agentv1.RegisterAgentServer(tcpServer, e.APIServers.AgentServer)
 	agentv1.RegisterAgentServer(udsServer, e.APIServers.AgentServer)
+	agentextv1.RegisterAgentExtensionsServer(tcpServer, e.APIServers.AgentExtensionsServer)
+	agentextv1.RegisterAgentExtensionsServer(udsServer, e.APIServers.AgentExtensionsServer)
 	bundlev1.RegisterBundleServer(tcpServer, e.APIServers.BundleServer)
 	bundlev1.RegisterBundleServer(udsServer, e.APIServers.BundleServer)
+	bundleextv1.RegisterBundleExtensionsServer(tcpServer, e.APIServers.BundleExtensionsServer)
+	bundleextv1.RegisterBundleExtensionsServer(udsServer, e.APIServers.BundleExtensionsServer)
+	datastorev1.RegisterDataStoreServer(tcpServer, e.APIServers.DataStoreServer)
+	datastorev1.RegisterDataStoreServer(udsServer, e.APIServers.DataStoreServer)
 	entryv1.RegisterEntryServer(tcpServer, e.APIServers.EntryServer)
 	entryv1.RegisterEntryServer(udsServer, e.APIServers.EntryServer)
+	entryextv1.RegisterEntryExtensionsServer(tcpServer, e.APIServers.EntryExtensionsServer)
+	entryextv1.RegisterEntryExtensionsServer(udsServer, e.APIServers.EntryExtensionsServer)
 	svidv1.RegisterSVIDServer(tcpServer, e.APIServers.SVIDServer)
 	svidv1.RegisterSVIDServer(udsServer, e.APIServers.SVIDServer)
 	trustdomainv1.RegisterTrustDomainServer(tcpServer, e.APIServers.TrustDomainServer)
 	trustdomainv1.RegisterTrustDomainServer(udsServer, e.APIServers.TrustDomainServer)
 
+	// The LocalAuthority API is only available when forced rotation is enabled
+	if fflag.IsSet(fflag.FlagForcedRotation) {
+		localauthorityv1.RegisterLocalAuthorityServer(tcpServer, e.APIServers.LocalAuthorityServer)
+		localauthorityv1.RegisterLocalAuthorityServer(udsServer, e.APIServers.LocalAuthorityServer)
+	}
+
 	// Register Health and Debug only on UDS server
 	grpc_health_v1.RegisterHealthServer(udsServer, e.APIServers.HealthServer)
 	debugv1_pb.RegisterDebugServer(udsServer, e.APIServers.DebugServer)
@@ -21,6 +21,7 @@ import (
 	entryv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/entry/v1"
 	svidv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/svid/v1"
 	trustdomainv1 "github.com/spiffe/spire-api-sdk/proto/spire/api/server/trustdomain/v1"
+	"github.com/spiffe/spire/pkg/common/fflag"
 	"github.com/spiffe/spire/pkg/common/util"
 	"github.com/spiffe/spire/pkg/server/authpolicy"
 	"github.com/spiffe/spire/pkg/server/ca/manager"
@@ -28,6 +29,11 @@ import (
 	"github.com/spiffe/spire/pkg/server/datastore"
 	"github.com/spiffe/spire/pkg/server/endpoints/bundle"
 	"github.com/spiffe/spire/pkg/server/svid"
+	agentextv1 "github.com/spiffe/spire/proto/spire/api/server/agent/v1"
+	bundleextv1 "github.com/spiffe/spire/proto/spire/api/server/bundle/v1"
+	datastorev1 "github.com/spiffe/spire/proto/spire/api/server/datastore/v1"
+	entryextv1 "github.com/spiffe/spire/proto/spire/api/server/entry/v1"
+	localauthorityv1 "github.com/spiffe/spire/proto/spire/api/server/localauthority/v1"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/clock"
 	"github.com/spiffe/spire/test/fakes/fakedatastore"
@@ -104,10 +110,15 @@ func TestNew(t *testing.T) {
 	assert.Equal(t, svidObserver, endpoints.SVIDObserver)
 	assert.Equal(t, testTD, endpoints.TrustDomain)
 	assert.NotNil(t, endpoints.APIServers.AgentServer)
+	assert.NotNil(t, endpoints.APIServers.AgentExtensionsServer)
 	assert.NotNil(t, endpoints.APIServers.BundleServer)
+	assert.NotNil(t, endpoints.APIServers.BundleExtensionsServer)
+	assert.NotNil(t, endpoints.APIServers.DataStoreServer)
 	assert.NotNil(t, endpoints.APIServers.DebugServer)
 	assert.NotNil(t, endpoints.APIServers.EntryServer)
+	assert.NotNil(t, endpoints.APIServers.EntryExtensionsServer)
 	assert.NotNil(t, endpoints.APIServers.HealthServer)
+	assert.NotNil(t, endpoints.APIServers.LocalAuthorityServer)
 	assert.NotNil(t, endpoints.APIServers.SVIDServer)
 	assert.NotNil(t, endpoints.BundleEndpointServer)
 	assert.Equal(t, cat.GetDataStore(), endpoints.DataStore)
@@ -158,6 +169,12 @@ func TestNewErrorCreatingAuthorizedEntryFetcher(t *testing.T) {
 }
 
 func TestListenAndServe(t *testing.T) {
+	// The LocalAuthority API is only registered when forced rotation is enabled
+	require.NoError(t, fflag.Load(fflag.RawConfig{"forced_rotation"}))
+	defer func() {
+		require.NoError(t, fflag.Unload())
+	}()
+
 	ctx := context.Background()
 	ca := testca.New(t, testTD)
 	federatedCA := testca.New(t, foreignFederatedTD)
@@ -200,13 +217,18 @@ func TestListenAndServe(t *testing.T) {
 		DataStore:    ds,
 		BundleCache:  bundle.NewCache(ds, clk),
 		APIServers: APIServers{
-			AgentServer:       &agentv1.UnimplementedAgentServer{},
-			BundleServer:      &bundlev1.UnimplementedBundleServer{},
-			DebugServer:       &debugv1.UnimplementedDebugServer{},
-			EntryServer:       &entryv1.UnimplementedEntryServer{},
-			HealthServer:      &grpc_health_v1.UnimplementedHealthServer{},
-			SVIDServer:        &svidv1.UnimplementedSVIDServer{},
-			TrustDomainServer: &trustdomainv1.UnimplementedTrustDomainServer{},
+			AgentServer:            &agentv1.UnimplementedAgentServer{},
+			AgentExtensionsServer:  &agentextv1.UnimplementedAgentExtensionsServer{},
+			BundleServer:           &bundlev1.UnimplementedBundleServer{},
+			BundleExtensionsServer: &bundleextv1.UnimplementedBundleExtensionsServer{},
+			DataStoreServer:        &datastorev1.UnimplementedDataStoreServer{},
+			DebugServer:            &debugv1.UnimplementedDebugServer{},
+			EntryServer:            &entryv1.UnimplementedEntryServer{},
+			EntryExtensionsServer:  &entryextv1.UnimplementedEntryExtensionsServer{},
+			HealthServer:           &grpc_health_v1.UnimplementedHealthServer{},
+			LocalAuthorityServer:   &localauthorityv1.UnimplementedLocalAuthorityServer{},
+			SVIDServer:             &svidv1.UnimplementedSVIDServer{},
+			TrustDomainServer:      &trustdomainv1.UnimplementedTrustDomainServer{},
 		},
 		BundleEndpointServer:         bundleEndpointServer,
 		Log:                          log,
@@ -282,6 +304,9 @@ func TestListenAndServe(t *testing.T) {
 	t.Run("Agent", func(t *testing.T) {
 		testAgentAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
 	})
+	t.Run("AgentExtensions", func(t *testing.T) {
+		testAgentExtensionsAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
+	})
 	t.Run("Debug", func(t *testing.T) {
 		testDebugAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
 	})
@@ -291,15 +316,27 @@ func TestListenAndServe(t *testing.T) {
 	t.Run("Bundle", func(t *testing.T) {
 		testBundleAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
 	})
+	t.Run("BundleExtensions", func(t *testing.T) {
+		testBundleExtensionsAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
+	})
 	t.Run("Entry", func(t *testing.T) {
 		testEntryAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
 	})
+	t.Run("EntryExtensions", func(t *testing.T) {
+		testEntryExtensionsAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
+	})
 	t.Run("SVID", func(t *testing.T) {
 		testSVIDAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
 	})
 	t.Run("TrustDomain", func(t *testing.T) {
 		testTrustDomainAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
 	})
+	t.Run("LocalAuthority", func(t *testing.T) {
+		testLocalAuthorityAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
+	})
+	t.Run("DataStore", func(t *testing.T) {
+		testDataStoreAPI(ctx, t, localConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn)
+	})
 
 	t.Run("Access denied to remote caller", func(t *testing.T) {
 		testRemoteCaller(ctx, t, target)
@@ -460,6 +497,126 @@ func testAgentAPI(ctx context.Context, t *testing.T, udsConn, noauthConn, agentC
 	})
 }
 
+func testAgentExtensionsAPI(ctx context.Context, t *testing.T, udsConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn *grpc.ClientConn) {
+	t.Run("UDS", func(t *testing.T) {
+		testAuthorization(ctx, t, agentextv1.NewAgentExtensionsClient(udsConn), map[string]bool{
+			"SearchAgents": true,
+			"GetAgentInfo": true,
+		})
+	})
+
+	t.Run("NoAuth", func(t *testing.T) {
+		testAuthorization(ctx, t, agentextv1.NewAgentExtensionsClient(noauthConn), map[string]bool{
+			"SearchAgents": false,
+			"GetAgentInfo": false,
+		})
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		testAuthorization(ctx, t, agentextv1.NewAgentExtensionsClient(agentConn), map[string]bool{
+			"SearchAgents": false,
+			"GetAgentInfo": false,
+		})
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, agentextv1.NewAgentExtensionsClient(adminConn), map[string]bool{
+			"SearchAgents": true,
+			"GetAgentInfo": true,
+		})
+	})
+
+	t.Run("Federated Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, agentextv1.NewAgentExtensionsClient(federatedAdminConn), map[string]bool{
+			"SearchAgents": true,
+			"GetAgentInfo": true,
+		})
+	})
+
+	t.Run("Downstream", func(t *testing.T) {
+		testAuthorization(ctx, t, agentextv1.NewAgentExtensionsClient(downstreamConn), map[string]bool{
+			"SearchAgents": false,
+			"GetAgentInfo": false,
+		})
+	})
+}
+
+func testBundleExtensionsAPI(ctx context.Context, t *testing.T, udsConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn *grpc.ClientConn) {
+	t.Run("UDS", func(t *testing.T) {
+		testAuthorization(ctx, t, bundleextv1.NewBundleExtensionsClient(udsConn), map[string]bool{
+			"GetTaintedAuthorities": true,
+		})
+	})
+
+	t.Run("NoAuth", func(t *testing.T) {
+		testAuthorization(ctx, t, bundleextv1.NewBundleExtensionsClient(noauthConn), map[string]bool{
+			"GetTaintedAuthorities": true,
+		})
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		testAuthorization(ctx, t, bundleextv1.NewBundleExtensionsClient(agentConn), map[string]bool{
+			"GetTaintedAuthorities": true,
+		})
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, bundleextv1.NewBundleExtensionsClient(adminConn), map[string]bool{
+			"GetTaintedAuthorities": true,
+		})
+	})
+
+	t.Run("Federated Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, bundleextv1.NewBundleExtensionsClient(federatedAdminConn), map[string]bool{
+			"GetTaintedAuthorities": true,
+		})
+	})
+
+	t.Run("Downstream", func(t *testing.T) {
+		testAuthorization(ctx, t, bundleextv1.NewBundleExtensionsClient(downstreamConn), map[string]bool{
+			"GetTaintedAuthorities": true,
+		})
+	})
+}
+
+func testEntryExtensionsAPI(ctx context.Context, t *testing.T, udsConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn *grpc.ClientConn) {
+	t.Run("UDS", func(t *testing.T) {
+		testAuthorization(ctx, t, entryextv1.NewEntryExtensionsClient(udsConn), map[string]bool{
+			"SearchEntries": true,
+		})
+	})
+
+	t.Run("NoAuth", func(t *testing.T) {
+		testAuthorization(ctx, t, entryextv1.NewEntryExtensionsClient(noauthConn), map[string]bool{
+			"SearchEntries": false,
+		})
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		testAuthorization(ctx, t, entryextv1.NewEntryExtensionsClient(agentConn), map[string]bool{
+			"SearchEntries": false,
+		})
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, entryextv1.NewEntryExtensionsClient(adminConn), map[string]bool{
+			"SearchEntries": true,
+		})
+	})
+
+	t.Run("Federated Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, entryextv1.NewEntryExtensionsClient(federatedAdminConn), map[string]bool{
+			"SearchEntries": true,
+		})
+	})
+
+	t.Run("Downstream", func(t *testing.T) {
+		testAuthorization(ctx, t, entryextv1.NewEntryExtensionsClient(downstreamConn), map[string]bool{
+			"SearchEntries": false,
+		})
+	})
+}
+
 func testHealthAPI(ctx context.Context, t *testing.T, udsConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn *grpc.ClientConn) {
 	t.Run("UDS", func(t *testing.T) {
 		testAuthorization(ctx, t, grpc_health_v1.NewHealthClient(udsConn), map[string]bool{
@@ -838,6 +995,142 @@ func testTrustDomainAPI(ctx context.Context, t *testing.T, udsConn, noauthConn,
 	})
 }
 
+func testLocalAuthorityAPI(ctx context.Context, t *testing.T, udsConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn *grpc.ClientConn) {
+	t.Run("UDS", func(t *testing.T) {
+		testAuthorization(ctx, t, localauthorityv1.NewLocalAuthorityClient(udsConn), map[string]bool{
+			"GetJWTAuthorityState":  true,
+			"PrepareJWTAuthority":   true,
+			"ActivateJWTAuthority":  true,
+			"TaintJWTAuthority":     true,
+			"RevokeJWTAuthority":    true,
+			"GetX509AuthorityState": true,
+			"PrepareX509Authority":  true,
+			"ActivateX509Authority": true,
+			"TaintX509Authority":    true,
+			"RevokeX509Authority":   true,
+		})
+	})
+
+	t.Run("NoAuth", func(t *testing.T) {
+		testAuthorization(ctx, t, localauthorityv1.NewLocalAuthorityClient(noauthConn), map[string]bool{
+			"GetJWTAuthorityState":  false,
+			"PrepareJWTAuthority":   false,
+			"ActivateJWTAuthority":  false,
+			"TaintJWTAuthority":     false,
+			"RevokeJWTAuthority":    false,
+			"GetX509AuthorityState": false,
+			"PrepareX509Authority":  false,
+			"ActivateX509Authority": false,
+			"TaintX509Authority":    false,
+			"RevokeX509Authority":   false,
+		})
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		testAuthorization(ctx, t, localauthorityv1.NewLocalAuthorityClient(agentConn), map[string]bool{
+			"GetJWTAuthorityState":  false,
+			"PrepareJWTAuthority":   false,
+			"ActivateJWTAuthority":  false,
+			"TaintJWTAuthority":     false,
+			"RevokeJWTAuthority":    false,
+			"GetX509AuthorityState": false,
+			"PrepareX509Authority":  false,
+			"ActivateX509Authority": false,
+			"TaintX509Authority":    false,
+			"RevokeX509Authority":   false,
+		})
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, localauthorityv1.NewLocalAuthorityClient(adminConn), map[string]bool{
+			"GetJWTAuthorityState":  true,
+			"PrepareJWTAuthority":   true,
+			"ActivateJWTAuthority":  true,
+			"TaintJWTAuthority":     true,
+			"RevokeJWTAuthority":    true,
+			"GetX509AuthorityState": true,
+			"PrepareX509Authority":  true,
+			"ActivateX509Authority": true,
+			"TaintX509Authority":    true,
+			"RevokeX509Authority":   true,
+		})
+	})
+
+	t.Run("Federated Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, localauthorityv1.NewLocalAuthorityClient(federatedAdminConn), map[string]bool{
+			"GetJWTAuthorityState":  true,
+			"PrepareJWTAuthority":   true,
+			"ActivateJWTAuthority":  true,
+			"TaintJWTAuthority":     true,
+			"RevokeJWTAuthority":    true,
+			"GetX509AuthorityState": true,
+			"PrepareX509Authority":  true,
+			"ActivateX509Authority": true,
+			"TaintX509Authority":    true,
+			"RevokeX509Authority":   true,
+		})
+	})
+
+	t.Run("Downstream", func(t *testing.T) {
+		testAuthorization(ctx, t, localauthorityv1.NewLocalAuthorityClient(downstreamConn), map[string]bool{
+			"GetJWTAuthorityState":  false,
+			"PrepareJWTAuthority":   false,
+			"ActivateJWTAuthority":  false,
+			"TaintJWTAuthority":     false,
+			"RevokeJWTAuthority":    false,
+			"GetX509AuthorityState": false,
+			"PrepareX509Authority":  false,
+			"ActivateX509Authority": false,
+			"TaintX509Authority":    false,
+			"RevokeX509Authority":   false,
+		})
+	})
+}
+
+func testDataStoreAPI(ctx context.Context, t *testing.T, udsConn, noauthConn, agentConn, adminConn, federatedAdminConn, downstreamConn *grpc.ClientConn) {
+	t.Run("UDS", func(t *testing.T) {
+		testAuthorization(ctx, t, datastorev1.NewDataStoreClient(udsConn), map[string]bool{
+			"Export": true,
+			"Import": true,
+		})
+	})
+
+	t.Run("NoAuth", func(t *testing.T) {
+		testAuthorization(ctx, t, datastorev1.NewDataStoreClient(noauthConn), map[string]bool{
+			"Export": false,
+			"Import": false,
+		})
+	})
+
+	t.Run("Agent", func(t *testing.T) {
+		testAuthorization(ctx, t, datastorev1.NewDataStoreClient(agentConn), map[string]bool{
+			"Export": false,
+			"Import": false,
+		})
+	})
+
+	t.Run("Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, datastorev1.NewDataStoreClient(adminConn), map[string]bool{
+			"Export": true,
+			"Import": true,
+		})
+	})
+
+	t.Run("Federated Admin", func(t *testing.T) {
+		testAuthorization(ctx, t, datastorev1.NewDataStoreClient(federatedAdminConn), map[string]bool{
+			"Export": true,
+			"Import": true,
+		})
+	})
+
+	t.Run("Downstream", func(t *testing.T) {
+		testAuthorization(ctx, t, datastorev1.NewDataStoreClient(downstreamConn), map[string]bool{
+			"Export": false,
+			"Import": false,
+		})
+	})
+}
+
 // testAuthorization makes an RPC for each method on the client interface and
 // asserts that the RPC was authorized or not. If a method is not represented
 // in the expectedAuthResults, or a method in expectedAuthResults does not
@@ -903,8 +1196,11 @@ func callRPC(ctx context.Context, t *testing.T, mv reflect.Value) []reflect.Valu
 		// Check for error
 		require.Nil(t, out[1].Interface(), "should have succeeded getting the stream")
 
-		// Invoke Recv()
+		// Invoke Recv(), or CloseAndRecv() for client streams
 		rv := out[0].MethodByName("Recv")
+		if !rv.IsValid() {
+			rv = out[0].MethodByName("CloseAndRecv")
+		}
 		out = rv.Call([]reflect.Value{})
 	}
 
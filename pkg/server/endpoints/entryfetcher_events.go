@@ -0,0 +1,297 @@
+package endpoints
+
+import (
+	"context"
+	"time"
+
+	"github.com/andres-erbsen/clock"
+	"github.com/sirupsen/logrus"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/api"
+	"github.com/spiffe/spire/pkg/server/cache/entrycache"
+	"github.com/spiffe/spire/pkg/server/datastore"
+)
+
+var _ api.AuthorizedEntryFetcher = (*AuthorizedEntryFetcherWithEventsBasedCache)(nil)
+
+const (
+	// missedEventTimeout is how long an event ID that was skipped over (e.g.
+	// because the transaction that created it had not committed yet when
+	// the events were listed) is waited on before giving up on it.
+	missedEventTimeout = time.Minute
+
+	// pruneEventsInterval is how often events older than the configured
+	// retention are pruned from the datastore.
+	pruneEventsInterval = time.Minute
+)
+
+// AuthorizedEntryFetcherWithEventsBasedCache keeps an in-memory entry cache
+// up to date by applying the registration entry and attested node events
+// recorded by the datastore, instead of periodically rebuilding the whole
+// cache.
+type AuthorizedEntryFetcherWithEventsBasedCache struct {
+	cache                *entrycache.MutableEntryCache
+	ds                   datastore.DataStore
+	clk                  clock.Clock
+	log                  logrus.FieldLogger
+	metrics              telemetry.Metrics
+	cacheReloadInterval  time.Duration
+	pruneEventsOlderThan time.Duration
+
+	lastEntryEventID    uint
+	lastNodeEventID     uint
+	missedEntryEvents   map[uint]time.Time
+	missedNodeEvents    map[uint]time.Time
+	lastEventsPruneTime time.Time
+}
+
+func NewAuthorizedEntryFetcherWithEventsBasedCache(ctx context.Context, log logrus.FieldLogger, metrics telemetry.Metrics, clk clock.Clock, ds datastore.DataStore, cacheReloadInterval, pruneEventsOlderThan time.Duration) (*AuthorizedEntryFetcherWithEventsBasedCache, error) {
+	log.Info("Building event-based in-memory entry cache")
+
+	// The last event IDs are retrieved before building the cache so changes
+	// made while the cache is being built are applied on the first update.
+	entryEvents, err := ds.ListRegistrationEntriesEvents(ctx, &datastore.ListRegistrationEntriesEventsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	nodeEvents, err := ds.ListAttestedNodesEvents(ctx, &datastore.ListAttestedNodesEventsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	cache, err := entrycache.BuildMutableFromDataStore(ctx, ds)
+	if err != nil {
+		return nil, err
+	}
+
+	a := &AuthorizedEntryFetcherWithEventsBasedCache{
+		cache:                cache,
+		ds:                   ds,
+		clk:                  clk,
+		log:                  log,
+		metrics:              metrics,
+		cacheReloadInterval:  cacheReloadInterval,
+		pruneEventsOlderThan: pruneEventsOlderThan,
+		missedEntryEvents:    make(map[uint]time.Time),
+		missedNodeEvents:     make(map[uint]time.Time),
+		lastEventsPruneTime:  clk.Now(),
+	}
+	if n := len(entryEvents.Events); n > 0 {
+		a.lastEntryEventID = entryEvents.Events[n-1].EventID
+	}
+	if n := len(nodeEvents.Events); n > 0 {
+		a.lastNodeEventID = nodeEvents.Events[n-1].EventID
+	}
+
+	log.Info("Completed building event-based in-memory entry cache")
+	return a, nil
+}
+
+func (a *AuthorizedEntryFetcherWithEventsBasedCache) FetchAuthorizedEntries(_ context.Context, agentID spiffeid.ID) ([]*types.Entry, error) {
+	return a.cache.GetAuthorizedEntries(agentID), nil
+}
+
+// RunUpdateCacheTask starts a ticker which applies the events recorded since
+// the last update to the in-memory entry cache, and prunes old events.
+func (a *AuthorizedEntryFetcherWithEventsBasedCache) RunUpdateCacheTask(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			a.log.Debug("Stopping in-memory entry cache hydrator")
+			return nil
+		case <-a.clk.After(a.cacheReloadInterval):
+			if err := a.updateCache(ctx); err != nil {
+				a.log.WithError(err).Error("Failed to update entry cache")
+			}
+			if err := a.maybePruneEvents(ctx); err != nil {
+				a.log.WithError(err).Error("Failed to prune events")
+			}
+		}
+	}
+}
+
+func (a *AuthorizedEntryFetcherWithEventsBasedCache) updateCache(ctx context.Context) (err error) {
+	call := telemetry.StartCall(a.metrics, telemetry.Entry, telemetry.Cache, telemetry.Update)
+	defer call.Done(&err)
+
+	if err := a.updateRegistrationEntriesCache(ctx); err != nil {
+		return err
+	}
+	return a.updateAttestedNodesCache(ctx)
+}
+
+func (a *AuthorizedEntryFetcherWithEventsBasedCache) updateRegistrationEntriesCache(ctx context.Context) error {
+	resp, err := a.ds.ListRegistrationEntriesEvents(ctx, &datastore.ListRegistrationEntriesEventsRequest{
+		GreaterThanEventID: listEventsFrom(a.lastEntryEventID, a.missedEntryEvents),
+	})
+	if err != nil {
+		return err
+	}
+
+	eventIDs := make([]uint, 0, len(resp.Events))
+	for _, event := range resp.Events {
+		eventIDs = append(eventIDs, event.EventID)
+	}
+
+	updated := make(map[string]struct{})
+	for _, event := range resp.Events {
+		if !isUnprocessedEvent(event.EventID, a.lastEntryEventID, a.missedEntryEvents) {
+			continue
+		}
+		if _, ok := updated[event.EntryID]; ok {
+			continue
+		}
+		if err := a.updateRegistrationEntry(ctx, event.EntryID); err != nil {
+			return err
+		}
+		updated[event.EntryID] = struct{}{}
+	}
+
+	a.lastEntryEventID = trackEvents(a.clk.Now(), a.lastEntryEventID, a.missedEntryEvents, eventIDs)
+	return nil
+}
+
+func (a *AuthorizedEntryFetcherWithEventsBasedCache) updateRegistrationEntry(ctx context.Context, entryID string) error {
+	commonEntry, err := a.ds.FetchRegistrationEntry(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	if commonEntry == nil {
+		a.cache.RemoveEntry(entryID)
+		return nil
+	}
+
+	entry, err := api.RegistrationEntryToProto(commonEntry)
+	if err != nil {
+		// Entries with invalid SPIFFE IDs are ignored, as they are when
+		// building the full cache. Operators are notified that they are
+		// ignored on server startup (see pkg/server/scanentries.go)
+		a.cache.RemoveEntry(entryID)
+		return nil
+	}
+	a.cache.UpdateEntry(entry)
+	return nil
+}
+
+func (a *AuthorizedEntryFetcherWithEventsBasedCache) updateAttestedNodesCache(ctx context.Context) error {
+	resp, err := a.ds.ListAttestedNodesEvents(ctx, &datastore.ListAttestedNodesEventsRequest{
+		GreaterThanEventID: listEventsFrom(a.lastNodeEventID, a.missedNodeEvents),
+	})
+	if err != nil {
+		return err
+	}
+
+	eventIDs := make([]uint, 0, len(resp.Events))
+	for _, event := range resp.Events {
+		eventIDs = append(eventIDs, event.EventID)
+	}
+
+	updated := make(map[string]struct{})
+	for _, event := range resp.Events {
+		if !isUnprocessedEvent(event.EventID, a.lastNodeEventID, a.missedNodeEvents) {
+			continue
+		}
+		if _, ok := updated[event.SpiffeID]; ok {
+			continue
+		}
+		if err := a.updateAttestedNode(ctx, event.SpiffeID); err != nil {
+			return err
+		}
+		updated[event.SpiffeID] = struct{}{}
+	}
+
+	a.lastNodeEventID = trackEvents(a.clk.Now(), a.lastNodeEventID, a.missedNodeEvents, eventIDs)
+	return nil
+}
+
+func (a *AuthorizedEntryFetcherWithEventsBasedCache) updateAttestedNode(ctx context.Context, spiffeID string) error {
+	agentID, err := spiffeid.FromString(spiffeID)
+	if err != nil {
+		a.log.WithError(err).WithField(telemetry.SPIFFEID, spiffeID).Warn("Ignoring event for attested node with invalid SPIFFE ID")
+		return nil
+	}
+
+	node, err := a.ds.FetchAttestedNode(ctx, spiffeID)
+	if err != nil {
+		return err
+	}
+	// Agents with expired SVIDs are left out, as they are when building
+	// the full cache.
+	if node == nil || time.Unix(node.CertNotAfter, 0).Before(a.clk.Now()) {
+		a.cache.RemoveAgent(agentID)
+		return nil
+	}
+
+	selectors, err := a.ds.GetNodeSelectors(ctx, spiffeID, datastore.RequireCurrent)
+	if err != nil {
+		return err
+	}
+	a.cache.UpdateAgent(agentID, api.ProtoFromSelectors(selectors))
+	return nil
+}
+
+func (a *AuthorizedEntryFetcherWithEventsBasedCache) maybePruneEvents(ctx context.Context) error {
+	now := a.clk.Now()
+	if now.Sub(a.lastEventsPruneTime) < pruneEventsInterval {
+		return nil
+	}
+	a.lastEventsPruneTime = now
+
+	if err := a.ds.PruneRegistrationEntriesEvents(ctx, a.pruneEventsOlderThan); err != nil {
+		return err
+	}
+	return a.ds.PruneAttestedNodesEvents(ctx, a.pruneEventsOlderThan)
+}
+
+// listEventsFrom returns the event ID that events need to be listed after in
+// order to get both new events and the events that were previously missed.
+func listEventsFrom(lastEventID uint, missed map[uint]time.Time) uint {
+	from := lastEventID
+	for eventID := range missed {
+		if eventID-1 < from {
+			from = eventID - 1
+		}
+	}
+	return from
+}
+
+// isUnprocessedEvent returns true if the event is either newer than the last
+// event processed or one that was previously missed.
+func isUnprocessedEvent(eventID, lastEventID uint, missed map[uint]time.Time) bool {
+	if eventID > lastEventID {
+		return true
+	}
+	_, ok := missed[eventID]
+	return ok
+}
+
+// trackEvents records the listed events as processed, keeping track of any
+// event IDs that were skipped over so they can be picked up by later updates,
+// and returns the new last event ID. Skipped event IDs are given up on after
+// missedEventTimeout, since they may belong to transactions that were rolled
+// back.
+func trackEvents(now time.Time, lastEventID uint, missed map[uint]time.Time, eventIDs []uint) uint {
+	for _, eventID := range eventIDs {
+		if eventID <= lastEventID {
+			delete(missed, eventID)
+			continue
+		}
+		// Gaps can only be detected once a first event has been seen, since
+		// the event IDs before that may have been pruned.
+		if lastEventID != 0 {
+			for skipped := lastEventID + 1; skipped < eventID; skipped++ {
+				missed[skipped] = now
+			}
+		}
+		lastEventID = eventID
+	}
+
+	for eventID, firstMissed := range missed {
+		if now.Sub(firstMissed) >= missedEventTimeout {
+			delete(missed, eventID)
+		}
+	}
+	return lastEventID
+}
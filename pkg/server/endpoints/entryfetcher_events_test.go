@@ -0,0 +1,176 @@
+package endpoints
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"github.com/spiffe/spire/pkg/server/datastore"
+	"github.com/spiffe/spire/proto/spire/common"
+	"github.com/spiffe/spire/test/clock"
+	"github.com/spiffe/spire/test/fakes/fakedatastore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorizedEntryFetcherWithEventsBasedCache(t *testing.T) {
+	ctx := context.Background()
+	log, _ := test.NewNullLogger()
+	clk := clock.NewMock(t)
+	ds := fakedatastore.New(t)
+
+	agentID := spiffeid.RequireFromPath(trustDomain, "/spire/agent/1")
+	serverID := spiffeid.RequireFromPath(trustDomain, "/spire/server")
+
+	// Create an entry before the cache is built
+	workload, err := ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+		ParentId:  agentID.String(),
+		SpiffeId:  spiffeid.RequireFromPath(trustDomain, "/workload").String(),
+		Selectors: []*common.Selector{{Type: "not", Value: "relevant"}},
+	})
+	require.NoError(t, err)
+
+	ef, err := NewAuthorizedEntryFetcherWithEventsBasedCache(ctx, log, telemetry.Blackhole{}, clk, ds, defaultCacheReloadInterval, defaultPruneEventsOlderThan)
+	require.NoError(t, err)
+	require.NotNil(t, ef)
+
+	assertAuthorizedEntryIDs := func(expected ...string) {
+		t.Helper()
+		entries, err := ef.FetchAuthorizedEntries(ctx, agentID)
+		require.NoError(t, err)
+		var actual []string
+		for _, entry := range entries {
+			actual = append(actual, entry.Id)
+		}
+		assert.ElementsMatch(t, expected, actual)
+	}
+
+	assertAuthorizedEntryIDs(workload.EntryId)
+
+	// Attest the agent and create an alias matching its selectors
+	_, err = ds.CreateAttestedNode(ctx, &common.AttestedNode{
+		SpiffeId:            agentID.String(),
+		AttestationDataType: "test-nodeattestor",
+		CertSerialNumber:    "1",
+		CertNotAfter:        clk.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	require.NoError(t, ds.SetNodeSelectors(ctx, agentID.String(), []*common.Selector{{Type: "a", Value: "1"}}))
+	alias, err := ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+		ParentId:  serverID.String(),
+		SpiffeId:  spiffeid.RequireFromPath(trustDomain, "/alias").String(),
+		Selectors: []*common.Selector{{Type: "a", Value: "1"}},
+	})
+	require.NoError(t, err)
+
+	// Changes are not picked up until the cache is updated
+	assertAuthorizedEntryIDs(workload.EntryId)
+	require.NoError(t, ef.updateCache(ctx))
+	assertAuthorizedEntryIDs(workload.EntryId, alias.EntryId)
+
+	// Deleting an entry removes it from the cache
+	_, err = ds.DeleteRegistrationEntry(ctx, workload.EntryId)
+	require.NoError(t, err)
+	require.NoError(t, ef.updateCache(ctx))
+	assertAuthorizedEntryIDs(alias.EntryId)
+
+	// Changing the agent selectors so they no longer match the alias
+	require.NoError(t, ds.SetNodeSelectors(ctx, agentID.String(), []*common.Selector{{Type: "b", Value: "2"}}))
+	require.NoError(t, ef.updateCache(ctx))
+	assertAuthorizedEntryIDs()
+
+	// Restore the selectors, then delete the agent
+	require.NoError(t, ds.SetNodeSelectors(ctx, agentID.String(), []*common.Selector{{Type: "a", Value: "1"}}))
+	require.NoError(t, ef.updateCache(ctx))
+	assertAuthorizedEntryIDs(alias.EntryId)
+	_, err = ds.DeleteAttestedNode(ctx, agentID.String())
+	require.NoError(t, err)
+	require.NoError(t, ef.updateCache(ctx))
+	assertAuthorizedEntryIDs()
+}
+
+func TestAuthorizedEntryFetcherWithEventsBasedCachePrunesEvents(t *testing.T) {
+	ctx := context.Background()
+	log, _ := test.NewNullLogger()
+	clk := clock.NewMock(t)
+	ds := fakedatastore.New(t)
+	ds.SetClock(clk)
+
+	ef, err := NewAuthorizedEntryFetcherWithEventsBasedCache(ctx, log, telemetry.Blackhole{}, clk, ds, defaultCacheReloadInterval, time.Hour)
+	require.NoError(t, err)
+
+	agentID := spiffeid.RequireFromPath(trustDomain, "/spire/agent/1")
+	_, err = ds.CreateRegistrationEntry(ctx, &common.RegistrationEntry{
+		ParentId:  agentID.String(),
+		SpiffeId:  spiffeid.RequireFromPath(trustDomain, "/workload").String(),
+		Selectors: []*common.Selector{{Type: "not", Value: "relevant"}},
+	})
+	require.NoError(t, err)
+	_, err = ds.CreateAttestedNode(ctx, &common.AttestedNode{
+		SpiffeId:            agentID.String(),
+		AttestationDataType: "test-nodeattestor",
+		CertSerialNumber:    "1",
+		CertNotAfter:        clk.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	requireEventCount := func(count int) {
+		t.Helper()
+		entryEvents, err := ds.ListRegistrationEntriesEvents(ctx, &datastore.ListRegistrationEntriesEventsRequest{})
+		require.NoError(t, err)
+		require.Len(t, entryEvents.Events, count)
+		nodeEvents, err := ds.ListAttestedNodesEvents(ctx, &datastore.ListAttestedNodesEventsRequest{})
+		require.NoError(t, err)
+		require.Len(t, nodeEvents.Events, count)
+	}
+
+	// Events are not pruned until the prune interval elapses
+	require.NoError(t, ef.maybePruneEvents(ctx))
+	requireEventCount(1)
+
+	// Events newer than the retention window are kept
+	clk.Add(pruneEventsInterval)
+	require.NoError(t, ef.maybePruneEvents(ctx))
+	requireEventCount(1)
+
+	clk.Add(time.Hour - 2*pruneEventsInterval)
+	require.NoError(t, ef.maybePruneEvents(ctx))
+	requireEventCount(1)
+
+	// Events older than the retention window are pruned
+	clk.Add(2 * pruneEventsInterval)
+	require.NoError(t, ef.maybePruneEvents(ctx))
+	requireEventCount(0)
+}
+
+func TestTrackEvents(t *testing.T) {
+	now := time.Now()
+	missed := make(map[uint]time.Time)
+
+	// Gaps are not tracked until a first event has been seen
+	last := trackEvents(now, 0, missed, []uint{3, 4})
+	assert.Equal(t, uint(4), last)
+	assert.Empty(t, missed)
+
+	// Skipped event IDs are tracked as missed
+	last = trackEvents(now, last, missed, []uint{7})
+	assert.Equal(t, uint(7), last)
+	assert.Equal(t, map[uint]time.Time{5: now, 6: now}, missed)
+	assert.Equal(t, uint(4), listEventsFrom(last, missed))
+	assert.True(t, isUnprocessedEvent(5, last, missed))
+	assert.False(t, isUnprocessedEvent(4, last, missed))
+
+	// Missed events that show up are no longer tracked
+	last = trackEvents(now, last, missed, []uint{5, 7})
+	assert.Equal(t, uint(7), last)
+	assert.Equal(t, map[uint]time.Time{6: now}, missed)
+
+	// Missed events are given up on after the timeout
+	last = trackEvents(now.Add(missedEventTimeout), last, missed, nil)
+	assert.Equal(t, uint(7), last)
+	assert.Empty(t, missed)
+	assert.Equal(t, uint(7), listEventsFrom(last, missed))
+}
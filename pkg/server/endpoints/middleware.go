@@ -89,7 +89,13 @@ func AgentAuthorizer(ds datastore.DataStore, clk clock.Clock) middleware.AgentAu
 				CertNotAfter:     attestedNode.NewCertNotAfter,
 				CertSerialNumber: attestedNode.NewCertSerialNumber,
 				CanReattest:      attestedNode.CanReattest,
-			}, nil)
+			}, &common.AttestedNodeMask{
+				CertNotAfter:        true,
+				CertSerialNumber:    true,
+				NewCertNotAfter:     true,
+				NewCertSerialNumber: true,
+				CanReattest:         true,
+			})
 			if err != nil {
 				log.WithFields(logrus.Fields{
 					telemetry.SVIDSerialNumber: agentSVID.SerialNumber.String(),
@@ -110,23 +116,32 @@ func AgentAuthorizer(ds datastore.DataStore, clk clock.Clock) middleware.AgentAu
 }
 
 func RateLimits(config RateLimitConfig) map[string]api.RateLimiter {
+	// Attesting agents do not have a SPIFFE ID yet, so attestation is always
+	// limited per IP.
+	perCallerLimit := middleware.PerIPLimitWithBurst
+	if config.PerCallerID {
+		perCallerLimit = middleware.PerCallerLimit
+	}
+
 	noLimit := middleware.NoLimit()
 	attestLimit := middleware.DisabledLimit()
 	if config.Attestation {
-		attestLimit = middleware.PerIPLimit(limits.AttestLimitPerIP)
+		limit, burst := limitAndBurst(config.AttestationLimit, config.AttestationBurst, limits.AttestLimitPerIP)
+		attestLimit = middleware.PerIPLimitWithBurst(limit, burst)
 	}
 
+	signLimit, signBurst := limitAndBurst(config.SigningLimit, config.SigningBurst, limits.SignLimitPerIP)
 	csrLimit := middleware.DisabledLimit()
 	if config.Signing {
-		csrLimit = middleware.PerIPLimit(limits.SignLimitPerIP)
+		csrLimit = perCallerLimit(signLimit, signBurst)
 	}
 
 	jsrLimit := middleware.DisabledLimit()
 	if config.Signing {
-		jsrLimit = middleware.PerIPLimit(limits.SignLimitPerIP)
+		jsrLimit = perCallerLimit(signLimit, signBurst)
 	}
 
-	pushJWTKeyLimit := middleware.PerIPLimit(limits.PushJWTKeyLimitPerIP)
+	pushJWTKeyLimit := perCallerLimit(limitAndBurst(config.PushJWTKeyLimit, config.PushJWTKeyBurst, limits.PushJWTKeyLimitPerIP))
 
 	return map[string]api.RateLimiter{
 		"/spire.api.server.svid.v1.SVID/MintX509SVID":                                    noLimit,
@@ -144,6 +159,7 @@ func RateLimits(config RateLimitConfig) map[string]api.RateLimiter {
 		"/spire.api.server.bundle.v1.Bundle/BatchUpdateFederatedBundle":                  noLimit,
 		"/spire.api.server.bundle.v1.Bundle/BatchSetFederatedBundle":                     noLimit,
 		"/spire.api.server.bundle.v1.Bundle/BatchDeleteFederatedBundle":                  noLimit,
+		"/spire.api.server.bundle.v1.BundleExtensions/GetTaintedAuthorities":             noLimit,
 		"/spire.api.server.debug.v1.Debug/GetInfo":                                       noLimit,
 		"/spire.api.server.entry.v1.Entry/CountEntries":                                  noLimit,
 		"/spire.api.server.entry.v1.Entry/ListEntries":                                   noLimit,
@@ -152,6 +168,7 @@ func RateLimits(config RateLimitConfig) map[string]api.RateLimiter {
 		"/spire.api.server.entry.v1.Entry/BatchUpdateEntry":                              noLimit,
 		"/spire.api.server.entry.v1.Entry/BatchDeleteEntry":                              noLimit,
 		"/spire.api.server.entry.v1.Entry/GetAuthorizedEntries":                          noLimit,
+		"/spire.api.server.entry.v1.EntryExtensions/SearchEntries":                       noLimit,
 		"/spire.api.server.agent.v1.Agent/CountAgents":                                   noLimit,
 		"/spire.api.server.agent.v1.Agent/ListAgents":                                    noLimit,
 		"/spire.api.server.agent.v1.Agent/GetAgent":                                      noLimit,
@@ -160,13 +177,39 @@ func RateLimits(config RateLimitConfig) map[string]api.RateLimiter {
 		"/spire.api.server.agent.v1.Agent/AttestAgent":                                   attestLimit,
 		"/spire.api.server.agent.v1.Agent/RenewAgent":                                    csrLimit,
 		"/spire.api.server.agent.v1.Agent/CreateJoinToken":                               noLimit,
+		"/spire.api.server.agent.v1.AgentExtensions/SearchAgents":                        noLimit,
+		"/spire.api.server.agent.v1.AgentExtensions/GetAgentInfo":                        noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/ListFederationRelationships":       noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/GetFederationRelationship":         noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/BatchCreateFederationRelationship": noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/BatchUpdateFederationRelationship": noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/BatchDeleteFederationRelationship": noLimit,
 		"/spire.api.server.trustdomain.v1.TrustDomain/RefreshBundle":                     noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/GetJWTAuthorityState":        noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/PrepareJWTAuthority":         noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/ActivateJWTAuthority":        noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/TaintJWTAuthority":           noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/RevokeJWTAuthority":          noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/GetX509AuthorityState":       noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/PrepareX509Authority":        noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/ActivateX509Authority":       noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/TaintX509Authority":          noLimit,
+		"/spire.api.server.localauthority.v1.LocalAuthority/RevokeX509Authority":         noLimit,
+		"/spire.api.server.datastore.v1.DataStore/Export":                                noLimit,
+		"/spire.api.server.datastore.v1.DataStore/Import":                                noLimit,
 		"/grpc.health.v1.Health/Check":                                                   noLimit,
 		"/grpc.health.v1.Health/Watch":                                                   noLimit,
 	}
 }
+
+// limitAndBurst returns the configured limit and burst size, falling back
+// to the default limit, and to the limit for the burst size, when unset.
+func limitAndBurst(limit, burst, defaultLimit int) (int, int) {
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if burst <= 0 {
+		burst = limit
+	}
+	return limit, burst
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"testing"
 	"time"
 
@@ -407,3 +408,97 @@ func createAuthorizedEntryTestData(t testing.TB, ds datastore.DataStore) *testEn
 		workloadEntries:  workloadEntries,
 	}
 }
+
+func TestRateLimits(t *testing.T) {
+	const (
+		attestAgent = "/spire.api.server.agent.v1.Agent/AttestAgent"
+		newX509SVID = "/spire.api.server.svid.v1.SVID/BatchNewX509SVID"
+		newJWTSVID  = "/spire.api.server.svid.v1.SVID/NewJWTSVID"
+		publishJWT  = "/spire.api.server.bundle.v1.Bundle/PublishJWTAuthority"
+	)
+
+	callerCtx := func(t *testing.T, id string) context.Context {
+		// Limiters fail right away instead of waiting past the deadline
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		t.Cleanup(cancel)
+		ctx = rpccontext.WithCallerAddr(ctx, &net.TCPAddr{IP: net.ParseIP("1.1.1.1")})
+		if id != "" {
+			ctx = rpccontext.WithCallerID(ctx, spiffeid.RequireFromString(id))
+		}
+		return ctx
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		rateLimits := RateLimits(RateLimitConfig{Attestation: true, Signing: true})
+
+		err := rateLimits[attestAgent].RateLimit(callerCtx(t, ""), 2)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate (2) exceeds burst size (1)")
+		err = rateLimits[newX509SVID].RateLimit(callerCtx(t, ""), 501)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate (501) exceeds burst size (500)")
+		err = rateLimits[newJWTSVID].RateLimit(callerCtx(t, ""), 501)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate (501) exceeds burst size (500)")
+		err = rateLimits[publishJWT].RateLimit(callerCtx(t, ""), 501)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate (501) exceeds burst size (500)")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		rateLimits := RateLimits(RateLimitConfig{})
+
+		require.NoError(t, rateLimits[attestAgent].RateLimit(callerCtx(t, ""), 1000))
+		require.NoError(t, rateLimits[newX509SVID].RateLimit(callerCtx(t, ""), 1000))
+		require.NoError(t, rateLimits[newJWTSVID].RateLimit(callerCtx(t, ""), 1000))
+	})
+
+	t.Run("configured limits and bursts", func(t *testing.T) {
+		rateLimits := RateLimits(RateLimitConfig{
+			Attestation:      true,
+			AttestationLimit: 5,
+			AttestationBurst: 10,
+			Signing:          true,
+			SigningLimit:     100,
+			PushJWTKeyLimit:  1,
+			PushJWTKeyBurst:  2,
+		})
+
+		err := rateLimits[attestAgent].RateLimit(callerCtx(t, ""), 11)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate (11) exceeds burst size (10)")
+		err = rateLimits[newX509SVID].RateLimit(callerCtx(t, ""), 101)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate (101) exceeds burst size (100)")
+		err = rateLimits[publishJWT].RateLimit(callerCtx(t, ""), 3)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate (3) exceeds burst size (2)")
+	})
+
+	t.Run("per IP", func(t *testing.T) {
+		rateLimits := RateLimits(RateLimitConfig{
+			Signing:      true,
+			SigningLimit: 1,
+			SigningBurst: 2,
+		})
+
+		// Callers sharing the IP share the limit
+		require.NoError(t, rateLimits[newX509SVID].RateLimit(callerCtx(t, "spiffe://example.org/agent/1"), 2))
+		err := rateLimits[newX509SVID].RateLimit(callerCtx(t, "spiffe://example.org/agent/2"), 2)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate: Wait(n=2) would exceed context deadline")
+	})
+
+	t.Run("per caller ID", func(t *testing.T) {
+		rateLimits := RateLimits(RateLimitConfig{
+			Attestation:  true,
+			Signing:      true,
+			SigningLimit: 1,
+			SigningBurst: 2,
+			PerCallerID:  true,
+		})
+
+		// Callers sharing the IP are limited separately
+		require.NoError(t, rateLimits[newX509SVID].RateLimit(callerCtx(t, "spiffe://example.org/agent/1"), 2))
+		require.NoError(t, rateLimits[newX509SVID].RateLimit(callerCtx(t, "spiffe://example.org/agent/2"), 2))
+		err := rateLimits[newX509SVID].RateLimit(callerCtx(t, "spiffe://example.org/agent/1"), 2)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate: Wait(n=2) would exceed context deadline")
+
+		// Attestation is still limited per IP
+		require.NoError(t, rateLimits[attestAgent].RateLimit(callerCtx(t, ""), 1))
+		err = rateLimits[attestAgent].RateLimit(callerCtx(t, ""), 1)
+		spiretest.RequireGRPCStatus(t, err, codes.ResourceExhausted, "rate: Wait(n=1) would exceed context deadline")
+	})
+}
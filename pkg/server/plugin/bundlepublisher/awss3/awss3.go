@@ -0,0 +1,204 @@
+package awss3
+
+import (
+	"bytes"
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	bundlepublisherv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/bundlepublisher/v1"
+	"github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/types"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/plugin/bundleformat"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	pluginName = "aws_s3"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *Plugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		bundlepublisherv1.BundlePublisherPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+// Config holds the configuration of the plugin.
+type Config struct {
+	AccessKeyID     string `hcl:"access_key_id" json:"access_key_id"`
+	SecretAccessKey string `hcl:"secret_access_key" json:"secret_access_key"`
+	Region          string `hcl:"region" json:"region"`
+	Bucket          string `hcl:"bucket" json:"bucket"`
+	ObjectKey       string `hcl:"object_key" json:"object_key"`
+	Format          string `hcl:"format" json:"format"`
+	Endpoint        string `hcl:"endpoint" json:"endpoint"`
+
+	// bundleFormat is used to store the content of Format, parsed
+	// as bundleformat.Format.
+	bundleFormat bundleformat.Format
+}
+
+// Plugin is the main representation of this bundle publisher plugin.
+type Plugin struct {
+	bundlepublisherv1.UnsafeBundlePublisherServer
+	configv1.UnsafeConfigServer
+
+	config    *Config
+	configMtx sync.RWMutex
+
+	bundle    *types.Bundle
+	bundleMtx sync.RWMutex
+
+	hooks struct {
+		newS3ClientFunc func(ctx context.Context, c *Config) (simpleStorageService, error)
+	}
+	s3Client simpleStorageService
+	log      hclog.Logger
+}
+
+// New creates a new aws_s3 bundle publisher plugin.
+func New() *Plugin {
+	return newPlugin(newS3Client)
+}
+
+// newPlugin returns a new plugin instance that uses the given function to
+// create the S3 client.
+func newPlugin(newS3ClientFunc func(ctx context.Context, c *Config) (simpleStorageService, error)) *Plugin {
+	p := &Plugin{}
+	p.hooks.newS3ClientFunc = newS3ClientFunc
+	return p
+}
+
+// SetLogger sets a logger in the plugin.
+func (p *Plugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+// Configure configures the plugin.
+func (p *Plugin) Configure(ctx context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	config, err := parseAndValidateConfig(req.HclConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	s3Client, err := p.hooks.newS3ClientFunc(ctx, config)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create S3 client: %v", err)
+	}
+
+	p.configMtx.Lock()
+	defer p.configMtx.Unlock()
+	p.s3Client = s3Client
+	p.config = config
+
+	// The published object may not match the new configuration, so the
+	// bundle must be published again.
+	p.setBundle(nil)
+	return &configv1.ConfigureResponse{}, nil
+}
+
+// PublishBundle puts the bundle in the configured S3 bucket and object key.
+func (p *Plugin) PublishBundle(ctx context.Context, req *bundlepublisherv1.PublishBundleRequest) (*bundlepublisherv1.PublishBundleResponse, error) {
+	config, s3Client, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Bundle == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing bundle in request")
+	}
+
+	currentBundle := p.getBundle()
+	if proto.Equal(req.Bundle, currentBundle) {
+		// Bundle not changed. No need to publish.
+		return &bundlepublisherv1.PublishBundleResponse{}, nil
+	}
+
+	bundleBytes, err := config.bundleFormat.Format(req.Bundle)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not format bundle: %v", err)
+	}
+
+	_, err = s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(config.Bucket),
+		Key:    aws.String(config.ObjectKey),
+		Body:   bytes.NewReader(bundleBytes),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to put object: %v", err)
+	}
+
+	p.setBundle(req.Bundle)
+	p.log.Debug("Bundle published", telemetry.BucketName, config.Bucket, telemetry.ObjectKey, config.ObjectKey)
+	return &bundlepublisherv1.PublishBundleResponse{}, nil
+}
+
+// getBundle gets the latest bundle that the plugin has published.
+func (p *Plugin) getBundle() *types.Bundle {
+	p.bundleMtx.RLock()
+	defer p.bundleMtx.RUnlock()
+
+	return p.bundle
+}
+
+// getConfig gets the configuration and the S3 client of the plugin.
+func (p *Plugin) getConfig() (*Config, simpleStorageService, error) {
+	p.configMtx.RLock()
+	defer p.configMtx.RUnlock()
+
+	if p.config == nil {
+		return nil, nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, p.s3Client, nil
+}
+
+// setBundle updates the current bundle in the plugin with the provided bundle.
+func (p *Plugin) setBundle(bundle *types.Bundle) {
+	p.bundleMtx.Lock()
+	defer p.bundleMtx.Unlock()
+
+	p.bundle = bundle
+}
+
+// parseAndValidateConfig returns an error if any configuration provided does
+// not meet acceptable criteria.
+func parseAndValidateConfig(c string) (*Config, error) {
+	config := new(Config)
+
+	if err := hcl.Decode(config, c); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if config.Region == "" {
+		return nil, status.Error(codes.InvalidArgument, "configuration is missing the region")
+	}
+	if config.Bucket == "" {
+		return nil, status.Error(codes.InvalidArgument, "configuration is missing the bucket name")
+	}
+	if config.ObjectKey == "" {
+		return nil, status.Error(codes.InvalidArgument, "configuration is missing the object key")
+	}
+	if config.Format == "" {
+		return nil, status.Error(codes.InvalidArgument, "configuration is missing the bundle format")
+	}
+
+	bundleFormat, err := bundleformat.FromString(config.Format)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not parse bundle format from configuration: %v", err)
+	}
+	config.bundleFormat = bundleFormat
+
+	return config, nil
+}
@@ -0,0 +1,235 @@
+package awss3
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	bundlepublisherv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/bundlepublisher/v1"
+	"github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/types"
+	"github.com/spiffe/spire/pkg/common/plugin/bundleformat"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		config       string
+		newClientErr error
+		expectCode   codes.Code
+		expectMsg    string
+		expectConfig *Config
+	}{
+		{
+			name: "success",
+			config: `
+				access_key_id = "access-key-id"
+				secret_access_key = "secret-access-key"
+				region = "region"
+				bucket = "bucket"
+				object_key = "object-key"
+				format = "spiffe"
+				endpoint = "http://localhost:9000"
+			`,
+			expectConfig: &Config{
+				AccessKeyID:     "access-key-id",
+				SecretAccessKey: "secret-access-key",
+				Region:          "region",
+				Bucket:          "bucket",
+				ObjectKey:       "object-key",
+				Format:          "spiffe",
+				Endpoint:        "http://localhost:9000",
+				bundleFormat:    bundleformat.SPIFFE,
+			},
+		},
+		{
+			name:       "malformed",
+			config:     "MALFORMED",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "unable to decode configuration",
+		},
+		{
+			name: "no region",
+			config: `
+				bucket = "bucket"
+				object_key = "object-key"
+				format = "spiffe"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "configuration is missing the region",
+		},
+		{
+			name: "no bucket",
+			config: `
+				region = "region"
+				object_key = "object-key"
+				format = "spiffe"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "configuration is missing the bucket name",
+		},
+		{
+			name: "no object key",
+			config: `
+				region = "region"
+				bucket = "bucket"
+				format = "spiffe"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "configuration is missing the object key",
+		},
+		{
+			name: "no format",
+			config: `
+				region = "region"
+				bucket = "bucket"
+				object_key = "object-key"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "configuration is missing the bundle format",
+		},
+		{
+			name: "unknown format",
+			config: `
+				region = "region"
+				bucket = "bucket"
+				object_key = "object-key"
+				format = "unknown"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "could not parse bundle format from configuration: unknown bundle format: \"unknown\"",
+		},
+		{
+			name: "client error",
+			config: `
+				region = "region"
+				bucket = "bucket"
+				object_key = "object-key"
+				format = "pem"
+			`,
+			newClientErr: errors.New("client creation error"),
+			expectCode:   codes.Internal,
+			expectMsg:    "failed to create S3 client: client creation error",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			newClient := func(ctx context.Context, c *Config) (simpleStorageService, error) {
+				if tt.newClientErr != nil {
+					return nil, tt.newClientErr
+				}
+				return &fakeClient{}, nil
+			}
+			p := newPlugin(newClient)
+			plugintest.Load(t, builtin(p), nil,
+				plugintest.Configure(tt.config),
+				plugintest.CaptureConfigureError(&err),
+			)
+
+			if tt.expectMsg != "" {
+				spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectConfig, p.config)
+		})
+	}
+}
+
+func TestPublishBundle(t *testing.T) {
+	td := spiffeid.RequireTrustDomainFromString("example.org")
+	bundle := &types.Bundle{
+		TrustDomain:     td.Name(),
+		X509Authorities: []*types.X509Certificate{{Asn1: testca.New(t, td).X509Authorities()[0].Raw}},
+	}
+	config := `
+		region = "region"
+		bucket = "bucket"
+		object_key = "object-key"
+		format = "pem"
+	`
+
+	t.Run("not configured", func(t *testing.T) {
+		p := newPlugin(newFakeClientFunc(&fakeClient{}))
+		plugintest.Load(t, builtin(p), nil)
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		spiretest.RequireGRPCStatusContains(t, err, codes.FailedPrecondition, "not configured")
+	})
+
+	t.Run("missing bundle", func(t *testing.T) {
+		p := newPlugin(newFakeClientFunc(&fakeClient{}))
+		plugintest.Load(t, builtin(p), nil, plugintest.Configure(config))
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{})
+		spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, "missing bundle in request")
+	})
+
+	t.Run("put object error", func(t *testing.T) {
+		client := &fakeClient{putObjectErr: errors.New("oh no")}
+		p := newPlugin(newFakeClientFunc(client))
+		plugintest.Load(t, builtin(p), nil, plugintest.Configure(config))
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, "failed to put object: oh no")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		client := &fakeClient{}
+		p := newPlugin(newFakeClientFunc(client))
+		plugintest.Load(t, builtin(p), nil, plugintest.Configure(config))
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		require.NoError(t, err)
+
+		expectedBody, err := bundleformat.PEM.Format(bundle)
+		require.NoError(t, err)
+		require.Equal(t, 1, client.putObjectCount)
+		require.Equal(t, "bucket", client.bucket)
+		require.Equal(t, "object-key", client.key)
+		require.Equal(t, expectedBody, client.body)
+
+		// Publishing the same bundle again does not put the object
+		_, err = p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		require.NoError(t, err)
+		require.Equal(t, 1, client.putObjectCount)
+	})
+}
+
+type fakeClient struct {
+	putObjectErr   error
+	putObjectCount int
+	bucket         string
+	key            string
+	body           []byte
+}
+
+func (c *fakeClient) PutObject(_ context.Context, params *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if c.putObjectErr != nil {
+		return nil, c.putObjectErr
+	}
+
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.putObjectCount++
+	c.bucket = *params.Bucket
+	c.key = *params.Key
+	c.body = body
+	return &s3.PutObjectOutput{}, nil
+}
+
+func newFakeClientFunc(client *fakeClient) func(context.Context, *Config) (simpleStorageService, error) {
+	return func(context.Context, *Config) (simpleStorageService, error) {
+		return client, nil
+	}
+}
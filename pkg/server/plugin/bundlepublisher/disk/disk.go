@@ -0,0 +1,180 @@
+package disk
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	bundlepublisherv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/bundlepublisher/v1"
+	"github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/types"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/diskutil"
+	"github.com/spiffe/spire/pkg/common/plugin/bundleformat"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	pluginName = "disk"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *Plugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		bundlepublisherv1.BundlePublisherPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+// Config holds the configuration of the plugin.
+type Config struct {
+	Paths  []string `hcl:"paths" json:"paths"`
+	Format string   `hcl:"format" json:"format"`
+
+	// bundleFormat is used to store the content of Format, parsed
+	// as bundleformat.Format.
+	bundleFormat bundleformat.Format
+}
+
+// Plugin is the main representation of this bundle publisher plugin.
+type Plugin struct {
+	bundlepublisherv1.UnsafeBundlePublisherServer
+	configv1.UnsafeConfigServer
+
+	config    *Config
+	configMtx sync.RWMutex
+
+	bundle    *types.Bundle
+	bundleMtx sync.RWMutex
+
+	hooks struct {
+		writeFile func(path string, data []byte) error
+	}
+	log hclog.Logger
+}
+
+// New creates a new disk bundle publisher plugin.
+func New() *Plugin {
+	p := &Plugin{}
+	p.hooks.writeFile = diskutil.AtomicWritePubliclyReadableFile
+	return p
+}
+
+// SetLogger sets a logger in the plugin.
+func (p *Plugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+// Configure configures the plugin.
+func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	config, err := parseAndValidateConfig(req.HclConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	p.configMtx.Lock()
+	defer p.configMtx.Unlock()
+	p.config = config
+
+	// The published files may not match the new configuration, so the
+	// bundle must be published again.
+	p.setBundle(nil)
+	return &configv1.ConfigureResponse{}, nil
+}
+
+// PublishBundle writes the bundle to the configured paths.
+func (p *Plugin) PublishBundle(_ context.Context, req *bundlepublisherv1.PublishBundleRequest) (*bundlepublisherv1.PublishBundleResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Bundle == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing bundle in request")
+	}
+
+	currentBundle := p.getBundle()
+	if proto.Equal(req.Bundle, currentBundle) {
+		// Bundle not changed. No need to publish.
+		return &bundlepublisherv1.PublishBundleResponse{}, nil
+	}
+
+	bundleBytes, err := config.bundleFormat.Format(req.Bundle)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not format bundle: %v", err)
+	}
+
+	for _, path := range config.Paths {
+		if err := p.hooks.writeFile(path, bundleBytes); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to write bundle to %q: %v", path, err)
+		}
+		p.log.Debug("Bundle published", telemetry.Path, path)
+	}
+
+	p.setBundle(req.Bundle)
+	return &bundlepublisherv1.PublishBundleResponse{}, nil
+}
+
+// getBundle gets the latest bundle that the plugin has published.
+func (p *Plugin) getBundle() *types.Bundle {
+	p.bundleMtx.RLock()
+	defer p.bundleMtx.RUnlock()
+
+	return p.bundle
+}
+
+// getConfig gets the configuration of the plugin.
+func (p *Plugin) getConfig() (*Config, error) {
+	p.configMtx.RLock()
+	defer p.configMtx.RUnlock()
+
+	if p.config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, nil
+}
+
+// setBundle updates the current bundle in the plugin with the provided bundle.
+func (p *Plugin) setBundle(bundle *types.Bundle) {
+	p.bundleMtx.Lock()
+	defer p.bundleMtx.Unlock()
+
+	p.bundle = bundle
+}
+
+// parseAndValidateConfig returns an error if any configuration provided does
+// not meet acceptable criteria.
+func parseAndValidateConfig(c string) (*Config, error) {
+	config := new(Config)
+
+	if err := hcl.Decode(config, c); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if len(config.Paths) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "configuration is missing the paths")
+	}
+	for _, path := range config.Paths {
+		if path == "" {
+			return nil, status.Error(codes.InvalidArgument, "configuration has an empty path")
+		}
+	}
+	if config.Format == "" {
+		return nil, status.Error(codes.InvalidArgument, "configuration is missing the bundle format")
+	}
+
+	bundleFormat, err := bundleformat.FromString(config.Format)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not parse bundle format from configuration: %v", err)
+	}
+	config.bundleFormat = bundleFormat
+
+	return config, nil
+}
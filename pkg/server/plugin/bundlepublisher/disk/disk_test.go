@@ -0,0 +1,169 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	bundlepublisherv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/bundlepublisher/v1"
+	"github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/types"
+	"github.com/spiffe/spire/pkg/common/plugin/bundleformat"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		config       string
+		expectCode   codes.Code
+		expectMsg    string
+		expectConfig *Config
+	}{
+		{
+			name: "success",
+			config: `
+				paths = ["/tmp/bundle1.json", "/tmp/bundle2.json"]
+				format = "jwks"
+			`,
+			expectConfig: &Config{
+				Paths:        []string{"/tmp/bundle1.json", "/tmp/bundle2.json"},
+				Format:       "jwks",
+				bundleFormat: bundleformat.JWKS,
+			},
+		},
+		{
+			name:       "malformed",
+			config:     "MALFORMED",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "unable to decode configuration",
+		},
+		{
+			name:       "no paths",
+			config:     `format = "pem"`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "configuration is missing the paths",
+		},
+		{
+			name: "empty path",
+			config: `
+				paths = [""]
+				format = "pem"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "configuration has an empty path",
+		},
+		{
+			name:       "no format",
+			config:     `paths = ["/tmp/bundle.pem"]`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "configuration is missing the bundle format",
+		},
+		{
+			name: "unknown format",
+			config: `
+				paths = ["/tmp/bundle.pem"]
+				format = "unknown"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "could not parse bundle format from configuration: unknown bundle format: \"unknown\"",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			p := New()
+			plugintest.Load(t, builtin(p), nil,
+				plugintest.Configure(tt.config),
+				plugintest.CaptureConfigureError(&err),
+			)
+
+			if tt.expectMsg != "" {
+				spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectConfig, p.config)
+		})
+	}
+}
+
+func TestPublishBundle(t *testing.T) {
+	td := spiffeid.RequireTrustDomainFromString("example.org")
+	bundle := &types.Bundle{
+		TrustDomain:     td.Name(),
+		X509Authorities: []*types.X509Certificate{{Asn1: testca.New(t, td).X509Authorities()[0].Raw}},
+	}
+	expectedData, err := bundleformat.PEM.Format(bundle)
+	require.NoError(t, err)
+
+	t.Run("not configured", func(t *testing.T) {
+		p := New()
+		plugintest.Load(t, builtin(p), nil)
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		spiretest.RequireGRPCStatusContains(t, err, codes.FailedPrecondition, "not configured")
+	})
+
+	t.Run("missing bundle", func(t *testing.T) {
+		p := New()
+		plugintest.Load(t, builtin(p), nil, plugintest.Configure(`
+			paths = ["/tmp/bundle.pem"]
+			format = "pem"
+		`))
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{})
+		spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, "missing bundle in request")
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		p := New()
+		p.hooks.writeFile = func(string, []byte) error {
+			return errors.New("oh no")
+		}
+		plugintest.Load(t, builtin(p), nil, plugintest.Configure(`
+			paths = ["/tmp/bundle.pem"]
+			format = "pem"
+		`))
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, `failed to write bundle to "/tmp/bundle.pem": oh no`)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		dir := spiretest.TempDir(t)
+		path1 := filepath.Join(dir, "bundle1.pem")
+		path2 := filepath.Join(dir, "bundle2.pem")
+
+		p := New()
+		writeCount := 0
+		p.hooks.writeFile = func(path string, data []byte) error {
+			writeCount++
+			return os.WriteFile(path, data, 0600)
+		}
+		plugintest.Load(t, builtin(p), nil, plugintest.ConfigureJSON(Config{
+			Paths:  []string{path1, path2},
+			Format: "pem",
+		}))
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		require.NoError(t, err)
+		require.Equal(t, 2, writeCount)
+		for _, path := range []string{path1, path2} {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			require.Equal(t, expectedData, data)
+		}
+
+		// Publishing the same bundle again does not write the files
+		_, err = p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		require.NoError(t, err)
+		require.Equal(t, 2, writeCount)
+	})
+}
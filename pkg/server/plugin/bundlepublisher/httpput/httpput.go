@@ -0,0 +1,318 @@
+package httpput
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	"github.com/spiffe/spire-plugin-sdk/pluginsdk"
+	identityproviderv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/hostservice/server/identityprovider/v1"
+	bundlepublisherv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/bundlepublisher/v1"
+	"github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/types"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/pemutil"
+	"github.com/spiffe/spire/pkg/common/plugin/bundleformat"
+	"github.com/spiffe/spire/pkg/common/telemetry"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	pluginName = "http_put"
+
+	// requestTimeout is the maximum amount of time to wait for the bundle
+	// to be uploaded.
+	requestTimeout = 30 * time.Second
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *Plugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		bundlepublisherv1.BundlePublisherPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+// Config holds the configuration of the plugin.
+type Config struct {
+	URL           string            `hcl:"url" json:"url"`
+	Format        string            `hcl:"format" json:"format"`
+	Headers       map[string]string `hcl:"headers" json:"headers"`
+	CABundlePath  string            `hcl:"ca_bundle_path" json:"ca_bundle_path"`
+	UseServerSVID bool              `hcl:"use_server_svid" json:"use_server_svid"`
+
+	// bundleFormat is used to store the content of Format, parsed
+	// as bundleformat.Format.
+	bundleFormat bundleformat.Format
+
+	// rootCAs holds the CA certificates loaded from CABundlePath, if set.
+	rootCAs *x509.CertPool
+
+	// httpClient is the client used to upload the bundle. It is built once
+	// per configuration so connections can be reused between uploads.
+	httpClient *http.Client
+}
+
+// Plugin is the main representation of this bundle publisher plugin.
+type Plugin struct {
+	bundlepublisherv1.UnsafeBundlePublisherServer
+	configv1.UnsafeConfigServer
+
+	config    *Config
+	configMtx sync.RWMutex
+
+	bundle    *types.Bundle
+	bundleMtx sync.RWMutex
+
+	identityProvider         identityproviderv1.IdentityProviderServiceClient
+	identityProviderBrokered bool
+	log                      hclog.Logger
+}
+
+// New creates a new http_put bundle publisher plugin.
+func New() *Plugin {
+	return &Plugin{}
+}
+
+// SetLogger sets a logger in the plugin.
+func (p *Plugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+// BrokerHostServices brokers the IdentityProvider host service, which is
+// used to obtain the server SVID when mTLS is configured.
+func (p *Plugin) BrokerHostServices(broker pluginsdk.ServiceBroker) error {
+	p.identityProviderBrokered = broker.BrokerClient(&p.identityProvider)
+	return nil
+}
+
+// Configure configures the plugin.
+func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	config, err := parseAndValidateConfig(req.HclConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.UseServerSVID && !p.identityProviderBrokered {
+		return nil, status.Error(codes.FailedPrecondition, "IdentityProvider host service is required to use the server SVID")
+	}
+
+	config.httpClient = p.newHTTPClient(config)
+
+	p.configMtx.Lock()
+	defer p.configMtx.Unlock()
+	if p.config != nil {
+		p.config.httpClient.CloseIdleConnections()
+	}
+	p.config = config
+
+	// The published bundle may not match the new configuration, so the
+	// bundle must be published again.
+	p.setBundle(nil)
+	return &configv1.ConfigureResponse{}, nil
+}
+
+// PublishBundle uploads the bundle to the configured URL using an HTTP PUT
+// request.
+func (p *Plugin) PublishBundle(ctx context.Context, req *bundlepublisherv1.PublishBundleRequest) (*bundlepublisherv1.PublishBundleResponse, error) {
+	config, err := p.getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Bundle == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing bundle in request")
+	}
+
+	currentBundle := p.getBundle()
+	if proto.Equal(req.Bundle, currentBundle) {
+		// Bundle not changed. No need to publish.
+		return &bundlepublisherv1.PublishBundleResponse{}, nil
+	}
+
+	bundleBytes, err := config.bundleFormat.Format(req.Bundle)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "could not format bundle: %v", err)
+	}
+
+	if err := putBundle(ctx, config, bundleBytes); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to put bundle: %v", err)
+	}
+
+	p.setBundle(req.Bundle)
+	p.log.Debug("Bundle published", telemetry.URL, config.URL)
+	return &bundlepublisherv1.PublishBundleResponse{}, nil
+}
+
+// newHTTPClient returns an HTTP client for the given configuration. When
+// configured to use the server SVID, the current SVID of the server is
+// fetched on every TLS handshake and used as the client certificate.
+func (p *Plugin) newHTTPClient(config *Config) *http.Client {
+	tlsConfig := &tls.Config{
+		RootCAs:    config.rootCAs,
+		MinVersion: tls.VersionTLS12,
+	}
+	if config.UseServerSVID {
+		tlsConfig.GetClientCertificate = p.getServerSVID
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{
+		Transport: transport,
+		Timeout:   requestTimeout,
+	}
+}
+
+// getServerSVID fetches the current SVID of the server through the
+// IdentityProvider host service.
+func (p *Plugin) getServerSVID(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	resp, err := p.identityProvider.FetchX509Identity(info.Context(), &identityproviderv1.FetchX509IdentityRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch server SVID: %w", err)
+	}
+	privateKey, err := x509.ParsePKCS8PrivateKey(resp.GetIdentity().GetPrivateKey())
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse server SVID private key: %w", err)
+	}
+	return &tls.Certificate{
+		Certificate: resp.Identity.CertChain,
+		PrivateKey:  privateKey,
+	}, nil
+}
+
+// getBundle gets the latest bundle that the plugin has published.
+func (p *Plugin) getBundle() *types.Bundle {
+	p.bundleMtx.RLock()
+	defer p.bundleMtx.RUnlock()
+
+	return p.bundle
+}
+
+// getConfig gets the configuration of the plugin.
+func (p *Plugin) getConfig() (*Config, error) {
+	p.configMtx.RLock()
+	defer p.configMtx.RUnlock()
+
+	if p.config == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.config, nil
+}
+
+// setBundle updates the current bundle in the plugin with the provided bundle.
+func (p *Plugin) setBundle(bundle *types.Bundle) {
+	p.bundleMtx.Lock()
+	defer p.bundleMtx.Unlock()
+
+	p.bundle = bundle
+}
+
+func putBundle(ctx context.Context, config *Config, bundleBytes []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, config.URL, bytes.NewReader(bundleBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType(config.bundleFormat))
+	for name, value := range config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := config.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func contentType(format bundleformat.Format) string {
+	if format == bundleformat.PEM {
+		return "application/x-pem-file"
+	}
+	return "application/json"
+}
+
+// parseAndValidateConfig returns an error if any configuration provided does
+// not meet acceptable criteria.
+func parseAndValidateConfig(c string) (*Config, error) {
+	config := new(Config)
+
+	if err := hcl.Decode(config, c); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if config.URL == "" {
+		return nil, status.Error(codes.InvalidArgument, "configuration is missing the URL")
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not parse URL from configuration: %v", err)
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if config.UseServerSVID {
+			return nil, status.Error(codes.InvalidArgument, "the URL must use the https scheme to use the server SVID")
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported URL scheme %q", u.Scheme)
+	}
+
+	if config.Format == "" {
+		return nil, status.Error(codes.InvalidArgument, "configuration is missing the bundle format")
+	}
+	bundleFormat, err := bundleformat.FromString(config.Format)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "could not parse bundle format from configuration: %v", err)
+	}
+	config.bundleFormat = bundleFormat
+
+	if config.CABundlePath != "" {
+		rootCAs, err := loadRootCAs(config.CABundlePath)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "could not load CA bundle: %v", err)
+		}
+		config.rootCAs = rootCAs
+	}
+
+	return config, nil
+}
+
+func loadRootCAs(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	certs, err := pemutil.ParseCertificates(data)
+	if err != nil {
+		return nil, err
+	}
+	rootCAs := x509.NewCertPool()
+	for _, cert := range certs {
+		rootCAs.AddCert(cert)
+	}
+	return rootCAs, nil
+}
@@ -0,0 +1,354 @@
+package httpput
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	identityproviderv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/hostservice/server/identityprovider/v1"
+	bundlepublisherv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/bundlepublisher/v1"
+	"github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/types"
+	"github.com/spiffe/spire/pkg/common/plugin/bundleformat"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testca"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	td       = spiffeid.RequireTrustDomainFromString("example.org")
+	serverID = spiffeid.RequireFromPath(td, "/spire/server")
+)
+
+func TestConfigure(t *testing.T) {
+	caBundlePath := filepath.Join(spiretest.TempDir(t), "ca.pem")
+	require.NoError(t, os.WriteFile(caBundlePath, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: testca.New(t, td).X509Authorities()[0].Raw,
+	}), 0600))
+
+	for _, tt := range []struct {
+		name               string
+		config             string
+		noIdentityProvider bool
+		expectCode         codes.Code
+		expectMsg          string
+	}{
+		{
+			name: "success",
+			config: `
+				url = "https://localhost/bundle"
+				format = "spiffe"
+				headers = { "Authorization" = "Bearer token" }
+				use_server_svid = true
+			`,
+		},
+		{
+			name: "success with CA bundle",
+			config: `
+				url = "https://localhost/bundle"
+				format = "spiffe"
+				ca_bundle_path = "` + caBundlePath + `"
+			`,
+		},
+		{
+			name:       "malformed",
+			config:     "MALFORMED",
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "unable to decode configuration",
+		},
+		{
+			name:       "no URL",
+			config:     `format = "pem"`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "configuration is missing the URL",
+		},
+		{
+			name: "unsupported scheme",
+			config: `
+				url = "ftp://localhost/bundle"
+				format = "pem"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  `unsupported URL scheme "ftp"`,
+		},
+		{
+			name: "server SVID over http",
+			config: `
+				url = "http://localhost/bundle"
+				format = "pem"
+				use_server_svid = true
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "the URL must use the https scheme to use the server SVID",
+		},
+		{
+			name:       "no format",
+			config:     `url = "https://localhost/bundle"`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "configuration is missing the bundle format",
+		},
+		{
+			name: "unknown format",
+			config: `
+				url = "https://localhost/bundle"
+				format = "unknown"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "could not parse bundle format from configuration: unknown bundle format: \"unknown\"",
+		},
+		{
+			name: "CA bundle does not exist",
+			config: `
+				url = "https://localhost/bundle"
+				format = "pem"
+				ca_bundle_path = "/does/not/exist"
+			`,
+			expectCode: codes.InvalidArgument,
+			expectMsg:  "could not load CA bundle",
+		},
+		{
+			name: "server SVID without identity provider",
+			config: `
+				url = "https://localhost/bundle"
+				format = "pem"
+				use_server_svid = true
+			`,
+			noIdentityProvider: true,
+			expectCode:         codes.FailedPrecondition,
+			expectMsg:          "IdentityProvider host service is required to use the server SVID",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			options := []plugintest.Option{
+				plugintest.Configure(tt.config),
+				plugintest.CaptureConfigureError(&err),
+			}
+			if !tt.noIdentityProvider {
+				options = append(options, plugintest.HostServices(identityproviderv1.IdentityProviderServiceServer(&fakeIdentityProvider{})))
+			}
+			plugintest.Load(t, BuiltIn(), nil, options...)
+
+			if tt.expectMsg != "" {
+				spiretest.RequireGRPCStatusContains(t, err, tt.expectCode, tt.expectMsg)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPublishBundle(t *testing.T) {
+	ca := testca.New(t, td)
+	bundle := &types.Bundle{
+		TrustDomain:     td.Name(),
+		X509Authorities: []*types.X509Certificate{{Asn1: ca.X509Authorities()[0].Raw}},
+	}
+	expectedBody, err := bundleformat.PEM.Format(bundle)
+	require.NoError(t, err)
+	otherBundle := &types.Bundle{
+		TrustDomain:     td.Name(),
+		X509Authorities: []*types.X509Certificate{{Asn1: testca.New(t, td).X509Authorities()[0].Raw}},
+	}
+
+	var requests []*receivedRequest
+	statusCode := http.StatusOK
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := &receivedRequest{
+			method:        r.Method,
+			contentType:   r.Header.Get("Content-Type"),
+			authorization: r.Header.Get("Authorization"),
+			body:          body,
+		}
+		if len(r.TLS.PeerCertificates) > 0 {
+			req.clientCert = r.TLS.PeerCertificates[0]
+		}
+		requests = append(requests, req)
+		w.WriteHeader(statusCode)
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequestClientCert,
+		MinVersion: tls.VersionTLS12,
+	}
+	var newConns int32
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	caBundlePath := filepath.Join(spiretest.TempDir(t), "ca.pem")
+	require.NoError(t, os.WriteFile(caBundlePath, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	}), 0600))
+
+	makeIdentity := func(svid *x509svid.SVID) *identityproviderv1.X509Identity {
+		privateKey, err := x509.MarshalPKCS8PrivateKey(svid.PrivateKey)
+		require.NoError(t, err)
+		return &identityproviderv1.X509Identity{
+			CertChain:  [][]byte{svid.Certificates[0].Raw},
+			PrivateKey: privateKey,
+		}
+	}
+	svid := ca.CreateX509SVID(serverID)
+	identityProvider := &fakeIdentityProvider{
+		identity: makeIdentity(svid),
+	}
+
+	load := func(t *testing.T, config string) *Plugin {
+		p := New()
+		plugintest.Load(t, builtin(p), nil,
+			plugintest.HostServices(identityproviderv1.IdentityProviderServiceServer(identityProvider)),
+			plugintest.Configure(config),
+		)
+		return p
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		p := New()
+		plugintest.Load(t, builtin(p), nil)
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		spiretest.RequireGRPCStatusContains(t, err, codes.FailedPrecondition, "not configured")
+	})
+
+	t.Run("missing bundle", func(t *testing.T) {
+		p := load(t, `
+			url = "`+server.URL+`"
+			format = "pem"
+			ca_bundle_path = "`+caBundlePath+`"
+		`)
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{})
+		spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, "missing bundle in request")
+	})
+
+	t.Run("untrusted server", func(t *testing.T) {
+		p := load(t, `
+			url = "`+server.URL+`"
+			format = "pem"
+		`)
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, "failed to put bundle:")
+	})
+
+	t.Run("unexpected status code", func(t *testing.T) {
+		statusCode = http.StatusForbidden
+		defer func() { statusCode = http.StatusOK }()
+		p := load(t, `
+			url = "`+server.URL+`"
+			format = "pem"
+			ca_bundle_path = "`+caBundlePath+`"
+		`)
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, "failed to put bundle: unexpected status code: 403")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		requests = nil
+		p := load(t, `
+			url = "`+server.URL+`"
+			format = "pem"
+			ca_bundle_path = "`+caBundlePath+`"
+			headers = { "Authorization" = "Bearer token" }
+		`)
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		require.NoError(t, err)
+		require.Equal(t, []*receivedRequest{{
+			method:        http.MethodPut,
+			contentType:   "application/x-pem-file",
+			authorization: "Bearer token",
+			body:          expectedBody,
+		}}, requests)
+
+		// Publishing the same bundle again does not send a request
+		_, err = p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+	})
+
+	t.Run("success with server SVID", func(t *testing.T) {
+		requests = nil
+		p := load(t, `
+			url = "`+server.URL+`"
+			format = "pem"
+			ca_bundle_path = "`+caBundlePath+`"
+			use_server_svid = true
+		`)
+
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		require.NotNil(t, requests[0].clientCert)
+		require.Equal(t, svid.Certificates[0].Raw, requests[0].clientCert.Raw)
+
+		// New connections use the rotated server SVID
+		rotatedSVID := ca.CreateX509SVID(serverID)
+		identityProvider.identity = makeIdentity(rotatedSVID)
+		p.config.httpClient.CloseIdleConnections()
+
+		_, err = p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: otherBundle})
+		require.NoError(t, err)
+		require.Len(t, requests, 2)
+		require.NotNil(t, requests[1].clientCert)
+		require.Equal(t, rotatedSVID.Certificates[0].Raw, requests[1].clientCert.Raw)
+	})
+
+	t.Run("connections are reused", func(t *testing.T) {
+		requests = nil
+		p := load(t, `
+			url = "`+server.URL+`"
+			format = "pem"
+			ca_bundle_path = "`+caBundlePath+`"
+		`)
+
+		connsBefore := atomic.LoadInt32(&newConns)
+		_, err := p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: bundle})
+		require.NoError(t, err)
+		_, err = p.PublishBundle(context.Background(), &bundlepublisherv1.PublishBundleRequest{Bundle: otherBundle})
+		require.NoError(t, err)
+		require.Len(t, requests, 2)
+		require.Equal(t, int32(1), atomic.LoadInt32(&newConns)-connsBefore)
+	})
+}
+
+type receivedRequest struct {
+	method        string
+	contentType   string
+	authorization string
+	body          []byte
+	clientCert    *x509.Certificate
+}
+
+type fakeIdentityProvider struct {
+	identityproviderv1.UnimplementedIdentityProviderServer
+
+	identity *identityproviderv1.X509Identity
+}
+
+func (p *fakeIdentityProvider) FetchX509Identity(context.Context, *identityproviderv1.FetchX509IdentityRequest) (*identityproviderv1.FetchX509IdentityResponse, error) {
+	return &identityproviderv1.FetchX509IdentityResponse{
+		Identity: p.identity,
+	}, nil
+}
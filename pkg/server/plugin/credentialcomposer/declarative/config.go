@@ -0,0 +1,288 @@
+package declarative
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"math/bits"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/hcl"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+var (
+	// reservedClaims are the registered JWT claims (RFC 7519, section 4.1),
+	// which are either set by the server or would change the semantics of
+	// the JWT-SVID, and therefore can't be set by this plugin.
+	reservedClaims = map[string]struct{}{
+		"iss": {},
+		"sub": {},
+		"aud": {},
+		"exp": {},
+		"nbf": {},
+		"iat": {},
+		"jti": {},
+	}
+
+	keyUsages = map[string]x509.KeyUsage{
+		"digital_signature":  x509.KeyUsageDigitalSignature,
+		"content_commitment": x509.KeyUsageContentCommitment,
+		"key_encipherment":   x509.KeyUsageKeyEncipherment,
+		"data_encipherment":  x509.KeyUsageDataEncipherment,
+		"key_agreement":      x509.KeyUsageKeyAgreement,
+	}
+
+	extKeyUsages = map[string]asn1.ObjectIdentifier{
+		"server_auth":      {1, 3, 6, 1, 5, 5, 7, 3, 1},
+		"client_auth":      {1, 3, 6, 1, 5, 5, 7, 3, 2},
+		"code_signing":     {1, 3, 6, 1, 5, 5, 7, 3, 3},
+		"email_protection": {1, 3, 6, 1, 5, 5, 7, 3, 4},
+		"time_stamping":    {1, 3, 6, 1, 5, 5, 7, 3, 8},
+		"ocsp_signing":     {1, 3, 6, 1, 5, 5, 7, 3, 9},
+	}
+
+	oidExtensionKeyUsage         = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidExtensionExtendedKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 37}
+)
+
+// Config is the configuration of the plugin.
+type Config struct {
+	Rules []RuleConfig `hcl:"rule" json:"rule"`
+}
+
+// RuleConfig configures the attributes added to the credentials of the
+// workloads whose SPIFFE ID path matches the pattern and, if set, whose
+// registration entry has the given hint.
+type RuleConfig struct {
+	// Name identifies the rule in error messages.
+	Name string `hcl:",key" json:"name"`
+
+	// SPIFFEIDPath is the pattern matched against the path of the SPIFFE ID
+	// of the workload. Each segment of the pattern matches a path segment
+	// literally, except for:
+	//   - "*", which matches any single segment;
+	//   - "{name}", which matches any single segment and captures it so
+	//     that it can be used in templates as {{ .Captures.name }};
+	//   - "**", which is only allowed as the last segment, and matches any
+	//     number (including zero) of remaining segments.
+	// It can only be omitted if EntryHint is set, in which case any path
+	// matches.
+	SPIFFEIDPath string `hcl:"spiffe_id_path" json:"spiffe_id_path"`
+
+	// EntryHint, if set, restricts the rule to the SVIDs minted for
+	// registration entries with this hint.
+	EntryHint string `hcl:"entry_hint" json:"entry_hint"`
+
+	// JWTClaims are extra claims added to JWT-SVIDs. String values are
+	// templates.
+	JWTClaims map[string]interface{} `hcl:"jwt_claims" json:"jwt_claims"`
+
+	// DNSNames are extra DNS SANs added to X509-SVIDs. Values are
+	// templates.
+	DNSNames []string `hcl:"dns_names" json:"dns_names"`
+
+	// SubjectOrganization and SubjectOrganizationalUnit are values added
+	// to the O and OU attributes of the X509-SVID subject. Values are
+	// templates.
+	SubjectOrganization       []string `hcl:"subject_organization" json:"subject_organization"`
+	SubjectOrganizationalUnit []string `hcl:"subject_organizational_unit" json:"subject_organizational_unit"`
+
+	// KeyUsage, if set, replaces the key usage of X509-SVIDs.
+	KeyUsage []string `hcl:"key_usage" json:"key_usage"`
+
+	// ExtKeyUsage, if set, replaces the extended key usage of X509-SVIDs.
+	ExtKeyUsage []string `hcl:"ext_key_usage" json:"ext_key_usage"`
+}
+
+type rule struct {
+	pattern   []string
+	entryHint string
+
+	// jwtClaims holds either a template (for string values) or a
+	// *structpb.Value for each claim.
+	jwtClaims                 map[string]interface{}
+	dnsNames                  []*template.Template
+	subjectOrganization       []*template.Template
+	subjectOrganizationalUnit []*template.Template
+	keyUsage                  []byte
+	extKeyUsage               []byte
+}
+
+func parseConfig(hclConfig string) ([]*rule, error) {
+	config := new(Config)
+	if err := hcl.Decode(config, hclConfig); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	rules := make([]*rule, 0, len(config.Rules))
+	for _, ruleConfig := range config.Rules {
+		r, err := parseRule(ruleConfig)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid rule %q: %v", ruleConfig.Name, err)
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+func parseRule(c RuleConfig) (_ *rule, err error) {
+	r := &rule{
+		entryHint: c.EntryHint,
+	}
+
+	switch {
+	case c.SPIFFEIDPath == "" && c.EntryHint != "":
+		r.pattern = []string{"**"}
+	default:
+		r.pattern, err = parsePattern(c.SPIFFEIDPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.jwtClaims, err = parseJWTClaims(c.JWTClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.dnsNames, err = parseTemplates("dns_names", c.DNSNames); err != nil {
+		return nil, err
+	}
+	if r.subjectOrganization, err = parseTemplates("subject_organization", c.SubjectOrganization); err != nil {
+		return nil, err
+	}
+	if r.subjectOrganizationalUnit, err = parseTemplates("subject_organizational_unit", c.SubjectOrganizationalUnit); err != nil {
+		return nil, err
+	}
+
+	if len(c.KeyUsage) > 0 {
+		if r.keyUsage, err = marshalKeyUsage(c.KeyUsage); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.ExtKeyUsage) > 0 {
+		if r.extKeyUsage, err = marshalExtKeyUsage(c.ExtKeyUsage); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+func parsePattern(pattern string) ([]string, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("spiffe_id_path or entry_hint is required")
+	}
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("spiffe_id_path %q must start with a slash", pattern)
+	}
+
+	segments := strings.Split(pattern[1:], "/")
+	for i, segment := range segments {
+		switch {
+		case segment == "":
+			return nil, fmt.Errorf("spiffe_id_path %q has an empty segment", pattern)
+		case segment == "**" && i != len(segments)-1:
+			return nil, fmt.Errorf("spiffe_id_path %q can only have ** as the last segment", pattern)
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && len(segment) == 2:
+			return nil, fmt.Errorf("spiffe_id_path %q has an unnamed capture", pattern)
+		}
+	}
+	return segments, nil
+}
+
+func parseJWTClaims(claims map[string]interface{}) (map[string]interface{}, error) {
+	out := make(map[string]interface{}, len(claims))
+	for name, value := range claims {
+		if _, ok := reservedClaims[name]; ok {
+			return nil, fmt.Errorf("jwt_claims cannot override the reserved %q claim", name)
+		}
+		if s, ok := value.(string); ok {
+			tmpl, err := parseTemplate(s)
+			if err != nil {
+				return nil, fmt.Errorf("jwt_claims %q: %w", name, err)
+			}
+			out[name] = tmpl
+			continue
+		}
+		v, err := structpb.NewValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("jwt_claims %q: %w", name, err)
+		}
+		out[name] = v
+	}
+	return out, nil
+}
+
+func parseTemplates(field string, texts []string) ([]*template.Template, error) {
+	var tmpls []*template.Template
+	for _, text := range texts {
+		tmpl, err := parseTemplate(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		tmpls = append(tmpls, tmpl)
+	}
+	return tmpls, nil
+}
+
+func parseTemplate(text string) (*template.Template, error) {
+	return template.New("").Option("missingkey=error").Parse(text)
+}
+
+func marshalKeyUsage(names []string) ([]byte, error) {
+	var keyUsage x509.KeyUsage
+	for _, name := range names {
+		ku, ok := keyUsages[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported key_usage %q", name)
+		}
+		keyUsage |= ku
+	}
+	if keyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return nil, fmt.Errorf("key_usage must include digital_signature")
+	}
+
+	// Encoded as in RFC 5280, section 4.2.1.3, i.e. as a BIT STRING where
+	// bit 0 is the digital signature bit.
+	var a [2]byte
+	a[0] = bits.Reverse8(byte(keyUsage))
+	a[1] = bits.Reverse8(byte(keyUsage >> 8))
+	bitString := a[:1]
+	if a[1] != 0 {
+		bitString = a[:2]
+	}
+	return asn1.Marshal(asn1.BitString{Bytes: bitString, BitLength: bitLength(bitString)})
+}
+
+func marshalExtKeyUsage(names []string) ([]byte, error) {
+	var oids []asn1.ObjectIdentifier
+	for _, name := range names {
+		oid, ok := extKeyUsages[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported ext_key_usage %q", name)
+		}
+		oids = append(oids, oid)
+	}
+	return asn1.Marshal(oids)
+}
+
+// bitLength returns the length of the bit string, ignoring the trailing
+// zero bits.
+func bitLength(bitString []byte) int {
+	bitLen := len(bitString) * 8
+	for i := range bitString {
+		b := bitString[len(bitString)-i-1]
+		for bit := uint(0); bit < 8; bit++ {
+			if (b>>bit)&1 == 1 {
+				return bitLen
+			}
+			bitLen--
+		}
+	}
+	return 0
+}
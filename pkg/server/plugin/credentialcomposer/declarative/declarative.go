@@ -0,0 +1,301 @@
+package declarative
+
+import (
+	"bytes"
+	"context"
+	"encoding/asn1"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	credentialcomposerv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/credentialcomposer/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	pluginName = "declarative"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *Plugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		credentialcomposerv1.CredentialComposerPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+// Plugin is a credential composer that customizes workload SVIDs according
+// to a set of rules provided in the configuration. Each rule applies to the
+// workloads whose SPIFFE ID path matches the pattern of the rule and, if the
+// rule has one, whose registration entry has the hint of the rule.
+type Plugin struct {
+	credentialcomposerv1.UnimplementedCredentialComposerServer
+	configv1.UnsafeConfigServer
+
+	log hclog.Logger
+
+	mtx   sync.RWMutex
+	rules []*rule
+}
+
+// templateData is the data available to the templates in the configuration.
+type templateData struct {
+	TrustDomain string
+	Path        string
+	Segments    []string
+	Captures    map[string]string
+	Hint        string
+}
+
+func New() *Plugin {
+	return &Plugin{}
+}
+
+func (p *Plugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	rules, err := parseConfig(req.HclConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.rules = rules
+	return &configv1.ConfigureResponse{}, nil
+}
+
+func (p *Plugin) ComposeWorkloadX509SVID(ctx context.Context, req *credentialcomposerv1.ComposeWorkloadX509SVIDRequest) (*credentialcomposerv1.ComposeWorkloadX509SVIDResponse, error) {
+	rules, err := p.getRules()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Attributes == nil {
+		return nil, status.Error(codes.InvalidArgument, "request missing attributes")
+	}
+	id, err := parseSPIFFEID(req.SpiffeId)
+	if err != nil {
+		return nil, err
+	}
+
+	hint := credentialcomposer.EntryHintFromContext(ctx)
+
+	attributes := req.Attributes
+	for _, r := range rules {
+		data, ok := r.match(id, hint)
+		if !ok {
+			continue
+		}
+
+		dnsNames, err := renderTemplates(r.dnsNames, data)
+		if err != nil {
+			return nil, err
+		}
+		attributes.DnsSans = appendMissing(attributes.DnsSans, dnsNames...)
+
+		organization, err := renderTemplates(r.subjectOrganization, data)
+		if err != nil {
+			return nil, err
+		}
+		organizationalUnit, err := renderTemplates(r.subjectOrganizationalUnit, data)
+		if err != nil {
+			return nil, err
+		}
+		if len(organization) > 0 || len(organizationalUnit) > 0 {
+			if attributes.Subject == nil {
+				attributes.Subject = &credentialcomposerv1.DistinguishedName{}
+			}
+			attributes.Subject.Organization = appendMissing(attributes.Subject.Organization, organization...)
+			attributes.Subject.OrganizationalUnit = appendMissing(attributes.Subject.OrganizationalUnit, organizationalUnit...)
+		}
+
+		// Extra extensions override the extensions that would be
+		// otherwise generated from the certificate template.
+		if r.keyUsage != nil {
+			attributes.ExtraExtensions = setExtension(attributes.ExtraExtensions, oidExtensionKeyUsage, r.keyUsage, true)
+		}
+		if r.extKeyUsage != nil {
+			attributes.ExtraExtensions = setExtension(attributes.ExtraExtensions, oidExtensionExtendedKeyUsage, r.extKeyUsage, false)
+		}
+	}
+
+	return &credentialcomposerv1.ComposeWorkloadX509SVIDResponse{
+		Attributes: attributes,
+	}, nil
+}
+
+func (p *Plugin) ComposeWorkloadJWTSVID(ctx context.Context, req *credentialcomposerv1.ComposeWorkloadJWTSVIDRequest) (*credentialcomposerv1.ComposeWorkloadJWTSVIDResponse, error) {
+	rules, err := p.getRules()
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Attributes == nil {
+		return nil, status.Error(codes.InvalidArgument, "request missing attributes")
+	}
+	id, err := parseSPIFFEID(req.SpiffeId)
+	if err != nil {
+		return nil, err
+	}
+
+	hint := credentialcomposer.EntryHintFromContext(ctx)
+
+	attributes := req.Attributes
+	for _, r := range rules {
+		data, ok := r.match(id, hint)
+		if !ok || len(r.jwtClaims) == 0 {
+			continue
+		}
+
+		if attributes.Claims == nil {
+			attributes.Claims = &structpb.Struct{}
+		}
+		if attributes.Claims.Fields == nil {
+			attributes.Claims.Fields = make(map[string]*structpb.Value)
+		}
+		for name, claim := range r.jwtClaims {
+			switch claim := claim.(type) {
+			case *template.Template:
+				value, err := renderTemplate(claim, data)
+				if err != nil {
+					return nil, err
+				}
+				attributes.Claims.Fields[name] = structpb.NewStringValue(value)
+			case *structpb.Value:
+				attributes.Claims.Fields[name] = claim
+			}
+		}
+	}
+
+	return &credentialcomposerv1.ComposeWorkloadJWTSVIDResponse{
+		Attributes: attributes,
+	}, nil
+}
+
+func (p *Plugin) getRules() ([]*rule, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	if p.rules == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.rules, nil
+}
+
+// match returns the template data for the given SPIFFE ID and entry hint if
+// the path of the SPIFFE ID matches the pattern of the rule and the hint
+// matches the hint of the rule, if any.
+func (r *rule) match(id spiffeid.ID, hint string) (*templateData, bool) {
+	if r.entryHint != "" && r.entryHint != hint {
+		return nil, false
+	}
+
+	var segments []string
+	if path := id.Path(); path != "" {
+		segments = strings.Split(path[1:], "/")
+	}
+
+	captures := make(map[string]string)
+	for i, pattern := range r.pattern {
+		if pattern == "**" {
+			break
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		switch {
+		case pattern == "*":
+		case strings.HasPrefix(pattern, "{") && strings.HasSuffix(pattern, "}"):
+			captures[pattern[1:len(pattern)-1]] = segments[i]
+		case pattern != segments[i]:
+			return nil, false
+		}
+	}
+	if len(r.pattern) != len(segments) && r.pattern[len(r.pattern)-1] != "**" {
+		return nil, false
+	}
+
+	return &templateData{
+		TrustDomain: id.TrustDomain().Name(),
+		Path:        id.Path(),
+		Segments:    segments,
+		Captures:    captures,
+		Hint:        hint,
+	}, true
+}
+
+func parseSPIFFEID(s string) (spiffeid.ID, error) {
+	if s == "" {
+		return spiffeid.ID{}, status.Error(codes.InvalidArgument, "request missing SPIFFE ID")
+	}
+	id, err := spiffeid.FromString(s)
+	if err != nil {
+		return spiffeid.ID{}, status.Errorf(codes.InvalidArgument, "malformed SPIFFE ID: %v", err)
+	}
+	return id, nil
+}
+
+func renderTemplates(tmpls []*template.Template, data *templateData) ([]string, error) {
+	var values []string
+	for _, tmpl := range tmpls {
+		value, err := renderTemplate(tmpl, data)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func renderTemplate(tmpl *template.Template, data *templateData) (string, error) {
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, data); err != nil {
+		return "", status.Errorf(codes.Internal, "failed to render template: %v", err)
+	}
+	return buf.String(), nil
+}
+
+func appendMissing(values []string, newValues ...string) []string {
+	for _, newValue := range newValues {
+		if !containsString(values, newValue) {
+			values = append(values, newValue)
+		}
+	}
+	return values
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func setExtension(extensions []*credentialcomposerv1.X509Extension, id asn1.ObjectIdentifier, value []byte, critical bool) []*credentialcomposerv1.X509Extension {
+	extension := &credentialcomposerv1.X509Extension{
+		Oid:      id.String(),
+		Value:    value,
+		Critical: critical,
+	}
+	for i, e := range extensions {
+		if e.Oid == extension.Oid {
+			extensions[i] = extension
+			return extensions
+		}
+	}
+	return append(extensions, extension)
+}
@@ -0,0 +1,348 @@
+package declarative_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer/declarative"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testkey"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ctx = context.Background()
+	key = testkey.MustEC256()
+
+	workloadID = spiffeid.RequireFromString("spiffe://example.org/ns/prod/sa/frontend")
+	otherID    = spiffeid.RequireFromString("spiffe://example.org/other")
+)
+
+const config = `
+	rule "namespaced" {
+		spiffe_id_path = "/ns/{namespace}/sa/{sa}"
+		jwt_claims = {
+			namespace = "{{ .Captures.namespace }}"
+			team = "web"
+			level = 3
+		}
+		dns_names = ["{{ .Captures.sa }}.{{ .Captures.namespace }}.svc"]
+		subject_organization = ["{{ .TrustDomain }}"]
+		subject_organizational_unit = ["{{ index .Segments 1 }}"]
+	}
+
+	rule "client" {
+		spiffe_id_path = "/ns/**"
+		key_usage = ["digital_signature"]
+		ext_key_usage = ["client_auth"]
+	}
+`
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		config    string
+		expectErr string
+	}{
+		{
+			name:   "no rules",
+			config: "",
+		},
+		{
+			name:   "valid",
+			config: config,
+		},
+		{
+			name:      "malformed",
+			config:    "MALFORMED",
+			expectErr: "unable to decode configuration",
+		},
+		{
+			name:      "missing pattern",
+			config:    `rule "test" { dns_names = ["foo"] }`,
+			expectErr: `invalid rule "test": spiffe_id_path or entry_hint is required`,
+		},
+		{
+			name:   "entry hint without pattern",
+			config: `rule "test" { entry_hint = "external" dns_names = ["foo"] }`,
+		},
+		{
+			name:      "relative pattern",
+			config:    `rule "test" { spiffe_id_path = "foo" }`,
+			expectErr: `invalid rule "test": spiffe_id_path "foo" must start with a slash`,
+		},
+		{
+			name:      "empty segment",
+			config:    `rule "test" { spiffe_id_path = "/foo//bar" }`,
+			expectErr: `invalid rule "test": spiffe_id_path "/foo//bar" has an empty segment`,
+		},
+		{
+			name:      "double wildcard not last",
+			config:    `rule "test" { spiffe_id_path = "/**/bar" }`,
+			expectErr: `invalid rule "test": spiffe_id_path "/**/bar" can only have ** as the last segment`,
+		},
+		{
+			name:      "unnamed capture",
+			config:    `rule "test" { spiffe_id_path = "/{}" }`,
+			expectErr: `invalid rule "test": spiffe_id_path "/{}" has an unnamed capture`,
+		},
+		{
+			name:      "reserved claim",
+			config:    `rule "test" { spiffe_id_path = "/**" jwt_claims = { sub = "foo" } }`,
+			expectErr: `invalid rule "test": jwt_claims cannot override the reserved "sub" claim`,
+		},
+		{
+			name:      "bad claim template",
+			config:    `rule "test" { spiffe_id_path = "/**" jwt_claims = { foo = "{{" } }`,
+			expectErr: `invalid rule "test": jwt_claims "foo":`,
+		},
+		{
+			name:      "bad DNS name template",
+			config:    `rule "test" { spiffe_id_path = "/**" dns_names = ["{{"] }`,
+			expectErr: `invalid rule "test": dns_names:`,
+		},
+		{
+			name:      "unsupported key usage",
+			config:    `rule "test" { spiffe_id_path = "/**" key_usage = ["cert_sign"] }`,
+			expectErr: `invalid rule "test": unsupported key_usage "cert_sign"`,
+		},
+		{
+			name:      "key usage without digital signature",
+			config:    `rule "test" { spiffe_id_path = "/**" key_usage = ["key_agreement"] }`,
+			expectErr: `invalid rule "test": key_usage must include digital_signature`,
+		},
+		{
+			name:      "unsupported ext key usage",
+			config:    `rule "test" { spiffe_id_path = "/**" ext_key_usage = ["any"] }`,
+			expectErr: `invalid rule "test": unsupported ext_key_usage "any"`,
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			plugintest.Load(t, declarative.BuiltIn(), nil,
+				plugintest.Configure(tt.config),
+				plugintest.CaptureConfigureError(&err),
+			)
+			if tt.expectErr != "" {
+				spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, tt.expectErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestComposeWorkloadX509SVID(t *testing.T) {
+	cc := loadPlugin(t, config)
+
+	attributes := credentialcomposer.X509SVIDAttributes{
+		Subject:  pkix.Name{Organization: []string{"SPIRE"}},
+		DNSNames: []string{"frontend.prod.svc"},
+	}
+
+	t.Run("matching rules", func(t *testing.T) {
+		got, err := cc.ComposeWorkloadX509SVID(ctx, workloadID, key.Public(), attributes)
+		require.NoError(t, err)
+		require.Equal(t, pkix.Name{
+			Organization:       []string{"SPIRE", "example.org"},
+			OrganizationalUnit: []string{"prod"},
+		}, got.Subject)
+		require.Equal(t, []string{"frontend.prod.svc"}, got.DNSNames)
+
+		cert := createCertificate(t, got)
+		require.Equal(t, x509.KeyUsageDigitalSignature, cert.KeyUsage)
+		require.Equal(t, []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}, cert.ExtKeyUsage)
+	})
+
+	t.Run("no matching rules", func(t *testing.T) {
+		got, err := cc.ComposeWorkloadX509SVID(ctx, otherID, key.Public(), attributes)
+		require.NoError(t, err)
+		require.Equal(t, attributes, got)
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		cc := loadPlugin(t, `rule "test" { spiffe_id_path = "/**" dns_names = ["{{ .Captures.missing }}"] }`)
+		_, err := cc.ComposeWorkloadX509SVID(ctx, workloadID, key.Public(), attributes)
+		spiretest.RequireGRPCStatusContains(t, err, codes.Internal, "failed to render template")
+	})
+}
+
+func TestComposeWorkloadJWTSVID(t *testing.T) {
+	cc := loadPlugin(t, config)
+
+	attributes := credentialcomposer.JWTSVIDAttributes{
+		Claims: map[string]interface{}{"sub": workloadID.String()},
+	}
+
+	t.Run("matching rules", func(t *testing.T) {
+		got, err := cc.ComposeWorkloadJWTSVID(ctx, workloadID, attributes)
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"sub":       workloadID.String(),
+			"namespace": "prod",
+			"team":      "web",
+			"level":     float64(3),
+		}, got.Claims)
+	})
+
+	t.Run("no matching rules", func(t *testing.T) {
+		got, err := cc.ComposeWorkloadJWTSVID(ctx, otherID, attributes)
+		require.NoError(t, err)
+		require.Equal(t, attributes, got)
+	})
+}
+
+func TestOtherCredentialsAreNotModified(t *testing.T) {
+	cc := loadPlugin(t, `rule "test" { spiffe_id_path = "/**" dns_names = ["foo"] }`)
+
+	want := credentialcomposer.X509SVIDAttributes{Subject: pkix.Name{CommonName: "agent"}}
+	got, err := cc.ComposeAgentX509SVID(ctx, otherID, key.Public(), want)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	got, err = cc.ComposeServerX509SVID(ctx, want)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestPatternMatching(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		id      string
+		match   bool
+	}{
+		{pattern: "/foo", id: "spiffe://example.org/foo", match: true},
+		{pattern: "/foo", id: "spiffe://example.org/bar", match: false},
+		{pattern: "/foo", id: "spiffe://example.org/foo/bar", match: false},
+		{pattern: "/*", id: "spiffe://example.org/foo", match: true},
+		{pattern: "/*", id: "spiffe://example.org", match: false},
+		{pattern: "/*/bar", id: "spiffe://example.org/foo/bar", match: true},
+		{pattern: "/{name}/bar", id: "spiffe://example.org/foo/baz", match: false},
+		{pattern: "/foo/**", id: "spiffe://example.org/foo", match: true},
+		{pattern: "/foo/**", id: "spiffe://example.org/foo/bar/baz", match: true},
+		{pattern: "/foo/**", id: "spiffe://example.org/bar/baz", match: false},
+		{pattern: "/**", id: "spiffe://example.org", match: true},
+	} {
+		cc := loadPlugin(t, `rule "test" { spiffe_id_path = "`+tt.pattern+`" dns_names = ["matched"] }`)
+		got, err := cc.ComposeWorkloadX509SVID(ctx, spiffeid.RequireFromString(tt.id), key.Public(), credentialcomposer.X509SVIDAttributes{})
+		require.NoError(t, err)
+		if tt.match {
+			require.Equal(t, []string{"matched"}, got.DNSNames, "%s should match %s", tt.pattern, tt.id)
+		} else {
+			require.Empty(t, got.DNSNames, "%s should not match %s", tt.pattern, tt.id)
+		}
+	}
+}
+
+func TestEntryHintMatching(t *testing.T) {
+	cc := loadPlugin(t, `
+		rule "hint" {
+			entry_hint = "external"
+			dns_names = ["{{ .Hint }}.example.org"]
+			jwt_claims = { hint = "{{ .Hint }}" }
+		}
+
+		rule "hint and pattern" {
+			spiffe_id_path = "/ns/{namespace}/**"
+			entry_hint = "external"
+			subject_organizational_unit = ["{{ .Captures.namespace }}"]
+		}
+	`)
+
+	for _, tt := range []struct {
+		name         string
+		id           spiffeid.ID
+		hint         string
+		expectX509   credentialcomposer.X509SVIDAttributes
+		expectClaims map[string]interface{}
+	}{
+		{
+			name: "no hint",
+			id:   workloadID,
+			expectClaims: map[string]interface{}{
+				"sub": workloadID.String(),
+			},
+		},
+		{
+			name: "other hint",
+			id:   workloadID,
+			hint: "internal",
+			expectClaims: map[string]interface{}{
+				"sub": workloadID.String(),
+			},
+		},
+		{
+			name: "matching hint",
+			id:   otherID,
+			hint: "external",
+			expectX509: credentialcomposer.X509SVIDAttributes{
+				DNSNames: []string{"external.example.org"},
+			},
+			expectClaims: map[string]interface{}{
+				"sub":  otherID.String(),
+				"hint": "external",
+			},
+		},
+		{
+			name: "matching hint and pattern",
+			id:   workloadID,
+			hint: "external",
+			expectX509: credentialcomposer.X509SVIDAttributes{
+				Subject:  pkix.Name{OrganizationalUnit: []string{"prod"}},
+				DNSNames: []string{"external.example.org"},
+			},
+			expectClaims: map[string]interface{}{
+				"sub":  workloadID.String(),
+				"hint": "external",
+			},
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := credentialcomposer.WithEntryHint(ctx, tt.hint)
+
+			gotX509, err := cc.ComposeWorkloadX509SVID(ctx, tt.id, key.Public(), credentialcomposer.X509SVIDAttributes{})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectX509, gotX509)
+
+			gotJWT, err := cc.ComposeWorkloadJWTSVID(ctx, tt.id, credentialcomposer.JWTSVIDAttributes{
+				Claims: map[string]interface{}{"sub": tt.id.String()},
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.expectClaims, gotJWT.Claims)
+		})
+	}
+}
+
+func loadPlugin(t *testing.T, config string) credentialcomposer.CredentialComposer {
+	cc := new(credentialcomposer.V1)
+	plugintest.Load(t, declarative.BuiltIn(), cc, plugintest.Configure(config))
+	return cc
+}
+
+func createCertificate(t *testing.T, attributes credentialcomposer.X509SVIDAttributes) *x509.Certificate {
+	tmpl := &x509.Certificate{
+		SerialNumber:    big.NewInt(1),
+		Subject:         attributes.Subject,
+		DNSNames:        attributes.DNSNames,
+		ExtraExtensions: attributes.ExtraExtensions,
+		NotAfter:        time.Now().Add(time.Hour),
+		KeyUsage:        x509.KeyUsageKeyEncipherment | x509.KeyUsageKeyAgreement | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
@@ -0,0 +1,37 @@
+package credentialcomposer
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// entryHintKey is the gRPC metadata key carrying the hint of the
+// registration entry a workload SVID is composed for.
+const entryHintKey = "spire-entry-hint"
+
+// WithEntryHint returns a context that makes the hint of the registration
+// entry available to the plugins composing a workload SVID. The
+// CredentialComposer plugin API does not carry the hint, so it is sent as
+// gRPC metadata. Plugins read it with EntryHintFromContext.
+func WithEntryHint(ctx context.Context, hint string) context.Context {
+	if hint == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, entryHintKey, hint)
+}
+
+// EntryHintFromContext returns the hint of the registration entry the
+// workload SVID is composed for, as sent with WithEntryHint. It returns an
+// empty string if the SVID is not minted for an entry or the entry has no
+// hint.
+func EntryHintFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(entryHintKey); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
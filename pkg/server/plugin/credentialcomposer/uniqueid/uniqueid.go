@@ -0,0 +1,172 @@
+package uniqueid
+
+import (
+	"context"
+	"sync"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	credentialcomposerv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/credentialcomposer/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/idutil"
+	"github.com/spiffe/spire/pkg/common/x509svid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pluginName = "uniqueid"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *Plugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		credentialcomposerv1.CredentialComposerPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+// Plugin is a credential composer that adds the X.500 unique identifier
+// attribute (see x509svid.UniqueIDAttribute) to the subject of the server,
+// agent and workload X509-SVIDs. The other credential types are left
+// untouched.
+type Plugin struct {
+	credentialcomposerv1.UnimplementedCredentialComposerServer
+	configv1.UnimplementedConfigServer
+
+	mtx      sync.RWMutex
+	serverID spiffeid.ID
+}
+
+func New() *Plugin {
+	return &Plugin{}
+}
+
+func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	if req.CoreConfiguration == nil {
+		return nil, status.Error(codes.InvalidArgument, "core configuration is required")
+	}
+
+	trustDomain, err := spiffeid.TrustDomainFromString(req.CoreConfiguration.TrustDomain)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "trust_domain is invalid: %v", err)
+	}
+
+	serverID, err := idutil.ServerID(trustDomain)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to build server ID: %v", err)
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.serverID = serverID
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+func (p *Plugin) ComposeServerX509SVID(_ context.Context, req *credentialcomposerv1.ComposeServerX509SVIDRequest) (*credentialcomposerv1.ComposeServerX509SVIDResponse, error) {
+	serverID, err := p.getServerID()
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, err := appendUniqueID(req.Attributes, serverID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &credentialcomposerv1.ComposeServerX509SVIDResponse{
+		Attributes: attributes,
+	}, nil
+}
+
+func (p *Plugin) ComposeAgentX509SVID(_ context.Context, req *credentialcomposerv1.ComposeAgentX509SVIDRequest) (*credentialcomposerv1.ComposeAgentX509SVIDResponse, error) {
+	attributes, err := appendUniqueID(req.Attributes, req.SpiffeId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &credentialcomposerv1.ComposeAgentX509SVIDResponse{
+		Attributes: attributes,
+	}, nil
+}
+
+func (p *Plugin) ComposeWorkloadX509SVID(_ context.Context, req *credentialcomposerv1.ComposeWorkloadX509SVIDRequest) (*credentialcomposerv1.ComposeWorkloadX509SVIDResponse, error) {
+	attributes, err := appendUniqueID(req.Attributes, req.SpiffeId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &credentialcomposerv1.ComposeWorkloadX509SVIDResponse{
+		Attributes: attributes,
+	}, nil
+}
+
+func (p *Plugin) getServerID() (spiffeid.ID, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	if p.serverID.IsZero() {
+		return spiffeid.ID{}, status.Error(codes.FailedPrecondition, "not configured")
+	}
+	return p.serverID, nil
+}
+
+// appendUniqueID appends the unique ID attribute for the given SPIFFE ID to
+// the subject of the attributes.
+func appendUniqueID(attributes *credentialcomposerv1.X509SVIDAttributes, spiffeID string) (*credentialcomposerv1.X509SVIDAttributes, error) {
+	switch {
+	case attributes == nil:
+		return nil, status.Error(codes.InvalidArgument, "request missing attributes")
+	case spiffeID == "":
+		return nil, status.Error(codes.InvalidArgument, "request missing SPIFFE ID")
+	}
+
+	id, err := spiffeid.FromString(spiffeID)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "malformed SPIFFE ID: %v", err)
+	}
+
+	uniqueID, err := uniqueIDAttributeTypeAndValue(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if attributes.Subject == nil {
+		attributes.Subject = &credentialcomposerv1.DistinguishedName{}
+	}
+
+	// The attribute is only appended once, even if the subject provided
+	// by the server (or a previous composer) already has it.
+	if !hasAttribute(attributes.Subject.ExtraNames, uniqueID) {
+		attributes.Subject.ExtraNames = append(attributes.Subject.ExtraNames, uniqueID)
+	}
+	return attributes, nil
+}
+
+func uniqueIDAttributeTypeAndValue(id spiffeid.ID) (*credentialcomposerv1.AttributeTypeAndValue, error) {
+	uniqueID := x509svid.UniqueIDAttribute(id)
+
+	stringValue, ok := uniqueID.Value.(string)
+	if !ok {
+		// purely defensive
+		return nil, status.Error(codes.Internal, "unique ID value is not a string")
+	}
+
+	return &credentialcomposerv1.AttributeTypeAndValue{
+		Oid:         uniqueID.Type.String(),
+		StringValue: stringValue,
+	}, nil
+}
+
+func hasAttribute(attributes []*credentialcomposerv1.AttributeTypeAndValue, attribute *credentialcomposerv1.AttributeTypeAndValue) bool {
+	for _, a := range attributes {
+		if a.Oid == attribute.Oid && a.StringValue == attribute.StringValue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package uniqueid_test
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	credentialcomposerv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/credentialcomposer/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/x509svid"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer"
+	"github.com/spiffe/spire/pkg/server/plugin/credentialcomposer/uniqueid"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/spiffe/spire/test/testkey"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+var (
+	ctx      = context.Background()
+	td       = spiffeid.RequireTrustDomainFromString("example.org")
+	serverID = spiffeid.RequireFromString("spiffe://example.org/spire/server")
+	agentID  = spiffeid.RequireFromString("spiffe://example.org/spire/agent/foo")
+	id       = spiffeid.RequireFromString("spiffe://example.org/workload")
+	key      = testkey.MustEC256()
+)
+
+func TestPlugin(t *testing.T) {
+	cc := loadPlugin(t)
+
+	t.Run("ComposeServerX509CA", func(t *testing.T) {
+		want := credentialcomposer.X509CAAttributes{Subject: pkix.Name{CommonName: "ca"}}
+		got, err := cc.ComposeServerX509CA(ctx, want)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	})
+
+	t.Run("ComposeServerX509SVID", func(t *testing.T) {
+		got, err := cc.ComposeServerX509SVID(ctx, credentialcomposer.X509SVIDAttributes{
+			Subject: pkix.Name{CommonName: "server"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, credentialcomposer.X509SVIDAttributes{
+			Subject: pkix.Name{
+				CommonName: "server",
+				ExtraNames: []pkix.AttributeTypeAndValue{x509svid.UniqueIDAttribute(serverID)},
+			},
+		}, got)
+	})
+
+	t.Run("ComposeAgentX509SVID", func(t *testing.T) {
+		got, err := cc.ComposeAgentX509SVID(ctx, agentID, key.Public(), credentialcomposer.X509SVIDAttributes{
+			Subject: pkix.Name{CommonName: "agent"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, credentialcomposer.X509SVIDAttributes{
+			Subject: pkix.Name{
+				CommonName: "agent",
+				ExtraNames: []pkix.AttributeTypeAndValue{x509svid.UniqueIDAttribute(agentID)},
+			},
+		}, got)
+	})
+
+	t.Run("ComposeWorkloadX509SVID", func(t *testing.T) {
+		got, err := cc.ComposeWorkloadX509SVID(ctx, id, key.Public(), credentialcomposer.X509SVIDAttributes{
+			Subject: pkix.Name{CommonName: "workload"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, credentialcomposer.X509SVIDAttributes{
+			Subject: pkix.Name{
+				CommonName: "workload",
+				ExtraNames: []pkix.AttributeTypeAndValue{x509svid.UniqueIDAttribute(id)},
+			},
+		}, got)
+	})
+
+	t.Run("ComposeWorkloadX509SVID with unique ID already present", func(t *testing.T) {
+		want := credentialcomposer.X509SVIDAttributes{
+			Subject: pkix.Name{
+				CommonName: "workload",
+				ExtraNames: []pkix.AttributeTypeAndValue{x509svid.UniqueIDAttribute(id)},
+			},
+		}
+		got, err := cc.ComposeWorkloadX509SVID(ctx, id, key.Public(), want)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	})
+
+	t.Run("ComposeWorkloadJWTSVID", func(t *testing.T) {
+		want := credentialcomposer.JWTSVIDAttributes{Claims: map[string]interface{}{"sub": id.String()}}
+		got, err := cc.ComposeWorkloadJWTSVID(ctx, id, want)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	})
+}
+
+func TestComposeWorkloadX509SVIDValidation(t *testing.T) {
+	p := uniqueid.New()
+
+	_, err := p.ComposeWorkloadX509SVID(ctx, &credentialcomposerv1.ComposeWorkloadX509SVIDRequest{SpiffeId: id.String()})
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "request missing attributes")
+
+	_, err = p.ComposeWorkloadX509SVID(ctx, &credentialcomposerv1.ComposeWorkloadX509SVIDRequest{
+		Attributes: &credentialcomposerv1.X509SVIDAttributes{},
+	})
+	spiretest.RequireGRPCStatus(t, err, codes.InvalidArgument, "request missing SPIFFE ID")
+
+	_, err = p.ComposeWorkloadX509SVID(ctx, &credentialcomposerv1.ComposeWorkloadX509SVIDRequest{
+		Attributes: &credentialcomposerv1.X509SVIDAttributes{},
+		SpiffeId:   "not-a-spiffe-id",
+	})
+	spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, "malformed SPIFFE ID")
+}
+
+func TestComposeServerX509SVIDNotConfigured(t *testing.T) {
+	p := uniqueid.New()
+
+	_, err := p.ComposeServerX509SVID(ctx, &credentialcomposerv1.ComposeServerX509SVIDRequest{
+		Attributes: &credentialcomposerv1.X509SVIDAttributes{},
+	})
+	spiretest.RequireGRPCStatus(t, err, codes.FailedPrecondition, "not configured")
+}
+
+func TestConfigure(t *testing.T) {
+	var err error
+	plugintest.Load(t, uniqueid.BuiltIn(), nil,
+		plugintest.CaptureConfigureError(&err),
+		plugintest.CoreConfig(catalog.CoreConfig{}),
+	)
+	spiretest.RequireGRPCStatusContains(t, err, codes.InvalidArgument, "trust_domain is invalid")
+}
+
+func loadPlugin(t *testing.T) credentialcomposer.CredentialComposer {
+	cc := new(credentialcomposer.V1)
+	plugintest.Load(t, uniqueid.BuiltIn(), cc,
+		plugintest.CoreConfig(catalog.CoreConfig{TrustDomain: td}),
+		plugintest.Configure(""),
+	)
+	return cc
+}
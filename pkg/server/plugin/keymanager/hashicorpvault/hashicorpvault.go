@@ -0,0 +1,647 @@
+package hashicorpvault
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/token"
+	keymanagerv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/keymanager/v1"
+	configv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/service/common/config/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/common/diskutil"
+	"github.com/spiffe/spire/pkg/common/pemutil"
+	vaultclient "github.com/spiffe/spire/pkg/common/plugin/vault"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	pluginName = "hashicorp_vault"
+
+	defaultTransitEnginePath = "transit"
+
+	keyNamePrefix = "spire-server"
+)
+
+func BuiltIn() catalog.BuiltIn {
+	return builtin(New())
+}
+
+func builtin(p *Plugin) catalog.BuiltIn {
+	return catalog.MakeBuiltIn(pluginName,
+		keymanagerv1.KeyManagerPluginServer(p),
+		configv1.ConfigServiceServer(p),
+	)
+}
+
+type keyEntry struct {
+	KeyName   string
+	Version   int
+	PublicKey *keymanagerv1.PublicKey
+}
+
+type pluginHooks struct {
+	lookupEnv func(string) (string, bool)
+}
+
+// Plugin is the main representation of this keymanager plugin
+type Plugin struct {
+	keymanagerv1.UnsafeKeyManagerServer
+	configv1.UnsafeConfigServer
+
+	log         hclog.Logger
+	mu          sync.RWMutex
+	entries     map[string]keyEntry
+	trustDomain string
+	serverID    string
+	mountPoint  string
+	hooks       pluginHooks
+
+	// clientMtx guards the Vault client, which is replaced when the token
+	// can no longer be renewed.
+	clientMtx  sync.Mutex
+	authMethod vaultclient.AuthMethod
+	cc         *vaultclient.ClientConfig
+	vc         *vaultclient.Client
+}
+
+// Config provides configuration context for the plugin
+type Config struct {
+	// A URL of Vault server. (e.g., https://vault.example.com:8443/)
+	VaultAddr string `hcl:"vault_addr" json:"vault_addr"`
+	// Name of the Vault namespace
+	Namespace string `hcl:"namespace" json:"namespace"`
+	// Name of the mount point where the Transit secrets engine is mounted (default: "transit")
+	TransitEnginePath string `hcl:"transit_engine_path" json:"transit_engine_path"`
+	// A file path location where information about generated keys will be persisted
+	KeyMetadataFile string `hcl:"key_metadata_file" json:"key_metadata_file"`
+	// Configuration for the Token authentication method
+	TokenAuth *vaultclient.TokenAuthConfig `hcl:"token_auth" json:"token_auth,omitempty"`
+	// Configuration for the Client Certificate authentication method
+	CertAuth *vaultclient.CertAuthConfig `hcl:"cert_auth" json:"cert_auth,omitempty"`
+	// Configuration for the AppRole authentication method
+	AppRoleAuth *vaultclient.AppRoleAuthConfig `hcl:"approle_auth" json:"approle_auth,omitempty"`
+	// Configuration for the Kubernetes authentication method
+	K8sAuth *vaultclient.K8sAuthConfig `hcl:"k8s_auth" json:"k8s_auth,omitempty"`
+	// Path to a CA certificate file that the client verifies the server certificate.
+	// Only PEM format is supported.
+	CACertPath string `hcl:"ca_cert_path" json:"ca_cert_path"`
+	// If true, vault client accepts any server certificates.
+	// It should be used only test environment so on.
+	InsecureSkipVerify bool `hcl:"insecure_skip_verify" json:"insecure_skip_verify"`
+
+	UnusedKeyPositions map[string][]token.Pos `hcl:",unusedKeyPositions" json:",omitempty"`
+}
+
+func (c *Config) vaultConfig() *vaultclient.Config {
+	return &vaultclient.Config{
+		VaultAddr:          c.VaultAddr,
+		CACertPath:         c.CACertPath,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		Namespace:          c.Namespace,
+		TokenAuth:          c.TokenAuth,
+		CertAuth:           c.CertAuth,
+		AppRoleAuth:        c.AppRoleAuth,
+		K8sAuth:            c.K8sAuth,
+	}
+}
+
+// New returns an instantiated plugin
+func New() *Plugin {
+	return &Plugin{
+		entries: make(map[string]keyEntry),
+		hooks: pluginHooks{
+			lookupEnv: os.LookupEnv,
+		},
+	}
+}
+
+// SetLogger sets a logger
+func (p *Plugin) SetLogger(log hclog.Logger) {
+	p.log = log
+}
+
+// Configure sets up the plugin
+func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*configv1.ConfigureResponse, error) {
+	config, err := parseAndValidateConfig(req.HclConfiguration)
+	if err != nil {
+		return nil, err
+	}
+
+	am, err := vaultclient.ParseAuthMethod(config.vaultConfig())
+	if err != nil {
+		return nil, err
+	}
+	cp, err := vaultclient.GenClientParams(am, config.vaultConfig(), p.hooks.lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+	vcConfig, err := vaultclient.NewClientConfig(cp, p.log)
+	if err != nil {
+		return nil, err
+	}
+
+	serverID, err := loadServerID(config.KeyMetadataFile)
+	if err != nil {
+		return nil, err
+	}
+	p.log.Debug("Loaded server id", "server_id", serverID)
+
+	p.clientMtx.Lock()
+	p.authMethod = am
+	p.cc = vcConfig
+	p.vc = nil
+	p.clientMtx.Unlock()
+
+	mountPoint := strings.Trim(config.TransitEnginePath, "/")
+	te, err := p.getTransitEngine(mountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	trustDomain := req.CoreConfiguration.TrustDomain
+	p.log.Debug("Fetching keys from the Transit secrets engine")
+	entries, err := p.fetchKeyEntries(te, keyNamePrefixForServer(trustDomain, serverID))
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.entries = entries
+	p.trustDomain = trustDomain
+	p.serverID = serverID
+	p.mountPoint = mountPoint
+
+	return &configv1.ConfigureResponse{}, nil
+}
+
+// GenerateKey creates a key in the Transit secrets engine. If the key already
+// exists it is rotated, or replaced when the key type changes.
+func (p *Plugin) GenerateKey(_ context.Context, req *keymanagerv1.GenerateKeyRequest) (*keymanagerv1.GenerateKeyResponse, error) {
+	if req.KeyId == "" {
+		return nil, status.Error(codes.InvalidArgument, "key id is required")
+	}
+	if req.KeyType == keymanagerv1.KeyType_UNSPECIFIED_KEY_TYPE {
+		return nil, status.Error(codes.InvalidArgument, "key type is required")
+	}
+	transitType, ok := transitTypeFromKeyType(req.KeyType)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported key type: %v", req.KeyType)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	te, err := p.getTransitEngine(p.mountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	spireKeyID := req.KeyId
+	keyName := keyNamePrefixForServer(p.trustDomain, p.serverID) + encodeKeyID(spireKeyID)
+	log := p.log.With("key_id", spireKeyID, "key_name", keyName)
+
+	existing, err := te.getKey(keyName)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get key: %v", err)
+	}
+
+	switch {
+	case existing == nil:
+		if err := te.createKey(keyName, transitType); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create key: %v", err)
+		}
+		log.Debug("Key created")
+	case existing.Type == transitType:
+		if err := te.rotateKey(keyName); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to rotate key: %v", err)
+		}
+		log.Debug("Key rotated")
+	default:
+		if err := te.deleteKey(keyName); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to delete key: %v", err)
+		}
+		if err := te.createKey(keyName, transitType); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to create key: %v", err)
+		}
+		log.Debug("Key replaced", "previous_type", existing.Type)
+	}
+
+	key, err := te.getKey(keyName)
+	switch {
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to get key: %v", err)
+	case key == nil:
+		return nil, status.Errorf(codes.Internal, "key %q not found after it was generated", keyName)
+	}
+
+	entry, err := makeKeyEntry(spireKeyID, keyName, key)
+	if err != nil {
+		return nil, err
+	}
+	p.entries[spireKeyID] = *entry
+
+	return &keymanagerv1.GenerateKeyResponse{
+		PublicKey: entry.PublicKey,
+	}, nil
+}
+
+// SignData creates a digital signature for the data to be signed
+func (p *Plugin) SignData(_ context.Context, req *keymanagerv1.SignDataRequest) (*keymanagerv1.SignDataResponse, error) {
+	if req.KeyId == "" {
+		return nil, status.Error(codes.InvalidArgument, "key id is required")
+	}
+	if req.SignerOpts == nil {
+		return nil, status.Error(codes.InvalidArgument, "signer opts is required")
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	entry, hasKey := p.entries[req.KeyId]
+	if !hasKey {
+		return nil, status.Errorf(codes.NotFound, "key %q not found", req.KeyId)
+	}
+
+	params, err := signingParamsForTransit(entry.PublicKey.Type, req.SignerOpts)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	te, err := p.getTransitEngine(p.mountPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := te.sign(entry.KeyName, entry.Version, params.hashAlgorithm, req.Data, params.signatureAlgorithm, params.saltLength)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to sign: %v", err)
+	}
+
+	return &keymanagerv1.SignDataResponse{
+		Signature:      signature,
+		KeyFingerprint: entry.PublicKey.Fingerprint,
+	}, nil
+}
+
+// GetPublicKey returns the public key for a given key
+func (p *Plugin) GetPublicKey(_ context.Context, req *keymanagerv1.GetPublicKeyRequest) (*keymanagerv1.GetPublicKeyResponse, error) {
+	if req.KeyId == "" {
+		return nil, status.Error(codes.InvalidArgument, "key id is required")
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	entry, ok := p.entries[req.KeyId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "key %q not found", req.KeyId)
+	}
+
+	return &keymanagerv1.GetPublicKeyResponse{
+		PublicKey: entry.PublicKey,
+	}, nil
+}
+
+// GetPublicKeys return the publicKey for all the keys
+func (p *Plugin) GetPublicKeys(context.Context, *keymanagerv1.GetPublicKeysRequest) (*keymanagerv1.GetPublicKeysResponse, error) {
+	var keys []*keymanagerv1.PublicKey
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, key := range p.entries {
+		keys = append(keys, key.PublicKey)
+	}
+
+	return &keymanagerv1.GetPublicKeysResponse{PublicKeys: keys}, nil
+}
+
+// fetchKeyEntries gets the latest version of the keys in the Transit secrets
+// engine that belong to this server.
+func (p *Plugin) fetchKeyEntries(te *transitEngine, prefix string) (map[string]keyEntry, error) {
+	names, err := te.listKeys()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list keys: %v", err)
+	}
+
+	entries := make(map[string]keyEntry)
+	for _, name := range names {
+		// ignore keys not belonging to this server
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		spireKeyID, err := decodeKeyID(strings.TrimPrefix(name, prefix))
+		if err != nil {
+			p.log.Warn("Ignoring key with malformed name", "key_name", name, "error", err)
+			continue
+		}
+
+		key, err := te.getKey(name)
+		switch {
+		case err != nil:
+			return nil, status.Errorf(codes.Internal, "failed to get key %q: %v", name, err)
+		case key == nil:
+			// The key was deleted after it was listed
+			continue
+		}
+
+		entry, err := makeKeyEntry(spireKeyID, name, key)
+		if err != nil {
+			return nil, err
+		}
+		entries[spireKeyID] = *entry
+	}
+
+	p.log.Debug("Found keys", "num_keys", len(entries))
+	return entries, nil
+}
+
+// getTransitEngine returns the Transit secrets engine, authenticating to
+// Vault if there is no authenticated client or its token could not be
+// renewed.
+func (p *Plugin) getTransitEngine(mountPoint string) (*transitEngine, error) {
+	p.clientMtx.Lock()
+	defer p.clientMtx.Unlock()
+
+	if p.cc == nil {
+		return nil, status.Error(codes.FailedPrecondition, "not configured")
+	}
+
+	if p.vc == nil {
+		renewCh := make(chan struct{})
+		vc, err := p.cc.NewAuthenticatedClient(p.authMethod, renewCh)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to prepare authenticated client: %v", err)
+		}
+		p.vc = vc
+
+		// if renewCh has been closed, the token can not be renewed and may expire,
+		// it needs to re-authenticate to the Vault.
+		go func() {
+			<-renewCh
+			p.clientMtx.Lock()
+			defer p.clientMtx.Unlock()
+			if p.vc == vc {
+				p.vc = nil
+			}
+			p.log.Debug("Going to re-authenticate to the Vault at the next key request time")
+		}()
+	}
+
+	return &transitEngine{
+		client:     p.vc,
+		mountPoint: mountPoint,
+	}, nil
+}
+
+// parseAndValidateConfig returns an error if any configuration provided does not meet acceptable criteria
+func parseAndValidateConfig(c string) (*Config, error) {
+	config := new(Config)
+
+	if err := hcl.Decode(config, c); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decode configuration: %v", err)
+	}
+
+	if len(config.UnusedKeyPositions) != 0 {
+		var keys []string
+		for k := range config.UnusedKeyPositions {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+		return nil, status.Errorf(codes.InvalidArgument, "unknown configurations detected: %s", strings.Join(keys, ","))
+	}
+
+	if config.KeyMetadataFile == "" {
+		return nil, status.Error(codes.InvalidArgument, "configuration is missing server id file path")
+	}
+
+	if config.TransitEnginePath == "" {
+		config.TransitEnginePath = defaultTransitEnginePath
+	}
+
+	return config, nil
+}
+
+func makeKeyEntry(spireKeyID, keyName string, key *transitKey) (*keyEntry, error) {
+	keyType, ok := keyTypeFromTransitType(key.Type)
+	if !ok {
+		return nil, status.Errorf(codes.Internal, "unsupported type %q of key %q", key.Type, keyName)
+	}
+
+	publicKey, err := pemutil.ParsePublicKey([]byte(key.PublicKey))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse public key of key %q: %v", keyName, err)
+	}
+	pkixData, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to marshal public key of key %q: %v", keyName, err)
+	}
+
+	return &keyEntry{
+		KeyName: keyName,
+		Version: key.LatestVersion,
+		PublicKey: &keymanagerv1.PublicKey{
+			Id:          spireKeyID,
+			Type:        keyType,
+			PkixData:    pkixData,
+			Fingerprint: makeFingerprint(pkixData),
+		},
+	}, nil
+}
+
+type signingParams struct {
+	hashAlgorithm      string
+	signatureAlgorithm string
+	saltLength         string
+}
+
+func signingParamsForTransit(keyType keymanagerv1.KeyType, signerOpts interface{}) (*signingParams, error) {
+	var (
+		hashAlgo   keymanagerv1.HashAlgorithm
+		isPSS      bool
+		saltLength int32
+	)
+
+	switch opts := signerOpts.(type) {
+	case *keymanagerv1.SignDataRequest_HashAlgorithm:
+		hashAlgo = opts.HashAlgorithm
+	case *keymanagerv1.SignDataRequest_PssOptions:
+		if opts.PssOptions == nil {
+			return nil, errors.New("PSS options are required")
+		}
+		hashAlgo = opts.PssOptions.HashAlgorithm
+		saltLength = opts.PssOptions.SaltLength
+		isPSS = true
+	default:
+		return nil, fmt.Errorf("unsupported signer opts type %T", opts)
+	}
+
+	params := new(signingParams)
+	switch hashAlgo {
+	case keymanagerv1.HashAlgorithm_UNSPECIFIED_HASH_ALGORITHM:
+		return nil, errors.New("hash algorithm is required")
+	case keymanagerv1.HashAlgorithm_SHA256:
+		params.hashAlgorithm = "sha2-256"
+	case keymanagerv1.HashAlgorithm_SHA384:
+		params.hashAlgorithm = "sha2-384"
+	case keymanagerv1.HashAlgorithm_SHA512:
+		params.hashAlgorithm = "sha2-512"
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm: %v", hashAlgo)
+	}
+
+	switch keyType {
+	case keymanagerv1.KeyType_EC_P256, keymanagerv1.KeyType_EC_P384:
+		if isPSS {
+			return nil, fmt.Errorf("unsupported combination of keytype: %v and PSS signature", keyType)
+		}
+	case keymanagerv1.KeyType_RSA_2048, keymanagerv1.KeyType_RSA_4096:
+		if !isPSS {
+			params.signatureAlgorithm = "pkcs1v15"
+			break
+		}
+		params.signatureAlgorithm = "pss"
+		switch {
+		case saltLength == rsa.PSSSaltLengthEqualsHash:
+			params.saltLength = "hash"
+		case saltLength == rsa.PSSSaltLengthAuto:
+			params.saltLength = "auto"
+		case saltLength > 0:
+			params.saltLength = strconv.Itoa(int(saltLength))
+		default:
+			return nil, fmt.Errorf("invalid PSS salt length: %d", saltLength)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type: %v", keyType)
+	}
+
+	return params, nil
+}
+
+func keyTypeFromTransitType(transitType string) (keymanagerv1.KeyType, bool) {
+	switch transitType {
+	case "ecdsa-p256":
+		return keymanagerv1.KeyType_EC_P256, true
+	case "ecdsa-p384":
+		return keymanagerv1.KeyType_EC_P384, true
+	case "rsa-2048":
+		return keymanagerv1.KeyType_RSA_2048, true
+	case "rsa-4096":
+		return keymanagerv1.KeyType_RSA_4096, true
+	default:
+		return keymanagerv1.KeyType_UNSPECIFIED_KEY_TYPE, false
+	}
+}
+
+func transitTypeFromKeyType(keyType keymanagerv1.KeyType) (string, bool) {
+	switch keyType {
+	case keymanagerv1.KeyType_EC_P256:
+		return "ecdsa-p256", true
+	case keymanagerv1.KeyType_EC_P384:
+		return "ecdsa-p384", true
+	case keymanagerv1.KeyType_RSA_2048:
+		return "rsa-2048", true
+	case keymanagerv1.KeyType_RSA_4096:
+		return "rsa-4096", true
+	default:
+		return "", false
+	}
+}
+
+// keyNamePrefixForServer returns the prefix of the names of the keys managed
+// by the server, of the form "spire-server-{TRUST_DOMAIN}-{SERVER_ID}-".
+func keyNamePrefixForServer(trustDomain, serverID string) string {
+	return fmt.Sprintf("%s-%s-%s-", keyNamePrefix, trustDomain, serverID)
+}
+
+func loadServerID(idPath string) (string, error) {
+	// get id from path
+	data, err := os.ReadFile(idPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return createServerID(idPath)
+	case err != nil:
+		return "", status.Errorf(codes.Internal, "failed to read server id from path: %v", err)
+	}
+
+	// validate what we got is a uuid
+	serverID, err := uuid.FromString(string(data))
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to parse server id from path: %v", err)
+	}
+	return serverID.String(), nil
+}
+
+func createServerID(idPath string) (string, error) {
+	// generate id
+	u, err := uuid.NewV4()
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to generate id for server: %v", err)
+	}
+	id := u.String()
+
+	// persist id
+	err = diskutil.WritePrivateFile(idPath, []byte(id))
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to persist server id on path: %v", err)
+	}
+	return id, nil
+}
+
+func makeFingerprint(pkixData []byte) string {
+	s := sha256.Sum256(pkixData)
+	return hex.EncodeToString(s[:])
+}
+
+// encodeKeyID maps the characters other than ASCII letters, digits and "-"
+// to their asciihex value using "_" as escape character, since Transit key
+// names are restricted to a small character set.
+func encodeKeyID(keyID string) string {
+	var b strings.Builder
+	for i := 0; i < len(keyID); i++ {
+		c := keyID[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "_%02x", c)
+		}
+	}
+	return b.String()
+}
+
+// decodeKeyID decodes the characters encoded by encodeKeyID.
+func decodeKeyID(keyID string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(keyID); i++ {
+		if keyID[i] != '_' {
+			b.WriteByte(keyID[i])
+			continue
+		}
+		if i+2 >= len(keyID) {
+			return "", fmt.Errorf("truncated escape sequence in %q", keyID)
+		}
+		c, err := hex.DecodeString(keyID[i+1 : i+3])
+		if err != nil {
+			return "", fmt.Errorf("invalid escape sequence in %q", keyID)
+		}
+		b.Write(c)
+		i += 2
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,634 @@
+package hashicorpvault
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	keymanagerv1 "github.com/spiffe/spire-plugin-sdk/proto/spire/plugin/server/keymanager/v1"
+	"github.com/spiffe/spire/pkg/common/catalog"
+	"github.com/spiffe/spire/pkg/server/plugin/keymanager"
+	keymanagertest "github.com/spiffe/spire/pkg/server/plugin/keymanager/test"
+	"github.com/spiffe/spire/test/plugintest"
+	"github.com/spiffe/spire/test/spiretest"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	serverID   = "8a8f7dd4-7c8a-4d0b-8c3c-7c2d6d1e3b0f"
+	keyPrefix  = "spire-server-example.org-" + serverID + "-"
+	spireKeyID = "spireKeyID"
+)
+
+var (
+	ctx         = context.Background()
+	isWindows   = runtime.GOOS == "windows"
+	trustDomain = spiffeid.RequireTrustDomainFromString("example.org")
+)
+
+func TestKeyManagerContract(t *testing.T) {
+	create := func(t *testing.T) keymanager.KeyManager {
+		vault := newFakeTransit(t)
+		km := new(keymanager.V1)
+		plugintest.Load(t, builtin(newTestPlugin()), km,
+			plugintest.CoreConfig(catalog.CoreConfig{
+				TrustDomain: trustDomain,
+			}),
+			plugintest.Configure(testConfig(t, vault)))
+		return km
+	}
+
+	keymanagertest.Test(t, keymanagertest.Config{
+		Create: create,
+	})
+}
+
+func TestConfigure(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+
+		config           func(vault *fakeTransit, keyMetadataFile string) string
+		failLookupSelf   bool
+		failPath         string
+		expectCode       codes.Code
+		expectMsgPrefix  string
+		expectMountPoint string
+	}{
+		{
+			name: "defaults",
+			config: func(vault *fakeTransit, keyMetadataFile string) string {
+				return fmt.Sprintf(`
+vault_addr = %q
+key_metadata_file = %q
+token_auth {
+	token = "test-token"
+}`, vault.server.URL, keyMetadataFile)
+			},
+			expectMountPoint: "transit",
+		},
+		{
+			name: "custom transit engine path",
+			config: func(vault *fakeTransit, keyMetadataFile string) string {
+				return fmt.Sprintf(`
+vault_addr = %q
+key_metadata_file = %q
+transit_engine_path = "/spire-transit/"
+token_auth {
+	token = "test-token"
+}`, vault.server.URL, keyMetadataFile)
+			},
+			expectMountPoint: "spire-transit",
+		},
+		{
+			name: "missing key metadata file",
+			config: func(vault *fakeTransit, _ string) string {
+				return fmt.Sprintf(`
+vault_addr = %q
+token_auth {
+	token = "test-token"
+}`, vault.server.URL)
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "configuration is missing server id file path",
+		},
+		{
+			name: "no auth method",
+			config: func(vault *fakeTransit, keyMetadataFile string) string {
+				return fmt.Sprintf(`
+vault_addr = %q
+key_metadata_file = %q`, vault.server.URL, keyMetadataFile)
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "must be configured one of these authentication method 'Token, Client Certificate, AppRole or Kubernetes",
+		},
+		{
+			name: "multiple auth methods",
+			config: func(vault *fakeTransit, keyMetadataFile string) string {
+				return fmt.Sprintf(`
+vault_addr = %q
+key_metadata_file = %q
+token_auth {
+	token = "test-token"
+}
+approle_auth {
+	approle_id = "test-approle-id"
+	approle_secret_id = "test-approle-secret-id"
+}`, vault.server.URL, keyMetadataFile)
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "only one authentication method can be configured",
+		},
+		{
+			name: "k8s auth without role",
+			config: func(vault *fakeTransit, keyMetadataFile string) string {
+				return fmt.Sprintf(`
+vault_addr = %q
+key_metadata_file = %q
+k8s_auth {
+	token_path = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+}`, vault.server.URL, keyMetadataFile)
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "k8s_auth_role_name is required",
+		},
+		{
+			name: "malformed configuration",
+			config: func(*fakeTransit, string) string {
+				return "{no a config}"
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "unable to decode configuration:",
+		},
+		{
+			name: "contains unused keys",
+			config: func(vault *fakeTransit, keyMetadataFile string) string {
+				return fmt.Sprintf(`
+vault_addr = %q
+key_metadata_file = %q
+kv_mount_point = "secret"
+token_auth {
+	token = "test-token"
+}`, vault.server.URL, keyMetadataFile)
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "unknown configurations detected: kv_mount_point",
+		},
+		{
+			name:            "authentication fails",
+			config:          testConfigFunc,
+			failLookupSelf:  true,
+			expectCode:      codes.Internal,
+			expectMsgPrefix: "failed to prepare authenticated client: rpc error: code = Internal desc = token lookup failed:",
+		},
+		{
+			name:            "list keys fails",
+			config:          testConfigFunc,
+			failPath:        "/v1/transit/keys",
+			expectCode:      codes.Internal,
+			expectMsgPrefix: "failed to list keys:",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			vault := newFakeTransit(t)
+			vault.failLookupSelf = tt.failLookupSelf
+			vault.failPath = tt.failPath
+			keyMetadataFile := newKeyMetadataFile(t)
+
+			p := newTestPlugin()
+			var err error
+			plugintest.Load(t, builtin(p), nil,
+				plugintest.CaptureConfigureError(&err),
+				plugintest.CoreConfig(catalog.CoreConfig{
+					TrustDomain: trustDomain,
+				}),
+				plugintest.Configure(tt.config(vault, keyMetadataFile)))
+			spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMsgPrefix)
+
+			if tt.expectCode != codes.OK {
+				return
+			}
+			require.Equal(t, tt.expectMountPoint, p.mountPoint)
+			require.Equal(t, "example.org", p.trustDomain)
+			require.Equal(t, serverID, p.serverID)
+		})
+	}
+}
+
+func TestConfigureCreatesServerID(t *testing.T) {
+	vault := newFakeTransit(t)
+	keyMetadataFile := filepath.Join(spiretest.TempDir(t), "metadata")
+	if isWindows {
+		keyMetadataFile = filepath.ToSlash(keyMetadataFile)
+	}
+
+	p := newTestPlugin()
+	plugintest.Load(t, builtin(p), nil,
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: trustDomain,
+		}),
+		plugintest.Configuref(`
+vault_addr = %q
+key_metadata_file = %q
+token_auth {
+	token = "test-token"
+}`, vault.server.URL, keyMetadataFile))
+
+	data, err := os.ReadFile(keyMetadataFile)
+	require.NoError(t, err)
+	require.Equal(t, p.serverID, string(data))
+	require.NotEqual(t, serverID, p.serverID)
+}
+
+func TestConfigureLoadsExistingKeys(t *testing.T) {
+	vault := newFakeTransit(t)
+	vault.putKey(t, keyPrefix+"x509-CA-A", "ecdsa-p256")
+	vault.putKey(t, keyPrefix+"JWT-Signer-A", "rsa-2048")
+	vault.putKey(t, keyPrefix+"with_2edot", "ecdsa-p384")
+	// Keys belonging to other servers, or not created by the plugin, are ignored
+	vault.putKey(t, "spire-server-example.org-00000000-0000-0000-0000-000000000000-x509-CA-A", "ecdsa-p256")
+	vault.putKey(t, "spire-server-other.org-"+serverID+"-x509-CA-A", "ecdsa-p256")
+	vault.putKey(t, "some-key", "rsa-4096")
+	// Keys with malformed names are ignored
+	vault.putKey(t, keyPrefix+"bad_zz", "ecdsa-p256")
+
+	p := newTestPlugin()
+	plugintest.Load(t, builtin(p), nil,
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: trustDomain,
+		}),
+		plugintest.Configure(testConfig(t, vault)))
+
+	resp, err := p.GetPublicKeys(ctx, &keymanagerv1.GetPublicKeysRequest{})
+	require.NoError(t, err)
+
+	keyTypes := make(map[string]keymanagerv1.KeyType)
+	for _, publicKey := range resp.PublicKeys {
+		keyTypes[publicKey.Id] = publicKey.Type
+	}
+	require.Equal(t, map[string]keymanagerv1.KeyType{
+		"x509-CA-A":    keymanagerv1.KeyType_EC_P256,
+		"JWT-Signer-A": keymanagerv1.KeyType_RSA_2048,
+		"with.dot":     keymanagerv1.KeyType_EC_P384,
+	}, keyTypes)
+}
+
+func TestConfigureFailsOnUnsupportedKeyType(t *testing.T) {
+	vault := newFakeTransit(t)
+	signer, err := vault.generateKey("ecdsa-p256")
+	require.NoError(t, err)
+	vault.keys[keyPrefix+"x509-CA-A"] = &fakeTransitKey{
+		keyType:  "aes256-gcm96",
+		versions: []crypto.Signer{signer},
+	}
+
+	plugintest.Load(t, builtin(newTestPlugin()), nil,
+		plugintest.CaptureConfigureError(&err),
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: trustDomain,
+		}),
+		plugintest.Configure(testConfig(t, vault)))
+	spiretest.RequireGRPCStatusHasPrefix(t, err, codes.Internal, `unsupported type "aes256-gcm96" of key "`+keyPrefix+`x509-CA-A"`)
+}
+
+func TestGenerateKey(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		existingType    string
+		keyType         keymanagerv1.KeyType
+		failPath        string
+		expectVersions  int
+		expectCode      codes.Code
+		expectMsgPrefix string
+	}{
+		{
+			name:           "create key",
+			keyType:        keymanagerv1.KeyType_EC_P256,
+			expectVersions: 1,
+		},
+		{
+			name:           "rotate key with the same type",
+			existingType:   "ecdsa-p256",
+			keyType:        keymanagerv1.KeyType_EC_P256,
+			expectVersions: 2,
+		},
+		{
+			name:           "replace key with a different type",
+			existingType:   "ecdsa-p256",
+			keyType:        keymanagerv1.KeyType_RSA_4096,
+			expectVersions: 1,
+		},
+		{
+			name:            "unsupported key type",
+			keyType:         keymanagerv1.KeyType(100),
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "unsupported key type: 100",
+		},
+		{
+			name:            "create fails",
+			keyType:         keymanagerv1.KeyType_EC_P256,
+			failPath:        "/v1/transit/keys/" + keyPrefix + spireKeyID,
+			expectCode:      codes.Internal,
+			expectMsgPrefix: "failed to get key:",
+		},
+		{
+			name:            "rotate fails",
+			existingType:    "ecdsa-p256",
+			keyType:         keymanagerv1.KeyType_EC_P256,
+			failPath:        "/v1/transit/keys/" + keyPrefix + spireKeyID + "/rotate",
+			expectCode:      codes.Internal,
+			expectMsgPrefix: "failed to rotate key:",
+		},
+		{
+			name:            "delete fails",
+			existingType:    "ecdsa-p256",
+			keyType:         keymanagerv1.KeyType_RSA_2048,
+			failPath:        "/v1/transit/keys/" + keyPrefix + spireKeyID + "/config",
+			expectCode:      codes.Internal,
+			expectMsgPrefix: "failed to delete key:",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			vault := newFakeTransit(t)
+			if tt.existingType != "" {
+				vault.putKey(t, keyPrefix+spireKeyID, tt.existingType)
+			}
+			p := loadPlugin(t, vault)
+			vault.failPath = tt.failPath
+
+			resp, err := p.GenerateKey(ctx, &keymanagerv1.GenerateKeyRequest{
+				KeyId:   spireKeyID,
+				KeyType: tt.keyType,
+			})
+			spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMsgPrefix)
+			if tt.expectCode != codes.OK {
+				return
+			}
+
+			require.Equal(t, spireKeyID, resp.PublicKey.Id)
+			require.Equal(t, tt.keyType, resp.PublicKey.Type)
+			require.Equal(t, tt.expectVersions, vault.keyVersions(keyPrefix+spireKeyID))
+
+			// The public key is the one of the latest version of the key
+			signer := vault.keys[keyPrefix+spireKeyID].versions[tt.expectVersions-1]
+			pkixData, err := x509.MarshalPKIXPublicKey(signer.Public())
+			require.NoError(t, err)
+			require.Equal(t, pkixData, resp.PublicKey.PkixData)
+			require.Equal(t, makeFingerprint(pkixData), resp.PublicKey.Fingerprint)
+
+			getResp, err := p.GetPublicKey(ctx, &keymanagerv1.GetPublicKeyRequest{KeyId: spireKeyID})
+			require.NoError(t, err)
+			spiretest.AssertProtoEqual(t, resp.PublicKey, getResp.PublicKey)
+		})
+	}
+}
+
+func TestGenerateKeyEncodesKeyID(t *testing.T) {
+	vault := newFakeTransit(t)
+	p := loadPlugin(t, vault)
+
+	_, err := p.GenerateKey(ctx, &keymanagerv1.GenerateKeyRequest{
+		KeyId:   "x509-CA.A+1",
+		KeyType: keymanagerv1.KeyType_EC_P256,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{keyPrefix + "x509-CA_2eA_2b1"}, vault.keyNames())
+}
+
+func TestSignData(t *testing.T) {
+	sum256 := sha256.Sum256(nil)
+
+	for _, tt := range []struct {
+		name            string
+		keyType         keymanagerv1.KeyType
+		request         *keymanagerv1.SignDataRequest
+		failPath        string
+		expectCode      codes.Code
+		expectMsgPrefix string
+	}{
+		{
+			name:    "EC with hash algorithm",
+			keyType: keymanagerv1.KeyType_EC_P256,
+			request: &keymanagerv1.SignDataRequest{
+				KeyId: spireKeyID,
+				Data:  sum256[:],
+				SignerOpts: &keymanagerv1.SignDataRequest_HashAlgorithm{
+					HashAlgorithm: keymanagerv1.HashAlgorithm_SHA256,
+				},
+			},
+		},
+		{
+			name:    "RSA with PSS options",
+			keyType: keymanagerv1.KeyType_RSA_2048,
+			request: &keymanagerv1.SignDataRequest{
+				KeyId: spireKeyID,
+				Data:  sum256[:],
+				SignerOpts: &keymanagerv1.SignDataRequest_PssOptions{
+					PssOptions: &keymanagerv1.SignDataRequest_PSSOptions{
+						HashAlgorithm: keymanagerv1.HashAlgorithm_SHA256,
+						SaltLength:    32,
+					},
+				},
+			},
+		},
+		{
+			name:    "key id is required",
+			keyType: keymanagerv1.KeyType_EC_P256,
+			request: &keymanagerv1.SignDataRequest{
+				Data: sum256[:],
+				SignerOpts: &keymanagerv1.SignDataRequest_HashAlgorithm{
+					HashAlgorithm: keymanagerv1.HashAlgorithm_SHA256,
+				},
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "key id is required",
+		},
+		{
+			name:    "signer opts is required",
+			keyType: keymanagerv1.KeyType_EC_P256,
+			request: &keymanagerv1.SignDataRequest{
+				KeyId: spireKeyID,
+				Data:  sum256[:],
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "signer opts is required",
+		},
+		{
+			name:    "key not found",
+			keyType: keymanagerv1.KeyType_EC_P256,
+			request: &keymanagerv1.SignDataRequest{
+				KeyId: "does-not-exist",
+				Data:  sum256[:],
+				SignerOpts: &keymanagerv1.SignDataRequest_HashAlgorithm{
+					HashAlgorithm: keymanagerv1.HashAlgorithm_SHA256,
+				},
+			},
+			expectCode:      codes.NotFound,
+			expectMsgPrefix: `key "does-not-exist" not found`,
+		},
+		{
+			name:    "hash algorithm is required",
+			keyType: keymanagerv1.KeyType_EC_P256,
+			request: &keymanagerv1.SignDataRequest{
+				KeyId: spireKeyID,
+				Data:  sum256[:],
+				SignerOpts: &keymanagerv1.SignDataRequest_HashAlgorithm{
+					HashAlgorithm: keymanagerv1.HashAlgorithm_UNSPECIFIED_HASH_ALGORITHM,
+				},
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "hash algorithm is required",
+		},
+		{
+			name:    "PSS options are required",
+			keyType: keymanagerv1.KeyType_RSA_2048,
+			request: &keymanagerv1.SignDataRequest{
+				KeyId:      spireKeyID,
+				Data:       sum256[:],
+				SignerOpts: &keymanagerv1.SignDataRequest_PssOptions{},
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "PSS options are required",
+		},
+		{
+			name:    "EC with PSS options",
+			keyType: keymanagerv1.KeyType_EC_P256,
+			request: &keymanagerv1.SignDataRequest{
+				KeyId: spireKeyID,
+				Data:  sum256[:],
+				SignerOpts: &keymanagerv1.SignDataRequest_PssOptions{
+					PssOptions: &keymanagerv1.SignDataRequest_PSSOptions{
+						HashAlgorithm: keymanagerv1.HashAlgorithm_SHA256,
+						SaltLength:    32,
+					},
+				},
+			},
+			expectCode:      codes.InvalidArgument,
+			expectMsgPrefix: "unsupported combination of keytype: EC_P256 and PSS signature",
+		},
+		{
+			name:    "sign fails",
+			keyType: keymanagerv1.KeyType_EC_P256,
+			request: &keymanagerv1.SignDataRequest{
+				KeyId: spireKeyID,
+				Data:  sum256[:],
+				SignerOpts: &keymanagerv1.SignDataRequest_HashAlgorithm{
+					HashAlgorithm: keymanagerv1.HashAlgorithm_SHA256,
+				},
+			},
+			failPath:        "/v1/transit/sign/" + keyPrefix + spireKeyID + "/sha2-256",
+			expectCode:      codes.Internal,
+			expectMsgPrefix: "failed to sign:",
+		},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			vault := newFakeTransit(t)
+			p := loadPlugin(t, vault)
+
+			genResp, err := p.GenerateKey(ctx, &keymanagerv1.GenerateKeyRequest{
+				KeyId:   spireKeyID,
+				KeyType: tt.keyType,
+			})
+			require.NoError(t, err)
+			vault.failPath = tt.failPath
+
+			resp, err := p.SignData(ctx, tt.request)
+			spiretest.RequireGRPCStatusHasPrefix(t, err, tt.expectCode, tt.expectMsgPrefix)
+			if tt.expectCode != codes.OK {
+				return
+			}
+			require.NotEmpty(t, resp.Signature)
+			require.Equal(t, genResp.PublicKey.Fingerprint, resp.KeyFingerprint)
+		})
+	}
+}
+
+func TestSignDataUsesGeneratedKeyVersion(t *testing.T) {
+	vault := newFakeTransit(t)
+	p := loadPlugin(t, vault)
+
+	genResp, err := p.GenerateKey(ctx, &keymanagerv1.GenerateKeyRequest{
+		KeyId:   spireKeyID,
+		KeyType: keymanagerv1.KeyType_EC_P256,
+	})
+	require.NoError(t, err)
+
+	// Rotating the key out of the plugin does not change the key used to sign
+	vault.mtx.Lock()
+	key := vault.keys[keyPrefix+spireKeyID]
+	newVersion, err := vault.generateKey(key.keyType)
+	require.NoError(t, err)
+	key.versions = append(key.versions, newVersion)
+	vault.mtx.Unlock()
+
+	sum256 := sha256.Sum256([]byte("DATA"))
+	resp, err := p.SignData(ctx, &keymanagerv1.SignDataRequest{
+		KeyId: spireKeyID,
+		Data:  sum256[:],
+		SignerOpts: &keymanagerv1.SignDataRequest_HashAlgorithm{
+			HashAlgorithm: keymanagerv1.HashAlgorithm_SHA256,
+		},
+	})
+	require.NoError(t, err)
+
+	publicKey, err := x509.ParsePKIXPublicKey(genResp.PublicKey.PkixData)
+	require.NoError(t, err)
+	ecPublicKey, ok := publicKey.(*ecdsa.PublicKey)
+	require.True(t, ok)
+	require.True(t, ecdsa.VerifyASN1(ecPublicKey, sum256[:], resp.Signature))
+}
+
+func TestNotConfigured(t *testing.T) {
+	p := New()
+	_, err := p.GenerateKey(ctx, &keymanagerv1.GenerateKeyRequest{
+		KeyId:   spireKeyID,
+		KeyType: keymanagerv1.KeyType_EC_P256,
+	})
+	spiretest.RequireGRPCStatus(t, err, codes.FailedPrecondition, "not configured")
+}
+
+func TestEncodeKeyID(t *testing.T) {
+	for _, keyID := range []string{"x509-CA-A", "JWT-Signer-A", "with.dot", "with_underscore", "a+b/c d"} {
+		encoded := encodeKeyID(keyID)
+		require.Regexp(t, `^[A-Za-z0-9\-_]+$`, encoded)
+		decoded, err := decodeKeyID(encoded)
+		require.NoError(t, err)
+		require.Equal(t, keyID, decoded)
+	}
+
+	_, err := decodeKeyID("abc_2")
+	require.EqualError(t, err, `truncated escape sequence in "abc_2"`)
+	_, err = decodeKeyID("abc_zz")
+	require.EqualError(t, err, `invalid escape sequence in "abc_zz"`)
+}
+
+func loadPlugin(t *testing.T, vault *fakeTransit) *Plugin {
+	p := newTestPlugin()
+	plugintest.Load(t, builtin(p), nil,
+		plugintest.CoreConfig(catalog.CoreConfig{
+			TrustDomain: trustDomain,
+		}),
+		plugintest.Configure(testConfig(t, vault)))
+	return p
+}
+
+func newTestPlugin() *Plugin {
+	p := New()
+	p.hooks.lookupEnv = func(string) (string, bool) { return "", false }
+	return p
+}
+
+func testConfig(t *testing.T, vault *fakeTransit) string {
+	return testConfigFunc(vault, newKeyMetadataFile(t))
+}
+
+func testConfigFunc(vault *fakeTransit, keyMetadataFile string) string {
+	return fmt.Sprintf(`
+vault_addr = %q
+key_metadata_file = %q
+token_auth {
+	token = "test-token"
+}`, vault.server.URL, keyMetadataFile)
+}
+
+// newKeyMetadataFile returns the path of a key metadata file holding a
+// well-known server ID
+func newKeyMetadataFile(t *testing.T) string {
+	keyMetadataFile := filepath.Join(spiretest.TempDir(t), "metadata")
+	require.NoError(t, os.WriteFile(keyMetadataFile, []byte(serverID), 0600))
+	if isWindows {
+		keyMetadataFile = filepath.ToSlash(keyMetadataFile)
+	}
+	return keyMetadataFile
+}
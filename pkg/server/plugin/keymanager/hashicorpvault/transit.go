@@ -0,0 +1,179 @@
+package hashicorpvault
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	vapi "github.com/hashicorp/vault/api"
+	vaultclient "github.com/spiffe/spire/pkg/common/plugin/vault"
+)
+
+// transitKey is a key stored in the Transit secrets engine
+type transitKey struct {
+	Type          string
+	LatestVersion int
+	// PublicKey is the PEM encoded public key of the latest version
+	PublicKey string
+}
+
+// transitEngine creates, rotates and signs with the keys of a Transit
+// secrets engine.
+type transitEngine struct {
+	client     *vaultclient.Client
+	mountPoint string
+}
+
+func (te *transitEngine) keyPath(name string) string {
+	return fmt.Sprintf("%s/keys/%s", te.mountPoint, name)
+}
+
+// listKeys returns the names of all the keys in the Transit secrets engine
+func (te *transitEngine) listKeys() ([]string, error) {
+	secret, err := te.client.List(fmt.Sprintf("%s/keys", te.mountPoint))
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, nil
+	}
+
+	rawKeys, _ := secret.Data["keys"].([]interface{})
+	names := make([]string, 0, len(rawKeys))
+	for _, rawKey := range rawKeys {
+		if name, ok := rawKey.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// getKey returns the key with the given name, or nil if not found
+func (te *transitEngine) getKey(name string) (*transitKey, error) {
+	secret, err := te.client.Read(te.keyPath(name))
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, nil
+	}
+
+	return parseTransitKey(secret)
+}
+
+func (te *transitEngine) createKey(name, keyType string) error {
+	_, err := te.client.Write(te.keyPath(name), map[string]interface{}{
+		"type": keyType,
+	})
+	return err
+}
+
+// rotateKey creates a new version of the key, that becomes the latest one
+func (te *transitEngine) rotateKey(name string) error {
+	_, err := te.client.Write(te.keyPath(name)+"/rotate", nil)
+	return err
+}
+
+// deleteKey deletes the key. Transit keys can not be deleted unless deletion
+// is explicitly allowed on them, so it is allowed first.
+func (te *transitEngine) deleteKey(name string) error {
+	if _, err := te.client.Write(te.keyPath(name)+"/config", map[string]interface{}{
+		"deletion_allowed": true,
+	}); err != nil {
+		return err
+	}
+	_, err := te.client.Delete(te.keyPath(name))
+	return err
+}
+
+// sign signs the digest with the given version of the key, returning the
+// signature without the "vault:v<version>:" prefix.
+func (te *transitEngine) sign(name string, version int, hashAlgorithm string, digest []byte, signatureAlgorithm, saltLength string) ([]byte, error) {
+	data := map[string]interface{}{
+		"input":                base64.StdEncoding.EncodeToString(digest),
+		"prehashed":            true,
+		"key_version":          version,
+		"marshaling_algorithm": "asn1",
+	}
+	if signatureAlgorithm != "" {
+		data["signature_algorithm"] = signatureAlgorithm
+	}
+	if saltLength != "" {
+		data["salt_length"] = saltLength
+	}
+
+	secret, err := te.client.Write(fmt.Sprintf("%s/sign/%s/%s", te.mountPoint, name, hashAlgorithm), data)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("empty response")
+	}
+
+	signature, ok := secret.Data["signature"].(string)
+	if !ok {
+		return nil, fmt.Errorf("signature not found in response")
+	}
+	return parseSignature(signature)
+}
+
+func parseTransitKey(secret *vapi.Secret) (*transitKey, error) {
+	keyType, ok := secret.Data["type"].(string)
+	if !ok {
+		return nil, fmt.Errorf("key type not found")
+	}
+
+	latestVersion, err := toInt(secret.Data["latest_version"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid latest version: %w", err)
+	}
+
+	versions, ok := secret.Data["keys"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("key versions not found")
+	}
+	version, ok := versions[strconv.Itoa(latestVersion)].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("latest version %d not found", latestVersion)
+	}
+	publicKey, ok := version["public_key"].(string)
+	if !ok || publicKey == "" {
+		return nil, fmt.Errorf("public key of version %d not found", latestVersion)
+	}
+
+	return &transitKey{
+		Type:          keyType,
+		LatestVersion: latestVersion,
+		PublicKey:     publicKey,
+	}, nil
+}
+
+// parseSignature decodes a signature in the "vault:v<version>:<base64>" form
+func parseSignature(signature string) ([]byte, error) {
+	parts := strings.SplitN(signature, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, fmt.Errorf("malformed signature %q", signature)
+	}
+
+	b, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature: %w", err)
+	}
+	return b, nil
+}
+
+func toInt(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err
+	case float64:
+		return int(n), nil
+	case int:
+		return n, nil
+	default:
+		return 0, fmt.Errorf("unexpected type %T", v)
+	}
+}
@@ -0,0 +1,380 @@
+package hashicorpvault
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spiffe/spire/test/testkey"
+)
+
+// fakeTransit is a Vault server with the token auth method and a Transit
+// secrets engine mounted at "transit".
+type fakeTransit struct {
+	server *httptest.Server
+
+	mtx            sync.Mutex
+	keys           map[string]*fakeTransitKey
+	gen            testkey.Generator
+	failLookupSelf bool
+	failPath       string
+}
+
+type fakeTransitKey struct {
+	keyType         string
+	versions        []crypto.Signer
+	deletionAllowed bool
+}
+
+func newFakeTransit(t *testing.T) *fakeTransit {
+	v := &fakeTransit{
+		keys: make(map[string]*fakeTransitKey),
+	}
+	v.server = httptest.NewServer(http.HandlerFunc(v.serveHTTP))
+	t.Cleanup(v.server.Close)
+	return v
+}
+
+// putKey adds a key, as if it was created out of the plugin
+func (v *fakeTransit) putKey(t *testing.T, name, keyType string) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	key, err := v.generateKey(keyType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.keys[name] = &fakeTransitKey{
+		keyType:  keyType,
+		versions: []crypto.Signer{key},
+	}
+}
+
+func (v *fakeTransit) keyNames() []string {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	var names []string
+	for name := range v.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (v *fakeTransit) keyVersions(name string) int {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	if key, ok := v.keys[name]; ok {
+		return len(key.versions)
+	}
+	return 0
+}
+
+func (v *fakeTransit) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	if r.URL.Path == "/v1/auth/token/lookup-self" {
+		if v.failLookupSelf || r.Header.Get("X-Vault-Token") != "test-token" {
+			writeError(w, http.StatusForbidden, "permission denied")
+			return
+		}
+		writeResponse(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"id":        "test-token",
+				"renewable": false,
+				"ttl":       0,
+			},
+		})
+		return
+	}
+
+	if r.URL.Path == v.failPath {
+		writeError(w, http.StatusBadRequest, "some error")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/transit/")
+	if path == r.URL.Path {
+		writeError(w, http.StatusNotFound, "no handler for route")
+		return
+	}
+
+	var body map[string]interface{}
+	if r.Method == http.MethodPut || r.Method == http.MethodPost {
+		decoder := json.NewDecoder(r.Body)
+		decoder.UseNumber()
+		if err := decoder.Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	parts := strings.Split(path, "/")
+	switch {
+	case path == "keys" && r.Method == http.MethodGet && r.URL.Query().Get("list") == "true":
+		v.listKeys(w)
+	case len(parts) == 2 && parts[0] == "keys":
+		switch r.Method {
+		case http.MethodGet:
+			v.readKey(w, parts[1])
+		case http.MethodPut, http.MethodPost:
+			v.createKey(w, parts[1], body)
+		case http.MethodDelete:
+			v.deleteKey(w, parts[1])
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "unsupported operation")
+		}
+	case len(parts) == 3 && parts[0] == "keys" && parts[2] == "rotate":
+		v.rotateKey(w, parts[1])
+	case len(parts) == 3 && parts[0] == "keys" && parts[2] == "config":
+		v.configKey(w, parts[1], body)
+	case len(parts) == 3 && parts[0] == "sign":
+		v.sign(w, parts[1], parts[2], body)
+	default:
+		writeError(w, http.StatusNotFound, "no handler for route")
+	}
+}
+
+func (v *fakeTransit) listKeys(w http.ResponseWriter) {
+	if len(v.keys) == 0 {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+
+	var names []string
+	for name := range v.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	writeResponse(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{"keys": names},
+	})
+}
+
+func (v *fakeTransit) readKey(w http.ResponseWriter, name string) {
+	key, ok := v.keys[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, "")
+		return
+	}
+
+	versions := make(map[string]interface{})
+	for i, signer := range key.versions {
+		pkixData, err := x509.MarshalPKIXPublicKey(signer.Public())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		versions[strconv.Itoa(i+1)] = map[string]interface{}{
+			"public_key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkixData})),
+		}
+	}
+
+	writeResponse(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{
+			"name":             name,
+			"type":             key.keyType,
+			"deletion_allowed": key.deletionAllowed,
+			"latest_version":   len(key.versions),
+			"keys":             versions,
+		},
+	})
+}
+
+func (v *fakeTransit) createKey(w http.ResponseWriter, name string, body map[string]interface{}) {
+	if _, ok := v.keys[name]; ok {
+		// Vault does not fail when the key already exists
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	keyType, _ := body["type"].(string)
+	key, err := v.generateKey(keyType)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	v.keys[name] = &fakeTransitKey{
+		keyType:  keyType,
+		versions: []crypto.Signer{key},
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (v *fakeTransit) rotateKey(w http.ResponseWriter, name string) {
+	key, ok := v.keys[name]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "key not found")
+		return
+	}
+
+	signer, err := v.generateKey(key.keyType)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	key.versions = append(key.versions, signer)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (v *fakeTransit) configKey(w http.ResponseWriter, name string, body map[string]interface{}) {
+	key, ok := v.keys[name]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "no existing key named "+name)
+		return
+	}
+
+	if deletionAllowed, ok := body["deletion_allowed"].(bool); ok {
+		key.deletionAllowed = deletionAllowed
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (v *fakeTransit) deleteKey(w http.ResponseWriter, name string) {
+	key, ok := v.keys[name]
+	if !ok {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if !key.deletionAllowed {
+		writeError(w, http.StatusBadRequest, "deletion is not allowed for this key")
+		return
+	}
+
+	delete(v.keys, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (v *fakeTransit) sign(w http.ResponseWriter, name, hashAlgorithm string, body map[string]interface{}) {
+	key, ok := v.keys[name]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "signing key not found")
+		return
+	}
+
+	if prehashed, _ := body["prehashed"].(bool); !prehashed {
+		writeError(w, http.StatusBadRequest, "input is expected to be prehashed")
+		return
+	}
+	if marshaling, _ := body["marshaling_algorithm"].(string); marshaling != "asn1" {
+		writeError(w, http.StatusBadRequest, "unexpected marshaling algorithm")
+		return
+	}
+
+	version := len(key.versions)
+	if n, ok := body["key_version"].(json.Number); ok {
+		i, err := n.Int64()
+		if err != nil || i < 1 || int(i) > len(key.versions) {
+			writeError(w, http.StatusBadRequest, "invalid key version")
+			return
+		}
+		version = int(i)
+	}
+
+	input, _ := body["input"].(string)
+	digest, err := base64.StdEncoding.DecodeString(input)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unable to decode input as base64")
+		return
+	}
+
+	var hash crypto.Hash
+	switch hashAlgorithm {
+	case "sha2-256":
+		hash = crypto.SHA256
+	case "sha2-384":
+		hash = crypto.SHA384
+	case "sha2-512":
+		hash = crypto.SHA512
+	default:
+		writeError(w, http.StatusBadRequest, "unsupported hash algorithm "+hashAlgorithm)
+		return
+	}
+
+	var signature []byte
+	switch signer := key.versions[version-1].(type) {
+	case *ecdsa.PrivateKey:
+		signature, err = ecdsa.SignASN1(rand.Reader, signer, digest)
+	case *rsa.PrivateKey:
+		signatureAlgorithm, _ := body["signature_algorithm"].(string)
+		switch signatureAlgorithm {
+		case "", "pss":
+			opts := &rsa.PSSOptions{Hash: hash, SaltLength: rsa.PSSSaltLengthAuto}
+			switch saltLength, _ := body["salt_length"].(string); saltLength {
+			case "", "auto":
+			case "hash":
+				opts.SaltLength = rsa.PSSSaltLengthEqualsHash
+			default:
+				opts.SaltLength, err = strconv.Atoi(saltLength)
+				if err != nil {
+					writeError(w, http.StatusBadRequest, "invalid salt length")
+					return
+				}
+			}
+			signature, err = rsa.SignPSS(rand.Reader, signer, hash, digest, opts)
+		case "pkcs1v15":
+			signature, err = rsa.SignPKCS1v15(rand.Reader, signer, hash, digest)
+		default:
+			writeError(w, http.StatusBadRequest, "unsupported signature algorithm "+signatureAlgorithm)
+			return
+		}
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeResponse(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{
+			"signature":   fmt.Sprintf("vault:v%d:%s", version, base64.StdEncoding.EncodeToString(signature)),
+			"key_version": version,
+		},
+	})
+}
+
+func (v *fakeTransit) generateKey(keyType string) (crypto.Signer, error) {
+	switch keyType {
+	case "ecdsa-p256":
+		return v.gen.GenerateEC256Key()
+	case "ecdsa-p384":
+		return v.gen.GenerateEC384Key()
+	case "rsa-2048":
+		return v.gen.GenerateRSA2048Key()
+	case "rsa-4096":
+		return v.gen.GenerateRSA4096Key()
+	default:
+		return nil, fmt.Errorf("unknown key type %q", keyType)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	var errs []string
+	if msg != "" {
+		errs = append(errs, msg)
+	}
+	writeResponse(w, code, map[string]interface{}{"errors": errs})
+}
+
+func writeResponse(w http.ResponseWriter, code int, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/common/coretypes/x509certificate"
 	"github.com/spiffe/spire/pkg/common/pemutil"
+	vaultclient "github.com/spiffe/spire/pkg/common/plugin/vault"
 )
 
 const (
@@ -41,13 +42,13 @@ type Configuration struct {
 	// Name of the mount point where PKI secret engine is mounted. (e.g., /<mount_point>/ca/pem)
 	PKIMountPoint string `hcl:"pki_mount_point" json:"pki_mount_point"`
 	// Configuration for the Token authentication method
-	TokenAuth *TokenAuthConfig `hcl:"token_auth" json:"token_auth,omitempty"`
+	TokenAuth *vaultclient.TokenAuthConfig `hcl:"token_auth" json:"token_auth,omitempty"`
 	// Configuration for the Client Certificate authentication method
-	CertAuth *CertAuthConfig `hcl:"cert_auth" json:"cert_auth,omitempty"`
+	CertAuth *vaultclient.CertAuthConfig `hcl:"cert_auth" json:"cert_auth,omitempty"`
 	// Configuration for the AppRole authentication method
-	AppRoleAuth *AppRoleAuthConfig `hcl:"approle_auth" json:"approle_auth,omitempty"`
+	AppRoleAuth *vaultclient.AppRoleAuthConfig `hcl:"approle_auth" json:"approle_auth,omitempty"`
 	// Configuration for the Kubernetes authentication method
-	K8sAuth *K8sAuthConfig `hcl:"k8s_auth" json:"k8s_auth,omitempty"`
+	K8sAuth *vaultclient.K8sAuthConfig `hcl:"k8s_auth" json:"k8s_auth,omitempty"`
 	// Path to a CA certificate file that the client verifies the server certificate.
 	// Only PEM format is supported.
 	CACertPath string `hcl:"ca_cert_path" json:"ca_cert_path"`
@@ -58,49 +59,17 @@ type Configuration struct {
 	Namespace string `hcl:"namespace" json:"namespace"`
 }
 
-// TokenAuthConfig represents parameters for token auth method
-type TokenAuthConfig struct {
-	// Token string to set into "X-Vault-Token" header
-	Token string `hcl:"token" json:"token"`
-}
-
-// CertAuthConfig represents parameters for cert auth method
-type CertAuthConfig struct {
-	// Name of the mount point where Client Certificate Auth method is mounted. (e.g., /auth/<mount_point>/login)
-	// If the value is empty, use default mount point (/auth/cert)
-	CertAuthMountPoint string `hcl:"cert_auth_mount_point" json:"cert_auth_mount_point"`
-	// Name of the Vault role.
-	// If given, the plugin authenticates against only the named role.
-	CertAuthRoleName string `hcl:"cert_auth_role_name" json:"cert_auth_role_name"`
-	// Path to a client certificate file.
-	// Only PEM format is supported.
-	ClientCertPath string `hcl:"client_cert_path" json:"client_cert_path"`
-	// Path to a client private key file.
-	// Only PEM format is supported.
-	ClientKeyPath string `hcl:"client_key_path" json:"client_key_path"`
-}
-
-// AppRoleAuthConfig represents parameters for AppRole auth method.
-type AppRoleAuthConfig struct {
-	// Name of the mount point where AppRole auth method is mounted. (e.g., /auth/<mount_point>/login)
-	// If the value is empty, use default mount point (/auth/approle)
-	AppRoleMountPoint string `hcl:"approle_auth_mount_point" json:"approle_auth_mount_point"`
-	// An identifier that selects the AppRole
-	RoleID string `hcl:"approle_id" json:"approle_id"`
-	// A credential that is required for login.
-	SecretID string `hcl:"approle_secret_id" json:"approle_secret_id"`
-}
-
-// K8sAuthConfig represents parameters for Kubernetes auth method.
-type K8sAuthConfig struct {
-	// Name of the mount point where Kubernetes auth method is mounted. (e.g., /auth/<mount_point>/login)
-	// If the value is empty, use default mount point (/auth/kubernetes)
-	K8sAuthMountPoint string `hcl:"k8s_auth_mount_point" json:"k8s_auth_mount_point"`
-	// Name of the Vault role.
-	// The plugin authenticates against the named role.
-	K8sAuthRoleName string `hcl:"k8s_auth_role_name" json:"k8s_auth_role_name"`
-	// Path to the Kubernetes Service Account Token to use authentication with the Vault.
-	TokenPath string `hcl:"token_path" json:"token_path"`
+func (c *Configuration) vaultConfig() *vaultclient.Config {
+	return &vaultclient.Config{
+		VaultAddr:          c.VaultAddr,
+		CACertPath:         c.CACertPath,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+		Namespace:          c.Namespace,
+		TokenAuth:          c.TokenAuth,
+		CertAuth:           c.CertAuth,
+		AppRoleAuth:        c.AppRoleAuth,
+		K8sAuth:            c.K8sAuth,
+	}
 }
 
 type Plugin struct {
@@ -110,9 +79,9 @@ type Plugin struct {
 	mtx    *sync.RWMutex
 	logger hclog.Logger
 
-	authMethod AuthMethod
-	cc         *ClientConfig
-	vc         *Client
+	authMethod vaultclient.AuthMethod
+	cc         *vaultclient.ClientConfig
+	vc         *vaultclient.Client
 
 	hooks struct {
 		lookupEnv func(string) (string, bool)
@@ -143,7 +112,7 @@ func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*
 	p.mtx.Lock()
 	defer p.mtx.Unlock()
 
-	am, err := parseAuthMethod(config)
+	am, err := vaultclient.ParseAuthMethod(config.vaultConfig())
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +120,7 @@ func (p *Plugin) Configure(_ context.Context, req *configv1.ConfigureRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	vcConfig, err := NewClientConfig(cp, p.logger)
+	vcConfig, err := vaultclient.NewClientConfig(cp, p.logger)
 	if err != nil {
 		return nil, err
 	}
@@ -265,83 +234,11 @@ func (*Plugin) PublishJWTKeyAndSubscribe(*upstreamauthorityv1.PublishJWTKeyReque
 	return status.Error(codes.Unimplemented, "publishing upstream is unsupported")
 }
 
-func (p *Plugin) genClientParams(method AuthMethod, config *Configuration) (*ClientParams, error) {
-	cp := &ClientParams{
-		VaultAddr:     p.getEnvOrDefault(envVaultAddr, config.VaultAddr),
-		CACertPath:    p.getEnvOrDefault(envVaultCACert, config.CACertPath),
-		PKIMountPoint: config.PKIMountPoint,
-		TLSSKipVerify: config.InsecureSkipVerify,
-		Namespace:     p.getEnvOrDefault(envVaultNamespace, config.Namespace),
-	}
-
-	switch method {
-	case TOKEN:
-		cp.Token = p.getEnvOrDefault(envVaultToken, config.TokenAuth.Token)
-	case CERT:
-		cp.CertAuthMountPoint = config.CertAuth.CertAuthMountPoint
-		cp.CertAuthRoleName = config.CertAuth.CertAuthRoleName
-		cp.ClientCertPath = p.getEnvOrDefault(envVaultClientCert, config.CertAuth.ClientCertPath)
-		cp.ClientKeyPath = p.getEnvOrDefault(envVaultClientKey, config.CertAuth.ClientKeyPath)
-	case APPROLE:
-		cp.AppRoleAuthMountPoint = config.AppRoleAuth.AppRoleMountPoint
-		cp.AppRoleID = p.getEnvOrDefault(envVaultAppRoleID, config.AppRoleAuth.RoleID)
-		cp.AppRoleSecretID = p.getEnvOrDefault(envVaultAppRoleSecretID, config.AppRoleAuth.SecretID)
-	case K8S:
-		if config.K8sAuth.K8sAuthRoleName == "" {
-			return nil, status.Error(codes.InvalidArgument, "k8s_auth_role_name is required")
-		}
-		if config.K8sAuth.TokenPath == "" {
-			return nil, status.Error(codes.InvalidArgument, "token_path is required")
-		}
-		cp.K8sAuthMountPoint = config.K8sAuth.K8sAuthMountPoint
-		cp.K8sAuthRoleName = config.K8sAuth.K8sAuthRoleName
-		cp.K8sAuthTokenPath = config.K8sAuth.TokenPath
+func (p *Plugin) genClientParams(method vaultclient.AuthMethod, config *Configuration) (*vaultclient.ClientParams, error) {
+	cp, err := vaultclient.GenClientParams(method, config.vaultConfig(), p.hooks.lookupEnv)
+	if err != nil {
+		return nil, err
 	}
-
+	cp.PKIMountPoint = config.PKIMountPoint
 	return cp, nil
 }
-
-func (p *Plugin) getEnvOrDefault(envKey, fallback string) string {
-	if value, ok := p.hooks.lookupEnv(envKey); ok {
-		return value
-	}
-	return fallback
-}
-
-func parseAuthMethod(config *Configuration) (AuthMethod, error) {
-	var authMethod AuthMethod
-	if config.TokenAuth != nil {
-		authMethod = TOKEN
-	}
-	if config.CertAuth != nil {
-		if err := checkForAuthMethodConfigured(authMethod); err != nil {
-			return 0, err
-		}
-		authMethod = CERT
-	}
-	if config.AppRoleAuth != nil {
-		if err := checkForAuthMethodConfigured(authMethod); err != nil {
-			return 0, err
-		}
-		authMethod = APPROLE
-	}
-	if config.K8sAuth != nil {
-		if err := checkForAuthMethodConfigured(authMethod); err != nil {
-			return 0, err
-		}
-		authMethod = K8S
-	}
-
-	if authMethod != 0 {
-		return authMethod, nil
-	}
-
-	return 0, status.Error(codes.InvalidArgument, "must be configured one of these authentication method 'Token, Client Certificate, AppRole or Kubernetes")
-}
-
-func checkForAuthMethodConfigured(authMethod AuthMethod) error {
-	if authMethod != 0 {
-		return status.Error(codes.InvalidArgument, "only one authentication method can be configured")
-	}
-	return nil
-}
@@ -9,7 +9,6 @@ import (
 	"text/template"
 	"time"
 
-	"github.com/hashicorp/vault/sdk/helper/consts"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
@@ -17,6 +16,8 @@ import (
 	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"github.com/spiffe/spire/pkg/common/catalog"
 	"github.com/spiffe/spire/pkg/common/pemutil"
+	vaultclient "github.com/spiffe/spire/pkg/common/plugin/vault"
+	vaulttest "github.com/spiffe/spire/pkg/common/plugin/vault/test"
 	"github.com/spiffe/spire/pkg/server/plugin/upstreamauthority"
 	"github.com/spiffe/spire/proto/spire/common"
 	"github.com/spiffe/spire/test/plugintest"
@@ -27,10 +28,10 @@ import (
 func TestConfigure(t *testing.T) {
 	fakeVaultServer := setupFakeVaultServer()
 	fakeVaultServer.CertAuthResponseCode = 200
-	fakeVaultServer.CertAuthResponse = []byte(testCertAuthResponse)
+	fakeVaultServer.CertAuthResponse = []byte(vaulttest.CertAuthResponse)
 	fakeVaultServer.CertAuthReqEndpoint = "/v1/auth/test-cert-auth/login"
 	fakeVaultServer.AppRoleAuthResponseCode = 200
-	fakeVaultServer.AppRoleAuthResponse = []byte(testAppRoleAuthResponse)
+	fakeVaultServer.AppRoleAuthResponse = []byte(vaulttest.AppRoleAuthResponse)
 	fakeVaultServer.AppRoleAuthReqEndpoint = "/v1/auth/test-approle-auth/login"
 
 	s, addr, err := fakeVaultServer.NewTLSServer()
@@ -45,7 +46,7 @@ func TestConfigure(t *testing.T) {
 		plainConfig              string
 		expectMsgPrefix          string
 		expectCode               codes.Code
-		wantAuth                 AuthMethod
+		wantAuth                 vaultclient.AuthMethod
 		wantNamespaceIsNotNil    bool
 		envKeyVal                map[string]string
 		expectToken              string
@@ -62,42 +63,42 @@ func TestConfigure(t *testing.T) {
 		{
 			name:        "Configure plugin with Client Certificate authentication params given in config file",
 			configTmpl:  testTokenAuthConfigTpl,
-			wantAuth:    TOKEN,
+			wantAuth:    vaultclient.TOKEN,
 			expectToken: "test-token",
 		},
 		{
 			name:       "Configure plugin with Token authentication params given as environment variables",
 			configTmpl: testTokenAuthConfigWithEnvTpl,
 			envKeyVal: map[string]string{
-				envVaultToken: "test-token",
+				"VAULT_TOKEN": "test-token",
 			},
-			wantAuth:    TOKEN,
+			wantAuth:    vaultclient.TOKEN,
 			expectToken: "test-token",
 		},
 		{
 			name:                     "Configure plugin with Client Certificate authentication params given in config file",
 			configTmpl:               testCertAuthConfigTpl,
-			wantAuth:                 CERT,
+			wantAuth:                 vaultclient.CERT,
 			expectCertAuthMountPoint: "test-cert-auth",
-			expectClientCertPath:     "testdata/client-cert.pem",
-			expectClientKeyPath:      "testdata/client-key.pem",
+			expectClientCertPath:     vaulttest.ClientCert,
+			expectClientKeyPath:      vaulttest.ClientKey,
 		},
 		{
 			name:       "Configure plugin with Client Certificate authentication params given as environment variables",
 			configTmpl: testCertAuthConfigWithEnvTpl,
 			envKeyVal: map[string]string{
-				envVaultClientCert: "testdata/client-cert.pem",
-				envVaultClientKey:  testClientKey,
+				"VAULT_CLIENT_CERT": vaulttest.ClientCert,
+				"VAULT_CLIENT_KEY":  vaulttest.ClientKey,
 			},
-			wantAuth:                 CERT,
+			wantAuth:                 vaultclient.CERT,
 			expectCertAuthMountPoint: "test-cert-auth",
-			expectClientCertPath:     testClientCert,
-			expectClientKeyPath:      testClientKey,
+			expectClientCertPath:     vaulttest.ClientCert,
+			expectClientKeyPath:      vaulttest.ClientKey,
 		},
 		{
 			name:                  "Configure plugin with AppRole authenticate params given in config file",
 			configTmpl:            testAppRoleAuthConfigTpl,
-			wantAuth:              APPROLE,
+			wantAuth:              vaultclient.APPROLE,
 			appRoleAuthMountPoint: "test-approle-auth",
 			appRoleID:             "test-approle-id",
 			appRoleSecretID:       "test-approle-secret-id",
@@ -106,10 +107,10 @@ func TestConfigure(t *testing.T) {
 			name:       "Configure plugin with AppRole authentication params given as environment variables",
 			configTmpl: testAppRoleAuthConfigWithEnvTpl,
 			envKeyVal: map[string]string{
-				envVaultAppRoleID:       "test-approle-id",
-				envVaultAppRoleSecretID: "test-approle-secret-id",
+				"VAULT_APPROLE_ID":        "test-approle-id",
+				"VAULT_APPROLE_SECRET_ID": "test-approle-secret-id",
 			},
-			wantAuth:              APPROLE,
+			wantAuth:              vaultclient.APPROLE,
 			appRoleAuthMountPoint: "test-approle-auth",
 			appRoleID:             "test-approle-id",
 			appRoleSecretID:       "test-approle-secret-id",
@@ -117,9 +118,9 @@ func TestConfigure(t *testing.T) {
 		{
 			name:                    "Configure plugin with Kubernetes authentication params given in config file",
 			configTmpl:              testK8sAuthConfigTpl,
-			wantAuth:                K8S,
+			wantAuth:                vaultclient.K8S,
 			expectK8sAuthMountPoint: "test-k8s-auth",
-			expectK8sAuthTokenPath:  "testdata/k8s/token",
+			expectK8sAuthTokenPath:  vaulttest.K8sToken,
 			expectK8sAuthRoleName:   "my-role",
 		},
 		{
@@ -132,15 +133,15 @@ func TestConfigure(t *testing.T) {
 			name:       "Pass VaultAddr via the environment variable",
 			configTmpl: testConfigWithVaultAddrEnvTpl,
 			envKeyVal: map[string]string{
-				envVaultAddr: fmt.Sprintf("https://%v/", addr),
+				"VAULT_ADDR": fmt.Sprintf("https://%v/", addr),
 			},
-			wantAuth:    TOKEN,
+			wantAuth:    vaultclient.TOKEN,
 			expectToken: "test-token",
 		},
 		{
 			name:                  "Configure plugin with given namespace",
 			configTmpl:            testNamespaceConfigTpl,
-			wantAuth:              TOKEN,
+			wantAuth:              vaultclient.TOKEN,
 			wantNamespaceIsNotNil: true,
 			expectToken:           "test-token",
 		},
@@ -153,14 +154,14 @@ func TestConfigure(t *testing.T) {
 		{
 			name:            "Required parameters are not given / k8s_auth_role_name",
 			configTmpl:      testK8sAuthNoRoleNameTpl,
-			wantAuth:        K8S,
+			wantAuth:        vaultclient.K8S,
 			expectCode:      codes.InvalidArgument,
 			expectMsgPrefix: "k8s_auth_role_name is required",
 		},
 		{
 			name:            "Required parameters are not given / token_path",
 			configTmpl:      testK8sAuthNoTokenPathTpl,
-			wantAuth:        K8S,
+			wantAuth:        vaultclient.K8S,
 			expectCode:      codes.InvalidArgument,
 			expectMsgPrefix: "token_path is required",
 		},
@@ -198,39 +199,39 @@ func TestConfigure(t *testing.T) {
 			}
 
 			require.NotNil(t, p.cc)
-			require.NotNil(t, p.cc.clientParams)
+			require.NotNil(t, p.cc.ClientParams())
 
 			switch tt.wantAuth {
-			case TOKEN:
-				require.Equal(t, tt.expectToken, p.cc.clientParams.Token)
-			case CERT:
-				require.Equal(t, tt.expectCertAuthMountPoint, p.cc.clientParams.CertAuthMountPoint)
-				require.Equal(t, tt.expectClientCertPath, p.cc.clientParams.ClientCertPath)
-				require.Equal(t, tt.expectClientKeyPath, p.cc.clientParams.ClientKeyPath)
-			case APPROLE:
-				require.NotNil(t, p.cc.clientParams.AppRoleAuthMountPoint)
-				require.NotNil(t, p.cc.clientParams.AppRoleID)
-				require.NotNil(t, p.cc.clientParams.AppRoleSecretID)
-			case K8S:
-				require.Equal(t, tt.expectK8sAuthMountPoint, p.cc.clientParams.K8sAuthMountPoint)
-				require.Equal(t, tt.expectK8sAuthRoleName, p.cc.clientParams.K8sAuthRoleName)
-				require.Equal(t, tt.expectK8sAuthTokenPath, p.cc.clientParams.K8sAuthTokenPath)
+			case vaultclient.TOKEN:
+				require.Equal(t, tt.expectToken, p.cc.ClientParams().Token)
+			case vaultclient.CERT:
+				require.Equal(t, tt.expectCertAuthMountPoint, p.cc.ClientParams().CertAuthMountPoint)
+				require.Equal(t, tt.expectClientCertPath, p.cc.ClientParams().ClientCertPath)
+				require.Equal(t, tt.expectClientKeyPath, p.cc.ClientParams().ClientKeyPath)
+			case vaultclient.APPROLE:
+				require.NotNil(t, p.cc.ClientParams().AppRoleAuthMountPoint)
+				require.NotNil(t, p.cc.ClientParams().AppRoleID)
+				require.NotNil(t, p.cc.ClientParams().AppRoleSecretID)
+			case vaultclient.K8S:
+				require.Equal(t, tt.expectK8sAuthMountPoint, p.cc.ClientParams().K8sAuthMountPoint)
+				require.Equal(t, tt.expectK8sAuthRoleName, p.cc.ClientParams().K8sAuthRoleName)
+				require.Equal(t, tt.expectK8sAuthTokenPath, p.cc.ClientParams().K8sAuthTokenPath)
 			}
 
 			if tt.wantNamespaceIsNotNil {
-				require.NotNil(t, p.cc.clientParams.Namespace)
+				require.NotNil(t, p.cc.ClientParams().Namespace)
 			}
 		})
 	}
 }
 
 func TestMintX509CA(t *testing.T) {
-	csr, err := pemutil.LoadCertificateRequest(testReqCSR)
+	csr, err := pemutil.LoadCertificateRequest(vaulttest.IntermediateCSR)
 	require.NoError(t, err)
 	successfulConfig := &Configuration{
 		PKIMountPoint: "test-pki",
-		CACertPath:    "testdata/root-cert.pem",
-		TokenAuth: &TokenAuthConfig{
+		CACertPath:    vaulttest.RootCert,
+		TokenAuth: &vaultclient.TokenAuthConfig{
 			Token: "test-token",
 		},
 	}
@@ -240,33 +241,33 @@ func TestMintX509CA(t *testing.T) {
 		csr                     []byte
 		config                  *Configuration
 		ttl                     time.Duration
-		authMethod              AuthMethod
+		authMethod              vaultclient.AuthMethod
 		expectCode              codes.Code
 		expectMsgPrefix         string
 		expectX509CA            []string
 		expectedX509Authorities []string
 
-		fakeServer func() *FakeVaultServerConfig
+		fakeServer func() *vaulttest.FakeServerConfig
 	}{
 		{
 			name: "Mint X509CA SVID with Token authentication",
 			csr:  csr.Raw,
 			config: &Configuration{
 				PKIMountPoint: "test-pki",
-				CACertPath:    "testdata/root-cert.pem",
-				TokenAuth: &TokenAuthConfig{
+				CACertPath:    vaulttest.RootCert,
+				TokenAuth: &vaultclient.TokenAuthConfig{
 					Token: "test-token",
 				},
 			},
-			authMethod:              TOKEN,
+			authMethod:              vaultclient.TOKEN,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
-				fakeServer.LookupSelfResponse = []byte(testLookupSelfResponse)
+				fakeServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponse)
 				fakeServer.CertAuthResponse = []byte{}
 				fakeServer.AppRoleAuthResponse = []byte{}
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -277,20 +278,20 @@ func TestMintX509CA(t *testing.T) {
 			ttl:  time.Minute,
 			config: &Configuration{
 				PKIMountPoint: "test-pki",
-				CACertPath:    "testdata/root-cert.pem",
-				TokenAuth: &TokenAuthConfig{
+				CACertPath:    vaulttest.RootCert,
+				TokenAuth: &vaultclient.TokenAuthConfig{
 					Token: "test-token",
 				},
 			},
-			authMethod:              TOKEN,
+			authMethod:              vaultclient.TOKEN,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
-				fakeServer.LookupSelfResponse = []byte(testLookupSelfResponse)
+				fakeServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponse)
 				fakeServer.CertAuthResponse = []byte{}
 				fakeServer.AppRoleAuthResponse = []byte{}
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -300,20 +301,20 @@ func TestMintX509CA(t *testing.T) {
 			csr:  csr.Raw,
 			config: &Configuration{
 				PKIMountPoint: "test-pki",
-				CACertPath:    "testdata/root-cert.pem",
-				TokenAuth: &TokenAuthConfig{
+				CACertPath:    vaulttest.RootCert,
+				TokenAuth: &vaultclient.TokenAuthConfig{
 					Token: "test-token",
 				},
 			},
-			authMethod:              TOKEN,
+			authMethod:              vaultclient.TOKEN,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
-				fakeServer.LookupSelfResponse = []byte(testLookupSelfResponseNotRenewable)
+				fakeServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponseNotRenewable)
 				fakeServer.CertAuthResponse = []byte{}
 				fakeServer.AppRoleAuthResponse = []byte{}
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -323,20 +324,20 @@ func TestMintX509CA(t *testing.T) {
 			csr:  csr.Raw,
 			config: &Configuration{
 				PKIMountPoint: "test-pki",
-				CACertPath:    "testdata/root-cert.pem",
-				TokenAuth: &TokenAuthConfig{
+				CACertPath:    vaulttest.RootCert,
+				TokenAuth: &vaultclient.TokenAuthConfig{
 					Token: "test-token",
 				},
 			},
-			authMethod:              TOKEN,
+			authMethod:              vaultclient.TOKEN,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
-				fakeServer.LookupSelfResponse = []byte(testLookupSelfResponseNeverExpire)
+				fakeServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponseNeverExpire)
 				fakeServer.CertAuthResponse = []byte{}
 				fakeServer.AppRoleAuthResponse = []byte{}
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -345,24 +346,24 @@ func TestMintX509CA(t *testing.T) {
 			name: "Mint X509CA SVID with TLS cert authentication",
 			csr:  csr.Raw,
 			config: &Configuration{
-				CACertPath:    "testdata/root-cert.pem",
+				CACertPath:    vaulttest.RootCert,
 				PKIMountPoint: "test-pki",
-				CertAuth: &CertAuthConfig{
+				CertAuth: &vaultclient.CertAuthConfig{
 					CertAuthMountPoint: "test-cert-auth",
 					CertAuthRoleName:   "test",
-					ClientCertPath:     testClientCert,
-					ClientKeyPath:      testClientKey,
+					ClientCertPath:     vaulttest.ClientCert,
+					ClientKeyPath:      vaulttest.ClientKey,
 				},
 			},
-			authMethod:              CERT,
+			authMethod:              vaultclient.CERT,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				fakeServer.LookupSelfResponse = []byte{}
-				fakeServer.CertAuthResponse = []byte(testCertAuthResponse)
+				fakeServer.CertAuthResponse = []byte(vaulttest.CertAuthResponse)
 				fakeServer.AppRoleAuthResponse = []byte{}
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -371,23 +372,23 @@ func TestMintX509CA(t *testing.T) {
 			name: "Mint X509CA SVID with AppRole authentication",
 			csr:  csr.Raw,
 			config: &Configuration{
-				CACertPath:    "testdata/root-cert.pem",
+				CACertPath:    vaulttest.RootCert,
 				PKIMountPoint: "test-pki",
-				AppRoleAuth: &AppRoleAuthConfig{
+				AppRoleAuth: &vaultclient.AppRoleAuthConfig{
 					AppRoleMountPoint: "test-approle-auth",
 					RoleID:            "test-approle-id",
 					SecretID:          "test-approle-secret-id",
 				},
 			},
-			authMethod:              APPROLE,
+			authMethod:              vaultclient.APPROLE,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				fakeServer.LookupSelfResponse = []byte{}
 				fakeServer.CertAuthResponse = []byte{}
-				fakeServer.AppRoleAuthResponse = []byte(testAppRoleAuthResponse)
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.AppRoleAuthResponse = []byte(vaulttest.AppRoleAuthResponse)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -396,22 +397,22 @@ func TestMintX509CA(t *testing.T) {
 			name: "Mint X509CA SVID with Kubernetes authentication",
 			csr:  csr.Raw,
 			config: &Configuration{
-				CACertPath:    "testdata/root-cert.pem",
+				CACertPath:    vaulttest.RootCert,
 				PKIMountPoint: "test-pki",
-				K8sAuth: &K8sAuthConfig{
+				K8sAuth: &vaultclient.K8sAuthConfig{
 					K8sAuthMountPoint: "test-k8s-auth",
 					K8sAuthRoleName:   "my-role",
-					TokenPath:         "testdata/k8s/token",
+					TokenPath:         vaulttest.K8sToken,
 				},
 			},
-			authMethod:              K8S,
+			authMethod:              vaultclient.K8S,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				fakeServer.LookupSelfResponse = []byte{}
-				fakeServer.K8sAuthResponse = []byte(testK8sAuthResponse)
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.K8sAuthResponse = []byte(vaulttest.K8sAuthResponse)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -420,24 +421,24 @@ func TestMintX509CA(t *testing.T) {
 			name: "Mint X509CA SVID with TLS cert authentication / Token is not renewable",
 			csr:  csr.Raw,
 			config: &Configuration{
-				CACertPath:    "testdata/root-cert.pem",
+				CACertPath:    vaulttest.RootCert,
 				PKIMountPoint: "test-pki",
-				CertAuth: &CertAuthConfig{
+				CertAuth: &vaultclient.CertAuthConfig{
 					CertAuthMountPoint: "test-cert-auth",
 					CertAuthRoleName:   "test",
-					ClientCertPath:     testClientCert,
-					ClientKeyPath:      testClientKey,
+					ClientCertPath:     vaulttest.ClientCert,
+					ClientKeyPath:      vaulttest.ClientKey,
 				},
 			},
-			authMethod:              CERT,
+			authMethod:              vaultclient.CERT,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				fakeServer.LookupSelfResponse = []byte{}
-				fakeServer.CertAuthResponse = []byte(testCertAuthResponseNotRenewable)
+				fakeServer.CertAuthResponse = []byte(vaulttest.CertAuthResponseNotRenewable)
 				fakeServer.AppRoleAuthResponse = []byte{}
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -446,23 +447,23 @@ func TestMintX509CA(t *testing.T) {
 			name: "Mint X509CA SVID with AppRole authentication / Token is not renewable",
 			csr:  csr.Raw,
 			config: &Configuration{
-				CACertPath:    "testdata/root-cert.pem",
+				CACertPath:    vaulttest.RootCert,
 				PKIMountPoint: "test-pki",
-				AppRoleAuth: &AppRoleAuthConfig{
+				AppRoleAuth: &vaultclient.AppRoleAuthConfig{
 					AppRoleMountPoint: "test-approle-auth",
 					RoleID:            "test-approle-id",
 					SecretID:          "test-approle-secret-id",
 				},
 			},
-			authMethod:              APPROLE,
+			authMethod:              vaultclient.APPROLE,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				fakeServer.LookupSelfResponse = []byte{}
 				fakeServer.CertAuthResponse = []byte{}
-				fakeServer.AppRoleAuthResponse = []byte(testAppRoleAuthResponseNotRenewable)
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.AppRoleAuthResponse = []byte(vaulttest.AppRoleAuthResponseNotRenewable)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -471,22 +472,22 @@ func TestMintX509CA(t *testing.T) {
 			name: "Mint X509CA SVID with Kubernetes authentication / Token is not renewable",
 			csr:  csr.Raw,
 			config: &Configuration{
-				CACertPath:    "testdata/root-cert.pem",
+				CACertPath:    vaulttest.RootCert,
 				PKIMountPoint: "test-pki",
-				K8sAuth: &K8sAuthConfig{
+				K8sAuth: &vaultclient.K8sAuthConfig{
 					K8sAuthMountPoint: "test-k8s-auth",
 					K8sAuthRoleName:   "my-role",
-					TokenPath:         "testdata/k8s/token",
+					TokenPath:         vaulttest.K8sToken,
 				},
 			},
-			authMethod:              K8S,
+			authMethod:              vaultclient.K8S,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				fakeServer.LookupSelfResponse = []byte{}
-				fakeServer.K8sAuthResponse = []byte(testK8sAuthResponseNotRenewable)
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.K8sAuthResponse = []byte(vaulttest.K8sAuthResponseNotRenewable)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -497,20 +498,20 @@ func TestMintX509CA(t *testing.T) {
 			config: &Configuration{
 				Namespace:     "test-ns",
 				PKIMountPoint: "test-pki",
-				CACertPath:    "testdata/root-cert.pem",
-				TokenAuth: &TokenAuthConfig{
+				CACertPath:    vaulttest.RootCert,
+				TokenAuth: &vaultclient.TokenAuthConfig{
 					Token: "test-token",
 				},
 			},
-			authMethod:              TOKEN,
+			authMethod:              vaultclient.TOKEN,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
-				fakeServer.LookupSelfResponse = []byte(testLookupSelfResponse)
+				fakeServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponse)
 				fakeServer.CertAuthResponse = []byte{}
 				fakeServer.AppRoleAuthResponse = []byte{}
-				fakeServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+				fakeServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 
 				return fakeServer
 			},
@@ -520,17 +521,17 @@ func TestMintX509CA(t *testing.T) {
 			csr:  csr.Raw,
 			config: &Configuration{
 				PKIMountPoint: "test-pki",
-				CACertPath:    "testdata/root-cert.pem",
-				TokenAuth: &TokenAuthConfig{
+				CACertPath:    vaulttest.RootCert,
+				TokenAuth: &vaultclient.TokenAuthConfig{
 					Token: "test-token",
 				},
 			},
-			authMethod:              TOKEN,
+			authMethod:              vaultclient.TOKEN,
 			expectX509CA:            []string{"spiffe://intermediate-spire"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
-				fakeServer.LookupSelfResponse = []byte(testLookupSelfResponse)
+				fakeServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponse)
 				fakeServer.CertAuthResponse = []byte{}
 				fakeServer.AppRoleAuthResponse = []byte{}
 				fakeServer.SignIntermediateResponse = []byte(testLegacySignIntermediateResponseNoChain)
@@ -543,17 +544,17 @@ func TestMintX509CA(t *testing.T) {
 			csr:  csr.Raw,
 			config: &Configuration{
 				PKIMountPoint: "test-pki",
-				CACertPath:    "testdata/root-cert.pem",
-				TokenAuth: &TokenAuthConfig{
+				CACertPath:    vaulttest.RootCert,
+				TokenAuth: &vaultclient.TokenAuthConfig{
 					Token: "test-token",
 				},
 			},
-			authMethod:              TOKEN,
+			authMethod:              vaultclient.TOKEN,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
-			fakeServer: func() *FakeVaultServerConfig {
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
-				fakeServer.LookupSelfResponse = []byte(testLookupSelfResponse)
+				fakeServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponse)
 				fakeServer.CertAuthResponse = []byte{}
 				fakeServer.AppRoleAuthResponse = []byte{}
 				fakeServer.SignIntermediateResponse = []byte(testLegacySignIntermediateResponse)
@@ -564,7 +565,7 @@ func TestMintX509CA(t *testing.T) {
 		{
 			name:                    "Plugin is not configured",
 			csr:                     csr.Raw,
-			authMethod:              TOKEN,
+			authMethod:              vaultclient.TOKEN,
 			expectX509CA:            []string{"spiffe://intermediate-spire", "spiffe://intermediate-vault"},
 			expectedX509Authorities: []string{"spiffe://root"},
 			fakeServer:              setupSuccessFakeVaultServer,
@@ -575,8 +576,8 @@ func TestMintX509CA(t *testing.T) {
 			name:       "Authenticate client fails",
 			csr:        csr.Raw,
 			config:     successfulConfig,
-			authMethod: TOKEN,
-			fakeServer: func() *FakeVaultServerConfig {
+			authMethod: vaultclient.TOKEN,
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				// Expect error
 				fakeServer.LookupSelfResponse = []byte("fake-error")
@@ -592,8 +593,8 @@ func TestMintX509CA(t *testing.T) {
 			name:       "Signin fails",
 			csr:        csr.Raw,
 			config:     successfulConfig,
-			authMethod: TOKEN,
-			fakeServer: func() *FakeVaultServerConfig {
+			authMethod: vaultclient.TOKEN,
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				// Expect error
 				fakeServer.SignIntermediateReqEndpoint = "/v1/test-pki/root/sign-intermediate"
@@ -609,8 +610,8 @@ func TestMintX509CA(t *testing.T) {
 			name:       "Invalid signing response",
 			csr:        csr.Raw,
 			config:     successfulConfig,
-			authMethod: TOKEN,
-			fakeServer: func() *FakeVaultServerConfig {
+			authMethod: vaultclient.TOKEN,
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				// Expect error
 				fakeServer.SignIntermediateReqEndpoint = "/v1/test-pki/root/sign-intermediate"
@@ -626,8 +627,8 @@ func TestMintX509CA(t *testing.T) {
 			name:       "Signing response malformed certificate",
 			csr:        csr.Raw,
 			config:     successfulConfig,
-			authMethod: TOKEN,
-			fakeServer: func() *FakeVaultServerConfig {
+			authMethod: vaultclient.TOKEN,
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				// Expect error
 				fakeServer.SignIntermediateReqEndpoint = "/v1/test-pki/root/sign-intermediate"
@@ -643,8 +644,8 @@ func TestMintX509CA(t *testing.T) {
 			name:       "Signing response malformed certificate",
 			csr:        csr.Raw,
 			config:     successfulConfig,
-			authMethod: TOKEN,
-			fakeServer: func() *FakeVaultServerConfig {
+			authMethod: vaultclient.TOKEN,
+			fakeServer: func() *vaulttest.FakeServerConfig {
 				fakeServer := setupSuccessFakeVaultServer()
 				// Expect error
 				fakeServer.SignIntermediateReqEndpoint = "/v1/test-pki/root/sign-intermediate"
@@ -660,7 +661,7 @@ func TestMintX509CA(t *testing.T) {
 			name:            "Invalid CSR",
 			csr:             []byte("malformed-csr"),
 			config:          successfulConfig,
-			authMethod:      TOKEN,
+			authMethod:      vaultclient.TOKEN,
 			fakeServer:      setupSuccessFakeVaultServer,
 			expectCode:      codes.InvalidArgument,
 			expectMsgPrefix: "upstreamauthority(vault): failed to parse CSR data:",
@@ -685,7 +686,7 @@ func TestMintX509CA(t *testing.T) {
 				tt.config.VaultAddr = fmt.Sprintf("https://%s", addr)
 				cp, err := p.genClientParams(tt.authMethod, tt.config)
 				require.NoError(t, err)
-				cc, err := NewClientConfig(cp, p.logger)
+				cc, err := vaultclient.NewClientConfig(cp, p.logger)
 				require.NoError(t, err)
 				p.cc = cc
 				options = append(options, plugintest.ConfigureJSON(tt.config))
@@ -716,17 +717,14 @@ func TestMintX509CA(t *testing.T) {
 			x509AuthoritiesIDs := certChainURIs(x509Authorities)
 			require.Equal(t, tt.expectedX509Authorities, x509AuthoritiesIDs)
 
-			if p.cc.clientParams.Namespace != "" {
-				headers := p.vc.vaultClient.Headers()
-				require.Equal(t, p.cc.clientParams.Namespace, headers.Get(consts.NamespaceHeaderName))
-			}
+			require.Equal(t, tt.config.Namespace, p.cc.ClientParams().Namespace)
 		})
 	}
 }
 
 func TestMintX509CA_InvalidCSR(t *testing.T) {
 	fakeVaultServer := setupFakeVaultServer()
-	fakeVaultServer.LookupSelfResponse = []byte(testLookupSelfResponse)
+	fakeVaultServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponse)
 	fakeVaultServer.LookupSelfResponseCode = 200
 
 	s, addr, err := fakeVaultServer.NewTLSServer()
@@ -741,9 +739,9 @@ func TestMintX509CA_InvalidCSR(t *testing.T) {
 	plugintest.Load(t, builtin(p), v1,
 		plugintest.ConfigureJSON(&Configuration{
 			VaultAddr:     fmt.Sprintf("https://%v/", addr),
-			CACertPath:    testRootCert,
+			CACertPath:    vaulttest.RootCert,
 			PKIMountPoint: "test-pki",
-			TokenAuth: &TokenAuthConfig{
+			TokenAuth: &vaultclient.TokenAuthConfig{
 				Token: "test-token",
 			},
 		}),
@@ -761,7 +759,7 @@ func TestMintX509CA_InvalidCSR(t *testing.T) {
 
 func TestPublishJWTKey(t *testing.T) {
 	fakeVaultServer := setupFakeVaultServer()
-	fakeVaultServer.LookupSelfResponse = []byte(testLookupSelfResponse)
+	fakeVaultServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponse)
 
 	s, addr, err := fakeVaultServer.NewTLSServer()
 	require.NoError(t, err)
@@ -773,9 +771,9 @@ func TestPublishJWTKey(t *testing.T) {
 	plugintest.Load(t, BuiltIn(), ua,
 		plugintest.ConfigureJSON(Configuration{
 			VaultAddr:     fmt.Sprintf("https://%v/", addr),
-			CACertPath:    testRootCert,
+			CACertPath:    vaulttest.RootCert,
 			PKIMountPoint: "test-pki",
-			TokenAuth: &TokenAuthConfig{
+			TokenAuth: &vaultclient.TokenAuthConfig{
 				Token: "test-token",
 			},
 		}),
@@ -796,7 +794,19 @@ func getTestConfigureRequest(t *testing.T, addr string, tpl string) string {
 	templ, err := template.New("plugin config").Parse(tpl)
 	require.NoError(t, err)
 
-	cp := &struct{ Addr string }{Addr: addr}
+	cp := &struct {
+		Addr       string
+		RootCert   string
+		ClientCert string
+		ClientKey  string
+		K8sToken   string
+	}{
+		Addr:       addr,
+		RootCert:   vaulttest.RootCert,
+		ClientCert: vaulttest.ClientCert,
+		ClientKey:  vaulttest.ClientKey,
+		K8sToken:   vaulttest.K8sToken,
+	}
 
 	var c bytes.Buffer
 	err = templ.Execute(&c, cp)
@@ -805,30 +815,30 @@ func getTestConfigureRequest(t *testing.T, addr string, tpl string) string {
 	return c.String()
 }
 
-func setupFakeVaultServer() *FakeVaultServerConfig {
-	fakeVaultServer := NewFakeVaultServerConfig()
-	fakeVaultServer.ServerCertificatePemPath = testServerCert
-	fakeVaultServer.ServerKeyPemPath = testServerKey
+func setupFakeVaultServer() *vaulttest.FakeServerConfig {
+	fakeVaultServer := vaulttest.NewFakeServerConfig()
+	fakeVaultServer.ServerCertificatePemPath = vaulttest.ServerCert
+	fakeVaultServer.ServerKeyPemPath = vaulttest.ServerKey
 	fakeVaultServer.RenewResponseCode = 200
-	fakeVaultServer.RenewResponse = []byte(testRenewResponse)
+	fakeVaultServer.RenewResponse = []byte(vaulttest.RenewResponse)
 	return fakeVaultServer
 }
 
-func setupSuccessFakeVaultServer() *FakeVaultServerConfig {
+func setupSuccessFakeVaultServer() *vaulttest.FakeServerConfig {
 	fakeVaultServer := setupFakeVaultServer()
 	fakeVaultServer.CertAuthResponseCode = 200
-	fakeVaultServer.CertAuthResponse = []byte(testCertAuthResponse)
+	fakeVaultServer.CertAuthResponse = []byte(vaulttest.CertAuthResponse)
 	fakeVaultServer.CertAuthReqEndpoint = "/v1/auth/test-cert-auth/login"
 	fakeVaultServer.AppRoleAuthResponseCode = 200
-	fakeVaultServer.AppRoleAuthResponse = []byte(testAppRoleAuthResponse)
+	fakeVaultServer.AppRoleAuthResponse = []byte(vaulttest.AppRoleAuthResponse)
 	fakeVaultServer.AppRoleAuthReqEndpoint = "/v1/auth/test-approle-auth/login"
 	fakeVaultServer.K8sAuthResponseCode = 200
 	fakeVaultServer.K8sAuthReqEndpoint = "/v1/auth/test-k8s-auth/login"
-	fakeVaultServer.K8sAuthResponse = []byte(testK8sAuthResponse)
-	fakeVaultServer.LookupSelfResponse = []byte(testLookupSelfResponse)
+	fakeVaultServer.K8sAuthResponse = []byte(vaulttest.K8sAuthResponse)
+	fakeVaultServer.LookupSelfResponse = []byte(vaulttest.LookupSelfResponse)
 	fakeVaultServer.LookupSelfResponseCode = 200
 	fakeVaultServer.SignIntermediateResponseCode = 200
-	fakeVaultServer.SignIntermediateResponse = []byte(testSignIntermediateResponse)
+	fakeVaultServer.SignIntermediateResponse = []byte(vaulttest.SignIntermediateResponse)
 	fakeVaultServer.SignIntermediateReqEndpoint = "/v1/test-pki/root/sign-intermediate"
 
 	return fakeVaultServer
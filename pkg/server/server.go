@@ -377,25 +377,28 @@ func (s *Server) newSVIDRotator(ctx context.Context, serverCA ca.ServerCA, metri
 	return svidRotator, nil
 }
 
-func (s *Server) newEndpointsServer(ctx context.Context, catalog catalog.Catalog, svidObserver svid.Observer, serverCA ca.ServerCA, metrics telemetry.Metrics, jwtKeyPublisher manager.JwtKeyPublisher, authPolicyEngine *authpolicy.Engine, bundleManager *bundle_client.Manager) (endpoints.Server, error) {
+func (s *Server) newEndpointsServer(ctx context.Context, catalog catalog.Catalog, svidObserver svid.Observer, serverCA ca.ServerCA, metrics telemetry.Metrics, caManager *manager.Manager, authPolicyEngine *authpolicy.Engine, bundleManager *bundle_client.Manager) (endpoints.Server, error) {
 	config := endpoints.Config{
-		TCPAddr:             s.config.BindAddress,
-		LocalAddr:           s.config.BindLocalAddress,
-		SVIDObserver:        svidObserver,
-		TrustDomain:         s.config.TrustDomain,
-		Catalog:             catalog,
-		ServerCA:            serverCA,
-		Log:                 s.config.Log.WithField(telemetry.SubsystemName, telemetry.Endpoints),
-		Metrics:             metrics,
-		JWTKeyPublisher:     jwtKeyPublisher,
-		RateLimit:           s.config.RateLimit,
-		Uptime:              uptime.Uptime,
-		Clock:               clock.New(),
-		CacheReloadInterval: s.config.CacheReloadInterval,
-		AuditLogEnabled:     s.config.AuditLogEnabled,
-		AuthPolicyEngine:    authPolicyEngine,
-		BundleManager:       bundleManager,
-		AdminIDs:            s.config.AdminIDs,
+		TCPAddr:              s.config.BindAddress,
+		LocalAddr:            s.config.BindLocalAddress,
+		SVIDObserver:         svidObserver,
+		TrustDomain:          s.config.TrustDomain,
+		Catalog:              catalog,
+		ServerCA:             serverCA,
+		Log:                  s.config.Log.WithField(telemetry.SubsystemName, telemetry.Endpoints),
+		Metrics:              metrics,
+		JWTKeyPublisher:      caManager,
+		AuthorityManager:     caManager,
+		RateLimit:            s.config.RateLimit,
+		Uptime:               uptime.Uptime,
+		Clock:                clock.New(),
+		CacheReloadInterval:  s.config.CacheReloadInterval,
+		EventsBasedCache:     s.config.EventsBasedCache,
+		PruneEventsOlderThan: s.config.PruneEventsOlderThan,
+		AuditLogEnabled:      s.config.AuditLogEnabled,
+		AuthPolicyEngine:     authPolicyEngine,
+		BundleManager:        bundleManager,
+		AdminIDs:             s.config.AdminIDs,
 	}
 	if s.config.Federation.BundleEndpoint != nil {
 		config.BundleEndpoint.Address = s.config.Federation.BundleEndpoint.Address
@@ -0,0 +1,438 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.20.1
+// source: spire/api/agent/delegatedidentity/v1/delegatedidentityext.proto
+
+package delegatedidentityv1
+
+import (
+	v1 "github.com/spiffe/spire-api-sdk/proto/spire/api/agent/delegatedidentity/v1"
+	types "github.com/spiffe/spire-api-sdk/proto/spire/api/types"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SubscribeToJWTSVIDsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Required. The audience(s) the workload intends to authenticate against.
+	Audience []string `protobuf:"bytes,1,rep,name=audience,proto3" json:"audience,omitempty"`
+	// Selectors describing the workload to subscribe to. Required unless
+	// pid is set.
+	Selectors []*types.Selector `protobuf:"bytes,2,rep,name=selectors,proto3" json:"selectors,omitempty"`
+	// PID of the workload process to subscribe to. Required unless
+	// selectors are set.
+	Pid int32 `protobuf:"varint,3,opt,name=pid,proto3" json:"pid,omitempty"`
+}
+
+func (x *SubscribeToJWTSVIDsRequest) Reset() {
+	*x = SubscribeToJWTSVIDsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeToJWTSVIDsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToJWTSVIDsRequest) ProtoMessage() {}
+
+func (x *SubscribeToJWTSVIDsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToJWTSVIDsRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeToJWTSVIDsRequest) Descriptor() ([]byte, []int) {
+	return file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubscribeToJWTSVIDsRequest) GetAudience() []string {
+	if x != nil {
+		return x.Audience
+	}
+	return nil
+}
+
+func (x *SubscribeToJWTSVIDsRequest) GetSelectors() []*types.Selector {
+	if x != nil {
+		return x.Selectors
+	}
+	return nil
+}
+
+func (x *SubscribeToJWTSVIDsRequest) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+// The SubscribeToJWTSVIDsResponse message conveys JWT-SVIDs.
+type SubscribeToJWTSVIDsResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Required. The list of returned JWT-SVIDs.
+	Svids []*types.JWTSVID `protobuf:"bytes,1,rep,name=svids,proto3" json:"svids,omitempty"`
+}
+
+func (x *SubscribeToJWTSVIDsResponse) Reset() {
+	*x = SubscribeToJWTSVIDsResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeToJWTSVIDsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToJWTSVIDsResponse) ProtoMessage() {}
+
+func (x *SubscribeToJWTSVIDsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToJWTSVIDsResponse.ProtoReflect.Descriptor instead.
+func (*SubscribeToJWTSVIDsResponse) Descriptor() ([]byte, []int) {
+	return file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubscribeToJWTSVIDsResponse) GetSvids() []*types.JWTSVID {
+	if x != nil {
+		return x.Svids
+	}
+	return nil
+}
+
+type SubscribeToX509SVIDsByPIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Required. PID of the workload process to subscribe to.
+	Pid int32 `protobuf:"varint,1,opt,name=pid,proto3" json:"pid,omitempty"`
+}
+
+func (x *SubscribeToX509SVIDsByPIDRequest) Reset() {
+	*x = SubscribeToX509SVIDsByPIDRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SubscribeToX509SVIDsByPIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeToX509SVIDsByPIDRequest) ProtoMessage() {}
+
+func (x *SubscribeToX509SVIDsByPIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeToX509SVIDsByPIDRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeToX509SVIDsByPIDRequest) Descriptor() ([]byte, []int) {
+	return file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubscribeToX509SVIDsByPIDRequest) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+type FetchJWTSVIDsByPIDRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Required. The audience(s) the workload intends to authenticate against.
+	Audience []string `protobuf:"bytes,1,rep,name=audience,proto3" json:"audience,omitempty"`
+	// Required. PID of the workload process to fetch.
+	Pid int32 `protobuf:"varint,2,opt,name=pid,proto3" json:"pid,omitempty"`
+}
+
+func (x *FetchJWTSVIDsByPIDRequest) Reset() {
+	*x = FetchJWTSVIDsByPIDRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetchJWTSVIDsByPIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetchJWTSVIDsByPIDRequest) ProtoMessage() {}
+
+func (x *FetchJWTSVIDsByPIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetchJWTSVIDsByPIDRequest.ProtoReflect.Descriptor instead.
+func (*FetchJWTSVIDsByPIDRequest) Descriptor() ([]byte, []int) {
+	return file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *FetchJWTSVIDsByPIDRequest) GetAudience() []string {
+	if x != nil {
+		return x.Audience
+	}
+	return nil
+}
+
+func (x *FetchJWTSVIDsByPIDRequest) GetPid() int32 {
+	if x != nil {
+		return x.Pid
+	}
+	return 0
+}
+
+var File_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto protoreflect.FileDescriptor
+
+var file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDesc = []byte{
+	0x0a, 0x3f, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64,
+	0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x65, 0x78, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x12, 0x24, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x61, 0x67, 0x65,
+	0x6e, 0x74, 0x2e, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x69, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x2e, 0x76, 0x31, 0x1a, 0x3c, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2f, 0x61,
+	0x70, 0x69, 0x2f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74,
+	0x65, 0x64, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2f, 0x76, 0x31, 0x2f, 0x64, 0x65,
+	0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1d, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2f, 0x61, 0x70, 0x69,
+	0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2f, 0x6a, 0x77, 0x74, 0x73, 0x76, 0x69, 0x64, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x83, 0x01, 0x0a, 0x1a, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x54, 0x6f, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x73, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x12,
+	0x37, 0x0a, 0x09, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x74,
+	0x79, 0x70, 0x65, 0x73, 0x2e, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x09, 0x73,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x22, 0x4d, 0x0a, 0x1b, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x73, 0x76, 0x69,
+	0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x73, 0x70, 0x69, 0x72, 0x65,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x4a, 0x57, 0x54, 0x53, 0x56,
+	0x49, 0x44, 0x52, 0x05, 0x73, 0x76, 0x69, 0x64, 0x73, 0x22, 0x34, 0x0a, 0x20, 0x53, 0x75, 0x62,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x58, 0x35, 0x30, 0x39, 0x53, 0x56, 0x49, 0x44,
+	0x73, 0x42, 0x79, 0x50, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x10, 0x0a,
+	0x03, 0x70, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x22,
+	0x49, 0x0a, 0x19, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x73,
+	0x42, 0x79, 0x50, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08,
+	0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08,
+	0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x70, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x70, 0x69, 0x64, 0x32, 0xfd, 0x03, 0x0a, 0x1b, 0x44,
+	0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x9c, 0x01, 0x0a, 0x13, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49,
+	0x44, 0x73, 0x12, 0x40, 0x2e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x69, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x54, 0x6f, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x41, 0x2e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2e, 0x61, 0x70, 0x69,
+	0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64,
+	0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x73, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0xa9, 0x01, 0x0a, 0x19, 0x53, 0x75,
+	0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x58, 0x35, 0x30, 0x39, 0x53, 0x56, 0x49,
+	0x44, 0x73, 0x42, 0x79, 0x50, 0x49, 0x44, 0x12, 0x46, 0x2e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61,
+	0x74, 0x65, 0x64, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x53,
+	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x54, 0x6f, 0x58, 0x35, 0x30, 0x39, 0x53, 0x56,
+	0x49, 0x44, 0x73, 0x42, 0x79, 0x50, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x42, 0x2e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x61, 0x67, 0x65, 0x6e,
+	0x74, 0x2e, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x69, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x54, 0x6f, 0x58, 0x35, 0x30, 0x39, 0x53, 0x56, 0x49, 0x44, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x30, 0x01, 0x12, 0x92, 0x01, 0x0a, 0x12, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4a,
+	0x57, 0x54, 0x53, 0x56, 0x49, 0x44, 0x73, 0x42, 0x79, 0x50, 0x49, 0x44, 0x12, 0x3f, 0x2e, 0x73,
+	0x70, 0x69, 0x72, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e, 0x64,
+	0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x2e, 0x76, 0x31, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49, 0x44,
+	0x73, 0x42, 0x79, 0x50, 0x49, 0x44, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x3b, 0x2e,
+	0x73, 0x70, 0x69, 0x72, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2e,
+	0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x4a, 0x57, 0x54, 0x53, 0x56, 0x49,
+	0x44, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x58, 0x5a, 0x56, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x70, 0x69, 0x66, 0x66, 0x65, 0x2f,
+	0x73, 0x70, 0x69, 0x72, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x70, 0x69, 0x72,
+	0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x2f, 0x64, 0x65, 0x6c, 0x65,
+	0x67, 0x61, 0x74, 0x65, 0x64, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2f, 0x76, 0x31,
+	0x3b, 0x64, 0x65, 0x6c, 0x65, 0x67, 0x61, 0x74, 0x65, 0x64, 0x69, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescOnce sync.Once
+	file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescData = file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDesc
+)
+
+func file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescGZIP() []byte {
+	file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescOnce.Do(func() {
+		file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescData = protoimpl.X.CompressGZIP(file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescData)
+	})
+	return file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDescData
+}
+
+var file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_goTypes = []interface{}{
+	(*SubscribeToJWTSVIDsRequest)(nil),       // 0: spire.api.agent.delegatedidentity.v1.SubscribeToJWTSVIDsRequest
+	(*SubscribeToJWTSVIDsResponse)(nil),      // 1: spire.api.agent.delegatedidentity.v1.SubscribeToJWTSVIDsResponse
+	(*SubscribeToX509SVIDsByPIDRequest)(nil), // 2: spire.api.agent.delegatedidentity.v1.SubscribeToX509SVIDsByPIDRequest
+	(*FetchJWTSVIDsByPIDRequest)(nil),        // 3: spire.api.agent.delegatedidentity.v1.FetchJWTSVIDsByPIDRequest
+	(*types.Selector)(nil),                   // 4: spire.api.types.Selector
+	(*types.JWTSVID)(nil),                    // 5: spire.api.types.JWTSVID
+	(*v1.SubscribeToX509SVIDsResponse)(nil),  // 6: spire.api.agent.delegatedidentity.v1.SubscribeToX509SVIDsResponse
+	(*v1.FetchJWTSVIDsResponse)(nil),         // 7: spire.api.agent.delegatedidentity.v1.FetchJWTSVIDsResponse
+}
+var file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_depIdxs = []int32{
+	4, // 0: spire.api.agent.delegatedidentity.v1.SubscribeToJWTSVIDsRequest.selectors:type_name -> spire.api.types.Selector
+	5, // 1: spire.api.agent.delegatedidentity.v1.SubscribeToJWTSVIDsResponse.svids:type_name -> spire.api.types.JWTSVID
+	0, // 2: spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions.SubscribeToJWTSVIDs:input_type -> spire.api.agent.delegatedidentity.v1.SubscribeToJWTSVIDsRequest
+	2, // 3: spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions.SubscribeToX509SVIDsByPID:input_type -> spire.api.agent.delegatedidentity.v1.SubscribeToX509SVIDsByPIDRequest
+	3, // 4: spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions.FetchJWTSVIDsByPID:input_type -> spire.api.agent.delegatedidentity.v1.FetchJWTSVIDsByPIDRequest
+	1, // 5: spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions.SubscribeToJWTSVIDs:output_type -> spire.api.agent.delegatedidentity.v1.SubscribeToJWTSVIDsResponse
+	6, // 6: spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions.SubscribeToX509SVIDsByPID:output_type -> spire.api.agent.delegatedidentity.v1.SubscribeToX509SVIDsResponse
+	7, // 7: spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions.FetchJWTSVIDsByPID:output_type -> spire.api.agent.delegatedidentity.v1.FetchJWTSVIDsResponse
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_init() }
+func file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_init() {
+	if File_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeToJWTSVIDsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeToJWTSVIDsResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubscribeToX509SVIDsByPIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetchJWTSVIDsByPIDRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_goTypes,
+		DependencyIndexes: file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_depIdxs,
+		MessageInfos:      file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_msgTypes,
+	}.Build()
+	File_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto = out.File
+	file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_rawDesc = nil
+	file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_goTypes = nil
+	file_spire_api_agent_delegatedidentity_v1_delegatedidentityext_proto_depIdxs = nil
+}
@@ -0,0 +1,252 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package delegatedidentityv1
+
+import (
+	context "context"
+	v1 "github.com/spiffe/spire-api-sdk/proto/spire/api/agent/delegatedidentity/v1"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DelegatedIdentityExtensionsClient is the client API for DelegatedIdentityExtensions service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DelegatedIdentityExtensionsClient interface {
+	// Subscribe to get JWT-SVIDs for workloads that match the given selectors,
+	// and for the requested audience. A new response with freshly minted
+	// JWT-SVIDs is sent ahead of the expiration of the previous ones, and
+	// whenever the identities of the workload change.
+	// The lifetime of the subscription aligns to the lifetime of the stream.
+	SubscribeToJWTSVIDs(ctx context.Context, in *SubscribeToJWTSVIDsRequest, opts ...grpc.CallOption) (DelegatedIdentityExtensions_SubscribeToJWTSVIDsClient, error)
+	// Subscribe to get X.509-SVIDs for the workload running in the process
+	// with the given PID. The subscription is closed if the selectors of the
+	// process change.
+	// The lifetime of the subscription aligns to the lifetime of the stream.
+	SubscribeToX509SVIDsByPID(ctx context.Context, in *SubscribeToX509SVIDsByPIDRequest, opts ...grpc.CallOption) (DelegatedIdentityExtensions_SubscribeToX509SVIDsByPIDClient, error)
+	// Fetch JWT-SVIDs for the workload running in the process with the given
+	// PID, and for the requested audience.
+	FetchJWTSVIDsByPID(ctx context.Context, in *FetchJWTSVIDsByPIDRequest, opts ...grpc.CallOption) (*v1.FetchJWTSVIDsResponse, error)
+}
+
+type delegatedIdentityExtensionsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDelegatedIdentityExtensionsClient(cc grpc.ClientConnInterface) DelegatedIdentityExtensionsClient {
+	return &delegatedIdentityExtensionsClient{cc}
+}
+
+func (c *delegatedIdentityExtensionsClient) SubscribeToJWTSVIDs(ctx context.Context, in *SubscribeToJWTSVIDsRequest, opts ...grpc.CallOption) (DelegatedIdentityExtensions_SubscribeToJWTSVIDsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DelegatedIdentityExtensions_ServiceDesc.Streams[0], "/spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions/SubscribeToJWTSVIDs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &delegatedIdentityExtensionsSubscribeToJWTSVIDsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DelegatedIdentityExtensions_SubscribeToJWTSVIDsClient interface {
+	Recv() (*SubscribeToJWTSVIDsResponse, error)
+	grpc.ClientStream
+}
+
+type delegatedIdentityExtensionsSubscribeToJWTSVIDsClient struct {
+	grpc.ClientStream
+}
+
+func (x *delegatedIdentityExtensionsSubscribeToJWTSVIDsClient) Recv() (*SubscribeToJWTSVIDsResponse, error) {
+	m := new(SubscribeToJWTSVIDsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *delegatedIdentityExtensionsClient) SubscribeToX509SVIDsByPID(ctx context.Context, in *SubscribeToX509SVIDsByPIDRequest, opts ...grpc.CallOption) (DelegatedIdentityExtensions_SubscribeToX509SVIDsByPIDClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DelegatedIdentityExtensions_ServiceDesc.Streams[1], "/spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions/SubscribeToX509SVIDsByPID", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &delegatedIdentityExtensionsSubscribeToX509SVIDsByPIDClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DelegatedIdentityExtensions_SubscribeToX509SVIDsByPIDClient interface {
+	Recv() (*v1.SubscribeToX509SVIDsResponse, error)
+	grpc.ClientStream
+}
+
+type delegatedIdentityExtensionsSubscribeToX509SVIDsByPIDClient struct {
+	grpc.ClientStream
+}
+
+func (x *delegatedIdentityExtensionsSubscribeToX509SVIDsByPIDClient) Recv() (*v1.SubscribeToX509SVIDsResponse, error) {
+	m := new(v1.SubscribeToX509SVIDsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *delegatedIdentityExtensionsClient) FetchJWTSVIDsByPID(ctx context.Context, in *FetchJWTSVIDsByPIDRequest, opts ...grpc.CallOption) (*v1.FetchJWTSVIDsResponse, error) {
+	out := new(v1.FetchJWTSVIDsResponse)
+	err := c.cc.Invoke(ctx, "/spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions/FetchJWTSVIDsByPID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DelegatedIdentityExtensionsServer is the server API for DelegatedIdentityExtensions service.
+// All implementations must embed UnimplementedDelegatedIdentityExtensionsServer
+// for forward compatibility
+type DelegatedIdentityExtensionsServer interface {
+	// Subscribe to get JWT-SVIDs for workloads that match the given selectors,
+	// and for the requested audience. A new response with freshly minted
+	// JWT-SVIDs is sent ahead of the expiration of the previous ones, and
+	// whenever the identities of the workload change.
+	// The lifetime of the subscription aligns to the lifetime of the stream.
+	SubscribeToJWTSVIDs(*SubscribeToJWTSVIDsRequest, DelegatedIdentityExtensions_SubscribeToJWTSVIDsServer) error
+	// Subscribe to get X.509-SVIDs for the workload running in the process
+	// with the given PID. The subscription is closed if the selectors of the
+	// process change.
+	// The lifetime of the subscription aligns to the lifetime of the stream.
+	SubscribeToX509SVIDsByPID(*SubscribeToX509SVIDsByPIDRequest, DelegatedIdentityExtensions_SubscribeToX509SVIDsByPIDServer) error
+	// Fetch JWT-SVIDs for the workload running in the process with the given
+	// PID, and for the requested audience.
+	FetchJWTSVIDsByPID(context.Context, *FetchJWTSVIDsByPIDRequest) (*v1.FetchJWTSVIDsResponse, error)
+	mustEmbedUnimplementedDelegatedIdentityExtensionsServer()
+}
+
+// UnimplementedDelegatedIdentityExtensionsServer must be embedded to have forward compatible implementations.
+type UnimplementedDelegatedIdentityExtensionsServer struct {
+}
+
+func (UnimplementedDelegatedIdentityExtensionsServer) SubscribeToJWTSVIDs(*SubscribeToJWTSVIDsRequest, DelegatedIdentityExtensions_SubscribeToJWTSVIDsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeToJWTSVIDs not implemented")
+}
+func (UnimplementedDelegatedIdentityExtensionsServer) SubscribeToX509SVIDsByPID(*SubscribeToX509SVIDsByPIDRequest, DelegatedIdentityExtensions_SubscribeToX509SVIDsByPIDServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeToX509SVIDsByPID not implemented")
+}
+func (UnimplementedDelegatedIdentityExtensionsServer) FetchJWTSVIDsByPID(context.Context, *FetchJWTSVIDsByPIDRequest) (*v1.FetchJWTSVIDsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FetchJWTSVIDsByPID not implemented")
+}
+func (UnimplementedDelegatedIdentityExtensionsServer) mustEmbedUnimplementedDelegatedIdentityExtensionsServer() {
+}
+
+// UnsafeDelegatedIdentityExtensionsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DelegatedIdentityExtensionsServer will
+// result in compilation errors.
+type UnsafeDelegatedIdentityExtensionsServer interface {
+	mustEmbedUnimplementedDelegatedIdentityExtensionsServer()
+}
+
+func RegisterDelegatedIdentityExtensionsServer(s grpc.ServiceRegistrar, srv DelegatedIdentityExtensionsServer) {
+	s.RegisterService(&DelegatedIdentityExtensions_ServiceDesc, srv)
+}
+
+func _DelegatedIdentityExtensions_SubscribeToJWTSVIDs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeToJWTSVIDsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DelegatedIdentityExtensionsServer).SubscribeToJWTSVIDs(m, &delegatedIdentityExtensionsSubscribeToJWTSVIDsServer{stream})
+}
+
+type DelegatedIdentityExtensions_SubscribeToJWTSVIDsServer interface {
+	Send(*SubscribeToJWTSVIDsResponse) error
+	grpc.ServerStream
+}
+
+type delegatedIdentityExtensionsSubscribeToJWTSVIDsServer struct {
+	grpc.ServerStream
+}
+
+func (x *delegatedIdentityExtensionsSubscribeToJWTSVIDsServer) Send(m *SubscribeToJWTSVIDsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DelegatedIdentityExtensions_SubscribeToX509SVIDsByPID_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeToX509SVIDsByPIDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DelegatedIdentityExtensionsServer).SubscribeToX509SVIDsByPID(m, &delegatedIdentityExtensionsSubscribeToX509SVIDsByPIDServer{stream})
+}
+
+type DelegatedIdentityExtensions_SubscribeToX509SVIDsByPIDServer interface {
+	Send(*v1.SubscribeToX509SVIDsResponse) error
+	grpc.ServerStream
+}
+
+type delegatedIdentityExtensionsSubscribeToX509SVIDsByPIDServer struct {
+	grpc.ServerStream
+}
+
+func (x *delegatedIdentityExtensionsSubscribeToX509SVIDsByPIDServer) Send(m *v1.SubscribeToX509SVIDsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DelegatedIdentityExtensions_FetchJWTSVIDsByPID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FetchJWTSVIDsByPIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DelegatedIdentityExtensionsServer).FetchJWTSVIDsByPID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions/FetchJWTSVIDsByPID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DelegatedIdentityExtensionsServer).FetchJWTSVIDsByPID(ctx, req.(*FetchJWTSVIDsByPIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DelegatedIdentityExtensions_ServiceDesc is the grpc.ServiceDesc for DelegatedIdentityExtensions service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DelegatedIdentityExtensions_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "spire.api.agent.delegatedidentity.v1.DelegatedIdentityExtensions",
+	HandlerType: (*DelegatedIdentityExtensionsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FetchJWTSVIDsByPID",
+			Handler:    _DelegatedIdentityExtensions_FetchJWTSVIDsByPID_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeToJWTSVIDs",
+			Handler:       _DelegatedIdentityExtensions_SubscribeToJWTSVIDs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeToX509SVIDsByPID",
+			Handler:       _DelegatedIdentityExtensions_SubscribeToX509SVIDsByPID_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "spire/api/agent/delegatedidentity/v1/delegatedidentityext.proto",
+}
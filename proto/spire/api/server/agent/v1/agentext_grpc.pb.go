@@ -0,0 +1,153 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package agentv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// AgentExtensionsClient is the client API for AgentExtensions service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AgentExtensionsClient interface {
+	// Searches agents, with filters and sorting beyond those supported by
+	// ListAgents. Agents are returned along with information about their
+	// activity.
+	//
+	// The caller must be local or present an admin X509-SVID.
+	SearchAgents(ctx context.Context, in *SearchAgentsRequest, opts ...grpc.CallOption) (*SearchAgentsResponse, error)
+	// Gets an agent, along with information about its activity.
+	//
+	// The caller must be local or present an admin X509-SVID.
+	GetAgentInfo(ctx context.Context, in *GetAgentInfoRequest, opts ...grpc.CallOption) (*AgentInfo, error)
+}
+
+type agentExtensionsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAgentExtensionsClient(cc grpc.ClientConnInterface) AgentExtensionsClient {
+	return &agentExtensionsClient{cc}
+}
+
+func (c *agentExtensionsClient) SearchAgents(ctx context.Context, in *SearchAgentsRequest, opts ...grpc.CallOption) (*SearchAgentsResponse, error) {
+	out := new(SearchAgentsResponse)
+	err := c.cc.Invoke(ctx, "/spire.api.server.agent.v1.AgentExtensions/SearchAgents", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *agentExtensionsClient) GetAgentInfo(ctx context.Context, in *GetAgentInfoRequest, opts ...grpc.CallOption) (*AgentInfo, error) {
+	out := new(AgentInfo)
+	err := c.cc.Invoke(ctx, "/spire.api.server.agent.v1.AgentExtensions/GetAgentInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AgentExtensionsServer is the server API for AgentExtensions service.
+// All implementations must embed UnimplementedAgentExtensionsServer
+// for forward compatibility
+type AgentExtensionsServer interface {
+	// Searches agents, with filters and sorting beyond those supported by
+	// ListAgents. Agents are returned along with information about their
+	// activity.
+	//
+	// The caller must be local or present an admin X509-SVID.
+	SearchAgents(context.Context, *SearchAgentsRequest) (*SearchAgentsResponse, error)
+	// Gets an agent, along with information about its activity.
+	//
+	// The caller must be local or present an admin X509-SVID.
+	GetAgentInfo(context.Context, *GetAgentInfoRequest) (*AgentInfo, error)
+	mustEmbedUnimplementedAgentExtensionsServer()
+}
+
+// UnimplementedAgentExtensionsServer must be embedded to have forward compatible implementations.
+type UnimplementedAgentExtensionsServer struct {
+}
+
+func (UnimplementedAgentExtensionsServer) SearchAgents(context.Context, *SearchAgentsRequest) (*SearchAgentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchAgents not implemented")
+}
+func (UnimplementedAgentExtensionsServer) GetAgentInfo(context.Context, *GetAgentInfoRequest) (*AgentInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAgentInfo not implemented")
+}
+func (UnimplementedAgentExtensionsServer) mustEmbedUnimplementedAgentExtensionsServer() {}
+
+// UnsafeAgentExtensionsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AgentExtensionsServer will
+// result in compilation errors.
+type UnsafeAgentExtensionsServer interface {
+	mustEmbedUnimplementedAgentExtensionsServer()
+}
+
+func RegisterAgentExtensionsServer(s grpc.ServiceRegistrar, srv AgentExtensionsServer) {
+	s.RegisterService(&AgentExtensions_ServiceDesc, srv)
+}
+
+func _AgentExtensions_SearchAgents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchAgentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentExtensionsServer).SearchAgents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/spire.api.server.agent.v1.AgentExtensions/SearchAgents",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentExtensionsServer).SearchAgents(ctx, req.(*SearchAgentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AgentExtensions_GetAgentInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAgentInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentExtensionsServer).GetAgentInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/spire.api.server.agent.v1.AgentExtensions/GetAgentInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentExtensionsServer).GetAgentInfo(ctx, req.(*GetAgentInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AgentExtensions_ServiceDesc is the grpc.ServiceDesc for AgentExtensions service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AgentExtensions_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "spire.api.server.agent.v1.AgentExtensions",
+	HandlerType: (*AgentExtensionsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SearchAgents",
+			Handler:    _AgentExtensions_SearchAgents_Handler,
+		},
+		{
+			MethodName: "GetAgentInfo",
+			Handler:    _AgentExtensions_GetAgentInfo_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "spire/api/server/agent/v1/agentext.proto",
+}
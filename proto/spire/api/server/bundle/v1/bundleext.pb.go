@@ -0,0 +1,228 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.31.0
+// 	protoc        v3.20.1
+// source: spire/api/server/bundle/v1/bundleext.proto
+
+package bundlev1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetTaintedAuthoritiesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *GetTaintedAuthoritiesRequest) Reset() {
+	*x = GetTaintedAuthoritiesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spire_api_server_bundle_v1_bundleext_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTaintedAuthoritiesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaintedAuthoritiesRequest) ProtoMessage() {}
+
+func (x *GetTaintedAuthoritiesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_api_server_bundle_v1_bundleext_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaintedAuthoritiesRequest.ProtoReflect.Descriptor instead.
+func (*GetTaintedAuthoritiesRequest) Descriptor() ([]byte, []int) {
+	return file_spire_api_server_bundle_v1_bundleext_proto_rawDescGZIP(), []int{0}
+}
+
+type GetTaintedAuthoritiesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The tainted X.509 authorities, as ASN.1 DER encoded certificates.
+	X509Authorities [][]byte `protobuf:"bytes,1,rep,name=x509_authorities,json=x509Authorities,proto3" json:"x509_authorities,omitempty"`
+	// The key IDs of the tainted JWT authorities.
+	JwtAuthorities []string `protobuf:"bytes,2,rep,name=jwt_authorities,json=jwtAuthorities,proto3" json:"jwt_authorities,omitempty"`
+}
+
+func (x *GetTaintedAuthoritiesResponse) Reset() {
+	*x = GetTaintedAuthoritiesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_spire_api_server_bundle_v1_bundleext_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTaintedAuthoritiesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTaintedAuthoritiesResponse) ProtoMessage() {}
+
+func (x *GetTaintedAuthoritiesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_spire_api_server_bundle_v1_bundleext_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTaintedAuthoritiesResponse.ProtoReflect.Descriptor instead.
+func (*GetTaintedAuthoritiesResponse) Descriptor() ([]byte, []int) {
+	return file_spire_api_server_bundle_v1_bundleext_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetTaintedAuthoritiesResponse) GetX509Authorities() [][]byte {
+	if x != nil {
+		return x.X509Authorities
+	}
+	return nil
+}
+
+func (x *GetTaintedAuthoritiesResponse) GetJwtAuthorities() []string {
+	if x != nil {
+		return x.JwtAuthorities
+	}
+	return nil
+}
+
+var File_spire_api_server_bundle_v1_bundleext_proto protoreflect.FileDescriptor
+
+var file_spire_api_server_bundle_v1_bundleext_proto_rawDesc = []byte{
+	0x0a, 0x2a, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x65, 0x72, 0x76,
+	0x65, 0x72, 0x2f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x2f, 0x62, 0x75, 0x6e,
+	0x64, 0x6c, 0x65, 0x65, 0x78, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1a, 0x73, 0x70,
+	0x69, 0x72, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x62,
+	0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x22, 0x1e, 0x0a, 0x1c, 0x47, 0x65, 0x74, 0x54,
+	0x61, 0x69, 0x6e, 0x74, 0x65, 0x64, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x73, 0x0a, 0x1d, 0x47, 0x65, 0x74, 0x54,
+	0x61, 0x69, 0x6e, 0x74, 0x65, 0x64, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x65,
+	0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x78, 0x35, 0x30,
+	0x39, 0x5f, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x0f, 0x78, 0x35, 0x30, 0x39, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69,
+	0x74, 0x69, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x6a, 0x77, 0x74, 0x5f, 0x61, 0x75, 0x74, 0x68,
+	0x6f, 0x72, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x6a,
+	0x77, 0x74, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x65, 0x73, 0x32, 0xa1, 0x01,
+	0x0a, 0x10, 0x42, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x45, 0x78, 0x74, 0x65, 0x6e, 0x73, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x8c, 0x01, 0x0a, 0x15, 0x47, 0x65, 0x74, 0x54, 0x61, 0x69, 0x6e, 0x74, 0x65,
+	0x64, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x38, 0x2e, 0x73,
+	0x70, 0x69, 0x72, 0x65, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e,
+	0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x69,
+	0x6e, 0x74, 0x65, 0x64, 0x41, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x39, 0x2e, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2e, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65,
+	0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x61, 0x69, 0x6e, 0x74, 0x65, 0x64, 0x41, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x69, 0x74, 0x69, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x42, 0x43, 0x5a, 0x41, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x73, 0x70, 0x69, 0x66, 0x66, 0x65, 0x2f, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x2f, 0x73, 0x70, 0x69, 0x72, 0x65, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x2f, 0x62, 0x75, 0x6e, 0x64, 0x6c, 0x65, 0x2f, 0x76, 0x31, 0x3b, 0x62, 0x75,
+	0x6e, 0x64, 0x6c, 0x65, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_spire_api_server_bundle_v1_bundleext_proto_rawDescOnce sync.Once
+	file_spire_api_server_bundle_v1_bundleext_proto_rawDescData = file_spire_api_server_bundle_v1_bundleext_proto_rawDesc
+)
+
+func file_spire_api_server_bundle_v1_bundleext_proto_rawDescGZIP() []byte {
+	file_spire_api_server_bundle_v1_bundleext_proto_rawDescOnce.Do(func() {
+		file_spire_api_server_bundle_v1_bundleext_proto_rawDescData = protoimpl.X.CompressGZIP(file_spire_api_server_bundle_v1_bundleext_proto_rawDescData)
+	})
+	return file_spire_api_server_bundle_v1_bundleext_proto_rawDescData
+}
+
+var file_spire_api_server_bundle_v1_bundleext_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_spire_api_server_bundle_v1_bundleext_proto_goTypes = []interface{}{
+	(*GetTaintedAuthoritiesRequest)(nil),  // 0: spire.api.server.bundle.v1.GetTaintedAuthoritiesRequest
+	(*GetTaintedAuthoritiesResponse)(nil), // 1: spire.api.server.bundle.v1.GetTaintedAuthoritiesResponse
+}
+var file_spire_api_server_bundle_v1_bundleext_proto_depIdxs = []int32{
+	0, // 0: spire.api.server.bundle.v1.BundleExtensions.GetTaintedAuthorities:input_type -> spire.api.server.bundle.v1.GetTaintedAuthoritiesRequest
+	1, // 1: spire.api.server.bundle.v1.BundleExtensions.GetTaintedAuthorities:output_type -> spire.api.server.bundle.v1.GetTaintedAuthoritiesResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_spire_api_server_bundle_v1_bundleext_proto_init() }
+func file_spire_api_server_bundle_v1_bundleext_proto_init() {
+	if File_spire_api_server_bundle_v1_bundleext_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_spire_api_server_bundle_v1_bundleext_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTaintedAuthoritiesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_spire_api_server_bundle_v1_bundleext_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetTaintedAuthoritiesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_spire_api_server_bundle_v1_bundleext_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_spire_api_server_bundle_v1_bundleext_proto_goTypes,
+		DependencyIndexes: file_spire_api_server_bundle_v1_bundleext_proto_depIdxs,
+		MessageInfos:      file_spire_api_server_bundle_v1_bundleext_proto_msgTypes,
+	}.Build()
+	File_spire_api_server_bundle_v1_bundleext_proto = out.File
+	file_spire_api_server_bundle_v1_bundleext_proto_rawDesc = nil
+	file_spire_api_server_bundle_v1_bundleext_proto_goTypes = nil
+	file_spire_api_server_bundle_v1_bundleext_proto_depIdxs = nil
+}
@@ -0,0 +1,111 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package bundlev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BundleExtensionsClient is the client API for BundleExtensions service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BundleExtensionsClient interface {
+	// Gets the authorities of the bundle for the trust domain of the server
+	// that have been tainted. Agents use them to rotate the SVIDs signed by
+	// a tainted authority ahead of their expiration.
+	//
+	// The caller can be anyone.
+	GetTaintedAuthorities(ctx context.Context, in *GetTaintedAuthoritiesRequest, opts ...grpc.CallOption) (*GetTaintedAuthoritiesResponse, error)
+}
+
+type bundleExtensionsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBundleExtensionsClient(cc grpc.ClientConnInterface) BundleExtensionsClient {
+	return &bundleExtensionsClient{cc}
+}
+
+func (c *bundleExtensionsClient) GetTaintedAuthorities(ctx context.Context, in *GetTaintedAuthoritiesRequest, opts ...grpc.CallOption) (*GetTaintedAuthoritiesResponse, error) {
+	out := new(GetTaintedAuthoritiesResponse)
+	err := c.cc.Invoke(ctx, "/spire.api.server.bundle.v1.BundleExtensions/GetTaintedAuthorities", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BundleExtensionsServer is the server API for BundleExtensions service.
+// All implementations must embed UnimplementedBundleExtensionsServer
+// for forward compatibility
+type BundleExtensionsServer interface {
+	// Gets the authorities of the bundle for the trust domain of the server
+	// that have been tainted. Agents use them to rotate the SVIDs signed by
+	// a tainted authority ahead of their expiration.
+	//
+	// The caller can be anyone.
+	GetTaintedAuthorities(context.Context, *GetTaintedAuthoritiesRequest) (*GetTaintedAuthoritiesResponse, error)
+	mustEmbedUnimplementedBundleExtensionsServer()
+}
+
+// UnimplementedBundleExtensionsServer must be embedded to have forward compatible implementations.
+type UnimplementedBundleExtensionsServer struct {
+}
+
+func (UnimplementedBundleExtensionsServer) GetTaintedAuthorities(context.Context, *GetTaintedAuthoritiesRequest) (*GetTaintedAuthoritiesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTaintedAuthorities not implemented")
+}
+func (UnimplementedBundleExtensionsServer) mustEmbedUnimplementedBundleExtensionsServer() {}
+
+// UnsafeBundleExtensionsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BundleExtensionsServer will
+// result in compilation errors.
+type UnsafeBundleExtensionsServer interface {
+	mustEmbedUnimplementedBundleExtensionsServer()
+}
+
+func RegisterBundleExtensionsServer(s grpc.ServiceRegistrar, srv BundleExtensionsServer) {
+	s.RegisterService(&BundleExtensions_ServiceDesc, srv)
+}
+
+func _BundleExtensions_GetTaintedAuthorities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTaintedAuthoritiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BundleExtensionsServer).GetTaintedAuthorities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/spire.api.server.bundle.v1.BundleExtensions/GetTaintedAuthorities",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BundleExtensionsServer).GetTaintedAuthorities(ctx, req.(*GetTaintedAuthoritiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BundleExtensions_ServiceDesc is the grpc.ServiceDesc for BundleExtensions service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BundleExtensions_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "spire.api.server.bundle.v1.BundleExtensions",
+	HandlerType: (*BundleExtensionsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTaintedAuthorities",
+			Handler:    _BundleExtensions_GetTaintedAuthorities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "spire/api/server/bundle/v1/bundleext.proto",
+}
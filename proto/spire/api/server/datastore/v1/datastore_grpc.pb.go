@@ -0,0 +1,212 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package datastorev1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// DataStoreClient is the client API for DataStore service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DataStoreClient interface {
+	// Export streams an archive with the contents of the datastore. The
+	// archive is read page by page while the server is running, so it is
+	// not a point-in-time snapshot of the datastore.
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (DataStore_ExportClient, error)
+	// Import restores the records of a streamed archive into the datastore.
+	// Registration entries keep their IDs and revision numbers. Records that
+	// already exist are skipped, except for bundles, which are merged into
+	// the existing ones.
+	Import(ctx context.Context, opts ...grpc.CallOption) (DataStore_ImportClient, error)
+}
+
+type dataStoreClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDataStoreClient(cc grpc.ClientConnInterface) DataStoreClient {
+	return &dataStoreClient{cc}
+}
+
+func (c *dataStoreClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (DataStore_ExportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataStore_ServiceDesc.Streams[0], "/spire.api.server.datastore.v1.DataStore/Export", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataStoreExportClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type DataStore_ExportClient interface {
+	Recv() (*ExportResponse, error)
+	grpc.ClientStream
+}
+
+type dataStoreExportClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataStoreExportClient) Recv() (*ExportResponse, error) {
+	m := new(ExportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dataStoreClient) Import(ctx context.Context, opts ...grpc.CallOption) (DataStore_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &DataStore_ServiceDesc.Streams[1], "/spire.api.server.datastore.v1.DataStore/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataStoreImportClient{stream}
+	return x, nil
+}
+
+type DataStore_ImportClient interface {
+	Send(*ImportRequest) error
+	CloseAndRecv() (*ImportResponse, error)
+	grpc.ClientStream
+}
+
+type dataStoreImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataStoreImportClient) Send(m *ImportRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *dataStoreImportClient) CloseAndRecv() (*ImportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DataStoreServer is the server API for DataStore service.
+// All implementations must embed UnimplementedDataStoreServer
+// for forward compatibility
+type DataStoreServer interface {
+	// Export streams an archive with the contents of the datastore. The
+	// archive is read page by page while the server is running, so it is
+	// not a point-in-time snapshot of the datastore.
+	Export(*ExportRequest, DataStore_ExportServer) error
+	// Import restores the records of a streamed archive into the datastore.
+	// Registration entries keep their IDs and revision numbers. Records that
+	// already exist are skipped, except for bundles, which are merged into
+	// the existing ones.
+	Import(DataStore_ImportServer) error
+	mustEmbedUnimplementedDataStoreServer()
+}
+
+// UnimplementedDataStoreServer must be embedded to have forward compatible implementations.
+type UnimplementedDataStoreServer struct {
+}
+
+func (UnimplementedDataStoreServer) Export(*ExportRequest, DataStore_ExportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Export not implemented")
+}
+func (UnimplementedDataStoreServer) Import(DataStore_ImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+func (UnimplementedDataStoreServer) mustEmbedUnimplementedDataStoreServer() {}
+
+// UnsafeDataStoreServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DataStoreServer will
+// result in compilation errors.
+type UnsafeDataStoreServer interface {
+	mustEmbedUnimplementedDataStoreServer()
+}
+
+func RegisterDataStoreServer(s grpc.ServiceRegistrar, srv DataStoreServer) {
+	s.RegisterService(&DataStore_ServiceDesc, srv)
+}
+
+func _DataStore_Export_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataStoreServer).Export(m, &dataStoreExportServer{stream})
+}
+
+type DataStore_ExportServer interface {
+	Send(*ExportResponse) error
+	grpc.ServerStream
+}
+
+type dataStoreExportServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataStoreExportServer) Send(m *ExportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _DataStore_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DataStoreServer).Import(&dataStoreImportServer{stream})
+}
+
+type DataStore_ImportServer interface {
+	SendAndClose(*ImportResponse) error
+	Recv() (*ImportRequest, error)
+	grpc.ServerStream
+}
+
+type dataStoreImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataStoreImportServer) SendAndClose(m *ImportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *dataStoreImportServer) Recv() (*ImportRequest, error) {
+	m := new(ImportRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DataStore_ServiceDesc is the grpc.ServiceDesc for DataStore service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DataStore_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "spire.api.server.datastore.v1.DataStore",
+	HandlerType: (*DataStoreServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Export",
+			Handler:       _DataStore_Export_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Import",
+			Handler:       _DataStore_Import_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "spire/api/server/datastore/v1/datastore.proto",
+}
@@ -0,0 +1,109 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package entryv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// EntryExtensionsClient is the client API for EntryExtensions service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type EntryExtensionsClient interface {
+	// Searches registration entries, with filters beyond those supported by
+	// ListEntries. The filtering happens in the datastore.
+	//
+	// The caller must be local or present an admin X509-SVID.
+	SearchEntries(ctx context.Context, in *SearchEntriesRequest, opts ...grpc.CallOption) (*SearchEntriesResponse, error)
+}
+
+type entryExtensionsClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEntryExtensionsClient(cc grpc.ClientConnInterface) EntryExtensionsClient {
+	return &entryExtensionsClient{cc}
+}
+
+func (c *entryExtensionsClient) SearchEntries(ctx context.Context, in *SearchEntriesRequest, opts ...grpc.CallOption) (*SearchEntriesResponse, error) {
+	out := new(SearchEntriesResponse)
+	err := c.cc.Invoke(ctx, "/spire.api.server.entry.v1.EntryExtensions/SearchEntries", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// EntryExtensionsServer is the server API for EntryExtensions service.
+// All implementations must embed UnimplementedEntryExtensionsServer
+// for forward compatibility
+type EntryExtensionsServer interface {
+	// Searches registration entries, with filters beyond those supported by
+	// ListEntries. The filtering happens in the datastore.
+	//
+	// The caller must be local or present an admin X509-SVID.
+	SearchEntries(context.Context, *SearchEntriesRequest) (*SearchEntriesResponse, error)
+	mustEmbedUnimplementedEntryExtensionsServer()
+}
+
+// UnimplementedEntryExtensionsServer must be embedded to have forward compatible implementations.
+type UnimplementedEntryExtensionsServer struct {
+}
+
+func (UnimplementedEntryExtensionsServer) SearchEntries(context.Context, *SearchEntriesRequest) (*SearchEntriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchEntries not implemented")
+}
+func (UnimplementedEntryExtensionsServer) mustEmbedUnimplementedEntryExtensionsServer() {}
+
+// UnsafeEntryExtensionsServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EntryExtensionsServer will
+// result in compilation errors.
+type UnsafeEntryExtensionsServer interface {
+	mustEmbedUnimplementedEntryExtensionsServer()
+}
+
+func RegisterEntryExtensionsServer(s grpc.ServiceRegistrar, srv EntryExtensionsServer) {
+	s.RegisterService(&EntryExtensions_ServiceDesc, srv)
+}
+
+func _EntryExtensions_SearchEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchEntriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EntryExtensionsServer).SearchEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/spire.api.server.entry.v1.EntryExtensions/SearchEntries",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EntryExtensionsServer).SearchEntries(ctx, req.(*SearchEntriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// EntryExtensions_ServiceDesc is the grpc.ServiceDesc for EntryExtensions service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EntryExtensions_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "spire.api.server.entry.v1.EntryExtensions",
+	HandlerType: (*EntryExtensionsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SearchEntries",
+			Handler:    _EntryExtensions_SearchEntries_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "spire/api/server/entry/v1/entryext.proto",
+}
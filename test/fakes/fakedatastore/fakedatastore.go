@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/andres-erbsen/clock"
 	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/require"
 
@@ -27,7 +28,7 @@ var (
 )
 
 type DataStore struct {
-	ds   datastore.DataStore
+	ds   *sql.Plugin
 	errs []error
 }
 
@@ -50,6 +51,12 @@ func New(tb testing.TB) *DataStore {
 	}
 }
 
+// SetClock sets the clock used to determine which events are old enough to be
+// pruned.
+func (s *DataStore) SetClock(clk clock.Clock) {
+	s.ds.SetClock(clk)
+}
+
 func (s *DataStore) CreateBundle(ctx context.Context, bundle *common.Bundle) (*common.Bundle, error) {
 	if err := s.getNextError(); err != nil {
 		return nil, err
@@ -149,6 +156,20 @@ func (s *DataStore) ListAttestedNodes(ctx context.Context, req *datastore.ListAt
 	return s.ds.ListAttestedNodes(ctx, req)
 }
 
+func (s *DataStore) ListAttestedNodesEvents(ctx context.Context, req *datastore.ListAttestedNodesEventsRequest) (*datastore.ListAttestedNodesEventsResponse, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.ListAttestedNodesEvents(ctx, req)
+}
+
+func (s *DataStore) PruneAttestedNodesEvents(ctx context.Context, olderThan time.Duration) error {
+	if err := s.getNextError(); err != nil {
+		return err
+	}
+	return s.ds.PruneAttestedNodesEvents(ctx, olderThan)
+}
+
 func (s *DataStore) UpdateAttestedNode(ctx context.Context, node *common.AttestedNode, mask *common.AttestedNodeMask) (*common.AttestedNode, error) {
 	if err := s.getNextError(); err != nil {
 		return nil, err
@@ -278,6 +299,27 @@ func (s *DataStore) PruneRegistrationEntries(ctx context.Context, expiresBefore
 	return s.ds.PruneRegistrationEntries(ctx, expiresBefore)
 }
 
+func (s *DataStore) RestoreRegistrationEntry(ctx context.Context, entry *common.RegistrationEntry) (*common.RegistrationEntry, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.RestoreRegistrationEntry(ctx, entry)
+}
+
+func (s *DataStore) ListRegistrationEntriesEvents(ctx context.Context, req *datastore.ListRegistrationEntriesEventsRequest) (*datastore.ListRegistrationEntriesEventsResponse, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.ListRegistrationEntriesEvents(ctx, req)
+}
+
+func (s *DataStore) PruneRegistrationEntriesEvents(ctx context.Context, olderThan time.Duration) error {
+	if err := s.getNextError(); err != nil {
+		return err
+	}
+	return s.ds.PruneRegistrationEntriesEvents(ctx, olderThan)
+}
+
 func (s *DataStore) CreateJoinToken(ctx context.Context, token *datastore.JoinToken) error {
 	if err := s.getNextError(); err != nil {
 		return err
@@ -292,6 +334,13 @@ func (s *DataStore) FetchJoinToken(ctx context.Context, token string) (*datastor
 	return s.ds.FetchJoinToken(ctx, token)
 }
 
+func (s *DataStore) ListJoinTokens(ctx context.Context, req *datastore.ListJoinTokensRequest) (*datastore.ListJoinTokensResponse, error) {
+	if err := s.getNextError(); err != nil {
+		return nil, err
+	}
+	return s.ds.ListJoinTokens(ctx, req)
+}
+
 func (s *DataStore) DeleteJoinToken(ctx context.Context, token string) error {
 	if err := s.getNextError(); err != nil {
 		return err
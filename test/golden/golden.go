@@ -0,0 +1,31 @@
+// Package golden compares test output against golden files.
+package golden
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// RequireEqual requires the output to be equal to the contents of the
+// golden file at testdata/<name>.golden. The golden file is written with
+// the output instead when the tests run with the -update flag.
+func RequireEqual(t *testing.T, name string, output []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, output, 0600))
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	require.NoError(t, err, "golden file not found; run the tests with -update to create it")
+	require.Equal(t, string(expected), string(output))
+}